@@ -5,4 +5,12 @@ go 1.23.0
 require (
 	google.golang.org/grpc v1.58.2
 	google.golang.org/protobuf v1.36.9
-)
\ No newline at end of file
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/net v0.12.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+	golang.org/x/text v0.11.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230711160842-782d3b101e98 // indirect
+)
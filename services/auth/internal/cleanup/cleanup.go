@@ -10,9 +10,9 @@ import (
 
 // CleanupWorker periodically cleans up expired sessions and blacklist entries
 type CleanupWorker struct {
-	sessionRepo  repository.SessionRepository
+	sessionRepo   repository.SessionRepository
 	blacklistRepo repository.BlacklistRepository
-	interval     time.Duration
+	interval      time.Duration
 }
 
 // NewCleanupWorker creates a new cleanup worker
@@ -22,9 +22,9 @@ func NewCleanupWorker(
 	interval time.Duration,
 ) *CleanupWorker {
 	return &CleanupWorker{
-		sessionRepo:  sessionRepo,
+		sessionRepo:   sessionRepo,
 		blacklistRepo: blacklistRepo,
-		interval:     interval,
+		interval:      interval,
 	}
 }
 
@@ -64,4 +64,4 @@ func (w *CleanupWorker) cleanup() error {
 
 	log.Println("Cleanup completed successfully")
 	return nil
-}
\ No newline at end of file
+}
@@ -8,14 +8,31 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Port            string `mapstructure:"port"`
-	Environment     string `mapstructure:"environment"`
-	LogLevel        string `mapstructure:"log_level"`
-	DatabaseURL     string `mapstructure:"database_url"`
-	JWTSecret       string `mapstructure:"jwt_secret"`
-	JWTExpiration   int    `mapstructure:"jwt_expiration"`
-	TraceHeader     string `mapstructure:"trace_header"`
-	AllowedOrigins  []string `mapstructure:"allowed_origins"`
+	Port           string   `mapstructure:"port"`
+	Environment    string   `mapstructure:"environment"`
+	LogLevel       string   `mapstructure:"log_level"`
+	DatabaseURL    string   `mapstructure:"database_url"`
+	JWTSecret      string   `mapstructure:"jwt_secret"`
+	JWTExpiration  int      `mapstructure:"jwt_expiration"`
+	TraceHeader    string   `mapstructure:"trace_header"`
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// RedisURL is used for the permission cache; empty disables Redis
+	// and falls back to an in-process cache
+	RedisURL string `mapstructure:"redis_url"`
+	// OAuthRedirectBaseURL is the externally visible base URL used to
+	// build OAuth callback URLs
+	OAuthRedirectBaseURL string `mapstructure:"oauth_redirect_base_url"`
+	// GeoIPLookupURL is an ip-api style lookup endpoint; empty disables
+	// geolocation features
+	GeoIPLookupURL     string `mapstructure:"geoip_lookup_url"`
+	GoogleClientID     string `mapstructure:"google_client_id"`
+	GoogleClientSecret string `mapstructure:"google_client_secret"`
+	GitHubClientID     string `mapstructure:"github_client_id"`
+	GitHubClientSecret string `mapstructure:"github_client_secret"`
+	WeChatAppID        string `mapstructure:"wechat_app_id"`
+	WeChatAppSecret    string `mapstructure:"wechat_app_secret"`
+	// GRPCPort is the gRPC listen port; empty disables the gRPC server
+	GRPCPort string `mapstructure:"grpc_port"`
 }
 
 // Load loads configuration from environment variables and config file
@@ -29,6 +46,7 @@ func Load() (Config, error) {
 	viper.SetDefault("jwt_expiration", 86400) // 24 hours
 	viper.SetDefault("trace_header", "X-Trace-ID")
 	viper.SetDefault("allowed_origins", []string{"*"})
+	viper.SetDefault("grpc_port", "9091")
 
 	// Configure viper to read from environment variables
 	viper.AutomaticEnv()
@@ -64,4 +82,4 @@ func Load() (Config, error) {
 	}
 
 	return cfg, nil
-}
\ No newline at end of file
+}
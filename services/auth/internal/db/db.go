@@ -20,4 +20,4 @@ func Initialize(databaseURL string) (*sql.DB, error) {
 	}
 
 	return db, nil
-}
\ No newline at end of file
+}
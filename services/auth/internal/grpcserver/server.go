@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/service"
+	"github.com/codetaoist/taishanglaojun/auth/proto/authpb"
+)
+
+// Server is the gRPC entry point for the auth service. It runs alongside
+// the REST API and lets internal services validate tokens and look up
+// users without HTTP overhead. Standard health and reflection services
+// are registered for probes and grpcurl debugging.
+type Server struct {
+	grpc *grpc.Server
+}
+
+// New creates a gRPC server wrapping the given auth service
+func New(authService service.AuthService) *Server {
+	server := grpc.NewServer()
+
+	authpb.RegisterAuthServiceServer(server, &authServer{service: authService})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{grpc: server}
+}
+
+// Serve listens on the given port and blocks until the server stops
+func (s *Server) Serve(port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on grpc port %s: %w", port, err)
+	}
+	return s.grpc.Serve(listener)
+}
+
+// Stop gracefully stops the server
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// authServer adapts service.AuthService to the generated gRPC interface
+type authServer struct {
+	authpb.UnimplementedAuthServiceServer
+	service service.AuthService
+}
+
+// ValidateToken verifies a JWT and returns the user it belongs to
+func (s *authServer) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.User, error) {
+	user, err := s.service.ValidateToken(req.GetToken())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return userToProto(user), nil
+}
+
+// GetUser looks up a user by ID
+func (s *authServer) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.User, error) {
+	user, err := s.service.GetUser(int(req.GetUserId()))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return userToProto(user), nil
+}
+
+// userToProto converts the user model to its proto message
+func userToProto(user *model.User) *authpb.User {
+	return &authpb.User{
+		Id:        int64(user.ID),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt.Unix(),
+	}
+}
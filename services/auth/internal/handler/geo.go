@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/service"
+)
+
+// GeoHandler handles geolocation policy and login region requests
+type GeoHandler struct {
+	geoService service.GeoIPService
+}
+
+// NewGeoHandler creates a new geo handler
+func NewGeoHandler(geoService service.GeoIPService) *GeoHandler {
+	return &GeoHandler{
+		geoService: geoService,
+	}
+}
+
+// ListMyRegions handles listing the login countries of the
+// authenticated user
+func (h *GeoHandler) ListMyRegions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	regions, err := h.geoService.ListUserRegions(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "LIST_FAILED",
+			"message": "Failed to list login regions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Login regions retrieved",
+		"data":    regions,
+	})
+}
+
+// ListPolicies handles listing region policy rules
+func (h *GeoHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.geoService.ListPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "LIST_FAILED",
+			"message": "Failed to list region policies",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Region policies retrieved",
+		"data":    policies,
+	})
+}
+
+// SetPolicy handles creating or updating a region policy rule
+func (h *GeoHandler) SetPolicy(c *gin.Context) {
+	var req struct {
+		Feature string `json:"feature" binding:"required"`
+		Country string `json:"country" binding:"required"`
+		Action  string `json:"action" binding:"required,oneof=allow deny"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.geoService.SetPolicy(req.Feature, req.Country, req.Action); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "POLICY_FAILED",
+			"message": "Failed to set region policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Region policy updated",
+	})
+}
+
+// DeletePolicy handles removing a region policy rule
+func (h *GeoHandler) DeletePolicy(c *gin.Context) {
+	var req struct {
+		Feature string `json:"feature" binding:"required"`
+		Country string `json:"country" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.geoService.DeletePolicy(req.Feature, req.Country); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "policy not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    "DELETE_FAILED",
+			"message": "Failed to delete region policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Region policy deleted",
+	})
+}
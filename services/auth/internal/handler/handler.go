@@ -13,6 +13,9 @@ import (
 // AuthHandler handles authentication requests
 type AuthHandler struct {
 	authService service.AuthService
+	// geoService is optional; when set, logins record regions and
+	// registration honors region policies
+	geoService service.GeoIPService
 }
 
 // NewAuthHandler creates a new authentication handler
@@ -22,6 +25,11 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 	}
 }
 
+// SetGeoIPService wires the optional geolocation service
+func (h *AuthHandler) SetGeoIPService(geoService service.GeoIPService) {
+	h.geoService = geoService
+}
+
 // Login handles login requests
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req model.LoginRequest
@@ -44,15 +52,38 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	// Record the login region and alert on a new country; lookup
+	// failures never block login
+	payload := gin.H{
 		"code":    "SUCCESS",
 		"message": "Login successful",
 		"data":    resp,
-	})
+	}
+	if h.geoService != nil && resp.User.ID != 0 {
+		if location, isNew, err := h.geoService.RecordLogin(resp.User.ID, c.ClientIP()); err == nil && isNew {
+			payload["geo_alert"] = gin.H{
+				"new_country": location.Country,
+				"message":     "Login from a new country detected",
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, payload)
 }
 
 // Register handles registration requests
 func (h *AuthHandler) Register(c *gin.Context) {
+	// Region policy: registration may be restricted by country
+	if h.geoService != nil {
+		if allowed, err := h.geoService.FeatureAllowed("registration", c.ClientIP()); err == nil && !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    "REGION_RESTRICTED",
+				"message": "Registration is not available in your region",
+			})
+			return
+		}
+	}
+
 	var req model.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -303,4 +334,4 @@ func (h *AuthHandler) RevokeToken(c *gin.Context) {
 		"code":    "SUCCESS",
 		"message": "Token revoked successfully",
 	})
-}
\ No newline at end of file
+}
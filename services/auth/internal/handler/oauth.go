@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/service"
+)
+
+// OAuthHandler handles social login requests
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// BeginLogin handles starting a social login flow
+func (h *OAuthHandler) BeginLogin(c *gin.Context) {
+	authURL, err := h.oauthService.BeginLogin(c.Param("provider"), 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "OAUTH_BEGIN_FAILED",
+			"message": "Failed to start OAuth flow",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Redirect to the authorization URL",
+		"data":    gin.H{"authorization_url": authURL},
+	})
+}
+
+// BeginLink handles starting an account-linking flow for the
+// authenticated user
+func (h *OAuthHandler) BeginLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	authURL, err := h.oauthService.BeginLogin(c.Param("provider"), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "OAUTH_BEGIN_FAILED",
+			"message": "Failed to start OAuth flow",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Redirect to the authorization URL",
+		"data":    gin.H{"authorization_url": authURL},
+	})
+}
+
+// Callback handles the provider redirect with code and state
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Missing code or state parameter",
+		})
+		return
+	}
+
+	resp, err := h.oauthService.CompleteLogin(c.Param("provider"), code, state)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "OAUTH_FAILED",
+			"message": "OAuth login failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Login successful",
+		"data":    resp,
+	})
+}
+
+// ListIdentities handles listing the linked identities of the
+// authenticated user
+func (h *OAuthHandler) ListIdentities(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	identities, err := h.oauthService.ListIdentities(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "LIST_FAILED",
+			"message": "Failed to list identities",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Identities retrieved",
+		"data":    identities,
+	})
+}
+
+// Unlink handles unlinking a provider identity
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	if err := h.oauthService.Unlink(userID.(int), c.Param("provider")); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "identity not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    "UNLINK_FAILED",
+			"message": "Failed to unlink identity",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Identity unlinked",
+	})
+}
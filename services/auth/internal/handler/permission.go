@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/service"
+)
+
+// PermissionHandler handles role permission management requests
+type PermissionHandler struct {
+	permissionService service.PermissionService
+}
+
+// NewPermissionHandler creates a new permission handler
+func NewPermissionHandler(permissionService service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+	}
+}
+
+// ListPermissions handles listing all role permission grants
+func (h *PermissionHandler) ListPermissions(c *gin.Context) {
+	grants, err := h.permissionService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "LIST_FAILED",
+			"message": "Failed to list permissions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Permissions retrieved",
+		"data":    grants,
+	})
+}
+
+// GrantPermission handles granting a permission to a role
+func (h *PermissionHandler) GrantPermission(c *gin.Context) {
+	var req model.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.permissionService.Grant(req.Role, req.Permission); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "GRANT_FAILED",
+			"message": "Failed to grant permission",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Permission granted",
+	})
+}
+
+// RevokePermission handles revoking a permission from a role
+func (h *PermissionHandler) RevokePermission(c *gin.Context) {
+	var req model.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.permissionService.Revoke(req.Role, req.Permission); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "permission not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    "REVOKE_FAILED",
+			"message": "Failed to revoke permission",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Permission revoked",
+	})
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/service"
+)
+
+// TOTPHandler handles two-factor authentication requests
+type TOTPHandler struct {
+	totpService service.TOTPService
+	authService service.AuthService
+}
+
+// NewTOTPHandler creates a new TOTP handler
+func NewTOTPHandler(totpService service.TOTPService, authService service.AuthService) *TOTPHandler {
+	return &TOTPHandler{
+		totpService: totpService,
+		authService: authService,
+	}
+}
+
+// Enroll handles starting a TOTP enrollment
+func (h *TOTPHandler) Enroll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+	username, _ := c.Get("username")
+
+	secret, uri, err := h.totpService.Enroll(userID.(int), username.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "ENROLL_FAILED",
+			"message": "Failed to enroll TOTP",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Scan the provisioning URI and verify a code to activate",
+		"data": gin.H{
+			"secret":           secret,
+			"provisioning_uri": uri,
+		},
+	})
+}
+
+// Activate handles verifying the first code and enabling 2FA
+func (h *TOTPHandler) Activate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	backupCodes, err := h.totpService.Activate(userID.(int), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "ACTIVATE_FAILED",
+			"message": "Failed to activate TOTP",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Two-factor authentication enabled; store the backup codes safely",
+		"data":    gin.H{"backup_codes": backupCodes},
+	})
+}
+
+// Disable handles turning 2FA off
+func (h *TOTPHandler) Disable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.totpService.Disable(userID.(int), req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "DISABLE_FAILED",
+			"message": "Failed to disable TOTP",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// Status handles reporting the 2FA state of the authenticated user
+func (h *TOTPHandler) Status(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	enrolled, enabled, err := h.totpService.Status(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "STATUS_FAILED",
+			"message": "Failed to get TOTP status",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "TOTP status retrieved",
+		"data":    gin.H{"enrolled": enrolled, "enabled": enabled},
+	})
+}
+
+// Verify handles the second-factor step of login
+func (h *TOTPHandler) Verify(c *gin.Context) {
+	var req model.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, err := h.totpService.CompleteChallenge(req.TwoFactorToken, req.Code)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    "TWOFA_FAILED",
+			"message": "Two-factor verification failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	user, err := h.authService.GetUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "USER_NOT_FOUND",
+			"message": "Failed to load user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.authService.IssueToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "TOKEN_FAILED",
+			"message": "Failed to issue token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "Login successful",
+		"data":    resp,
+	})
+}
+
+// SetRolePolicy handles setting the 2FA requirement for a role
+func (h *TOTPHandler) SetRolePolicy(c *gin.Context) {
+	var req struct {
+		Role     string `json:"role" binding:"required"`
+		Required *bool  `json:"required" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.totpService.SetRolePolicy(req.Role, *req.Required); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "POLICY_FAILED",
+			"message": "Failed to set 2FA policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "2FA policy updated",
+	})
+}
+
+// GetRolePolicy handles reading the 2FA requirement for a role
+func (h *TOTPHandler) GetRolePolicy(c *gin.Context) {
+	role := c.Query("role")
+	if role == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "INVALID_REQUEST",
+			"message": "role query parameter is required",
+		})
+		return
+	}
+
+	required, err := h.totpService.IsRoleRequired(role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "POLICY_FAILED",
+			"message": "Failed to get 2FA policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "2FA policy retrieved",
+		"data":    gin.H{"role": role, "required": required},
+	})
+}
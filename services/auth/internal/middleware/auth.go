@@ -85,6 +85,54 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+// RequirePermission creates a middleware that checks whether the
+// authenticated user's role holds the given permission (e.g. "users:write").
+// It must run after AuthMiddleware so the role is present in the context.
+func RequirePermission(permissionService service.PermissionService, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		roleName, ok := role.(string)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		allowed, err := permissionService.HasPermission(roleName, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    "PERMISSION_CHECK_FAILED",
+				"message": "Failed to check permissions",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    "FORBIDDEN",
+				"message": "Permission '" + permission + "' required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // OptionalAuthMiddleware creates a middleware that validates JWT tokens if present
 func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -121,4 +169,4 @@ func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
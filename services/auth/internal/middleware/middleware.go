@@ -7,8 +7,8 @@ import (
 	"runtime/debug"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/codetaoist/taishanglaojun/auth/internal/config"
+	"github.com/gin-gonic/gin"
 )
 
 // RequestLogger is a middleware that logs requests
@@ -105,4 +105,4 @@ func ErrorHandler() gin.HandlerFunc {
 			"details": message,
 		})
 	})
-}
\ No newline at end of file
+}
@@ -18,12 +18,12 @@ type User struct {
 
 // Session represents a user session
 type Session struct {
-	ID            int       `json:"id" db:"id"`
-	UserID        int       `json:"user_id" db:"user_id"`
-	RefreshToken  string    `json:"refresh_token" db:"refresh_token"` // Changed from TokenHash to RefreshToken
-	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID           int       `json:"id" db:"id"`
+	UserID       int       `json:"user_id" db:"user_id"`
+	RefreshToken string    `json:"refresh_token" db:"refresh_token"` // Changed from TokenHash to RefreshToken
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // LoginRequest represents a login request
@@ -32,11 +32,18 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse represents a login response
+// LoginResponse represents a login response. When the account has 2FA
+// enabled, Token is empty and TwoFactorToken must be exchanged together
+// with a TOTP code at the verification endpoint.
 type LoginResponse struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	User      User      `json:"user"`
+	Token             string    `json:"token,omitempty"`
+	ExpiresAt         time.Time `json:"expires_at,omitempty"`
+	User              User      `json:"user"`
+	TwoFactorRequired bool      `json:"two_factor_required,omitempty"`
+	TwoFactorToken    string    `json:"two_factor_token,omitempty"`
+	// TwoFactorEnrollmentRequired is set when the role policy requires
+	// 2FA but the account has not enrolled yet
+	TwoFactorEnrollmentRequired bool `json:"two_factor_enrollment_required,omitempty"`
 }
 
 // RegisterRequest represents a registration request
@@ -72,4 +79,62 @@ type TokenBlacklist struct {
 	Reason    string    `json:"reason" db:"reason"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-}
\ No newline at end of file
+}
+
+// RolePermission represents a permission granted to a role
+type RolePermission struct {
+	ID         int       `json:"id" db:"id"`
+	Role       string    `json:"role" db:"role"`
+	Permission string    `json:"permission" db:"permission"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// UserTOTP represents a TOTP second-factor enrollment
+type UserTOTP struct {
+	UserID    int       `json:"user_id" db:"user_id"`
+	Secret    string    `json:"-" db:"secret"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TwoFactorVerifyRequest represents a second-factor verification request
+type TwoFactorVerifyRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// UserIdentity represents an external OAuth identity linked to a user
+type UserIdentity struct {
+	ID             int       `json:"id" db:"id"`
+	UserID         int       `json:"user_id" db:"user_id"`
+	Provider       string    `json:"provider" db:"provider"`
+	ProviderUserID string    `json:"provider_user_id" db:"provider_user_id"`
+	Email          string    `json:"email" db:"email"`
+	DisplayName    string    `json:"display_name" db:"display_name"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// LoginRegion represents a country a user has logged in from
+type LoginRegion struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      int       `json:"user_id" db:"user_id"`
+	Country     string    `json:"country" db:"country"`
+	Region      string    `json:"region" db:"region"`
+	FirstSeenAt time.Time `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// RegionPolicy represents a region-based feature access rule
+type RegionPolicy struct {
+	ID        int       `json:"id" db:"id"`
+	Feature   string    `json:"feature" db:"feature"`
+	Country   string    `json:"country" db:"country"`
+	Action    string    `json:"action" db:"action"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// GrantPermissionRequest represents a permission grant/revoke request
+type GrantPermissionRequest struct {
+	Role       string `json:"role" binding:"required"`
+	Permission string `json:"permission" binding:"required"`
+}
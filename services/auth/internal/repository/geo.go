@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+)
+
+// GeoRepository interface defines geolocation persistence operations
+type GeoRepository interface {
+	// UpsertLoginRegion records a login country for a user and reports
+	// whether the country was seen for the first time
+	UpsertLoginRegion(userID int, country, region string) (bool, error)
+	ListUserRegions(userID int) ([]*model.LoginRegion, error)
+	SetPolicy(feature, country, action string) error
+	DeletePolicy(feature, country string) error
+	ListPolicies() ([]*model.RegionPolicy, error)
+	// PolicyAction returns the action for a feature/country pair,
+	// considering the '*' wildcard; empty when no rule matches
+	PolicyAction(feature, country string) (string, error)
+}
+
+// geoRepository implements GeoRepository
+type geoRepository struct {
+	db *sql.DB
+}
+
+// NewGeoRepository creates a new geo repository
+func NewGeoRepository(db *sql.DB) GeoRepository {
+	return &geoRepository{db: db}
+}
+
+// UpsertLoginRegion records a login country for a user
+func (r *geoRepository) UpsertLoginRegion(userID int, country, region string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE lao_login_regions SET last_seen_at = NOW(), region = $3
+		WHERE user_id = $1 AND country = $2
+	`, userID, country, region)
+	if err != nil {
+		return false, fmt.Errorf("failed to update login region: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected > 0 {
+		return false, nil
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO lao_login_regions (user_id, country, region)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, country) DO UPDATE SET last_seen_at = NOW()
+	`, userID, country, region)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert login region: %w", err)
+	}
+	return true, nil
+}
+
+// ListUserRegions lists the login countries seen for a user
+func (r *geoRepository) ListUserRegions(userID int) ([]*model.LoginRegion, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, country, region, first_seen_at, last_seen_at
+		FROM lao_login_regions
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login regions: %w", err)
+	}
+	defer rows.Close()
+
+	var regions []*model.LoginRegion
+	for rows.Next() {
+		region := &model.LoginRegion{}
+		err := rows.Scan(&region.ID, &region.UserID, &region.Country, &region.Region,
+			&region.FirstSeenAt, &region.LastSeenAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login region: %w", err)
+		}
+		regions = append(regions, region)
+	}
+	return regions, rows.Err()
+}
+
+// SetPolicy creates or updates a region policy rule
+func (r *geoRepository) SetPolicy(feature, country, action string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO lao_region_policies (feature, country, action)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (feature, country) DO UPDATE SET action = $3
+	`, feature, country, action)
+	if err != nil {
+		return fmt.Errorf("failed to set region policy: %w", err)
+	}
+	return nil
+}
+
+// DeletePolicy removes a region policy rule
+func (r *geoRepository) DeletePolicy(feature, country string) error {
+	result, err := r.db.Exec(`
+		DELETE FROM lao_region_policies WHERE feature = $1 AND country = $2
+	`, feature, country)
+	if err != nil {
+		return fmt.Errorf("failed to delete region policy: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("policy not found")
+	}
+	return nil
+}
+
+// ListPolicies lists all region policy rules
+func (r *geoRepository) ListPolicies() ([]*model.RegionPolicy, error) {
+	rows, err := r.db.Query(`
+		SELECT id, feature, country, action, created_at
+		FROM lao_region_policies
+		ORDER BY feature, country
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list region policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*model.RegionPolicy
+	for rows.Next() {
+		policy := &model.RegionPolicy{}
+		err := rows.Scan(&policy.ID, &policy.Feature, &policy.Country, &policy.Action, &policy.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan region policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// PolicyAction returns the matching action for a feature/country pair.
+// A country-specific rule wins over the '*' wildcard.
+func (r *geoRepository) PolicyAction(feature, country string) (string, error) {
+	var action string
+	err := r.db.QueryRow(`
+		SELECT action FROM lao_region_policies
+		WHERE feature = $1 AND country IN ($2, '*')
+		ORDER BY CASE WHEN country = $2 THEN 0 ELSE 1 END
+		LIMIT 1
+	`, feature, country).Scan(&action)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get policy action: %w", err)
+	}
+	return action, nil
+}
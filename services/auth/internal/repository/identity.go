@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+)
+
+// IdentityRepository interface defines external identity operations
+type IdentityRepository interface {
+	Create(identity *model.UserIdentity) error
+	GetByProvider(provider, providerUserID string) (*model.UserIdentity, error)
+	ListByUser(userID int) ([]*model.UserIdentity, error)
+	Delete(userID int, provider string) error
+}
+
+// identityRepository implements IdentityRepository
+type identityRepository struct {
+	db *sql.DB
+}
+
+// NewIdentityRepository creates a new identity repository
+func NewIdentityRepository(db *sql.DB) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create creates a new external identity link
+func (r *identityRepository) Create(identity *model.UserIdentity) error {
+	query := `
+		INSERT INTO lao_user_identities (user_id, provider, provider_user_id, email, display_name)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(query,
+		identity.UserID, identity.Provider, identity.ProviderUserID,
+		identity.Email, identity.DisplayName,
+	).Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create identity: %w", err)
+	}
+	return nil
+}
+
+// GetByProvider finds an identity by provider and provider user ID
+func (r *identityRepository) GetByProvider(provider, providerUserID string) (*model.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, provider_user_id, email, display_name, created_at
+		FROM lao_user_identities
+		WHERE provider = $1 AND provider_user_id = $2
+	`
+	identity := &model.UserIdentity{}
+	err := r.db.QueryRow(query, provider, providerUserID).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider,
+		&identity.ProviderUserID, &identity.Email, &identity.DisplayName,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("identity not found")
+		}
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+	return identity, nil
+}
+
+// ListByUser lists all external identities linked to a user
+func (r *identityRepository) ListByUser(userID int) ([]*model.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, provider_user_id, email, display_name, created_at
+		FROM lao_user_identities
+		WHERE user_id = $1
+		ORDER BY provider
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	defer rows.Close()
+
+	var identities []*model.UserIdentity
+	for rows.Next() {
+		identity := &model.UserIdentity{}
+		err := rows.Scan(
+			&identity.ID, &identity.UserID, &identity.Provider,
+			&identity.ProviderUserID, &identity.Email, &identity.DisplayName,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+// Delete unlinks a provider identity from a user
+func (r *identityRepository) Delete(userID int, provider string) error {
+	query := `DELETE FROM lao_user_identities WHERE user_id = $1 AND provider = $2`
+
+	result, err := r.db.Exec(query, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to delete identity: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("identity not found")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+)
+
+// PermissionRepository interface defines role permission operations
+type PermissionRepository interface {
+	GetByRole(role string) ([]string, error)
+	Grant(role, permission string) error
+	Revoke(role, permission string) error
+	List() ([]*model.RolePermission, error)
+}
+
+// permissionRepository implements PermissionRepository
+type permissionRepository struct {
+	db *sql.DB
+}
+
+// NewPermissionRepository creates a new permission repository
+func NewPermissionRepository(db *sql.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+// GetByRole returns all permissions granted to a role
+func (r *permissionRepository) GetByRole(role string) ([]string, error) {
+	query := `SELECT permission FROM lao_role_permissions WHERE role = $1 ORDER BY permission`
+
+	rows, err := r.db.Query(query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+// Grant grants a permission to a role (idempotent)
+func (r *permissionRepository) Grant(role, permission string) error {
+	query := `
+		INSERT INTO lao_role_permissions (role, permission)
+		VALUES ($1, $2)
+		ON CONFLICT (role, permission) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, role, permission); err != nil {
+		return fmt.Errorf("failed to grant permission: %w", err)
+	}
+	return nil
+}
+
+// Revoke removes a permission from a role
+func (r *permissionRepository) Revoke(role, permission string) error {
+	query := `DELETE FROM lao_role_permissions WHERE role = $1 AND permission = $2`
+
+	result, err := r.db.Exec(query, role, permission)
+	if err != nil {
+		return fmt.Errorf("failed to revoke permission: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("permission not found")
+	}
+	return nil
+}
+
+// List returns all role permission grants
+func (r *permissionRepository) List() ([]*model.RolePermission, error) {
+	query := `SELECT id, role, permission, created_at FROM lao_role_permissions ORDER BY role, permission`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var grants []*model.RolePermission
+	for rows.Next() {
+		grant := &model.RolePermission{}
+		if err := rows.Scan(&grant.ID, &grant.Role, &grant.Permission, &grant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		grants = append(grants, grant)
+	}
+	return grants, rows.Err()
+}
@@ -482,4 +482,4 @@ func (r *blacklistRepository) DeleteExpired() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+)
+
+// TOTPRepository interface defines TOTP persistence operations
+type TOTPRepository interface {
+	Upsert(userID int, secret string) error
+	Get(userID int) (*model.UserTOTP, error)
+	Enable(userID int) error
+	Delete(userID int) error
+	ReplaceBackupCodes(userID int, codeHashes []string) error
+	ConsumeBackupCode(userID int, codeHash string) (bool, error)
+	IsRoleRequired(role string) (bool, error)
+	SetRoleRequired(role string, required bool) error
+}
+
+// totpRepository implements TOTPRepository
+type totpRepository struct {
+	db *sql.DB
+}
+
+// NewTOTPRepository creates a new TOTP repository
+func NewTOTPRepository(db *sql.DB) TOTPRepository {
+	return &totpRepository{db: db}
+}
+
+// Upsert stores a (not yet enabled) TOTP secret for a user
+func (r *totpRepository) Upsert(userID int, secret string) error {
+	query := `
+		INSERT INTO lao_user_totp (user_id, secret, enabled)
+		VALUES ($1, $2, FALSE)
+		ON CONFLICT (user_id) DO UPDATE SET secret = $2, enabled = FALSE
+	`
+	if _, err := r.db.Exec(query, userID, secret); err != nil {
+		return fmt.Errorf("failed to store totp secret: %w", err)
+	}
+	return nil
+}
+
+// Get returns the TOTP enrollment of a user
+func (r *totpRepository) Get(userID int) (*model.UserTOTP, error) {
+	query := `SELECT user_id, secret, enabled, created_at FROM lao_user_totp WHERE user_id = $1`
+
+	totp := &model.UserTOTP{}
+	err := r.db.QueryRow(query, userID).Scan(&totp.UserID, &totp.Secret, &totp.Enabled, &totp.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("totp not enrolled")
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+	return totp, nil
+}
+
+// Enable marks a TOTP enrollment as active
+func (r *totpRepository) Enable(userID int) error {
+	query := `UPDATE lao_user_totp SET enabled = TRUE WHERE user_id = $1`
+
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("totp not enrolled")
+	}
+	return nil
+}
+
+// Delete removes a TOTP enrollment and its backup codes
+func (r *totpRepository) Delete(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM lao_totp_backup_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete backup codes: %w", err)
+	}
+	if _, err := r.db.Exec(`DELETE FROM lao_user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+	return nil
+}
+
+// ReplaceBackupCodes replaces all backup codes of a user
+func (r *totpRepository) ReplaceBackupCodes(userID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM lao_totp_backup_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to clear backup codes: %w", err)
+	}
+	for _, hash := range codeHashes {
+		_, err := tx.Exec(`INSERT INTO lao_totp_backup_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			return fmt.Errorf("failed to insert backup code: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ConsumeBackupCode marks an unused backup code as used; returns whether
+// a matching unused code existed
+func (r *totpRepository) ConsumeBackupCode(userID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE lao_totp_backup_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume backup code: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// IsRoleRequired reports whether 2FA is required for a role
+func (r *totpRepository) IsRoleRequired(role string) (bool, error) {
+	query := `SELECT required FROM lao_twofa_role_policies WHERE role = $1`
+
+	var required bool
+	err := r.db.QueryRow(query, role).Scan(&required)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get twofa policy: %w", err)
+	}
+	return required, nil
+}
+
+// SetRoleRequired sets the 2FA requirement for a role
+func (r *totpRepository) SetRoleRequired(role string, required bool) error {
+	query := `
+		INSERT INTO lao_twofa_role_policies (role, required, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (role) DO UPDATE SET required = $2, updated_at = NOW()
+	`
+	if _, err := r.db.Exec(query, role, required); err != nil {
+		return fmt.Errorf("failed to set twofa policy: %w", err)
+	}
+	return nil
+}
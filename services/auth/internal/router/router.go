@@ -12,23 +12,47 @@ import (
 	"github.com/codetaoist/taishanglaojun/auth/internal/service"
 )
 
-// Setup sets up the router with all routes and middleware
-func Setup(cfg config.Config, r *gin.Engine, db *sql.DB) {
+// Setup sets up the router with all routes and middleware. The auth
+// service is returned so main can share it with the gRPC server.
+func Setup(cfg config.Config, r *gin.Engine, db *sql.DB) (service.AuthService, error) {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
 	blacklistRepo := repository.NewBlacklistRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, sessionRepo, blacklistRepo, cfg.JWTSecret, cfg.JWTExpiration)
+	permissionService, err := service.NewPermissionService(permissionRepo, cfg.RedisURL)
+	if err != nil {
+		return nil, err
+	}
+
+	totpRepo := repository.NewTOTPRepository(db)
+	totpService := service.NewTOTPService(totpRepo)
+	authService.SetTwoFactorService(totpService)
+	geoRepo := repository.NewGeoRepository(db)
+	geoService := service.NewGeoIPService(geoRepo, cfg.GeoIPLookupURL)
+	oauthService := service.NewOAuthService(authService, userRepo, identityRepo, service.OAuthConfig{
+		RedirectBaseURL: cfg.OAuthRedirectBaseURL,
+		Google:          service.ProviderConfig{ClientID: cfg.GoogleClientID, ClientSecret: cfg.GoogleClientSecret},
+		GitHub:          service.ProviderConfig{ClientID: cfg.GitHubClientID, ClientSecret: cfg.GitHubClientSecret},
+		WeChat:          service.ProviderConfig{ClientID: cfg.WeChatAppID, ClientSecret: cfg.WeChatAppSecret},
+	})
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
+	permissionHandler := handler.NewPermissionHandler(permissionService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	totpHandler := handler.NewTOTPHandler(totpService, authService)
+	geoHandler := handler.NewGeoHandler(geoService)
+	authHandler.SetGeoIPService(geoService)
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"status": "ok",
+			"status":  "ok",
 			"service": "auth",
 		})
 	})
@@ -42,6 +66,13 @@ func Setup(cfg config.Config, r *gin.Engine, db *sql.DB) {
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/refresh", authHandler.RefreshToken)
+
+			// Social login (OAuth2/OIDC)
+			auth.GET("/oauth/:provider", oauthHandler.BeginLogin)
+			auth.GET("/oauth/:provider/callback", oauthHandler.Callback)
+
+			// Second-factor step of login
+			auth.POST("/2fa/verify", totpHandler.Verify)
 		}
 
 		// Protected routes (authentication required)
@@ -50,14 +81,46 @@ func Setup(cfg config.Config, r *gin.Engine, db *sql.DB) {
 		{
 			// User profile routes
 			protected.GET("/profile", authHandler.GetProfile)
+			protected.GET("/profile/regions", geoHandler.ListMyRegions)
 			protected.POST("/change-password", authHandler.ChangePassword)
 			protected.POST("/revoke-token", authHandler.RevokeToken)
 
-			// Admin routes (admin role required)
+			// Admin routes (permission-scoped)
 			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminMiddleware())
 			{
-				admin.GET("/users/:id", authHandler.GetUser)
+				admin.GET("/users/:id",
+					middleware.RequirePermission(permissionService, "users:read"),
+					authHandler.GetUser)
+
+				// Permission management (requires permissions:* grants)
+				admin.GET("/permissions",
+					middleware.RequirePermission(permissionService, "permissions:read"),
+					permissionHandler.ListPermissions)
+				admin.POST("/permissions",
+					middleware.RequirePermission(permissionService, "permissions:write"),
+					permissionHandler.GrantPermission)
+				admin.DELETE("/permissions",
+					middleware.RequirePermission(permissionService, "permissions:write"),
+					permissionHandler.RevokePermission)
+
+				// 2FA role policy
+				admin.GET("/2fa-policy",
+					middleware.RequirePermission(permissionService, "twofa:read"),
+					totpHandler.GetRolePolicy)
+				admin.PUT("/2fa-policy",
+					middleware.RequirePermission(permissionService, "twofa:write"),
+					totpHandler.SetRolePolicy)
+
+				// Region-based access policies
+				admin.GET("/region-policies",
+					middleware.RequirePermission(permissionService, "geo:read"),
+					geoHandler.ListPolicies)
+				admin.PUT("/region-policies",
+					middleware.RequirePermission(permissionService, "geo:write"),
+					geoHandler.SetPolicy)
+				admin.DELETE("/region-policies",
+					middleware.RequirePermission(permissionService, "geo:write"),
+					geoHandler.DeletePolicy)
 			}
 		}
 
@@ -66,6 +129,19 @@ func Setup(cfg config.Config, r *gin.Engine, db *sql.DB) {
 		protectedAuth.Use(middleware.AuthMiddleware(authService))
 		{
 			protectedAuth.POST("/logout", authHandler.Logout)
+
+			// Account linking for additional providers
+			protectedAuth.POST("/oauth/:provider/link", oauthHandler.BeginLink)
+			protectedAuth.GET("/identities", oauthHandler.ListIdentities)
+			protectedAuth.DELETE("/identities/:provider", oauthHandler.Unlink)
+
+			// TOTP enrollment management
+			protectedAuth.POST("/2fa/enroll", totpHandler.Enroll)
+			protectedAuth.POST("/2fa/activate", totpHandler.Activate)
+			protectedAuth.POST("/2fa/disable", totpHandler.Disable)
+			protectedAuth.GET("/2fa/status", totpHandler.Status)
 		}
 	}
-}
\ No newline at end of file
+
+	return authService, nil
+}
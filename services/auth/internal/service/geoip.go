@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/repository"
+)
+
+// geoCacheTTL is how long IP lookups stay cached
+const geoCacheTTL = 6 * time.Hour
+
+// Location is the result of a GeoIP lookup
+type Location struct {
+	Country string `json:"country"`
+	Region  string `json:"region"`
+}
+
+// GeoIPService interface defines geolocation operations
+type GeoIPService interface {
+	// Lookup resolves an IP address to a location; private and
+	// unparseable addresses return an empty location without error
+	Lookup(ip string) (*Location, error)
+	// RecordLogin stores the login country for a user and reports
+	// whether it is a new country for that account
+	RecordLogin(userID int, ip string) (*Location, bool, error)
+	// FeatureAllowed checks the region policy for a feature against the
+	// caller's IP; unknown locations are allowed
+	FeatureAllowed(feature, ip string) (bool, error)
+	ListUserRegions(userID int) ([]*model.LoginRegion, error)
+	SetPolicy(feature, country, action string) error
+	DeletePolicy(feature, country string) error
+	ListPolicies() ([]*model.RegionPolicy, error)
+}
+
+type geoCacheEntry struct {
+	location  Location
+	expiresAt time.Time
+}
+
+// geoIPService implements GeoIPService against an ip-api style HTTP
+// lookup endpoint
+type geoIPService struct {
+	repo      repository.GeoRepository
+	lookupURL string
+	client    *http.Client
+
+	mu    sync.RWMutex
+	cache map[string]geoCacheEntry
+}
+
+// NewGeoIPService creates a new GeoIP service. lookupURL may be empty,
+// in which case lookups return empty locations and policies never deny.
+func NewGeoIPService(repo repository.GeoRepository, lookupURL string) GeoIPService {
+	return &geoIPService{
+		repo:      repo,
+		lookupURL: lookupURL,
+		client:    &http.Client{Timeout: 3 * time.Second},
+		cache:     make(map[string]geoCacheEntry),
+	}
+}
+
+// Lookup resolves an IP address to a location
+func (s *geoIPService) Lookup(ip string) (*Location, error) {
+	parsed := net.ParseIP(ip)
+	if s.lookupURL == "" || parsed == nil || parsed.IsLoopback() || parsed.IsPrivate() {
+		return &Location{}, nil
+	}
+
+	s.mu.RLock()
+	entry, ok := s.cache[ip]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		location := entry.location
+		return &location, nil
+	}
+
+	lookupURL := strings.TrimRight(s.lookupURL, "/") + "/" + url.PathEscape(ip)
+	resp, err := s.client.Get(lookupURL)
+	if err != nil {
+		return nil, fmt.Errorf("geoip lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geoip lookup returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		CountryCode string `json:"countryCode"`
+		RegionName  string `json:"regionName"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to parse geoip response: %w", err)
+	}
+
+	location := Location{Country: payload.CountryCode, Region: payload.RegionName}
+	s.mu.Lock()
+	s.cache[ip] = geoCacheEntry{location: location, expiresAt: time.Now().Add(geoCacheTTL)}
+	s.mu.Unlock()
+
+	return &location, nil
+}
+
+// RecordLogin stores the login country and reports new countries
+func (s *geoIPService) RecordLogin(userID int, ip string) (*Location, bool, error) {
+	location, err := s.Lookup(ip)
+	if err != nil {
+		return nil, false, err
+	}
+	if location.Country == "" {
+		return location, false, nil
+	}
+
+	isNew, err := s.repo.UpsertLoginRegion(userID, location.Country, location.Region)
+	if err != nil {
+		return nil, false, err
+	}
+	return location, isNew, nil
+}
+
+// FeatureAllowed checks the region policy for a feature
+func (s *geoIPService) FeatureAllowed(feature, ip string) (bool, error) {
+	location, err := s.Lookup(ip)
+	if err != nil {
+		// Lookup failures fail open: compliance rules depend on a
+		// positive country match
+		return true, nil
+	}
+	if location.Country == "" {
+		return true, nil
+	}
+
+	action, err := s.repo.PolicyAction(feature, location.Country)
+	if err != nil {
+		return false, err
+	}
+	return action != "deny", nil
+}
+
+// ListUserRegions lists the login countries seen for a user
+func (s *geoIPService) ListUserRegions(userID int) ([]*model.LoginRegion, error) {
+	return s.repo.ListUserRegions(userID)
+}
+
+// SetPolicy creates or updates a region policy rule
+func (s *geoIPService) SetPolicy(feature, country, action string) error {
+	if action != "allow" && action != "deny" {
+		return fmt.Errorf("invalid action: %s", action)
+	}
+	if country != "*" && len(country) != 2 {
+		return fmt.Errorf("invalid country code: %s", country)
+	}
+	return s.repo.SetPolicy(feature, strings.ToUpper(country), action)
+}
+
+// DeletePolicy removes a region policy rule
+func (s *geoIPService) DeletePolicy(feature, country string) error {
+	return s.repo.DeletePolicy(feature, strings.ToUpper(country))
+}
+
+// ListPolicies lists all region policy rules
+func (s *geoIPService) ListPolicies() ([]*model.RegionPolicy, error) {
+	return s.repo.ListPolicies()
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/repository"
+)
+
+// stateTTL is how long a pending OAuth authorization is valid
+const stateTTL = 10 * time.Minute
+
+// ProviderConfig holds the OAuth client credentials for one provider
+type ProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// OAuthConfig holds the OAuth configuration for all providers
+type OAuthConfig struct {
+	// RedirectBaseURL is the externally visible base URL of this service,
+	// used to build the per-provider callback URLs
+	RedirectBaseURL string
+	Google          ProviderConfig
+	GitHub          ProviderConfig
+	WeChat          ProviderConfig
+}
+
+// OAuthService interface defines social login operations
+type OAuthService interface {
+	// BeginLogin starts an authorization flow and returns the provider
+	// authorization URL the client should redirect to. linkUserID is
+	// non-zero when an existing account is linking a new provider.
+	BeginLogin(provider string, linkUserID int) (string, error)
+	// CompleteLogin exchanges the callback code, provisions or links the
+	// user, and returns a normal login response
+	CompleteLogin(provider, code, state string) (*model.LoginResponse, error)
+	ListIdentities(userID int) ([]*model.UserIdentity, error)
+	Unlink(userID int, provider string) error
+}
+
+// oauthProvider describes one upstream OAuth2/OIDC provider
+type oauthProvider struct {
+	name        string
+	authURL     string
+	tokenURL    string
+	userInfoURL string
+	scopes      string
+	// wechatStyle providers pass appid/secret as query parameters and
+	// return the user ID (openid) in the token response
+	wechatStyle bool
+	config      ProviderConfig
+}
+
+// pendingAuth tracks an in-flight authorization (state + PKCE verifier)
+type pendingAuth struct {
+	provider     string
+	codeVerifier string
+	linkUserID   int
+	expiresAt    time.Time
+}
+
+// providerClaims is the normalized user info extracted from a provider
+type providerClaims struct {
+	providerUserID string
+	email          string
+	displayName    string
+}
+
+// oauthService implements OAuthService
+type oauthService struct {
+	authService  AuthService
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+	config       OAuthConfig
+	providers    map[string]*oauthProvider
+	client       *http.Client
+
+	mu      sync.Mutex
+	pending map[string]pendingAuth
+}
+
+// NewOAuthService creates a new OAuth service
+func NewOAuthService(
+	authService AuthService,
+	userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository,
+	config OAuthConfig,
+) OAuthService {
+	providers := map[string]*oauthProvider{
+		"google": {
+			name:        "google",
+			authURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+			tokenURL:    "https://oauth2.googleapis.com/token",
+			userInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+			scopes:      "openid email profile",
+			config:      config.Google,
+		},
+		"github": {
+			name:        "github",
+			authURL:     "https://github.com/login/oauth/authorize",
+			tokenURL:    "https://github.com/login/oauth/access_token",
+			userInfoURL: "https://api.github.com/user",
+			scopes:      "read:user user:email",
+			config:      config.GitHub,
+		},
+		"wechat": {
+			name:        "wechat",
+			authURL:     "https://open.weixin.qq.com/connect/qrconnect",
+			tokenURL:    "https://api.weixin.qq.com/sns/oauth2/access_token",
+			userInfoURL: "https://api.weixin.qq.com/sns/userinfo",
+			scopes:      "snsapi_login",
+			wechatStyle: true,
+			config:      config.WeChat,
+		},
+	}
+
+	return &oauthService{
+		authService:  authService,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		config:       config,
+		providers:    providers,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		pending:      make(map[string]pendingAuth),
+	}
+}
+
+// BeginLogin starts an authorization flow for a provider
+func (s *oauthService) BeginLogin(provider string, linkUserID int) (string, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	verifier, err := randomToken(48)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	s.mu.Lock()
+	s.prunePendingLocked()
+	s.pending[state] = pendingAuth{
+		provider:     provider,
+		codeVerifier: verifier,
+		linkUserID:   linkUserID,
+		expiresAt:    time.Now().Add(stateTTL),
+	}
+	s.mu.Unlock()
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("redirect_uri", s.redirectURL(provider))
+	query.Set("scope", p.scopes)
+	query.Set("state", state)
+	if p.wechatStyle {
+		// WeChat uses appid instead of client_id and has no PKCE support
+		query.Set("appid", p.config.ClientID)
+	} else {
+		query.Set("client_id", p.config.ClientID)
+		query.Set("code_challenge", pkceChallenge(verifier))
+		query.Set("code_challenge_method", "S256")
+	}
+
+	return p.authURL + "?" + query.Encode(), nil
+}
+
+// CompleteLogin finishes the authorization flow from the provider callback
+func (s *oauthService) CompleteLogin(provider, code, state string) (*model.LoginResponse, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	auth, ok := s.pending[state]
+	delete(s.pending, state)
+	s.mu.Unlock()
+	if !ok || auth.provider != provider || time.Now().After(auth.expiresAt) {
+		return nil, fmt.Errorf("invalid or expired state")
+	}
+
+	accessToken, openID, err := s.exchangeCode(p, code, auth.codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.fetchClaims(p, accessToken, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(p.name, claims, auth.linkUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.IssueToken(user)
+}
+
+// ListIdentities lists the external identities linked to a user
+func (s *oauthService) ListIdentities(userID int) ([]*model.UserIdentity, error) {
+	return s.identityRepo.ListByUser(userID)
+}
+
+// Unlink removes a linked provider identity
+func (s *oauthService) Unlink(userID int, provider string) error {
+	if _, err := s.provider(provider); err != nil {
+		return err
+	}
+	return s.identityRepo.Delete(userID, provider)
+}
+
+// resolveUser maps provider claims to a local user: an existing link wins,
+// then an explicit link request, otherwise a new user is provisioned
+func (s *oauthService) resolveUser(provider string, claims *providerClaims, linkUserID int) (*model.User, error) {
+	identity, err := s.identityRepo.GetByProvider(provider, claims.providerUserID)
+	if err == nil {
+		if linkUserID != 0 && identity.UserID != linkUserID {
+			return nil, fmt.Errorf("identity already linked to another account")
+		}
+		return s.userRepo.GetByID(identity.UserID)
+	}
+
+	var user *model.User
+	if linkUserID != 0 {
+		// Account linking: attach the identity to the authenticated user
+		user, err = s.userRepo.GetByID(linkUserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user for linking: %w", err)
+		}
+	} else if claims.email != "" {
+		// Match by verified email to avoid duplicate accounts
+		user, _ = s.userRepo.GetByEmail(claims.email)
+	}
+
+	if user == nil {
+		user, err = s.provisionUser(provider, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identityRepo.Create(&model.UserIdentity{
+		UserID:         user.ID,
+		Provider:       provider,
+		ProviderUserID: claims.providerUserID,
+		Email:          claims.email,
+		DisplayName:    claims.displayName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionUser creates a local user from provider claims. The account
+// gets a random unusable password; it can only log in via the provider
+// until a password is set.
+func (s *oauthService) provisionUser(provider string, claims *providerClaims) (*model.User, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	username := claims.displayName
+	if username == "" {
+		username = provider + "_" + claims.providerUserID
+	}
+	// Ensure the username is unique; fall back to a provider-scoped name
+	if _, err := s.userRepo.GetByUsername(username); err == nil {
+		username = provider + "_" + claims.providerUserID
+	}
+
+	user := &model.User{
+		Username: username,
+		Email:    claims.email,
+		Password: string(hashed),
+		Role:     "user",
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to provision user: %w", err)
+	}
+	return user, nil
+}
+
+// exchangeCode swaps the authorization code for an access token. For
+// WeChat-style providers the returned openid accompanies the token.
+func (s *oauthService) exchangeCode(p *oauthProvider, code, verifier string) (accessToken, openID string, err error) {
+	var req *http.Request
+	if p.wechatStyle {
+		query := url.Values{}
+		query.Set("appid", p.config.ClientID)
+		query.Set("secret", p.config.ClientSecret)
+		query.Set("code", code)
+		query.Set("grant_type", "authorization_code")
+		req, err = http.NewRequest(http.MethodGet, p.tokenURL+"?"+query.Encode(), nil)
+	} else {
+		form := url.Values{}
+		form.Set("grant_type", "authorization_code")
+		form.Set("code", code)
+		form.Set("client_id", p.config.ClientID)
+		form.Set("client_secret", p.config.ClientSecret)
+		form.Set("redirect_uri", s.redirectURL(p.name))
+		form.Set("code_verifier", verifier)
+		req, err = http.NewRequest(http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("token exchange failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		OpenID      string `json:"openid"`
+		Error       string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", "", fmt.Errorf("token exchange rejected: %s", token.Error)
+	}
+	return token.AccessToken, token.OpenID, nil
+}
+
+// fetchClaims retrieves and normalizes the provider user info
+func (s *oauthService) fetchClaims(p *oauthProvider, accessToken, openID string) (*providerClaims, error) {
+	infoURL := p.userInfoURL
+	if p.wechatStyle {
+		query := url.Values{}
+		query.Set("access_token", accessToken)
+		query.Set("openid", openID)
+		infoURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, infoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build userinfo request: %w", err)
+	}
+	if !p.wechatStyle {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read userinfo response: %w", err)
+	}
+
+	var info struct {
+		// OIDC (Google)
+		Sub   string `json:"sub"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+		// GitHub
+		ID    json.Number `json:"id"`
+		Login string      `json:"login"`
+		// WeChat
+		OpenID   string `json:"openid"`
+		Nickname string `json:"nickname"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse userinfo response: %w", err)
+	}
+
+	claims := &providerClaims{email: info.Email}
+	switch {
+	case info.Sub != "":
+		claims.providerUserID = info.Sub
+		claims.displayName = info.Name
+	case info.OpenID != "" || openID != "":
+		claims.providerUserID = info.OpenID
+		if claims.providerUserID == "" {
+			claims.providerUserID = openID
+		}
+		claims.displayName = info.Nickname
+	case info.ID.String() != "":
+		claims.providerUserID = info.ID.String()
+		claims.displayName = info.Login
+		if claims.displayName == "" {
+			claims.displayName = info.Name
+		}
+	default:
+		return nil, fmt.Errorf("provider returned no user identifier")
+	}
+	return claims, nil
+}
+
+// provider looks up a configured provider by name
+func (s *oauthService) provider(name string) (*oauthProvider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s", name)
+	}
+	if p.config.ClientID == "" || p.config.ClientSecret == "" {
+		return nil, fmt.Errorf("provider not configured: %s", name)
+	}
+	return p, nil
+}
+
+// redirectURL builds the callback URL for a provider
+func (s *oauthService) redirectURL(provider string) string {
+	base := strings.TrimRight(s.config.RedirectBaseURL, "/")
+	return base + "/api/v1/auth/oauth/" + provider + "/callback"
+}
+
+// prunePendingLocked removes expired states; caller must hold the lock
+func (s *oauthService) prunePendingLocked() {
+	now := time.Now()
+	for state, auth := range s.pending {
+		if now.After(auth.expiresAt) {
+			delete(s.pending, state)
+		}
+	}
+}
+
+// pkceChallenge derives the S256 code challenge from a verifier
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomToken returns a URL-safe random string
+func randomToken(bytes int) (string, error) {
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
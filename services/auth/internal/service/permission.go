@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/repository"
+)
+
+// permissionCacheTTL is how long role permissions stay cached before
+// being re-read from the database
+const permissionCacheTTL = 5 * time.Minute
+
+// PermissionService interface defines permission checking operations
+type PermissionService interface {
+	HasPermission(role, permission string) (bool, error)
+	Grant(role, permission string) error
+	Revoke(role, permission string) error
+	List() ([]*model.RolePermission, error)
+}
+
+// permissionService implements PermissionService with a Redis-backed
+// cache; when Redis is not configured it falls back to an in-process cache
+type permissionService struct {
+	repo  repository.PermissionRepository
+	redis *redis.Client
+
+	mu    sync.RWMutex
+	local map[string]localCacheEntry
+}
+
+type localCacheEntry struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+// NewPermissionService creates a new permission service. redisURL may be
+// empty, in which case caching is in-process only.
+func NewPermissionService(repo repository.PermissionRepository, redisURL string) (PermissionService, error) {
+	svc := &permissionService{
+		repo:  repo,
+		local: make(map[string]localCacheEntry),
+	}
+	if redisURL != "" {
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+		svc.redis = redis.NewClient(opts)
+	}
+	return svc, nil
+}
+
+// HasPermission reports whether a role holds the given permission.
+// The wildcard grant "*" matches everything; "users:*" matches any
+// action on the users resource.
+func (s *permissionService) HasPermission(role, permission string) (bool, error) {
+	permissions, err := s.rolePermissions(role)
+	if err != nil {
+		return false, err
+	}
+	for _, granted := range permissions {
+		if permissionMatches(granted, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Grant grants a permission to a role and invalidates the cache
+func (s *permissionService) Grant(role, permission string) error {
+	if err := s.repo.Grant(role, permission); err != nil {
+		return err
+	}
+	s.invalidate(role)
+	return nil
+}
+
+// Revoke removes a permission from a role and invalidates the cache
+func (s *permissionService) Revoke(role, permission string) error {
+	if err := s.repo.Revoke(role, permission); err != nil {
+		return err
+	}
+	s.invalidate(role)
+	return nil
+}
+
+// List returns all role permission grants
+func (s *permissionService) List() ([]*model.RolePermission, error) {
+	return s.repo.List()
+}
+
+// rolePermissions returns the cached permission list for a role,
+// reading through to the database on a miss
+func (s *permissionService) rolePermissions(role string) ([]string, error) {
+	if permissions, ok := s.cacheGet(role); ok {
+		return permissions, nil
+	}
+
+	permissions, err := s.repo.GetByRole(role)
+	if err != nil {
+		return nil, err
+	}
+	s.cacheSet(role, permissions)
+	return permissions, nil
+}
+
+func (s *permissionService) cacheKey(role string) string {
+	return "auth:permissions:" + role
+}
+
+func (s *permissionService) cacheGet(role string) ([]string, bool) {
+	if s.redis != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		raw, err := s.redis.Get(ctx, s.cacheKey(role)).Result()
+		if err == nil {
+			var permissions []string
+			if json.Unmarshal([]byte(raw), &permissions) == nil {
+				return permissions, true
+			}
+		}
+		// Redis miss or unavailable: fall through to local cache
+	}
+
+	s.mu.RLock()
+	entry, ok := s.local[role]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, true
+	}
+	return nil, false
+}
+
+func (s *permissionService) cacheSet(role string, permissions []string) {
+	if s.redis != nil {
+		if raw, err := json.Marshal(permissions); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			s.redis.Set(ctx, s.cacheKey(role), raw, permissionCacheTTL)
+		}
+	}
+
+	s.mu.Lock()
+	s.local[role] = localCacheEntry{permissions: permissions, expiresAt: time.Now().Add(permissionCacheTTL)}
+	s.mu.Unlock()
+}
+
+func (s *permissionService) invalidate(role string) {
+	if s.redis != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.redis.Del(ctx, s.cacheKey(role))
+	}
+
+	s.mu.Lock()
+	delete(s.local, role)
+	s.mu.Unlock()
+}
+
+// permissionMatches reports whether a granted permission covers the
+// required one. Permissions use the "resource:action" form; a trailing
+// "*" acts as a prefix wildcard.
+func permissionMatches(granted, required string) bool {
+	if granted == "*" || granted == required {
+		return true
+	}
+	if strings.HasSuffix(granted, ":*") {
+		return strings.HasPrefix(required, strings.TrimSuffix(granted, "*"))
+	}
+	return false
+}
@@ -23,15 +23,20 @@ type AuthService interface {
 	GetUser(userID int) (*model.User, error)
 	ValidateToken(token string) (*model.User, error)
 	RevokeToken(token string, reason string) error
+	IssueToken(user *model.User) (*model.LoginResponse, error)
+	SetTwoFactorService(totp TOTPService)
 }
 
 // authService implements AuthService
 type authService struct {
-	userRepo     repository.UserRepository
-	sessionRepo  repository.SessionRepository
+	userRepo      repository.UserRepository
+	sessionRepo   repository.SessionRepository
 	blacklistRepo repository.BlacklistRepository
-	jwtSecret    string
-	jwtExp       int
+	jwtSecret     string
+	jwtExp        int
+	// twoFactor is optional; when set, Login returns a second-factor
+	// challenge instead of a token for 2FA-enabled accounts
+	twoFactor TOTPService
 }
 
 // NewAuthService creates a new authentication service
@@ -43,11 +48,11 @@ func NewAuthService(
 	jwtExp int,
 ) AuthService {
 	return &authService{
-		userRepo:     userRepo,
-		sessionRepo:  sessionRepo,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
 		blacklistRepo: blacklistRepo,
-		jwtSecret:    jwtSecret,
-		jwtExp:       jwtExp,
+		jwtSecret:     jwtSecret,
+		jwtExp:        jwtExp,
 	}
 }
 
@@ -69,6 +74,31 @@ func (s *authService) Login(req *model.LoginRequest) (*model.LoginResponse, erro
 		return nil, fmt.Errorf("invalid username or password")
 	}
 
+	// Second factor: 2FA-enabled accounts get a challenge token instead
+	// of a JWT; role policy may require enrollment
+	if s.twoFactor != nil {
+		challenge, enrollmentRequired, err := s.twoFactor.LoginOutcome(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check two-factor state: %w", err)
+		}
+		if challenge != "" {
+			user.Password = ""
+			return &model.LoginResponse{
+				User:              *user,
+				TwoFactorRequired: true,
+				TwoFactorToken:    challenge,
+			}, nil
+		}
+		if enrollmentRequired {
+			resp, err := s.IssueToken(user)
+			if err != nil {
+				return nil, err
+			}
+			resp.TwoFactorEnrollmentRequired = true
+			return resp, nil
+		}
+	}
+
 	// Generate JWT token
 	token, expiresAt, err := s.generateToken(user)
 	if err != nil {
@@ -95,6 +125,37 @@ func (s *authService) Login(req *model.LoginRequest) (*model.LoginResponse, erro
 	}, nil
 }
 
+// SetTwoFactorService wires the optional TOTP second factor into login
+func (s *authService) SetTwoFactorService(totp TOTPService) {
+	s.twoFactor = totp
+}
+
+// IssueToken generates a JWT token and session for an already
+// authenticated user (used by OAuth logins which skip the password check)
+func (s *authService) IssueToken(user *model.User) (*model.LoginResponse, error) {
+	token, expiresAt, err := s.generateToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	session := &model.Session{
+		UserID:       user.ID,
+		RefreshToken: token,
+		ExpiresAt:    expiresAt,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	user.Password = ""
+
+	return &model.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      *user,
+	}, nil
+}
+
 // Register creates a new user
 func (s *authService) Register(req *model.RegisterRequest) error {
 	// Check if username already exists
@@ -359,4 +420,4 @@ func generateRandomString(length int) (string, error) {
 		return "", err
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
-}
\ No newline at end of file
+}
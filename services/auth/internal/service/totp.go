@@ -0,0 +1,276 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codetaoist/taishanglaojun/auth/internal/model"
+	"github.com/codetaoist/taishanglaojun/auth/internal/repository"
+)
+
+const (
+	// totpIssuer appears in authenticator apps
+	totpIssuer = "TaiShangLaoJun"
+	// totpStep is the RFC 6238 time step
+	totpStep = 30 * time.Second
+	// totpDigits is the code length
+	totpDigits = 6
+	// totpSkew allows one step of clock drift in either direction
+	totpSkew = 1
+	// challengeTTL is how long a pending second-factor challenge is valid
+	challengeTTL = 5 * time.Minute
+	// backupCodeCount is the number of backup codes issued on activation
+	backupCodeCount = 10
+)
+
+// TOTPService interface defines two-factor authentication operations
+type TOTPService interface {
+	// Enroll generates a new secret and returns it with the otpauth
+	// provisioning URI for QR rendering; 2FA stays inactive until the
+	// first code is verified via Activate
+	Enroll(userID int, username string) (secret, provisioningURI string, err error)
+	// Activate verifies the first code and enables 2FA, returning the
+	// one-time backup codes
+	Activate(userID int, code string) ([]string, error)
+	// Disable turns 2FA off after verifying a current code
+	Disable(userID int, code string) error
+	// Status reports whether the user has 2FA enabled
+	Status(userID int) (enrolled, enabled bool, err error)
+	// LoginOutcome inspects a user after password verification and
+	// reports whether a second factor is needed (returning a challenge
+	// token) or enrollment is required by role policy
+	LoginOutcome(user *model.User) (challengeToken string, enrollmentRequired bool, err error)
+	// CompleteChallenge validates a challenge token plus TOTP or backup
+	// code and returns the user ID it belongs to
+	CompleteChallenge(challengeToken, code string) (int, error)
+	// SetRolePolicy sets whether 2FA is required for a role
+	SetRolePolicy(role string, required bool) error
+	// IsRoleRequired reports whether 2FA is required for a role
+	IsRoleRequired(role string) (bool, error)
+}
+
+// pendingChallenge tracks an issued second-factor challenge
+type pendingChallenge struct {
+	userID    int
+	expiresAt time.Time
+}
+
+// totpService implements TOTPService
+type totpService struct {
+	repo repository.TOTPRepository
+
+	mu         sync.Mutex
+	challenges map[string]pendingChallenge
+}
+
+// NewTOTPService creates a new TOTP service
+func NewTOTPService(repo repository.TOTPRepository) TOTPService {
+	return &totpService{
+		repo:       repo,
+		challenges: make(map[string]pendingChallenge),
+	}
+}
+
+// Enroll generates and stores a new TOTP secret
+func (s *totpService) Enroll(userID int, username string) (string, string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+
+	if err := s.repo.Upsert(userID, secret); err != nil {
+		return "", "", err
+	}
+
+	label := url.PathEscape(totpIssuer + ":" + username)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", totpDigits))
+	query.Set("period", fmt.Sprintf("%d", int(totpStep.Seconds())))
+	uri := "otpauth://totp/" + label + "?" + query.Encode()
+
+	return secret, uri, nil
+}
+
+// Activate verifies the first code and enables the enrollment
+func (s *totpService) Activate(userID int, code string) ([]string, error) {
+	totp, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !validateTOTP(totp.Secret, code, time.Now()) {
+		return nil, fmt.Errorf("invalid verification code")
+	}
+	if err := s.repo.Enable(userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, backupCodeCount)
+	hashes := make([]string, 0, backupCodeCount)
+	for i := 0; i < backupCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate backup code: %w", err)
+		}
+		code := hex.EncodeToString(buf)
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(code))
+	}
+	if err := s.repo.ReplaceBackupCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns 2FA off after verifying a current code
+func (s *totpService) Disable(userID int, code string) error {
+	totp, err := s.repo.Get(userID)
+	if err != nil {
+		return err
+	}
+	if !validateTOTP(totp.Secret, code, time.Now()) {
+		return fmt.Errorf("invalid verification code")
+	}
+	return s.repo.Delete(userID)
+}
+
+// Status reports the enrollment state of a user
+func (s *totpService) Status(userID int) (bool, bool, error) {
+	totp, err := s.repo.Get(userID)
+	if err != nil {
+		if err.Error() == "totp not enrolled" {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, totp.Enabled, nil
+}
+
+// LoginOutcome decides the second-factor step after password login
+func (s *totpService) LoginOutcome(user *model.User) (string, bool, error) {
+	_, enabled, err := s.Status(user.ID)
+	if err != nil {
+		return "", false, err
+	}
+	if enabled {
+		token, err := s.issueChallenge(user.ID)
+		if err != nil {
+			return "", false, err
+		}
+		return token, false, nil
+	}
+
+	required, err := s.repo.IsRoleRequired(user.Role)
+	if err != nil {
+		return "", false, err
+	}
+	return "", required, nil
+}
+
+// CompleteChallenge validates a challenge token plus code
+func (s *totpService) CompleteChallenge(challengeToken, code string) (int, error) {
+	s.mu.Lock()
+	challenge, ok := s.challenges[challengeToken]
+	delete(s.challenges, challengeToken)
+	s.mu.Unlock()
+	if !ok || time.Now().After(challenge.expiresAt) {
+		return 0, fmt.Errorf("invalid or expired two-factor token")
+	}
+
+	totp, err := s.repo.Get(challenge.userID)
+	if err != nil {
+		return 0, err
+	}
+	if validateTOTP(totp.Secret, code, time.Now()) {
+		return challenge.userID, nil
+	}
+
+	// Fall back to backup codes
+	used, err := s.repo.ConsumeBackupCode(challenge.userID, hashBackupCode(code))
+	if err != nil {
+		return 0, err
+	}
+	if !used {
+		return 0, fmt.Errorf("invalid verification code")
+	}
+	return challenge.userID, nil
+}
+
+// SetRolePolicy sets whether 2FA is required for a role
+func (s *totpService) SetRolePolicy(role string, required bool) error {
+	return s.repo.SetRoleRequired(role, required)
+}
+
+// IsRoleRequired reports whether 2FA is required for a role
+func (s *totpService) IsRoleRequired(role string) (bool, error) {
+	return s.repo.IsRoleRequired(role)
+}
+
+// issueChallenge creates a short-lived second-factor token
+func (s *totpService) issueChallenge(userID int) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	for key, challenge := range s.challenges {
+		if now.After(challenge.expiresAt) {
+			delete(s.challenges, key)
+		}
+	}
+	s.challenges[token] = pendingChallenge{userID: userID, expiresAt: now.Add(challengeTTL)}
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+// validateTOTP checks an RFC 6238 code allowing totpSkew steps of drift
+func validateTOTP(secret, code string, at time.Time) bool {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return false
+	}
+
+	counter := uint64(at.Unix()) / uint64(totpStep.Seconds())
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		if hotp(key, counter+uint64(int64(skew))) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hotp computes an RFC 4226 code for a counter value
+func hotp(key []byte, counter uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// hashBackupCode hashes a backup code for storage
+func hashBackupCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(strings.ToLower(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -7,8 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	
+
 	"github.com/codetaoist/taishanglaojun/auth/internal/config"
+	"github.com/codetaoist/taishanglaojun/auth/internal/grpcserver"
 	"github.com/codetaoist/taishanglaojun/auth/internal/router"
 )
 
@@ -44,7 +45,22 @@ func main() {
 	r.Use(gin.Recovery())
 
 	// 设置路由
-	router.Setup(cfg, r, db)
+	authService, err := router.Setup(cfg, r, db)
+	if err != nil {
+		log.Fatalf("Failed to setup router: %v", err)
+	}
+
+	// 启动gRPC服务器（与REST并行，供内部服务校验令牌）
+	if cfg.GRPCPort != "" {
+		grpcServer := grpcserver.New(authService)
+		go func() {
+			log.Printf("Auth gRPC listening on :%s", cfg.GRPCPort)
+			if err := grpcServer.Serve(cfg.GRPCPort); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+		defer grpcServer.Stop()
+	}
 
 	// 获取端口
 	port := cfg.Port
@@ -58,4 +74,4 @@ func main() {
 	if err := r.Run(addr); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS lao_login_regions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    region VARCHAR(100),
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, country),
+    FOREIGN KEY (user_id) REFERENCES lao_users(id) ON DELETE CASCADE
+);
+
+-- Region-based feature policies. country '*' matches any country;
+-- deny rules win over allow rules.
+CREATE TABLE IF NOT EXISTS lao_region_policies (
+    id SERIAL PRIMARY KEY,
+    feature VARCHAR(50) NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('allow', 'deny')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (feature, country)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS lao_role_permissions (
+    id SERIAL PRIMARY KEY,
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (role, permission)
+);
+
+-- Default grants: admin gets the wildcard, regular users get read access
+INSERT INTO lao_role_permissions (role, permission) VALUES
+    ('admin', '*'),
+    ('user', 'users:read')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS lao_user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    provider_user_id VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    display_name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (provider, provider_user_id),
+    FOREIGN KEY (user_id) REFERENCES lao_users(id) ON DELETE CASCADE
+);
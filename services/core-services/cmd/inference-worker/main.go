@@ -0,0 +1,75 @@
+// inference-worker 本地模型推理worker
+//
+// 以Ollama（或llama.cpp server）为运行时，对外提供与AI补全
+// 服务一致的 /v1/completions 接口；核心服务把ai_service_url
+// 指向本worker即可使用"Local"提供方。
+//
+// 用法示例：
+//
+//	inference-worker -models qwen2:7b,llama3:8b -device gpu
+//	inference-worker -models qwen2:7b -device cpu -core-url http://localhost:8082
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/inference"
+)
+
+func main() {
+	port := flag.String("port", "8090", "worker监听端口")
+	ollamaURL := flag.String("ollama-url", "http://localhost:11434", "模型运行时地址")
+	models := flag.String("models", "", "逗号分隔的模型列表（启动时下载并预热）")
+	device := flag.String("device", "cpu", "推理设备：cpu或gpu")
+	gpuLayers := flag.Int("gpu-layers", 0, "gpu模式下卸载到显存的层数（0表示全部）")
+	keepAlive := flag.Duration("keep-alive", 30*time.Minute, "预热模型的保留时长")
+	coreURL := flag.String("core-url", "", "核心服务地址，非空时注册provider限制")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	var modelList []string
+	for _, model := range strings.Split(*models, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			modelList = append(modelList, model)
+		}
+	}
+
+	worker, err := inference.NewWorker(inference.Config{
+		OllamaURL: *ollamaURL,
+		Models:    modelList,
+		Device:    *device,
+		GPULayers: *gpuLayers,
+		KeepAlive: *keepAlive,
+		CoreURL:   *coreURL,
+	}, logger)
+	if err != nil {
+		log.Fatalf("Failed to create inference worker: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	if err := worker.Start(ctx); err != nil {
+		cancel()
+		log.Fatalf("Failed to start inference worker: %v", err)
+	}
+	cancel()
+	defer worker.Stop()
+
+	router := gin.Default()
+	worker.SetupRoutes(router)
+	logger.Info("inference worker listening", zap.String("port", *port))
+	if err := router.Run(":" + *port); err != nil {
+		log.Fatalf("Failed to run inference worker: %v", err)
+	}
+}
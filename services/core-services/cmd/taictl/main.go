@@ -0,0 +1,291 @@
+// taictl 运维命令行工具
+//
+// 封装认证服务与核心服务的管理API，运维人员无需手工拼curl请求。
+// 连接信息与令牌保存在配置文件的profile中：
+//
+//	~/.config/taictl/config.json
+//	{
+//	  "default": {
+//	    "auth_url": "http://localhost:8081",
+//	    "core_url": "http://localhost:8082",
+//	    "token": "<jwt>"
+//	  }
+//	}
+//
+// 用法示例：
+//
+//	taictl -profile staging user get 42
+//	taictl permissions list admin
+//	taictl jobs list
+//	taictl jobs run <jobId>
+//	taictl apikeys revoke <keyId>
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Profile 单个环境的连接配置
+type Profile struct {
+	AuthURL string `json:"auth_url"`
+	CoreURL string `json:"core_url"`
+	Token   string `json:"token"`
+}
+
+// client 携带profile的HTTP客户端
+type client struct {
+	profile Profile
+	http    *http.Client
+}
+
+func main() {
+	profileName := flag.String("profile", "default", "配置文件中的profile名")
+	configPath := flag.String("config", "", "配置文件路径（默认 ~/.config/taictl/config.json）")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		fatalf("failed to load profile: %v", err)
+	}
+	c := &client{profile: profile, http: &http.Client{Timeout: 15 * time.Second}}
+
+	if err := c.run(args); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+// usage 打印帮助
+func usage() {
+	fmt.Fprintf(os.Stderr, `taictl - taishanglaojun运维命令行工具
+
+用法: taictl [-profile NAME] <命令> [参数...]
+
+命令:
+  user get <id>                       查询用户（认证服务）
+  permissions list <role>             列出角色权限
+  permissions grant <role> <perm>     授予权限
+  permissions revoke <role> <perm>    撤销权限
+  jobs list                           列出仓库导出作业
+  jobs run <jobId>                    立即执行导出作业
+  jobs runs [jobId]                   查看导出运行记录
+  apikeys list                        列出API密钥
+  apikeys rotate <keyId>              轮换API密钥
+  apikeys revoke <keyId>              吊销API密钥
+  review pending                      查看待复核队列
+  terms status <userId>               查看用户条款接受状态
+  events consumers                    列出可重放的事件消费者
+  events replay <consumer> <since>    重放事件到消费者（since为RFC3339，--dry-run预览）
+  events replays [replayId]           查看重放任务进度
+  events resume <replayId>            续传中断的重放
+`)
+	flag.PrintDefaults()
+}
+
+// loadProfile 从配置文件读取指定profile
+func loadProfile(path, name string) (Profile, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Profile{}, err
+		}
+		path = filepath.Join(home, ".config", "taictl", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var profiles map[string]Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return Profile{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile not found: %s", name)
+	}
+	return profile, nil
+}
+
+// run 分发子命令
+func (c *client) run(args []string) error {
+	switch args[0] {
+	case "user":
+		return c.runUser(args[1:])
+	case "permissions":
+		return c.runPermissions(args[1:])
+	case "jobs":
+		return c.runJobs(args[1:])
+	case "apikeys":
+		return c.runAPIKeys(args[1:])
+	case "review":
+		return c.runReview(args[1:])
+	case "terms":
+		return c.runTerms(args[1:])
+	case "events":
+		return c.runEvents(args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func (c *client) runUser(args []string) error {
+	if len(args) == 2 && args[0] == "get" {
+		return c.call(http.MethodGet, c.profile.AuthURL, "/api/v1/admin/users/"+args[1], nil)
+	}
+	return fmt.Errorf("usage: taictl user get <id>")
+}
+
+func (c *client) runPermissions(args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "list":
+		return c.call(http.MethodGet, c.profile.AuthURL, "/api/v1/admin/permissions?role="+args[1], nil)
+	case len(args) == 3 && args[0] == "grant":
+		return c.call(http.MethodPost, c.profile.AuthURL, "/api/v1/admin/permissions",
+			map[string]string{"role": args[1], "permission": args[2]})
+	case len(args) == 3 && args[0] == "revoke":
+		return c.call(http.MethodDelete, c.profile.AuthURL, "/api/v1/admin/permissions",
+			map[string]string{"role": args[1], "permission": args[2]})
+	}
+	return fmt.Errorf("usage: taictl permissions list|grant|revoke <role> [perm]")
+}
+
+func (c *client) runJobs(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/warehouse/jobs", nil)
+	case len(args) == 2 && args[0] == "run":
+		return c.call(http.MethodPost, c.profile.CoreURL, "/api/v1/warehouse/jobs/"+args[1]+"/run", nil)
+	case len(args) >= 1 && args[0] == "runs":
+		path := "/api/v1/warehouse/runs"
+		if len(args) == 2 {
+			path = "/api/v1/warehouse/jobs/" + args[1] + "/runs"
+		}
+		return c.call(http.MethodGet, c.profile.CoreURL, path, nil)
+	}
+	return fmt.Errorf("usage: taictl jobs list|run|runs [jobId]")
+}
+
+func (c *client) runAPIKeys(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/apikeys", nil)
+	case len(args) == 2 && args[0] == "rotate":
+		return c.call(http.MethodPost, c.profile.CoreURL, "/api/v1/apikeys/"+args[1]+"/rotate", nil)
+	case len(args) == 2 && args[0] == "revoke":
+		return c.call(http.MethodDelete, c.profile.CoreURL, "/api/v1/apikeys/"+args[1], nil)
+	}
+	return fmt.Errorf("usage: taictl apikeys list|rotate|revoke [keyId]")
+}
+
+func (c *client) runReview(args []string) error {
+	if len(args) == 1 && args[0] == "pending" {
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/review/pending", nil)
+	}
+	return fmt.Errorf("usage: taictl review pending")
+}
+
+func (c *client) runTerms(args []string) error {
+	if len(args) == 2 && args[0] == "status" {
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/users/"+args[1]+"/terms/status", nil)
+	}
+	return fmt.Errorf("usage: taictl terms status <userId>")
+}
+
+func (c *client) runEvents(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "consumers":
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/admin/events/consumers", nil)
+	case len(args) >= 3 && args[0] == "replay":
+		payload := map[string]interface{}{
+			"consumer": args[1],
+			"since":    args[2],
+		}
+		for _, arg := range args[3:] {
+			if arg == "--dry-run" {
+				payload["dryRun"] = true
+			}
+		}
+		return c.call(http.MethodPost, c.profile.CoreURL, "/api/v1/admin/events/replays", payload)
+	case len(args) == 1 && args[0] == "replays":
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/admin/events/replays", nil)
+	case len(args) == 2 && args[0] == "replays":
+		return c.call(http.MethodGet, c.profile.CoreURL, "/api/v1/admin/events/replays/"+args[1], nil)
+	case len(args) == 2 && args[0] == "resume":
+		return c.call(http.MethodPost, c.profile.CoreURL, "/api/v1/admin/events/replays/"+args[1]+"/resume", nil)
+	}
+	return fmt.Errorf("usage: taictl events consumers|replay|replays|resume [args]")
+}
+
+// call 发送请求并把响应以缩进JSON打印到标准输出
+func (c *client) call(method, baseURL, path string, payload interface{}) error {
+	if baseURL == "" {
+		return fmt.Errorf("profile is missing the service URL for %s", path)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(baseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.profile.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.profile.Token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, data, "", "  ") == nil {
+		fmt.Println(pretty.String())
+	} else {
+		fmt.Println(string(data))
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s %s returned status %d", method, path, resp.StatusCode)
+	}
+	return nil
+}
+
+// fatalf 打印错误并退出
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "taictl: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,92 @@
+package activity
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 活动时间线HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建活动时间线处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册活动时间线相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/activity", h.List)
+	router.DELETE("/activity/:activityId", h.Delete)
+}
+
+// requireUser 从请求头取当前用户
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// List 我的历史时间线
+func (h *Handler) List(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	query := &ListQuery{}
+	if raw := c.Query("types"); raw != "" {
+		query.Types = strings.Split(raw, ",")
+	}
+	if raw := c.Query("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be RFC3339"})
+			return
+		}
+		query.Since = since
+	}
+	if raw := c.Query("until"); raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be RFC3339"})
+			return
+		}
+		query.Until = until
+	}
+	query.Limit, _ = strconv.Atoi(c.Query("limit"))
+	entries, err := h.service.List(c.Request.Context(), userID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"activities": entries, "total": len(entries)})
+}
+
+// Delete 删除单条历史记录
+func (h *Handler) Delete(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), userID, c.Param("activityId")); err != nil {
+		msg := err.Error()
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(msg, "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(msg, "cannot be deleted"):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "activity deleted"})
+}
@@ -0,0 +1,198 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 活动类型
+const (
+	TypePost    = "post"
+	TypeComment = "comment"
+	TypeTask    = "task"
+	TypeAI      = "ai"
+	TypeLogin   = "login"
+	TypeHealth  = "health"
+)
+
+// protectedTypes 出于安全审计要求不允许用户删除的活动类型
+var protectedTypes = map[string]bool{
+	TypeLogin: true,
+}
+
+// Entry 用户时间线上的一条活动
+//
+// 从领域事件流投影而来，按用户组织；原始事件保留在
+// cs_domain_events中，这里只是面向最终用户的只读视图。
+type Entry struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// Type post/comment/task/ai/login/health
+	Type string `json:"type" gorm:"index;type:varchar(16);not null"`
+	// Event 来源事件名，如 task.status_changed
+	Event string `json:"event" gorm:"type:varchar(64)"`
+	// RefID 关联资源ID
+	RefID string `json:"refId,omitempty" gorm:"type:varchar(36)"`
+	// Summary 时间线上展示的描述
+	Summary    string    `json:"summary" gorm:"type:varchar(512)"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"index"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Entry) TableName() string {
+	return "cs_user_activities"
+}
+
+// Service 用户活动时间线服务
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewService 创建活动时间线服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Entry{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate activity tables: %w", err)
+	}
+	return &Service{db: db, logger: logger}, nil
+}
+
+// eventPayload 各领域事件里与时间线相关的公共字段
+type eventPayload struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	AuthorID    string     `json:"authorId"`
+	AssigneeID  string     `json:"assigneeId"`
+	UserID      string     `json:"userId"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+// HandleEvent 事件回调：把领域事件投影到用户时间线
+//
+// 与搜索索引、webhook共用同一事件流，在main中与其它订阅者
+// 一起挂到事件发布器上；不关心的事件直接忽略。
+func (s *Service) HandleEvent(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	var common eventPayload
+	if err := json.Unmarshal(data, &common); err != nil {
+		return
+	}
+
+	var entry *Entry
+	switch event {
+	case "post.created":
+		entry = &Entry{
+			UserID: common.AuthorID, Type: TypePost, RefID: common.ID,
+			Summary: fmt.Sprintf("发布了帖子「%s」", common.Title),
+		}
+	case "comment.created":
+		entry = &Entry{
+			UserID: common.AuthorID, Type: TypeComment, RefID: common.ID,
+			Summary: "发表了评论",
+		}
+	case "task.created":
+		entry = &Entry{
+			UserID: common.AssigneeID, Type: TypeTask, RefID: common.ID,
+			Summary: fmt.Sprintf("创建了任务「%s」", common.Title),
+		}
+	case "task.status_changed":
+		// 只有完成才值得上时间线，列间普通移动忽略
+		if common.CompletedAt == nil {
+			return
+		}
+		entry = &Entry{
+			UserID: common.AssigneeID, Type: TypeTask, RefID: common.ID,
+			Summary: fmt.Sprintf("完成了任务「%s」", common.Title),
+		}
+	case "ai.session_started":
+		entry = &Entry{
+			UserID: common.UserID, Type: TypeAI, RefID: common.ID,
+			Summary: "开始了一次AI会话",
+		}
+	case "auth.login":
+		entry = &Entry{
+			UserID: common.UserID, Type: TypeLogin,
+			Summary: "登录了系统",
+		}
+	case "health.alert.triggered":
+		entry = &Entry{
+			UserID: common.UserID, Type: TypeHealth, RefID: common.ID,
+			Summary: "触发了一条健康预警",
+		}
+	default:
+		return
+	}
+	if entry.UserID == "" {
+		return
+	}
+	entry.ID = uuid.NewString()
+	entry.Event = event
+	entry.OccurredAt = time.Now()
+	if err := s.db.Create(entry).Error; err != nil {
+		s.logger.Warn("failed to record activity",
+			zap.String("event", event), zap.Error(err))
+	}
+}
+
+// ListQuery 时间线过滤参数
+type ListQuery struct {
+	Types []string
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+// List 列出用户的活动时间线
+func (s *Service) List(ctx context.Context, userID string, query *ListQuery) ([]*Entry, error) {
+	limit := query.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(query.Types) > 0 {
+		db = db.Where("type IN ?", query.Types)
+	}
+	if !query.Since.IsZero() {
+		db = db.Where("occurred_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		db = db.Where("occurred_at <= ?", query.Until)
+	}
+	var entries []*Entry
+	if err := db.Order("occurred_at DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list activities: %w", err)
+	}
+	return entries, nil
+}
+
+// Delete 删除单条活动记录
+//
+// 登录记录出于安全审计要求不可删除，其余类型允许用户清理
+// 自己的历史。
+func (s *Service) Delete(ctx context.Context, userID, entryID string) error {
+	var entry Entry
+	err := s.db.WithContext(ctx).First(&entry, "id = ? AND user_id = ?", entryID, userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("activity not found")
+		}
+		return fmt.Errorf("failed to load activity: %w", err)
+	}
+	if protectedTypes[entry.Type] {
+		return fmt.Errorf("activity type %s cannot be deleted", entry.Type)
+	}
+	if err := s.db.WithContext(ctx).Delete(&entry).Error; err != nil {
+		return fmt.Errorf("failed to delete activity: %w", err)
+	}
+	return nil
+}
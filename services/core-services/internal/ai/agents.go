@@ -0,0 +1,532 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 步骤类型
+const (
+	StepPrompt = "prompt"
+	StepTool   = "tool"
+)
+
+// 输出目标类型
+const (
+	OutputReport  = "report"
+	OutputPost    = "post"
+	OutputWebhook = "webhook"
+)
+
+// Agent运行状态
+const (
+	AgentRunRunning = "running"
+	AgentRunDone    = "done"
+	AgentRunFailed  = "failed"
+)
+
+// maxStepRetries 单步失败的重试次数
+const maxStepRetries = 2
+
+// defaultAgentBudget 未配置时的单次运行token预算
+const defaultAgentBudget = 50000
+
+// AgentTool 数据采集工具，由main注册（如取社区热帖、逾期任务）
+type AgentTool func(ctx context.Context) (string, error)
+
+// AgentWorkflow 定时Agent工作流定义
+//
+// 按cron表达式触发，依次执行步骤：tool步骤采集数据，prompt
+// 步骤把此前产出交给模型加工；最终输出按目标类型分发。
+type AgentWorkflow struct {
+	ID   string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name string `json:"name" gorm:"type:varchar(128);uniqueIndex;not null"`
+	// CronExpr 标准五段cron表达式
+	CronExpr string      `json:"cronExpr" gorm:"type:varchar(64);not null"`
+	Provider string      `json:"provider" gorm:"type:varchar(64)"`
+	Steps    []AgentStep `json:"steps" gorm:"foreignKey:WorkflowID"`
+	// OutputType report/post/webhook
+	OutputType string `json:"outputType" gorm:"type:varchar(16);not null"`
+	// OutputTarget post目标串ID或webhook事件名
+	OutputTarget string `json:"outputTarget" gorm:"type:varchar(128)"`
+	// BudgetTokens 单次运行的token预算，超出即中止
+	BudgetTokens int        `json:"budgetTokens"`
+	Paused       bool       `json:"paused"`
+	NextRunAt    *time.Time `json:"nextRunAt,omitempty" gorm:"index"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (AgentWorkflow) TableName() string {
+	return "cs_ai_agent_workflows"
+}
+
+// AgentStep 工作流中的一步
+type AgentStep struct {
+	ID         string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	WorkflowID string `json:"workflowId" gorm:"type:varchar(36);index;not null"`
+	Position   int    `json:"position" gorm:"not null"`
+	// Type prompt或tool
+	Type string `json:"type" gorm:"type:varchar(16);not null"`
+	// Tool tool步骤引用的已注册工具名
+	Tool string `json:"tool,omitempty" gorm:"type:varchar(64)"`
+	// Prompt prompt步骤的指令，此前步骤产出会附加在后
+	Prompt    string    `json:"prompt,omitempty" gorm:"type:text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (AgentStep) TableName() string {
+	return "cs_ai_agent_steps"
+}
+
+// AgentRun 一次工作流运行
+type AgentRun struct {
+	ID         string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	WorkflowID string `json:"workflowId" gorm:"type:varchar(36);index;not null"`
+	Status     string `json:"status" gorm:"type:varchar(16);index;not null"`
+	// Trigger schedule或manual
+	Trigger    string `json:"trigger" gorm:"type:varchar(16);not null"`
+	TokensUsed int    `json:"tokensUsed"`
+	// Output 最终产出（report类型从这里读取）
+	Output string `json:"output,omitempty" gorm:"type:text"`
+	// StepLogs 各步骤的产出摘要
+	StepLogs    datatypes.JSON `json:"stepLogs,omitempty" gorm:"type:jsonb"`
+	LastError   string         `json:"lastError,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time      `json:"createdAt" gorm:"index"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (AgentRun) TableName() string {
+	return "cs_ai_agent_runs"
+}
+
+// agentStepLog 步骤执行日志条目
+type agentStepLog struct {
+	Position int    `json:"position"`
+	Type     string `json:"type"`
+	Tokens   int    `json:"tokens"`
+	// Preview 产出截断预览
+	Preview string `json:"preview"`
+	Retries int    `json:"retries"`
+}
+
+// AgentService 定时Agent执行引擎
+type AgentService struct {
+	db        *gorm.DB
+	completer Completer
+	logger    *zap.Logger
+
+	mu    sync.RWMutex
+	tools map[string]AgentTool
+	// postSink post输出目标回调（由main接线到社区模块）
+	postSink func(ctx context.Context, threadID, content string) error
+	// publish webhook输出目标回调
+	publish func(event string, payload interface{})
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAgentService 创建Agent执行引擎
+func NewAgentService(db *gorm.DB, completer Completer, logger *zap.Logger) (*AgentService, error) {
+	if err := db.AutoMigrate(&AgentWorkflow{}, &AgentStep{}, &AgentRun{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate agent tables: %w", err)
+	}
+	return &AgentService{
+		db:        db,
+		completer: completer,
+		logger:    logger,
+		tools:     make(map[string]AgentTool),
+		stopCh:    make(chan struct{}),
+	}, nil
+}
+
+// RegisterTool 注册数据采集工具
+func (s *AgentService) RegisterTool(name string, tool AgentTool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[name] = tool
+}
+
+// SetPostSink 接线post输出目标
+func (s *AgentService) SetPostSink(sink func(ctx context.Context, threadID, content string) error) {
+	s.postSink = sink
+}
+
+// SetEventPublisher 接线webhook输出目标
+func (s *AgentService) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// Tools 列出已注册工具名
+func (s *AgentService) Tools() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.tools))
+	for name := range s.tools {
+		names = append(names, name)
+	}
+	return names
+}
+
+// AgentStepRequest 创建工作流时的步骤参数
+type AgentStepRequest struct {
+	Type   string `json:"type" binding:"required"`
+	Tool   string `json:"tool"`
+	Prompt string `json:"prompt"`
+}
+
+// CreateWorkflowRequest 创建工作流的参数
+type CreateWorkflowRequest struct {
+	Name         string             `json:"name" binding:"required"`
+	CronExpr     string             `json:"cronExpr" binding:"required"`
+	Provider     string             `json:"provider"`
+	Steps        []AgentStepRequest `json:"steps" binding:"required"`
+	OutputType   string             `json:"outputType" binding:"required"`
+	OutputTarget string             `json:"outputTarget"`
+	BudgetTokens int                `json:"budgetTokens"`
+}
+
+// CreateWorkflow 创建工作流
+func (s *AgentService) CreateWorkflow(ctx context.Context, req *CreateWorkflowRequest) (*AgentWorkflow, error) {
+	schedule, err := cron.ParseStandard(req.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", req.CronExpr, err)
+	}
+	if len(req.Steps) == 0 {
+		return nil, fmt.Errorf("workflow must have at least one step")
+	}
+	switch req.OutputType {
+	case OutputReport:
+	case OutputPost, OutputWebhook:
+		if req.OutputTarget == "" {
+			return nil, fmt.Errorf("output target is required for %s output", req.OutputType)
+		}
+	default:
+		return nil, fmt.Errorf("invalid output type: %s", req.OutputType)
+	}
+
+	workflow := &AgentWorkflow{
+		ID:           uuid.NewString(),
+		Name:         req.Name,
+		CronExpr:     req.CronExpr,
+		Provider:     req.Provider,
+		OutputType:   req.OutputType,
+		OutputTarget: req.OutputTarget,
+		BudgetTokens: req.BudgetTokens,
+	}
+	if workflow.BudgetTokens <= 0 {
+		workflow.BudgetTokens = defaultAgentBudget
+	}
+	next := schedule.Next(time.Now())
+	workflow.NextRunAt = &next
+
+	s.mu.RLock()
+	for i, step := range req.Steps {
+		switch step.Type {
+		case StepTool:
+			if _, ok := s.tools[step.Tool]; !ok {
+				s.mu.RUnlock()
+				return nil, fmt.Errorf("unknown tool: %s", step.Tool)
+			}
+		case StepPrompt:
+			if strings.TrimSpace(step.Prompt) == "" {
+				s.mu.RUnlock()
+				return nil, fmt.Errorf("prompt is required for prompt steps")
+			}
+		default:
+			s.mu.RUnlock()
+			return nil, fmt.Errorf("invalid step type: %s", step.Type)
+		}
+		workflow.Steps = append(workflow.Steps, AgentStep{
+			ID:         uuid.NewString(),
+			WorkflowID: workflow.ID,
+			Position:   i,
+			Type:       step.Type,
+			Tool:       step.Tool,
+			Prompt:     step.Prompt,
+		})
+	}
+	s.mu.RUnlock()
+
+	if err := s.db.WithContext(ctx).Create(workflow).Error; err != nil {
+		return nil, fmt.Errorf("failed to create agent workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// ListWorkflows 列出工作流
+func (s *AgentService) ListWorkflows(ctx context.Context) ([]*AgentWorkflow, error) {
+	var workflows []*AgentWorkflow
+	err := s.db.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Order("created_at ASC").
+		Find(&workflows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// SetPaused 暂停或恢复工作流
+func (s *AgentService) SetPaused(ctx context.Context, workflowID string, paused bool) error {
+	updates := map[string]interface{}{"paused": paused}
+	if !paused {
+		var workflow AgentWorkflow
+		if err := s.db.WithContext(ctx).First(&workflow, "id = ?", workflowID).Error; err == nil {
+			if schedule, err := cron.ParseStandard(workflow.CronExpr); err == nil {
+				updates["next_run_at"] = schedule.Next(time.Now())
+			}
+		}
+	}
+	result := s.db.WithContext(ctx).Model(&AgentWorkflow{}).
+		Where("id = ?", workflowID).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update workflow: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("workflow not found: %s", workflowID)
+	}
+	return nil
+}
+
+// TriggerNow 手动触发一次运行
+func (s *AgentService) TriggerNow(ctx context.Context, workflowID string) (*AgentRun, error) {
+	workflow, err := s.getWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return s.startRun(ctx, workflow, "manual")
+}
+
+// ListRuns 列出工作流的运行历史
+func (s *AgentService) ListRuns(ctx context.Context, workflowID string, limit int) ([]*AgentRun, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if workflowID != "" {
+		query = query.Where("workflow_id = ?", workflowID)
+	}
+	var runs []*AgentRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list agent runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun 读取单次运行
+func (s *AgentService) GetRun(ctx context.Context, runID string) (*AgentRun, error) {
+	var run AgentRun
+	err := s.db.WithContext(ctx).First(&run, "id = ?", runID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("agent run not found: %s", runID)
+		}
+		return nil, fmt.Errorf("failed to get agent run: %w", err)
+	}
+	return &run, nil
+}
+
+// Start 启动调度循环
+func (s *AgentService) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.runDue(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止调度循环
+func (s *AgentService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// runDue 触发所有到期的工作流
+func (s *AgentService) runDue(ctx context.Context) {
+	var due []*AgentWorkflow
+	err := s.db.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("paused = ? AND next_run_at <= ?", false, time.Now()).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due agent workflows", zap.Error(err))
+		return
+	}
+	for _, workflow := range due {
+		if _, err := s.startRun(ctx, workflow, "schedule"); err != nil {
+			s.logger.Error("failed to start agent run",
+				zap.String("workflow", workflow.Name), zap.Error(err))
+		}
+	}
+}
+
+// getWorkflow 读取工作流（含步骤）
+func (s *AgentService) getWorkflow(ctx context.Context, workflowID string) (*AgentWorkflow, error) {
+	var workflow AgentWorkflow
+	err := s.db.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&workflow, "id = ?", workflowID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("workflow not found: %s", workflowID)
+		}
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+	return &workflow, nil
+}
+
+// startRun 创建运行记录并异步执行，推进下次调度时间
+func (s *AgentService) startRun(ctx context.Context, workflow *AgentWorkflow, trigger string) (*AgentRun, error) {
+	run := &AgentRun{
+		ID:         uuid.NewString(),
+		WorkflowID: workflow.ID,
+		Status:     AgentRunRunning,
+		Trigger:    trigger,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to create agent run: %w", err)
+	}
+	if trigger == "schedule" {
+		if schedule, err := cron.ParseStandard(workflow.CronExpr); err == nil {
+			s.db.WithContext(ctx).Model(&AgentWorkflow{}).
+				Where("id = ?", workflow.ID).
+				Update("next_run_at", schedule.Next(time.Now()))
+		}
+	}
+	go s.execute(workflow, run)
+	return run, nil
+}
+
+// execute 依次执行步骤并分发输出
+func (s *AgentService) execute(workflow *AgentWorkflow, run *AgentRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	provider := workflow.Provider
+	if provider == "" {
+		provider = defaultProvider
+	}
+
+	var logs []agentStepLog
+	output := ""
+	for _, step := range workflow.Steps {
+		result, retries, err := s.runStep(ctx, provider, &step, output)
+		tokens := EstimateTokens(result)
+		run.TokensUsed += tokens
+		logs = append(logs, agentStepLog{
+			Position: step.Position,
+			Type:     step.Type,
+			Tokens:   tokens,
+			Preview:  truncateSnippet(result),
+			Retries:  retries,
+		})
+		if err != nil {
+			s.finishRun(run, AgentRunFailed, output, logs,
+				fmt.Sprintf("step %d failed: %v", step.Position, err))
+			return
+		}
+		if run.TokensUsed > workflow.BudgetTokens {
+			s.finishRun(run, AgentRunFailed, output, logs,
+				fmt.Sprintf("budget exceeded: %d > %d tokens", run.TokensUsed, workflow.BudgetTokens))
+			return
+		}
+		output = result
+	}
+
+	if err := s.dispatch(ctx, workflow, output); err != nil {
+		s.finishRun(run, AgentRunFailed, output, logs, "output dispatch failed: "+err.Error())
+		return
+	}
+	s.finishRun(run, AgentRunDone, output, logs, "")
+}
+
+// runStep 执行单步（带重试），返回产出与重试次数
+func (s *AgentService) runStep(ctx context.Context, provider string, step *AgentStep, previous string) (string, int, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxStepRetries; attempt++ {
+		var result string
+		switch step.Type {
+		case StepTool:
+			s.mu.RLock()
+			tool, ok := s.tools[step.Tool]
+			s.mu.RUnlock()
+			if !ok {
+				return "", attempt, fmt.Errorf("unknown tool: %s", step.Tool)
+			}
+			result, lastErr = tool(ctx)
+		case StepPrompt:
+			prompt := step.Prompt
+			if previous != "" {
+				prompt = prompt + "\n\n此前步骤的产出：\n" + previous
+			}
+			result, lastErr = s.completer.Complete(ctx, provider, prompt)
+		default:
+			return "", attempt, fmt.Errorf("invalid step type: %s", step.Type)
+		}
+		if lastErr == nil {
+			return result, attempt, nil
+		}
+	}
+	return "", maxStepRetries, lastErr
+}
+
+// dispatch 把最终产出送往目标
+func (s *AgentService) dispatch(ctx context.Context, workflow *AgentWorkflow, output string) error {
+	switch workflow.OutputType {
+	case OutputReport:
+		return nil
+	case OutputPost:
+		if s.postSink == nil {
+			return fmt.Errorf("post output is not wired")
+		}
+		return s.postSink(ctx, workflow.OutputTarget, output)
+	case OutputWebhook:
+		if s.publish == nil {
+			return fmt.Errorf("webhook output is not wired")
+		}
+		s.publish(workflow.OutputTarget, map[string]interface{}{
+			"workflowId": workflow.ID,
+			"workflow":   workflow.Name,
+			"output":     output,
+		})
+		return nil
+	default:
+		return fmt.Errorf("invalid output type: %s", workflow.OutputType)
+	}
+}
+
+// finishRun 终态更新运行记录
+func (s *AgentService) finishRun(run *AgentRun, status, output string, logs []agentStepLog, lastError string) {
+	now := time.Now()
+	run.Status = status
+	run.Output = output
+	run.LastError = lastError
+	run.CompletedAt = &now
+	if data, err := json.Marshal(logs); err == nil {
+		run.StepLogs = datatypes.JSON(data)
+	}
+	if err := s.db.Save(run).Error; err != nil {
+		s.logger.Error("failed to finalize agent run", zap.Error(err))
+	}
+}
@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AgentHandler 定时Agent HTTP处理器
+type AgentHandler struct {
+	service *AgentService
+}
+
+// NewAgentHandler 创建Agent处理器
+func NewAgentHandler(service *AgentService) *AgentHandler {
+	return &AgentHandler{service: service}
+}
+
+// SetupRoutes 注册Agent相关路由
+func (h *AgentHandler) SetupRoutes(router gin.IRouter) {
+	router.POST("/ai/agents", h.CreateWorkflow)
+	router.GET("/ai/agents", h.ListWorkflows)
+	router.GET("/ai/agents/tools", h.Tools)
+	router.PUT("/ai/agents/:workflowId/paused", h.SetPaused)
+	router.POST("/ai/agents/:workflowId/trigger", h.Trigger)
+	router.GET("/ai/agents/runs", h.ListRuns)
+	router.GET("/ai/agents/runs/:runId", h.GetRun)
+}
+
+// CreateWorkflow 创建工作流
+func (h *AgentHandler) CreateWorkflow(c *gin.Context) {
+	var req CreateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workflow, err := h.service.CreateWorkflow(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForAgentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, workflow)
+}
+
+// ListWorkflows 列出工作流
+func (h *AgentHandler) ListWorkflows(c *gin.Context) {
+	workflows, err := h.service.ListWorkflows(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workflows": workflows})
+}
+
+// Tools 列出可用工具
+func (h *AgentHandler) Tools(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"tools": h.service.Tools()})
+}
+
+// SetPaused 暂停/恢复工作流
+func (h *AgentHandler) SetPaused(c *gin.Context) {
+	var req struct {
+		Paused *bool `json:"paused" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.SetPaused(c.Request.Context(), c.Param("workflowId"), *req.Paused); err != nil {
+		c.JSON(statusForAgentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"paused": *req.Paused})
+}
+
+// Trigger 手动触发一次运行
+func (h *AgentHandler) Trigger(c *gin.Context) {
+	run, err := h.service.TriggerNow(c.Request.Context(), c.Param("workflowId"))
+	if err != nil {
+		c.JSON(statusForAgentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListRuns 列出运行历史
+func (h *AgentHandler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	runs, err := h.service.ListRuns(c.Request.Context(), c.Query("workflowId"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// GetRun 读取单次运行
+func (h *AgentHandler) GetRun(c *gin.Context) {
+	run, err := h.service.GetRun(c.Request.Context(), c.Param("runId"))
+	if err != nil {
+		c.JSON(statusForAgentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// statusForAgentError 根据错误内容映射HTTP状态码
+func statusForAgentError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "unknown tool"),
+		strings.Contains(msg, "required"), strings.Contains(msg, "must have"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
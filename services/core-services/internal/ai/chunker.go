@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EstimateTokens 估算文本的token数
+//
+// 不依赖具体分词器的启发式：CJK字符约1字符/token，
+// 其他文本约4字符/token。偏保守（略高估），用于限流与
+// 分块决策足够，精确计量由计费侧完成。
+func EstimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// TruncateTokens 把文本截断到大约maxTokens
+func TruncateTokens(text string, maxTokens int) string {
+	runes := []rune(text)
+	tokens := 0
+	for i, r := range runes {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			tokens++
+		} else {
+			tokens += 1 // 非CJK按保守1:1截断，宁短勿超
+		}
+		if tokens >= maxTokens {
+			return string(runes[:i+1])
+		}
+	}
+	return text
+}
+
+// SplitChunks 把长文本切成带重叠的块
+//
+// 优先在段落边界切分，段落过长时退化为句子边界，
+// 相邻块之间保留overlapTokens的尾部重叠。
+func SplitChunks(text string, chunkTokens, overlapTokens int) []string {
+	if chunkTokens <= 0 {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		chunks = append(chunks, current.String())
+		// 下一块以当前块尾部的重叠内容开头
+		overlap := tailTokens(current.String(), overlapTokens)
+		current.Reset()
+		current.WriteString(overlap)
+		currentTokens = EstimateTokens(overlap)
+	}
+
+	for _, segment := range splitSegments(text, chunkTokens) {
+		segmentTokens := EstimateTokens(segment)
+		if currentTokens+segmentTokens > chunkTokens && currentTokens > 0 {
+			flush()
+		}
+		current.WriteString(segment)
+		currentTokens += segmentTokens
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitSegments 把文本拆成不超过chunkTokens的段落/句子片段
+func splitSegments(text string, chunkTokens int) []string {
+	var segments []string
+	for _, paragraph := range strings.SplitAfter(text, "\n\n") {
+		if EstimateTokens(paragraph) <= chunkTokens {
+			segments = append(segments, paragraph)
+			continue
+		}
+		// 段落本身超限，按句子再拆
+		segments = append(segments, splitSentences(paragraph)...)
+	}
+	return segments
+}
+
+// splitSentences 按中英文句末标点拆分
+func splitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+	for _, r := range text {
+		current.WriteRune(r)
+		switch r {
+		case '。', '！', '？', '.', '!', '?', '\n':
+			sentences = append(sentences, current.String())
+			current.Reset()
+		}
+	}
+	if current.Len() > 0 {
+		sentences = append(sentences, current.String())
+	}
+	return sentences
+}
+
+// tailTokens 取文本尾部大约n个token的内容
+func tailTokens(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	tokens := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		tokens++
+		if tokens >= n {
+			return string(runes[i:])
+		}
+	}
+	return text
+}
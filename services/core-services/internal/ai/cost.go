@@ -0,0 +1,247 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 预算告警阈值（百分比，升序）
+var budgetThresholds = []int{50, 80, 100}
+
+// Pricing 提供方计价，按百万token计美元
+type Pricing struct {
+	Provider string `json:"provider"`
+	// InputPerMTokens 输入价格（USD / 1M tokens）
+	InputPerMTokens float64 `json:"inputPerMTokens"`
+	// OutputPerMTokens 输出价格（USD / 1M tokens）
+	OutputPerMTokens float64 `json:"outputPerMTokens"`
+}
+
+// Budget 租户月度预算
+type Budget struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	TenantID string `json:"tenantId" gorm:"type:varchar(64);uniqueIndex;not null"`
+	// MonthlyLimit 月度预算（USD），0表示无限制
+	MonthlyLimit float64   `json:"monthlyLimit"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Budget) TableName() string {
+	return "cs_ai_budgets"
+}
+
+// Spend 租户月度用量累计
+type Spend struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	TenantID string `json:"tenantId" gorm:"type:varchar(64);uniqueIndex:idx_ai_spend;not null"`
+	// Month 账期，格式YYYY-MM
+	Month        string  `json:"month" gorm:"type:varchar(7);uniqueIndex:idx_ai_spend;not null"`
+	InputTokens  int64   `json:"inputTokens"`
+	OutputTokens int64   `json:"outputTokens"`
+	Cost         float64 `json:"cost"`
+	// AlertedAt 本月已触发的最高告警阈值（百分比），避免重复告警
+	AlertedAt int       `json:"alertedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Spend) TableName() string {
+	return "cs_ai_spend"
+}
+
+// CostService AI用量计费与预算告警
+//
+// 用量由计费侧（usage accounting）通过HTTP推送，按租户月度
+// 累计；跨过50/80/100%阈值时发布ai.budget_alert事件，由
+// webhook等下游转成通知。
+type CostService struct {
+	db      *gorm.DB
+	publish func(event string, payload interface{})
+
+	mu      sync.RWMutex
+	pricing map[string]Pricing
+}
+
+// NewCostService 创建计费服务
+func NewCostService(db *gorm.DB) (*CostService, error) {
+	if err := db.AutoMigrate(&Budget{}, &Spend{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate ai cost tables: %w", err)
+	}
+	return &CostService{
+		db: db,
+		pricing: map[string]Pricing{
+			defaultProvider: {Provider: defaultProvider, InputPerMTokens: 3, OutputPerMTokens: 15},
+		},
+	}, nil
+}
+
+// SetEventPublisher 注入事件发布函数
+func (s *CostService) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// SetPricing 配置提供方计价
+func (s *CostService) SetPricing(p Pricing) error {
+	if p.Provider == "" || p.InputPerMTokens < 0 || p.OutputPerMTokens < 0 {
+		return fmt.Errorf("provider and non-negative prices are required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pricing[p.Provider] = p
+	return nil
+}
+
+// pricingFor 取计价，未知提供方回落到默认值
+func (s *CostService) pricingFor(provider string) Pricing {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if p, ok := s.pricing[provider]; ok {
+		return p
+	}
+	return s.pricing[defaultProvider]
+}
+
+// Estimate 估算一次调用的token数与费用
+//
+// expectedOutputTokens<=0时按输入的1/4估算输出。
+func (s *CostService) Estimate(provider, prompt string, expectedOutputTokens int) map[string]interface{} {
+	p := s.pricingFor(provider)
+	inputTokens := EstimateTokens(prompt)
+	if expectedOutputTokens <= 0 {
+		expectedOutputTokens = inputTokens / 4
+	}
+	cost := float64(inputTokens)/1e6*p.InputPerMTokens +
+		float64(expectedOutputTokens)/1e6*p.OutputPerMTokens
+	return map[string]interface{}{
+		"provider":       p.Provider,
+		"inputTokens":    inputTokens,
+		"outputTokens":   expectedOutputTokens,
+		"estimatedCost":  cost,
+		"inputPerMTok":   p.InputPerMTokens,
+		"outputPerMTok":  p.OutputPerMTokens,
+		"estimatedAt":    time.Now(),
+		"tokenHeuristic": "cjk≈1/char, other≈1/4chars",
+	}
+}
+
+// SetBudget 配置租户月度预算
+func (s *CostService) SetBudget(ctx context.Context, tenantID string, monthlyLimit float64) (*Budget, error) {
+	if monthlyLimit < 0 {
+		return nil, fmt.Errorf("monthlyLimit must be non-negative")
+	}
+	budget := &Budget{TenantID: tenantID, MonthlyLimit: monthlyLimit}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"monthly_limit", "updated_at"}),
+	}).Create(budget).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to set budget: %w", err)
+	}
+	return budget, nil
+}
+
+// RecordUsage 记录一次实际用量并检查预算阈值
+func (s *CostService) RecordUsage(ctx context.Context, tenantID, provider string, inputTokens, outputTokens int64) (*Spend, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenantId is required")
+	}
+	p := s.pricingFor(provider)
+	cost := float64(inputTokens)/1e6*p.InputPerMTokens +
+		float64(outputTokens)/1e6*p.OutputPerMTokens
+	month := time.Now().Format("2006-01")
+
+	spend := &Spend{
+		TenantID:     tenantID,
+		Month:        month,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         cost,
+	}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "month"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"input_tokens":  gorm.Expr("cs_ai_spend.input_tokens + ?", inputTokens),
+			"output_tokens": gorm.Expr("cs_ai_spend.output_tokens + ?", outputTokens),
+			"cost":          gorm.Expr("cs_ai_spend.cost + ?", cost),
+		}),
+	}).Create(spend).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record ai usage: %w", err)
+	}
+
+	// 重新读取累计值后检查阈值
+	if err := s.db.WithContext(ctx).
+		First(spend, "tenant_id = ? AND month = ?", tenantID, month).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload ai spend: %w", err)
+	}
+	s.checkBudget(ctx, spend)
+	return spend, nil
+}
+
+// checkBudget 跨过未告警的阈值时发布告警事件
+func (s *CostService) checkBudget(ctx context.Context, spend *Spend) {
+	var budget Budget
+	err := s.db.WithContext(ctx).First(&budget, "tenant_id = ?", spend.TenantID).Error
+	if err != nil || budget.MonthlyLimit <= 0 {
+		return
+	}
+
+	percent := int(spend.Cost / budget.MonthlyLimit * 100)
+	highest := 0
+	for _, threshold := range budgetThresholds {
+		if percent >= threshold && threshold > spend.AlertedAt {
+			highest = threshold
+		}
+	}
+	if highest == 0 {
+		return
+	}
+
+	s.db.WithContext(ctx).Model(&Spend{}).
+		Where("tenant_id = ? AND month = ?", spend.TenantID, spend.Month).
+		Update("alerted_at", highest)
+	if s.publish != nil {
+		s.publish("ai.budget_alert", map[string]interface{}{
+			"tenantId":     spend.TenantID,
+			"month":        spend.Month,
+			"threshold":    highest,
+			"spent":        spend.Cost,
+			"monthlyLimit": budget.MonthlyLimit,
+		})
+	}
+}
+
+// BudgetStatus 查询租户当月预算与用量
+func (s *CostService) BudgetStatus(ctx context.Context, tenantID string) (map[string]interface{}, error) {
+	month := time.Now().Format("2006-01")
+	status := map[string]interface{}{
+		"tenantId":     tenantID,
+		"month":        month,
+		"monthlyLimit": float64(0),
+		"spent":        float64(0),
+		"percentUsed":  float64(0),
+	}
+
+	var budget Budget
+	if err := s.db.WithContext(ctx).First(&budget, "tenant_id = ?", tenantID).Error; err == nil {
+		status["monthlyLimit"] = budget.MonthlyLimit
+	}
+	var spend Spend
+	if err := s.db.WithContext(ctx).
+		First(&spend, "tenant_id = ? AND month = ?", tenantID, month).Error; err == nil {
+		status["spent"] = spend.Cost
+		status["inputTokens"] = spend.InputTokens
+		status["outputTokens"] = spend.OutputTokens
+		if budget.MonthlyLimit > 0 {
+			status["percentUsed"] = spend.Cost / budget.MonthlyLimit * 100
+		}
+	}
+	return status, nil
+}
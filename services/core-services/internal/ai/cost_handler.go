@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CostHandler AI计费与预算HTTP处理器
+type CostHandler struct {
+	service *CostService
+}
+
+// NewCostHandler 创建计费处理器
+func NewCostHandler(service *CostService) *CostHandler {
+	return &CostHandler{service: service}
+}
+
+// SetupRoutes 注册计费相关路由
+func (h *CostHandler) SetupRoutes(router gin.IRouter) {
+	router.GET("/ai/estimate", h.Estimate)
+	router.POST("/ai/usage", h.RecordUsage)
+	router.GET("/ai/budgets/:tenantId", h.BudgetStatus)
+	router.PUT("/ai/budgets/:tenantId", h.SetBudget)
+	router.PUT("/ai/pricing/:provider", h.SetPricing)
+}
+
+// Estimate 估算prompt的token数与费用
+func (h *CostHandler) Estimate(c *gin.Context) {
+	prompt := c.Query("prompt")
+	if prompt == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prompt is required"})
+		return
+	}
+	expectedOutput, _ := strconv.Atoi(c.DefaultQuery("expectedOutputTokens", "0"))
+	c.JSON(http.StatusOK, h.service.Estimate(c.Query("provider"), prompt, expectedOutput))
+}
+
+// RecordUsage 记录实际用量（计费侧推送）
+func (h *CostHandler) RecordUsage(c *gin.Context) {
+	var req struct {
+		TenantID     string `json:"tenantId" binding:"required"`
+		Provider     string `json:"provider"`
+		InputTokens  int64  `json:"inputTokens"`
+		OutputTokens int64  `json:"outputTokens"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	spend, err := h.service.RecordUsage(c.Request.Context(),
+		req.TenantID, req.Provider, req.InputTokens, req.OutputTokens)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, spend)
+}
+
+// BudgetStatus 查询当月预算与用量
+func (h *CostHandler) BudgetStatus(c *gin.Context) {
+	status, err := h.service.BudgetStatus(c.Request.Context(), c.Param("tenantId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// SetBudget 配置月度预算
+func (h *CostHandler) SetBudget(c *gin.Context) {
+	var req struct {
+		MonthlyLimit float64 `json:"monthlyLimit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, err := h.service.SetBudget(c.Request.Context(), c.Param("tenantId"), req.MonthlyLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, budget)
+}
+
+// SetPricing 配置提供方计价
+func (h *CostHandler) SetPricing(c *gin.Context) {
+	var req struct {
+		InputPerMTokens  float64 `json:"inputPerMTokens"`
+		OutputPerMTokens float64 `json:"outputPerMTokens"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p := Pricing{
+		Provider:         c.Param("provider"),
+		InputPerMTokens:  req.InputPerMTokens,
+		OutputPerMTokens: req.OutputPerMTokens,
+	}
+	if err := h.service.SetPricing(p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
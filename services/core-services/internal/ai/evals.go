@@ -0,0 +1,415 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 用例判定方式
+const (
+	CheckRegex = "regex"
+	// CheckContains 回答包含期望子串
+	CheckContains = "contains"
+	// CheckSimilarity 与期望文本的词面相似度达到阈值
+	//
+	// 无嵌入服务依赖，用分词Jaccard近似语义相似度；对改写
+	// 敏感的用例应改用judge。
+	CheckSimilarity = "similarity"
+	// CheckJudge 由模型按评分标准打分（LLM-as-judge）
+	CheckJudge = "judge"
+)
+
+// 评测运行状态
+const (
+	EvalRunRunning = "running"
+	EvalRunDone    = "done"
+	EvalRunFailed  = "failed"
+)
+
+// similarityThreshold similarity判定通过的默认阈值
+const similarityThreshold = 0.6
+
+// judgePassScore judge打分（0-10）判定通过的最低分
+const judgePassScore = 7.0
+
+// EvalSuite 评测套件
+type EvalSuite struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name        string     `json:"name" gorm:"type:varchar(128);uniqueIndex;not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	Cases       []EvalCase `json:"cases" gorm:"foreignKey:SuiteID"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (EvalSuite) TableName() string {
+	return "cs_ai_eval_suites"
+}
+
+// EvalCase 评测用例
+type EvalCase struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	SuiteID string `json:"suiteId" gorm:"type:varchar(36);index;not null"`
+	// Prompt 发送给模型的完整提示词
+	Prompt string `json:"prompt" gorm:"type:text;not null"`
+	// CheckType 判定方式：regex/contains/similarity/judge
+	CheckType string `json:"checkType" gorm:"type:varchar(16);not null"`
+	// Expected 判定依据：正则、子串、参考答案或评分标准
+	Expected  string    `json:"expected" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (EvalCase) TableName() string {
+	return "cs_ai_eval_cases"
+}
+
+// EvalRun 一次评测运行
+type EvalRun struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	SuiteID string `json:"suiteId" gorm:"type:varchar(36);index;not null"`
+	// Provider 被评测的提供方/模型
+	Provider string `json:"provider" gorm:"type:varchar(64);not null"`
+	// PromptVersion 调用方标记的提示词版本，用于对比
+	PromptVersion string     `json:"promptVersion" gorm:"type:varchar(64)"`
+	Status        string     `json:"status" gorm:"type:varchar(16);index;not null"`
+	Passed        int        `json:"passed"`
+	Failed        int        `json:"failed"`
+	Score         float64    `json:"score"` // 用例平均分（0-1）
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"index"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (EvalRun) TableName() string {
+	return "cs_ai_eval_runs"
+}
+
+// EvalResult 单个用例的评测结果
+type EvalResult struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RunID  string `json:"runId" gorm:"type:varchar(36);index;not null"`
+	CaseID string `json:"caseId" gorm:"type:varchar(36);index;not null"`
+	Answer string `json:"answer" gorm:"type:text"`
+	Passed bool   `json:"passed"`
+	// Score 0-1归一化得分；regex/contains为0或1
+	Score     float64   `json:"score"`
+	Detail    string    `json:"detail" gorm:"type:text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (EvalResult) TableName() string {
+	return "cs_ai_eval_results"
+}
+
+// EvalService 提示词评测服务
+//
+// 提示词或提供方变更前先跑套件，对比运行结果再发布。
+type EvalService struct {
+	db        *gorm.DB
+	completer Completer
+}
+
+// NewEvalService 创建评测服务
+func NewEvalService(db *gorm.DB, completer Completer) (*EvalService, error) {
+	if err := db.AutoMigrate(&EvalSuite{}, &EvalCase{}, &EvalRun{}, &EvalResult{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate eval tables: %w", err)
+	}
+	return &EvalService{db: db, completer: completer}, nil
+}
+
+// EvalCaseRequest 创建套件时的用例参数
+type EvalCaseRequest struct {
+	Prompt    string `json:"prompt" binding:"required"`
+	CheckType string `json:"checkType" binding:"required"`
+	Expected  string `json:"expected" binding:"required"`
+}
+
+// CreateSuite 创建评测套件
+func (s *EvalService) CreateSuite(ctx context.Context, name, description string, cases []EvalCaseRequest) (*EvalSuite, error) {
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("suite must have at least one case")
+	}
+	suite := &EvalSuite{
+		ID:          uuid.NewString(),
+		Name:        name,
+		Description: description,
+	}
+	for _, c := range cases {
+		switch c.CheckType {
+		case CheckRegex:
+			if _, err := regexp.Compile(c.Expected); err != nil {
+				return nil, fmt.Errorf("invalid regex in case: %w", err)
+			}
+		case CheckContains, CheckSimilarity, CheckJudge:
+		default:
+			return nil, fmt.Errorf("invalid check type: %s", c.CheckType)
+		}
+		suite.Cases = append(suite.Cases, EvalCase{
+			ID:        uuid.NewString(),
+			SuiteID:   suite.ID,
+			Prompt:    c.Prompt,
+			CheckType: c.CheckType,
+			Expected:  c.Expected,
+		})
+	}
+	if err := s.db.WithContext(ctx).Create(suite).Error; err != nil {
+		return nil, fmt.Errorf("failed to create eval suite: %w", err)
+	}
+	return suite, nil
+}
+
+// ListSuites 列出套件（含用例）
+func (s *EvalService) ListSuites(ctx context.Context) ([]*EvalSuite, error) {
+	var suites []*EvalSuite
+	err := s.db.WithContext(ctx).Preload("Cases").Order("created_at ASC").Find(&suites).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eval suites: %w", err)
+	}
+	return suites, nil
+}
+
+// StartRun 启动一次评测运行，异步执行
+func (s *EvalService) StartRun(ctx context.Context, suiteID, provider, promptVersion string) (*EvalRun, error) {
+	var suite EvalSuite
+	err := s.db.WithContext(ctx).Preload("Cases").First(&suite, "id = ?", suiteID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("eval suite not found: %s", suiteID)
+		}
+		return nil, fmt.Errorf("failed to get eval suite: %w", err)
+	}
+	if provider == "" {
+		provider = defaultProvider
+	}
+
+	run := &EvalRun{
+		ID:            uuid.NewString(),
+		SuiteID:       suite.ID,
+		Provider:      provider,
+		PromptVersion: promptVersion,
+		Status:        EvalRunRunning,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to create eval run: %w", err)
+	}
+	go s.execute(run, suite.Cases)
+	return run, nil
+}
+
+// execute 逐用例调用模型并判分
+func (s *EvalService) execute(run *EvalRun, cases []EvalCase) {
+	ctx := context.Background()
+	var total float64
+	for _, c := range cases {
+		result := &EvalResult{
+			ID:     uuid.NewString(),
+			RunID:  run.ID,
+			CaseID: c.ID,
+		}
+		answer, err := s.completer.Complete(ctx, run.Provider, c.Prompt)
+		if err != nil {
+			run.Status = EvalRunFailed
+			run.LastError = err.Error()
+			now := time.Now()
+			run.CompletedAt = &now
+			s.db.Save(run)
+			return
+		}
+		result.Answer = answer
+		result.Passed, result.Score, result.Detail = s.grade(ctx, run.Provider, c, answer)
+		if result.Passed {
+			run.Passed++
+		} else {
+			run.Failed++
+		}
+		total += result.Score
+		s.db.Create(result)
+	}
+
+	if len(cases) > 0 {
+		run.Score = total / float64(len(cases))
+	}
+	run.Status = EvalRunDone
+	now := time.Now()
+	run.CompletedAt = &now
+	s.db.Save(run)
+}
+
+// grade 按用例判定方式打分
+func (s *EvalService) grade(ctx context.Context, provider string, c EvalCase, answer string) (bool, float64, string) {
+	switch c.CheckType {
+	case CheckRegex:
+		re, err := regexp.Compile(c.Expected)
+		if err != nil {
+			return false, 0, fmt.Sprintf("invalid regex: %v", err)
+		}
+		if re.MatchString(answer) {
+			return true, 1, "regex matched"
+		}
+		return false, 0, "regex did not match"
+	case CheckContains:
+		if strings.Contains(answer, c.Expected) {
+			return true, 1, "expected substring found"
+		}
+		return false, 0, "expected substring not found"
+	case CheckSimilarity:
+		score := jaccardSimilarity(answer, c.Expected)
+		detail := fmt.Sprintf("similarity %.2f (threshold %.2f)", score, similarityThreshold)
+		return score >= similarityThreshold, score, detail
+	case CheckJudge:
+		return s.judge(ctx, provider, c, answer)
+	default:
+		return false, 0, fmt.Sprintf("unknown check type: %s", c.CheckType)
+	}
+}
+
+// judge 让模型按评分标准给回答打0-10分
+func (s *EvalService) judge(ctx context.Context, provider string, c EvalCase, answer string) (bool, float64, string) {
+	prompt := fmt.Sprintf(
+		"你是评测员。按以下标准给回答打分，只输出0到10之间的数字。\n\n评分标准：%s\n\n被评回答：\n%s",
+		c.Expected, answer)
+	verdict, err := s.completer.Complete(ctx, provider, prompt)
+	if err != nil {
+		return false, 0, fmt.Sprintf("judge call failed: %v", err)
+	}
+	score, err := strconv.ParseFloat(strings.TrimSpace(verdict), 64)
+	if err != nil || score < 0 || score > 10 {
+		return false, 0, fmt.Sprintf("judge returned non-numeric verdict: %q", strings.TrimSpace(verdict))
+	}
+	return score >= judgePassScore, score / 10, fmt.Sprintf("judge score %.1f/10", score)
+}
+
+// GetRun 读取运行及结果
+func (s *EvalService) GetRun(ctx context.Context, runID string) (*EvalRun, []*EvalResult, error) {
+	var run EvalRun
+	err := s.db.WithContext(ctx).First(&run, "id = ?", runID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, fmt.Errorf("eval run not found: %s", runID)
+		}
+		return nil, nil, fmt.Errorf("failed to get eval run: %w", err)
+	}
+	var results []*EvalResult
+	err = s.db.WithContext(ctx).Where("run_id = ?", runID).Order("created_at ASC").Find(&results).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get eval results: %w", err)
+	}
+	return &run, results, nil
+}
+
+// ListRuns 列出套件的运行记录
+func (s *EvalService) ListRuns(ctx context.Context, suiteID string) ([]*EvalRun, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(100)
+	if suiteID != "" {
+		query = query.Where("suite_id = ?", suiteID)
+	}
+	var runs []*EvalRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list eval runs: %w", err)
+	}
+	return runs, nil
+}
+
+// CaseComparison 同一用例在两次运行中的结果对比
+type CaseComparison struct {
+	CaseID         string  `json:"caseId"`
+	BaseScore      float64 `json:"baseScore"`
+	CandidateScore float64 `json:"candidateScore"`
+	// Change improved/regressed/unchanged
+	Change string `json:"change"`
+}
+
+// RunComparison 两次运行的整体对比
+type RunComparison struct {
+	Base      *EvalRun          `json:"base"`
+	Candidate *EvalRun          `json:"candidate"`
+	Improved  int               `json:"improved"`
+	Regressed int               `json:"regressed"`
+	Cases     []*CaseComparison `json:"cases"`
+}
+
+// CompareRuns 按用例对比两次运行，用于变更发布前验证
+func (s *EvalService) CompareRuns(ctx context.Context, baseID, candidateID string) (*RunComparison, error) {
+	base, baseResults, err := s.GetRun(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	candidate, candidateResults, err := s.GetRun(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	if base.SuiteID != candidate.SuiteID {
+		return nil, fmt.Errorf("runs belong to different suites")
+	}
+
+	baseByCase := make(map[string]*EvalResult, len(baseResults))
+	for _, r := range baseResults {
+		baseByCase[r.CaseID] = r
+	}
+	comparison := &RunComparison{Base: base, Candidate: candidate}
+	for _, r := range candidateResults {
+		prev, ok := baseByCase[r.CaseID]
+		if !ok {
+			continue
+		}
+		cc := &CaseComparison{
+			CaseID:         r.CaseID,
+			BaseScore:      prev.Score,
+			CandidateScore: r.Score,
+			Change:         "unchanged",
+		}
+		if r.Score > prev.Score {
+			cc.Change = "improved"
+			comparison.Improved++
+		} else if r.Score < prev.Score {
+			cc.Change = "regressed"
+			comparison.Regressed++
+		}
+		comparison.Cases = append(comparison.Cases, cc)
+	}
+	return comparison, nil
+}
+
+// jaccardSimilarity 分词集合的Jaccard相似度
+//
+// 中文按单字、其他按空白分词；作为无嵌入依赖的相似度近似。
+func jaccardSimilarity(a, b string) float64 {
+	setA := tokenSet(a)
+	setB := tokenSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+	intersection := 0
+	for token := range setA {
+		if setB[token] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	return float64(intersection) / float64(union)
+}
+
+// tokenSet 切分为去重词元集合
+func tokenSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		for _, r := range field {
+			if r >= 0x4E00 && r <= 0x9FFF {
+				set[string(r)] = true
+			}
+		}
+		set[field] = true
+	}
+	return set
+}
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EvalHandler 评测HTTP处理器
+type EvalHandler struct {
+	service *EvalService
+}
+
+// NewEvalHandler 创建评测处理器
+func NewEvalHandler(service *EvalService) *EvalHandler {
+	return &EvalHandler{service: service}
+}
+
+// SetupRoutes 注册评测相关路由
+func (h *EvalHandler) SetupRoutes(router gin.IRouter) {
+	router.POST("/ai/evals/suites", h.CreateSuite)
+	router.GET("/ai/evals/suites", h.ListSuites)
+	router.POST("/ai/evals/suites/:suiteId/runs", h.StartRun)
+	router.GET("/ai/evals/runs", h.ListRuns)
+	router.GET("/ai/evals/runs/:runId", h.GetRun)
+	router.GET("/ai/evals/compare", h.Compare)
+}
+
+// CreateSuite 创建评测套件
+func (h *EvalHandler) CreateSuite(c *gin.Context) {
+	var req struct {
+		Name        string            `json:"name" binding:"required"`
+		Description string            `json:"description"`
+		Cases       []EvalCaseRequest `json:"cases" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	suite, err := h.service.CreateSuite(c.Request.Context(), req.Name, req.Description, req.Cases)
+	if err != nil {
+		c.JSON(statusForEvalError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, suite)
+}
+
+// ListSuites 列出评测套件
+func (h *EvalHandler) ListSuites(c *gin.Context) {
+	suites, err := h.service.ListSuites(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suites": suites})
+}
+
+// StartRun 启动评测运行
+func (h *EvalHandler) StartRun(c *gin.Context) {
+	var req struct {
+		Provider      string `json:"provider"`
+		PromptVersion string `json:"promptVersion"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	run, err := h.service.StartRun(c.Request.Context(), c.Param("suiteId"), req.Provider, req.PromptVersion)
+	if err != nil {
+		c.JSON(statusForEvalError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListRuns 列出运行记录
+func (h *EvalHandler) ListRuns(c *gin.Context) {
+	runs, err := h.service.ListRuns(c.Request.Context(), c.Query("suiteId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// GetRun 读取运行及逐用例结果
+func (h *EvalHandler) GetRun(c *gin.Context) {
+	run, results, err := h.service.GetRun(c.Request.Context(), c.Param("runId"))
+	if err != nil {
+		c.JSON(statusForEvalError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"run": run, "results": results})
+}
+
+// Compare 对比两次运行
+func (h *EvalHandler) Compare(c *gin.Context) {
+	baseID := c.Query("base")
+	candidateID := c.Query("candidate")
+	if baseID == "" || candidateID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base and candidate are required"})
+		return
+	}
+	comparison, err := h.service.CompareRuns(c.Request.Context(), baseID, candidateID)
+	if err != nil {
+		c.JSON(statusForEvalError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// statusForEvalError 根据错误内容映射HTTP状态码
+func statusForEvalError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "must have"),
+		strings.Contains(msg, "different suites"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,358 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ConsentPurposeTraining 训练数据采集的同意用途标识
+const ConsentPurposeTraining = "ai_training"
+
+// 训练候选状态
+const (
+	CandidatePending  = "pending"
+	CandidateApproved = "approved"
+	CandidateRejected = "rejected"
+)
+
+// 数据集状态
+const (
+	DatasetDraft    = "draft"
+	DatasetExported = "exported"
+)
+
+// ConsentChecker 判断用户是否同意其内容用于模型训练
+//
+// 由consent模块实现，这里只依赖接口以避免反向耦合。
+type ConsentChecker interface {
+	Allows(ctx context.Context, userID, purpose string) (bool, error)
+}
+
+// TrainingCandidate 训练候选样本
+//
+// 内容哈希唯一索引在入库时天然去重：同一对话被多次标记
+// 只保留一条。
+type TrainingCandidate struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// Source 来源类型：chat/task
+	Source   string `json:"source" gorm:"type:varchar(16);index;not null"`
+	SourceID string `json:"sourceId" gorm:"type:varchar(36);index"`
+	// UserID 内容归属用户，标记时校验其训练用途同意
+	UserID      string    `json:"userId" gorm:"type:varchar(36);index;not null"`
+	Prompt      string    `json:"prompt" gorm:"type:text;not null"`
+	Completion  string    `json:"completion" gorm:"type:text;not null"`
+	Status      string    `json:"status" gorm:"type:varchar(16);index;not null"`
+	ContentHash string    `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (TrainingCandidate) TableName() string {
+	return "cs_ai_training_candidates"
+}
+
+// Dataset 训练数据集版本
+//
+// 同名数据集按Version递增，导出后冻结；模型溯源记录指向
+// 具体版本而非名称。
+type Dataset struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name      string     `json:"name" gorm:"type:varchar(128);uniqueIndex:idx_dataset_version;not null"`
+	Version   int        `json:"version" gorm:"uniqueIndex:idx_dataset_version;not null"`
+	Status    string     `json:"status" gorm:"type:varchar(16);index;not null"`
+	ItemCount int        `json:"itemCount"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	FrozenAt  *time.Time `json:"frozenAt,omitempty"`
+}
+
+// TableName 指定表名
+func (Dataset) TableName() string {
+	return "cs_ai_datasets"
+}
+
+// DatasetItem 数据集与候选样本的关联
+type DatasetItem struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	DatasetID   string    `json:"datasetId" gorm:"type:varchar(36);uniqueIndex:idx_dataset_item;not null"`
+	CandidateID string    `json:"candidateId" gorm:"type:varchar(36);uniqueIndex:idx_dataset_item;not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (DatasetItem) TableName() string {
+	return "cs_ai_dataset_items"
+}
+
+// ModelLineage 已部署模型与训练数据集版本的溯源关系
+type ModelLineage struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ModelName string    `json:"modelName" gorm:"type:varchar(128);index;not null"`
+	Provider  string    `json:"provider" gorm:"type:varchar(64);not null"`
+	DatasetID string    `json:"datasetId" gorm:"type:varchar(36);index;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ModelLineage) TableName() string {
+	return "cs_ai_model_lineage"
+}
+
+// FinetuneService 微调数据集整备服务
+type FinetuneService struct {
+	db *gorm.DB
+	// consent 训练用途同意检查，可为nil（视为全部拒绝）
+	consent ConsentChecker
+}
+
+// NewFinetuneService 创建微调数据服务
+func NewFinetuneService(db *gorm.DB) (*FinetuneService, error) {
+	err := db.AutoMigrate(&TrainingCandidate{}, &Dataset{}, &DatasetItem{}, &ModelLineage{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate finetune tables: %w", err)
+	}
+	return &FinetuneService{db: db}, nil
+}
+
+// SetConsentChecker 接入同意检查，标记候选前校验训练用途授权
+func (s *FinetuneService) SetConsentChecker(checker ConsentChecker) {
+	s.consent = checker
+}
+
+// TagCandidateRequest 标记训练候选的参数
+type TagCandidateRequest struct {
+	Source     string `json:"source" binding:"required"`
+	SourceID   string `json:"sourceId"`
+	UserID     string `json:"userId" binding:"required"`
+	Prompt     string `json:"prompt" binding:"required"`
+	Completion string `json:"completion" binding:"required"`
+}
+
+// TagCandidate 把一段交互标记为训练候选
+//
+// 未同意训练用途的用户内容直接拒绝入库，而非入库后过滤，
+// 避免撤回同意前的数据残留。
+func (s *FinetuneService) TagCandidate(ctx context.Context, req *TagCandidateRequest) (*TrainingCandidate, error) {
+	if req.Source != "chat" && req.Source != "task" {
+		return nil, fmt.Errorf("invalid source: %s", req.Source)
+	}
+	if s.consent == nil {
+		return nil, fmt.Errorf("consent required: training consent checker is not configured")
+	}
+	allowed, err := s.consent.Allows(ctx, req.UserID, ConsentPurposeTraining)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check training consent: %w", err)
+	}
+	if !allowed {
+		return nil, fmt.Errorf("consent required: user %s has not consented to training use", req.UserID)
+	}
+
+	hash := sha256.Sum256([]byte(req.Prompt + "\x00" + req.Completion))
+	candidate := &TrainingCandidate{
+		ID:          uuid.NewString(),
+		Source:      req.Source,
+		SourceID:    req.SourceID,
+		UserID:      req.UserID,
+		Prompt:      req.Prompt,
+		Completion:  req.Completion,
+		Status:      CandidatePending,
+		ContentHash: hex.EncodeToString(hash[:]),
+	}
+	err = s.db.WithContext(ctx).Create(candidate).Error
+	if err != nil {
+		// 唯一索引冲突即重复内容，返回已有记录
+		var existing TrainingCandidate
+		findErr := s.db.WithContext(ctx).
+			First(&existing, "content_hash = ?", candidate.ContentHash).Error
+		if findErr == nil {
+			return &existing, nil
+		}
+		return nil, fmt.Errorf("failed to create training candidate: %w", err)
+	}
+	return candidate, nil
+}
+
+// ListCandidates 按状态列出候选
+func (s *FinetuneService) ListCandidates(ctx context.Context, status string, limit int) ([]*TrainingCandidate, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var candidates []*TrainingCandidate
+	if err := query.Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// ReviewCandidate 审核候选（approved/rejected）
+func (s *FinetuneService) ReviewCandidate(ctx context.Context, candidateID, status string) error {
+	if status != CandidateApproved && status != CandidateRejected {
+		return fmt.Errorf("invalid review status: %s", status)
+	}
+	result := s.db.WithContext(ctx).Model(&TrainingCandidate{}).
+		Where("id = ?", candidateID).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("failed to review candidate: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("candidate not found: %s", candidateID)
+	}
+	return nil
+}
+
+// BuildDataset 把当前已审核通过的候选固化为数据集新版本
+func (s *FinetuneService) BuildDataset(ctx context.Context, name string) (*Dataset, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+	dataset := &Dataset{
+		ID:     uuid.NewString(),
+		Name:   name,
+		Status: DatasetDraft,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest Dataset
+		err := tx.Where("name = ?", name).Order("version DESC").First(&latest).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to resolve dataset version: %w", err)
+		}
+		dataset.Version = latest.Version + 1
+
+		var approved []*TrainingCandidate
+		if err := tx.Where("status = ?", CandidateApproved).Find(&approved).Error; err != nil {
+			return fmt.Errorf("failed to load approved candidates: %w", err)
+		}
+		if len(approved) == 0 {
+			return fmt.Errorf("no approved candidates to build dataset from")
+		}
+		dataset.ItemCount = len(approved)
+		if err := tx.Create(dataset).Error; err != nil {
+			return fmt.Errorf("failed to create dataset: %w", err)
+		}
+		items := make([]DatasetItem, 0, len(approved))
+		for _, candidate := range approved {
+			items = append(items, DatasetItem{
+				ID:          uuid.NewString(),
+				DatasetID:   dataset.ID,
+				CandidateID: candidate.ID,
+			})
+		}
+		return tx.CreateInBatches(items, 200).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// ListDatasets 列出数据集版本
+func (s *FinetuneService) ListDatasets(ctx context.Context, name string) ([]*Dataset, error) {
+	query := s.db.WithContext(ctx).Order("name ASC, version DESC")
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	var datasets []*Dataset
+	if err := query.Find(&datasets).Error; err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+	return datasets, nil
+}
+
+// ExportJSONL 把数据集导出为提供方微调JSONL格式
+//
+// 每行 {"messages":[{"role":"user",...},{"role":"assistant",...}]}，
+// 首次导出将数据集置为exported并冻结。
+func (s *FinetuneService) ExportJSONL(ctx context.Context, datasetID string, yield func(line []byte) error) error {
+	var dataset Dataset
+	err := s.db.WithContext(ctx).First(&dataset, "id = ?", datasetID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("dataset not found: %s", datasetID)
+		}
+		return fmt.Errorf("failed to get dataset: %w", err)
+	}
+
+	var candidates []*TrainingCandidate
+	err = s.db.WithContext(ctx).
+		Joins("JOIN cs_ai_dataset_items ON cs_ai_dataset_items.candidate_id = cs_ai_training_candidates.id").
+		Where("cs_ai_dataset_items.dataset_id = ?", datasetID).
+		Find(&candidates).Error
+	if err != nil {
+		return fmt.Errorf("failed to load dataset items: %w", err)
+	}
+
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	for _, candidate := range candidates {
+		line, err := json.Marshal(map[string][]message{
+			"messages": {
+				{Role: "user", Content: candidate.Prompt},
+				{Role: "assistant", Content: candidate.Completion},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal dataset line: %w", err)
+		}
+		if err := yield(line); err != nil {
+			return err
+		}
+	}
+
+	if dataset.Status != DatasetExported {
+		now := time.Now()
+		s.db.WithContext(ctx).Model(&dataset).
+			Updates(map[string]interface{}{"status": DatasetExported, "frozen_at": now})
+	}
+	return nil
+}
+
+// RecordLineage 记录模型由哪个数据集版本训练
+func (s *FinetuneService) RecordLineage(ctx context.Context, modelName, provider, datasetID string) (*ModelLineage, error) {
+	var dataset Dataset
+	err := s.db.WithContext(ctx).First(&dataset, "id = ?", datasetID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("dataset not found: %s", datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset: %w", err)
+	}
+	lineage := &ModelLineage{
+		ID:        uuid.NewString(),
+		ModelName: modelName,
+		Provider:  provider,
+		DatasetID: dataset.ID,
+	}
+	if err := s.db.WithContext(ctx).Create(lineage).Error; err != nil {
+		return nil, fmt.Errorf("failed to record model lineage: %w", err)
+	}
+	return lineage, nil
+}
+
+// Lineage 查询模型的训练数据溯源
+func (s *FinetuneService) Lineage(ctx context.Context, modelName string) ([]*ModelLineage, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	if modelName != "" {
+		query = query.Where("model_name = ?", modelName)
+	}
+	var records []*ModelLineage
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list model lineage: %w", err)
+	}
+	return records, nil
+}
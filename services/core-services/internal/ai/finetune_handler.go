@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FinetuneHandler 微调数据HTTP处理器
+type FinetuneHandler struct {
+	service *FinetuneService
+}
+
+// NewFinetuneHandler 创建微调数据处理器
+func NewFinetuneHandler(service *FinetuneService) *FinetuneHandler {
+	return &FinetuneHandler{service: service}
+}
+
+// SetupRoutes 注册微调数据相关路由
+func (h *FinetuneHandler) SetupRoutes(router gin.IRouter) {
+	router.POST("/ai/training/candidates", h.TagCandidate)
+	router.GET("/ai/training/candidates", h.ListCandidates)
+	router.PUT("/ai/training/candidates/:candidateId/review", h.ReviewCandidate)
+	router.POST("/ai/training/datasets", h.BuildDataset)
+	router.GET("/ai/training/datasets", h.ListDatasets)
+	router.GET("/ai/training/datasets/:datasetId/export", h.Export)
+	router.POST("/ai/training/lineage", h.RecordLineage)
+	router.GET("/ai/training/lineage", h.Lineage)
+}
+
+// TagCandidate 标记训练候选
+func (h *FinetuneHandler) TagCandidate(c *gin.Context) {
+	var req TagCandidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	candidate, err := h.service.TagCandidate(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForFinetuneError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, candidate)
+}
+
+// ListCandidates 列出训练候选
+func (h *FinetuneHandler) ListCandidates(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	candidates, err := h.service.ListCandidates(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"candidates": candidates})
+}
+
+// ReviewCandidate 审核训练候选
+func (h *FinetuneHandler) ReviewCandidate(c *gin.Context) {
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.ReviewCandidate(c.Request.Context(), c.Param("candidateId"), req.Status); err != nil {
+		c.JSON(statusForFinetuneError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": req.Status})
+}
+
+// BuildDataset 固化数据集新版本
+func (h *FinetuneHandler) BuildDataset(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dataset, err := h.service.BuildDataset(c.Request.Context(), req.Name)
+	if err != nil {
+		c.JSON(statusForFinetuneError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, dataset)
+}
+
+// ListDatasets 列出数据集版本
+func (h *FinetuneHandler) ListDatasets(c *gin.Context) {
+	datasets, err := h.service.ListDatasets(c.Request.Context(), c.Query("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"datasets": datasets})
+}
+
+// Export 以JSONL流式导出数据集
+func (h *FinetuneHandler) Export(c *gin.Context) {
+	c.Header("Content-Type", "application/jsonl; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="dataset.jsonl"`)
+	err := h.service.ExportJSONL(c.Request.Context(), c.Param("datasetId"), func(line []byte) error {
+		if _, err := c.Writer.Write(line); err != nil {
+			return err
+		}
+		_, err := c.Writer.Write([]byte("\n"))
+		return err
+	})
+	if err != nil {
+		// 尚未写出任何行时才可能返回结构化错误
+		if !c.Writer.Written() {
+			c.JSON(statusForFinetuneError(err), gin.H{"error": err.Error()})
+		}
+		return
+	}
+}
+
+// RecordLineage 记录模型训练溯源
+func (h *FinetuneHandler) RecordLineage(c *gin.Context) {
+	var req struct {
+		ModelName string `json:"modelName" binding:"required"`
+		Provider  string `json:"provider" binding:"required"`
+		DatasetID string `json:"datasetId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lineage, err := h.service.RecordLineage(c.Request.Context(), req.ModelName, req.Provider, req.DatasetID)
+	if err != nil {
+		c.JSON(statusForFinetuneError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, lineage)
+}
+
+// Lineage 查询模型训练溯源
+func (h *FinetuneHandler) Lineage(c *gin.Context) {
+	records, err := h.service.Lineage(c.Request.Context(), c.Query("modelName"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"lineage": records})
+}
+
+// statusForFinetuneError 根据错误内容映射HTTP状态码
+func statusForFinetuneError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "consent required"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "required"),
+		strings.Contains(msg, "no approved"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,196 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 护栏触发类型
+const (
+	GuardKindInjection = "injection"
+	GuardKindSecret    = "secret"
+	GuardKindSchema    = "schema"
+)
+
+// maxRepairAttempts JSON输出不合法时的自动修复重试次数
+const maxRepairAttempts = 2
+
+// injectionPatterns 提示词注入特征
+//
+// 特征匹配只能拦截已知套路，目的在于阻断低成本批量攻击并
+// 留下审计记录，不能替代输出侧校验。
+var injectionPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"ignore_instructions", regexp.MustCompile(`(?i)(ignore|disregard|forget)\s+(all\s+|your\s+|the\s+)?(previous|prior|above|earlier)\s+(instructions|prompts|rules)`)},
+	{"reveal_system_prompt", regexp.MustCompile(`(?i)(reveal|show|print|repeat|output)\s+(your\s+|the\s+)?(system\s+prompt|initial\s+instructions|hidden\s+rules)`)},
+	{"role_override", regexp.MustCompile(`(?i)you\s+are\s+(now|no\s+longer)\s`)},
+	{"developer_mode", regexp.MustCompile(`(?i)(developer|dan|jailbreak|god)\s+mode`)},
+	{"ignore_instructions_zh", regexp.MustCompile(`忽略(之前|以上|上面|先前)的?(所有)?(指令|提示|规则|设定)`)},
+	{"reveal_system_prompt_zh", regexp.MustCompile(`(输出|显示|重复|泄露)(你的)?(系统提示词?|初始指令)`)},
+	{"role_override_zh", regexp.MustCompile(`(现在|从现在起)你(是|扮演|不再是)`)},
+}
+
+// secretPatterns 输入中需要脱敏的凭证特征
+var secretPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"aws_access_key", regexp.MustCompile(`AKIA[0-9A-Z]{16}`)},
+	{"private_key_block", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"bearer_token", regexp.MustCompile(`(?i)bearer\s+[a-z0-9._~+/-]{20,}=*`)},
+	{"api_key_assignment", regexp.MustCompile(`(?i)(api[_-]?key|secret[_-]?key|access[_-]?token|password)\s*[:=]\s*['"]?[a-z0-9._/+-]{12,}['"]?`)},
+	{"jwt", regexp.MustCompile(`eyJ[a-zA-Z0-9_-]{10,}\.[a-zA-Z0-9_-]{10,}\.[a-zA-Z0-9_-]{10,}`)},
+}
+
+// GuardrailEvent 护栏触发记录，供安全复查
+//
+// 只保留触发片段的截断副本，命中secret类时片段本身已脱敏。
+type GuardrailEvent struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Kind      string    `json:"kind" gorm:"type:varchar(16);index;not null"`
+	Pattern   string    `json:"pattern" gorm:"type:varchar(64);not null"`
+	Snippet   string    `json:"snippet" gorm:"type:varchar(255)"`
+	Provider  string    `json:"provider" gorm:"type:varchar(64)"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (GuardrailEvent) TableName() string {
+	return "cs_ai_guardrail_events"
+}
+
+// Guard AI输入输出护栏
+type Guard struct {
+	db *gorm.DB
+}
+
+// NewGuard 创建护栏，迁移触发记录表
+func NewGuard(db *gorm.DB) (*Guard, error) {
+	if err := db.AutoMigrate(&GuardrailEvent{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate guardrail tables: %w", err)
+	}
+	return &Guard{db: db}, nil
+}
+
+// CheckInjection 扫描输入中的提示词注入特征，返回命中的特征名
+func (g *Guard) CheckInjection(ctx context.Context, provider, input string) []string {
+	var matched []string
+	for _, p := range injectionPatterns {
+		if loc := p.re.FindStringIndex(input); loc != nil {
+			matched = append(matched, p.name)
+			g.record(ctx, GuardKindInjection, p.name, provider, snippet(input, loc))
+		}
+	}
+	return matched
+}
+
+// StripSecrets 脱敏输入中的凭证，返回处理后的输入与命中次数
+func (g *Guard) StripSecrets(ctx context.Context, provider, input string) (string, int) {
+	total := 0
+	for _, p := range secretPatterns {
+		count := 0
+		input = p.re.ReplaceAllStringFunc(input, func(string) string {
+			count++
+			return "[REDACTED:" + p.name + "]"
+		})
+		if count > 0 {
+			total += count
+			g.record(ctx, GuardKindSecret, p.name, provider,
+				fmt.Sprintf("%d occurrence(s) redacted", count))
+		}
+	}
+	return input, total
+}
+
+// ValidateJSON 校验模型输出为合法JSON对象且包含必需字段
+func (g *Guard) ValidateJSON(output string, requiredFields []string) error {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(ExtractJSON(output)), &parsed); err != nil {
+		return fmt.Errorf("output is not a JSON object: %w", err)
+	}
+	var missing []string
+	for _, field := range requiredFields {
+		if _, ok := parsed[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("output is missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// RecordSchemaFailure 记录输出schema校验失败
+func (g *Guard) RecordSchemaFailure(ctx context.Context, provider string, err error) {
+	g.record(ctx, GuardKindSchema, "json_schema", provider, err.Error())
+}
+
+// ListEvents 按时间倒序列出最近的触发记录
+func (g *Guard) ListEvents(ctx context.Context, kind string, limit int) ([]*GuardrailEvent, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	query := g.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	var guardEvents []*GuardrailEvent
+	if err := query.Find(&guardEvents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list guardrail events: %w", err)
+	}
+	return guardEvents, nil
+}
+
+// record 持久化触发记录，失败不阻断主流程
+func (g *Guard) record(ctx context.Context, kind, pattern, provider, snippetText string) {
+	g.db.WithContext(ctx).Create(&GuardrailEvent{
+		ID:       uuid.NewString(),
+		Kind:     kind,
+		Pattern:  pattern,
+		Snippet:  truncateSnippet(snippetText),
+		Provider: provider,
+	})
+}
+
+// ExtractJSON 去掉模型常见的markdown代码围栏，提取JSON主体
+func ExtractJSON(output string) string {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "```") {
+		output = strings.TrimPrefix(output, "```json")
+		output = strings.TrimPrefix(output, "```")
+		if idx := strings.LastIndex(output, "```"); idx >= 0 {
+			output = output[:idx]
+		}
+	}
+	return strings.TrimSpace(output)
+}
+
+// snippet 取命中位置附近的上下文片段
+func snippet(input string, loc []int) string {
+	start := loc[0] - 40
+	if start < 0 {
+		start = 0
+	}
+	end := loc[1] + 40
+	if end > len(input) {
+		end = len(input)
+	}
+	return strings.ToValidUTF8(input[start:end], "")
+}
+
+// truncateSnippet 限制片段长度以适配列宽
+func truncateSnippet(s string) string {
+	if len(s) > 255 {
+		return s[:255]
+	}
+	return s
+}
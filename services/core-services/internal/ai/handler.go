@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler AI处理HTTP处理器
+type Handler struct {
+	service *Service
+	// guard 非nil时注册护栏触发记录查询路由
+	guard *Guard
+}
+
+// NewHandler 创建AI处理器
+func NewHandler(service *Service, guard *Guard) *Handler {
+	return &Handler{service: service, guard: guard}
+}
+
+// SetupRoutes 注册AI处理相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/ai/process", h.Process)
+	router.GET("/ai/providers", h.Providers)
+	router.PUT("/ai/providers/:name/limits", h.SetProviderLimits)
+	if h.guard != nil {
+		router.GET("/ai/guardrail-events", h.GuardrailEvents)
+	}
+}
+
+// Process 处理可能超长的输入
+func (h *Handler) Process(c *gin.Context) {
+	var req ProcessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Process(c.Request.Context(), req)
+	if err != nil {
+		status := http.StatusBadGateway
+		if strings.Contains(err.Error(), "chunking disabled") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		if strings.Contains(err.Error(), "injection detected") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Providers 列出提供方限制配置
+func (h *Handler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.service.Providers()})
+}
+
+// GuardrailEvents 列出护栏触发记录（安全复查）
+func (h *Handler) GuardrailEvents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	guardEvents, err := h.guard.ListEvents(c.Request.Context(), c.Query("kind"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": guardEvents})
+}
+
+// SetProviderLimits 配置提供方的上下文限制
+func (h *Handler) SetProviderLimits(c *gin.Context) {
+	var req struct {
+		MaxInputTokens int `json:"maxInputTokens" binding:"required"`
+		ChunkTokens    int `json:"chunkTokens"`
+		OverlapTokens  int `json:"overlapTokens"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p := Provider{
+		Name:           c.Param("name"),
+		MaxInputTokens: req.MaxInputTokens,
+		ChunkTokens:    req.ChunkTokens,
+		OverlapTokens:  req.OverlapTokens,
+	}
+	if err := h.service.SetProvider(p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
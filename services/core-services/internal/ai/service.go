@@ -0,0 +1,335 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 处理策略
+const (
+	StrategyDirect    = "direct"
+	StrategyMapReduce = "map_reduce"
+)
+
+// defaultProvider 未指定provider时使用的默认值
+const defaultProvider = "default"
+
+// Provider 单个模型提供方的上下文限制配置
+type Provider struct {
+	Name string `json:"name"`
+	// MaxInputTokens 硬上限：输入超过该值时必须分块或拒绝
+	MaxInputTokens int `json:"maxInputTokens"`
+	// ChunkTokens 分块大小，留出指令与回答的余量
+	ChunkTokens int `json:"chunkTokens"`
+	// OverlapTokens 相邻块之间的重叠，避免切断跨块语义
+	OverlapTokens int `json:"overlapTokens"`
+}
+
+// Completer 底层补全调用，由HTTP客户端或测试桩实现
+type Completer interface {
+	Complete(ctx context.Context, provider, prompt string) (string, error)
+}
+
+// Observer AI调用指标观察者（与community.AIObserver同形，
+// 消费端自定义接口避免跨包依赖）
+type Observer interface {
+	AIRequestStarted(provider string)
+	ObserveAIRequest(provider string, duration time.Duration, err error)
+}
+
+// Service 超长输入的分块处理器
+//
+// 输入在提供方上下文限制内时直接调用；超限时按map-reduce
+// 处理：分块摘要后再汇总回答，响应元数据中报告所用策略。
+type Service struct {
+	completer Completer
+	observer  Observer
+	// guard 输入输出护栏，可为nil
+	guard *Guard
+
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewService 创建分块处理器
+func NewService(completer Completer) *Service {
+	return &Service{
+		completer: completer,
+		providers: map[string]Provider{
+			defaultProvider: {
+				Name:           defaultProvider,
+				MaxInputTokens: 8000,
+				ChunkTokens:    3000,
+				OverlapTokens:  200,
+			},
+		},
+	}
+}
+
+// SetObserver 注入指标观察者
+func (s *Service) SetObserver(observer Observer) {
+	s.observer = observer
+}
+
+// SetGuard 注入输入输出护栏
+func (s *Service) SetGuard(guard *Guard) {
+	s.guard = guard
+}
+
+// SetProvider 配置提供方限制
+func (s *Service) SetProvider(p Provider) error {
+	if p.Name == "" || p.MaxInputTokens <= 0 {
+		return fmt.Errorf("provider name and maxInputTokens are required")
+	}
+	if p.ChunkTokens <= 0 {
+		p.ChunkTokens = p.MaxInputTokens / 2
+	}
+	if p.OverlapTokens < 0 || p.OverlapTokens >= p.ChunkTokens {
+		return fmt.Errorf("overlapTokens must be in [0, chunkTokens)")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers[p.Name] = p
+	return nil
+}
+
+// Providers 列出提供方配置
+func (s *Service) Providers() []Provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	providers := make([]Provider, 0, len(s.providers))
+	for _, p := range s.providers {
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// provider 取提供方配置，未知名称回落到默认值
+func (s *Service) provider(name string) Provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if p, ok := s.providers[name]; ok {
+		return p
+	}
+	p := s.providers[defaultProvider]
+	if name != "" {
+		p.Name = name
+	}
+	return p
+}
+
+// ProcessRequest 处理请求
+type ProcessRequest struct {
+	Provider string `json:"provider"`
+	// Input 主体内容（可能超长的部分）
+	Input string `json:"input" binding:"required"`
+	// Question 对内容提出的问题，空则输出摘要
+	Question string `json:"question"`
+	// DisableChunking 调用方选择不分块，超限直接报错
+	DisableChunking bool `json:"disableChunking"`
+	// JSONFields 非空时要求输出为包含这些字段的JSON对象，
+	// 不合法时自动带错误重试修复
+	JSONFields []string `json:"jsonFields"`
+}
+
+// ProcessResult 处理结果与策略元数据
+type ProcessResult struct {
+	Answer      string `json:"answer"`
+	Strategy    string `json:"strategy"`
+	Chunks      int    `json:"chunks"`
+	InputTokens int    `json:"inputTokens"`
+	// RedactedSecrets 输入中被脱敏的凭证数量
+	RedactedSecrets int `json:"redactedSecrets,omitempty"`
+	// RepairAttempts JSON输出修复重试次数
+	RepairAttempts int `json:"repairAttempts,omitempty"`
+}
+
+// Process 处理可能超长的输入
+//
+// 接入护栏时先对输入做注入检测与凭证脱敏，要求JSON输出时
+// 在返回前做schema校验与自动修复。
+func (s *Service) Process(ctx context.Context, req ProcessRequest) (*ProcessResult, error) {
+	p := s.provider(req.Provider)
+
+	redacted := 0
+	if s.guard != nil {
+		req.Input, redacted = s.guard.StripSecrets(ctx, p.Name, req.Input)
+		if matched := s.guard.CheckInjection(ctx, p.Name, req.Input); len(matched) > 0 {
+			return nil, fmt.Errorf("prompt injection detected: %s", strings.Join(matched, ", "))
+		}
+	}
+
+	result, err := s.process(ctx, p, req)
+	if err != nil {
+		return nil, err
+	}
+	result.RedactedSecrets = redacted
+
+	if len(req.JSONFields) > 0 && s.guard != nil {
+		if err := s.enforceJSON(ctx, p.Name, req.JSONFields, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// enforceJSON 校验JSON输出，不合法时带错误重试修复
+func (s *Service) enforceJSON(ctx context.Context, provider string, requiredFields []string, result *ProcessResult) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		lastErr = s.guard.ValidateJSON(result.Answer, requiredFields)
+		if lastErr == nil {
+			result.Answer = ExtractJSON(result.Answer)
+			result.RepairAttempts = attempt
+			return nil
+		}
+		s.guard.RecordSchemaFailure(ctx, provider, lastErr)
+		if attempt == maxRepairAttempts {
+			break
+		}
+		repaired, err := s.complete(ctx, provider, fmt.Sprintf(
+			"以下输出未通过JSON校验（%s），请修复后只输出合法的JSON对象，必需字段：%s。\n\n%s",
+			lastErr, strings.Join(requiredFields, ", "), result.Answer))
+		if err != nil {
+			return fmt.Errorf("repair attempt failed: %w", err)
+		}
+		result.Answer = repaired
+	}
+	return fmt.Errorf("output schema validation failed after %d repair attempts: %w", maxRepairAttempts, lastErr)
+}
+
+// process 按上下文限制选择直接调用或map-reduce
+func (s *Service) process(ctx context.Context, p Provider, req ProcessRequest) (*ProcessResult, error) {
+	tokens := EstimateTokens(req.Input)
+
+	if tokens <= p.MaxInputTokens {
+		answer, err := s.complete(ctx, p.Name, buildPrompt(req.Input, req.Question))
+		if err != nil {
+			return nil, err
+		}
+		return &ProcessResult{
+			Answer:      answer,
+			Strategy:    StrategyDirect,
+			Chunks:      1,
+			InputTokens: tokens,
+		}, nil
+	}
+
+	if req.DisableChunking {
+		return nil, fmt.Errorf("input exceeds context limit for %s: %d > %d tokens (chunking disabled)",
+			p.Name, tokens, p.MaxInputTokens)
+	}
+
+	chunks := SplitChunks(req.Input, p.ChunkTokens, p.OverlapTokens)
+
+	// map：逐块摘要
+	summaries := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		prompt := fmt.Sprintf("以下是长文档的第%d/%d部分，请保留关键信息进行摘要：\n\n%s",
+			i+1, len(chunks), chunk)
+		summary, err := s.complete(ctx, p.Name, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d/%d failed: %w", i+1, len(chunks), err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	// reduce：汇总摘要后回答；合并结果仍超限时截断到上限
+	merged := strings.Join(summaries, "\n\n")
+	if EstimateTokens(merged) > p.MaxInputTokens {
+		merged = TruncateTokens(merged, p.MaxInputTokens)
+	}
+	answer, err := s.complete(ctx, p.Name, buildPrompt(merged, req.Question))
+	if err != nil {
+		return nil, fmt.Errorf("reduce step failed: %w", err)
+	}
+
+	return &ProcessResult{
+		Answer:      answer,
+		Strategy:    StrategyMapReduce,
+		Chunks:      len(chunks),
+		InputTokens: tokens,
+	}, nil
+}
+
+// complete 带指标观察的底层补全调用
+func (s *Service) complete(ctx context.Context, provider, prompt string) (string, error) {
+	if s.observer != nil {
+		s.observer.AIRequestStarted(provider)
+	}
+	start := time.Now()
+	answer, err := s.completer.Complete(ctx, provider, prompt)
+	if s.observer != nil {
+		s.observer.ObserveAIRequest(provider, time.Since(start), err)
+	}
+	return answer, err
+}
+
+// buildPrompt 拼装最终提问
+func buildPrompt(input, question string) string {
+	if question == "" {
+		return "请对以下内容给出摘要：\n\n" + input
+	}
+	return fmt.Sprintf("依据以下内容回答问题。\n\n内容：\n%s\n\n问题：%s", input, question)
+}
+
+// HTTPCompleter 通过AI服务HTTP接口执行补全
+type HTTPCompleter struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPCompleter 创建HTTP补全客户端
+func NewHTTPCompleter(baseURL string) *HTTPCompleter {
+	return &HTTPCompleter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// SetHTTPClient 替换底层HTTP客户端（注入熔断等出站治理）
+func (c *HTTPCompleter) SetHTTPClient(client *http.Client) {
+	c.client = client
+}
+
+// Complete 调用AI服务的补全接口
+func (c *HTTPCompleter) Complete(ctx context.Context, provider, prompt string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"provider": provider,
+		"prompt":   prompt,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/v1/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("completion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("completion service returned %d: %s", resp.StatusCode, string(data))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid completion response: %w", err)
+	}
+	return result.Text, nil
+}
@@ -0,0 +1,114 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler API密钥HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建API密钥处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册API密钥相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	keys := router.Group("/apikeys")
+	{
+		keys.POST("", h.Create)
+		keys.GET("", h.List)
+		keys.POST("/:keyId/rotate", h.Rotate)
+		keys.DELETE("/:keyId", h.Revoke)
+	}
+}
+
+// Middleware 返回校验X-API-Key请求头的中间件
+//
+// 未携带X-API-Key的请求直接放行，由上游JWT认证处理；携带的请求
+// 校验失败时拒绝，成功时在上下文写入api_key_id与api_key_service。
+func (h *Handler) Middleware(requiredScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.GetHeader("X-API-Key"))
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		key, err := h.service.Verify(c.Request.Context(), raw, requiredScope)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if strings.Contains(err.Error(), "rate limit") {
+				status = http.StatusTooManyRequests
+			} else if strings.Contains(err.Error(), "lacks scope") {
+				status = http.StatusForbidden
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set("api_key_id", key.ID)
+		c.Set("api_key_service", key.Service)
+		c.Next()
+	}
+}
+
+// Create 创建密钥
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// List 列出密钥
+func (h *Handler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context(), c.Query("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// Rotate 轮换密钥
+func (h *Handler) Rotate(c *gin.Context) {
+	created, err := h.service.Rotate(c.Request.Context(), c.Param("keyId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "not active") {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, created)
+}
+
+// Revoke 吊销密钥
+func (h *Handler) Revoke(c *gin.Context) {
+	if err := h.service.Revoke(c.Request.Context(), c.Param("keyId")); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
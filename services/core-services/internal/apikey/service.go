@@ -0,0 +1,294 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 密钥状态
+const (
+	StatusActive  = "active"
+	StatusRevoked = "revoked"
+)
+
+// keyPrefixLen 是密钥前缀长度，用于在不存明文的前提下定位密钥记录
+const keyPrefixLen = 12
+
+// defaultRateLimit 是未显式配置时每分钟允许的请求数
+const defaultRateLimit = 600
+
+// Key API密钥记录，仅保存哈希，明文只在创建/轮换时返回一次
+type Key struct {
+	ID         string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TenantID   string     `gorm:"type:varchar(64);index;not null" json:"tenant_id"`
+	Name       string     `gorm:"type:varchar(128);not null" json:"name"`
+	Service    string     `gorm:"type:varchar(64);index;not null" json:"service"`
+	Scopes     string     `gorm:"type:varchar(512)" json:"scopes"`
+	KeyPrefix  string     `gorm:"type:varchar(16);index;not null" json:"key_prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);not null" json:"-"`
+	RateLimit  int        `gorm:"default:0" json:"rate_limit"`
+	Status     string     `gorm:"type:varchar(16);default:'active';index" json:"status"`
+	RotatedTo  string     `gorm:"type:varchar(36)" json:"rotated_to,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Key) TableName() string {
+	return "cs_api_keys"
+}
+
+// CreateKeyRequest 创建密钥请求
+type CreateKeyRequest struct {
+	TenantID  string   `json:"tenant_id" binding:"required"`
+	Name      string   `json:"name" binding:"required"`
+	Service   string   `json:"service" binding:"required"`
+	Scopes    []string `json:"scopes"`
+	RateLimit int      `json:"rate_limit"`
+	TTLDays   int      `json:"ttl_days"`
+}
+
+// CreatedKey 创建/轮换结果，Plaintext仅此一次下发
+type CreatedKey struct {
+	Key       *Key   `json:"key"`
+	Plaintext string `json:"plaintext"`
+}
+
+// Service API密钥服务
+type Service struct {
+	db *gorm.DB
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+// rateWindow 按分钟的固定窗口计数器
+type rateWindow struct {
+	windowStart time.Time
+	count       int
+}
+
+// NewService 创建API密钥服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Key{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate api key tables: %w", err)
+	}
+	return &Service{db: db, windows: make(map[string]*rateWindow)}, nil
+}
+
+// Create 生成新密钥，明文仅在响应中返回一次
+func (s *Service) Create(ctx context.Context, req *CreateKeyRequest) (*CreatedKey, error) {
+	if req.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+
+	plaintext, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		ID:        uuid.NewString(),
+		TenantID:  req.TenantID,
+		Name:      req.Name,
+		Service:   req.Service,
+		Scopes:    strings.Join(req.Scopes, ","),
+		KeyPrefix: plaintext[:keyPrefixLen],
+		KeyHash:   hashKey(plaintext),
+		RateLimit: req.RateLimit,
+		Status:    StatusActive,
+	}
+	if req.TTLDays > 0 {
+		expires := time.Now().AddDate(0, 0, req.TTLDays)
+		key.ExpiresAt = &expires
+	}
+
+	if err := s.db.WithContext(ctx).Create(key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+	return &CreatedKey{Key: key, Plaintext: plaintext}, nil
+}
+
+// List 列出租户下的密钥（不含哈希）
+func (s *Service) List(ctx context.Context, tenantID string) ([]*Key, error) {
+	var keys []*Key
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Rotate 轮换密钥：生成同配置的新密钥并吊销旧密钥
+func (s *Service) Rotate(ctx context.Context, keyID string) (*CreatedKey, error) {
+	var old Key
+	if err := s.db.WithContext(ctx).First(&old, "id = ?", keyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("api key not found: %s", keyID)
+		}
+		return nil, fmt.Errorf("failed to load api key: %w", err)
+	}
+	if old.Status != StatusActive {
+		return nil, fmt.Errorf("api key is not active")
+	}
+
+	plaintext, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &Key{
+		ID:        uuid.NewString(),
+		TenantID:  old.TenantID,
+		Name:      old.Name,
+		Service:   old.Service,
+		Scopes:    old.Scopes,
+		KeyPrefix: plaintext[:keyPrefixLen],
+		KeyHash:   hashKey(plaintext),
+		RateLimit: old.RateLimit,
+		Status:    StatusActive,
+		ExpiresAt: old.ExpiresAt,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(replacement).Error; err != nil {
+			return fmt.Errorf("failed to create replacement key: %w", err)
+		}
+		if err := tx.Model(&old).Updates(map[string]interface{}{
+			"status":     StatusRevoked,
+			"rotated_to": replacement.ID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to revoke old key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreatedKey{Key: replacement, Plaintext: plaintext}, nil
+}
+
+// Revoke 吊销密钥
+func (s *Service) Revoke(ctx context.Context, keyID string) error {
+	result := s.db.WithContext(ctx).Model(&Key{}).
+		Where("id = ? AND status = ?", keyID, StatusActive).
+		Update("status", StatusRevoked)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke api key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("api key not found or already revoked: %s", keyID)
+	}
+	return nil
+}
+
+// Verify 校验明文密钥并执行限流，成功时返回密钥记录
+func (s *Service) Verify(ctx context.Context, plaintext, requiredScope string) (*Key, error) {
+	if len(plaintext) < keyPrefixLen {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	var keys []*Key
+	if err := s.db.WithContext(ctx).
+		Where("key_prefix = ? AND status = ?", plaintext[:keyPrefixLen], StatusActive).
+		Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+
+	hash := hashKey(plaintext)
+	var matched *Key
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hash)) == 1 {
+			matched = key
+			break
+		}
+	}
+	if matched == nil {
+		return nil, fmt.Errorf("invalid api key")
+	}
+	if matched.ExpiresAt != nil && time.Now().After(*matched.ExpiresAt) {
+		return nil, fmt.Errorf("api key expired")
+	}
+	if requiredScope != "" && !scopeAllowed(matched.Scopes, requiredScope) {
+		return nil, fmt.Errorf("api key lacks scope: %s", requiredScope)
+	}
+	if !s.allowRequest(matched) {
+		return nil, fmt.Errorf("api key rate limit exceeded")
+	}
+
+	// 最后使用时间为尽力更新，失败不影响请求
+	now := time.Now()
+	s.db.WithContext(ctx).Model(matched).UpdateColumn("last_used_at", &now)
+
+	return matched, nil
+}
+
+// allowRequest 按密钥执行每分钟固定窗口限流
+func (s *Service) allowRequest(key *Key) bool {
+	limit := key.RateLimit
+	if limit <= 0 {
+		limit = defaultRateLimit
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	window, ok := s.windows[key.ID]
+	if !ok || now.Sub(window.windowStart) >= time.Minute {
+		s.windows[key.ID] = &rateWindow{windowStart: now, count: 1}
+		return true
+	}
+	if window.count >= limit {
+		return false
+	}
+	window.count++
+	return true
+}
+
+// scopeAllowed 判断作用域列表是否覆盖所需作用域，支持"*"与"resource:*"
+func scopeAllowed(scopes, required string) bool {
+	for _, scope := range strings.Split(scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if scope == "*" || scope == required {
+			return true
+		}
+		if strings.HasSuffix(scope, ":*") && strings.HasPrefix(required, strings.TrimSuffix(scope, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateKey 生成带前缀的随机密钥明文
+func generateKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return "csk_" + hex.EncodeToString(buf), nil
+}
+
+// hashKey 计算密钥明文的存储哈希
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,130 @@
+package apiv2
+
+import (
+	"time"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+)
+
+// v2的DTO与领域模型解耦：字段单独声明而不是直接序列化模型，
+// 这样模型演进不会意外改变已发布的v2契约。
+
+// Board 看板DTO
+type Board struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"ownerId"`
+	Columns   []Column  `json:"columns"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Column 看板列DTO
+type Column struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	WIPLimit int    `json:"wipLimit"`
+	Tasks    []Task `json:"tasks"`
+}
+
+// Task 任务DTO
+type Task struct {
+	ID          string     `json:"id"`
+	BoardID     string     `json:"boardId"`
+	ColumnID    string     `json:"columnId"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	AssigneeID  string     `json:"assigneeId,omitempty"`
+	Position    int        `json:"position"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// Thread 讨论串DTO
+type Thread struct {
+	ID              string    `json:"id"`
+	Scope           string    `json:"scope"`
+	Title           string    `json:"title"`
+	AuthorID        string    `json:"authorId"`
+	IsLocked        bool      `json:"isLocked"`
+	SlowModeSeconds int       `json:"slowModeSeconds"`
+	PostCount       int64     `json:"postCount"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// Post 回复DTO
+type Post struct {
+	ID          string    `json:"id"`
+	ThreadID    string    `json:"threadId"`
+	AuthorID    string    `json:"authorId"`
+	Content     string    `json:"content"`
+	NeedsReview bool      `json:"needsReview"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// boardDTO 看板模型转DTO
+func boardDTO(board *tasks.Board) *Board {
+	dto := &Board{
+		ID:        board.ID,
+		Name:      board.Name,
+		OwnerID:   board.OwnerID,
+		Columns:   make([]Column, 0, len(board.Columns)),
+		CreatedAt: board.CreatedAt,
+	}
+	for _, column := range board.Columns {
+		columnDTO := Column{
+			ID:       column.ID,
+			Name:     column.Name,
+			Position: column.Position,
+			WIPLimit: column.WIPLimit,
+			Tasks:    make([]Task, 0, len(column.Tasks)),
+		}
+		for _, task := range column.Tasks {
+			columnDTO.Tasks = append(columnDTO.Tasks, *taskDTO(&task))
+		}
+		dto.Columns = append(dto.Columns, columnDTO)
+	}
+	return dto
+}
+
+// taskDTO 任务模型转DTO
+func taskDTO(task *tasks.Task) *Task {
+	return &Task{
+		ID:          task.ID,
+		BoardID:     task.BoardID,
+		ColumnID:    task.ColumnID,
+		Title:       task.Title,
+		Description: task.Description,
+		AssigneeID:  task.AssigneeID,
+		Position:    task.Position,
+		CompletedAt: task.CompletedAt,
+		CreatedAt:   task.CreatedAt,
+	}
+}
+
+// threadDTO 讨论串模型转DTO
+func threadDTO(thread *community.Thread) *Thread {
+	return &Thread{
+		ID:              thread.ID,
+		Scope:           thread.Scope,
+		Title:           thread.Title,
+		AuthorID:        thread.AuthorID,
+		IsLocked:        thread.IsLocked,
+		SlowModeSeconds: thread.SlowModeSeconds,
+		PostCount:       thread.PostCount,
+		CreatedAt:       thread.CreatedAt,
+	}
+}
+
+// postDTO 回复模型转DTO
+func postDTO(post *community.ThreadPost) *Post {
+	return &Post{
+		ID:          post.ID,
+		ThreadID:    post.ThreadID,
+		AuthorID:    post.AuthorID,
+		Content:     post.Content,
+		NeedsReview: post.NeedsReview,
+		CreatedAt:   post.CreatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package apiv2
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+)
+
+// Handler v2 API处理器
+//
+// v2与v1共享同一套服务层，区别在于对外契约：v2统一使用平台
+// 标准响应包装（code/data/message，见 openapi/core.v2.yaml），
+// 并通过DTO层与内部模型解耦；v1的响应形状保持冻结。
+type Handler struct {
+	taskService   *tasks.Service
+	threadService *community.ThreadService
+}
+
+// NewHandler 创建v2处理器
+func NewHandler(taskService *tasks.Service, threadService *community.ThreadService) *Handler {
+	return &Handler{
+		taskService:   taskService,
+		threadService: threadService,
+	}
+}
+
+// SetupRoutes 注册v2路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/boards/:boardId", h.GetBoard)
+	router.POST("/boards/:boardId/tasks", h.CreateTask)
+	router.POST("/tasks/:taskId/move", h.MoveTask)
+
+	router.GET("/threads/:threadId", h.GetThread)
+	router.GET("/threads/:threadId/posts", h.ListPosts)
+	router.POST("/threads/:threadId/posts", h.AddPost)
+}
+
+// GetBoard 获取看板
+func (h *Handler) GetBoard(c *gin.Context) {
+	board, err := h.taskService.GetBoard(c.Request.Context(), c.Param("boardId"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, boardDTO(board))
+}
+
+// CreateTask 创建任务
+func (h *Handler) CreateTask(c *gin.Context) {
+	var req tasks.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err.Error())
+		return
+	}
+	task, err := h.taskService.CreateTask(c.Request.Context(), c.Param("boardId"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respond(c, http.StatusCreated, taskDTO(task))
+}
+
+// MoveTask 移动任务
+func (h *Handler) MoveTask(c *gin.Context) {
+	var req tasks.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err.Error())
+		return
+	}
+	task, err := h.taskService.MoveTask(c.Request.Context(), c.Param("taskId"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, taskDTO(task))
+}
+
+// GetThread 获取讨论串
+func (h *Handler) GetThread(c *gin.Context) {
+	thread, err := h.threadService.GetThread(c.Request.Context(), c.Param("threadId"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, threadDTO(thread))
+}
+
+// ListPosts 列出讨论串回复
+func (h *Handler) ListPosts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	posts, err := h.threadService.ListPosts(c.Request.Context(), c.Param("threadId"), limit, offset)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	dtos := make([]*Post, 0, len(posts))
+	for _, post := range posts {
+		dtos = append(dtos, postDTO(post))
+	}
+	respond(c, http.StatusOK, gin.H{"posts": dtos})
+}
+
+// AddPost 发表回复
+func (h *Handler) AddPost(c *gin.Context) {
+	var req struct {
+		AuthorID string `json:"authorId" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err.Error())
+		return
+	}
+	post, err := h.threadService.AddPost(c.Request.Context(), c.Param("threadId"), req.AuthorID, req.Content)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respond(c, http.StatusCreated, postDTO(post))
+}
+
+// respond 按平台标准包装返回成功响应
+func respond(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, gin.H{"code": "OK", "data": data})
+}
+
+// respondInvalid 返回参数错误
+func respondInvalid(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{"code": "INVALID_ARGUMENT", "message": message})
+}
+
+// respondError 把业务错误映射为标准错误码
+func respondError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"code": "NOT_FOUND", "message": message})
+	case strings.Contains(message, "locked"),
+		strings.Contains(message, "rejected"),
+		strings.Contains(message, "limit"):
+		c.JSON(http.StatusConflict, gin.H{"code": "FAILED_PRECONDITION", "message": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"code": "INTERNAL", "message": message})
+	}
+}
@@ -0,0 +1,163 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 替代文本来源
+const (
+	AltSourceHuman = "human"
+	AltSourceAI    = "ai"
+)
+
+// altTextMaxLen 替代文本长度上限
+const altTextMaxLen = 512
+
+// Captioner 图片描述生成器
+//
+// 消费方接口：由main接线到AI服务，未注入时AI建议端点不可用。
+type Captioner interface {
+	Caption(ctx context.Context, imageURL string) (string, error)
+}
+
+// SetCaptioner 接入AI图片描述
+func (s *Service) SetCaptioner(captioner Captioner) {
+	s.captioner = captioner
+}
+
+// SetAltTextRequired 开启无障碍强制策略
+//
+// 开启后图片附件confirm时必须已有替代文本，否则拒绝发布。
+func (s *Service) SetAltTextRequired(required bool) {
+	s.requireAltText = required
+}
+
+// SetAltText 人工设置替代文本
+func (s *Service) SetAltText(ctx context.Context, attachmentID, altText string) (*Attachment, error) {
+	altText = strings.TrimSpace(altText)
+	if altText == "" {
+		return nil, fmt.Errorf("invalid alt text: must not be empty")
+	}
+	if len(altText) > altTextMaxLen {
+		return nil, fmt.Errorf("alt text too long: limit is %d bytes", altTextMaxLen)
+	}
+	attachment, err := s.get(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(attachment.ContentType, "image/") {
+		return nil, fmt.Errorf("invalid attachment: alt text applies to images only")
+	}
+	err = s.db.WithContext(ctx).Model(attachment).Updates(map[string]interface{}{
+		"alt_text":        altText,
+		"alt_text_source": AltSourceHuman,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save alt text: %w", err)
+	}
+	attachment.AltText = altText
+	attachment.AltTextSource = AltSourceHuman
+	return attachment, nil
+}
+
+// SuggestAltText 用AI为图片生成替代文本建议
+//
+// 建议在附件尚无人工替代文本时顺带保存（来源标记ai），已有
+// 人工文本则只返回不覆盖。
+func (s *Service) SuggestAltText(ctx context.Context, attachmentID string) (*Attachment, string, error) {
+	if s.captioner == nil {
+		return nil, "", fmt.Errorf("captioning is not configured")
+	}
+	attachment, err := s.get(ctx, attachmentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.HasPrefix(attachment.ContentType, "image/") {
+		return nil, "", fmt.Errorf("invalid attachment: alt text applies to images only")
+	}
+	if attachment.Status != StatusReady {
+		return nil, "", fmt.Errorf("attachment not ready: %s", attachmentID)
+	}
+	imageURL, err := s.backend.PresignDownload(attachment.ObjectKey, 5*time.Minute)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to presign image for captioning: %w", err)
+	}
+	caption, err := s.captioner.Caption(ctx, imageURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("captioning failed: %w", err)
+	}
+	caption = strings.TrimSpace(caption)
+	if len(caption) > altTextMaxLen {
+		caption = caption[:altTextMaxLen]
+	}
+
+	if attachment.AltTextSource != AltSourceHuman {
+		err = s.db.WithContext(ctx).Model(attachment).Updates(map[string]interface{}{
+			"alt_text":        caption,
+			"alt_text_source": AltSourceAI,
+		}).Error
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to save alt text suggestion: %w", err)
+		}
+		attachment.AltText = caption
+		attachment.AltTextSource = AltSourceAI
+	}
+	return attachment, caption, nil
+}
+
+// HTTPCaptioner 经HTTP调用AI服务的图片描述实现
+//
+// POST {baseURL}/v1/captions，请求{"imageUrl":...}，响应
+// {"caption":...}。
+type HTTPCaptioner struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPCaptioner 创建HTTP图片描述器
+func NewHTTPCaptioner(baseURL string) *HTTPCaptioner {
+	return &HTTPCaptioner{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Caption 生成图片描述
+func (c *HTTPCaptioner) Caption(ctx context.Context, imageURL string) (string, error) {
+	body, err := json.Marshal(map[string]string{"imageUrl": imageURL})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/captions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("caption service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return "", fmt.Errorf("caption service returned status %d: %s", resp.StatusCode, string(message))
+	}
+	var parsed struct {
+		Caption string `json:"caption"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("failed to decode caption response: %w", err)
+	}
+	if parsed.Caption == "" {
+		return "", fmt.Errorf("caption service returned empty caption")
+	}
+	return parsed.Caption, nil
+}
@@ -0,0 +1,196 @@
+package attachments
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 附件HTTP处理器
+type Handler struct {
+	service *Service
+	// local 非nil时注册本地内容路由（开发环境）
+	local *LocalBackend
+}
+
+// NewHandler 创建附件处理器
+func NewHandler(service *Service, local *LocalBackend) *Handler {
+	return &Handler{service: service, local: local}
+}
+
+// SetupRoutes 注册附件相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/attachments", h.Create)
+	router.GET("/attachments", h.List)
+	router.POST("/attachments/:attachmentId/confirm", h.Confirm)
+	router.GET("/attachments/:attachmentId/download", h.Download)
+	router.DELETE("/attachments/:attachmentId", h.Delete)
+	router.PUT("/attachments/:attachmentId/alt-text", h.SetAltText)
+	router.POST("/attachments/:attachmentId/alt-text/suggest", h.SuggestAltText)
+	if h.local != nil {
+		// 本地后端的"预签名"URL指向这两条路由
+		router.PUT("/attachments/content/*objectKey", h.PutLocal)
+		router.GET("/attachments/content/*objectKey", h.GetLocal)
+	}
+}
+
+// Create 申请上传：校验策略并返回预签名上传URL
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ticket, err := h.service.Create(c.Request.Context(), c.GetHeader("X-User-ID"), &req)
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// Confirm 确认上传完成
+func (h *Handler) Confirm(c *gin.Context) {
+	attachment, err := h.service.Confirm(c.Request.Context(), c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, attachment)
+}
+
+// Download 签发下载URL并302跳转（downloadUrl也在响应体中）
+func (h *Handler) Download(c *gin.Context) {
+	signed, err := h.service.DownloadURL(c.Request.Context(), c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("redirect") == "false" {
+		c.JSON(http.StatusOK, gin.H{"downloadUrl": signed})
+		return
+	}
+	c.Redirect(http.StatusFound, signed)
+}
+
+// List 列出业务对象的附件
+func (h *Handler) List(c *gin.Context) {
+	ownerType := c.Query("ownerType")
+	ownerID := c.Query("ownerId")
+	if ownerType == "" || ownerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ownerType and ownerId are required"})
+		return
+	}
+	items, err := h.service.List(c.Request.Context(), ownerType, ownerID)
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attachments": items})
+}
+
+// Delete 删除附件
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("attachmentId")); err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetAltText 人工设置图片替代文本
+func (h *Handler) SetAltText(c *gin.Context) {
+	var req struct {
+		AltText string `json:"altText" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	attachment, err := h.service.SetAltText(c.Request.Context(), c.Param("attachmentId"), req.AltText)
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, attachment)
+}
+
+// SuggestAltText AI生成替代文本建议
+func (h *Handler) SuggestAltText(c *gin.Context) {
+	attachment, suggestion, err := h.service.SuggestAltText(c.Request.Context(), c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(statusForAttachmentError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attachment": attachment, "suggestion": suggestion})
+}
+
+// PutLocal 接收本地后端的直传内容
+func (h *Handler) PutLocal(c *gin.Context) {
+	objectKey := strings.TrimPrefix(c.Param("objectKey"), "/")
+	if err := h.local.Verify(http.MethodPut, objectKey, c.Query("exp"), c.Query("sig")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	err := h.local.Write(objectKey, func(path string) error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(f, io.LimitReader(c.Request.Body, h.service.maxSize+1))
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to store object: %v", err)})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// GetLocal 输出本地后端的对象内容
+func (h *Handler) GetLocal(c *gin.Context) {
+	objectKey := strings.TrimPrefix(c.Param("objectKey"), "/")
+	if err := h.local.Verify(http.MethodGet, objectKey, c.Query("exp"), c.Query("sig")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	f, err := h.local.Open(objectKey)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "object not found"})
+		return
+	}
+	defer f.Close()
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, f)
+}
+
+// statusForAttachmentError 根据错误内容映射HTTP状态码
+func statusForAttachmentError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "too large"):
+		return http.StatusRequestEntityTooLarge
+	case strings.Contains(msg, "not allowed"), strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "must be"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "not ready"):
+		return http.StatusConflict
+	case strings.Contains(msg, "alt text required"), strings.Contains(msg, "too long"):
+		return http.StatusUnprocessableEntity
+	case strings.Contains(msg, "quarantined"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "captioning failed"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
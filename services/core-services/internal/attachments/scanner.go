@@ -0,0 +1,109 @@
+package attachments
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// 扫描状态
+const (
+	// ScanPending 已进入扫描队列，下载暂不可用（隔离中）
+	ScanPending = "pending"
+	// ScanClean 扫描通过
+	ScanClean = "clean"
+	// ScanInfected 检出威胁，永久隔离
+	ScanInfected = "infected"
+	// ScanError 扫描失败，按fail-open/fail-closed策略放行或隔离
+	ScanError = "error"
+)
+
+// ScanResult 单次扫描结论
+type ScanResult struct {
+	Clean bool
+	// Signature 检出的威胁名，Clean时为空
+	Signature string
+}
+
+// Scanner 病毒扫描接口，由ClamAV客户端或测试桩实现
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*ScanResult, error)
+}
+
+// clamChunkSize INSTREAM协议单块大小
+const clamChunkSize = 32 << 10
+
+// ClamAVScanner 通过clamd TCP接口执行扫描
+type ClamAVScanner struct {
+	addr    string // 如 localhost:3310
+	timeout time.Duration
+}
+
+// NewClamAVScanner 创建clamd客户端
+func NewClamAVScanner(addr string) *ClamAVScanner {
+	return &ClamAVScanner{addr: addr, timeout: 60 * time.Second}
+}
+
+// Scan 以INSTREAM协议把内容送入clamd扫描
+func (s *ClamAVScanner) Scan(ctx context.Context, r io.Reader) (*ScanResult, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("failed to start clamd stream: %w", err)
+	}
+
+	buf := make([]byte, clamChunkSize)
+	header := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(header, uint32(n))
+			if _, err := conn.Write(header); err != nil {
+				return nil, fmt.Errorf("failed to write clamd chunk: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, fmt.Errorf("failed to write clamd chunk: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read scan input: %w", readErr)
+		}
+	}
+	// 零长度块表示流结束
+	binary.BigEndian.PutUint32(header, 0)
+	if _, err := conn.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to finish clamd stream: %w", err)
+	}
+
+	reply, err := io.ReadAll(io.LimitReader(conn, 1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	verdict := strings.TrimRight(string(reply), "\x00\n")
+	switch {
+	case strings.HasSuffix(verdict, "OK"):
+		return &ScanResult{Clean: true}, nil
+	case strings.HasSuffix(verdict, "FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(verdict, "stream: "), " FOUND")
+		return &ScanResult{Clean: false, Signature: signature}, nil
+	default:
+		return nil, fmt.Errorf("unexpected clamd reply: %q", verdict)
+	}
+}
@@ -0,0 +1,451 @@
+package attachments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// StatusPending 已签发上传URL但尚未确认上传完成
+	StatusPending = "pending"
+	// StatusReady 上传完成，可被业务对象引用
+	StatusReady = "ready"
+)
+
+const (
+	// defaultMaxSize 附件大小上限（20MB）
+	defaultMaxSize = 20 << 20
+	// uploadExpiry 上传URL有效期
+	uploadExpiry = 15 * time.Minute
+	// downloadExpiry 下载URL有效期
+	downloadExpiry = 10 * time.Minute
+	// orphanAge pending状态超过该时长即视为孤儿，清理时删除
+	orphanAge = 24 * time.Hour
+	// cleanupInterval 孤儿清理周期
+	cleanupInterval = time.Hour
+)
+
+// ownerTypes 附件可挂载的业务对象类型
+var ownerTypes = map[string]bool{
+	"task":    true,
+	"post":    true,
+	"comment": true,
+}
+
+// allowedContentPrefixes 允许上传的内容类型前缀
+var allowedContentPrefixes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"text/",
+	"application/pdf",
+	"application/zip",
+	"application/json",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.",
+	"application/vnd.ms-excel",
+}
+
+// Attachment 附件元数据，对象内容存于后端存储
+type Attachment struct {
+	ID          string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	OwnerType   string `json:"ownerType" gorm:"type:varchar(16);index:idx_attachment_owner;not null"`
+	OwnerID     string `json:"ownerId" gorm:"type:varchar(36);index:idx_attachment_owner;not null"`
+	UploaderID  string `json:"uploaderId" gorm:"type:varchar(36);index"`
+	FileName    string `json:"fileName" gorm:"type:varchar(255);not null"`
+	ContentType string `json:"contentType" gorm:"type:varchar(128);not null"`
+	Size        int64  `json:"size" gorm:"not null"`
+	ObjectKey   string `json:"-" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Status      string `json:"status" gorm:"type:varchar(16);index;not null"`
+	// ScanStatus 病毒扫描状态，未启用扫描时为空
+	ScanStatus string `json:"scanStatus,omitempty" gorm:"type:varchar(16);index"`
+	// ScanSignature 检出的威胁名
+	ScanSignature string `json:"scanSignature,omitempty" gorm:"type:varchar(128)"`
+	// AltText 图片的替代文本（无障碍）
+	AltText string `json:"altText,omitempty" gorm:"type:varchar(512)"`
+	// AltTextSource human/ai，区分人工与AI生成
+	AltTextSource string     `json:"altTextSource,omitempty" gorm:"type:varchar(8)"`
+	ScannedAt     *time.Time `json:"scannedAt,omitempty"`
+	ReadyAt       *time.Time `json:"readyAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"index"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Attachment) TableName() string {
+	return "cs_attachments"
+}
+
+// Service 附件服务
+//
+// 上传流程：客户端先声明文件名/类型/大小换取预签名上传URL，
+// 直传对象存储后回调confirm把记录置为ready；超时未confirm的
+// pending记录由后台清理任务连同对象一起删除。
+type Service struct {
+	db      *gorm.DB
+	backend Backend
+	logger  *zap.Logger
+	maxSize int64
+
+	// scanner 病毒扫描器，可为nil（不扫描）
+	scanner Scanner
+	// scanFailOpen 扫描失败时true放行、false隔离
+	scanFailOpen bool
+	// captioner AI图片描述，可为nil（不提供AI建议）
+	captioner Captioner
+	// requireAltText 图片附件confirm时强制要求替代文本
+	requireAltText bool
+	// fetch 从对象存储拉取内容供扫描
+	fetch *http.Client
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建附件服务
+func NewService(db *gorm.DB, backend Backend, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Attachment{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate attachment tables: %w", err)
+	}
+	return &Service{
+		db:      db,
+		backend: backend,
+		logger:  logger,
+		maxSize: defaultMaxSize,
+		fetch:   &http.Client{Timeout: 2 * time.Minute},
+		stopCh:  make(chan struct{}),
+	}, nil
+}
+
+// SetScanner 接入病毒扫描：confirm后的附件先隔离，扫描
+// 通过才可下载；failOpen决定扫描失败时放行还是继续隔离
+func (s *Service) SetScanner(scanner Scanner, failOpen bool) {
+	s.scanner = scanner
+	s.scanFailOpen = failOpen
+}
+
+// SetMaxSize 调整附件大小上限
+func (s *Service) SetMaxSize(limit int64) {
+	if limit > 0 {
+		s.maxSize = limit
+	}
+}
+
+// CreateRequest 申请上传的参数
+type CreateRequest struct {
+	OwnerType   string `json:"ownerType" binding:"required"`
+	OwnerID     string `json:"ownerId" binding:"required"`
+	FileName    string `json:"fileName" binding:"required"`
+	ContentType string `json:"contentType" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+	// AltText 图片的替代文本，可在confirm前随时补
+	AltText string `json:"altText"`
+}
+
+// UploadTicket 申请上传的结果
+type UploadTicket struct {
+	Attachment *Attachment `json:"attachment"`
+	UploadURL  string      `json:"uploadUrl"`
+	ExpiresIn  int         `json:"expiresIn"` // 秒
+}
+
+// Create 校验策略并签发预签名上传URL
+func (s *Service) Create(ctx context.Context, uploaderID string, req *CreateRequest) (*UploadTicket, error) {
+	if !ownerTypes[req.OwnerType] {
+		return nil, fmt.Errorf("invalid owner type: %s", req.OwnerType)
+	}
+	if req.Size <= 0 {
+		return nil, fmt.Errorf("size must be positive")
+	}
+	if req.Size > s.maxSize {
+		return nil, fmt.Errorf("file too large: limit is %d bytes", s.maxSize)
+	}
+	if !contentTypeAllowed(req.ContentType) {
+		return nil, fmt.Errorf("content type not allowed: %s", req.ContentType)
+	}
+
+	id := uuid.NewString()
+	attachment := &Attachment{
+		ID:          id,
+		OwnerType:   req.OwnerType,
+		OwnerID:     req.OwnerID,
+		UploaderID:  uploaderID,
+		FileName:    path.Base(req.FileName),
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		ObjectKey:   objectKey(req.OwnerType, req.OwnerID, id, req.FileName),
+		Status:      StatusPending,
+	}
+	if alt := strings.TrimSpace(req.AltText); alt != "" && strings.HasPrefix(req.ContentType, "image/") {
+		if len(alt) > altTextMaxLen {
+			return nil, fmt.Errorf("alt text too long: limit is %d bytes", altTextMaxLen)
+		}
+		attachment.AltText = alt
+		attachment.AltTextSource = AltSourceHuman
+	}
+	if err := s.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	uploadURL, err := s.backend.PresignUpload(attachment.ObjectKey, attachment.ContentType, uploadExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+	return &UploadTicket{
+		Attachment: attachment,
+		UploadURL:  uploadURL,
+		ExpiresIn:  int(uploadExpiry.Seconds()),
+	}, nil
+}
+
+// Confirm 确认上传完成，记录置为ready
+func (s *Service) Confirm(ctx context.Context, attachmentID string) (*Attachment, error) {
+	attachment, err := s.get(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Status == StatusReady {
+		return attachment, nil
+	}
+	if s.requireAltText && strings.HasPrefix(attachment.ContentType, "image/") && attachment.AltText == "" {
+		return nil, fmt.Errorf("alt text required: set alt text before confirming image attachments")
+	}
+	now := time.Now()
+	updates := map[string]interface{}{"status": StatusReady, "ready_at": now}
+	if s.scanner != nil {
+		updates["scan_status"] = ScanPending
+	}
+	err = s.db.WithContext(ctx).Model(attachment).Updates(updates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm attachment: %w", err)
+	}
+	attachment.Status = StatusReady
+	attachment.ReadyAt = &now
+	if s.scanner != nil {
+		attachment.ScanStatus = ScanPending
+		go s.runScan(attachment.ID, attachment.ObjectKey)
+	}
+	return attachment, nil
+}
+
+// runScan 拉取对象内容扫描并回写结论
+func (s *Service) runScan(attachmentID, objKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	scanStatus := ScanError
+	signature := ""
+	content, err := s.openObject(ctx, objKey)
+	if err != nil {
+		s.logger.Error("failed to fetch attachment for scan",
+			zap.String("id", attachmentID), zap.Error(err))
+	} else {
+		defer content.Close()
+		result, err := s.scanner.Scan(ctx, content)
+		if err != nil {
+			s.logger.Error("attachment scan failed",
+				zap.String("id", attachmentID), zap.Error(err))
+		} else if result.Clean {
+			scanStatus = ScanClean
+		} else {
+			scanStatus = ScanInfected
+			signature = result.Signature
+			s.logger.Warn("attachment quarantined",
+				zap.String("id", attachmentID), zap.String("signature", signature))
+		}
+	}
+
+	now := time.Now()
+	err = s.db.Model(&Attachment{}).Where("id = ?", attachmentID).
+		Updates(map[string]interface{}{
+			"scan_status":    scanStatus,
+			"scan_signature": signature,
+			"scanned_at":     now,
+		}).Error
+	if err != nil {
+		s.logger.Error("failed to record scan result",
+			zap.String("id", attachmentID), zap.Error(err))
+	}
+}
+
+// openObject 打开对象内容：本地后端直接读文件，对象存储
+// 后端通过预签名GET拉取
+func (s *Service) openObject(ctx context.Context, objKey string) (io.ReadCloser, error) {
+	if local, ok := s.backend.(*LocalBackend); ok {
+		return local.Open(objKey)
+	}
+	signed, err := s.backend.PresignDownload(objKey, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signed, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.fetch.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch object: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("object store returned %d on fetch", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// DownloadURL 签发预签名下载URL，仅ready附件可下载
+func (s *Service) DownloadURL(ctx context.Context, attachmentID string) (string, error) {
+	attachment, err := s.get(ctx, attachmentID)
+	if err != nil {
+		return "", err
+	}
+	if attachment.Status != StatusReady {
+		return "", fmt.Errorf("attachment not ready: %s", attachmentID)
+	}
+	if s.scanner != nil {
+		switch attachment.ScanStatus {
+		case ScanClean:
+		case ScanPending:
+			return "", fmt.Errorf("attachment not ready: virus scan in progress")
+		case ScanInfected:
+			return "", fmt.Errorf("attachment quarantined: %s", attachment.ScanSignature)
+		case ScanError:
+			if !s.scanFailOpen {
+				return "", fmt.Errorf("attachment quarantined: scan failed and fail-closed policy is active")
+			}
+		default:
+			// 扫描启用前确认的历史附件视作已放行
+		}
+	}
+	signed, err := s.backend.PresignDownload(attachment.ObjectKey, downloadExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download: %w", err)
+	}
+	return signed, nil
+}
+
+// List 列出业务对象的ready附件
+func (s *Service) List(ctx context.Context, ownerType, ownerID string) ([]*Attachment, error) {
+	if !ownerTypes[ownerType] {
+		return nil, fmt.Errorf("invalid owner type: %s", ownerType)
+	}
+	var items []*Attachment
+	err := s.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ? AND status = ?", ownerType, ownerID, StatusReady).
+		Order("created_at ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return items, nil
+}
+
+// Delete 删除附件记录及后端对象
+func (s *Service) Delete(ctx context.Context, attachmentID string) error {
+	attachment, err := s.get(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.backend.Delete(ctx, attachment.ObjectKey); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(attachment).Error; err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// get 按ID读取附件
+func (s *Service) get(ctx context.Context, attachmentID string) (*Attachment, error) {
+	var attachment Attachment
+	err := s.db.WithContext(ctx).First(&attachment, "id = ?", attachmentID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attachment not found: %s", attachmentID)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+// Start 启动孤儿清理循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if n, err := s.CleanupOrphans(context.Background()); err != nil {
+					s.logger.Error("attachment cleanup failed", zap.Error(err))
+				} else if n > 0 {
+					s.logger.Info("attachment cleanup removed orphans", zap.Int("count", n))
+				}
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止清理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// CleanupOrphans 删除超时未确认上传的pending附件
+//
+// 对象删除失败时保留记录，下一轮重试，避免元数据先丢导致
+// 后端对象永久泄漏。
+func (s *Service) CleanupOrphans(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-orphanAge)
+	var orphans []*Attachment
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", StatusPending, cutoff).
+		Limit(200).
+		Find(&orphans).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list orphan attachments: %w", err)
+	}
+
+	removed := 0
+	for _, orphan := range orphans {
+		if err := s.backend.Delete(ctx, orphan.ObjectKey); err != nil {
+			s.logger.Warn("failed to delete orphan object", zap.String("objectKey", orphan.ObjectKey), zap.Error(err))
+			continue
+		}
+		if err := s.db.WithContext(ctx).Delete(orphan).Error; err != nil {
+			s.logger.Warn("failed to delete orphan record", zap.String("id", orphan.ID), zap.Error(err))
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// objectKey 生成对象键，按类型/对象分目录避免单目录过大
+func objectKey(ownerType, ownerID, id, fileName string) string {
+	ext := path.Ext(fileName)
+	return fmt.Sprintf("%s/%s/%s%s", ownerType, ownerID, id, ext)
+}
+
+// contentTypeAllowed 检查内容类型是否在白名单内
+func contentTypeAllowed(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, prefix := range allowedContentPrefixes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package attachments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backend 对象存储后端
+//
+// 上传与下载都通过预签名URL进行，服务进程不中转文件内容；
+// S3Backend覆盖AWS S3/MinIO/OSS等兼容实现，LocalBackend用于
+// 开发环境，由本服务自己签名并托管内容。
+type Backend interface {
+	// PresignUpload 生成限时上传URL
+	PresignUpload(objectKey, contentType string, expires time.Duration) (string, error)
+	// PresignDownload 生成限时下载URL
+	PresignDownload(objectKey string, expires time.Duration) (string, error)
+	// Delete 删除对象
+	Delete(ctx context.Context, objectKey string) error
+}
+
+// S3Backend S3兼容对象存储（AWS S3 / MinIO / 阿里云OSS）
+//
+// 使用SigV4查询串预签名，path-style访问以兼容MinIO默认配置。
+type S3Backend struct {
+	endpoint  string // 如 https://minio.internal:9000
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewS3Backend 创建S3兼容后端
+func NewS3Backend(endpoint, region, bucket, accessKey, secretKey string) *S3Backend {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Backend{
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// PresignUpload 生成限时PUT URL
+func (b *S3Backend) PresignUpload(objectKey, contentType string, expires time.Duration) (string, error) {
+	return b.presign(http.MethodPut, objectKey, expires)
+}
+
+// PresignDownload 生成限时GET URL
+func (b *S3Backend) PresignDownload(objectKey string, expires time.Duration) (string, error) {
+	return b.presign(http.MethodGet, objectKey, expires)
+}
+
+// Delete 通过预签名DELETE请求删除对象
+func (b *S3Backend) Delete(ctx context.Context, objectKey string) error {
+	signed, err := b.presign(http.MethodDelete, objectKey, time.Minute)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, signed, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("object store returned %d on delete", resp.StatusCode)
+	}
+	return nil
+}
+
+// presign SigV4查询串预签名
+func (b *S3Backend) presign(method, objectKey string, expires time.Duration) (string, error) {
+	endpoint, err := url.Parse(b.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	scope := shortDate + "/" + b.region + "/s3/aws4_request"
+	path := "/" + b.bucket + "/" + objectKey
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", b.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		query.Encode(),
+		"host:" + endpoint.Host,
+		"",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256(
+		hmacSHA256(
+			hmacSHA256(
+				hmacSHA256([]byte("AWS4"+b.secretKey), shortDate),
+				b.region),
+			"s3"),
+		"aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	query.Set("X-Amz-Signature", signature)
+	return b.endpoint + path + "?" + query.Encode(), nil
+}
+
+// hmacSHA256 HMAC-SHA256简写
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// LocalBackend 本地磁盘后端（开发环境）
+//
+// "预签名"URL指向本服务的内容路由，带HMAC签名与过期时间，
+// 语义与对象存储一致，业务代码无需区分。
+type LocalBackend struct {
+	dir    string
+	secret []byte
+	// baseURL 内容路由前缀，如 /api/v1/attachments/content
+	baseURL string
+}
+
+// NewLocalBackend 创建本地后端
+func NewLocalBackend(dir, secret, baseURL string) (*LocalBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment dir: %w", err)
+	}
+	return &LocalBackend{dir: dir, secret: []byte(secret), baseURL: baseURL}, nil
+}
+
+// PresignUpload 生成限时上传URL
+func (b *LocalBackend) PresignUpload(objectKey, contentType string, expires time.Duration) (string, error) {
+	return b.sign(http.MethodPut, objectKey, expires), nil
+}
+
+// PresignDownload 生成限时下载URL
+func (b *LocalBackend) PresignDownload(objectKey string, expires time.Duration) (string, error) {
+	return b.sign(http.MethodGet, objectKey, expires), nil
+}
+
+// Delete 删除本地文件
+func (b *LocalBackend) Delete(ctx context.Context, objectKey string) error {
+	err := os.Remove(b.objectPath(objectKey))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete local object: %w", err)
+	}
+	return nil
+}
+
+// sign 生成带HMAC签名的本地内容URL
+func (b *LocalBackend) sign(method, objectKey string, expires time.Duration) string {
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	mac := hex.EncodeToString(hmacSHA256(b.secret, method+"|"+objectKey+"|"+exp))
+	return fmt.Sprintf("%s/%s?exp=%s&sig=%s", b.baseURL, url.PathEscape(objectKey), exp, mac)
+}
+
+// Verify 校验本地内容URL的签名与有效期
+func (b *LocalBackend) Verify(method, objectKey, exp, sig string) error {
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return fmt.Errorf("url expired")
+	}
+	expected := hex.EncodeToString(hmacSHA256(b.secret, method+"|"+objectKey+"|"+exp))
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// objectPath 对象在磁盘上的路径
+func (b *LocalBackend) objectPath(objectKey string) string {
+	return filepath.Join(b.dir, filepath.FromSlash(objectKey))
+}
+
+// Open 打开对象内容
+func (b *LocalBackend) Open(objectKey string) (*os.File, error) {
+	return os.Open(b.objectPath(objectKey))
+}
+
+// Write 写入对象内容
+func (b *LocalBackend) Write(objectKey string, create func(path string) error) error {
+	path := b.objectPath(objectKey)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create object dir: %w", err)
+	}
+	return create(path)
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 审计日志HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建审计日志处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册审计日志相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/admin/audit-logs", h.List)
+}
+
+// List 按条件查询审计记录
+func (h *Handler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	query := Query{
+		ActorID:  c.Query("actor_id"),
+		Action:   c.Query("action"),
+		Resource: c.Query("resource"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if since := c.Query("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be RFC3339"})
+			return
+		}
+		query.Since = &parsed
+	}
+	if until := c.Query("until"); until != "" {
+		parsed, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be RFC3339"})
+			return
+		}
+		query.Until = &parsed
+	}
+
+	entries, err := h.service.List(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
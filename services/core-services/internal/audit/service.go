@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Entry 一条审计记录
+//
+// 记录谁在什么时候对什么资源做了什么，变更前后的快照以JSON保存，
+// 供安全审查与合规追溯。表只追加，按保留期定期清理。
+type Entry struct {
+	ID         string         `json:"id" gorm:"type:varchar(36);primaryKey"`
+	ActorID    string         `json:"actorId" gorm:"type:varchar(36);index;not null"`
+	Action     string         `json:"action" gorm:"type:varchar(64);index;not null"` // 如 user.ban / permission.grant
+	Resource   string         `json:"resource" gorm:"type:varchar(64);index;not null"`
+	ResourceID string         `json:"resourceId" gorm:"type:varchar(64);index"`
+	Before     datatypes.JSON `json:"before,omitempty"`
+	After      datatypes.JSON `json:"after,omitempty"`
+	IPAddress  string         `json:"ipAddress" gorm:"type:varchar(45)"`
+	CreatedAt  time.Time      `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Entry) TableName() string {
+	return "cs_audit_logs"
+}
+
+// Record 写审计记录时的输入
+type Record struct {
+	ActorID    string
+	Action     string
+	Resource   string
+	ResourceID string
+	Before     interface{}
+	After      interface{}
+	IPAddress  string
+}
+
+// Service 审计日志服务
+type Service struct {
+	db            *gorm.DB
+	logger        *zap.Logger
+	retentionDays int
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建审计日志服务，retentionDays<=0时使用默认180天
+func NewService(db *gorm.DB, retentionDays int, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Entry{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate audit tables: %w", err)
+	}
+	if retentionDays <= 0 {
+		retentionDays = 180
+	}
+	return &Service{
+		db:            db,
+		logger:        logger,
+		retentionDays: retentionDays,
+		stopCh:        make(chan struct{}),
+	}, nil
+}
+
+// Start 启动保留期清理循环
+func (s *Service) Start() {
+	go s.retentionLoop()
+}
+
+// Stop 停止清理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// retentionLoop 每小时清理超过保留期的记录
+func (s *Service) retentionLoop() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			cutoff := time.Now().AddDate(0, 0, -s.retentionDays)
+			if err := s.db.Where("created_at < ?", cutoff).Delete(&Entry{}).Error; err != nil {
+				s.logger.Error("audit retention cleanup failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Log 写入一条审计记录
+//
+// 审计失败只记日志不向调用方传播，避免审计故障阻断业务操作。
+func (s *Service) Log(ctx context.Context, record Record) {
+	entry := &Entry{
+		ID:         uuid.NewString(),
+		ActorID:    record.ActorID,
+		Action:     record.Action,
+		Resource:   record.Resource,
+		ResourceID: record.ResourceID,
+		IPAddress:  record.IPAddress,
+	}
+	if record.Before != nil {
+		if data, err := json.Marshal(record.Before); err == nil {
+			entry.Before = data
+		}
+	}
+	if record.After != nil {
+		if data, err := json.Marshal(record.After); err == nil {
+			entry.After = data
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		s.logger.Error("failed to write audit entry",
+			zap.String("action", record.Action), zap.Error(err))
+	}
+}
+
+// LogAction 以扁平参数写入审计记录
+//
+// 各业务模块通过本方法对应的消费方接口挂接审计，无需依赖Record类型。
+func (s *Service) LogAction(ctx context.Context, actorID, action, resource, resourceID, ip string, before, after interface{}) {
+	s.Log(ctx, Record{
+		ActorID:    actorID,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		Before:     before,
+		After:      after,
+		IPAddress:  ip,
+	})
+}
+
+// Query 审计记录查询条件
+type Query struct {
+	ActorID  string
+	Action   string
+	Resource string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
+// List 按条件查询审计记录
+func (s *Service) List(ctx context.Context, q Query) ([]*Entry, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	if q.ActorID != "" {
+		query = query.Where("actor_id = ?", q.ActorID)
+	}
+	if q.Action != "" {
+		query = query.Where("action = ?", q.Action)
+	}
+	if q.Resource != "" {
+		query = query.Where("resource = ?", q.Resource)
+	}
+	if q.Since != nil {
+		query = query.Where("created_at >= ?", *q.Since)
+	}
+	if q.Until != nil {
+		query = query.Where("created_at <= ?", *q.Until)
+	}
+	if q.Limit <= 0 || q.Limit > 200 {
+		q.Limit = 50
+	}
+	query = query.Limit(q.Limit).Offset(q.Offset)
+
+	var entries []*Entry
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, nil
+}
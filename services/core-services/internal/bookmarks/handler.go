@@ -0,0 +1,132 @@
+package bookmarks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 收藏HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建收藏处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册收藏相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/bookmarks", h.Save)
+	router.GET("/bookmarks", h.List)
+	router.GET("/bookmarks/folders", h.Folders)
+	router.GET("/bookmarks/export", h.Export)
+	router.DELETE("/bookmarks/:bookmarkId", h.Delete)
+}
+
+// requireUser 从请求头取当前用户
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// Save 收藏资源
+func (h *Handler) Save(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req SaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	bookmark, err := h.service.Save(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForBookmarkError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// List 列出收藏
+func (h *Handler) List(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	items, err := h.service.List(c.Request.Context(), userID, &ListQuery{
+		ResourceType: c.Query("resourceType"),
+		Folder:       c.Query("folder"),
+		Search:       c.Query("q"),
+		Limit:        limit,
+	})
+	if err != nil {
+		c.JSON(statusForBookmarkError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bookmarks": items, "total": len(items)})
+}
+
+// Folders 列出收藏夹
+func (h *Handler) Folders(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	folders, err := h.service.Folders(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForBookmarkError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"folders": folders})
+}
+
+// Export 导出收藏为CSV附件
+func (h *Handler) Export(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	csv, err := h.service.ExportCSV(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForBookmarkError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="bookmarks.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(csv))
+}
+
+// Delete 删除收藏
+func (h *Handler) Delete(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), userID, c.Param("bookmarkId")); err != nil {
+		c.JSON(statusForBookmarkError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark deleted"})
+}
+
+// statusForBookmarkError 根据错误内容映射HTTP状态码
+func statusForBookmarkError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
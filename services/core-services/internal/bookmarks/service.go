@@ -0,0 +1,212 @@
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 可收藏的资源类型
+const (
+	ResourcePost     = "post"
+	ResourceThread   = "thread"
+	ResourceTask     = "task"
+	ResourceWisdom   = "wisdom"
+	ResourceAIAnswer = "ai_answer"
+)
+
+// validResources 资源类型白名单
+var validResources = map[string]bool{
+	ResourcePost:     true,
+	ResourceThread:   true,
+	ResourceTask:     true,
+	ResourceWisdom:   true,
+	ResourceAIAnswer: true,
+}
+
+// Bookmark 一条收藏
+//
+// Title为收藏时的资源标题快照，列表与搜索不用回源各模块；
+// 同一用户对同一资源只保留一条收藏。
+type Bookmark struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex:idx_bookmark_user_resource;type:varchar(36);not null"`
+	// ResourceType post/thread/task/wisdom/ai_answer
+	ResourceType string `json:"resourceType" gorm:"uniqueIndex:idx_bookmark_user_resource;type:varchar(32);not null"`
+	ResourceID   string `json:"resourceId" gorm:"uniqueIndex:idx_bookmark_user_resource;type:varchar(36);not null"`
+	Title        string `json:"title" gorm:"type:varchar(512)"`
+	Note         string `json:"note,omitempty" gorm:"type:varchar(1024)"`
+	// Folder 收藏夹名，默认为空（未分组）
+	Folder    string    `json:"folder,omitempty" gorm:"index;type:varchar(128)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Bookmark) TableName() string {
+	return "cs_bookmarks"
+}
+
+// Service 统一收藏服务
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService 创建收藏服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Bookmark{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate bookmark tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// SaveRequest 收藏参数
+type SaveRequest struct {
+	ResourceType string `json:"resourceType" binding:"required"`
+	ResourceID   string `json:"resourceId" binding:"required"`
+	Title        string `json:"title"`
+	Note         string `json:"note"`
+	Folder       string `json:"folder"`
+}
+
+// Save 收藏一个资源，重复收藏时更新备注与收藏夹
+func (s *Service) Save(ctx context.Context, userID string, req *SaveRequest) (*Bookmark, error) {
+	if !validResources[req.ResourceType] {
+		return nil, fmt.Errorf("invalid resource type: %s", req.ResourceType)
+	}
+	var existing Bookmark
+	err := s.db.WithContext(ctx).
+		First(&existing, "user_id = ? AND resource_type = ? AND resource_id = ?",
+			userID, req.ResourceType, req.ResourceID).Error
+	if err == nil {
+		existing.Title = req.Title
+		existing.Note = req.Note
+		existing.Folder = req.Folder
+		if err := s.db.WithContext(ctx).Save(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to update bookmark: %w", err)
+		}
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check bookmark: %w", err)
+	}
+
+	bookmark := &Bookmark{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		Title:        req.Title,
+		Note:         req.Note,
+		Folder:       req.Folder,
+	}
+	if err := s.db.WithContext(ctx).Create(bookmark).Error; err != nil {
+		return nil, fmt.Errorf("failed to create bookmark: %w", err)
+	}
+	return bookmark, nil
+}
+
+// ListQuery 列表过滤参数
+type ListQuery struct {
+	ResourceType string
+	Folder       string
+	// Search 在标题与备注中做关键词匹配
+	Search string
+	Limit  int
+}
+
+// List 列出用户的收藏
+func (s *Service) List(ctx context.Context, userID string, query *ListQuery) ([]*Bookmark, error) {
+	limit := query.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if query.ResourceType != "" {
+		db = db.Where("resource_type = ?", query.ResourceType)
+	}
+	if query.Folder != "" {
+		db = db.Where("folder = ?", query.Folder)
+	}
+	if query.Search != "" {
+		pattern := "%" + strings.ToLower(query.Search) + "%"
+		db = db.Where("LOWER(title) LIKE ? OR LOWER(note) LIKE ?", pattern, pattern)
+	}
+	var items []*Bookmark
+	if err := db.Order("created_at DESC").Limit(limit).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	return items, nil
+}
+
+// Folders 列出用户用过的收藏夹名及条数
+func (s *Service) Folders(ctx context.Context, userID string) (map[string]int64, error) {
+	var rows []struct {
+		Folder string
+		Count  int64
+	}
+	err := s.db.WithContext(ctx).Model(&Bookmark{}).
+		Select("folder, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("folder").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	folders := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		folders[row.Folder] = row.Count
+	}
+	return folders, nil
+}
+
+// Delete 删除收藏
+func (s *Service) Delete(ctx context.Context, userID, bookmarkID string) error {
+	result := s.db.WithContext(ctx).
+		Delete(&Bookmark{}, "id = ? AND user_id = ?", bookmarkID, userID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bookmark not found")
+	}
+	return nil
+}
+
+// ExportCSV 导出用户全部收藏为CSV
+func (s *Service) ExportCSV(ctx context.Context, userID string) (string, error) {
+	var items []*Bookmark
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&items).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to export bookmarks: %w", err)
+	}
+	var b strings.Builder
+	b.WriteString("resourceType,resourceId,title,note,folder,createdAt\n")
+	for _, item := range items {
+		b.WriteString(strings.Join([]string{
+			csvEscape(item.ResourceType),
+			csvEscape(item.ResourceID),
+			csvEscape(item.Title),
+			csvEscape(item.Note),
+			csvEscape(item.Folder),
+			item.CreatedAt.Format(time.RFC3339),
+		}, ","))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// csvEscape 按CSV规则转义字段
+func csvEscape(field string) string {
+	if strings.ContainsAny(field, ",\"\n") {
+		return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+	}
+	return field
+}
@@ -0,0 +1,202 @@
+package breaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 熔断状态
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// 熔断与重试参数
+const (
+	// windowInterval 统计窗口长度，窗口结束时计数清零
+	windowInterval = 30 * time.Second
+	// minSamples 窗口内达到该请求数才评估是否熔断
+	minSamples = 10
+	// errorRateThreshold 错误率达到该值时熔断
+	errorRateThreshold = 0.5
+	// latencyThreshold 窗口均值时延超过该值时熔断
+	latencyThreshold = 5 * time.Second
+	// openCooldown 熔断后经过该时长进入半开
+	openCooldown = 30 * time.Second
+	// halfOpenProbes 半开状态放行的试探请求数
+	halfOpenProbes = 3
+	// retryBudgetRatio 每成功该数量请求积累一个重试名额
+	retryBudgetRatio = 10
+	// retryBudgetCap 重试名额上限
+	retryBudgetCap = 10
+)
+
+// serviceBreaker 单个上游服务的熔断器与重试预算
+//
+// 错误率和均值时延按固定窗口统计；熔断冷却后进入半开，
+// 试探全部成功才闭合。重试名额随成功请求积累，防止上游
+// 故障时重试放大流量。
+type serviceBreaker struct {
+	state       string
+	windowStart time.Time
+	requests    int
+	failures    int
+	totalTime   time.Duration
+	openedAt    time.Time
+	probes      int
+	probeFails  int
+	retryBudget int
+	successRun  int
+}
+
+// Manager 按上游服务名管理熔断器
+type Manager struct {
+	mu       sync.Mutex
+	services map[string]*serviceBreaker
+}
+
+// NewManager 创建熔断管理器
+func NewManager() *Manager {
+	return &Manager{services: make(map[string]*serviceBreaker)}
+}
+
+// breakerFor 取或建服务的熔断器（须持锁调用）
+func (m *Manager) breakerFor(service string) *serviceBreaker {
+	b, ok := m.services[service]
+	if !ok {
+		b = &serviceBreaker{state: StateClosed, windowStart: time.Now()}
+		m.services[service] = b
+	}
+	return b
+}
+
+// Allow 请求放行判定，熔断打开时返回错误
+func (m *Manager) Allow(service string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.breakerFor(service)
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < openCooldown {
+			return fmt.Errorf("circuit open for upstream: %s", service)
+		}
+		b.state = StateHalfOpen
+		b.probes, b.probeFails = 0, 0
+		return nil
+	case StateHalfOpen:
+		if b.probes >= halfOpenProbes {
+			return fmt.Errorf("circuit open for upstream: %s", service)
+		}
+		b.probes++
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record 上报一次请求结果
+func (m *Manager) Record(service string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.breakerFor(service)
+
+	if b.state == StateHalfOpen {
+		if failed {
+			b.probeFails++
+			b.trip()
+			return
+		}
+		if b.probes >= halfOpenProbes && b.probeFails == 0 {
+			b.reset()
+		}
+		return
+	}
+
+	if time.Since(b.windowStart) > windowInterval {
+		b.windowStart = time.Now()
+		b.requests, b.failures, b.totalTime = 0, 0, 0
+	}
+	b.requests++
+	b.totalTime += duration
+	if failed {
+		b.failures++
+		b.successRun = 0
+	} else {
+		b.successRun++
+		if b.successRun%retryBudgetRatio == 0 && b.retryBudget < retryBudgetCap {
+			b.retryBudget++
+		}
+	}
+
+	if b.state == StateClosed && b.requests >= minSamples {
+		errorRate := float64(b.failures) / float64(b.requests)
+		avgLatency := b.totalTime / time.Duration(b.requests)
+		if errorRate >= errorRateThreshold || avgLatency > latencyThreshold {
+			b.trip()
+		}
+	}
+}
+
+// AllowRetry 申请一个重试名额，无名额时拒绝
+func (m *Manager) AllowRetry(service string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.breakerFor(service)
+	if b.state != StateClosed || b.retryBudget <= 0 {
+		return false
+	}
+	b.retryBudget--
+	return true
+}
+
+// trip 打开熔断
+func (b *serviceBreaker) trip() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.retryBudget = 0
+	b.successRun = 0
+}
+
+// reset 闭合熔断并清空窗口
+func (b *serviceBreaker) reset() {
+	b.state = StateClosed
+	b.windowStart = time.Now()
+	b.requests, b.failures, b.totalTime = 0, 0, 0
+}
+
+// ServiceStatus 单个上游的熔断状态视图
+type ServiceStatus struct {
+	Service      string  `json:"service"`
+	State        string  `json:"state"`
+	Requests     int     `json:"requests"`
+	ErrorRate    float64 `json:"errorRate"`
+	AvgLatencyMs int64   `json:"avgLatencyMs"`
+	RetryBudget  int     `json:"retryBudget"`
+	OpenedAt     string  `json:"openedAt,omitempty"`
+}
+
+// Status 全部上游的熔断状态
+func (m *Manager) Status() []*ServiceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statuses := make([]*ServiceStatus, 0, len(m.services))
+	for service, b := range m.services {
+		status := &ServiceStatus{
+			Service:     service,
+			State:       b.state,
+			Requests:    b.requests,
+			RetryBudget: b.retryBudget,
+		}
+		if b.requests > 0 {
+			status.ErrorRate = float64(b.failures) / float64(b.requests)
+			status.AvgLatencyMs = (b.totalTime / time.Duration(b.requests)).Milliseconds()
+		}
+		if b.state == StateOpen {
+			status.OpenedAt = b.openedAt.Format(time.RFC3339)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,28 @@
+package breaker
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 熔断状态HTTP处理器
+type Handler struct {
+	manager *Manager
+}
+
+// NewHandler 创建熔断状态处理器
+func NewHandler(manager *Manager) *Handler {
+	return &Handler{manager: manager}
+}
+
+// SetupRoutes 注册熔断状态路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/gateway/status", h.Status)
+}
+
+// Status 各上游服务的熔断器状态
+func (h *Handler) Status(c *gin.Context) {
+	statuses := h.manager.Status()
+	c.JSON(http.StatusOK, gin.H{"services": statuses, "total": len(statuses)})
+}
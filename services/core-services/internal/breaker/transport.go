@@ -0,0 +1,95 @@
+package breaker
+
+import (
+	"net/http"
+	"time"
+)
+
+// maxAttempts 单请求总尝试次数（首次+重试）
+const maxAttempts = 2
+
+// transport 带熔断与重试预算的RoundTripper
+type transport struct {
+	manager *Manager
+	service string
+	base    http.RoundTripper
+}
+
+// Client 构造带熔断保护的HTTP客户端
+func (m *Manager) Client(service string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &transport{
+			manager: m,
+			service: service,
+			base:    http.DefaultTransport,
+		},
+	}
+}
+
+// RoundTrip 执行请求：熔断判定、结果上报与受预算的重试
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if allowErr := t.manager.Allow(t.service); allowErr != nil {
+			return nil, allowErr
+		}
+		attemptReq := req
+		if attempt > 0 {
+			if attemptReq, err = rewindRequest(req); err != nil {
+				break
+			}
+		}
+		start := time.Now()
+		resp, err = t.base.RoundTrip(attemptReq)
+		failed := err != nil || resp.StatusCode >= 500
+		t.manager.Record(t.service, time.Since(start), failed)
+		if !failed {
+			return resp, nil
+		}
+		if !t.shouldRetry(req, attempt) {
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+	return resp, err
+}
+
+// shouldRetry 仅幂等请求可重试，且须拿到重试名额
+func (t *transport) shouldRetry(req *http.Request, attempt int) bool {
+	if attempt+1 >= maxAttempts {
+		return false
+	}
+	if !isIdempotent(req) {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	return t.manager.AllowRetry(t.service)
+}
+
+// isIdempotent 按方法或Idempotency-Key头判定幂等
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// rewindRequest 为重试重建请求体
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
@@ -0,0 +1,47 @@
+package caching
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 响应缓存管理HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建响应缓存处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册缓存管理路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/cache")
+	{
+		group.GET("/stats", h.Stats)
+		group.DELETE("", h.Invalidate)
+	}
+}
+
+// Stats 命中率统计
+func (h *Handler) Stats(c *gin.Context) {
+	hits, misses, entries := h.service.Stats()
+	var hitRate float64
+	if hits+misses > 0 {
+		hitRate = float64(hits) / float64(hits+misses)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"hits":    hits,
+		"misses":  misses,
+		"entries": entries,
+		"hitRate": hitRate,
+	})
+}
+
+// Invalidate 手动失效缓存（path为路径前缀，缺省清空全部）
+func (h *Handler) Invalidate(c *gin.Context) {
+	removed := h.service.Invalidate(c.Query("path"))
+	c.JSON(http.StatusOK, gin.H{"removed": removed})
+}
@@ -0,0 +1,86 @@
+package caching
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyCapture 包装ResponseWriter以复制响应体
+type bodyCapture struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 同时写客户端与缓冲区
+func (w *bodyCapture) Write(data []byte) (int, error) {
+	if w.body.Len() < maxBodyBytes {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Middleware GET响应缓存中间件
+//
+// 缓存键为路径+查询串+授权范围（X-User-ID或X-API-Key），
+// 同一路径对不同调用方互不可见；命中时以X-Cache: HIT标记。
+func (s *Service) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		key := cacheKey(c)
+		headerValue := func(name string) string { return c.GetHeader(name) }
+
+		if cached := s.lookup(key, headerValue); cached != nil {
+			c.Header("X-Cache", "HIT")
+			c.Data(cached.status, cached.contentType, cached.body)
+			c.Abort()
+			return
+		}
+		c.Header("X-Cache", "MISS")
+
+		capture := &bodyCapture{ResponseWriter: c.Writer}
+		c.Writer = capture
+		c.Next()
+
+		status := capture.Status()
+		if status != http.StatusOK || capture.body.Len() >= maxBodyBytes {
+			return
+		}
+		ttl := cacheTTL(capture.Header().Get("Cache-Control"))
+		if ttl <= 0 {
+			return
+		}
+		var vary []string
+		for _, name := range strings.Split(capture.Header().Get("Vary"), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				if name == "*" {
+					return
+				}
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+		body := make([]byte, capture.body.Len())
+		copy(body, capture.body.Bytes())
+		s.store(key, vary, headerValue, &entry{
+			status:      status,
+			contentType: capture.Header().Get("Content-Type"),
+			body:        body,
+			expiresAt:   time.Now().Add(ttl),
+		})
+	}
+}
+
+// cacheKey 路径+查询串+授权范围
+func cacheKey(c *gin.Context) string {
+	scope := c.GetHeader("X-User-ID")
+	if scope == "" {
+		scope = c.GetHeader("X-API-Key")
+	}
+	return c.Request.URL.Path + "?" + c.Request.URL.RawQuery + "|" + scope
+}
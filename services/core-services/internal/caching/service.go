@@ -0,0 +1,197 @@
+package caching
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 缓存容量与TTL上限
+const (
+	// maxTTL 单条缓存的最长存活时间，上游max-age超出时截断
+	maxTTL = 5 * time.Minute
+	// maxBodyBytes 超过该大小的响应不缓存
+	maxBodyBytes = 1 << 20
+	// maxEntries 变体总数上限，达到后新响应不再入缓存
+	maxEntries = 10000
+	// sweepInterval 过期清理间隔
+	sweepInterval = time.Minute
+)
+
+// entry 一个缓存变体：某主键在某组Vary请求头取值下的响应
+type entry struct {
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// record 主键（路径+查询+授权范围）下的全部变体
+//
+// Vary头由首个被缓存的响应确定，后续查找按其列出的请求头
+// 取值区分变体。
+type record struct {
+	vary     []string
+	variants map[string]*entry
+}
+
+// Service GET响应缓存
+//
+// 进程内存储（多实例部署时各实例独立命中），只缓存响应头
+// 显式给出Cache-Control max-age的GET请求，遵循no-store/
+// no-cache与Vary语义。
+type Service struct {
+	mu      sync.Mutex
+	records map[string]*record
+	count   int
+
+	hits   int64
+	misses int64
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建响应缓存
+func NewService() *Service {
+	return &Service{
+		records: make(map[string]*record),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start 启动过期清理循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.sweep()
+			}
+		}
+	}()
+}
+
+// Stop 停止清理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// lookup 查缓存，返回命中的变体
+func (s *Service) lookup(key string, headerValue func(string) string) *entry {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[key]
+	if !ok {
+		s.misses++
+		return nil
+	}
+	variant := variantKey(rec.vary, headerValue)
+	cached, ok := rec.variants[variant]
+	if !ok || now.After(cached.expiresAt) {
+		s.misses++
+		return nil
+	}
+	s.hits++
+	return cached
+}
+
+// store 写入一个变体
+func (s *Service) store(key string, vary []string, headerValue func(string) string, cached *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count >= maxEntries {
+		return
+	}
+	rec, ok := s.records[key]
+	if !ok {
+		rec = &record{vary: vary, variants: make(map[string]*entry)}
+		s.records[key] = rec
+	}
+	variant := variantKey(rec.vary, headerValue)
+	if _, exists := rec.variants[variant]; !exists {
+		s.count++
+	}
+	rec.variants[variant] = cached
+}
+
+// Invalidate 按路径前缀失效缓存，前缀为空时清空全部；返回清除的变体数
+func (s *Service) Invalidate(pathPrefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for key, rec := range s.records {
+		if pathPrefix != "" && !strings.HasPrefix(key, pathPrefix) {
+			continue
+		}
+		removed += len(rec.variants)
+		delete(s.records, key)
+	}
+	s.count -= removed
+	return removed
+}
+
+// Stats 命中/未命中计数与当前条目数
+func (s *Service) Stats() (hits, misses int64, entries int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits, s.misses, s.count
+}
+
+// sweep 清除过期变体
+func (s *Service) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, rec := range s.records {
+		for variant, cached := range rec.variants {
+			if now.After(cached.expiresAt) {
+				delete(rec.variants, variant)
+				s.count--
+			}
+		}
+		if len(rec.variants) == 0 {
+			delete(s.records, key)
+		}
+	}
+}
+
+// variantKey 按Vary列出的请求头取值拼变体键
+func variantKey(vary []string, headerValue func(string) string) string {
+	if len(vary) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(vary))
+	for _, name := range vary {
+		parts = append(parts, name+"="+headerValue(name))
+	}
+	return strings.Join(parts, "|")
+}
+
+// cacheTTL 解析Cache-Control求缓存时长，0表示不可缓存
+func cacheTTL(cacheControl string) time.Duration {
+	var ttl time.Duration
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache" || directive == "private":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds <= 0 {
+				return 0
+			}
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl
+}
@@ -0,0 +1,209 @@
+package calls
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// 信令消息类型，经WebSocket hub在房间内定向转发
+const (
+	msgOffer  = "call.offer"
+	msgAnswer = "call.answer"
+	msgICE    = "call.ice"
+)
+
+// Handler 通话信令HTTP/WebSocket处理器
+type Handler struct {
+	service *Service
+	hub     *realtime.Hub
+}
+
+// NewHandler 创建通话处理器并在hub上注册信令转发
+func NewHandler(service *Service, hub *realtime.Hub) *Handler {
+	h := &Handler{service: service, hub: hub}
+	hub.Handle(msgOffer, h.relay)
+	hub.Handle(msgAnswer, h.relay)
+	hub.Handle(msgICE, h.relay)
+	return h
+}
+
+// SetupRoutes 注册通话相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	calls := router.Group("/calls")
+	{
+		calls.POST("/turn-credentials", h.IssueCredentials)
+		calls.POST("/sessions", h.StartSession)
+		calls.GET("/sessions/:sessionId", h.GetSession)
+		calls.POST("/sessions/:sessionId/join", h.Join)
+		calls.POST("/sessions/:sessionId/leave", h.Leave)
+		calls.POST("/sessions/:sessionId/end", h.EndSession)
+		calls.GET("/rooms/:roomId/sessions", h.ListRoomSessions)
+		calls.GET("/rooms/:roomId/ws", h.ServeWS)
+	}
+}
+
+// relay 把offer/answer/ICE定向转发给目标用户
+//
+// payload需携带"to"字段指明接收方；转发原样保留payload，
+// 业务语义（SDP、candidate）由客户端解释。
+func (h *Handler) relay(client *realtime.Client, message *realtime.Message) (*realtime.Message, error) {
+	var target struct {
+		To string `json:"to"`
+	}
+	if err := json.Unmarshal(message.Payload, &target); err != nil || target.To == "" {
+		// 无明确接收方时广播给房间内其他人（群组通话协商）
+		return message, nil
+	}
+
+	h.hub.SendToUser(client.Room, target.To, message)
+	return nil, nil
+}
+
+// ServeWS 加入房间的通话信令通道
+func (h *Handler) ServeWS(c *gin.Context) {
+	userID := c.Query("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	h.hub.ServeWS(c, "call:"+c.Param("roomId"), userID)
+}
+
+// IssueCredentials 签发短时效TURN凭证
+func (h *Handler) IssueCredentials(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	credentials, err := h.service.IssueCredentials(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, credentials)
+}
+
+// StartSession 开始通话会话
+func (h *Handler) StartSession(c *gin.Context) {
+	var req struct {
+		RoomID    string `json:"roomId" binding:"required"`
+		Kind      string `json:"kind" binding:"required,oneof=audio video screen-share"`
+		StartedBy string `json:"startedBy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.StartSession(c.Request.Context(), req.RoomID, req.Kind, req.StartedBy)
+	if err != nil {
+		c.JSON(statusForCallError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetSession 获取通话会话
+func (h *Handler) GetSession(c *gin.Context) {
+	session, err := h.service.GetSession(c.Request.Context(), c.Param("sessionId"))
+	if err != nil {
+		c.JSON(statusForCallError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Join 加入通话
+func (h *Handler) Join(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Join(c.Request.Context(), c.Param("sessionId"), req.UserID); err != nil {
+		c.JSON(statusForCallError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "joined call"})
+}
+
+// Leave 离开通话
+func (h *Handler) Leave(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Leave(c.Request.Context(), c.Param("sessionId"), req.UserID); err != nil {
+		c.JSON(statusForCallError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "left call"})
+}
+
+// EndSession 结束通话会话
+func (h *Handler) EndSession(c *gin.Context) {
+	if err := h.service.EndSession(c.Request.Context(), c.Param("sessionId")); err != nil {
+		c.JSON(statusForCallError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "call session ended"})
+}
+
+// ListRoomSessions 列出房间通话记录
+//
+// 带cursor参数时走游标分页并返回nextCursor；
+// 旧的limit/offset路径保留以兼容既有调用方。
+func (h *Handler) ListRoomSessions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if cursor, hasCursor := c.GetQuery("cursor"); hasCursor {
+		sessions, next, err := h.service.ListRoomSessionsBefore(c.Request.Context(), c.Param("roomId"), cursor, limit)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "invalid cursor") {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"sessions": sessions, "count": len(sessions), "nextCursor": next})
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	sessions, err := h.service.ListRoomSessions(c.Request.Context(), c.Param("roomId"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions, "count": len(sessions)})
+}
+
+// statusForCallError 把服务层错误映射为HTTP状态码
+func statusForCallError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already ended"), strings.Contains(message, "unsupported"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,279 @@
+package calls
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/pagination"
+)
+
+// defaultCredentialTTL TURN凭证默认有效期
+const defaultCredentialTTL = 10 * time.Minute
+
+// Session 通话会话记录（用于分析）
+type Session struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RoomID string `json:"roomId" gorm:"index;type:varchar(36);not null"`
+	// Kind 通话类型：audio / video / screen-share
+	Kind      string     `json:"kind" gorm:"type:varchar(16)"`
+	StartedBy string     `json:"startedBy" gorm:"type:varchar(36)"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	// DurationSeconds 通话时长，结束时计算
+	DurationSeconds int64 `json:"durationSeconds" gorm:"default:0"`
+
+	Participants []Participant `json:"participants,omitempty" gorm:"foreignKey:SessionID"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "cs_call_sessions"
+}
+
+// Participant 通话参与者
+type Participant struct {
+	ID        uint       `json:"-" gorm:"primaryKey"`
+	SessionID string     `json:"sessionId" gorm:"index;type:varchar(36);not null"`
+	UserID    string     `json:"userId" gorm:"type:varchar(36);not null"`
+	JoinedAt  time.Time  `json:"joinedAt"`
+	LeftAt    *time.Time `json:"leftAt,omitempty"`
+}
+
+// TableName 指定表名
+func (Participant) TableName() string {
+	return "cs_call_participants"
+}
+
+// TURNCredentials 短时效TURN凭证
+//
+// 采用coturn的REST API凭证约定：username为"过期时间戳:用户ID"，
+// credential为共享密钥对username的HMAC-SHA1。
+type TURNCredentials struct {
+	Username   string   `json:"username"`
+	Credential string   `json:"credential"`
+	TTL        int64    `json:"ttl"`
+	URIs       []string `json:"uris"`
+}
+
+// Service 通话信令与TURN凭证服务
+type Service struct {
+	db *gorm.DB
+	// turnSecret coturn共享密钥，为空时不提供凭证签发
+	turnSecret string
+	turnURIs   []string
+	ttl        time.Duration
+}
+
+// NewService 创建通话服务
+func NewService(db *gorm.DB, turnSecret string, turnURIs []string) (*Service, error) {
+	if err := db.AutoMigrate(&Session{}, &Participant{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate call tables: %w", err)
+	}
+	return &Service{
+		db:         db,
+		turnSecret: turnSecret,
+		turnURIs:   turnURIs,
+		ttl:        defaultCredentialTTL,
+	}, nil
+}
+
+// IssueCredentials 签发短时效TURN凭证
+func (s *Service) IssueCredentials(userID string) (*TURNCredentials, error) {
+	if s.turnSecret == "" {
+		return nil, fmt.Errorf("turn credential issuance is not configured")
+	}
+
+	expiry := time.Now().Add(s.ttl).Unix()
+	username := fmt.Sprintf("%d:%s", expiry, userID)
+
+	mac := hmac.New(sha1.New, []byte(s.turnSecret))
+	mac.Write([]byte(username))
+	credential := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return &TURNCredentials{
+		Username:   username,
+		Credential: credential,
+		TTL:        int64(s.ttl.Seconds()),
+		URIs:       s.turnURIs,
+	}, nil
+}
+
+// StartSession 开始通话会话
+func (s *Service) StartSession(ctx context.Context, roomID, kind, startedBy string) (*Session, error) {
+	if kind != "audio" && kind != "video" && kind != "screen-share" {
+		return nil, fmt.Errorf("unsupported call kind: %s", kind)
+	}
+
+	session := &Session{
+		ID:        uuid.NewString(),
+		RoomID:    roomID,
+		Kind:      kind,
+		StartedBy: startedBy,
+		StartedAt: time.Now(),
+		Participants: []Participant{
+			{UserID: startedBy, JoinedAt: time.Now()},
+		},
+	}
+	if err := s.db.WithContext(ctx).Create(session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create call session: %w", err)
+	}
+	return session, nil
+}
+
+// Join 参与者加入通话
+func (s *Service) Join(ctx context.Context, sessionID, userID string) error {
+	session, err := s.getOpenSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	err = s.db.WithContext(ctx).Model(&Participant{}).
+		Where("session_id = ? AND user_id = ? AND left_at IS NULL", session.ID, userID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check participant: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	participant := &Participant{SessionID: session.ID, UserID: userID, JoinedAt: time.Now()}
+	if err := s.db.WithContext(ctx).Create(participant).Error; err != nil {
+		return fmt.Errorf("failed to add participant: %w", err)
+	}
+	return nil
+}
+
+// Leave 参与者离开通话，最后一人离开时自动结束会话
+func (s *Service) Leave(ctx context.Context, sessionID, userID string) error {
+	session, err := s.getOpenSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.db.WithContext(ctx).Model(&Participant{}).
+		Where("session_id = ? AND user_id = ? AND left_at IS NULL", session.ID, userID).
+		Update("left_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark participant left: %w", err)
+	}
+
+	var active int64
+	err = s.db.WithContext(ctx).Model(&Participant{}).
+		Where("session_id = ? AND left_at IS NULL", session.ID).
+		Count(&active).Error
+	if err != nil {
+		return fmt.Errorf("failed to count active participants: %w", err)
+	}
+	if active == 0 {
+		return s.EndSession(ctx, session.ID)
+	}
+	return nil
+}
+
+// EndSession 结束通话会话并计算时长
+func (s *Service) EndSession(ctx context.Context, sessionID string) error {
+	session, err := s.getOpenSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"ended_at":         now,
+		"duration_seconds": int64(now.Sub(session.StartedAt).Seconds()),
+	}
+	err = s.db.WithContext(ctx).Model(&Session{}).Where("id = ?", session.ID).Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	// 兜底：把仍在线的参与者标记为离开
+	return s.db.WithContext(ctx).Model(&Participant{}).
+		Where("session_id = ? AND left_at IS NULL", session.ID).
+		Update("left_at", now).Error
+}
+
+// GetSession 获取通话会话（含参与者）
+func (s *Service) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	var session Session
+	err := s.db.WithContext(ctx).Preload("Participants").First(&session, "id = ?", sessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("call session not found: %s", sessionID)
+		}
+		return nil, fmt.Errorf("failed to get call session: %w", err)
+	}
+	return &session, nil
+}
+
+// ListRoomSessions 列出房间的通话记录
+func (s *Service) ListRoomSessions(ctx context.Context, roomID string, limit, offset int) ([]*Session, error) {
+	query := s.db.WithContext(ctx).Where("room_id = ?", roomID).Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var sessions []*Session
+	if err := query.Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list call sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// ListRoomSessionsBefore 游标分页列出房间通话记录（新到旧）
+//
+// 返回下一页游标；没有更多历史时游标为空。
+func (s *Service) ListRoomSessionsBefore(ctx context.Context, roomID, cursor string, limit int) ([]*Session, string, error) {
+	anchor, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = pagination.Clamp(limit)
+
+	var sessions []*Session
+	err = s.db.WithContext(ctx).
+		Where("room_id = ?", roomID).
+		Scopes(pagination.Before("started_at", anchor)).
+		Order("started_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list call sessions: %w", err)
+	}
+
+	next := ""
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		last := sessions[len(sessions)-1]
+		next = pagination.Encode(last.StartedAt, last.ID)
+	}
+	return sessions, next, nil
+}
+
+// getOpenSession 获取未结束的会话
+func (s *Service) getOpenSession(ctx context.Context, sessionID string) (*Session, error) {
+	var session Session
+	err := s.db.WithContext(ctx).First(&session, "id = ?", sessionID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("call session not found: %s", sessionID)
+		}
+		return nil, fmt.Errorf("failed to get call session: %w", err)
+	}
+	if session.EndedAt != nil {
+		return nil, fmt.Errorf("call session already ended: %s", sessionID)
+	}
+	return &session, nil
+}
@@ -0,0 +1,51 @@
+package cdn
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler CDN清除管理HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建CDN清除处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册CDN清除路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/cdn")
+	{
+		group.POST("/purge", h.Purge)
+		group.GET("/purges", h.ListPurges)
+	}
+}
+
+// Purge 手动登记待清除的代理键（下个批次下发）
+func (h *Handler) Purge(c *gin.Context) {
+	var req struct {
+		Keys []string `json:"keys" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.service.Enqueue(req.Keys...)
+	c.JSON(http.StatusAccepted, gin.H{"queued": len(req.Keys)})
+}
+
+// ListPurges 清除记录
+func (h *Handler) ListPurges(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	records, err := h.service.ListPurges(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"purges": records, "total": len(records)})
+}
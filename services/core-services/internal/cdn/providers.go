@@ -0,0 +1,160 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// purgeTimeout 调用CDN接口的超时
+const purgeTimeout = 15 * time.Second
+
+// CloudflarePurger 按缓存标签清除的Cloudflare后端
+type CloudflarePurger struct {
+	zoneID string
+	token  string
+	client *http.Client
+}
+
+// NewCloudflarePurger 创建Cloudflare清除后端
+func NewCloudflarePurger(zoneID, token string) *CloudflarePurger {
+	return &CloudflarePurger{
+		zoneID: zoneID,
+		token:  token,
+		client: &http.Client{Timeout: purgeTimeout},
+	}
+}
+
+// Name 后端标识
+func (p *CloudflarePurger) Name() string {
+	return "cloudflare"
+}
+
+// Purge 按标签清除（Cache-Tag/Surrogate-Key）
+func (p *CloudflarePurger) Purge(ctx context.Context, tags []string, urls []string) error {
+	body, err := json.Marshal(map[string][]string{"tags": tags})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/purge_cache", p.zoneID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare purge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("cloudflare purge response invalid: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("cloudflare purge rejected: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// AliyunPurger 按URL刷新的阿里云CDN后端
+type AliyunPurger struct {
+	accessKeyID     string
+	accessKeySecret string
+	client          *http.Client
+}
+
+// NewAliyunPurger 创建阿里云清除后端
+func NewAliyunPurger(accessKeyID, accessKeySecret string) *AliyunPurger {
+	return &AliyunPurger{
+		accessKeyID:     accessKeyID,
+		accessKeySecret: accessKeySecret,
+		client:          &http.Client{Timeout: purgeTimeout},
+	}
+}
+
+// Name 后端标识
+func (p *AliyunPurger) Name() string {
+	return "aliyun"
+}
+
+// Purge 调用RefreshObjectCaches按URL刷新
+func (p *AliyunPurger) Purge(ctx context.Context, tags []string, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	params := map[string]string{
+		"Action":           "RefreshObjectCaches",
+		"Version":          "2018-05-10",
+		"Format":           "JSON",
+		"AccessKeyId":      p.accessKeyID,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   uuid.NewString(),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"ObjectPath":       strings.Join(urls, "\n"),
+		"ObjectType":       "File",
+	}
+	params["Signature"] = signAliyun(params, p.accessKeySecret)
+
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://cdn.aliyuncs.com/?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("aliyun purge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("aliyun purge rejected: status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// signAliyun 计算阿里云RPC风格的HMAC-SHA1签名
+func signAliyun(params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, percentEncode(key)+"="+percentEncode(params[key]))
+	}
+	stringToSign := "GET&%2F&" + percentEncode(strings.Join(pairs, "&"))
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// percentEncode 按阿里云签名规范编码
+func percentEncode(raw string) string {
+	encoded := url.QueryEscape(raw)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	encoded = strings.ReplaceAll(encoded, "%7E", "~")
+	return encoded
+}
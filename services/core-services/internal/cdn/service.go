@@ -0,0 +1,215 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 公共内容的代理键（Surrogate-Key），响应打标、变更时按键清除
+const (
+	TagTrending = "public-trending"
+	TagWisdom   = "public-wisdom"
+	TagDaily    = "public-wisdom-daily"
+)
+
+// 批量清除参数
+const (
+	// flushInterval 待清除键的合并发送间隔
+	flushInterval = 10 * time.Second
+	// flushBatchSize 单次清除的键数上限
+	flushBatchSize = 50
+)
+
+// 清除记录状态
+const (
+	PurgeStatusSuccess = "success"
+	PurgeStatusFailed  = "failed"
+)
+
+// PurgeRecord 一次CDN清除的结果，供状态查询与排障
+type PurgeRecord struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// Keys 本次清除的代理键，空格分隔
+	Keys     string    `json:"keys" gorm:"type:text;not null"`
+	Provider string    `json:"provider" gorm:"type:varchar(16)"`
+	Status   string    `json:"status" gorm:"type:varchar(16);not null"`
+	Error    string    `json:"error,omitempty" gorm:"type:varchar(512)"`
+	Created  time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (PurgeRecord) TableName() string {
+	return "cs_cdn_purges"
+}
+
+// Purger CDN清除后端
+//
+// 标签型CDN（Cloudflare）直接按tags清除；URL型（阿里云）
+// 使用按注册路径解析出的完整URL。
+type Purger interface {
+	Name() string
+	Purge(ctx context.Context, tags []string, urls []string) error
+}
+
+// Service CDN边缘缓存清除服务
+//
+// 内容变更只入队代理键，后台按批合并下发，避免编辑高峰把
+// CDN清除配额打穿；未配置清除后端时打标仍生效、清除为空操作。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	purger Purger
+	// baseURL 公共站点源地址，URL型清除后端用它拼完整URL
+	baseURL string
+	// paths 代理键 → 公共路径
+	paths map[string]string
+
+	mu      sync.Mutex
+	pending map[string]bool
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建CDN清除服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&PurgeRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate cdn tables: %w", err)
+	}
+	return &Service{
+		db:      db,
+		logger:  logger,
+		paths:   make(map[string]string),
+		pending: make(map[string]bool),
+		stopCh:  make(chan struct{}),
+	}, nil
+}
+
+// SetPurger 配置清除后端与公共站点源地址
+func (s *Service) SetPurger(purger Purger, baseURL string) {
+	s.purger = purger
+	s.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// RegisterPath 注册代理键对应的公共路径（启动期调用）
+func (s *Service) RegisterPath(tag, path string) {
+	s.paths[tag] = path
+}
+
+// Start 启动批量清除循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.flush(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止清除循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Enqueue 登记待清除的代理键
+func (s *Service) Enqueue(tags ...string) {
+	if s.purger == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		s.pending[tag] = true
+	}
+}
+
+// HandleEvent 订阅内容变更事件，映射到代理键
+func (s *Service) HandleEvent(event string, payload interface{}) {
+	switch {
+	case event == "post.created" || event == "comment.created":
+		s.Enqueue(TagTrending)
+	case event == "wisdom.daily.push":
+		s.Enqueue(TagDaily)
+	case strings.HasPrefix(event, "wisdom."):
+		s.Enqueue(TagWisdom)
+	}
+}
+
+// ListPurges 最近的清除记录（新到旧）
+func (s *Service) ListPurges(ctx context.Context, limit int) ([]*PurgeRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	var records []*PurgeRecord
+	err := s.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purge records: %w", err)
+	}
+	return records, nil
+}
+
+// flush 把待清除键按批下发并记录结果
+func (s *Service) flush(ctx context.Context) {
+	if s.purger == nil {
+		return
+	}
+	s.mu.Lock()
+	tags := make([]string, 0, len(s.pending))
+	for tag := range s.pending {
+		tags = append(tags, tag)
+		if len(tags) >= flushBatchSize {
+			break
+		}
+	}
+	for _, tag := range tags {
+		delete(s.pending, tag)
+	}
+	s.mu.Unlock()
+	if len(tags) == 0 {
+		return
+	}
+
+	urls := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if path, ok := s.paths[tag]; ok && s.baseURL != "" {
+			urls = append(urls, s.baseURL+path)
+		}
+	}
+
+	record := &PurgeRecord{
+		ID:       uuid.NewString(),
+		Keys:     strings.Join(tags, " "),
+		Provider: s.purger.Name(),
+		Status:   PurgeStatusSuccess,
+	}
+	if err := s.purger.Purge(ctx, tags, urls); err != nil {
+		record.Status = PurgeStatusFailed
+		record.Error = err.Error()
+		s.logger.Warn("cdn purge failed", zap.Strings("tags", tags), zap.Error(err))
+		// 失败的键放回队列，下个周期重试
+		s.mu.Lock()
+		for _, tag := range tags {
+			s.pending[tag] = true
+		}
+		s.mu.Unlock()
+	}
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		s.logger.Warn("failed to store purge record", zap.Error(err))
+	}
+}
@@ -0,0 +1,186 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 跨发布目标类型
+const (
+	TargetCategory = "category" // 板块/分类
+	TargetRoom     = "room"     // 聊天房间
+)
+
+// CrossPost 跨发布记录
+//
+// 原帖是唯一的规范来源（canonical source），跨发布只保存引用，
+// 统计与内容变更都回溯到原帖。
+type CrossPost struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// SourcePostID 原帖ID（规范来源）
+	SourcePostID   string    `json:"sourcePostId" gorm:"uniqueIndex:idx_crosspost_target;type:varchar(36);not null"`
+	SourceThreadID string    `json:"sourceThreadId" gorm:"index;type:varchar(36);not null"`
+	TargetType     string    `json:"targetType" gorm:"uniqueIndex:idx_crosspost_target;type:varchar(16);not null"`
+	TargetID       string    `json:"targetId" gorm:"uniqueIndex:idx_crosspost_target;type:varchar(36);not null"`
+	SharedBy       string    `json:"sharedBy" gorm:"type:varchar(36)"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (CrossPost) TableName() string {
+	return "cs_cross_posts"
+}
+
+// CrossPostTarget 跨发布目标
+type CrossPostTarget struct {
+	Type string `json:"type" binding:"required,oneof=category room"`
+	ID   string `json:"id" binding:"required"`
+}
+
+// RichCard 分享到聊天房间时渲染的富卡片
+type RichCard struct {
+	PostID      string `json:"postId"`
+	ThreadID    string `json:"threadId"`
+	ThreadTitle string `json:"threadTitle"`
+	AuthorID    string `json:"authorId"`
+	Excerpt     string `json:"excerpt"`
+	// CanonicalPath 指向原帖的规范路径，客户端据此跳转并聚合统计
+	CanonicalPath string `json:"canonicalPath"`
+}
+
+// cardExcerptRunes 富卡片摘要的最大长度
+const cardExcerptRunes = 140
+
+// CrossPostService 跨发布服务
+type CrossPostService struct {
+	db      *gorm.DB
+	threads *ThreadService
+}
+
+// NewCrossPostService 创建跨发布服务
+func NewCrossPostService(db *gorm.DB, threads *ThreadService) (*CrossPostService, error) {
+	if err := db.AutoMigrate(&CrossPost{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate cross post table: %w", err)
+	}
+	return &CrossPostService{db: db, threads: threads}, nil
+}
+
+// Share 把原帖跨发布到多个板块或房间
+//
+// 同一帖子对同一目标只保留一条记录，重复分享被忽略。
+func (s *CrossPostService) Share(ctx context.Context, postID, sharedBy string, targets []CrossPostTarget) ([]*CrossPost, error) {
+	post, err := s.getSourcePost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*CrossPost, 0, len(targets))
+	for _, target := range targets {
+		if target.Type != TargetCategory && target.Type != TargetRoom {
+			return nil, fmt.Errorf("unsupported cross post target type: %s", target.Type)
+		}
+		record := &CrossPost{
+			ID:             uuid.NewString(),
+			SourcePostID:   post.ID,
+			SourceThreadID: post.ThreadID,
+			TargetType:     target.Type,
+			TargetID:       target.ID,
+			SharedBy:       sharedBy,
+		}
+		result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to create cross post: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			created = append(created, record)
+		}
+	}
+	return created, nil
+}
+
+// Card 构建原帖的富卡片，供聊天房间内展示
+func (s *CrossPostService) Card(ctx context.Context, postID string) (*RichCard, error) {
+	post, err := s.getSourcePost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := s.threads.GetThread(ctx, post.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	excerpt := []rune(post.Content)
+	if len(excerpt) > cardExcerptRunes {
+		excerpt = append(excerpt[:cardExcerptRunes-1], '…')
+	}
+	return &RichCard{
+		PostID:        post.ID,
+		ThreadID:      thread.ID,
+		ThreadTitle:   thread.Title,
+		AuthorID:      post.AuthorID,
+		Excerpt:       string(excerpt),
+		CanonicalPath: fmt.Sprintf("/community/threads/%s/posts/%s", thread.ID, post.ID),
+	}, nil
+}
+
+// ListForTarget 列出跨发布到某板块或房间的帖子
+func (s *CrossPostService) ListForTarget(ctx context.Context, targetType, targetID string) ([]*CrossPost, error) {
+	var records []*CrossPost
+	err := s.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cross posts: %w", err)
+	}
+	return records, nil
+}
+
+// ShareCount 统计原帖被跨发布的次数（聚合到规范来源）
+func (s *CrossPostService) ShareCount(ctx context.Context, postID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&CrossPost{}).
+		Where("source_post_id = ?", postID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count cross posts: %w", err)
+	}
+	return count, nil
+}
+
+// DeletePost 删除原帖并级联清理所有跨发布记录
+//
+// 删除传播规则：原帖删除后，所有板块与房间中的分享一并失效。
+func (s *CrossPostService) DeletePost(ctx context.Context, postID string) error {
+	post, err := s.getSourcePost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&CrossPost{}, "source_post_id = ?", postID).Error; err != nil {
+			return fmt.Errorf("failed to delete cross posts: %w", err)
+		}
+		if err := tx.Delete(&ThreadPost{}, "id = ?", postID).Error; err != nil {
+			return fmt.Errorf("failed to delete post: %w", err)
+		}
+		return tx.Model(&Thread{}).Where("id = ? AND post_count > 0", post.ThreadID).
+			UpdateColumn("post_count", gorm.Expr("post_count - 1")).Error
+	})
+}
+
+// getSourcePost 获取原帖
+func (s *CrossPostService) getSourcePost(ctx context.Context, postID string) (*ThreadPost, error) {
+	var post ThreadPost
+	if err := s.db.WithContext(ctx).First(&post, "id = ?", postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("post not found: %s", postID)
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	return &post, nil
+}
@@ -0,0 +1,96 @@
+package community
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CrossPostHandler 跨发布HTTP处理器
+type CrossPostHandler struct {
+	service *CrossPostService
+}
+
+// NewCrossPostHandler 创建跨发布处理器
+func NewCrossPostHandler(service *CrossPostService) *CrossPostHandler {
+	return &CrossPostHandler{service: service}
+}
+
+// SetupRoutes 注册跨发布相关路由
+func (h *CrossPostHandler) SetupRoutes(router gin.IRouter) {
+	posts := router.Group("/community/posts/:postId")
+	{
+		posts.POST("/cross-posts", h.Share)
+		posts.GET("/cross-posts/count", h.ShareCount)
+		posts.GET("/card", h.Card)
+		posts.DELETE("", h.DeletePost)
+	}
+	router.GET("/community/targets/:type/:id/cross-posts", h.ListForTarget)
+}
+
+// Share 跨发布原帖到多个目标
+func (h *CrossPostHandler) Share(c *gin.Context) {
+	var req struct {
+		SharedBy string            `json:"sharedBy" binding:"required"`
+		Targets  []CrossPostTarget `json:"targets" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.Share(c.Request.Context(), c.Param("postId"), req.SharedBy, req.Targets)
+	if err != nil {
+		c.JSON(statusForCrossPostError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"crossPosts": created, "count": len(created)})
+}
+
+// Card 获取原帖富卡片
+func (h *CrossPostHandler) Card(c *gin.Context) {
+	card, err := h.service.Card(c.Request.Context(), c.Param("postId"))
+	if err != nil {
+		c.JSON(statusForCrossPostError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
+
+// ShareCount 查询原帖的跨发布次数
+func (h *CrossPostHandler) ShareCount(c *gin.Context) {
+	count, err := h.service.ShareCount(c.Request.Context(), c.Param("postId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"postId": c.Param("postId"), "shareCount": count})
+}
+
+// ListForTarget 列出跨发布到某目标的帖子
+func (h *CrossPostHandler) ListForTarget(c *gin.Context) {
+	records, err := h.service.ListForTarget(c.Request.Context(), c.Param("type"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"crossPosts": records, "count": len(records)})
+}
+
+// DeletePost 删除原帖并传播删除所有分享
+func (h *CrossPostHandler) DeletePost(c *gin.Context) {
+	if err := h.service.DeletePost(c.Request.Context(), c.Param("postId")); err != nil {
+		c.JSON(statusForCrossPostError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "post and cross posts deleted"})
+}
+
+// statusForCrossPostError 把服务层错误映射为HTTP状态码
+func statusForCrossPostError(err error) int {
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
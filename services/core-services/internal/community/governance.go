@@ -0,0 +1,311 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/pagination"
+)
+
+// 选举状态
+const (
+	ElectionStatusOpen   = "open"
+	ElectionStatusClosed = "closed"
+)
+
+// Moderator 社区版主（由用户选举产生）
+type Moderator struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Scope    string `json:"scope" gorm:"uniqueIndex:idx_scope_user;not null"` // 房间或板块ID
+	UserID   string `json:"userId" gorm:"uniqueIndex:idx_scope_user;type:varchar(36);not null"`
+	IsActive bool   `json:"isActive" gorm:"default:true"`
+	// TermEndsAt 任期结束时间，零值表示无任期限制
+	TermEndsAt *time.Time `json:"termEndsAt,omitempty"`
+	ElectedAt  time.Time  `json:"electedAt"`
+}
+
+// TableName 指定表名
+func (Moderator) TableName() string {
+	return "cs_moderators"
+}
+
+// Election 版主选举
+type Election struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Scope     string     `json:"scope" gorm:"index;not null"`
+	Status    string     `json:"status" gorm:"default:open"`
+	Seats     int        `json:"seats" gorm:"default:1"` // 本次选举产生的版主席位数
+	EndsAt    time.Time  `json:"endsAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (Election) TableName() string {
+	return "cs_elections"
+}
+
+// ElectionVote 选举投票（每人一票）
+type ElectionVote struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ElectionID  string    `json:"electionId" gorm:"uniqueIndex:idx_election_voter;type:varchar(36);not null"`
+	VoterID     string    `json:"voterId" gorm:"uniqueIndex:idx_election_voter;type:varchar(36);not null"`
+	CandidateID string    `json:"candidateId" gorm:"index;type:varchar(36);not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ElectionVote) TableName() string {
+	return "cs_election_votes"
+}
+
+// ModerationAction 版主处置动作日志（只追加）
+type ModerationAction struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Scope       string    `json:"scope" gorm:"index;not null"`
+	ModeratorID string    `json:"moderatorId" gorm:"index;type:varchar(36);not null"`
+	Action      string    `json:"action" gorm:"not null"` // 如 delete_post / mute_user / lock_thread
+	TargetType  string    `json:"targetType"`
+	TargetID    string    `json:"targetId"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ModerationAction) TableName() string {
+	return "cs_moderation_actions"
+}
+
+// GovernanceService 社区治理服务
+type GovernanceService struct {
+	db *gorm.DB
+}
+
+// NewGovernanceService 创建社区治理服务
+func NewGovernanceService(db *gorm.DB) (*GovernanceService, error) {
+	if err := db.AutoMigrate(&Moderator{}, &Election{}, &ElectionVote{}, &ModerationAction{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate governance tables: %w", err)
+	}
+	return &GovernanceService{db: db}, nil
+}
+
+// StartElection 发起版主选举
+func (s *GovernanceService) StartElection(ctx context.Context, scope string, seats int, duration time.Duration) (*Election, error) {
+	if seats <= 0 {
+		seats = 1
+	}
+	if duration <= 0 {
+		duration = 7 * 24 * time.Hour
+	}
+
+	var open int64
+	if err := s.db.WithContext(ctx).Model(&Election{}).
+		Where("scope = ? AND status = ?", scope, ElectionStatusOpen).
+		Count(&open).Error; err != nil {
+		return nil, fmt.Errorf("failed to check open elections: %w", err)
+	}
+	if open > 0 {
+		return nil, fmt.Errorf("an election is already open for scope: %s", scope)
+	}
+
+	election := &Election{
+		ID:     uuid.NewString(),
+		Scope:  scope,
+		Status: ElectionStatusOpen,
+		Seats:  seats,
+		EndsAt: time.Now().Add(duration),
+	}
+	if err := s.db.WithContext(ctx).Create(election).Error; err != nil {
+		return nil, fmt.Errorf("failed to create election: %w", err)
+	}
+	return election, nil
+}
+
+// Vote 投票（每个用户在一次选举中只能投一票）
+func (s *GovernanceService) Vote(ctx context.Context, electionID, voterID, candidateID string) error {
+	var election Election
+	if err := s.db.WithContext(ctx).First(&election, "id = ?", electionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("election not found: %s", electionID)
+		}
+		return fmt.Errorf("failed to get election: %w", err)
+	}
+	if election.Status != ElectionStatusOpen || time.Now().After(election.EndsAt) {
+		return fmt.Errorf("election is not open for voting: %s", electionID)
+	}
+	if voterID == candidateID {
+		return fmt.Errorf("voting for yourself is not allowed")
+	}
+
+	vote := &ElectionVote{
+		ElectionID:  electionID,
+		VoterID:     voterID,
+		CandidateID: candidateID,
+	}
+	if err := s.db.WithContext(ctx).Create(vote).Error; err != nil {
+		return fmt.Errorf("failed to record vote (already voted?): %w", err)
+	}
+	return nil
+}
+
+// CloseElection 结束选举并按得票任命版主
+func (s *GovernanceService) CloseElection(ctx context.Context, electionID string) ([]*Moderator, error) {
+	var election Election
+	if err := s.db.WithContext(ctx).First(&election, "id = ?", electionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("election not found: %s", electionID)
+		}
+		return nil, fmt.Errorf("failed to get election: %w", err)
+	}
+	if election.Status != ElectionStatusOpen {
+		return nil, fmt.Errorf("election is already closed: %s", electionID)
+	}
+
+	// 统计得票前N名
+	type tally struct {
+		CandidateID string
+		Votes       int64
+	}
+	var tallies []tally
+	if err := s.db.WithContext(ctx).Model(&ElectionVote{}).
+		Select("candidate_id, COUNT(*) AS votes").
+		Where("election_id = ?", electionID).
+		Group("candidate_id").
+		Order("votes DESC").
+		Limit(election.Seats).
+		Scan(&tallies).Error; err != nil {
+		return nil, fmt.Errorf("failed to tally votes: %w", err)
+	}
+
+	now := time.Now()
+	var moderators []*Moderator
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, t := range tallies {
+			moderator := &Moderator{
+				ID:        uuid.NewString(),
+				Scope:     election.Scope,
+				UserID:    t.CandidateID,
+				IsActive:  true,
+				ElectedAt: now,
+			}
+			// 已是版主的用户刷新当选时间
+			var existing Moderator
+			err := tx.Where("scope = ? AND user_id = ?", election.Scope, t.CandidateID).First(&existing).Error
+			switch {
+			case err == gorm.ErrRecordNotFound:
+				if err := tx.Create(moderator).Error; err != nil {
+					return fmt.Errorf("failed to appoint moderator: %w", err)
+				}
+			case err != nil:
+				return fmt.Errorf("failed to check existing moderator: %w", err)
+			default:
+				if err := tx.Model(&existing).Updates(map[string]interface{}{
+					"is_active":  true,
+					"elected_at": now,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to renew moderator: %w", err)
+				}
+				moderator = &existing
+			}
+			moderators = append(moderators, moderator)
+		}
+
+		return tx.Model(&Election{}).Where("id = ?", electionID).Updates(map[string]interface{}{
+			"status":    ElectionStatusClosed,
+			"closed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moderators, nil
+}
+
+// ListModerators 列出范围内的在任版主
+func (s *GovernanceService) ListModerators(ctx context.Context, scope string) ([]*Moderator, error) {
+	var moderators []*Moderator
+	if err := s.db.WithContext(ctx).
+		Where("scope = ? AND is_active = ?", scope, true).
+		Find(&moderators).Error; err != nil {
+		return nil, fmt.Errorf("failed to list moderators: %w", err)
+	}
+	return moderators, nil
+}
+
+// IsModerator 判断用户是否为范围内的在任版主
+func (s *GovernanceService) IsModerator(ctx context.Context, scope, userID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&Moderator{}).
+		Where("scope = ? AND user_id = ? AND is_active = ?", scope, userID, true).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check moderator: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RecordAction 记录版主处置动作（要求操作者是在任版主）
+func (s *GovernanceService) RecordAction(ctx context.Context, action *ModerationAction) error {
+	isModerator, err := s.IsModerator(ctx, action.Scope, action.ModeratorID)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return fmt.Errorf("user %s is not an active moderator for scope %s", action.ModeratorID, action.Scope)
+	}
+
+	action.ID = uuid.NewString()
+	if err := s.db.WithContext(ctx).Create(action).Error; err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+	return nil
+}
+
+// ListActions 查询范围内的处置动作日志
+func (s *GovernanceService) ListActions(ctx context.Context, scope string, limit, offset int) ([]*ModerationAction, error) {
+	query := s.db.WithContext(ctx).Where("scope = ?", scope).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var actions []*ModerationAction
+	if err := query.Find(&actions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list moderation actions: %w", err)
+	}
+	return actions, nil
+}
+
+// ListActionsBefore 游标分页查询处置动作日志（新到旧）
+func (s *GovernanceService) ListActionsBefore(ctx context.Context, scope, cursor string, limit int) ([]*ModerationAction, string, error) {
+	anchor, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = pagination.Clamp(limit)
+
+	var actions []*ModerationAction
+	err = s.db.WithContext(ctx).
+		Where("scope = ?", scope).
+		Scopes(pagination.Before("created_at", anchor)).
+		Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&actions).Error
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list moderation actions: %w", err)
+	}
+
+	next := ""
+	if len(actions) > limit {
+		actions = actions[:limit]
+		last := actions[len(actions)-1]
+		next = pagination.Encode(last.CreatedAt, last.ID)
+	}
+	return actions, next, nil
+}
@@ -0,0 +1,152 @@
+package community
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GovernanceHandler 社区治理HTTP处理器
+type GovernanceHandler struct {
+	service *GovernanceService
+}
+
+// NewGovernanceHandler 创建社区治理处理器
+func NewGovernanceHandler(service *GovernanceService) *GovernanceHandler {
+	return &GovernanceHandler{service: service}
+}
+
+// SetupRoutes 注册社区治理相关路由
+func (h *GovernanceHandler) SetupRoutes(router gin.IRouter) {
+	governance := router.Group("/community/governance")
+	{
+		governance.POST("/elections", h.StartElection)
+		governance.POST("/elections/:id/votes", h.Vote)
+		governance.POST("/elections/:id/close", h.CloseElection)
+		governance.GET("/scopes/:scope/moderators", h.ListModerators)
+		governance.POST("/actions", h.RecordAction)
+		governance.GET("/scopes/:scope/actions", h.ListActions)
+	}
+}
+
+// StartElection 发起选举
+func (h *GovernanceHandler) StartElection(c *gin.Context) {
+	var req struct {
+		Scope        string `json:"scope" binding:"required"`
+		Seats        int    `json:"seats"`
+		DurationDays int    `json:"durationDays"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	election, err := h.service.StartElection(c.Request.Context(), req.Scope, req.Seats,
+		time.Duration(req.DurationDays)*24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, election)
+}
+
+// Vote 投票
+func (h *GovernanceHandler) Vote(c *gin.Context) {
+	var req struct {
+		VoterID     string `json:"voterId" binding:"required"`
+		CandidateID string `json:"candidateId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Vote(c.Request.Context(), c.Param("id"), req.VoterID, req.CandidateID); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "vote recorded"})
+}
+
+// CloseElection 结束选举
+func (h *GovernanceHandler) CloseElection(c *gin.Context) {
+	moderators, err := h.service.CloseElection(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"moderators": moderators})
+}
+
+// ListModerators 列出在任版主
+func (h *GovernanceHandler) ListModerators(c *gin.Context) {
+	moderators, err := h.service.ListModerators(c.Request.Context(), c.Param("scope"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"moderators": moderators})
+}
+
+// RecordAction 记录处置动作
+func (h *GovernanceHandler) RecordAction(c *gin.Context) {
+	var req struct {
+		Scope       string `json:"scope" binding:"required"`
+		ModeratorID string `json:"moderatorId" binding:"required"`
+		Action      string `json:"action" binding:"required"`
+		TargetType  string `json:"targetType"`
+		TargetID    string `json:"targetId"`
+		Reason      string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	action := &ModerationAction{
+		Scope:       req.Scope,
+		ModeratorID: req.ModeratorID,
+		Action:      req.Action,
+		TargetType:  req.TargetType,
+		TargetID:    req.TargetID,
+		Reason:      req.Reason,
+	}
+	if err := h.service.RecordAction(c.Request.Context(), action); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, action)
+}
+
+// ListActions 查询处置动作日志
+//
+// 带cursor参数时走游标分页并返回nextCursor；
+// 旧的limit/offset路径保留以兼容既有调用方。
+func (h *GovernanceHandler) ListActions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if cursor, hasCursor := c.GetQuery("cursor"); hasCursor {
+		actions, next, err := h.service.ListActionsBefore(c.Request.Context(), c.Param("scope"), cursor, limit)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "invalid cursor") {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"actions": actions, "count": len(actions), "nextCursor": next})
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	actions, err := h.service.ListActions(c.Request.Context(), c.Param("scope"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"actions": actions, "count": len(actions)})
+}
@@ -0,0 +1,326 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 审核裁决
+const (
+	VerdictPass   = "pass"
+	VerdictReview = "review"
+	VerdictBlock  = "block"
+)
+
+// 审核队列状态
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// ModerationRule 关键词/正则审核规则
+type ModerationRule struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Pattern string `json:"pattern" gorm:"not null"`
+	// IsRegex true时Pattern按正则匹配，否则做大小写不敏感子串匹配
+	IsRegex bool `json:"isRegex" gorm:"default:false"`
+	// Action 命中后动作：review送审、block直接拦截
+	Action    string    `json:"action" gorm:"type:varchar(16);not null"`
+	CreatedBy string    `json:"createdBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ModerationRule) TableName() string {
+	return "cs_moderation_rules"
+}
+
+// ReviewItem 待复核内容
+type ReviewItem struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// ContentType post / comment / message
+	ContentType string `json:"contentType" gorm:"type:varchar(16);not null"`
+	ContentID   string `json:"contentId" gorm:"index;type:varchar(36);not null"`
+	AuthorID    string `json:"authorId" gorm:"type:varchar(36)"`
+	Content     string `json:"content" gorm:"type:text"`
+	// Reason 进入队列的原因（命中规则或AI评分）
+	Reason       string    `json:"reason"`
+	Status       string    `json:"status" gorm:"type:varchar(16);index;default:'pending'"`
+	ReviewedBy   string    `json:"reviewedBy,omitempty" gorm:"type:varchar(36)"`
+	ReviewReason string    `json:"reviewReason,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ReviewItem) TableName() string {
+	return "cs_review_queue"
+}
+
+// ReviewAudit 审核操作留痕
+type ReviewAudit struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	ItemID    string    `json:"itemId" gorm:"index;type:varchar(36);not null"`
+	Action    string    `json:"action" gorm:"type:varchar(16);not null"` // enqueued / approved / rejected
+	ActorID   string    `json:"actorId" gorm:"type:varchar(36)"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ReviewAudit) TableName() string {
+	return "cs_review_audit"
+}
+
+// ReviewService 审核管线服务
+//
+// 规则优先于AI：命中block规则直接拦截；命中review规则或
+// AI评分超过阈值的内容进入人工复核队列。
+type ReviewService struct {
+	db *gorm.DB
+	// classifier AI毒性分类，可为nil
+	classifier      ToxicityScorer
+	reviewThreshold float64
+
+	// feedback 复核裁决回调（垃圾检测阈值调优等），可为nil
+	feedback ReviewFeedbackSink
+
+	mu    sync.RWMutex
+	rules []*ModerationRule // 内存缓存，变更后重建
+}
+
+// ReviewFeedbackSink 复核裁决的反馈接收方
+type ReviewFeedbackSink interface {
+	ReviewFeedback(ctx context.Context, item *ReviewItem, decision string)
+}
+
+// SetFeedbackSink 接入复核裁决反馈
+func (s *ReviewService) SetFeedbackSink(sink ReviewFeedbackSink) {
+	s.feedback = sink
+}
+
+// NewReviewService 创建审核管线服务
+func NewReviewService(db *gorm.DB) (*ReviewService, error) {
+	if err := db.AutoMigrate(&ModerationRule{}, &ReviewItem{}, &ReviewAudit{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate review tables: %w", err)
+	}
+	s := &ReviewService{db: db, reviewThreshold: DefaultToxicityPolicy().ReviewThreshold}
+	if err := s.reloadRules(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetClassifier 接入AI分类
+func (s *ReviewService) SetClassifier(classifier ToxicityScorer) {
+	s.classifier = classifier
+}
+
+// Screen 内容发布前的审核检查
+//
+// 返回裁决与原因；verdict为review时已将内容加入复核队列。
+func (s *ReviewService) Screen(ctx context.Context, contentType, contentID, authorID, content string) (string, string, error) {
+	s.mu.RLock()
+	rules := s.rules
+	s.mu.RUnlock()
+
+	for _, rule := range rules {
+		if !ruleMatches(rule, content) {
+			continue
+		}
+		reason := fmt.Sprintf("matched rule %s", rule.ID)
+		if rule.Action == VerdictBlock {
+			return VerdictBlock, reason, nil
+		}
+		if err := s.enqueue(ctx, contentType, contentID, authorID, content, reason); err != nil {
+			return "", "", err
+		}
+		return VerdictReview, reason, nil
+	}
+
+	if s.classifier != nil {
+		score, err := s.classifier.ScoreText(ctx, contentType, contentID, content)
+		if err == nil && score >= s.reviewThreshold {
+			reason := fmt.Sprintf("ai toxicity score %.2f", score)
+			if err := s.enqueue(ctx, contentType, contentID, authorID, content, reason); err != nil {
+				return "", "", err
+			}
+			return VerdictReview, reason, nil
+		}
+	}
+	return VerdictPass, "", nil
+}
+
+// AddRule 新增审核规则
+func (s *ReviewService) AddRule(ctx context.Context, pattern string, isRegex bool, action, createdBy string) (*ModerationRule, error) {
+	if action != VerdictReview && action != VerdictBlock {
+		return nil, fmt.Errorf("unsupported rule action: %s", action)
+	}
+	if isRegex {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		}
+	}
+
+	rule := &ModerationRule{
+		ID:        uuid.NewString(),
+		Pattern:   pattern,
+		IsRegex:   isRegex,
+		Action:    action,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+	if err := s.reloadRules(ctx); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// RemoveRule 删除审核规则
+func (s *ReviewService) RemoveRule(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).Delete(&ModerationRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("rule not found: %s", id)
+	}
+	return s.reloadRules(ctx)
+}
+
+// ListRules 列出审核规则
+func (s *ReviewService) ListRules(ctx context.Context) ([]*ModerationRule, error) {
+	var rules []*ModerationRule
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ListPending 列出待复核内容
+func (s *ReviewService) ListPending(ctx context.Context, limit, offset int) ([]*ReviewItem, error) {
+	query := s.db.WithContext(ctx).Where("status = ?", ReviewPending).Order("created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var items []*ReviewItem
+	if err := query.Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pending items: %w", err)
+	}
+	return items, nil
+}
+
+// PendingCount 当前待复核队列深度
+func (s *ReviewService) PendingCount(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&ReviewItem{}).
+		Where("status = ?", ReviewPending).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count pending items: %w", err)
+	}
+	return count, nil
+}
+
+// Resolve 复核裁决（approve/reject），写入审计记录
+func (s *ReviewService) Resolve(ctx context.Context, itemID, moderatorID, decision, reason string) (*ReviewItem, error) {
+	if decision != ReviewApproved && decision != ReviewRejected {
+		return nil, fmt.Errorf("unsupported decision: %s", decision)
+	}
+
+	var item ReviewItem
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&item, "id = ?", itemID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("review item not found: %s", itemID)
+			}
+			return fmt.Errorf("failed to get review item: %w", err)
+		}
+		if item.Status != ReviewPending {
+			return fmt.Errorf("review item already resolved: %s", item.Status)
+		}
+
+		item.Status = decision
+		item.ReviewedBy = moderatorID
+		item.ReviewReason = reason
+		if err := tx.Save(&item).Error; err != nil {
+			return fmt.Errorf("failed to resolve review item: %w", err)
+		}
+		audit := &ReviewAudit{ItemID: item.ID, Action: decision, ActorID: moderatorID, Reason: reason}
+		return tx.Create(audit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.feedback != nil {
+		s.feedback.ReviewFeedback(ctx, &item, decision)
+	}
+	return &item, nil
+}
+
+// AuditTrail 查询某条内容的审核留痕
+func (s *ReviewService) AuditTrail(ctx context.Context, itemID string) ([]*ReviewAudit, error) {
+	var entries []*ReviewAudit
+	err := s.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit trail: %w", err)
+	}
+	return entries, nil
+}
+
+// enqueue 加入复核队列并留痕
+func (s *ReviewService) enqueue(ctx context.Context, contentType, contentID, authorID, content, reason string) error {
+	item := &ReviewItem{
+		ID:          uuid.NewString(),
+		ContentType: contentType,
+		ContentID:   contentID,
+		AuthorID:    authorID,
+		Content:     content,
+		Reason:      reason,
+		Status:      ReviewPending,
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return fmt.Errorf("failed to enqueue review item: %w", err)
+		}
+		audit := &ReviewAudit{ItemID: item.ID, Action: "enqueued", Reason: reason}
+		return tx.Create(audit).Error
+	})
+}
+
+// reloadRules 重建规则内存缓存
+func (s *ReviewService) reloadRules(ctx context.Context) error {
+	rules, err := s.ListRules(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
+
+// ruleMatches 判断内容是否命中规则
+func ruleMatches(rule *ModerationRule, content string) bool {
+	if rule.IsRegex {
+		matched, err := regexp.MatchString(rule.Pattern, content)
+		return err == nil && matched
+	}
+	return strings.Contains(strings.ToLower(content), strings.ToLower(rule.Pattern))
+}
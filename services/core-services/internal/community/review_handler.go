@@ -0,0 +1,161 @@
+package community
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler 审核管线HTTP处理器
+type ReviewHandler struct {
+	service *ReviewService
+}
+
+// NewReviewHandler 创建审核管线处理器
+func NewReviewHandler(service *ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// SetupRoutes 注册审核管线相关路由
+func (h *ReviewHandler) SetupRoutes(router gin.IRouter) {
+	moderation := router.Group("/community/moderation")
+	{
+		moderation.POST("/rules", h.AddRule)
+		moderation.GET("/rules", h.ListRules)
+		moderation.DELETE("/rules/:ruleId", h.RemoveRule)
+		moderation.GET("/queue", h.ListPending)
+		moderation.POST("/queue/:itemId/approve", h.Approve)
+		moderation.POST("/queue/:itemId/reject", h.Reject)
+		moderation.GET("/queue/:itemId/audit", h.AuditTrail)
+		moderation.POST("/screen", h.Screen)
+	}
+}
+
+// AddRule 新增审核规则
+func (h *ReviewHandler) AddRule(c *gin.Context) {
+	var req struct {
+		Pattern   string `json:"pattern" binding:"required"`
+		IsRegex   bool   `json:"isRegex"`
+		Action    string `json:"action" binding:"required,oneof=review block"`
+		CreatedBy string `json:"createdBy"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.AddRule(c.Request.Context(), req.Pattern, req.IsRegex, req.Action, req.CreatedBy)
+	if err != nil {
+		c.JSON(statusForReviewError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// ListRules 列出审核规则
+func (h *ReviewHandler) ListRules(c *gin.Context) {
+	rules, err := h.service.ListRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "count": len(rules)})
+}
+
+// RemoveRule 删除审核规则
+func (h *ReviewHandler) RemoveRule(c *gin.Context) {
+	if err := h.service.RemoveRule(c.Request.Context(), c.Param("ruleId")); err != nil {
+		c.JSON(statusForReviewError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "rule removed"})
+}
+
+// ListPending 列出待复核内容
+func (h *ReviewHandler) ListPending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	items, err := h.service.ListPending(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "count": len(items)})
+}
+
+// Approve 复核通过
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	h.resolve(c, ReviewApproved)
+}
+
+// Reject 复核拒绝
+func (h *ReviewHandler) Reject(c *gin.Context) {
+	h.resolve(c, ReviewRejected)
+}
+
+// resolve 复核裁决的公共处理
+func (h *ReviewHandler) resolve(c *gin.Context, decision string) {
+	var req struct {
+		ModeratorID string `json:"moderatorId" binding:"required"`
+		Reason      string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.Resolve(c.Request.Context(), c.Param("itemId"), req.ModeratorID, decision, req.Reason)
+	if err != nil {
+		c.JSON(statusForReviewError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// AuditTrail 查询审核留痕
+func (h *ReviewHandler) AuditTrail(c *gin.Context) {
+	entries, err := h.service.AuditTrail(c.Request.Context(), c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audit": entries, "count": len(entries)})
+}
+
+// Screen 手动送检一段内容（聊天消息等外部管线调用）
+func (h *ReviewHandler) Screen(c *gin.Context) {
+	var req struct {
+		ContentType string `json:"contentType" binding:"required,oneof=post comment message"`
+		ContentID   string `json:"contentId" binding:"required"`
+		AuthorID    string `json:"authorId"`
+		Content     string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	verdict, reason, err := h.service.Screen(c.Request.Context(), req.ContentType, req.ContentID, req.AuthorID, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"verdict": verdict, "reason": reason})
+}
+
+// statusForReviewError 把服务层错误映射为HTTP状态码
+func statusForReviewError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already resolved"):
+		return http.StatusConflict
+	case strings.Contains(message, "unsupported"), strings.Contains(message, "invalid"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
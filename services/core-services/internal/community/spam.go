@@ -0,0 +1,318 @@
+package community
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 垃圾评分权重与阈值边界
+const (
+	spamWeightAccountAge  = 0.20
+	spamWeightVelocity    = 0.25
+	spamWeightLinkDensity = 0.20
+	spamWeightDuplicate   = 0.25
+	spamWeightClassifier  = 0.10
+
+	// spamVelocityWindow 发帖速率统计窗口
+	spamVelocityWindow = 10 * time.Minute
+	// spamVelocityLimit 窗口内达到该数量视为最高速率风险
+	spamVelocityLimit = 10
+	// spamDuplicateWindow 重复内容检测窗口
+	spamDuplicateWindow = 24 * time.Hour
+
+	// spamThresholdMin / spamThresholdMax 反馈调整的阈值边界
+	spamThresholdMin = 0.40
+	spamThresholdMax = 0.90
+	// spamFeedbackStep 每次复核反馈的阈值调整步长
+	spamFeedbackStep = 0.02
+)
+
+// spamLinkPattern 内容中的链接
+var spamLinkPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// SpamPolicyRecord 垃圾检测阈值（单行，随复核反馈自动调整）
+type SpamPolicyRecord struct {
+	ID uint `json:"-" gorm:"primaryKey"`
+	// ReviewThreshold 总分达到该值进入影子队列复核
+	ReviewThreshold float64   `json:"reviewThreshold" gorm:"default:0.6"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (SpamPolicyRecord) TableName() string {
+	return "cs_spam_policies"
+}
+
+// SpamFingerprint 内容指纹，用于重复内容与发帖速率统计
+type SpamFingerprint struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	AuthorID    string    `json:"authorId" gorm:"index;type:varchar(36);not null"`
+	ContentHash string    `json:"contentHash" gorm:"index;type:varchar(64);not null"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (SpamFingerprint) TableName() string {
+	return "cs_spam_fingerprints"
+}
+
+// SpamSignals 垃圾评分各信号明细
+type SpamSignals struct {
+	AccountAge  float64 `json:"accountAge"`
+	Velocity    float64 `json:"velocity"`
+	LinkDensity float64 `json:"linkDensity"`
+	Duplicate   float64 `json:"duplicate"`
+	Classifier  float64 `json:"classifier"`
+	Total       float64 `json:"total"`
+}
+
+// SpamService 垃圾检测服务
+//
+// 综合账号年龄、发帖速率、链接密度、重复内容与AI分类打分；
+// 高分内容进入影子复核队列（不拦截发布），复核裁决反馈
+// 用于自动微调阈值。
+type SpamService struct {
+	db *gorm.DB
+	// classifier AI分类，可为nil
+	classifier ToxicityScorer
+	review     *ReviewService
+
+	mu              sync.RWMutex
+	reviewThreshold float64
+}
+
+// NewSpamService 创建垃圾检测服务
+func NewSpamService(db *gorm.DB, review *ReviewService) (*SpamService, error) {
+	if err := db.AutoMigrate(&SpamPolicyRecord{}, &SpamFingerprint{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate spam tables: %w", err)
+	}
+
+	var policy SpamPolicyRecord
+	err := db.First(&policy).Error
+	if err == gorm.ErrRecordNotFound {
+		policy = SpamPolicyRecord{ReviewThreshold: 0.6}
+		if err := db.Create(&policy).Error; err != nil {
+			return nil, fmt.Errorf("failed to create spam policy: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load spam policy: %w", err)
+	}
+
+	return &SpamService{db: db, review: review, reviewThreshold: policy.ReviewThreshold}, nil
+}
+
+// SetClassifier 接入AI分类
+func (s *SpamService) SetClassifier(classifier ToxicityScorer) {
+	s.classifier = classifier
+}
+
+// Score 对一段内容打分并记录指纹
+func (s *SpamService) Score(ctx context.Context, authorID, content string) (*SpamSignals, error) {
+	signals := &SpamSignals{}
+
+	age, err := s.accountAgeScore(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	signals.AccountAge = age
+
+	velocity, err := s.velocityScore(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	signals.Velocity = velocity
+
+	signals.LinkDensity = linkDensityScore(content)
+
+	hash := contentHash(content)
+	duplicate, err := s.duplicateScore(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	signals.Duplicate = duplicate
+
+	// AI分类不可用时该信号记0，不影响其它启发式
+	if s.classifier != nil {
+		if score, err := s.classifier.ScoreText(ctx, "comment", "", content); err == nil {
+			signals.Classifier = score
+		}
+	}
+
+	signals.Total = spamWeightAccountAge*signals.AccountAge +
+		spamWeightVelocity*signals.Velocity +
+		spamWeightLinkDensity*signals.LinkDensity +
+		spamWeightDuplicate*signals.Duplicate +
+		spamWeightClassifier*signals.Classifier
+
+	// 指纹写入失败不影响评分结果
+	_ = s.db.WithContext(ctx).Create(&SpamFingerprint{AuthorID: authorID, ContentHash: hash}).Error
+
+	return signals, nil
+}
+
+// CheckSpam 发布管线的垃圾检查（由讨论串服务调用）
+//
+// 影子队列语义：高分内容仍正常发布，但标记待复核并入队。
+func (s *SpamService) CheckSpam(ctx context.Context, contentType, contentID, authorID, content string) (string, string, error) {
+	signals, err := s.Score(ctx, authorID, content)
+	if err != nil {
+		return "", "", err
+	}
+
+	if signals.Total >= s.ReviewThreshold() {
+		reason := fmt.Sprintf("spam score %.2f", signals.Total)
+		if s.review != nil {
+			if err := s.review.enqueue(ctx, contentType, contentID, authorID, content, reason); err != nil {
+				return "", "", err
+			}
+		}
+		return VerdictReview, reason, nil
+	}
+	return VerdictPass, "", nil
+}
+
+// ReviewFeedback 复核裁决反馈，微调阈值
+//
+// 复核通过说明误报，上调阈值放宽检测；复核拒绝说明命中，
+// 下调阈值收紧检测。仅处理垃圾检测入队的条目。
+func (s *SpamService) ReviewFeedback(ctx context.Context, item *ReviewItem, decision string) {
+	if !strings.HasPrefix(item.Reason, "spam score") {
+		return
+	}
+
+	s.mu.Lock()
+	threshold := s.reviewThreshold
+	switch decision {
+	case ReviewApproved:
+		threshold += spamFeedbackStep
+	case ReviewRejected:
+		threshold -= spamFeedbackStep
+	}
+	if threshold < spamThresholdMin {
+		threshold = spamThresholdMin
+	}
+	if threshold > spamThresholdMax {
+		threshold = spamThresholdMax
+	}
+	s.reviewThreshold = threshold
+	s.mu.Unlock()
+
+	_ = s.db.WithContext(ctx).Model(&SpamPolicyRecord{}).
+		Where("1 = 1").
+		Update("review_threshold", threshold).Error
+}
+
+// ReviewThreshold 当前生效的复核阈值
+func (s *SpamService) ReviewThreshold() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.reviewThreshold
+}
+
+// SetReviewThreshold 手动设置复核阈值（管理操作）
+func (s *SpamService) SetReviewThreshold(ctx context.Context, threshold float64) error {
+	if threshold < spamThresholdMin || threshold > spamThresholdMax {
+		return fmt.Errorf("invalid threshold: must be between %.2f and %.2f", spamThresholdMin, spamThresholdMax)
+	}
+
+	s.mu.Lock()
+	s.reviewThreshold = threshold
+	s.mu.Unlock()
+
+	err := s.db.WithContext(ctx).Model(&SpamPolicyRecord{}).
+		Where("1 = 1").
+		Update("review_threshold", threshold).Error
+	if err != nil {
+		return fmt.Errorf("failed to update spam policy: %w", err)
+	}
+	return nil
+}
+
+// accountAgeScore 账号越新风险越高，以首条指纹时间近似账号年龄
+func (s *SpamService) accountAgeScore(ctx context.Context, authorID string) (float64, error) {
+	var first SpamFingerprint
+	err := s.db.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Order("created_at ASC").
+		First(&first).Error
+	if err == gorm.ErrRecordNotFound {
+		return 1.0, nil // 首次发言，风险最高
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to check account age: %w", err)
+	}
+
+	age := time.Since(first.CreatedAt)
+	switch {
+	case age < 24*time.Hour:
+		return 0.7, nil
+	case age < 7*24*time.Hour:
+		return 0.3, nil
+	}
+	return 0, nil
+}
+
+// velocityScore 窗口内发帖数归一化为0-1
+func (s *SpamService) velocityScore(ctx context.Context, authorID string) (float64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&SpamFingerprint{}).
+		Where("author_id = ? AND created_at >= ?", authorID, time.Now().Add(-spamVelocityWindow)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recent posts: %w", err)
+	}
+
+	score := float64(count) / spamVelocityLimit
+	if score > 1 {
+		score = 1
+	}
+	return score, nil
+}
+
+// duplicateScore 窗口内出现过相同内容记满分
+func (s *SpamService) duplicateScore(ctx context.Context, hash string) (float64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&SpamFingerprint{}).
+		Where("content_hash = ? AND created_at >= ?", hash, time.Now().Add(-spamDuplicateWindow)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to check duplicate content: %w", err)
+	}
+	if count > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// linkDensityScore 链接数相对词数的占比归一化为0-1
+func linkDensityScore(content string) float64 {
+	links := len(spamLinkPattern.FindAllString(content, -1))
+	if links == 0 {
+		return 0
+	}
+	words := len(strings.Fields(content))
+	if words == 0 {
+		words = 1
+	}
+
+	score := float64(links) * 4 / float64(words)
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
+
+// contentHash 内容指纹（忽略首尾空白与大小写）
+func contentHash(content string) string {
+	normalized := strings.ToLower(strings.TrimSpace(content))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,73 @@
+package community
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpamHandler 垃圾检测HTTP处理器
+type SpamHandler struct {
+	service *SpamService
+}
+
+// NewSpamHandler 创建垃圾检测处理器
+func NewSpamHandler(service *SpamService) *SpamHandler {
+	return &SpamHandler{service: service}
+}
+
+// SetupRoutes 注册垃圾检测相关路由
+func (h *SpamHandler) SetupRoutes(router gin.IRouter) {
+	spam := router.Group("/community/spam")
+	{
+		spam.POST("/score", h.Score)
+		spam.GET("/policy", h.GetPolicy)
+		spam.PUT("/policy", h.SetPolicy)
+	}
+}
+
+// Score 对内容打分（外部管线调用）
+func (h *SpamHandler) Score(c *gin.Context) {
+	var req struct {
+		AuthorID string `json:"authorId" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	signals, err := h.service.Score(c.Request.Context(), req.AuthorID, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, signals)
+}
+
+// GetPolicy 查询当前阈值
+func (h *SpamHandler) GetPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"reviewThreshold": h.service.ReviewThreshold()})
+}
+
+// SetPolicy 手动设置阈值
+func (h *SpamHandler) SetPolicy(c *gin.Context) {
+	var req struct {
+		ReviewThreshold float64 `json:"reviewThreshold" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetReviewThreshold(c.Request.Context(), req.ReviewThreshold); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "spam policy updated"})
+}
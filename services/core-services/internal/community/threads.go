@@ -0,0 +1,436 @@
+package community
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/pagination"
+)
+
+// Thread 讨论串
+type Thread struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Scope    string `json:"scope" gorm:"index;not null"` // 房间或板块ID
+	Title    string `json:"title" gorm:"not null"`
+	AuthorID string `json:"authorId" gorm:"type:varchar(36)"`
+	// IsLocked 锁定后不再接受新回复
+	IsLocked   bool   `json:"isLocked" gorm:"default:false"`
+	LockReason string `json:"lockReason,omitempty"`
+	// SlowModeSeconds 慢速模式下同一用户两次发言的最小间隔，0表示未开启
+	SlowModeSeconds int       `json:"slowModeSeconds" gorm:"default:0"`
+	PostCount       int64     `json:"postCount" gorm:"default:0"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Thread) TableName() string {
+	return "cs_threads"
+}
+
+// ThreadPost 讨论串回复
+type ThreadPost struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ThreadID string `json:"threadId" gorm:"index;type:varchar(36);not null"`
+	AuthorID string `json:"authorId" gorm:"index;type:varchar(36);not null"`
+	Content  string `json:"content" gorm:"type:text;not null"`
+	// ToxicityScore AI毒性评分，-1表示未评分
+	ToxicityScore float64 `json:"toxicityScore" gorm:"default:-1"`
+	// NeedsReview 评分超过复核阈值时标记，由版主复核
+	NeedsReview bool      `json:"needsReview" gorm:"default:false"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (ThreadPost) TableName() string {
+	return "cs_thread_posts"
+}
+
+// HeatPolicy 过热讨论的自动处置策略
+type HeatPolicy struct {
+	// Window 活跃度统计窗口
+	Window time.Duration
+	// SlowModeThreshold 窗口内回复数达到该值时自动开启慢速模式
+	SlowModeThreshold int64
+	// SlowModeSeconds 自动开启的慢速间隔
+	SlowModeSeconds int
+	// LockThreshold 窗口内回复数达到该值时自动锁定
+	LockThreshold int64
+}
+
+// DefaultHeatPolicy 默认过热策略
+func DefaultHeatPolicy() HeatPolicy {
+	return HeatPolicy{
+		Window:            5 * time.Minute,
+		SlowModeThreshold: 30,
+		SlowModeSeconds:   60,
+		LockThreshold:     100,
+	}
+}
+
+// ThreadService 讨论串服务，带过热自动降温
+type ThreadService struct {
+	db             *gorm.DB
+	policy         HeatPolicy
+	toxicity       ToxicityScorer // 可为nil，表示未接入毒性评分
+	toxicityPolicy ToxicityPolicy
+	// publish 社区事件发布回调（webhook等），可为nil
+	publish func(event string, payload interface{})
+	// screener 发布前的内容审核检查，可为nil
+	screener ContentScreener
+	// spam 垃圾检测（影子队列，不拦截发布），可为nil
+	spam SpamDetector
+}
+
+// SpamDetector 发布管线的垃圾检测接口（由垃圾检测服务实现）
+type SpamDetector interface {
+	CheckSpam(ctx context.Context, contentType, contentID, authorID, content string) (verdict, reason string, err error)
+}
+
+// SetSpamDetector 接入垃圾检测
+func (s *ThreadService) SetSpamDetector(detector SpamDetector) {
+	s.spam = detector
+}
+
+// ContentScreener 内容发布前的审核检查接口（由审核管线实现）
+type ContentScreener interface {
+	Screen(ctx context.Context, contentType, contentID, authorID, content string) (verdict, reason string, err error)
+}
+
+// SetScreener 接入审核管线，发布的内容先过规则与AI检查
+func (s *ThreadService) SetScreener(screener ContentScreener) {
+	s.screener = screener
+}
+
+// SetEventPublisher 接入社区事件发布（post.created、comment.created）
+func (s *ThreadService) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// emit 发布事件，未接入时为空操作
+func (s *ThreadService) emit(event string, payload interface{}) {
+	if s.publish != nil {
+		s.publish(event, payload)
+	}
+}
+
+// NewThreadService 创建讨论串服务
+func NewThreadService(db *gorm.DB, policy HeatPolicy) (*ThreadService, error) {
+	if err := db.AutoMigrate(&Thread{}, &ThreadPost{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate thread tables: %w", err)
+	}
+	if policy.Window <= 0 {
+		policy = DefaultHeatPolicy()
+	}
+	return &ThreadService{db: db, policy: policy, toxicityPolicy: DefaultToxicityPolicy()}, nil
+}
+
+// SetToxicityScorer 接入AI毒性评分，评论发布前按策略拦截或标记
+func (s *ThreadService) SetToxicityScorer(scorer ToxicityScorer, policy ToxicityPolicy) {
+	s.toxicity = scorer
+	if policy.BlockThreshold > 0 {
+		s.toxicityPolicy = policy
+	}
+}
+
+// CreateThread 创建讨论串
+func (s *ThreadService) CreateThread(ctx context.Context, scope, title, authorID string) (*Thread, error) {
+	thread := &Thread{
+		ID:       uuid.NewString(),
+		Scope:    scope,
+		Title:    title,
+		AuthorID: authorID,
+	}
+	if err := s.db.WithContext(ctx).Create(thread).Error; err != nil {
+		return nil, fmt.Errorf("failed to create thread: %w", err)
+	}
+	s.emit("post.created", thread)
+	return thread, nil
+}
+
+// GetThread 获取讨论串
+func (s *ThreadService) GetThread(ctx context.Context, id string) (*Thread, error) {
+	var thread Thread
+	if err := s.db.WithContext(ctx).First(&thread, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("thread not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	return &thread, nil
+}
+
+// GetThreadsByIDs 批量获取讨论串，供GraphQL等聚合查询避免N+1
+func (s *ThreadService) GetThreadsByIDs(ctx context.Context, ids []string) (map[string]*Thread, error) {
+	var threads []*Thread
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&threads).Error; err != nil {
+		return nil, fmt.Errorf("failed to get threads: %w", err)
+	}
+	result := make(map[string]*Thread, len(threads))
+	for _, thread := range threads {
+		result[thread.ID] = thread
+	}
+	return result, nil
+}
+
+// AddPost 发表回复
+//
+// 锁定的讨论串拒绝回复；慢速模式下限制同一用户的发言间隔；
+// 成功写入后检查活跃度并按策略自动开启慢速模式或锁定。
+func (s *ThreadService) AddPost(ctx context.Context, threadID, authorID, content string) (*ThreadPost, error) {
+	thread, err := s.GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread.IsLocked {
+		return nil, fmt.Errorf("thread is locked: %s", thread.LockReason)
+	}
+
+	// 慢速模式检查
+	if thread.SlowModeSeconds > 0 {
+		var last ThreadPost
+		err := s.db.WithContext(ctx).
+			Where("thread_id = ? AND author_id = ?", threadID, authorID).
+			Order("created_at DESC").
+			First(&last).Error
+		if err == nil {
+			wait := time.Duration(thread.SlowModeSeconds)*time.Second - time.Since(last.CreatedAt)
+			if wait > 0 {
+				return nil, fmt.Errorf("slow mode active, retry in %d seconds", int(wait.Seconds())+1)
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to check slow mode: %w", err)
+		}
+	}
+
+	post := &ThreadPost{
+		ID:            uuid.NewString(),
+		ThreadID:      threadID,
+		AuthorID:      authorID,
+		Content:       content,
+		ToxicityScore: -1,
+	}
+
+	// 审核管线：命中block规则直接拒绝，送审内容标记待复核
+	if s.screener != nil {
+		verdict, _, err := s.screener.Screen(ctx, "comment", post.ID, authorID, content)
+		if err == nil {
+			switch verdict {
+			case VerdictBlock:
+				return nil, fmt.Errorf("post rejected: content blocked by moderation rules")
+			case VerdictReview:
+				post.NeedsReview = true
+			}
+		}
+	}
+
+	// 垃圾检测：影子队列语义，高分内容照常发布但标记待复核
+	if s.spam != nil {
+		verdict, _, err := s.spam.CheckSpam(ctx, "comment", post.ID, authorID, content)
+		if err == nil && verdict == VerdictReview {
+			post.NeedsReview = true
+		}
+	}
+
+	// 毒性评分：超过拦截阈值拒绝，超过复核阈值标记；
+	// 评分服务不可用时放行，不影响正常发言
+	if s.toxicity != nil {
+		score, err := s.toxicity.ScoreText(ctx, "comment", post.ID, content)
+		if err == nil {
+			if score >= s.toxicityPolicy.BlockThreshold {
+				return nil, fmt.Errorf("post rejected: content flagged as toxic")
+			}
+			post.ToxicityScore = score
+			post.NeedsReview = post.NeedsReview || score >= s.toxicityPolicy.ReviewThreshold
+		}
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return fmt.Errorf("failed to create post: %w", err)
+		}
+		return tx.Model(&Thread{}).Where("id = ?", threadID).
+			UpdateColumn("post_count", gorm.Expr("post_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 活跃度检查失败不影响发帖结果
+	_ = s.applyHeatPolicy(ctx, thread)
+
+	s.emit("comment.created", post)
+	return post, nil
+}
+
+// applyHeatPolicy 按窗口内回复量自动降温
+func (s *ThreadService) applyHeatPolicy(ctx context.Context, thread *Thread) error {
+	var recent int64
+	err := s.db.WithContext(ctx).Model(&ThreadPost{}).
+		Where("thread_id = ? AND created_at >= ?", thread.ID, time.Now().Add(-s.policy.Window)).
+		Count(&recent).Error
+	if err != nil {
+		return fmt.Errorf("failed to count recent posts: %w", err)
+	}
+
+	updates := map[string]interface{}{}
+	if s.policy.LockThreshold > 0 && recent >= s.policy.LockThreshold && !thread.IsLocked {
+		updates["is_locked"] = true
+		updates["lock_reason"] = "automatically locked due to heated discussion"
+	} else if s.policy.SlowModeThreshold > 0 && recent >= s.policy.SlowModeThreshold && thread.SlowModeSeconds == 0 {
+		updates["slow_mode_seconds"] = s.policy.SlowModeSeconds
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&Thread{}).Where("id = ?", thread.ID).Updates(updates).Error
+}
+
+// SetLock 手动锁定/解锁讨论串（版主操作）
+func (s *ThreadService) SetLock(ctx context.Context, threadID string, locked bool, reason string) error {
+	updates := map[string]interface{}{
+		"is_locked":   locked,
+		"lock_reason": reason,
+	}
+	if !locked {
+		updates["lock_reason"] = ""
+	}
+	result := s.db.WithContext(ctx).Model(&Thread{}).Where("id = ?", threadID).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update thread lock: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("thread not found: %s", threadID)
+	}
+	return nil
+}
+
+// SetSlowMode 手动设置慢速模式（版主操作），0表示关闭
+func (s *ThreadService) SetSlowMode(ctx context.Context, threadID string, seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("slow mode seconds must be non-negative")
+	}
+	result := s.db.WithContext(ctx).Model(&Thread{}).
+		Where("id = ?", threadID).
+		Update("slow_mode_seconds", seconds)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update slow mode: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("thread not found: %s", threadID)
+	}
+	return nil
+}
+
+// ListPosts 列出讨论串回复
+func (s *ThreadService) ListPosts(ctx context.Context, threadID string, limit, offset int) ([]*ThreadPost, error) {
+	query := s.db.WithContext(ctx).Where("thread_id = ?", threadID).Order("created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var posts []*ThreadPost
+	if err := query.Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+	return posts, nil
+}
+
+// RecentActivityDigest 汇总时间窗内的社区动态为纯文本
+//
+// 供定时Agent等摘要场景作为数据源：按回帖量排序列出活跃
+// 讨论串及其最新回复片段。
+func (s *ThreadService) RecentActivityDigest(ctx context.Context, since time.Time, limit int) (string, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	type threadActivity struct {
+		ThreadID string
+		Count    int64
+	}
+	var activity []threadActivity
+	err := s.db.WithContext(ctx).Model(&ThreadPost{}).
+		Select("thread_id, COUNT(*) as count").
+		Where("created_at >= ?", since).
+		Group("thread_id").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&activity).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to aggregate activity: %w", err)
+	}
+	if len(activity) == 0 {
+		return "该时间段内没有社区动态。", nil
+	}
+
+	ids := make([]string, 0, len(activity))
+	for _, entry := range activity {
+		ids = append(ids, entry.ThreadID)
+	}
+	threads, err := s.GetThreadsByIDs(ctx, ids)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, entry := range activity {
+		thread, ok := threads[entry.ThreadID]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "讨论串「%s」新增%d条回复\n", thread.Title, entry.Count)
+		var latest ThreadPost
+		err := s.db.WithContext(ctx).
+			Where("thread_id = ? AND created_at >= ?", entry.ThreadID, since).
+			Order("created_at DESC").
+			First(&latest).Error
+		if err == nil {
+			content := latest.Content
+			if len(content) > 200 {
+				content = content[:200]
+			}
+			fmt.Fprintf(&b, "最新回复：%s\n", content)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// ListPostsAfter 游标分页列出讨论串回复
+//
+// 返回下一页游标；没有更多数据时游标为空。并发写入下不会
+// 漏读或重读，深翻页性能也优于offset，见internal/pagination。
+func (s *ThreadService) ListPostsAfter(ctx context.Context, threadID, cursor string, limit int) ([]*ThreadPost, string, error) {
+	anchor, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = pagination.Clamp(limit)
+
+	var posts []*ThreadPost
+	err = s.db.WithContext(ctx).
+		Where("thread_id = ?", threadID).
+		Scopes(pagination.After("created_at", anchor)).
+		Order("created_at ASC, id ASC").
+		Limit(limit + 1).
+		Find(&posts).Error
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	next := ""
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		next = pagination.Encode(last.CreatedAt, last.ID)
+	}
+	return posts, next, nil
+}
@@ -0,0 +1,151 @@
+package community
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ThreadHandler 讨论串HTTP处理器
+type ThreadHandler struct {
+	service *ThreadService
+}
+
+// NewThreadHandler 创建讨论串处理器
+func NewThreadHandler(service *ThreadService) *ThreadHandler {
+	return &ThreadHandler{service: service}
+}
+
+// SetupRoutes 注册讨论串相关路由
+func (h *ThreadHandler) SetupRoutes(router gin.IRouter) {
+	threads := router.Group("/community/threads")
+	{
+		threads.POST("", h.CreateThread)
+		threads.GET("/:id", h.GetThread)
+		threads.POST("/:id/posts", h.AddPost)
+		threads.GET("/:id/posts", h.ListPosts)
+		threads.PUT("/:id/lock", h.SetLock)
+		threads.PUT("/:id/slow-mode", h.SetSlowMode)
+	}
+}
+
+// CreateThread 创建讨论串
+func (h *ThreadHandler) CreateThread(c *gin.Context) {
+	var req struct {
+		Scope    string `json:"scope" binding:"required"`
+		Title    string `json:"title" binding:"required"`
+		AuthorID string `json:"authorId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	thread, err := h.service.CreateThread(c.Request.Context(), req.Scope, req.Title, req.AuthorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, thread)
+}
+
+// GetThread 获取讨论串
+func (h *ThreadHandler) GetThread(c *gin.Context) {
+	thread, err := h.service.GetThread(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+// AddPost 发表回复
+func (h *ThreadHandler) AddPost(c *gin.Context) {
+	var req struct {
+		AuthorID string `json:"authorId" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.service.AddPost(c.Request.Context(), c.Param("id"), req.AuthorID, req.Content)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "locked") || strings.Contains(err.Error(), "slow mode") ||
+			strings.Contains(err.Error(), "toxic") || strings.Contains(err.Error(), "blocked by moderation") {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, post)
+}
+
+// ListPosts 列出回复
+//
+// 带cursor参数时走游标分页并返回nextCursor；
+// 旧的limit/offset路径保留以兼容既有调用方。
+func (h *ThreadHandler) ListPosts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if cursor, hasCursor := c.GetQuery("cursor"); hasCursor {
+		posts, next, err := h.service.ListPostsAfter(c.Request.Context(), c.Param("id"), cursor, limit)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "invalid cursor") {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts), "nextCursor": next})
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	posts, err := h.service.ListPosts(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "count": len(posts)})
+}
+
+// SetLock 手动锁定/解锁
+func (h *ThreadHandler) SetLock(c *gin.Context) {
+	var req struct {
+		Locked bool   `json:"locked"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetLock(c.Request.Context(), c.Param("id"), req.Locked, req.Reason); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "thread lock updated"})
+}
+
+// SetSlowMode 手动设置慢速模式
+func (h *ThreadHandler) SetSlowMode(c *gin.Context) {
+	var req struct {
+		Seconds int `json:"seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetSlowMode(c.Request.Context(), c.Param("id"), req.Seconds); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "slow mode updated"})
+}
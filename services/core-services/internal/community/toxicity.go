@@ -0,0 +1,122 @@
+package community
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tracing"
+)
+
+// ToxicityScorer 文本毒性评分接口（由AI审核服务实现）
+type ToxicityScorer interface {
+	// ScoreText 返回0（无害）到1（严重）的毒性评分
+	ScoreText(ctx context.Context, source, refID, text string) (float64, error)
+}
+
+// ToxicityPolicy 毒性评分的处置阈值
+type ToxicityPolicy struct {
+	// BlockThreshold 达到该评分直接拒绝发布
+	BlockThreshold float64
+	// ReviewThreshold 达到该评分放行但标记待复核
+	ReviewThreshold float64
+}
+
+// DefaultToxicityPolicy 默认毒性处置策略
+func DefaultToxicityPolicy() ToxicityPolicy {
+	return ToxicityPolicy{
+		BlockThreshold:  0.9,
+		ReviewThreshold: 0.6,
+	}
+}
+
+// AIObserver 记录AI服务商调用指标的接口（由metrics模块实现）
+type AIObserver interface {
+	AIRequestStarted(provider string)
+	ObserveAIRequest(provider string, duration time.Duration, err error)
+}
+
+// HTTPToxicityScorer 通过HTTP调用混合API审核服务的毒性评分端点
+type HTTPToxicityScorer struct {
+	baseURL  string // 如 http://localhost:8081/api/v1
+	tenantID string
+	client   *http.Client
+	observer AIObserver // 可选
+}
+
+// NewHTTPToxicityScorer 创建HTTP毒性评分客户端
+func NewHTTPToxicityScorer(baseURL, tenantID string) *HTTPToxicityScorer {
+	return &HTTPToxicityScorer{
+		baseURL:  baseURL,
+		tenantID: tenantID,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetObserver 设置可选的调用指标观察器
+func (s *HTTPToxicityScorer) SetObserver(observer AIObserver) {
+	s.observer = observer
+}
+
+// ScoreText 调用远端毒性评分端点
+func (s *HTTPToxicityScorer) ScoreText(ctx context.Context, source, refID, text string) (score float64, err error) {
+	if s.observer != nil {
+		s.observer.AIRequestStarted("moderation")
+		start := time.Now()
+		defer func() {
+			s.observer.ObserveAIRequest("moderation", time.Since(start), err)
+		}()
+	}
+	return s.scoreText(ctx, source, refID, text)
+}
+
+// scoreText 执行实际的HTTP调用
+func (s *HTTPToxicityScorer) scoreText(ctx context.Context, source, refID, text string) (_ float64, err error) {
+	ctx, span, inject := tracing.StartClientSpan(ctx, "moderation.score_text")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	payload, err := json.Marshal(map[string]string{
+		"source": source,
+		"refId":  refID,
+		"text":   text,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal toxicity request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/moderation/tenants/%s/toxicity", s.baseURL, s.tenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build toxicity request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	inject(propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("toxicity request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("toxicity service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Score float64 `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode toxicity response: %w", err)
+	}
+	return result.Score, nil
+}
@@ -0,0 +1,191 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ctxApplied 防止路径改写重派发时重复包装
+const ctxApplied = "compression.applied"
+
+// Middleware 请求解压与响应压缩中间件
+func (s *Service) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool(ctxApplied) {
+			c.Next()
+			return
+		}
+		c.Set(ctxApplied, true)
+
+		if !s.decompressRequest(c) {
+			return
+		}
+		// WebSocket升级与显式禁压的请求不包装
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+		encoding, factory := s.pickEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			factory:        factory,
+			minBytes:       s.minBytes,
+			status:         http.StatusOK,
+		}
+		c.Writer = writer
+		c.Next()
+		writer.finish()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// decompressRequest 解开压缩的请求体，返回是否继续处理
+func (s *Service) decompressRequest(c *gin.Context) bool {
+	encoding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return true
+	case "gzip":
+		gz, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+			return false
+		}
+		c.Request.Body = io.NopCloser(io.LimitReader(gz, requestBodyLimit))
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.ContentLength = -1
+		return true
+	default:
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType,
+			gin.H{"error": "unsupported content encoding: " + encoding})
+		return false
+	}
+}
+
+// compressWriter 协商压缩的响应写入器
+//
+// 普通响应先缓冲到体积下限再决定是否压缩；流式响应
+// （text/event-stream）跳过缓冲直接压缩，Flush时同步冲刷
+// 压缩器，保证事件即时送达。
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	factory  WriterFactory
+	minBytes int
+
+	status  int
+	decided bool
+	// passthrough 判定不压缩后原样直写
+	passthrough bool
+	buf         bytes.Buffer
+	cw          flushWriter
+}
+
+// WriteHeader 暂存状态码，流式响应立即开始压缩
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	w.status = status
+	w.decide(status)
+}
+
+// Write 写入响应
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(w.status)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.cw != nil {
+		return w.cw.Write(data)
+	}
+	n, _ := w.buf.Write(data)
+	if w.buf.Len() >= w.minBytes {
+		w.start()
+	}
+	return n, nil
+}
+
+// WriteString 写入响应
+func (w *compressWriter) WriteString(data string) (int, error) {
+	return w.Write([]byte(data))
+}
+
+// Flush 冲刷压缩器与底层连接（SSE逐事件推送依赖）
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(w.status)
+	}
+	if w.cw == nil && !w.passthrough && w.buf.Len() > 0 {
+		// 主动冲刷视为流式语义，不再等体积下限
+		w.start()
+	}
+	if w.cw != nil {
+		w.cw.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide 按响应头确定压缩策略
+func (w *compressWriter) decide(status int) {
+	w.decided = true
+	header := w.Header()
+	contentType := header.Get("Content-Type")
+	switch {
+	case status == http.StatusNoContent || status == http.StatusNotModified:
+		w.passthrough = true
+	case header.Get("Content-Encoding") != "":
+		w.passthrough = true
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		// 流式响应立即压缩，体积下限不适用
+		w.start()
+	}
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// start 写响应头并切换到压缩写入
+func (w *compressWriter) start() {
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.cw = w.factory(w.ResponseWriter)
+	if w.buf.Len() > 0 {
+		w.cw.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish 请求结束时落盘：小响应原样返回，压缩流收尾
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide(w.status)
+	}
+	if w.cw != nil {
+		w.cw.Close()
+		return
+	}
+	if w.passthrough {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
@@ -0,0 +1,87 @@
+package compression
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 压缩参数
+const (
+	// defaultMinBytes 响应压缩的默认体积下限，小响应不值得压
+	defaultMinBytes = 1024
+	// requestBodyLimit 请求体解压后的上限，防解压炸弹
+	requestBodyLimit = 32 << 20
+)
+
+// flushWriter 支持中途冲刷的压缩写入器（SSE等流式响应需要）
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// WriterFactory 按编码名构造压缩写入器
+type WriterFactory func(w io.Writer) flushWriter
+
+// Service 请求解压与响应压缩
+//
+// 请求侧解开Content-Encoding: gzip的上传体（批量健康数据、
+// 埋点等移动端大载荷）；响应侧按Accept-Encoding协商压缩，
+// 小于下限的响应原样返回，text/event-stream走逐事件冲刷的
+// 流式压缩。内置gzip与deflate；br经RegisterEncoding接入，
+// 仓库暂未引入brotli实现时自动降级到gzip。
+type Service struct {
+	minBytes  int
+	logger    *zap.Logger
+	factories map[string]WriterFactory
+	// preference 协商时的编码优先级
+	preference []string
+}
+
+// NewService 创建压缩服务；minBytes<=0时使用默认下限
+func NewService(minBytes int, logger *zap.Logger) *Service {
+	if minBytes <= 0 {
+		minBytes = defaultMinBytes
+	}
+	s := &Service{
+		minBytes:   minBytes,
+		logger:     logger,
+		factories:  make(map[string]WriterFactory),
+		preference: []string{"br", "gzip", "deflate"},
+	}
+	s.RegisterEncoding("gzip", func(w io.Writer) flushWriter {
+		return gzip.NewWriter(w)
+	})
+	s.RegisterEncoding("deflate", func(w io.Writer) flushWriter {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	})
+	return s
+}
+
+// RegisterEncoding 注册一种响应编码实现
+func (s *Service) RegisterEncoding(name string, factory WriterFactory) {
+	s.factories[name] = factory
+}
+
+// pickEncoding 按Accept-Encoding选可用编码，无可用时返回空
+func (s *Service) pickEncoding(acceptEncoding string) (string, WriterFactory) {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		token := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if token != "" {
+			accepted[strings.ToLower(token)] = true
+		}
+	}
+	for _, name := range s.preference {
+		if accepted[name] {
+			if factory, ok := s.factories[name]; ok {
+				return name, factory
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// Config 核心服务配置
+type Config struct {
+	Port           string   `mapstructure:"port"`
+	Environment    string   `mapstructure:"environment"`
+	LogLevel       string   `mapstructure:"log_level"`
+	DatabaseURL    string   `mapstructure:"database_url"`
+	JWTSecret      string   `mapstructure:"jwt_secret"`
+	TraceHeader    string   `mapstructure:"trace_header"`
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// ModerationURL AI审核服务地址（hybrid-api），为空时不启用毒性评分
+	ModerationURL string `mapstructure:"moderation_url"`
+	// ModerationTenant 调用审核服务使用的租户ID
+	ModerationTenant string `mapstructure:"moderation_tenant"`
+	// TURNSecret coturn共享密钥，为空时不提供TURN凭证签发
+	TURNSecret string `mapstructure:"turn_secret"`
+	// TURNURIs TURN服务器地址列表
+	TURNURIs []string `mapstructure:"turn_uris"`
+	// ElasticsearchURL ES地址，为空时搜索功能不可用
+	ElasticsearchURL string `mapstructure:"elasticsearch_url"`
+	// SearchIndex 搜索索引名
+	SearchIndex string `mapstructure:"search_index"`
+	// UnfurlDenyHosts 链接预览拒绝抓取的域名列表
+	UnfurlDenyHosts []string `mapstructure:"unfurl_deny_hosts"`
+	// TermsGraceDays 新版条款生效后的接受宽限期（天）
+	TermsGraceDays int `mapstructure:"terms_grace_days"`
+	// WarehouseURL 数据仓库ClickHouse HTTP地址，为空时导出作业不可运行
+	WarehouseURL string `mapstructure:"warehouse_url"`
+	// OTLPEndpoint OpenTelemetry收集器HTTP地址（如 localhost:4318），为空时不导出trace
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
+	// AuditRetentionDays 审计日志保留天数，<=0时使用默认180天
+	AuditRetentionDays int `mapstructure:"audit_retention_days"`
+	// GRPCPort gRPC服务监听端口，为空时不启动gRPC
+	GRPCPort string `mapstructure:"grpc_port"`
+	// AIServiceURL AI补全服务地址，为空时AI处理端点不可用
+	AIServiceURL string `mapstructure:"ai_service_url"`
+	// TTSServiceURL 语音合成服务地址，为空时不生成朗读音频
+	TTSServiceURL string `mapstructure:"tts_service_url"`
+	// StorageEndpoint 对象存储地址（S3/MinIO/OSS），为空时附件使用本地磁盘
+	StorageEndpoint string `mapstructure:"storage_endpoint"`
+	// StorageRegion 对象存储区域
+	StorageRegion string `mapstructure:"storage_region"`
+	// StorageBucket 附件存储桶
+	StorageBucket string `mapstructure:"storage_bucket"`
+	// StorageAccessKey 对象存储访问密钥ID
+	StorageAccessKey string `mapstructure:"storage_access_key"`
+	// StorageSecretKey 对象存储私钥
+	StorageSecretKey string `mapstructure:"storage_secret_key"`
+	// AttachmentDir 本地后端的附件目录
+	AttachmentDir string `mapstructure:"attachment_dir"`
+	// ClamAVAddr clamd TCP地址（如 localhost:3310），为空时不扫描附件
+	ClamAVAddr string `mapstructure:"clamav_addr"`
+	// ScanFailOpen 扫描失败时放行（true）还是继续隔离（false）
+	ScanFailOpen bool `mapstructure:"scan_fail_open"`
+	// RequireAltText 图片附件发布前强制要求替代文本
+	RequireAltText bool `mapstructure:"require_alt_text"`
+	// TransformRulesPath 请求/响应转换规则YAML路径，为空时不启用转换
+	TransformRulesPath string `mapstructure:"transform_rules_path"`
+	// CompressMinBytes 响应压缩的体积下限，0取内置默认值
+	CompressMinBytes int `mapstructure:"compress_min_bytes"`
+	// CDNProvider 边缘缓存清除后端：cloudflare/aliyun，为空时不下发清除
+	CDNProvider string `mapstructure:"cdn_provider"`
+	// CDNBaseURL 公共站点源地址，URL型清除后端用它拼完整URL
+	CDNBaseURL string `mapstructure:"cdn_base_url"`
+	// CloudflareZoneID Cloudflare区域ID
+	CloudflareZoneID string `mapstructure:"cloudflare_zone_id"`
+	// CloudflareAPIToken Cloudflare接口令牌
+	CloudflareAPIToken string `mapstructure:"cloudflare_api_token"`
+	// AliyunAccessKeyID 阿里云访问密钥ID
+	AliyunAccessKeyID string `mapstructure:"aliyun_access_key_id"`
+	// AliyunAccessKeySecret 阿里云访问密钥
+	AliyunAccessKeySecret string `mapstructure:"aliyun_access_key_secret"`
+	// EmailDriver 邮件驱动：smtp/sendgrid/ses，为空时不发送邮件
+	EmailDriver string `mapstructure:"email_driver"`
+	// EmailFrom 发件人地址
+	EmailFrom string `mapstructure:"email_from"`
+	// SMTPAddr SMTP服务器地址（host:port）
+	SMTPAddr     string `mapstructure:"smtp_addr"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	// SendGridAPIKey SendGrid接口密钥
+	SendGridAPIKey string `mapstructure:"sendgrid_api_key"`
+	// SESRegion SES区域（如 us-east-1）
+	SESRegion    string `mapstructure:"ses_region"`
+	SESAccessKey string `mapstructure:"ses_access_key"`
+	SESSecretKey string `mapstructure:"ses_secret_key"`
+}
+
+// Load 从环境变量和配置文件加载配置
+func Load() (Config, error) {
+	var cfg Config
+
+	// 默认值
+	viper.SetDefault("port", "8082")
+	viper.SetDefault("environment", "development")
+	viper.SetDefault("log_level", "info")
+	viper.SetDefault("trace_header", "X-Trace-ID")
+	viper.SetDefault("allowed_origins", []string{"*"})
+	viper.SetDefault("moderation_tenant", "default")
+	viper.SetDefault("search_index", "taishang")
+	viper.SetDefault("terms_grace_days", 14)
+	viper.SetDefault("grpc_port", "9092")
+	viper.SetDefault("attachment_dir", "./uploads")
+
+	// 环境变量支持
+	viper.AutomaticEnv()
+	viper.SetEnvPrefix("CORE")
+
+	// 尝试读取配置文件（不存在时使用默认值）
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("./config")
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return cfg, err
+		}
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// FileUsed 返回实际读取的配置文件路径（未找到配置文件时为空）
+func FileUsed() string {
+	return viper.ConfigFileUsed()
+}
@@ -0,0 +1,101 @@
+package consent
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 同意管理HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建同意管理处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册同意管理相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	consent := router.Group("/consent")
+	{
+		consent.GET("/texts", h.ActiveTexts)
+		consent.POST("/texts", h.PublishText)
+	}
+	users := router.Group("/users/:userId/consents")
+	{
+		users.GET("", h.UserConsents)
+		users.POST("/:purpose/grant", h.Grant)
+		users.POST("/:purpose/withdraw", h.Withdraw)
+		users.GET("/export", h.ExportHistory)
+	}
+}
+
+// ActiveTexts 获取当前生效的同意文案
+func (h *Handler) ActiveTexts(c *gin.Context) {
+	texts, err := h.service.ActiveTexts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"texts": texts})
+}
+
+// PublishText 发布新版同意文案
+func (h *Handler) PublishText(c *gin.Context) {
+	var req CreateTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	text, err := h.service.PublishText(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, text)
+}
+
+// UserConsents 获取用户当前的同意状态
+func (h *Handler) UserConsents(c *gin.Context) {
+	records, err := h.service.UserConsents(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"consents": records})
+}
+
+// Grant 用户授予同意
+func (h *Handler) Grant(c *gin.Context) {
+	record, err := h.service.Grant(c.Request.Context(),
+		c.Param("userId"), c.Param("purpose"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// Withdraw 用户撤回同意
+func (h *Handler) Withdraw(c *gin.Context) {
+	record, err := h.service.Withdraw(c.Request.Context(),
+		c.Param("userId"), c.Param("purpose"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// ExportHistory 导出同意变更历史
+func (h *Handler) ExportHistory(c *gin.Context) {
+	history, err := h.service.ExportHistory(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
@@ -0,0 +1,73 @@
+package consent
+
+import (
+	"time"
+)
+
+// 同意用途
+const (
+	PurposeAnalytics  = "analytics"   // 行为分析跟踪
+	PurposeAITraining = "ai_training" // 用户内容用于AI训练
+	PurposeMarketing  = "marketing"   // 营销邮件
+)
+
+// KnownPurposes 当前支持的全部同意用途
+var KnownPurposes = []string{PurposeAnalytics, PurposeAITraining, PurposeMarketing}
+
+// ConsentText 带版本的同意文案
+//
+// 每次文案变更发布新版本，用户的同意记录与其当时看到的版本绑定，
+// 以满足合规要求。
+type ConsentText struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Purpose   string    `json:"purpose" gorm:"uniqueIndex:idx_purpose_version;not null"`
+	Version   int       `json:"version" gorm:"uniqueIndex:idx_purpose_version;not null"`
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	IsActive  bool      `json:"isActive" gorm:"default:true"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ConsentText) TableName() string {
+	return "cs_consent_texts"
+}
+
+// ConsentRecord 用户当前的同意状态
+type ConsentRecord struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      string     `json:"userId" gorm:"type:varchar(36);uniqueIndex:idx_user_purpose;not null"`
+	Purpose     string     `json:"purpose" gorm:"uniqueIndex:idx_user_purpose;not null"`
+	TextVersion int        `json:"textVersion" gorm:"not null"`
+	Granted     bool       `json:"granted"`
+	GrantedAt   *time.Time `json:"grantedAt,omitempty"`
+	WithdrawnAt *time.Time `json:"withdrawnAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ConsentRecord) TableName() string {
+	return "cs_consent_records"
+}
+
+// ConsentHistory 同意状态变更历史（只追加，供合规导出）
+type ConsentHistory struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      string    `json:"userId" gorm:"type:varchar(36);index;not null"`
+	Purpose     string    `json:"purpose" gorm:"not null"`
+	TextVersion int       `json:"textVersion" gorm:"not null"`
+	Action      string    `json:"action" gorm:"not null"` // granted / withdrawn
+	IPAddress   string    `json:"ipAddress"`
+	UserAgent   string    `json:"userAgent"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ConsentHistory) TableName() string {
+	return "cs_consent_history"
+}
+
+// CreateTextRequest 发布同意文案请求
+type CreateTextRequest struct {
+	Purpose string `json:"purpose" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
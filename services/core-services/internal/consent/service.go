@@ -0,0 +1,203 @@
+package consent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Checker 同意检查接口
+//
+// 分析采集、AI管线等执法点只依赖该接口，不依赖具体实现。
+type Checker interface {
+	// Allows 判断用户是否对指定用途给予了有效同意
+	Allows(ctx context.Context, userID, purpose string) (bool, error)
+}
+
+// Service 同意管理服务
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService 创建同意管理服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&ConsentText{}, &ConsentRecord{}, &ConsentHistory{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate consent tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// PublishText 发布新版同意文案（旧版本自动停用）
+func (s *Service) PublishText(ctx context.Context, req *CreateTextRequest) (*ConsentText, error) {
+	if !isKnownPurpose(req.Purpose) {
+		return nil, fmt.Errorf("unknown consent purpose: %s", req.Purpose)
+	}
+
+	var text *ConsentText
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&ConsentText{}).
+			Where("purpose = ?", req.Purpose).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return fmt.Errorf("failed to get max text version: %w", err)
+		}
+
+		if err := tx.Model(&ConsentText{}).
+			Where("purpose = ? AND is_active = ?", req.Purpose, true).
+			Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate old texts: %w", err)
+		}
+
+		text = &ConsentText{
+			Purpose:  req.Purpose,
+			Version:  maxVersion + 1,
+			Content:  req.Content,
+			IsActive: true,
+		}
+		if err := tx.Create(text).Error; err != nil {
+			return fmt.Errorf("failed to create consent text: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return text, nil
+}
+
+// ActiveTexts 获取当前生效的全部同意文案
+func (s *Service) ActiveTexts(ctx context.Context) ([]*ConsentText, error) {
+	var texts []*ConsentText
+	if err := s.db.WithContext(ctx).Where("is_active = ?", true).Find(&texts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list consent texts: %w", err)
+	}
+	return texts, nil
+}
+
+// Grant 记录用户同意
+func (s *Service) Grant(ctx context.Context, userID, purpose, ip, userAgent string) (*ConsentRecord, error) {
+	return s.setConsent(ctx, userID, purpose, true, ip, userAgent)
+}
+
+// Withdraw 记录用户撤回同意
+func (s *Service) Withdraw(ctx context.Context, userID, purpose, ip, userAgent string) (*ConsentRecord, error) {
+	return s.setConsent(ctx, userID, purpose, false, ip, userAgent)
+}
+
+// setConsent 写入同意状态并追加历史
+func (s *Service) setConsent(ctx context.Context, userID, purpose string, granted bool, ip, userAgent string) (*ConsentRecord, error) {
+	if !isKnownPurpose(purpose) {
+		return nil, fmt.Errorf("unknown consent purpose: %s", purpose)
+	}
+
+	var activeText ConsentText
+	if err := s.db.WithContext(ctx).
+		Where("purpose = ? AND is_active = ?", purpose, true).
+		First(&activeText).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("no active consent text for purpose: %s", purpose)
+		}
+		return nil, fmt.Errorf("failed to get active consent text: %w", err)
+	}
+
+	now := time.Now()
+	record := &ConsentRecord{
+		UserID:      userID,
+		Purpose:     purpose,
+		TextVersion: activeText.Version,
+		Granted:     granted,
+	}
+	action := "withdrawn"
+	if granted {
+		record.GrantedAt = &now
+		action = "granted"
+	} else {
+		record.WithdrawnAt = &now
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing ConsentRecord
+		err := tx.Where("user_id = ? AND purpose = ?", userID, purpose).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			if err := tx.Create(record).Error; err != nil {
+				return fmt.Errorf("failed to create consent record: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to get consent record: %w", err)
+		default:
+			record.ID = existing.ID
+			if err := tx.Save(record).Error; err != nil {
+				return fmt.Errorf("failed to update consent record: %w", err)
+			}
+		}
+
+		history := &ConsentHistory{
+			UserID:      userID,
+			Purpose:     purpose,
+			TextVersion: activeText.Version,
+			Action:      action,
+			IPAddress:   ip,
+			UserAgent:   userAgent,
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return fmt.Errorf("failed to append consent history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// UserConsents 获取用户当前的全部同意状态
+func (s *Service) UserConsents(ctx context.Context, userID string) ([]*ConsentRecord, error) {
+	var records []*ConsentRecord
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user consents: %w", err)
+	}
+	return records, nil
+}
+
+// ExportHistory 导出用户的同意变更历史（合规用）
+func (s *Service) ExportHistory(ctx context.Context, userID string) ([]*ConsentHistory, error) {
+	var history []*ConsentHistory
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to export consent history: %w", err)
+	}
+	return history, nil
+}
+
+// Allows 判断用户是否对指定用途给予了有效同意
+//
+// 没有任何记录时视为未同意（默认拒绝）。
+func (s *Service) Allows(ctx context.Context, userID, purpose string) (bool, error) {
+	var record ConsentRecord
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check consent: %w", err)
+	}
+	return record.Granted, nil
+}
+
+// isKnownPurpose 判断是否为已支持的同意用途
+func isKnownPurpose(purpose string) bool {
+	for _, p := range KnownPurposes {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package consent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 法律文档类型
+const (
+	DocTypeTerms   = "tos"     // 服务条款
+	DocTypePrivacy = "privacy" // 隐私政策
+)
+
+// KnownDocTypes 当前支持的法律文档类型
+var KnownDocTypes = []string{DocTypeTerms, DocTypePrivacy}
+
+// TermsVersion 带版本的法律文档
+//
+// 每次条款变更发布新版本，用户须在宽限期内重新接受最新版本。
+type TermsVersion struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	DocType     string    `json:"docType" gorm:"uniqueIndex:idx_doc_version;not null"`
+	Version     int       `json:"version" gorm:"uniqueIndex:idx_doc_version;not null"`
+	Content     string    `json:"content" gorm:"type:text;not null"`
+	Summary     string    `json:"summary" gorm:"type:text"` // 面向用户的变更摘要
+	EffectiveAt time.Time `json:"effectiveAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TermsVersion) TableName() string {
+	return "cs_terms_versions"
+}
+
+// TermsAcceptance 用户对某一版本的接受记录（只追加）
+type TermsAcceptance struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"userId" gorm:"type:varchar(36);uniqueIndex:idx_user_doc_version;not null"`
+	DocType   string    `json:"docType" gorm:"uniqueIndex:idx_user_doc_version;not null"`
+	Version   int       `json:"version" gorm:"uniqueIndex:idx_user_doc_version;not null"`
+	IPAddress string    `json:"ipAddress"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TermsAcceptance) TableName() string {
+	return "cs_terms_acceptances"
+}
+
+// PublishTermsRequest 发布法律文档版本请求
+type PublishTermsRequest struct {
+	DocType     string     `json:"docType" binding:"required"`
+	Content     string     `json:"content" binding:"required"`
+	Summary     string     `json:"summary"`
+	EffectiveAt *time.Time `json:"effectiveAt"`
+}
+
+// TermsStatus 用户对单个文档类型的接受状态
+type TermsStatus struct {
+	DocType          string     `json:"docType"`
+	LatestVersion    int        `json:"latestVersion"`
+	AcceptedVersion  int        `json:"acceptedVersion"`
+	AcceptanceNeeded bool       `json:"acceptanceNeeded"`
+	GraceDeadline    *time.Time `json:"graceDeadline,omitempty"` // 超过该时间未接受将被拦截
+}
+
+// TermsService 法律文档版本与接受管理服务
+type TermsService struct {
+	db          *gorm.DB
+	gracePeriod time.Duration
+}
+
+// NewTermsService 创建法律文档服务，gracePeriod为新版本生效后的接受宽限期
+func NewTermsService(db *gorm.DB, gracePeriod time.Duration) (*TermsService, error) {
+	if err := db.AutoMigrate(&TermsVersion{}, &TermsAcceptance{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate terms tables: %w", err)
+	}
+	return &TermsService{db: db, gracePeriod: gracePeriod}, nil
+}
+
+// Publish 发布新版本文档，版本号自动递增
+func (s *TermsService) Publish(ctx context.Context, req *PublishTermsRequest) (*TermsVersion, error) {
+	if !isKnownDocType(req.DocType) {
+		return nil, fmt.Errorf("unknown document type: %s", req.DocType)
+	}
+
+	effective := time.Now()
+	if req.EffectiveAt != nil {
+		effective = *req.EffectiveAt
+	}
+
+	var version *TermsVersion
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&TermsVersion{}).
+			Where("doc_type = ?", req.DocType).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return fmt.Errorf("failed to get max terms version: %w", err)
+		}
+
+		version = &TermsVersion{
+			DocType:     req.DocType,
+			Version:     maxVersion + 1,
+			Content:     req.Content,
+			Summary:     req.Summary,
+			EffectiveAt: effective,
+		}
+		if err := tx.Create(version).Error; err != nil {
+			return fmt.Errorf("failed to create terms version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Latest 获取某文档类型当前已生效的最新版本
+func (s *TermsService) Latest(ctx context.Context, docType string) (*TermsVersion, error) {
+	var version TermsVersion
+	err := s.db.WithContext(ctx).
+		Where("doc_type = ? AND effective_at <= ?", docType, time.Now()).
+		Order("version DESC").
+		First(&version).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest terms version: %w", err)
+	}
+	return &version, nil
+}
+
+// Accept 记录用户对指定版本的接受
+func (s *TermsService) Accept(ctx context.Context, userID, docType string, version int, ip, userAgent string) error {
+	if !isKnownDocType(docType) {
+		return fmt.Errorf("unknown document type: %s", docType)
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&TermsVersion{}).
+		Where("doc_type = ? AND version = ?", docType, version).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check terms version: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("terms version not found: %s v%d", docType, version)
+	}
+
+	acceptance := &TermsAcceptance{
+		UserID:    userID,
+		DocType:   docType,
+		Version:   version,
+		IPAddress: ip,
+		UserAgent: userAgent,
+	}
+	// 重复接受同一版本是幂等的
+	err := s.db.WithContext(ctx).Create(acceptance).Error
+	if err != nil && s.db.WithContext(ctx).
+		Where("user_id = ? AND doc_type = ? AND version = ?", userID, docType, version).
+		First(&TermsAcceptance{}).Error == nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record acceptance: %w", err)
+	}
+	return nil
+}
+
+// UserStatus 返回用户对全部文档类型的接受状态
+func (s *TermsService) UserStatus(ctx context.Context, userID string) ([]*TermsStatus, error) {
+	statuses := make([]*TermsStatus, 0, len(KnownDocTypes))
+	for _, docType := range KnownDocTypes {
+		status, err := s.docStatus(ctx, userID, docType)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// AcceptanceOverdue 判断用户是否存在超过宽限期仍未接受的文档
+func (s *TermsService) AcceptanceOverdue(ctx context.Context, userID string) (bool, error) {
+	statuses, err := s.UserStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, status := range statuses {
+		if status.AcceptanceNeeded && status.GraceDeadline != nil && now.After(*status.GraceDeadline) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// docStatus 计算用户对单个文档类型的接受状态
+func (s *TermsService) docStatus(ctx context.Context, userID, docType string) (*TermsStatus, error) {
+	status := &TermsStatus{DocType: docType}
+
+	latest, err := s.Latest(ctx, docType)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return status, nil
+	}
+	status.LatestVersion = latest.Version
+
+	var accepted TermsAcceptance
+	err = s.db.WithContext(ctx).
+		Where("user_id = ? AND doc_type = ?", userID, docType).
+		Order("version DESC").
+		First(&accepted).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get acceptance: %w", err)
+	}
+	if err == nil {
+		status.AcceptedVersion = accepted.Version
+	}
+
+	if status.AcceptedVersion < status.LatestVersion {
+		status.AcceptanceNeeded = true
+		deadline := latest.EffectiveAt.Add(s.gracePeriod)
+		status.GraceDeadline = &deadline
+	}
+	return status, nil
+}
+
+// isKnownDocType 判断文档类型是否受支持
+func isKnownDocType(docType string) bool {
+	for _, known := range KnownDocTypes {
+		if known == docType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package consent
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TermsHandler 法律文档HTTP处理器
+type TermsHandler struct {
+	service *TermsService
+}
+
+// NewTermsHandler 创建法律文档处理器
+func NewTermsHandler(service *TermsService) *TermsHandler {
+	return &TermsHandler{service: service}
+}
+
+// SetupRoutes 注册法律文档相关路由
+func (h *TermsHandler) SetupRoutes(router gin.IRouter) {
+	terms := router.Group("/terms")
+	{
+		terms.GET("/:docType/latest", h.Latest)
+		terms.POST("/versions", h.Publish)
+	}
+	users := router.Group("/users/:userId/terms")
+	{
+		users.GET("/status", h.UserStatus)
+		users.POST("/accept", h.Accept)
+	}
+}
+
+// Middleware 返回检查条款接受状态的中间件
+//
+// 网关在X-User-ID中注入用户身份；宽限期内未接受最新版本的请求
+// 以X-Terms-Outdated响应头提示前端，超过宽限期后直接拦截。
+// 条款接口本身不拦截，否则用户无法完成接受。
+func (h *TermsHandler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+		if userID == "" || strings.Contains(c.FullPath(), "/terms") {
+			c.Next()
+			return
+		}
+
+		statuses, err := h.service.UserStatus(c.Request.Context(), userID)
+		if err != nil {
+			// 状态检查失败不阻断业务请求
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		for _, status := range statuses {
+			if !status.AcceptanceNeeded {
+				continue
+			}
+			if status.GraceDeadline != nil && now.After(*status.GraceDeadline) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error":    "latest terms must be accepted before continuing",
+					"docType":  status.DocType,
+					"version":  status.LatestVersion,
+					"deadline": status.GraceDeadline,
+				})
+				return
+			}
+			c.Header("X-Terms-Outdated", status.DocType)
+		}
+		c.Next()
+	}
+}
+
+// Latest 获取最新生效版本
+func (h *TermsHandler) Latest(c *gin.Context) {
+	version, err := h.service.Latest(c.Request.Context(), c.Param("docType"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if version == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no published version"})
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// Publish 发布新版本
+func (h *TermsHandler) Publish(c *gin.Context) {
+	var req PublishTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := h.service.Publish(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, version)
+}
+
+// UserStatus 获取用户的接受状态
+func (h *TermsHandler) UserStatus(c *gin.Context) {
+	statuses, err := h.service.UserStatus(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses})
+}
+
+// Accept 记录用户接受
+func (h *TermsHandler) Accept(c *gin.Context) {
+	var req struct {
+		DocType string `json:"docType" binding:"required"`
+		Version int    `json:"version" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.Accept(c.Request.Context(), c.Param("userId"), req.DocType, req.Version,
+		c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "unknown") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "accepted"})
+}
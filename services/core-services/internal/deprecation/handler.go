@@ -0,0 +1,81 @@
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 弃用注册表HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建弃用注册表处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册弃用报表相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/admin/deprecations", h.List)
+	router.GET("/admin/deprecations/report", h.Report)
+}
+
+// Middleware 返回为弃用端点补充响应头并统计用量的中间件
+func (h *Handler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := h.service.Lookup(c.Request.Method, c.FullPath())
+		if info == nil {
+			c.Next()
+			return
+		}
+
+		// RFC 8594风格的弃用响应头
+		c.Header("Deprecation", "true")
+		if info.Sunset != nil {
+			c.Header("Sunset", info.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if info.Replacement != "" {
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", info.Replacement))
+		}
+
+		// 统计失败不影响请求本身
+		_ = h.service.RecordUsage(c.Request.Context(),
+			info.Method, info.Route, clientIdentifier(c))
+
+		c.Next()
+	}
+}
+
+// clientIdentifier 确定调用方标识：API密钥 > 网关注入的用户 > IP
+func clientIdentifier(c *gin.Context) string {
+	if keyID, ok := c.Get("api_key_id"); ok {
+		if id, ok := keyID.(string); ok && id != "" {
+			return "key:" + id
+		}
+	}
+	if userID := strings.TrimSpace(c.GetHeader("X-User-ID")); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// List 列出已注册的弃用端点
+func (h *Handler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"deprecations": h.service.List()})
+}
+
+// Report 输出按客户端聚合的使用报表
+func (h *Handler) Report(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
+	report, err := h.service.Report(c.Request.Context(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
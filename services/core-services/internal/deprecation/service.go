@@ -0,0 +1,141 @@
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Info 单个端点的弃用信息
+type Info struct {
+	Method      string     `json:"method"`
+	Route       string     `json:"route"` // gin路由模板，如 /api/v1/threads/:id
+	Replacement string     `json:"replacement,omitempty"`
+	Sunset      *time.Time `json:"sunset,omitempty"` // 计划下线时间
+	Note        string     `json:"note,omitempty"`
+}
+
+// Usage 按客户端与天聚合的弃用端点调用量
+type Usage struct {
+	ID       uint      `json:"-" gorm:"primaryKey"`
+	Method   string    `json:"method" gorm:"type:varchar(8);uniqueIndex:idx_dep_usage;not null"`
+	Route    string    `json:"route" gorm:"type:varchar(191);uniqueIndex:idx_dep_usage;not null"`
+	ClientID string    `json:"clientId" gorm:"type:varchar(64);uniqueIndex:idx_dep_usage;not null"`
+	Day      time.Time `json:"day" gorm:"type:date;uniqueIndex:idx_dep_usage;not null"`
+	Count    int64     `json:"count" gorm:"default:0"`
+}
+
+// TableName 指定表名
+func (Usage) TableName() string {
+	return "cs_deprecated_usage"
+}
+
+// Service 端点弃用注册表
+//
+// 路由在启动时注册弃用信息；中间件为命中的请求自动补充
+// Deprecation/Sunset/Link响应头，并按客户端聚合调用量，
+// 供迁移报表使用。
+type Service struct {
+	db *gorm.DB
+
+	mu      sync.RWMutex
+	entries map[string]*Info // method+" "+route
+}
+
+// NewService 创建弃用注册表
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Usage{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate deprecation tables: %w", err)
+	}
+	return &Service{
+		db:      db,
+		entries: make(map[string]*Info),
+	}, nil
+}
+
+// Register 标记一个端点为已弃用
+func (s *Service) Register(info Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[info.Method+" "+info.Route] = &info
+}
+
+// Lookup 查询端点的弃用信息，未弃用时返回nil
+func (s *Service) Lookup(method, route string) *Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entries[method+" "+route]
+}
+
+// List 列出全部已注册的弃用端点
+func (s *Service) List() []*Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := make([]*Info, 0, len(s.entries))
+	for _, info := range s.entries {
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// RecordUsage 记录一次弃用端点调用（按天/客户端聚合）
+func (s *Service) RecordUsage(ctx context.Context, method, route, clientID string) error {
+	day := time.Now().Truncate(24 * time.Hour)
+	usage := &Usage{Method: method, Route: route, ClientID: clientID, Day: day, Count: 1}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "method"}, {Name: "route"}, {Name: "client_id"}, {Name: "day"},
+		},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count": gorm.Expr("cs_deprecated_usage.count + 1"),
+		}),
+	}).Create(usage).Error
+}
+
+// Report 聚合各弃用端点的客户端使用情况
+func (s *Service) Report(ctx context.Context, days int) ([]map[string]interface{}, error) {
+	if days <= 0 || days > 90 {
+		days = 30
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	var rows []struct {
+		Method   string
+		Route    string
+		ClientID string
+		Total    int64
+		LastDay  time.Time
+	}
+	err := s.db.WithContext(ctx).Model(&Usage{}).
+		Select("method, route, client_id, SUM(count) AS total, MAX(day) AS last_day").
+		Where("day >= ?", since).
+		Group("method, route, client_id").
+		Order("total DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to build deprecation report: %w", err)
+	}
+
+	report := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		entry := map[string]interface{}{
+			"method":   row.Method,
+			"route":    row.Route,
+			"clientId": row.ClientID,
+			"total":    row.Total,
+			"lastDay":  row.LastDay.Format("2006-01-02"),
+		}
+		if info := s.Lookup(row.Method, row.Route); info != nil {
+			entry["replacement"] = info.Replacement
+			if info.Sunset != nil {
+				entry["sunset"] = info.Sunset
+			}
+		}
+		report = append(report, entry)
+	}
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package digest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 摘要HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建摘要处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册摘要相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/digest/preferences", h.GetPreference)
+	router.PUT("/digest/preferences", h.Subscribe)
+	// 退订链接来自邮件，不要求认证
+	router.GET("/digest/unsubscribe/:token", h.Unsubscribe)
+}
+
+// GetPreference 读取当前用户的摘要偏好
+func (h *Handler) GetPreference(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	pref, err := h.service.GetPreference(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForDigestError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// Subscribe 创建或更新当前用户的摘要偏好
+func (h *Handler) Subscribe(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Subscribe(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForDigestError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Unsubscribe 通过邮件中的令牌退订
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	if err := h.service.Unsubscribe(c.Request.Context(), c.Param("token")); err != nil {
+		c.JSON(statusForDigestError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已退订摘要邮件"})
+}
+
+// statusForDigestError 根据错误内容映射HTTP状态码
+func statusForDigestError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "must be"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
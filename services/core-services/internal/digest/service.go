@@ -0,0 +1,289 @@
+package digest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 摘要频率
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+	FrequencyOff    = "off"
+)
+
+// Preference 每用户的摘要订阅偏好
+//
+// 邮件中的退订链接携带未认证的长期令牌，与其他令牌一致
+// 只存哈希。
+type Preference struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	Email  string `json:"email" gorm:"type:varchar(255);not null"`
+	// Frequency daily/weekly/off
+	Frequency string `json:"frequency" gorm:"type:varchar(16);not null"`
+	// Hour 发送的本地小时（0-23）
+	Hour       int        `json:"hour"`
+	NextSendAt *time.Time `json:"nextSendAt,omitempty" gorm:"index"`
+	TokenHash  string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Preference) TableName() string {
+	return "cs_digest_preferences"
+}
+
+// Service 活动摘要服务
+//
+// 按偏好聚合每个用户的任务与社区动态，经邮件模块发送；
+// 数据源与发送函数由main接线，避免跨包依赖。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	// taskSource 取用户的任务摘要条目
+	taskSource func(ctx context.Context, userID string) ([]string, error)
+	// communitySource 取社区动态摘要文本
+	communitySource func(ctx context.Context) (string, error)
+	// send 发送摘要邮件（接线到email模块的digest模板）
+	send func(ctx context.Context, to, title string, items []string) error
+	// locationFor 取用户本地时区（接线到时间偏好模块），
+	// 发送小时按用户本地时间换算
+	locationFor func(ctx context.Context, userID string) *time.Location
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建摘要服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Preference{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate digest tables: %w", err)
+	}
+	return &Service{db: db, logger: logger, stopCh: make(chan struct{})}, nil
+}
+
+// SetTaskSource 接线任务摘要数据源
+func (s *Service) SetTaskSource(source func(ctx context.Context, userID string) ([]string, error)) {
+	s.taskSource = source
+}
+
+// SetCommunitySource 接线社区动态数据源
+func (s *Service) SetCommunitySource(source func(ctx context.Context) (string, error)) {
+	s.communitySource = source
+}
+
+// SetSender 接线邮件发送
+func (s *Service) SetSender(send func(ctx context.Context, to, title string, items []string) error) {
+	s.send = send
+}
+
+// SetLocationResolver 接线用户时区解析
+func (s *Service) SetLocationResolver(locationFor func(ctx context.Context, userID string) *time.Location) {
+	s.locationFor = locationFor
+}
+
+// userLocation 取用户本地时区，未接线时用服务器时区
+func (s *Service) userLocation(ctx context.Context, userID string) *time.Location {
+	if s.locationFor != nil {
+		if loc := s.locationFor(ctx, userID); loc != nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// SubscribeRequest 订阅/修改偏好的参数
+type SubscribeRequest struct {
+	Email     string `json:"email" binding:"required"`
+	Frequency string `json:"frequency" binding:"required"`
+	Hour      int    `json:"hour"`
+}
+
+// SubscribeResult 订阅结果，退订链接只在此返回一次
+type SubscribeResult struct {
+	Preference     *Preference `json:"preference"`
+	UnsubscribeURL string      `json:"unsubscribeUrl"`
+}
+
+// Subscribe 创建或更新用户的摘要偏好
+func (s *Service) Subscribe(ctx context.Context, userID string, req *SubscribeRequest) (*SubscribeResult, error) {
+	switch req.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyOff:
+	default:
+		return nil, fmt.Errorf("invalid frequency: %s", req.Frequency)
+	}
+	if req.Hour < 0 || req.Hour > 23 {
+		return nil, fmt.Errorf("hour must be in [0, 23]")
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate unsubscribe token: %w", err)
+	}
+	token := "dig_" + hex.EncodeToString(raw)
+	hash := sha256.Sum256([]byte(token))
+
+	pref := &Preference{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Frequency: req.Frequency,
+		Hour:      req.Hour,
+		TokenHash: hex.EncodeToString(hash[:]),
+	}
+	if req.Frequency != FrequencyOff {
+		next := nextSendTime(time.Now().In(s.userLocation(ctx, userID)), req.Frequency, req.Hour)
+		pref.NextSendAt = &next
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Preference{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		return tx.Create(pref).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save digest preference: %w", err)
+	}
+	return &SubscribeResult{
+		Preference:     pref,
+		UnsubscribeURL: "/api/v1/digest/unsubscribe/" + token,
+	}, nil
+}
+
+// GetPreference 读取用户偏好
+func (s *Service) GetPreference(ctx context.Context, userID string) (*Preference, error) {
+	var pref Preference
+	err := s.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("digest preference not found for user %s", userID)
+		}
+		return nil, fmt.Errorf("failed to get digest preference: %w", err)
+	}
+	return &pref, nil
+}
+
+// Unsubscribe 通过邮件中的令牌退订
+func (s *Service) Unsubscribe(ctx context.Context, token string) error {
+	hash := sha256.Sum256([]byte(token))
+	result := s.db.WithContext(ctx).Model(&Preference{}).
+		Where("token_hash = ?", hex.EncodeToString(hash[:])).
+		Updates(map[string]interface{}{"frequency": FrequencyOff, "next_send_at": nil})
+	if result.Error != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("unsubscribe token not found")
+	}
+	return nil
+}
+
+// Start 启动发送循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.sendDue(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止发送循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// sendDue 为所有到期用户生成并发送摘要
+func (s *Service) sendDue(ctx context.Context) {
+	var due []*Preference
+	err := s.db.WithContext(ctx).
+		Where("frequency <> ? AND next_send_at <= ?", FrequencyOff, time.Now()).
+		Limit(200).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due digests", zap.Error(err))
+		return
+	}
+	for _, pref := range due {
+		if err := s.sendOne(ctx, pref); err != nil {
+			s.logger.Error("failed to send digest",
+				zap.String("userId", pref.UserID), zap.Error(err))
+		}
+		next := nextSendTime(time.Now().In(s.userLocation(ctx, pref.UserID)), pref.Frequency, pref.Hour)
+		s.db.WithContext(ctx).Model(&Preference{}).
+			Where("id = ?", pref.ID).
+			Update("next_send_at", next)
+	}
+}
+
+// sendOne 聚合单个用户的摘要并发送，无内容时跳过
+func (s *Service) sendOne(ctx context.Context, pref *Preference) error {
+	var items []string
+	if s.taskSource != nil {
+		taskItems, err := s.taskSource(ctx, pref.UserID)
+		if err != nil {
+			s.logger.Warn("task source failed", zap.Error(err))
+		} else {
+			items = append(items, taskItems...)
+		}
+	}
+	if s.communitySource != nil {
+		community, err := s.communitySource(ctx)
+		if err != nil {
+			s.logger.Warn("community source failed", zap.Error(err))
+		} else if community != "" {
+			for _, line := range strings.Split(strings.TrimSpace(community), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					items = append(items, line)
+				}
+			}
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if s.send == nil {
+		return fmt.Errorf("digest sender is not wired")
+	}
+	title := "每日摘要"
+	if pref.Frequency == FrequencyWeekly {
+		title = "每周摘要"
+	}
+	return s.send(ctx, pref.Email, title, items)
+}
+
+// nextSendTime 计算下一个发送时间点
+//
+// now应已换算到用户本地时区，hour按该时区的本地小时对齐。
+func nextSendTime(now time.Time, frequency string, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if frequency == FrequencyWeekly {
+		// 对齐到下一个周一
+		for next.Weekday() != time.Monday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
@@ -0,0 +1,216 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Driver 邮件发送驱动，由SMTP/SendGrid/SES实现
+type Driver interface {
+	// Send 发送一封HTML邮件
+	Send(ctx context.Context, from, to, subject, htmlBody string) error
+}
+
+// SMTPDriver 标准SMTP发送
+type SMTPDriver struct {
+	addr     string // host:port
+	username string
+	password string
+}
+
+// NewSMTPDriver 创建SMTP驱动
+func NewSMTPDriver(addr, username, password string) *SMTPDriver {
+	return &SMTPDriver{addr: addr, username: username, password: password}
+}
+
+// Send 通过SMTP发送
+func (d *SMTPDriver) Send(ctx context.Context, from, to, subject, htmlBody string) error {
+	var msg bytes.Buffer
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(htmlBody)
+
+	var auth smtp.Auth
+	if d.username != "" {
+		host := d.addr
+		if idx := strings.LastIndex(host, ":"); idx >= 0 {
+			host = host[:idx]
+		}
+		auth = smtp.PlainAuth("", d.username, d.password, host)
+	}
+	if err := smtp.SendMail(d.addr, auth, from, []string{to}, msg.Bytes()); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return nil
+}
+
+// SendGridDriver 通过SendGrid v3 API发送
+type SendGridDriver struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewSendGridDriver 创建SendGrid驱动
+func NewSendGridDriver(apiKey string) *SendGridDriver {
+	return &SendGridDriver{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send 调用SendGrid mail/send接口
+func (d *SendGridDriver) Send(ctx context.Context, from, to, subject, htmlBody string) error {
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": to}}},
+		},
+		"from":    map[string]string{"email": from},
+		"subject": subject,
+		"content": []map[string]string{
+			{"type": "text/html", "value": htmlBody},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://api.sendgrid.com/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sendgrid returned %d: %s", resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+// SESDriver 通过Amazon SES v2 API发送（SigV4头签名）
+type SESDriver struct {
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewSESDriver 创建SES驱动
+func NewSESDriver(region, accessKey, secretKey string) *SESDriver {
+	return &SESDriver{
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send 调用SES outbound-emails接口
+func (d *SESDriver) Send(ctx context.Context, from, to, subject, htmlBody string) error {
+	payload := map[string]interface{}{
+		"FromEmailAddress": from,
+		"Destination":      map[string][]string{"ToAddresses": {to}},
+		"Content": map[string]interface{}{
+			"Simple": map[string]interface{}{
+				"Subject": map[string]string{"Data": subject, "Charset": "UTF-8"},
+				"Body": map[string]interface{}{
+					"Html": map[string]string{"Data": htmlBody, "Charset": "UTF-8"},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	host := "email." + d.region + ".amazonaws.com"
+	path := "/v2/email/outbound-emails"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://"+host+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	d.signV4(req, host, path, body)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ses returned %d: %s", resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+// signV4 为SES请求附加SigV4 Authorization头
+func (d *SESDriver) signV4(req *http.Request, host, path string, body []byte) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	scope := shortDate + "/" + d.region + "/ses/aws4_request"
+
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("Host", host)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPost,
+		path,
+		"",
+		"host:" + host,
+		"x-amz-date:" + amzDate,
+		"",
+		"host;x-amz-date",
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+d.secretKey), shortDate)
+	key = hmacSHA256(key, d.region)
+	key = hmacSHA256(key, "ses")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-date, Signature=%s",
+		d.accessKey, scope, signature))
+}
+
+// hmacSHA256 HMAC-SHA256简写
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,134 @@
+package email
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 邮件HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建邮件处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册邮件相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/email/send", h.Send)
+	router.GET("/email/queue", h.Queue)
+	router.GET("/email/templates", h.Templates)
+	router.GET("/email/suppressions", h.Suppressions)
+	router.POST("/email/suppressions", h.Suppress)
+	router.DELETE("/email/suppressions/:address", h.Unsuppress)
+	// 投递服务商的退信回调
+	router.POST("/email/bounces", h.Bounce)
+}
+
+// Send 渲染模板并入队发送
+func (h *Handler) Send(c *gin.Context) {
+	var req struct {
+		To       string                 `json:"to" binding:"required"`
+		Template string                 `json:"template" binding:"required"`
+		Data     map[string]interface{} `json:"data"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	queued, err := h.service.Enqueue(c.Request.Context(), req.To, req.Template, req.Data)
+	if err != nil {
+		c.JSON(statusForEmailError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, queued)
+}
+
+// Queue 查询发送队列
+func (h *Handler) Queue(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	queued, err := h.service.Queue(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"queue": queued})
+}
+
+// Templates 列出内置模板名
+func (h *Handler) Templates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"templates": TemplateNames()})
+}
+
+// Suppressions 列出抑制名单
+func (h *Handler) Suppressions(c *gin.Context) {
+	entries, err := h.service.Suppressions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suppressions": entries})
+}
+
+// Suppress 手动加入抑制名单
+func (h *Handler) Suppress(c *gin.Context) {
+	var req struct {
+		Address string `json:"address" binding:"required"`
+		Reason  string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.Suppress(c.Request.Context(), req.Address, req.Reason)
+	if err != nil {
+		c.JSON(statusForEmailError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// Unsuppress 移出抑制名单
+func (h *Handler) Unsuppress(c *gin.Context) {
+	if err := h.service.Unsuppress(c.Request.Context(), c.Param("address")); err != nil {
+		c.JSON(statusForEmailError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Bounce 处理退信回调，把退信地址加入抑制名单
+func (h *Handler) Bounce(c *gin.Context) {
+	var req struct {
+		Address string `json:"address" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.Suppress(c.Request.Context(), req.Address, "bounce")
+	if err != nil {
+		c.JSON(statusForEmailError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// statusForEmailError 根据错误内容映射HTTP状态码
+func statusForEmailError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "unknown"),
+		strings.Contains(msg, "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
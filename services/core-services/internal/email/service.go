@@ -0,0 +1,306 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 发送状态
+const (
+	SendPending    = "pending"
+	SendSuccess    = "success"
+	SendFailed     = "failed"
+	SendSuppressed = "suppressed"
+)
+
+// maxSendAttempts 单封邮件的最大发送尝试次数
+const maxSendAttempts = 5
+
+// sendBackoff 第n次失败后的重试间隔（指数退避）
+var sendBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// QueuedEmail 发送队列中的一封邮件
+type QueuedEmail struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	To       string `json:"to" gorm:"type:varchar(255);index;not null"`
+	Template string `json:"template" gorm:"type:varchar(32);not null"`
+	// Data 模板渲染数据
+	Data     datatypes.JSON `json:"data" gorm:"type:jsonb"`
+	Status   string         `json:"status" gorm:"type:varchar(16);index;default:'pending'"`
+	Attempts int            `json:"attempts" gorm:"default:0"`
+	// NextRetryAt 下次尝试时间，成功或放弃后清空
+	NextRetryAt *time.Time `json:"nextRetryAt,omitempty" gorm:"index"`
+	LastError   string     `json:"lastError,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (QueuedEmail) TableName() string {
+	return "cs_email_queue"
+}
+
+// Suppression 抑制名单条目（退信、投诉或手动加入）
+type Suppression struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Address string `json:"address" gorm:"type:varchar(255);uniqueIndex;not null"`
+	// Reason bounce/complaint/manual
+	Reason    string    `json:"reason" gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Suppression) TableName() string {
+	return "cs_email_suppressions"
+}
+
+// Service 邮件发送服务
+//
+// 入队后由后台循环渲染模板并经驱动发送，失败按指数退避
+// 重试；抑制名单中的地址在入队时直接标记suppressed。
+type Service struct {
+	db     *gorm.DB
+	driver Driver
+	from   string
+	logger *zap.Logger
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建邮件服务
+func NewService(db *gorm.DB, driver Driver, from string, logger *zap.Logger) (*Service, error) {
+	if from == "" {
+		return nil, fmt.Errorf("sender address is required")
+	}
+	if err := db.AutoMigrate(&QueuedEmail{}, &Suppression{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate email tables: %w", err)
+	}
+	return &Service{
+		db:     db,
+		driver: driver,
+		from:   from,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 启动发送循环
+func (s *Service) Start() {
+	go s.sendLoop()
+}
+
+// Stop 停止发送循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Enqueue 渲染前校验并入队
+func (s *Service) Enqueue(ctx context.Context, to, templateName string, data interface{}) (*QueuedEmail, error) {
+	to = strings.ToLower(strings.TrimSpace(to))
+	if to == "" || !strings.Contains(to, "@") {
+		return nil, fmt.Errorf("invalid recipient address: %s", to)
+	}
+	// 提前渲染一次以尽早暴露模板/数据问题
+	if _, _, err := Render(templateName, data); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template data: %w", err)
+	}
+
+	queued := &QueuedEmail{
+		ID:       uuid.NewString(),
+		To:       to,
+		Template: templateName,
+		Data:     datatypes.JSON(payload),
+		Status:   SendPending,
+	}
+	suppressed, err := s.isSuppressed(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if suppressed {
+		queued.Status = SendSuppressed
+		queued.LastError = "recipient is on the suppression list"
+	} else {
+		now := time.Now()
+		queued.NextRetryAt = &now
+	}
+	if err := s.db.WithContext(ctx).Create(queued).Error; err != nil {
+		return nil, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+	return queued, nil
+}
+
+// Queue 查询队列最近的记录
+func (s *Service) Queue(ctx context.Context, status string, limit int) ([]*QueuedEmail, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var queued []*QueuedEmail
+	if err := query.Find(&queued).Error; err != nil {
+		return nil, fmt.Errorf("failed to list email queue: %w", err)
+	}
+	return queued, nil
+}
+
+// Suppress 把地址加入抑制名单
+func (s *Service) Suppress(ctx context.Context, address, reason string) (*Suppression, error) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	if reason == "" {
+		reason = "manual"
+	}
+	entry := &Suppression{
+		ID:      uuid.NewString(),
+		Address: address,
+		Reason:  reason,
+	}
+	err := s.db.WithContext(ctx).Create(entry).Error
+	if err != nil {
+		// 已在名单中视为成功
+		var existing Suppression
+		if findErr := s.db.WithContext(ctx).First(&existing, "address = ?", address).Error; findErr == nil {
+			return &existing, nil
+		}
+		return nil, fmt.Errorf("failed to suppress address: %w", err)
+	}
+	return entry, nil
+}
+
+// Unsuppress 把地址移出抑制名单
+func (s *Service) Unsuppress(ctx context.Context, address string) error {
+	address = strings.ToLower(strings.TrimSpace(address))
+	result := s.db.WithContext(ctx).Delete(&Suppression{}, "address = ?", address)
+	if result.Error != nil {
+		return fmt.Errorf("failed to unsuppress address: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("address not found on suppression list: %s", address)
+	}
+	return nil
+}
+
+// Suppressions 列出抑制名单
+func (s *Service) Suppressions(ctx context.Context) ([]*Suppression, error) {
+	var entries []*Suppression
+	err := s.db.WithContext(ctx).Order("created_at DESC").Limit(500).Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions: %w", err)
+	}
+	return entries, nil
+}
+
+// isSuppressed 判断地址是否在抑制名单
+func (s *Service) isSuppressed(ctx context.Context, address string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&Suppression{}).
+		Where("address = ?", address).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check suppression list: %w", err)
+	}
+	return count > 0, nil
+}
+
+// sendLoop 周期性发送到期的队列记录
+func (s *Service) sendLoop() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.sendDue(context.Background())
+		}
+	}
+}
+
+// sendDue 发送所有到期记录
+func (s *Service) sendDue(ctx context.Context) {
+	var due []*QueuedEmail
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND next_retry_at <= ?", SendPending, time.Now()).
+		Limit(100).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due emails", zap.Error(err))
+		return
+	}
+	for _, queued := range due {
+		s.attempt(ctx, queued)
+	}
+}
+
+// attempt 执行一次发送并更新状态
+func (s *Service) attempt(ctx context.Context, queued *QueuedEmail) {
+	var data interface{}
+	if err := json.Unmarshal(queued.Data, &data); err != nil {
+		s.finish(ctx, queued, SendFailed, "invalid template data: "+err.Error())
+		return
+	}
+	subject, body, err := Render(queued.Template, data)
+	if err == nil {
+		err = s.driver.Send(ctx, s.from, queued.To, subject, body)
+	}
+	if err == nil {
+		s.finish(ctx, queued, SendSuccess, "")
+		return
+	}
+
+	queued.Attempts++
+	if queued.Attempts >= maxSendAttempts {
+		s.finish(ctx, queued, SendFailed, err.Error())
+		return
+	}
+	backoff := sendBackoff[len(sendBackoff)-1]
+	if queued.Attempts-1 < len(sendBackoff) {
+		backoff = sendBackoff[queued.Attempts-1]
+	}
+	next := time.Now().Add(backoff)
+	updateErr := s.db.WithContext(ctx).Model(&QueuedEmail{}).Where("id = ?", queued.ID).
+		Updates(map[string]interface{}{
+			"attempts":      queued.Attempts,
+			"next_retry_at": next,
+			"last_error":    err.Error(),
+		}).Error
+	if updateErr != nil {
+		s.logger.Error("failed to reschedule email", zap.Error(updateErr))
+	}
+}
+
+// finish 终态更新
+func (s *Service) finish(ctx context.Context, queued *QueuedEmail, status, lastError string) {
+	err := s.db.WithContext(ctx).Model(&QueuedEmail{}).Where("id = ?", queued.ID).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"next_retry_at": nil,
+			"last_error":    lastError,
+		}).Error
+	if err != nil {
+		s.logger.Error("failed to finalize email", zap.Error(err))
+	}
+}
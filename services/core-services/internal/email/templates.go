@@ -0,0 +1,99 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// 内置模板名
+const (
+	TemplateWelcome        = "welcome"
+	TemplatePasswordReset  = "password_reset"
+	TemplateTaskAssignment = "task_assignment"
+	TemplateDigest         = "digest"
+	TemplateNotification   = "notification"
+)
+
+// templateDef 模板主题与HTML正文
+type templateDef struct {
+	subject string
+	body    string
+}
+
+// builtinTemplates 内置HTML模板
+//
+// 正文统一走html/template转义，数据字段见各模板占位符。
+var builtinTemplates = map[string]templateDef{
+	TemplateWelcome: {
+		subject: "欢迎加入太上老君",
+		body: `<html><body>
+<h2>欢迎，{{.Username}}！</h2>
+<p>你的账号已创建成功，现在可以登录并开始使用了。</p>
+</body></html>`,
+	},
+	TemplatePasswordReset: {
+		subject: "重置密码",
+		body: `<html><body>
+<h2>重置密码</h2>
+<p>{{.Username}}，你好。点击下面的链接重置密码（{{.ExpiresMinutes}}分钟内有效）：</p>
+<p><a href="{{.ResetURL}}">{{.ResetURL}}</a></p>
+<p>如果这不是你本人的操作，请忽略本邮件。</p>
+</body></html>`,
+	},
+	TemplateTaskAssignment: {
+		subject: "你有新的任务指派",
+		body: `<html><body>
+<h2>任务指派</h2>
+<p>{{.Username}}，任务「{{.TaskTitle}}」已指派给你。</p>
+{{if .DueAt}}<p>截止时间：{{.DueAt}}</p>{{end}}
+<p><a href="{{.TaskURL}}">查看任务</a></p>
+</body></html>`,
+	},
+	TemplateDigest: {
+		subject: "你的摘要",
+		body: `<html><body>
+<h2>{{.Title}}</h2>
+{{range .Items}}<p>• {{.}}</p>
+{{end}}
+</body></html>`,
+	},
+	TemplateNotification: {
+		subject: "你有一条新通知",
+		body: `<html><body>
+<h2>{{.Title}}</h2>
+{{if .Body}}<p>{{.Body}}</p>{{end}}
+</body></html>`,
+	},
+}
+
+// parsedTemplates 启动时解析一次的模板缓存
+var parsedTemplates = func() map[string]*template.Template {
+	parsed := make(map[string]*template.Template, len(builtinTemplates))
+	for name, def := range builtinTemplates {
+		parsed[name] = template.Must(template.New(name).Parse(def.body))
+	}
+	return parsed
+}()
+
+// Render 渲染模板，返回主题与HTML正文
+func Render(name string, data interface{}) (string, string, error) {
+	tpl, ok := parsedTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template: %s", name)
+	}
+	var body bytes.Buffer
+	if err := tpl.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("failed to render email template %s: %w", name, err)
+	}
+	return builtinTemplates[name].subject, body.String(), nil
+}
+
+// TemplateNames 列出内置模板名
+func TemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	return names
+}
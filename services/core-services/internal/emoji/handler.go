@@ -0,0 +1,179 @@
+package emoji
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 表情包HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建表情包处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册表情包相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	packs := router.Group("/emoji/packs")
+	{
+		packs.POST("", h.CreatePack)
+		packs.GET("", h.ListPacks)
+		packs.GET("/:packId", h.GetPack)
+		packs.POST("/:packId/emojis", h.AddEmoji)
+		packs.POST("/:packId/review", h.ReviewPack)
+		packs.GET("/:packId/stats", h.PackStats)
+	}
+	router.PUT("/emoji/rooms/:roomId/packs/:packId", h.SetRoomPack)
+	router.POST("/emoji/rooms/:roomId/resolve", h.Resolve)
+}
+
+// CreatePack 上传表情包
+func (h *Handler) CreatePack(c *gin.Context) {
+	var req struct {
+		TenantID  string `json:"tenantId" binding:"required"`
+		Name      string `json:"name" binding:"required"`
+		Kind      string `json:"kind" binding:"required,oneof=emoji sticker"`
+		CreatedBy string `json:"createdBy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pack, err := h.service.CreatePack(c.Request.Context(), req.TenantID, req.Name, req.Kind, req.CreatedBy)
+	if err != nil {
+		c.JSON(statusForEmojiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pack)
+}
+
+// ListPacks 列出租户表情包
+func (h *Handler) ListPacks(c *gin.Context) {
+	tenantID := c.Query("tenantId")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenantId is required"})
+		return
+	}
+
+	packs, err := h.service.ListPacks(c.Request.Context(), tenantID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"packs": packs, "count": len(packs)})
+}
+
+// GetPack 获取表情包详情
+func (h *Handler) GetPack(c *gin.Context) {
+	pack, err := h.service.GetPack(c.Request.Context(), c.Param("packId"))
+	if err != nil {
+		c.JSON(statusForEmojiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
+
+// AddEmoji 向表情包添加表情
+func (h *Handler) AddEmoji(c *gin.Context) {
+	var req struct {
+		Shortcode string `json:"shortcode" binding:"required"`
+		ImageURL  string `json:"imageUrl" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.AddEmoji(c.Request.Context(), c.Param("packId"), req.Shortcode, req.ImageURL)
+	if err != nil {
+		c.JSON(statusForEmojiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// ReviewPack 审核表情包
+func (h *Handler) ReviewPack(c *gin.Context) {
+	var req struct {
+		ReviewerID string `json:"reviewerId" binding:"required"`
+		Decision   string `json:"decision" binding:"required,oneof=approved rejected"`
+		Reason     string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pack, err := h.service.ReviewPack(c.Request.Context(), c.Param("packId"), req.ReviewerID, req.Decision, req.Reason)
+	if err != nil {
+		c.JSON(statusForEmojiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
+
+// PackStats 表情包使用统计
+func (h *Handler) PackStats(c *gin.Context) {
+	stats, err := h.service.PackStats(c.Request.Context(), c.Param("packId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"stats": stats, "count": len(stats)})
+}
+
+// SetRoomPack 房间启用/停用表情包
+func (h *Handler) SetRoomPack(c *gin.Context) {
+	var req struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.SetRoomPack(c.Request.Context(), c.Param("roomId"), c.Param("packId"), *req.Enabled)
+	if err != nil {
+		c.JSON(statusForEmojiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "room pack updated"})
+}
+
+// Resolve 解析消息中的表情引用
+func (h *Handler) Resolve(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resolved, err := h.service.Resolve(c.Request.Context(), c.Param("roomId"), req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"emojis": resolved, "count": len(resolved)})
+}
+
+// statusForEmojiError 把服务层错误映射为HTTP状态码
+func statusForEmojiError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already reviewed"), strings.Contains(message, "not approved"):
+		return http.StatusConflict
+	case strings.Contains(message, "unsupported"), strings.Contains(message, "invalid"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,273 @@
+package emoji
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 表情包状态（上传后需审核通过才可启用）
+const (
+	PackPending  = "pending"
+	PackApproved = "approved"
+	PackRejected = "rejected"
+)
+
+// shortcodePattern 消息中的表情引用，如 :party_laojun:
+var shortcodePattern = regexp.MustCompile(`:([a-z0-9_]+):`)
+
+// Pack 表情/贴纸包
+type Pack struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID string `json:"tenantId" gorm:"index;type:varchar(36);not null"`
+	Name     string `json:"name" gorm:"not null"`
+	// Kind emoji / sticker
+	Kind         string    `json:"kind" gorm:"type:varchar(16);default:'emoji'"`
+	Status       string    `json:"status" gorm:"type:varchar(16);default:'pending'"`
+	ReviewedBy   string    `json:"reviewedBy,omitempty" gorm:"type:varchar(36)"`
+	ReviewReason string    `json:"reviewReason,omitempty"`
+	CreatedBy    string    `json:"createdBy" gorm:"type:varchar(36)"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	Emojis []Emoji `json:"emojis,omitempty" gorm:"foreignKey:PackID"`
+}
+
+// TableName 指定表名
+func (Pack) TableName() string {
+	return "cs_emoji_packs"
+}
+
+// Emoji 单个表情
+type Emoji struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	PackID string `json:"packId" gorm:"index;type:varchar(36);not null"`
+	// Shortcode 引用名，租户内唯一，客户端以 :shortcode: 引用
+	Shortcode string    `json:"shortcode" gorm:"uniqueIndex:idx_emoji_shortcode;type:varchar(64);not null"`
+	TenantID  string    `json:"tenantId" gorm:"uniqueIndex:idx_emoji_shortcode;type:varchar(36);not null"`
+	ImageURL  string    `json:"imageUrl" gorm:"not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Emoji) TableName() string {
+	return "cs_emojis"
+}
+
+// RoomPack 房间启用的表情包
+type RoomPack struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	RoomID  string `json:"roomId" gorm:"uniqueIndex:idx_room_pack;type:varchar(36);not null"`
+	PackID  string `json:"packId" gorm:"uniqueIndex:idx_room_pack;type:varchar(36);not null"`
+	Enabled bool   `json:"enabled" gorm:"default:true"`
+}
+
+// TableName 指定表名
+func (RoomPack) TableName() string {
+	return "cs_room_packs"
+}
+
+// UsageStat 表情按日使用统计
+type UsageStat struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	EmojiID string `json:"emojiId" gorm:"uniqueIndex:idx_emoji_usage;type:varchar(36);not null"`
+	Date    string `json:"date" gorm:"uniqueIndex:idx_emoji_usage;type:varchar(10);not null"` // YYYY-MM-DD
+	Count   int64  `json:"count" gorm:"default:0"`
+}
+
+// TableName 指定表名
+func (UsageStat) TableName() string {
+	return "cs_emoji_usage"
+}
+
+// Service 表情包服务
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService 创建表情包服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Pack{}, &Emoji{}, &RoomPack{}, &UsageStat{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate emoji tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// CreatePack 上传新表情包（进入待审核状态）
+func (s *Service) CreatePack(ctx context.Context, tenantID, name, kind, createdBy string) (*Pack, error) {
+	if kind != "emoji" && kind != "sticker" {
+		return nil, fmt.Errorf("unsupported pack kind: %s", kind)
+	}
+	pack := &Pack{
+		ID:        uuid.NewString(),
+		TenantID:  tenantID,
+		Name:      name,
+		Kind:      kind,
+		Status:    PackPending,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(pack).Error; err != nil {
+		return nil, fmt.Errorf("failed to create pack: %w", err)
+	}
+	return pack, nil
+}
+
+// AddEmoji 向表情包添加表情
+func (s *Service) AddEmoji(ctx context.Context, packID, shortcode, imageURL string) (*Emoji, error) {
+	if !shortcodePattern.MatchString(":" + shortcode + ":") {
+		return nil, fmt.Errorf("invalid shortcode %q: only lowercase letters, digits and underscore allowed", shortcode)
+	}
+	pack, err := s.GetPack(ctx, packID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &Emoji{
+		ID:        uuid.NewString(),
+		PackID:    pack.ID,
+		TenantID:  pack.TenantID,
+		Shortcode: shortcode,
+		ImageURL:  imageURL,
+	}
+	if err := s.db.WithContext(ctx).Create(item).Error; err != nil {
+		return nil, fmt.Errorf("failed to add emoji: %w", err)
+	}
+	return item, nil
+}
+
+// GetPack 获取表情包（含表情）
+func (s *Service) GetPack(ctx context.Context, id string) (*Pack, error) {
+	var pack Pack
+	if err := s.db.WithContext(ctx).Preload("Emojis").First(&pack, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("pack not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get pack: %w", err)
+	}
+	return &pack, nil
+}
+
+// ListPacks 列出租户的表情包
+func (s *Service) ListPacks(ctx context.Context, tenantID, status string) ([]*Pack, error) {
+	query := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var packs []*Pack
+	if err := query.Order("created_at DESC").Find(&packs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	return packs, nil
+}
+
+// ReviewPack 审核表情包
+func (s *Service) ReviewPack(ctx context.Context, packID, reviewerID, decision, reason string) (*Pack, error) {
+	if decision != PackApproved && decision != PackRejected {
+		return nil, fmt.Errorf("unsupported decision: %s", decision)
+	}
+	pack, err := s.GetPack(ctx, packID)
+	if err != nil {
+		return nil, err
+	}
+	if pack.Status != PackPending {
+		return nil, fmt.Errorf("pack already reviewed: %s", pack.Status)
+	}
+
+	updates := map[string]interface{}{
+		"status":        decision,
+		"reviewed_by":   reviewerID,
+		"review_reason": reason,
+	}
+	if err := s.db.WithContext(ctx).Model(&Pack{}).Where("id = ?", packID).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to review pack: %w", err)
+	}
+	pack.Status = decision
+	pack.ReviewedBy = reviewerID
+	pack.ReviewReason = reason
+	return pack, nil
+}
+
+// SetRoomPack 在房间内启用/停用表情包（仅限已审核通过的包）
+func (s *Service) SetRoomPack(ctx context.Context, roomID, packID string, enabled bool) error {
+	pack, err := s.GetPack(ctx, packID)
+	if err != nil {
+		return err
+	}
+	if enabled && pack.Status != PackApproved {
+		return fmt.Errorf("pack is not approved: %s", pack.Status)
+	}
+
+	record := &RoomPack{RoomID: roomID, PackID: packID, Enabled: enabled}
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "room_id"}, {Name: "pack_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+	}).Create(record).Error
+	if err != nil {
+		return fmt.Errorf("failed to update room pack: %w", err)
+	}
+	return nil
+}
+
+// Resolve 解析消息中的表情引用
+//
+// 扫描 :shortcode: 引用，返回在该房间可用（所在包已启用且
+// 审核通过）的表情映射，供客户端一致渲染；同时记录使用统计。
+func (s *Service) Resolve(ctx context.Context, roomID, text string) (map[string]string, error) {
+	matches := shortcodePattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return map[string]string{}, nil
+	}
+	shortcodes := make([]string, 0, len(matches))
+	for _, match := range matches {
+		shortcodes = append(shortcodes, match[1])
+	}
+
+	var emojis []*Emoji
+	err := s.db.WithContext(ctx).
+		Joins("JOIN cs_room_packs ON cs_room_packs.pack_id = cs_emojis.pack_id AND cs_room_packs.room_id = ? AND cs_room_packs.enabled = ?", roomID, true).
+		Joins("JOIN cs_emoji_packs ON cs_emoji_packs.id = cs_emojis.pack_id AND cs_emoji_packs.status = ?", PackApproved).
+		Where("cs_emojis.shortcode IN ?", shortcodes).
+		Find(&emojis).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve emojis: %w", err)
+	}
+
+	resolved := make(map[string]string, len(emojis))
+	for _, item := range emojis {
+		resolved[item.Shortcode] = item.ImageURL
+		s.recordUsage(ctx, item.ID)
+	}
+	return resolved, nil
+}
+
+// PackStats 表情包的使用统计（按表情聚合）
+func (s *Service) PackStats(ctx context.Context, packID string) ([]*UsageStat, error) {
+	var stats []*UsageStat
+	err := s.db.WithContext(ctx).Model(&UsageStat{}).
+		Select("cs_emoji_usage.emoji_id, '' AS date, SUM(cs_emoji_usage.count) AS count").
+		Joins("JOIN cs_emojis ON cs_emojis.id = cs_emoji_usage.emoji_id").
+		Where("cs_emojis.pack_id = ?", packID).
+		Group("cs_emoji_usage.emoji_id").
+		Order("count DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pack stats: %w", err)
+	}
+	return stats, nil
+}
+
+// recordUsage 使用计数+1（按日），失败不影响解析结果
+func (s *Service) recordUsage(ctx context.Context, emojiID string) {
+	today := time.Now().Format("2006-01-02")
+	stat := &UsageStat{EmojiID: emojiID, Date: today, Count: 1}
+	_ = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "emoji_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("cs_emoji_usage.count + 1")}),
+	}).Create(stat).Error
+}
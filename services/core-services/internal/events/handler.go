@@ -0,0 +1,98 @@
+package events
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 事件重放HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建事件重放处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册事件重放相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	replays := router.Group("/admin/events/replays")
+	{
+		replays.POST("", h.StartReplay)
+		replays.GET("", h.ListReplays)
+		replays.GET("/:replayId", h.GetReplay)
+		replays.POST("/:replayId/resume", h.Resume)
+	}
+	router.GET("/admin/events/consumers", h.Consumers)
+}
+
+// StartReplay 启动重放
+func (h *Handler) StartReplay(c *gin.Context) {
+	var req ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	replay, err := h.service.StartReplay(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusForReplayError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, replay)
+}
+
+// Resume 续传中断的重放
+func (h *Handler) Resume(c *gin.Context) {
+	rate, _ := strconv.Atoi(c.DefaultQuery("rate", "0"))
+	replay, err := h.service.Resume(c.Request.Context(), c.Param("replayId"), rate)
+	if err != nil {
+		c.JSON(statusForReplayError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, replay)
+}
+
+// GetReplay 查询重放进度
+func (h *Handler) GetReplay(c *gin.Context) {
+	replay, err := h.service.GetReplay(c.Request.Context(), c.Param("replayId"))
+	if err != nil {
+		c.JSON(statusForReplayError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, replay)
+}
+
+// ListReplays 列出重放任务
+func (h *Handler) ListReplays(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	replays, err := h.service.ListReplays(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"replays": replays, "count": len(replays)})
+}
+
+// Consumers 列出可重放的消费者
+func (h *Handler) Consumers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"consumers": h.service.Consumers()})
+}
+
+// statusForReplayError 把服务层错误映射为HTTP状态码
+func statusForReplayError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "unknown consumer"),
+		strings.Contains(message, "must be within"),
+		strings.Contains(message, "already running"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,324 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 重放状态
+const (
+	ReplayRunning  = "running"
+	ReplayDone     = "done"
+	ReplayFailed   = "failed"
+	ReplayDryRun   = "dry_run"
+	ReplayStopped  = "stopped"
+	defaultRate    = 200  // 每秒事件数
+	replayBatch    = 500  // 单次读取的事件数
+	maxReplayHours = 2160 // 最早可重放90天前的事件
+)
+
+// Event 领域事件日志
+//
+// publishEvent 扇出时同步落库，作为重放与审计的事实来源；
+// webhook投递、搜索索引等都是它的下游。
+type Event struct {
+	ID        string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Event     string         `json:"event" gorm:"type:varchar(64);index;not null"`
+	Payload   datatypes.JSON `json:"payload" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Event) TableName() string {
+	return "cs_domain_events"
+}
+
+// Replay 一次重放任务的检查点与进度
+type Replay struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Consumer string `json:"consumer" gorm:"type:varchar(64);index;not null"`
+	// EventFilter 只重放该前缀的事件，空表示全部（如 "task."）
+	EventFilter string    `json:"eventFilter,omitempty" gorm:"type:varchar(64)"`
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	// Cursor 已处理到的事件时间，中断后从这里续传
+	Cursor    time.Time `json:"cursor"`
+	CursorID  string    `json:"cursorId" gorm:"type:varchar(36)"`
+	Processed int64     `json:"processed"`
+	Failed    int64     `json:"failed"`
+	Status    string    `json:"status" gorm:"type:varchar(16);index"`
+	DryRun    bool      `json:"dryRun"`
+	// Summary 按事件类型统计的处理量，dry-run时即为diff预览
+	Summary   datatypes.JSON `json:"summary,omitempty" gorm:"type:jsonb"`
+	LastError string         `json:"lastError,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Replay) TableName() string {
+	return "cs_event_replays"
+}
+
+// Consumer 可接收重放事件的下游
+//
+// 消费端接口：搜索索引、统计表等在main中注册，
+// 重放器不关心各下游的具体实现。
+type Consumer interface {
+	HandleEvent(event string, payload interface{})
+}
+
+// ConsumerFunc 函数式消费者适配器
+type ConsumerFunc func(event string, payload interface{})
+
+// HandleEvent 实现Consumer
+func (f ConsumerFunc) HandleEvent(event string, payload interface{}) {
+	f(event, payload)
+}
+
+// Service 领域事件日志与重放器
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	consumers map[string]Consumer
+}
+
+// NewService 创建事件日志服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Event{}, &Replay{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate event tables: %w", err)
+	}
+	return &Service{
+		db:        db,
+		logger:    logger,
+		consumers: make(map[string]Consumer),
+	}, nil
+}
+
+// RegisterConsumer 注册可重放的下游消费者
+func (s *Service) RegisterConsumer(name string, consumer Consumer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumers[name] = consumer
+}
+
+// Record 把事件追加到日志
+//
+// 在publishEvent扇出路径上同步调用；失败只记日志，
+// 不阻塞业务写入。
+func (s *Service) Record(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.logger.Error("failed to marshal domain event", zap.Error(err))
+		return
+	}
+	record := &Event{
+		ID:      uuid.NewString(),
+		Event:   event,
+		Payload: datatypes.JSON(data),
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		s.logger.Error("failed to record domain event",
+			zap.String("event", event), zap.Error(err))
+	}
+}
+
+// ReplayRequest 重放请求
+type ReplayRequest struct {
+	Consumer    string    `json:"consumer" binding:"required"`
+	EventFilter string    `json:"eventFilter"`
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	// Rate 每秒重放的事件数，<=0使用默认值
+	Rate   int  `json:"rate"`
+	DryRun bool `json:"dryRun"`
+}
+
+// StartReplay 启动一次重放，立即返回任务记录，处理在后台进行
+func (s *Service) StartReplay(ctx context.Context, req ReplayRequest) (*Replay, error) {
+	s.mu.RLock()
+	consumer, ok := s.consumers[req.Consumer]
+	s.mu.RUnlock()
+	if !ok && !req.DryRun {
+		return nil, fmt.Errorf("unknown consumer: %s", req.Consumer)
+	}
+
+	now := time.Now()
+	if req.Until.IsZero() {
+		req.Until = now
+	}
+	if req.Since.IsZero() || now.Sub(req.Since) > maxReplayHours*time.Hour {
+		return nil, fmt.Errorf("since is required and must be within the last %d days", maxReplayHours/24)
+	}
+	if req.Rate <= 0 {
+		req.Rate = defaultRate
+	}
+
+	replay := &Replay{
+		ID:          uuid.NewString(),
+		Consumer:    req.Consumer,
+		EventFilter: req.EventFilter,
+		Since:       req.Since,
+		Until:       req.Until,
+		Cursor:      req.Since,
+		Status:      ReplayRunning,
+		DryRun:      req.DryRun,
+	}
+	if err := s.db.WithContext(ctx).Create(replay).Error; err != nil {
+		return nil, fmt.Errorf("failed to create replay: %w", err)
+	}
+
+	go s.run(replay, consumer, req.Rate)
+	return replay, nil
+}
+
+// Resume 续传一个中断的重放
+func (s *Service) Resume(ctx context.Context, replayID string, rate int) (*Replay, error) {
+	var replay Replay
+	if err := s.db.WithContext(ctx).First(&replay, "id = ?", replayID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("replay not found: %s", replayID)
+		}
+		return nil, fmt.Errorf("failed to get replay: %w", err)
+	}
+	if replay.Status == ReplayRunning {
+		return nil, fmt.Errorf("replay already running: %s", replayID)
+	}
+
+	s.mu.RLock()
+	consumer, ok := s.consumers[replay.Consumer]
+	s.mu.RUnlock()
+	if !ok && !replay.DryRun {
+		return nil, fmt.Errorf("unknown consumer: %s", replay.Consumer)
+	}
+	if rate <= 0 {
+		rate = defaultRate
+	}
+
+	replay.Status = ReplayRunning
+	replay.LastError = ""
+	if err := s.db.WithContext(ctx).Save(&replay).Error; err != nil {
+		return nil, fmt.Errorf("failed to resume replay: %w", err)
+	}
+	go s.run(&replay, consumer, rate)
+	return &replay, nil
+}
+
+// run 重放主循环：分批读取、限速投递、定期落盘检查点
+func (s *Service) run(replay *Replay, consumer Consumer, rate int) {
+	interval := time.Second / time.Duration(rate)
+	summary := make(map[string]int64)
+	_ = json.Unmarshal(replay.Summary, &summary)
+
+	for {
+		var batch []*Event
+		query := s.db.
+			Where("created_at > ? OR (created_at = ? AND id > ?)",
+				replay.Cursor, replay.Cursor, replay.CursorID).
+			Where("created_at <= ?", replay.Until).
+			Order("created_at ASC, id ASC").
+			Limit(replayBatch)
+		if replay.EventFilter != "" {
+			query = query.Where("event LIKE ?", replay.EventFilter+"%")
+		}
+		if err := query.Find(&batch).Error; err != nil {
+			s.finish(replay, ReplayFailed, summary, err.Error())
+			return
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, event := range batch {
+			if !replay.DryRun {
+				var payload interface{}
+				if err := json.Unmarshal(event.Payload, &payload); err == nil {
+					consumer.HandleEvent(event.Event, payload)
+				} else {
+					replay.Failed++
+				}
+				time.Sleep(interval)
+			}
+			summary[event.Event]++
+			replay.Processed++
+			replay.Cursor = event.CreatedAt
+			replay.CursorID = event.ID
+		}
+
+		// 每批落盘一次检查点，中断后可续传
+		s.checkpoint(replay, summary)
+	}
+
+	status := ReplayDone
+	if replay.DryRun {
+		status = ReplayDryRun
+	}
+	s.finish(replay, status, summary, "")
+}
+
+// checkpoint 持久化重放进度
+func (s *Service) checkpoint(replay *Replay, summary map[string]int64) {
+	data, _ := json.Marshal(summary)
+	replay.Summary = datatypes.JSON(data)
+	if err := s.db.Save(replay).Error; err != nil {
+		s.logger.Error("failed to checkpoint replay",
+			zap.String("replay", replay.ID), zap.Error(err))
+	}
+}
+
+// finish 结束重放并记录状态
+func (s *Service) finish(replay *Replay, status string, summary map[string]int64, lastError string) {
+	replay.Status = status
+	replay.LastError = lastError
+	s.checkpoint(replay, summary)
+	s.logger.Info("event replay finished",
+		zap.String("replay", replay.ID),
+		zap.String("status", status),
+		zap.Int64("processed", replay.Processed))
+}
+
+// GetReplay 查询重放任务
+func (s *Service) GetReplay(ctx context.Context, id string) (*Replay, error) {
+	var replay Replay
+	if err := s.db.WithContext(ctx).First(&replay, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("replay not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get replay: %w", err)
+	}
+	return &replay, nil
+}
+
+// ListReplays 列出最近的重放任务
+func (s *Service) ListReplays(ctx context.Context, limit int) ([]*Replay, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	var replays []*Replay
+	err := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&replays).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replays: %w", err)
+	}
+	return replays, nil
+}
+
+// Consumers 列出已注册的消费者名称
+func (s *Service) Consumers() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.consumers))
+	for name := range s.consumers {
+		names = append(names, name)
+	}
+	return names
+}
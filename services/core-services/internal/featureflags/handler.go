@@ -0,0 +1,156 @@
+package featureflags
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Enabled SDK式辅助：按当前请求的用户与角色评估开关
+//
+// 处理器在分支新旧逻辑处直接调用，未登录请求按空用户评估
+// （只可能命中角色与全量规则）。
+func (s *Service) Enabled(c *gin.Context, key string) bool {
+	return s.Evaluate(c.Request.Context(), key, c.GetHeader("X-User-ID"), c.GetHeader("X-User-Role"))
+}
+
+// Handler 功能开关HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建功能开关处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册功能开关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	flags := router.Group("/flags")
+	{
+		flags.POST("", h.CreateFlag)
+		flags.GET("", h.ListFlags)
+		flags.GET("/:flagKey", h.GetFlag)
+		flags.PUT("/:flagKey", h.UpdateFlag)
+		flags.DELETE("/:flagKey", h.DeleteFlag)
+		flags.GET("/:flagKey/evaluate", h.EvaluateFlag)
+	}
+}
+
+// flagResponse 管理端视图，展开定向列表
+type flagResponse struct {
+	*Flag
+	UserIDs []string `json:"userIds"`
+	Roles   []string `json:"roles"`
+}
+
+// newFlagResponse 构造管理端视图
+func newFlagResponse(flag *Flag) flagResponse {
+	return flagResponse{Flag: flag, UserIDs: flag.UserIDList(), Roles: flag.RoleList()}
+}
+
+// CreateFlag 创建开关
+func (h *Handler) CreateFlag(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var input CreateFlagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	flag, err := h.service.CreateFlag(c.Request.Context(), userID, input)
+	if err != nil {
+		c.JSON(statusForFlagError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, newFlagResponse(flag))
+}
+
+// ListFlags 列出全部开关
+func (h *Handler) ListFlags(c *gin.Context) {
+	flags, err := h.service.ListFlags(c.Request.Context())
+	if err != nil {
+		c.JSON(statusForFlagError(err), gin.H{"error": err.Error()})
+		return
+	}
+	result := make([]flagResponse, 0, len(flags))
+	for i := range flags {
+		result = append(result, newFlagResponse(&flags[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"flags": result, "total": len(result)})
+}
+
+// GetFlag 查询开关
+func (h *Handler) GetFlag(c *gin.Context) {
+	flag, err := h.service.GetFlag(c.Request.Context(), c.Param("flagKey"))
+	if err != nil {
+		c.JSON(statusForFlagError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newFlagResponse(flag))
+}
+
+// UpdateFlag 更新开关
+func (h *Handler) UpdateFlag(c *gin.Context) {
+	if _, ok := requireUser(c); !ok {
+		return
+	}
+	var input UpdateFlagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	flag, err := h.service.UpdateFlag(c.Request.Context(), c.Param("flagKey"), input)
+	if err != nil {
+		c.JSON(statusForFlagError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newFlagResponse(flag))
+}
+
+// DeleteFlag 删除开关
+func (h *Handler) DeleteFlag(c *gin.Context) {
+	if _, ok := requireUser(c); !ok {
+		return
+	}
+	if err := h.service.DeleteFlag(c.Request.Context(), c.Param("flagKey")); err != nil {
+		c.JSON(statusForFlagError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "flag deleted"})
+}
+
+// EvaluateFlag 按当前请求用户评估开关（客户端预取用）
+func (h *Handler) EvaluateFlag(c *gin.Context) {
+	key := c.Param("flagKey")
+	c.JSON(http.StatusOK, gin.H{
+		"key":     key,
+		"enabled": h.service.Enabled(c, key),
+	})
+}
+
+// requireUser 从请求头解析用户ID
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return "", false
+	}
+	return userID, true
+}
+
+// statusForFlagError 根据错误内容映射HTTP状态码
+func statusForFlagError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
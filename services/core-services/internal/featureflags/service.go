@@ -0,0 +1,331 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 评估与缓存参数
+const (
+	// evalCacheTTL 单个开关定义的进程内缓存时长
+	evalCacheTTL = 30 * time.Second
+	// maxTargetUsers 单开关定向用户数上限
+	maxTargetUsers = 200
+	// flagKeyMaxLen 开关键长度上限
+	flagKeyMaxLen = 64
+)
+
+// Flag 一个功能开关
+//
+// Enabled为总闸；定向规则按 用户ID → 角色 → 百分比 的顺序
+// 评估，任一命中即放行。三类规则都为空时视为全量放行，便于
+// 上线稳定后直接当普通开关用。
+type Flag struct {
+	ID  string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Key string `json:"key" gorm:"uniqueIndex;type:varchar(64);not null"`
+	// Description 开关用途说明
+	Description string `json:"description" gorm:"type:varchar(255)"`
+	Enabled     bool   `json:"enabled"`
+	// Percentage 百分比灰度，0-100；按开关键+用户ID哈希分桶，
+	// 同一用户在同一开关下的结果稳定
+	Percentage int `json:"percentage"`
+	// UserIDs 定向放行的用户ID，逗号分隔存储
+	UserIDs string `json:"-" gorm:"type:text"`
+	// Roles 定向放行的角色，逗号分隔存储
+	Roles     string    `json:"-" gorm:"type:varchar(255)"`
+	CreatedBy string    `json:"createdBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Flag) TableName() string {
+	return "cs_feature_flags"
+}
+
+// UserIDList 定向用户ID列表
+func (f *Flag) UserIDList() []string {
+	return splitList(f.UserIDs)
+}
+
+// RoleList 定向角色列表
+func (f *Flag) RoleList() []string {
+	return splitList(f.Roles)
+}
+
+// cachedFlag 带过期时间的开关缓存项；missing表示键不存在
+type cachedFlag struct {
+	flag      *Flag
+	missing   bool
+	expiresAt time.Time
+}
+
+// Service 功能开关服务
+//
+// 开关定义落库；评估走进程内带TTL的缓存以避免逐请求查库
+// （部署未引入Redis，多实例各自缓存，变更最迟30秒内生效）。
+// 处理器侧通过Enabled辅助方法按当前用户评估。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	cacheMu sync.Mutex
+	cache   map[string]*cachedFlag
+}
+
+// NewService 创建功能开关服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Flag{}); err != nil {
+		return nil, fmt.Errorf("migrate feature flags: %w", err)
+	}
+	return &Service{
+		db:     db,
+		logger: logger,
+		cache:  make(map[string]*cachedFlag),
+	}, nil
+}
+
+// CreateFlagInput 创建开关的入参
+type CreateFlagInput struct {
+	Key         string   `json:"key" binding:"required"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Percentage  int      `json:"percentage"`
+	UserIDs     []string `json:"userIds"`
+	Roles       []string `json:"roles"`
+}
+
+// CreateFlag 创建开关
+func (s *Service) CreateFlag(ctx context.Context, userID string, input CreateFlagInput) (*Flag, error) {
+	key := strings.TrimSpace(input.Key)
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	if err := validateRules(input.Percentage, input.UserIDs); err != nil {
+		return nil, err
+	}
+	flag := &Flag{
+		ID:          uuid.NewString(),
+		Key:         key,
+		Description: strings.TrimSpace(input.Description),
+		Enabled:     input.Enabled,
+		Percentage:  input.Percentage,
+		UserIDs:     joinList(input.UserIDs),
+		Roles:       joinList(input.Roles),
+		CreatedBy:   userID,
+	}
+	if err := s.db.WithContext(ctx).Create(flag).Error; err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") || strings.Contains(err.Error(), "Duplicate") {
+			return nil, fmt.Errorf("invalid flag: key %q already exists", key)
+		}
+		return nil, err
+	}
+	s.invalidate(key)
+	s.logger.Info("feature flag created", zap.String("key", key), zap.String("user_id", userID))
+	return flag, nil
+}
+
+// UpdateFlagInput 更新开关的入参，nil字段保持不变
+type UpdateFlagInput struct {
+	Description *string   `json:"description"`
+	Enabled     *bool     `json:"enabled"`
+	Percentage  *int      `json:"percentage"`
+	UserIDs     *[]string `json:"userIds"`
+	Roles       *[]string `json:"roles"`
+}
+
+// UpdateFlag 更新开关
+func (s *Service) UpdateFlag(ctx context.Context, key string, input UpdateFlagInput) (*Flag, error) {
+	flag, err := s.GetFlag(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if input.Description != nil {
+		flag.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Enabled != nil {
+		flag.Enabled = *input.Enabled
+	}
+	if input.Percentage != nil {
+		flag.Percentage = *input.Percentage
+	}
+	if input.UserIDs != nil {
+		flag.UserIDs = joinList(*input.UserIDs)
+	}
+	if input.Roles != nil {
+		flag.Roles = joinList(*input.Roles)
+	}
+	if err := validateRules(flag.Percentage, flag.UserIDList()); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(flag).Error; err != nil {
+		return nil, err
+	}
+	s.invalidate(key)
+	s.logger.Info("feature flag updated", zap.String("key", key),
+		zap.Bool("enabled", flag.Enabled), zap.Int("percentage", flag.Percentage))
+	return flag, nil
+}
+
+// DeleteFlag 删除开关
+func (s *Service) DeleteFlag(ctx context.Context, key string) error {
+	result := s.db.WithContext(ctx).Where("`key` = ?", key).Delete(&Flag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("flag not found")
+	}
+	s.invalidate(key)
+	return nil
+}
+
+// GetFlag 按键查询开关
+func (s *Service) GetFlag(ctx context.Context, key string) (*Flag, error) {
+	var flag Flag
+	err := s.db.WithContext(ctx).Where("`key` = ?", key).First(&flag).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("flag not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// ListFlags 列出全部开关
+func (s *Service) ListFlags(ctx context.Context) ([]Flag, error) {
+	var flags []Flag
+	if err := s.db.WithContext(ctx).Order("`key`").Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// Evaluate 按用户与角色评估开关
+//
+// 未定义或总闸关闭返回false；评估经进程内缓存，SDK侧可在
+// 热路径上放心调用。
+func (s *Service) Evaluate(ctx context.Context, key, userID, role string) bool {
+	flag := s.cachedFlag(ctx, key)
+	if flag == nil || !flag.Enabled {
+		return false
+	}
+	userIDs := flag.UserIDList()
+	roles := flag.RoleList()
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	if role != "" {
+		for _, candidate := range roles {
+			if candidate == role {
+				return true
+			}
+		}
+	}
+	if flag.Percentage > 0 {
+		return bucket(key, userID) < flag.Percentage
+	}
+	// 无任何定向规则时视为全量放行
+	return len(userIDs) == 0 && len(roles) == 0
+}
+
+// cachedFlag 经TTL缓存取开关定义，键不存在也缓存以抵御穿透
+func (s *Service) cachedFlag(ctx context.Context, key string) *Flag {
+	now := time.Now()
+	s.cacheMu.Lock()
+	entry, ok := s.cache[key]
+	if ok && now.Before(entry.expiresAt) {
+		s.cacheMu.Unlock()
+		if entry.missing {
+			return nil
+		}
+		return entry.flag
+	}
+	s.cacheMu.Unlock()
+
+	flag, err := s.GetFlag(ctx, key)
+	missing := false
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			s.logger.Warn("feature flag lookup failed", zap.String("key", key), zap.Error(err))
+			return nil
+		}
+		missing = true
+	}
+	s.cacheMu.Lock()
+	s.cache[key] = &cachedFlag{flag: flag, missing: missing, expiresAt: now.Add(evalCacheTTL)}
+	s.cacheMu.Unlock()
+	return flag
+}
+
+// invalidate 写路径后清除缓存项
+func (s *Service) invalidate(key string) {
+	s.cacheMu.Lock()
+	delete(s.cache, key)
+	s.cacheMu.Unlock()
+}
+
+// bucket 按开关键+用户ID哈希到0-99的稳定分桶
+func bucket(key, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte(":"))
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
+
+// validateKey 校验开关键
+func validateKey(key string) error {
+	if key == "" || len(key) > flagKeyMaxLen {
+		return fmt.Errorf("invalid flag: key must be 1-%d characters", flagKeyMaxLen)
+	}
+	for _, r := range key {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			continue
+		}
+		return fmt.Errorf("invalid flag: key may contain lowercase letters, digits, '-', '_' and '.'")
+	}
+	return nil
+}
+
+// validateRules 校验定向规则
+func validateRules(percentage int, userIDs []string) error {
+	if percentage < 0 || percentage > 100 {
+		return fmt.Errorf("invalid flag: percentage must be between 0 and 100")
+	}
+	if len(userIDs) > maxTargetUsers {
+		return fmt.Errorf("invalid flag: at most %d targeted users", maxTargetUsers)
+	}
+	return nil
+}
+
+// splitList 逗号分隔字符串转列表
+func splitList(joined string) []string {
+	if joined == "" {
+		return nil
+	}
+	parts := strings.Split(joined, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// joinList 列表转逗号分隔字符串，去除空项
+func joinList(items []string) string {
+	return strings.Join(splitList(strings.Join(items, ",")), ",")
+}
@@ -0,0 +1,117 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+)
+
+const (
+	// maxQueryDepth 查询最大嵌套深度，超过则拒绝执行
+	maxQueryDepth = 10
+	// maxQueryFields 单次查询的字段总数上限，防止组合爆炸
+	maxQueryFields = 200
+)
+
+// Handler GraphQL端点处理器
+//
+// 把任务与社区模块聚合到一个schema下，前端一次请求即可取到
+// 跨模块数据；执行前做深度与复杂度校验，防止恶意深查询。
+type Handler struct {
+	schema        graphql.Schema
+	threadService *community.ThreadService
+}
+
+// NewHandler 创建GraphQL处理器
+func NewHandler(taskService *tasks.Service, threadService *community.ThreadService) (*Handler, error) {
+	schema, err := buildSchema(taskService, threadService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graphql schema: %w", err)
+	}
+	return &Handler{schema: schema, threadService: threadService}, nil
+}
+
+// SetupRoutes 注册GraphQL路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/graphql", h.Query)
+}
+
+// Query 执行GraphQL查询
+func (h *Handler) Query(c *gin.Context) {
+	var req struct {
+		Query     string                 `json:"query" binding:"required"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateComplexity(req.Query); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 加载器按请求创建，缓存只在单次查询内生效
+	ctx := withLoaders(c.Request.Context(), newThreadLoader(h.threadService))
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// validateComplexity 校验查询的深度与字段总数
+func validateComplexity(query string) error {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query)}),
+	})
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	fields := 0
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth := selectionDepth(operation.SelectionSet, &fields)
+		if depth > maxQueryDepth {
+			return fmt.Errorf("query depth %d exceeds limit %d", depth, maxQueryDepth)
+		}
+	}
+	if fields > maxQueryFields {
+		return fmt.Errorf("query selects %d fields, exceeds limit %d", fields, maxQueryFields)
+	}
+	return nil
+}
+
+// selectionDepth 递归计算选择集深度并累计字段数
+func selectionDepth(set *ast.SelectionSet, fields *int) int {
+	if set == nil {
+		return 0
+	}
+	max := 0
+	for _, selection := range set.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+		*fields++
+		if depth := selectionDepth(field.SelectionSet, fields); depth > max {
+			max = depth
+		}
+	}
+	return max + 1
+}
@@ -0,0 +1,90 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+)
+
+// threadLoader 按请求批量加载讨论串
+//
+// 解析器通过Load登记需要的ID并拿到一个thunk；graphql-go会在
+// 当前层的所有字段派发完成后再求值thunk，第一个被求值的thunk
+// 触发一次 IN 查询取回全部登记的ID，从而避免逐Post查询的N+1。
+type threadLoader struct {
+	service *community.ThreadService
+
+	mu      sync.Mutex
+	pending []string
+	cache   map[string]*community.Thread
+	loaded  map[string]bool
+}
+
+// newThreadLoader 创建讨论串加载器
+func newThreadLoader(service *community.ThreadService) *threadLoader {
+	return &threadLoader{
+		service: service,
+		cache:   make(map[string]*community.Thread),
+		loaded:  make(map[string]bool),
+	}
+}
+
+// Load 登记ID并返回延迟求值的thunk
+func (l *threadLoader) Load(ctx context.Context, id string) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.loaded[id] {
+		l.pending = append(l.pending, id)
+		l.loaded[id] = true
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		if err := l.flush(ctx); err != nil {
+			return nil, err
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		thread, ok := l.cache[id]
+		if !ok {
+			return nil, nil
+		}
+		return thread, nil
+	}
+}
+
+// flush 执行一次批量查询，取回所有待加载的ID
+func (l *threadLoader) flush(ctx context.Context) error {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	threads, err := l.service.GetThreadsByIDs(ctx, pending)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	for id, thread := range threads {
+		l.cache[id] = thread
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// loaderKey context键类型
+type loaderKey struct{}
+
+// withLoaders 把按请求创建的加载器挂到context
+func withLoaders(ctx context.Context, loader *threadLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+// threadLoaderFrom 从context取出讨论串加载器
+func threadLoaderFrom(ctx context.Context) *threadLoader {
+	loader, _ := ctx.Value(loaderKey{}).(*threadLoader)
+	return loader
+}
@@ -0,0 +1,221 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+)
+
+// buildSchema 构建GraphQL模式
+//
+// 解析器全部复用现有服务层，不直接访问数据库；
+// Post.thread通过批量加载器解析以避免N+1查询。
+func buildSchema(taskService *tasks.Service, threadService *community.ThreadService) (graphql.Schema, error) {
+	taskType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id":          scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.ID }),
+			"boardId":     scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.BoardID }),
+			"columnId":    scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.ColumnID }),
+			"title":       scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.Title }),
+			"description": scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.Description }),
+			"assigneeId":  scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.AssigneeID }),
+			"position":    scalarField(graphql.Int, func(t *tasks.Task) interface{} { return t.Position }),
+			"createdAt":   scalarField(graphql.String, func(t *tasks.Task) interface{} { return t.CreatedAt.Format(time.RFC3339) }),
+		},
+	})
+
+	columnType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Column",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.String},
+			"name": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tasks.BoardColumn).Name, nil
+				},
+			},
+			"position": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tasks.BoardColumn).Position, nil
+				},
+			},
+			"wipLimit": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tasks.BoardColumn).WIPLimit, nil
+				},
+			},
+			"tasks": &graphql.Field{
+				Type: graphql.NewList(taskType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					column := p.Source.(tasks.BoardColumn)
+					result := make([]*tasks.Task, 0, len(column.Tasks))
+					for i := range column.Tasks {
+						result = append(result, &column.Tasks[i])
+					}
+					return result, nil
+				},
+			},
+		},
+	})
+
+	boardType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Board",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*tasks.Board).ID, nil
+				},
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*tasks.Board).Name, nil
+				},
+			},
+			"ownerId": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*tasks.Board).OwnerID, nil
+				},
+			},
+			"columns": &graphql.Field{
+				Type: graphql.NewList(columnType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*tasks.Board).Columns, nil
+				},
+			},
+		},
+	})
+
+	threadType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Thread",
+		Fields: graphql.Fields{
+			"id":              threadField(func(t *community.Thread) interface{} { return t.ID }, graphql.String),
+			"scope":           threadField(func(t *community.Thread) interface{} { return t.Scope }, graphql.String),
+			"title":           threadField(func(t *community.Thread) interface{} { return t.Title }, graphql.String),
+			"authorId":        threadField(func(t *community.Thread) interface{} { return t.AuthorID }, graphql.String),
+			"isLocked":        threadField(func(t *community.Thread) interface{} { return t.IsLocked }, graphql.Boolean),
+			"slowModeSeconds": threadField(func(t *community.Thread) interface{} { return t.SlowModeSeconds }, graphql.Int),
+			"postCount":       threadField(func(t *community.Thread) interface{} { return int(t.PostCount) }, graphql.Int),
+			"createdAt":       threadField(func(t *community.Thread) interface{} { return t.CreatedAt.Format(time.RFC3339) }, graphql.String),
+		},
+	})
+
+	postType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.Fields{
+			"id":          postField(func(p *community.ThreadPost) interface{} { return p.ID }, graphql.String),
+			"threadId":    postField(func(p *community.ThreadPost) interface{} { return p.ThreadID }, graphql.String),
+			"authorId":    postField(func(p *community.ThreadPost) interface{} { return p.AuthorID }, graphql.String),
+			"content":     postField(func(p *community.ThreadPost) interface{} { return p.Content }, graphql.String),
+			"needsReview": postField(func(p *community.ThreadPost) interface{} { return p.NeedsReview }, graphql.Boolean),
+			"createdAt":   postField(func(p *community.ThreadPost) interface{} { return p.CreatedAt.Format(time.RFC3339) }, graphql.String),
+		},
+	})
+
+	// Post.thread 通过加载器批量解析，避免逐条查询
+	postType.AddFieldConfig("thread", &graphql.Field{
+		Type: threadType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			post := p.Source.(*community.ThreadPost)
+			loader := threadLoaderFrom(p.Context)
+			if loader == nil {
+				return nil, fmt.Errorf("thread loader missing from context")
+			}
+			thunk := loader.Load(p.Context, post.ThreadID)
+			return func() (interface{}, error) { return thunk() }, nil
+		},
+	})
+
+	// Thread.posts 嵌套分页
+	threadType.AddFieldConfig("posts", &graphql.Field{
+		Type: graphql.NewList(postType),
+		Args: graphql.FieldConfigArgument{
+			"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 50},
+			"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			thread := p.Source.(*community.Thread)
+			limit, _ := p.Args["limit"].(int)
+			offset, _ := p.Args["offset"].(int)
+			return threadService.ListPosts(p.Context, thread.ID, limit, offset)
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"board": &graphql.Field{
+				Type: boardType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return taskService.GetBoard(p.Context, p.Args["id"].(string))
+				},
+			},
+			"thread": &graphql.Field{
+				Type: threadType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return threadService.GetThread(p.Context, p.Args["id"].(string))
+				},
+			},
+			"posts": &graphql.Field{
+				Type: graphql.NewList(postType),
+				Args: graphql.FieldConfigArgument{
+					"threadId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"limit":    &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 50},
+					"offset":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, _ := p.Args["limit"].(int)
+					offset, _ := p.Args["offset"].(int)
+					return threadService.ListPosts(p.Context, p.Args["threadId"].(string), limit, offset)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// scalarField 任务标量字段的简写
+func scalarField(kind graphql.Output, extract func(*tasks.Task) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: kind,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return extract(p.Source.(*tasks.Task)), nil
+		},
+	}
+}
+
+// threadField 讨论串标量字段的简写
+func threadField(extract func(*community.Thread) interface{}, kind graphql.Output) *graphql.Field {
+	return &graphql.Field{
+		Type: kind,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return extract(p.Source.(*community.Thread)), nil
+		},
+	}
+}
+
+// postField 回复标量字段的简写
+func postField(extract func(*community.ThreadPost) interface{}, kind graphql.Output) *graphql.Field {
+	return &graphql.Field{
+		Type: kind,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return extract(p.Source.(*community.ThreadPost)), nil
+		},
+	}
+}
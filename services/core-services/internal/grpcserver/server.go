@@ -0,0 +1,202 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+	"github.com/codetaoist/taishanglaojun/core-services/proto/corepb"
+)
+
+// Server 核心服务的gRPC入口
+//
+// 与REST并行暴露任务与社区模块，供内部服务低开销调用；
+// 包含标准health与reflection服务，方便探活与grpcurl调试。
+type Server struct {
+	grpc *grpc.Server
+}
+
+// New 创建gRPC服务器并注册各模块
+func New(taskService *tasks.Service, threadService *community.ThreadService) *Server {
+	server := grpc.NewServer()
+
+	corepb.RegisterTaskServiceServer(server, &taskServer{service: taskService})
+	corepb.RegisterCommunityServiceServer(server, &communityServer{service: threadService})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{grpc: server}
+}
+
+// Serve 在指定端口阻塞监听
+func (s *Server) Serve(port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on grpc port %s: %w", port, err)
+	}
+	return s.grpc.Serve(listener)
+}
+
+// Stop 优雅停止
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// taskServer 任务模块的gRPC适配
+type taskServer struct {
+	corepb.UnimplementedTaskServiceServer
+	service *tasks.Service
+}
+
+// GetBoard 获取看板及其列与任务
+func (s *taskServer) GetBoard(ctx context.Context, req *corepb.GetBoardRequest) (*corepb.Board, error) {
+	board, err := s.service.GetBoard(ctx, req.GetBoardId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &corepb.Board{
+		Id:      board.ID,
+		Name:    board.Name,
+		OwnerId: board.OwnerID,
+	}
+	for _, column := range board.Columns {
+		pbColumn := &corepb.Column{
+			Id:       column.ID,
+			Name:     column.Name,
+			WipLimit: int32(column.WIPLimit),
+		}
+		for _, task := range column.Tasks {
+			pbColumn.Tasks = append(pbColumn.Tasks, taskToProto(&task))
+		}
+		resp.Columns = append(resp.Columns, pbColumn)
+	}
+	return resp, nil
+}
+
+// CreateTask 创建任务
+func (s *taskServer) CreateTask(ctx context.Context, req *corepb.CreateTaskRequest) (*corepb.Task, error) {
+	task, err := s.service.CreateTask(ctx, req.GetBoardId(), &tasks.CreateTaskRequest{
+		ColumnID:    req.GetColumnId(),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		AssigneeID:  req.GetAssigneeId(),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return taskToProto(task), nil
+}
+
+// MoveTask 移动任务
+func (s *taskServer) MoveTask(ctx context.Context, req *corepb.MoveTaskRequest) (*corepb.Task, error) {
+	task, err := s.service.MoveTask(ctx, req.GetTaskId(), &tasks.MoveTaskRequest{
+		ColumnID: req.GetColumnId(),
+		Position: int(req.GetPosition()),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return taskToProto(task), nil
+}
+
+// communityServer 社区模块的gRPC适配
+type communityServer struct {
+	corepb.UnimplementedCommunityServiceServer
+	service *community.ThreadService
+}
+
+// GetThread 获取主题
+func (s *communityServer) GetThread(ctx context.Context, req *corepb.GetThreadRequest) (*corepb.Thread, error) {
+	thread, err := s.service.GetThread(ctx, req.GetThreadId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &corepb.Thread{
+		Id:              thread.ID,
+		Scope:           thread.Scope,
+		Title:           thread.Title,
+		AuthorId:        thread.AuthorID,
+		IsLocked:        thread.IsLocked,
+		SlowModeSeconds: int32(thread.SlowModeSeconds),
+		PostCount:       thread.PostCount,
+		CreatedAt:       thread.CreatedAt.Unix(),
+	}, nil
+}
+
+// ListPosts 列出主题下的发言
+func (s *communityServer) ListPosts(ctx context.Context, req *corepb.ListPostsRequest) (*corepb.ListPostsResponse, error) {
+	posts, err := s.service.ListPosts(ctx, req.GetThreadId(), int(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &corepb.ListPostsResponse{}
+	for _, post := range posts {
+		resp.Posts = append(resp.Posts, postToProto(post))
+	}
+	return resp, nil
+}
+
+// AddPost 发表回复
+func (s *communityServer) AddPost(ctx context.Context, req *corepb.AddPostRequest) (*corepb.Post, error) {
+	post, err := s.service.AddPost(ctx, req.GetThreadId(), req.GetAuthorId(), req.GetContent())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return postToProto(post), nil
+}
+
+// taskToProto 任务模型转proto
+func taskToProto(task *tasks.Task) *corepb.Task {
+	return &corepb.Task{
+		Id:          task.ID,
+		BoardId:     task.BoardID,
+		ColumnId:    task.ColumnID,
+		Title:       task.Title,
+		Description: task.Description,
+		AssigneeId:  task.AssigneeID,
+		Position:    int32(task.Position),
+		CreatedAt:   task.CreatedAt.Unix(),
+	}
+}
+
+// postToProto 发言模型转proto
+func postToProto(post *community.ThreadPost) *corepb.Post {
+	return &corepb.Post{
+		Id:          post.ID,
+		ThreadId:    post.ThreadID,
+		AuthorId:    post.AuthorID,
+		Content:     post.Content,
+		NeedsReview: post.NeedsReview,
+		CreatedAt:   post.CreatedAt.Unix(),
+	}
+}
+
+// grpcError 把业务错误映射为gRPC状态码
+func grpcError(err error) error {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return status.Error(codes.NotFound, message)
+	case strings.Contains(message, "locked"),
+		strings.Contains(message, "rejected"),
+		strings.Contains(message, "limit"):
+		return status.Error(codes.FailedPrecondition, message)
+	default:
+		return status.Error(codes.Internal, message)
+	}
+}
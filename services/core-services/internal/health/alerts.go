@@ -0,0 +1,357 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 告警规则类型
+const (
+	RuleThreshold = "threshold"
+	RuleTrend     = "trend"
+)
+
+// 比较方向
+const (
+	OperatorAbove = "above"
+	OperatorBelow = "below"
+)
+
+// 告警生命周期状态
+const (
+	AlertOpen         = "open"
+	AlertAcknowledged = "acknowledged"
+	AlertResolved     = "resolved"
+)
+
+// AlertRule 健康告警规则
+//
+// threshold规则要求指标的日均值连续WindowDays天越过Threshold
+// （如静息心率>100持续3天）；trend规则比较最近窗口与前一窗口
+// 的均值差（如体重一周变化>2kg）。
+type AlertRule struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// CreatedBy 创建者，可以是用户本人或其医护
+	CreatedBy string `json:"createdBy" gorm:"type:varchar(36)"`
+	Name      string `json:"name" gorm:"type:varchar(128);not null"`
+	Metric    string `json:"metric" gorm:"type:varchar(32);not null"`
+	// Type threshold/trend
+	Type string `json:"type" gorm:"type:varchar(16);not null"`
+	// Operator above/below
+	Operator  string  `json:"operator" gorm:"type:varchar(8);not null"`
+	Threshold float64 `json:"threshold"`
+	// ChangeAmount trend规则的变化量阈值
+	ChangeAmount float64 `json:"changeAmount"`
+	// WindowDays 评估窗口天数
+	WindowDays int       `json:"windowDays"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (AlertRule) TableName() string {
+	return "cs_health_alert_rules"
+}
+
+// Alert 一次触发的健康告警
+type Alert struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RuleID string `json:"ruleId" gorm:"index;type:varchar(36);not null"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// Status open/acknowledged/resolved
+	Status  string `json:"status" gorm:"type:varchar(16);not null"`
+	Message string `json:"message" gorm:"type:varchar(512)"`
+	// Value 触发时的观测值（threshold为窗口内日均值，trend为变化量）
+	Value          float64    `json:"value"`
+	TriggeredAt    time.Time  `json:"triggeredAt"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Alert) TableName() string {
+	return "cs_health_alerts"
+}
+
+// SetEventPublisher 接线业务事件发布（通知集成）
+func (s *Service) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// CreateRuleRequest 创建告警规则的参数
+type CreateRuleRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	Metric       string  `json:"metric" binding:"required"`
+	Type         string  `json:"type" binding:"required"`
+	Operator     string  `json:"operator" binding:"required"`
+	Threshold    float64 `json:"threshold"`
+	ChangeAmount float64 `json:"changeAmount"`
+	WindowDays   int     `json:"windowDays"`
+	CreatedBy    string  `json:"createdBy"`
+}
+
+// CreateRule 创建告警规则
+func (s *Service) CreateRule(ctx context.Context, userID string, req *CreateRuleRequest) (*AlertRule, error) {
+	if err := validMetric(req.Metric); err != nil {
+		return nil, err
+	}
+	if req.Type != RuleThreshold && req.Type != RuleTrend {
+		return nil, fmt.Errorf("invalid rule type: %s", req.Type)
+	}
+	if req.Operator != OperatorAbove && req.Operator != OperatorBelow {
+		return nil, fmt.Errorf("invalid operator: %s", req.Operator)
+	}
+	if req.Type == RuleTrend && req.ChangeAmount <= 0 {
+		return nil, fmt.Errorf("changeAmount must be positive for trend rules")
+	}
+	if req.WindowDays <= 0 {
+		req.WindowDays = 1
+	}
+	if req.WindowDays > 90 {
+		return nil, fmt.Errorf("invalid windowDays: maximum 90")
+	}
+	rule := &AlertRule{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		CreatedBy:    req.CreatedBy,
+		Name:         req.Name,
+		Metric:       req.Metric,
+		Type:         req.Type,
+		Operator:     req.Operator,
+		Threshold:    req.Threshold,
+		ChangeAmount: req.ChangeAmount,
+		WindowDays:   req.WindowDays,
+		Enabled:      true,
+	}
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules 列出用户的告警规则
+func (s *Service) ListRules(ctx context.Context, userID string) ([]*AlertRule, error) {
+	var rules []*AlertRule
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteRule 删除用户的告警规则
+func (s *Service) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	result := s.db.WithContext(ctx).
+		Delete(&AlertRule{}, "id = ? AND user_id = ?", ruleID, userID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("alert rule not found")
+	}
+	return nil
+}
+
+// ListAlerts 列出用户的告警，可按状态过滤
+func (s *Service) ListAlerts(ctx context.Context, userID, status string) ([]*Alert, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var alerts []*Alert
+	if err := query.Order("triggered_at DESC").Limit(200).Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// AcknowledgeAlert 确认告警
+func (s *Service) AcknowledgeAlert(ctx context.Context, userID, alertID string) (*Alert, error) {
+	return s.transitionAlert(ctx, userID, alertID, AlertAcknowledged)
+}
+
+// ResolveAlert 关闭告警
+func (s *Service) ResolveAlert(ctx context.Context, userID, alertID string) (*Alert, error) {
+	return s.transitionAlert(ctx, userID, alertID, AlertResolved)
+}
+
+// transitionAlert 推进告警生命周期
+func (s *Service) transitionAlert(ctx context.Context, userID, alertID, target string) (*Alert, error) {
+	var alert Alert
+	err := s.db.WithContext(ctx).
+		First(&alert, "id = ? AND user_id = ?", alertID, userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return nil, fmt.Errorf("failed to load alert: %w", err)
+	}
+	now := time.Now()
+	switch target {
+	case AlertAcknowledged:
+		if alert.Status != AlertOpen {
+			return nil, fmt.Errorf("invalid transition: only open alerts can be acknowledged")
+		}
+		alert.Status = AlertAcknowledged
+		alert.AcknowledgedAt = &now
+	case AlertResolved:
+		if alert.Status == AlertResolved {
+			return nil, fmt.Errorf("invalid transition: alert already resolved")
+		}
+		alert.Status = AlertResolved
+		alert.ResolvedAt = &now
+	}
+	if err := s.db.WithContext(ctx).Save(&alert).Error; err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+	return &alert, nil
+}
+
+// evaluateRules 评估所有启用的规则，触发新告警
+func (s *Service) evaluateRules(ctx context.Context) {
+	var rules []*AlertRule
+	err := s.db.WithContext(ctx).Where("enabled = ?", true).Limit(1000).Find(&rules).Error
+	if err != nil {
+		s.logger.Error("failed to load alert rules", zap.Error(err))
+		return
+	}
+	now := time.Now()
+	for _, rule := range rules {
+		triggered, value, err := s.evaluateRule(ctx, rule, now)
+		if err != nil {
+			s.logger.Error("failed to evaluate alert rule",
+				zap.String("ruleId", rule.ID), zap.Error(err))
+			continue
+		}
+		if !triggered {
+			continue
+		}
+		s.triggerAlert(ctx, rule, value, now)
+	}
+}
+
+// evaluateRule 评估单条规则，返回是否触发与观测值
+func (s *Service) evaluateRule(ctx context.Context, rule *AlertRule, now time.Time) (bool, float64, error) {
+	switch rule.Type {
+	case RuleThreshold:
+		return s.evaluateThreshold(ctx, rule, now)
+	case RuleTrend:
+		return s.evaluateTrend(ctx, rule, now)
+	default:
+		return false, 0, fmt.Errorf("invalid rule type: %s", rule.Type)
+	}
+}
+
+// evaluateThreshold 判断日均值是否连续越界
+//
+// 窗口内每一天都要有数据且日均值越过阈值才触发，缺数据的
+// 天视为未越界，避免稀疏数据误报。
+func (s *Service) evaluateThreshold(ctx context.Context, rule *AlertRule, now time.Time) (bool, float64, error) {
+	var total float64
+	for day := 0; day < rule.WindowDays; day++ {
+		to := now.AddDate(0, 0, -day)
+		from := to.AddDate(0, 0, -1)
+		avg, count, err := s.metricAverage(ctx, rule.UserID, rule.Metric, from, to)
+		if err != nil {
+			return false, 0, err
+		}
+		if count == 0 || !violates(avg, rule.Operator, rule.Threshold) {
+			return false, 0, nil
+		}
+		total += avg
+	}
+	return true, total / float64(rule.WindowDays), nil
+}
+
+// evaluateTrend 判断最近窗口相对前一窗口的均值变化是否超限
+func (s *Service) evaluateTrend(ctx context.Context, rule *AlertRule, now time.Time) (bool, float64, error) {
+	window := time.Duration(rule.WindowDays) * 24 * time.Hour
+	recentAvg, recentCount, err := s.metricAverage(ctx, rule.UserID, rule.Metric, now.Add(-window), now)
+	if err != nil {
+		return false, 0, err
+	}
+	previousAvg, previousCount, err := s.metricAverage(ctx, rule.UserID, rule.Metric, now.Add(-2*window), now.Add(-window))
+	if err != nil {
+		return false, 0, err
+	}
+	if recentCount == 0 || previousCount == 0 {
+		return false, 0, nil
+	}
+	delta := recentAvg - previousAvg
+	switch rule.Operator {
+	case OperatorAbove:
+		return delta >= rule.ChangeAmount, delta, nil
+	default:
+		return delta <= -rule.ChangeAmount, delta, nil
+	}
+}
+
+// metricAverage 求区间内指标均值与样本数
+func (s *Service) metricAverage(ctx context.Context, userID, metric string, from, to time.Time) (float64, int64, error) {
+	var result struct {
+		Avg   float64
+		Count int64
+	}
+	err := s.db.WithContext(ctx).Model(&HealthData{}).
+		Select("AVG(value) AS avg, COUNT(*) AS count").
+		Where("user_id = ? AND metric = ? AND recorded_at >= ? AND recorded_at < ?",
+			userID, metric, from, to).
+		Scan(&result).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to aggregate health data: %w", err)
+	}
+	return result.Avg, result.Count, nil
+}
+
+// triggerAlert 创建告警并发布事件，已有未关闭告警时不重复
+func (s *Service) triggerAlert(ctx context.Context, rule *AlertRule, value float64, now time.Time) {
+	var open int64
+	err := s.db.WithContext(ctx).Model(&Alert{}).
+		Where("rule_id = ? AND status <> ?", rule.ID, AlertResolved).
+		Count(&open).Error
+	if err != nil {
+		s.logger.Error("failed to check open alerts", zap.Error(err))
+		return
+	}
+	if open > 0 {
+		return
+	}
+	alert := &Alert{
+		ID:          uuid.NewString(),
+		RuleID:      rule.ID,
+		UserID:      rule.UserID,
+		Status:      AlertOpen,
+		Message:     fmt.Sprintf("规则「%s」触发：%s 观测值 %.2f", rule.Name, rule.Metric, value),
+		Value:       value,
+		TriggeredAt: now,
+	}
+	if err := s.db.WithContext(ctx).Create(alert).Error; err != nil {
+		s.logger.Error("failed to create alert", zap.Error(err))
+		return
+	}
+	s.logger.Info("health alert triggered",
+		zap.String("ruleId", rule.ID), zap.String("userId", rule.UserID))
+	if s.publish != nil {
+		s.publish("health.alert.triggered", alert)
+	}
+}
+
+// violates 判断值是否越过阈值
+func violates(value float64, operator string, threshold float64) bool {
+	if operator == OperatorAbove {
+		return value > threshold
+	}
+	return value < threshold
+}
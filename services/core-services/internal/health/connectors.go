@@ -0,0 +1,316 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GoogleFitConnector 通过Google Fit REST API拉取数据
+//
+// 使用aggregate接口按天聚合步数与睡眠、按原始点拉取心率；
+// 令牌由客户端完成OAuth后交给服务端保存。
+type GoogleFitConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewGoogleFitConnector 创建Google Fit连接器
+func NewGoogleFitConnector() *GoogleFitConnector {
+	return &GoogleFitConnector{
+		baseURL: "https://www.googleapis.com/fitness/v1",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Provider 返回来源标识
+func (g *GoogleFitConnector) Provider() string {
+	return SourceGoogleFit
+}
+
+// googleFitDataTypes 指标到Google Fit数据类型的映射
+var googleFitDataTypes = map[string]struct {
+	dataType string
+	unit     string
+}{
+	MetricSteps:     {"com.google.step_count.delta", "步"},
+	MetricHeartRate: {"com.google.heart_rate.bpm", "bpm"},
+	MetricSleep:     {"com.google.sleep.segment", "分钟"},
+}
+
+// Fetch 拉取区间内按天聚合的数据点
+func (g *GoogleFitConnector) Fetch(ctx context.Context, conn *Connection, since, until time.Time) ([]*Point, error) {
+	if conn.ExpiresAt != nil && conn.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("google fit token expired, reconnect required")
+	}
+	var points []*Point
+	for metric, spec := range googleFitDataTypes {
+		body, err := json.Marshal(map[string]interface{}{
+			"aggregateBy":     []map[string]string{{"dataTypeName": spec.dataType}},
+			"bucketByTime":    map[string]int64{"durationMillis": 24 * time.Hour.Milliseconds()},
+			"startTimeMillis": since.UnixMilli(),
+			"endTimeMillis":   until.UnixMilli(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			g.baseURL+"/users/me/dataset:aggregate", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("google fit request failed: %w", err)
+		}
+		var parsed struct {
+			Bucket []struct {
+				StartTimeMillis string `json:"startTimeMillis"`
+				Dataset         []struct {
+					Point []struct {
+						Value []struct {
+							IntVal float64 `json:"intVal"`
+							FpVal  float64 `json:"fpVal"`
+						} `json:"value"`
+					} `json:"point"`
+				} `json:"dataset"`
+			} `json:"bucket"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("google fit returned status %d", resp.StatusCode)
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode google fit response: %w", decodeErr)
+		}
+		for _, bucket := range parsed.Bucket {
+			millis, err := strconv.ParseInt(bucket.StartTimeMillis, 10, 64)
+			if err != nil {
+				continue
+			}
+			var total float64
+			var count int
+			for _, dataset := range bucket.Dataset {
+				for _, p := range dataset.Point {
+					for _, v := range p.Value {
+						total += v.IntVal + v.FpVal
+						count++
+					}
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			value := total
+			if metric == MetricHeartRate {
+				// 心率取桶内均值，其余指标取总量
+				value = total / float64(count)
+			}
+			points = append(points, &Point{
+				Metric:     metric,
+				Value:      value,
+				Unit:       spec.unit,
+				RecordedAt: time.UnixMilli(millis),
+				SourceRef:  spec.dataType + ":" + bucket.StartTimeMillis,
+			})
+		}
+	}
+	return points, nil
+}
+
+// HuaweiConnector 通过华为运动健康REST API拉取数据
+type HuaweiConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHuaweiConnector 创建华为连接器
+func NewHuaweiConnector() *HuaweiConnector {
+	return &HuaweiConnector{
+		baseURL: "https://health-api.cloud.huawei.com/healthkit/v1",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Provider 返回来源标识
+func (h *HuaweiConnector) Provider() string {
+	return SourceHuawei
+}
+
+// huaweiDataTypes 指标到华为数据类型的映射
+var huaweiDataTypes = map[string]struct {
+	dataType string
+	unit     string
+}{
+	MetricSteps:     {"com.huawei.continuous.steps.delta", "步"},
+	MetricHeartRate: {"com.huawei.instantaneous.heart_rate", "bpm"},
+	MetricSleep:     {"com.huawei.continuous.sleep.fragment", "分钟"},
+}
+
+// Fetch 拉取区间内按天聚合的数据点
+func (h *HuaweiConnector) Fetch(ctx context.Context, conn *Connection, since, until time.Time) ([]*Point, error) {
+	if conn.ExpiresAt != nil && conn.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("huawei token expired, reconnect required")
+	}
+	var points []*Point
+	for metric, spec := range huaweiDataTypes {
+		body, err := json.Marshal(map[string]interface{}{
+			"polymerizeWith":  []map[string]string{{"dataTypeName": spec.dataType}},
+			"groupByTime":     map[string]int64{"duration": 24 * time.Hour.Milliseconds()},
+			"startTimeMillis": since.UnixMilli(),
+			"endTimeMillis":   until.UnixMilli(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			h.baseURL+"/sampleSet:polymerize", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := h.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("huawei health request failed: %w", err)
+		}
+		var parsed struct {
+			Group []struct {
+				StartTime int64 `json:"startTime"`
+				SampleSet []struct {
+					SamplePoints []struct {
+						Value []struct {
+							IntegerValue int64   `json:"integerValue"`
+							FloatValue   float64 `json:"floatValue"`
+						} `json:"value"`
+					} `json:"samplePoints"`
+				} `json:"sampleSet"`
+			} `json:"group"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("huawei health returned status %d", resp.StatusCode)
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode huawei response: %w", decodeErr)
+		}
+		for _, group := range parsed.Group {
+			var total float64
+			var count int
+			for _, sampleSet := range group.SampleSet {
+				for _, p := range sampleSet.SamplePoints {
+					for _, v := range p.Value {
+						total += float64(v.IntegerValue) + v.FloatValue
+						count++
+					}
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			value := total
+			if metric == MetricHeartRate {
+				value = total / float64(count)
+			}
+			points = append(points, &Point{
+				Metric:     metric,
+				Value:      value,
+				Unit:       spec.unit,
+				RecordedAt: time.UnixMilli(group.StartTime),
+				SourceRef:  spec.dataType + ":" + strconv.FormatInt(group.StartTime, 10),
+			})
+		}
+	}
+	return points, nil
+}
+
+// appleTypeMap Apple Health导出记录类型到指标的映射
+var appleTypeMap = map[string]struct {
+	metric string
+	unit   string
+}{
+	"HKQuantityTypeIdentifierStepCount":     {MetricSteps, "步"},
+	"HKQuantityTypeIdentifierHeartRate":     {MetricHeartRate, "bpm"},
+	"HKCategoryTypeIdentifierSleepAnalysis": {MetricSleep, "分钟"},
+}
+
+// appleTimeLayout Apple Health导出文件的时间格式
+const appleTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// ImportAppleHealth 解析Apple Health导出文件（export.xml）并入库
+//
+// Apple没有服务端API，客户端从健康App导出数据后上传；
+// 流式解码避免把整个导出文件（常见数百MB）读入内存。
+func (s *Service) ImportAppleHealth(ctx context.Context, userID string, r io.Reader) (int, error) {
+	decoder := xml.NewDecoder(r)
+	var points []*Point
+	var imported int
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, fmt.Errorf("failed to parse apple health export: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Record" {
+			continue
+		}
+		attrs := make(map[string]string, len(start.Attr))
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		spec, ok := appleTypeMap[attrs["type"]]
+		if !ok {
+			continue
+		}
+		startAt, err := time.Parse(appleTimeLayout, attrs["startDate"])
+		if err != nil {
+			continue
+		}
+		var value float64
+		if spec.metric == MetricSleep {
+			// 睡眠记录没有value，用起止时长换算分钟
+			endAt, err := time.Parse(appleTimeLayout, attrs["endDate"])
+			if err != nil {
+				continue
+			}
+			value = endAt.Sub(startAt).Minutes()
+		} else {
+			value, err = strconv.ParseFloat(attrs["value"], 64)
+			if err != nil {
+				continue
+			}
+		}
+		points = append(points, &Point{
+			Metric:     spec.metric,
+			Value:      value,
+			Unit:       spec.unit,
+			RecordedAt: startAt,
+			SourceRef:  attrs["type"] + ":" + attrs["startDate"],
+		})
+		// 分批入库，限制内存占用
+		if len(points) >= 500 {
+			n, err := s.ingest(ctx, userID, SourceAppleHealth, points)
+			imported += n
+			if err != nil {
+				return imported, err
+			}
+			points = points[:0]
+		}
+	}
+	n, err := s.ingest(ctx, userID, SourceAppleHealth, points)
+	imported += n
+	return imported, err
+}
@@ -0,0 +1,343 @@
+package health
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 健康数据HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建健康数据处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册健康数据相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/health-data", h.Record)
+	router.GET("/health-data", h.List)
+	router.POST("/health-data/connections", h.Connect)
+	router.GET("/health-data/connections", h.ListConnections)
+	router.DELETE("/health-data/connections/:provider", h.Disconnect)
+	router.POST("/health-data/connections/:provider/sync", h.Sync)
+	router.POST("/health-data/import/apple", h.ImportApple)
+	router.GET("/health-data/trends", h.Trends)
+	router.POST("/health-data/alert-rules", h.CreateRule)
+	router.GET("/health-data/alert-rules", h.ListRules)
+	router.DELETE("/health-data/alert-rules/:ruleId", h.DeleteRule)
+	router.GET("/health-data/alerts", h.ListAlerts)
+	router.POST("/health-data/alerts/:alertId/acknowledge", h.AcknowledgeAlert)
+	router.POST("/health-data/alerts/:alertId/resolve", h.ResolveAlert)
+	// 分享令牌使PDF可由未登录的查看方打开
+	router.GET("/health-reports/:userId/pdf", h.ReportPDF)
+	router.POST("/health-reports/:userId/share", h.ShareReport)
+}
+
+// requireUser 从请求头取用户标识
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// Record 手动录入健康数据
+func (h *Handler) Record(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req RecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	record, err := h.service.Record(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, record)
+}
+
+// List 查询健康数据
+func (h *Handler) List(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be RFC3339"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be RFC3339"})
+		return
+	}
+	records, err := h.service.List(c.Request.Context(), userID, c.Query("metric"), from, to)
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": records})
+}
+
+// Connect 保存平台授权连接
+func (h *Handler) Connect(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req ConnectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	conn, err := h.service.Connect(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, conn)
+}
+
+// ListConnections 列出连接及同步状态
+func (h *Handler) ListConnections(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	conns, err := h.service.ListConnections(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"connections": conns})
+}
+
+// Disconnect 删除平台连接
+func (h *Handler) Disconnect(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Disconnect(c.Request.Context(), userID, c.Param("provider")); err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "连接已删除"})
+}
+
+// Sync 手动触发一次增量同步
+func (h *Handler) Sync(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	conn, err := h.service.Sync(c.Request.Context(), userID, c.Param("provider"))
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, conn)
+}
+
+// ImportApple 上传并导入Apple Health导出文件
+func (h *Handler) ImportApple(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	imported, err := h.service.ImportAppleHealth(c.Request.Context(), userID, c.Request.Body)
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"imported": imported})
+}
+
+// Trends 查询指标的时间序列聚合
+func (h *Handler) Trends(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be RFC3339"})
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be RFC3339"})
+		return
+	}
+	result, err := h.service.Trends(c.Request.Context(), userID,
+		c.Query("metric"), from, to, c.Query("granularity"))
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateRule 创建告警规则
+func (h *Handler) CreateRule(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req CreateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := h.service.CreateRule(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// ListRules 列出告警规则
+func (h *Handler) ListRules(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	rules, err := h.service.ListRules(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// DeleteRule 删除告警规则
+func (h *Handler) DeleteRule(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteRule(c.Request.Context(), userID, c.Param("ruleId")); err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "规则已删除"})
+}
+
+// ListAlerts 列出告警
+func (h *Handler) ListAlerts(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	alerts, err := h.service.ListAlerts(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+// AcknowledgeAlert 确认告警
+func (h *Handler) AcknowledgeAlert(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	alert, err := h.service.AcknowledgeAlert(c.Request.Context(), userID, c.Param("alertId"))
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// ResolveAlert 关闭告警
+func (h *Handler) ResolveAlert(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	alert, err := h.service.ResolveAlert(c.Request.Context(), userID, c.Param("alertId"))
+	if err != nil {
+		c.JSON(statusForHealthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// ReportPDF 下载健康报告PDF
+//
+// 本人（X-User-ID匹配）或持有效分享令牌的访问方可下载。
+func (h *Handler) ReportPDF(c *gin.Context) {
+	userID := c.Param("userId")
+	requester := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if requester != userID {
+		exp, err := strconv.ParseInt(c.Query("exp"), 10, 64)
+		if err != nil || !h.service.VerifyReportToken(userID, c.Query("token"), exp) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid or expired share token"})
+			return
+		}
+	}
+	data, err := h.service.RenderReportPDF(c.Request.Context(), userID, c.DefaultQuery("lang", "zh"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="health-report.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// ShareReport 生成报告的限时分享链接
+func (h *Handler) ShareReport(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if userID != c.Param("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "can only share your own report"})
+		return
+	}
+	var req struct {
+		TTLMinutes int `json:"ttlMinutes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TTLMinutes <= 0 || req.TTLMinutes > 7*24*60 {
+		req.TTLMinutes = 24 * 60
+	}
+	url, expiresAt := h.service.ShareReportURL(userID, time.Duration(req.TTLMinutes)*time.Minute)
+	c.JSON(http.StatusCreated, gin.H{"url": url, "expiresAt": expiresAt})
+}
+
+// statusForHealthError 根据错误内容映射HTTP状态码
+func statusForHealthError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "unknown provider"),
+		strings.Contains(msg, "failed to parse"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "expired"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,265 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+// reportChartDays 报告图表覆盖的天数
+const reportChartDays = 14
+
+// reportLabels 报告文案的本地化表
+var reportLabels = map[string]map[string]string{
+	"zh": {
+		"title":           "健康报告",
+		"generated":       "生成时间",
+		"steps":           "每日步数",
+		"heart_rate":      "心率（bpm）",
+		"sleep":           "睡眠（分钟）",
+		"weight":          "体重（kg）",
+		"avg":             "均值",
+		"max":             "最大",
+		"min":             "最小",
+		"no_data":         "该指标暂无数据",
+		"recommendations": "建议",
+		"rec_steps":       "近两周日均步数偏低，建议每天步行30分钟以上。",
+		"rec_sleep":       "近两周平均睡眠不足7小时，建议规律作息。",
+		"rec_heart":       "近两周平均心率偏高，如持续请咨询医生。",
+		"rec_ok":          "各项指标处于正常范围，请继续保持。",
+	},
+	"en": {
+		"title":           "Health Report",
+		"generated":       "Generated at",
+		"steps":           "Daily steps",
+		"heart_rate":      "Heart rate (bpm)",
+		"sleep":           "Sleep (minutes)",
+		"weight":          "Weight (kg)",
+		"avg":             "avg",
+		"max":             "max",
+		"min":             "min",
+		"no_data":         "No data for this metric",
+		"recommendations": "Recommendations",
+		"rec_steps":       "Average daily steps are low; aim for at least 30 minutes of walking per day.",
+		"rec_sleep":       "Average sleep is under 7 hours; try to keep a regular schedule.",
+		"rec_heart":       "Average heart rate is elevated; consult a doctor if it persists.",
+		"rec_ok":          "All metrics are within normal ranges. Keep it up.",
+	},
+}
+
+// reportMetrics 报告展示的指标顺序
+var reportMetrics = []string{MetricSteps, MetricHeartRate, MetricSleep, MetricWeight}
+
+// SetReportSecret 设置报告分享链接的签名密钥
+func (s *Service) SetReportSecret(secret string) {
+	s.reportSecret = secret
+}
+
+// ShareReportURL 生成带过期时间的报告分享链接
+//
+// 令牌为HMAC(userID|exp)，无须落库；链接可转发给医护等
+// 未登录的查看方。
+func (s *Service) ShareReportURL(userID string, ttl time.Duration) (string, time.Time) {
+	exp := time.Now().Add(ttl)
+	token := s.reportToken(userID, exp.Unix())
+	url := fmt.Sprintf("/api/v1/health-reports/%s/pdf?token=%s&exp=%d", userID, token, exp.Unix())
+	return url, exp
+}
+
+// VerifyReportToken 校验分享令牌
+func (s *Service) VerifyReportToken(userID, token string, exp int64) bool {
+	if exp < time.Now().Unix() {
+		return false
+	}
+	expected := s.reportToken(userID, exp)
+	return hmac.Equal([]byte(token), []byte(expected))
+}
+
+// reportToken 计算分享令牌
+func (s *Service) reportToken(userID string, exp int64) string {
+	mac := hmac.New(sha256.New, []byte(s.reportSecret))
+	mac.Write([]byte(userID + "|" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RenderReportPDF 渲染用户的健康报告PDF
+//
+// 服务端直接产出PDF内容流（图表用路径算子绘制），不依赖
+// 外部渲染服务；中文通过标准STSong CID字体输出，无须内嵌。
+func (s *Service) RenderReportPDF(ctx context.Context, userID, lang string) ([]byte, error) {
+	labels, ok := reportLabels[lang]
+	if !ok {
+		labels = reportLabels["zh"]
+	}
+	now := time.Now()
+
+	var content bytes.Buffer
+	y := 800.0
+	pdfText(&content, 50, y, 20, labels["title"])
+	y -= 22
+	pdfText(&content, 50, y, 10, labels["generated"]+": "+now.Format("2006-01-02 15:04"))
+	y -= 30
+
+	var lowSteps, shortSleep, highHeart bool
+	for _, metric := range reportMetrics {
+		values, err := s.dailyAverages(ctx, userID, metric, now)
+		if err != nil {
+			return nil, err
+		}
+		pdfText(&content, 50, y, 12, labels[metric])
+		y -= 14
+		avg, min, max, samples := summarize(values)
+		if samples == 0 {
+			pdfText(&content, 50, y-10, 9, labels["no_data"])
+			y -= 40
+			continue
+		}
+		pdfText(&content, 50, y, 9, fmt.Sprintf("%s %.1f  %s %.1f  %s %.1f",
+			labels["avg"], avg, labels["max"], max, labels["min"], min))
+		y -= 70
+		pdfBarChart(&content, 50, y, 460, 55, values)
+		y -= 30
+
+		switch metric {
+		case MetricSteps:
+			lowSteps = avg < 6000
+		case MetricSleep:
+			shortSleep = avg < 420
+		case MetricHeartRate:
+			highHeart = avg > 90
+		}
+	}
+
+	pdfText(&content, 50, y, 12, labels["recommendations"])
+	y -= 16
+	var any bool
+	for _, rec := range []struct {
+		key string
+		hit bool
+	}{
+		{"rec_steps", lowSteps},
+		{"rec_sleep", shortSleep},
+		{"rec_heart", highHeart},
+	} {
+		if rec.hit {
+			key := rec.key
+			pdfText(&content, 50, y, 9, "- "+labels[key])
+			y -= 13
+			any = true
+		}
+	}
+	if !any {
+		pdfText(&content, 50, y, 9, "- "+labels["rec_ok"])
+	}
+
+	return buildPDF(content.Bytes()), nil
+}
+
+// dailyAverages 求指标最近reportChartDays天的日均值序列（旧到新）
+func (s *Service) dailyAverages(ctx context.Context, userID, metric string, now time.Time) ([]float64, error) {
+	values := make([]float64, reportChartDays)
+	for day := 0; day < reportChartDays; day++ {
+		to := now.AddDate(0, 0, -day)
+		from := to.AddDate(0, 0, -1)
+		avg, count, err := s.metricAverage(ctx, userID, metric, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			values[reportChartDays-1-day] = avg
+		}
+	}
+	return values, nil
+}
+
+// summarize 求序列的均值、最小、最大与非零样本数
+func summarize(values []float64) (avg, min, max float64, samples int) {
+	for _, v := range values {
+		if v == 0 {
+			continue
+		}
+		if samples == 0 || v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		avg += v
+		samples++
+	}
+	if samples > 0 {
+		avg /= float64(samples)
+	}
+	return avg, min, max, samples
+}
+
+// pdfText 输出一段文本（UCS-2编码，经STSong CID字体渲染）
+func pdfText(b *bytes.Buffer, x, y float64, size int, text string) {
+	fmt.Fprintf(b, "BT /F1 %d Tf %.1f %.1f Td <", size, x, y)
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(b, "%04X", unit)
+	}
+	b.WriteString("> Tj ET\n")
+}
+
+// pdfBarChart 用矩形填充算子绘制柱状图
+func pdfBarChart(b *bytes.Buffer, x, y, width, height float64, values []float64) {
+	var max float64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	if max == 0 {
+		return
+	}
+	// 坐标轴
+	fmt.Fprintf(b, "0.6 0.6 0.6 RG %.1f %.1f m %.1f %.1f l %.1f %.1f l S\n",
+		x, y+height, x, y, x+width, y)
+	barWidth := width/float64(len(values)) - 3
+	fmt.Fprintf(b, "0.30 0.50 0.80 rg\n")
+	for i, v := range values {
+		if v <= 0 {
+			continue
+		}
+		barX := x + float64(i)*(barWidth+3) + 2
+		barHeight := height * v / max
+		fmt.Fprintf(b, "%.1f %.1f %.1f %.1f re f\n", barX, y, barWidth, barHeight)
+	}
+	fmt.Fprintf(b, "0 0 0 rg\n")
+}
+
+// buildPDF 把内容流组装为单页PDF文档
+func buildPDF(content []byte) []byte {
+	objects := [][]byte{
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte("<< /Type /Pages /Kids [3 0 R] /Count 1 >>"),
+		[]byte("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)),
+		[]byte("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [6 0 R] >>"),
+		[]byte("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 5 >> /FontDescriptor 7 0 R /DW 1000 >>"),
+		[]byte("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 4 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>"),
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects)+1)
+	for i, object := range objects {
+		offsets[i+1] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for i := 1; i <= len(objects); i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, xref)
+	return b.Bytes()
+}
@@ -0,0 +1,412 @@
+package health
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 健康指标类型
+const (
+	MetricSteps     = "steps"
+	MetricHeartRate = "heart_rate"
+	MetricSleep     = "sleep"
+	MetricWeight    = "weight"
+)
+
+// 数据来源
+const (
+	SourceManual      = "manual"
+	SourceGoogleFit   = "google_fit"
+	SourceAppleHealth = "apple_health"
+	SourceHuawei      = "huawei"
+)
+
+// 同步状态
+const (
+	SyncStatusSuccess = "success"
+	SyncStatusFailed  = "failed"
+)
+
+// initialSyncWindow 首次同步回溯的时间窗口
+const initialSyncWindow = 30 * 24 * time.Hour
+
+// syncInterval 后台自动同步间隔
+const syncInterval = time.Hour
+
+// HealthData 一条健康数据记录
+//
+// DedupKey由用户、指标、来源、来源引用与时间点哈希得出，
+// 唯一索引使重复导入（重叠的增量窗口、重复上传）天然幂等。
+type HealthData struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index:idx_health_user_metric;type:varchar(36);not null"`
+	// Metric steps/heart_rate/sleep
+	Metric string  `json:"metric" gorm:"index:idx_health_user_metric;type:varchar(32);not null"`
+	Value  float64 `json:"value"`
+	// Unit 步/bpm/分钟
+	Unit       string    `json:"unit" gorm:"type:varchar(16)"`
+	RecordedAt time.Time `json:"recordedAt" gorm:"index;not null"`
+	// Source manual/google_fit/apple_health/huawei
+	Source string `json:"source" gorm:"type:varchar(32);not null"`
+	// SourceRef 来源方的记录标识，手动录入时为空
+	SourceRef string    `json:"sourceRef,omitempty" gorm:"type:varchar(128)"`
+	DedupKey  string    `json:"-" gorm:"uniqueIndex;type:char(64);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (HealthData) TableName() string {
+	return "cs_health_data"
+}
+
+// Connection 与外部健康平台的授权连接
+//
+// OAuth流程在客户端完成，服务端只保存令牌并做增量同步；
+// Apple Health没有服务端API，通过导出文件上传，不产生连接。
+type Connection struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex:idx_health_conn_user_provider;type:varchar(36);not null"`
+	// Provider google_fit/huawei
+	Provider     string     `json:"provider" gorm:"uniqueIndex:idx_health_conn_user_provider;type:varchar(32);not null"`
+	AccessToken  string     `json:"-" gorm:"type:text;not null"`
+	RefreshToken string     `json:"-" gorm:"type:text"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	LastSyncAt   *time.Time `json:"lastSyncAt,omitempty"`
+	// LastSyncStatus success/failed
+	LastSyncStatus string `json:"lastSyncStatus,omitempty" gorm:"type:varchar(16)"`
+	LastSyncError  string `json:"lastSyncError,omitempty" gorm:"type:varchar(512)"`
+	// LastSyncCount 最近一次同步新增的记录数
+	LastSyncCount int       `json:"lastSyncCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Connection) TableName() string {
+	return "cs_health_connections"
+}
+
+// Point 连接器返回的一个数据点
+type Point struct {
+	Metric     string
+	Value      float64
+	Unit       string
+	RecordedAt time.Time
+	SourceRef  string
+}
+
+// Connector 外部健康平台连接器
+type Connector interface {
+	// Provider 返回来源标识
+	Provider() string
+	// Fetch 拉取区间内的数据点
+	Fetch(ctx context.Context, conn *Connection, since, until time.Time) ([]*Point, error)
+}
+
+// Service 健康数据服务
+type Service struct {
+	db         *gorm.DB
+	logger     *zap.Logger
+	connectors map[string]Connector
+
+	// publish 业务事件发布，告警触发时通知下游
+	publish func(event string, payload interface{})
+	// reportSecret 报告分享链接的签名密钥
+	reportSecret string
+
+	// trendCache 趋势聚合的进程内缓存
+	trendMu    sync.Mutex
+	trendCache map[string]*trendCacheEntry
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建健康数据服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&HealthData{}, &Connection{}, &AlertRule{}, &Alert{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate health tables: %w", err)
+	}
+	service := &Service{
+		db:         db,
+		logger:     logger,
+		connectors: make(map[string]Connector),
+		trendCache: make(map[string]*trendCacheEntry),
+		stopCh:     make(chan struct{}),
+	}
+	service.tryEnableTimescale()
+	return service, nil
+}
+
+// RegisterConnector 注册平台连接器
+func (s *Service) RegisterConnector(connector Connector) {
+	s.connectors[connector.Provider()] = connector
+}
+
+// RecordRequest 手动录入参数
+type RecordRequest struct {
+	Metric     string    `json:"metric" binding:"required"`
+	Value      float64   `json:"value" binding:"required"`
+	Unit       string    `json:"unit"`
+	RecordedAt time.Time `json:"recordedAt" binding:"required"`
+}
+
+// Record 手动录入一条健康数据
+func (s *Service) Record(ctx context.Context, userID string, req *RecordRequest) (*HealthData, error) {
+	if err := validMetric(req.Metric); err != nil {
+		return nil, err
+	}
+	record := &HealthData{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		Metric:     req.Metric,
+		Value:      req.Value,
+		Unit:       req.Unit,
+		RecordedAt: req.RecordedAt,
+		Source:     SourceManual,
+	}
+	record.DedupKey = dedupKey(record)
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to record health data: %w", err)
+	}
+	return record, nil
+}
+
+// List 查询用户某指标在区间内的数据
+func (s *Service) List(ctx context.Context, userID, metric string, from, to time.Time) ([]*HealthData, error) {
+	if err := validMetric(metric); err != nil {
+		return nil, err
+	}
+	var records []*HealthData
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND metric = ? AND recorded_at >= ? AND recorded_at < ?",
+			userID, metric, from, to).
+		Order("recorded_at ASC").
+		Limit(2000).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health data: %w", err)
+	}
+	return records, nil
+}
+
+// ConnectRequest 保存平台授权的参数
+type ConnectRequest struct {
+	Provider     string     `json:"provider" binding:"required"`
+	AccessToken  string     `json:"accessToken" binding:"required"`
+	RefreshToken string     `json:"refreshToken"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+}
+
+// Connect 保存（或替换）用户与平台的授权连接
+func (s *Service) Connect(ctx context.Context, userID string, req *ConnectRequest) (*Connection, error) {
+	if _, ok := s.connectors[req.Provider]; !ok {
+		return nil, fmt.Errorf("unknown provider: %s", req.Provider)
+	}
+	conn := &Connection{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		Provider:     req.Provider,
+		AccessToken:  req.AccessToken,
+		RefreshToken: req.RefreshToken,
+		ExpiresAt:    req.ExpiresAt,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Connection{}, "user_id = ? AND provider = ?", userID, req.Provider).Error; err != nil {
+			return err
+		}
+		return tx.Create(conn).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save health connection: %w", err)
+	}
+	return conn, nil
+}
+
+// ListConnections 列出用户的平台连接及同步状态
+func (s *Service) ListConnections(ctx context.Context, userID string) ([]*Connection, error) {
+	var conns []*Connection
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("provider ASC").
+		Find(&conns).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health connections: %w", err)
+	}
+	return conns, nil
+}
+
+// Disconnect 删除用户与平台的连接
+func (s *Service) Disconnect(ctx context.Context, userID, provider string) error {
+	result := s.db.WithContext(ctx).
+		Delete(&Connection{}, "user_id = ? AND provider = ?", userID, provider)
+	if result.Error != nil {
+		return fmt.Errorf("failed to disconnect: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("health connection not found")
+	}
+	return nil
+}
+
+// Sync 对用户与平台的连接执行一次增量同步
+//
+// 从上次同步时间（首次回溯30天）拉到当前，结果写回连接的
+// 同步状态字段，供同步状态端点查询。
+func (s *Service) Sync(ctx context.Context, userID, provider string) (*Connection, error) {
+	var conn Connection
+	err := s.db.WithContext(ctx).
+		First(&conn, "user_id = ? AND provider = ?", userID, provider).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("health connection not found")
+		}
+		return nil, fmt.Errorf("failed to load health connection: %w", err)
+	}
+	s.syncConnection(ctx, &conn)
+	return &conn, nil
+}
+
+// syncConnection 执行同步并写回状态
+func (s *Service) syncConnection(ctx context.Context, conn *Connection) {
+	connector, ok := s.connectors[conn.Provider]
+	now := time.Now()
+	var inserted int
+	var syncErr error
+	if !ok {
+		syncErr = fmt.Errorf("unknown provider: %s", conn.Provider)
+	} else {
+		since := now.Add(-initialSyncWindow)
+		if conn.LastSyncAt != nil {
+			since = *conn.LastSyncAt
+		}
+		var points []*Point
+		points, syncErr = connector.Fetch(ctx, conn, since, now)
+		if syncErr == nil {
+			inserted, syncErr = s.ingest(ctx, conn.UserID, conn.Provider, points)
+		}
+	}
+
+	updates := map[string]interface{}{
+		"last_sync_at":    now,
+		"last_sync_count": inserted,
+	}
+	if syncErr != nil {
+		updates["last_sync_status"] = SyncStatusFailed
+		updates["last_sync_error"] = truncate(syncErr.Error(), 512)
+		// 失败时不推进同步点，下次重试同一窗口
+		updates["last_sync_at"] = conn.LastSyncAt
+		s.logger.Error("health sync failed",
+			zap.String("userId", conn.UserID),
+			zap.String("provider", conn.Provider),
+			zap.Error(syncErr))
+	} else {
+		updates["last_sync_status"] = SyncStatusSuccess
+		updates["last_sync_error"] = ""
+	}
+	if err := s.db.WithContext(ctx).Model(&Connection{}).
+		Where("id = ?", conn.ID).
+		Updates(updates).Error; err != nil {
+		s.logger.Error("failed to update sync status", zap.Error(err))
+		return
+	}
+	s.db.WithContext(ctx).First(conn, "id = ?", conn.ID)
+}
+
+// ingest 批量写入数据点，依赖唯一索引去重
+func (s *Service) ingest(ctx context.Context, userID, source string, points []*Point) (int, error) {
+	var inserted int
+	for _, point := range points {
+		if err := validMetric(point.Metric); err != nil {
+			continue
+		}
+		record := &HealthData{
+			ID:         uuid.NewString(),
+			UserID:     userID,
+			Metric:     point.Metric,
+			Value:      point.Value,
+			Unit:       point.Unit,
+			RecordedAt: point.RecordedAt,
+			Source:     source,
+			SourceRef:  point.SourceRef,
+		}
+		record.DedupKey = dedupKey(record)
+		result := s.db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(record)
+		if result.Error != nil {
+			return inserted, fmt.Errorf("failed to ingest health data: %w", result.Error)
+		}
+		inserted += int(result.RowsAffected)
+	}
+	return inserted, nil
+}
+
+// Start 启动后台自动同步循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.syncAll(context.Background())
+				s.evaluateRules(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止后台同步循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// syncAll 同步所有连接
+func (s *Service) syncAll(ctx context.Context) {
+	var conns []*Connection
+	if err := s.db.WithContext(ctx).Limit(500).Find(&conns).Error; err != nil {
+		s.logger.Error("failed to load health connections", zap.Error(err))
+		return
+	}
+	for _, conn := range conns {
+		s.syncConnection(ctx, conn)
+	}
+}
+
+// validMetric 校验指标类型
+func validMetric(metric string) error {
+	switch metric {
+	case MetricSteps, MetricHeartRate, MetricSleep, MetricWeight:
+		return nil
+	default:
+		return fmt.Errorf("invalid metric: %s", metric)
+	}
+}
+
+// dedupKey 计算记录的去重键
+func dedupKey(record *HealthData) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%d",
+		record.UserID, record.Metric, record.Source, record.SourceRef,
+		record.RecordedAt.UTC().Unix())))
+	return hex.EncodeToString(hash[:])
+}
+
+// truncate 截断过长文本
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	return text[:max]
+}
@@ -0,0 +1,183 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 趋势聚合粒度
+const (
+	GranularityDaily   = "daily"
+	GranularityWeekly  = "weekly"
+	GranularityMonthly = "monthly"
+)
+
+// maxTrendBuckets 单次查询返回的桶数上限，超出时自动降采样
+const maxTrendBuckets = 120
+
+// maxTrendRows 单次聚合扫描的原始记录上限
+const maxTrendRows = 50000
+
+// trendCacheTTL 聚合结果缓存时长
+const trendCacheTTL = 5 * time.Minute
+
+// TrendBucket 一个时间桶的聚合结果
+type TrendBucket struct {
+	Start time.Time `json:"start"`
+	Min   float64   `json:"min"`
+	Avg   float64   `json:"avg"`
+	Max   float64   `json:"max"`
+	Count int       `json:"count"`
+}
+
+// TrendResult 趋势查询结果
+type TrendResult struct {
+	Metric string `json:"metric"`
+	// Granularity 实际使用的粒度，长区间会被降采样
+	Granularity string         `json:"granularity"`
+	Buckets     []*TrendBucket `json:"buckets"`
+}
+
+// trendCacheEntry 进程内缓存的一项聚合结果
+type trendCacheEntry struct {
+	result    *TrendResult
+	expiresAt time.Time
+}
+
+// Trends 对用户某指标做时间序列聚合
+//
+// 按粒度输出每桶的min/avg/max；区间过长导致桶数超限时自动
+// 提升粒度降采样。结果短暂缓存，重复的看板查询不回表。
+func (s *Service) Trends(ctx context.Context, userID, metric string, from, to time.Time, granularity string) (*TrendResult, error) {
+	if err := validMetric(metric); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid range: to must be after from")
+	}
+	switch granularity {
+	case GranularityDaily, GranularityWeekly, GranularityMonthly:
+	case "":
+		granularity = GranularityDaily
+	default:
+		return nil, fmt.Errorf("invalid granularity: %s", granularity)
+	}
+	granularity = downsample(from, to, granularity)
+
+	cacheKey := fmt.Sprintf("%s|%s|%d|%d|%s", userID, metric, from.Unix(), to.Unix(), granularity)
+	if cached := s.trendCacheGet(cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	var rows []struct {
+		Value      float64
+		RecordedAt time.Time
+	}
+	err := s.db.WithContext(ctx).Model(&HealthData{}).
+		Select("value", "recorded_at").
+		Where("user_id = ? AND metric = ? AND recorded_at >= ? AND recorded_at < ?",
+			userID, metric, from, to).
+		Order("recorded_at ASC").
+		Limit(maxTrendRows).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate trends: %w", err)
+	}
+
+	grouped := make(map[time.Time]*TrendBucket)
+	var order []time.Time
+	for _, row := range rows {
+		start := bucketStart(row.RecordedAt, granularity)
+		bucket, ok := grouped[start]
+		if !ok {
+			bucket = &TrendBucket{Start: start, Min: row.Value, Max: row.Value}
+			grouped[start] = bucket
+			order = append(order, start)
+		}
+		if row.Value < bucket.Min {
+			bucket.Min = row.Value
+		}
+		if row.Value > bucket.Max {
+			bucket.Max = row.Value
+		}
+		bucket.Avg += row.Value
+		bucket.Count++
+	}
+	buckets := make([]*TrendBucket, 0, len(order))
+	for _, start := range order {
+		bucket := grouped[start]
+		bucket.Avg /= float64(bucket.Count)
+		buckets = append(buckets, bucket)
+	}
+
+	result := &TrendResult{Metric: metric, Granularity: granularity, Buckets: buckets}
+	s.trendCachePut(cacheKey, result)
+	return result, nil
+}
+
+// downsample 桶数超限时逐级提升粒度
+func downsample(from, to time.Time, granularity string) string {
+	days := to.Sub(from).Hours() / 24
+	if granularity == GranularityDaily && days > maxTrendBuckets {
+		granularity = GranularityWeekly
+	}
+	if granularity == GranularityWeekly && days/7 > maxTrendBuckets {
+		granularity = GranularityMonthly
+	}
+	return granularity
+}
+
+// bucketStart 计算记录落入的桶起点
+func bucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	switch granularity {
+	case GranularityWeekly:
+		// 回退到该周周一
+		offset := (int(t.Weekday()) + 6) % 7
+		t = t.AddDate(0, 0, -offset)
+	case GranularityMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// trendCacheGet 读缓存，过期项视为未命中
+func (s *Service) trendCacheGet(key string) *TrendResult {
+	s.trendMu.Lock()
+	defer s.trendMu.Unlock()
+	entry, ok := s.trendCache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return entry.result
+}
+
+// trendCachePut 写缓存，顺带清理过期项
+func (s *Service) trendCachePut(key string, result *TrendResult) {
+	s.trendMu.Lock()
+	defer s.trendMu.Unlock()
+	now := time.Now()
+	for k, entry := range s.trendCache {
+		if now.After(entry.expiresAt) {
+			delete(s.trendCache, k)
+		}
+	}
+	s.trendCache[key] = &trendCacheEntry{result: result, expiresAt: now.Add(trendCacheTTL)}
+}
+
+// tryEnableTimescale 在PostgreSQL上尝试把健康数据表转为超表
+//
+// TimescaleDB未安装或方言不支持时仅记录告警，功能不受影响。
+func (s *Service) tryEnableTimescale() {
+	if !strings.Contains(s.db.Dialector.Name(), "postgres") {
+		return
+	}
+	err := s.db.Exec(
+		"SELECT create_hypertable('cs_health_data', 'recorded_at', if_not_exists => TRUE, migrate_data => TRUE)",
+	).Error
+	if err != nil {
+		s.logger.Warn("timescale hypertable not enabled for health data")
+	}
+}
@@ -0,0 +1,149 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 支持的语言标签
+const (
+	LangZhHans = "zh-Hans"
+	LangZhHant = "zh-Hant"
+	LangEn     = "en"
+)
+
+// DefaultLanguage 兜底语言：历史响应均为简体中文
+const DefaultLanguage = LangZhHans
+
+// SupportedLanguages 内容与消息目录覆盖的语言
+var SupportedLanguages = []string{LangZhHans, LangZhHant, LangEn}
+
+// aliasTable Accept-Language常见写法到规范标签的映射
+var aliasTable = map[string]string{
+	"zh":      LangZhHans,
+	"zh-cn":   LangZhHans,
+	"zh-sg":   LangZhHans,
+	"zh-hans": LangZhHans,
+	"zh-tw":   LangZhHant,
+	"zh-hk":   LangZhHant,
+	"zh-mo":   LangZhHant,
+	"zh-hant": LangZhHant,
+	"en":      LangEn,
+	"en-us":   LangEn,
+	"en-gb":   LangEn,
+}
+
+// messages 接口消息目录
+//
+// 键为稳定的消息标识，各处理器用Msg取本地化文案；缺失的
+// 语言回退到DefaultLanguage。
+var messages = map[string]map[string]string{
+	"error.unauthorized": {
+		LangZhHans: "缺少X-User-ID请求头",
+		LangZhHant: "缺少X-User-ID請求頭",
+		LangEn:     "X-User-ID header is required",
+	},
+	"error.not_found": {
+		LangZhHans: "资源不存在",
+		LangZhHant: "資源不存在",
+		LangEn:     "resource not found",
+	},
+	"error.invalid_request": {
+		LangZhHans: "请求参数不合法",
+		LangZhHant: "請求參數不合法",
+		LangEn:     "invalid request",
+	},
+	"error.internal": {
+		LangZhHans: "服务内部错误",
+		LangZhHant: "服務內部錯誤",
+		LangEn:     "internal server error",
+	},
+}
+
+// Msg 取某语言下的消息文案，缺失时回退默认语言再回退键本身
+func Msg(lang, key string) string {
+	entry, ok := messages[key]
+	if !ok {
+		return key
+	}
+	if text, ok := entry[lang]; ok {
+		return text
+	}
+	if text, ok := entry[DefaultLanguage]; ok {
+		return text
+	}
+	return key
+}
+
+// Normalize 把语言标签规范化到支持集，未知标签返回空串
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+	if canonical, ok := aliasTable[tag]; ok {
+		return canonical
+	}
+	// 带地区的未知变体按主语言匹配
+	if idx := strings.Index(tag, "-"); idx > 0 {
+		if canonical, ok := aliasTable[tag[:idx]]; ok {
+			return canonical
+		}
+	}
+	return ""
+}
+
+// acceptEntry Accept-Language中的一项
+type acceptEntry struct {
+	lang    string
+	quality float64
+	order   int
+}
+
+// Negotiate 解析Accept-Language并返回最合适的支持语言
+//
+// 按q值降序依次匹配，全部不识别时回退DefaultLanguage。
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLanguage
+	}
+	var entries []acceptEntry
+	for order, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := Normalize(fields[0])
+		if lang == "" {
+			continue
+		}
+		quality := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(field, "q=") {
+				if parsed, err := strconv.ParseFloat(field[2:], 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		entries = append(entries, acceptEntry{lang: lang, quality: quality, order: order})
+	}
+	if len(entries) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].quality != entries[j].quality {
+			return entries[i].quality > entries[j].quality
+		}
+		return entries[i].order < entries[j].order
+	})
+	return entries[0].lang
+}
+
+// Supported 判断标签是否在支持集内
+func Supported(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if supported == lang {
+			return true
+		}
+	}
+	return false
+}
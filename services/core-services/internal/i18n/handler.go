@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 翻译管理HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建翻译处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册翻译相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/i18n/languages", h.Languages)
+	router.GET("/i18n/translations", h.List)
+	router.PUT("/i18n/translations", h.Upsert)
+	router.DELETE("/i18n/translations/:translationId", h.Delete)
+	router.POST("/i18n/translations/draft", h.Draft)
+}
+
+// requireUser 从请求头取当前用户
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": Msg(Negotiate(c.GetHeader("Accept-Language")), "error.unauthorized")})
+		return "", false
+	}
+	return userID, true
+}
+
+// Languages 支持的语言与请求协商结果
+func (h *Handler) Languages(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"supported":  SupportedLanguages,
+		"default":    DefaultLanguage,
+		"negotiated": Negotiate(c.GetHeader("Accept-Language")),
+	})
+}
+
+// List 列出某资源的翻译
+func (h *Handler) List(c *gin.Context) {
+	resourceType := c.Query("resourceType")
+	resourceID := c.Query("resourceId")
+	if resourceType == "" || resourceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resourceType and resourceId are required"})
+		return
+	}
+	items, err := h.service.List(c.Request.Context(), resourceType, resourceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"translations": items})
+}
+
+// Upsert 写入或更新翻译
+func (h *Handler) Upsert(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		ResourceType string `json:"resourceType" binding:"required"`
+		ResourceID   string `json:"resourceId" binding:"required"`
+		Field        string `json:"field" binding:"required"`
+		Language     string `json:"language" binding:"required"`
+		Value        string `json:"value" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	translation, err := h.service.Upsert(c.Request.Context(), userID, &Translation{
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		Field:        req.Field,
+		Language:     req.Language,
+		Value:        req.Value,
+	})
+	if err != nil {
+		c.JSON(statusForI18nError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// Delete 删除翻译
+func (h *Handler) Delete(c *gin.Context) {
+	if _, ok := requireUser(c); !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("translationId")); err != nil {
+		c.JSON(statusForI18nError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted"})
+}
+
+// Draft AI辅助起草翻译
+func (h *Handler) Draft(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		ResourceType string `json:"resourceType" binding:"required"`
+		ResourceID   string `json:"resourceId" binding:"required"`
+		Field        string `json:"field" binding:"required"`
+		Language     string `json:"language" binding:"required"`
+		Source       string `json:"source" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	translation, err := h.service.Draft(c.Request.Context(), userID,
+		req.ResourceType, req.ResourceID, req.Field, req.Language, req.Source)
+	if err != nil {
+		c.JSON(statusForI18nError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, translation)
+}
+
+// statusForI18nError 根据错误内容映射HTTP状态码
+func statusForI18nError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "translation failed"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,195 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 可挂翻译的资源类型
+const (
+	ResourceWisdom = "wisdom"
+	ResourcePost   = "post"
+)
+
+// validResources 资源类型白名单
+var validResources = map[string]bool{
+	ResourceWisdom: true,
+	ResourcePost:   true,
+}
+
+// Completer AI翻译的底层补全调用（与ai.Completer同形，
+// 消费端自定义接口避免跨包依赖）
+type Completer interface {
+	Complete(ctx context.Context, provider, prompt string) (string, error)
+}
+
+// Translation 某资源某字段的一个语言变体
+type Translation struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// ResourceType wisdom/post
+	ResourceType string `json:"resourceType" gorm:"uniqueIndex:idx_translation;type:varchar(32);not null"`
+	ResourceID   string `json:"resourceId" gorm:"uniqueIndex:idx_translation;type:varchar(36);not null"`
+	// Field 被翻译的字段名，如 title/content
+	Field string `json:"field" gorm:"uniqueIndex:idx_translation;type:varchar(32);not null"`
+	// Language 规范语言标签：zh-Hans/zh-Hant/en
+	Language string `json:"language" gorm:"uniqueIndex:idx_translation;type:varchar(16);not null"`
+	Value    string `json:"value" gorm:"type:text"`
+	// Machine true表示AI起草、未经人工确认
+	Machine   bool      `json:"machine"`
+	UpdatedBy string    `json:"updatedBy,omitempty" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Translation) TableName() string {
+	return "cs_translations"
+}
+
+// Service 翻译管理服务
+type Service struct {
+	db *gorm.DB
+
+	completer Completer
+	provider  string
+}
+
+// NewService 创建翻译服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Translation{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate translation tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// SetCompleter 注入AI补全，启用AI辅助起草
+func (s *Service) SetCompleter(completer Completer, provider string) {
+	s.completer = completer
+	s.provider = provider
+}
+
+// Upsert 写入或更新一个翻译
+func (s *Service) Upsert(ctx context.Context, userID string, t *Translation) (*Translation, error) {
+	if !validResources[t.ResourceType] {
+		return nil, fmt.Errorf("invalid resource type: %s", t.ResourceType)
+	}
+	if !Supported(t.Language) {
+		return nil, fmt.Errorf("invalid language: %s", t.Language)
+	}
+	if t.ResourceID == "" || t.Field == "" {
+		return nil, fmt.Errorf("invalid translation: resourceId and field are required")
+	}
+	var existing Translation
+	err := s.db.WithContext(ctx).
+		First(&existing, "resource_type = ? AND resource_id = ? AND field = ? AND language = ?",
+			t.ResourceType, t.ResourceID, t.Field, t.Language).Error
+	if err == nil {
+		existing.Value = t.Value
+		existing.Machine = t.Machine
+		existing.UpdatedBy = userID
+		if err := s.db.WithContext(ctx).Save(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to update translation: %w", err)
+		}
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check translation: %w", err)
+	}
+	t.ID = uuid.NewString()
+	t.UpdatedBy = userID
+	if err := s.db.WithContext(ctx).Create(t).Error; err != nil {
+		return nil, fmt.Errorf("failed to create translation: %w", err)
+	}
+	return t, nil
+}
+
+// List 列出某资源的全部翻译
+func (s *Service) List(ctx context.Context, resourceType, resourceID string) ([]*Translation, error) {
+	var items []*Translation
+	err := s.db.WithContext(ctx).
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("language ASC, field ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	return items, nil
+}
+
+// Delete 删除一个翻译
+func (s *Service) Delete(ctx context.Context, translationID string) error {
+	result := s.db.WithContext(ctx).Delete(&Translation{}, "id = ?", translationID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete translation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("translation not found")
+	}
+	return nil
+}
+
+// Resolve 取某资源某字段在目标语言下的最佳值
+//
+// 精确命中优先，否则回退到fallback原文；Machine标记透传，
+// 客户端可据此提示“机器翻译”。
+func (s *Service) Resolve(ctx context.Context, resourceType, resourceID, field, lang, fallback string) (string, bool) {
+	var t Translation
+	err := s.db.WithContext(ctx).
+		First(&t, "resource_type = ? AND resource_id = ? AND field = ? AND language = ?",
+			resourceType, resourceID, field, lang).Error
+	if err != nil {
+		return fallback, false
+	}
+	return t.Value, t.Machine
+}
+
+// languageNames 提示词中使用的语言描述
+var languageNames = map[string]string{
+	LangZhHans: "简体中文",
+	LangZhHant: "繁体中文",
+	LangEn:     "英语",
+}
+
+// Draft AI辅助起草翻译
+//
+// 结果以Machine=true落库，等待人工复核修订；不覆盖已有的
+// 人工翻译。
+func (s *Service) Draft(ctx context.Context, userID, resourceType, resourceID, field, targetLang, source string) (*Translation, error) {
+	if s.completer == nil {
+		return nil, fmt.Errorf("ai translation not configured")
+	}
+	if !Supported(targetLang) {
+		return nil, fmt.Errorf("invalid language: %s", targetLang)
+	}
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("invalid translation: source text is required")
+	}
+	var existing Translation
+	err := s.db.WithContext(ctx).
+		First(&existing, "resource_type = ? AND resource_id = ? AND field = ? AND language = ?",
+			resourceType, resourceID, field, targetLang).Error
+	if err == nil && !existing.Machine {
+		return nil, fmt.Errorf("invalid draft: human translation already exists")
+	}
+
+	prompt := fmt.Sprintf(
+		"把下面的文本翻译成%s。保持原文的语气与格式，不要添加解释，只输出译文。\n\n%s",
+		languageNames[targetLang], source)
+	translated, err := s.completer.Complete(ctx, s.provider, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("translation failed: %w", err)
+	}
+	return s.Upsert(ctx, userID, &Translation{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Field:        field,
+		Language:     targetLang,
+		Value:        strings.TrimSpace(translated),
+		Machine:      true,
+	})
+}
@@ -0,0 +1,161 @@
+package inference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OllamaClient 本地模型运行时客户端（Ollama HTTP API，
+// llama.cpp的server模式兼容同一接口形状）
+type OllamaClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOllamaClient 创建运行时客户端
+func NewOllamaClient(baseURL string) *OllamaClient {
+	return &OllamaClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		// 本地推理可能很慢，超时放宽
+		client: &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// GenerateOptions 单次推理选项
+type GenerateOptions struct {
+	// NumGPU 卸载到GPU的层数，0表示纯CPU
+	NumGPU int
+	// KeepAlive 推理后模型在内存中保留的时长
+	KeepAlive time.Duration
+}
+
+// Generate 执行一次补全
+func (c *OllamaClient) Generate(ctx context.Context, model, prompt string, opts GenerateOptions) (string, error) {
+	payload := map[string]interface{}{
+		"model":      model,
+		"prompt":     prompt,
+		"stream":     false,
+		"keep_alive": opts.KeepAlive.String(),
+		"options": map[string]interface{}{
+			"num_gpu": opts.NumGPU,
+		},
+	}
+	var result struct {
+		Response string `json:"response"`
+	}
+	if err := c.post(ctx, "/api/generate", payload, &result); err != nil {
+		return "", err
+	}
+	return result.Response, nil
+}
+
+// Warm 空提示加载模型进内存（预热池）
+func (c *OllamaClient) Warm(ctx context.Context, model string, opts GenerateOptions) error {
+	payload := map[string]interface{}{
+		"model":      model,
+		"stream":     false,
+		"keep_alive": opts.KeepAlive.String(),
+		"options": map[string]interface{}{
+			"num_gpu": opts.NumGPU,
+		},
+	}
+	return c.post(ctx, "/api/generate", payload, nil)
+}
+
+// Pull 下载模型
+func (c *OllamaClient) Pull(ctx context.Context, model string) error {
+	payload := map[string]interface{}{
+		"name":   model,
+		"stream": false,
+	}
+	var result struct {
+		Status string `json:"status"`
+	}
+	if err := c.post(ctx, "/api/pull", payload, &result); err != nil {
+		return err
+	}
+	if result.Status != "success" {
+		return fmt.Errorf("model pull did not complete: %s", result.Status)
+	}
+	return nil
+}
+
+// ModelInfo 已下载模型信息
+type ModelInfo struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// List 列出已下载模型
+func (c *OllamaClient) List(ctx context.Context) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("runtime request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("runtime returned %d", resp.StatusCode)
+	}
+	var result struct {
+		Models []ModelInfo `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid runtime response: %w", err)
+	}
+	return result.Models, nil
+}
+
+// Ping 检查运行时是否可达
+func (c *OllamaClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("runtime unreachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// post 执行JSON POST请求
+func (c *OllamaClient) post(ctx context.Context, path string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("runtime request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("runtime returned %d: %s", resp.StatusCode, string(data))
+	}
+	if result == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("invalid runtime response: %w", err)
+	}
+	return nil
+}
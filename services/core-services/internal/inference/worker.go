@@ -0,0 +1,287 @@
+package inference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Config 推理worker配置
+type Config struct {
+	// OllamaURL 模型运行时地址（如 http://localhost:11434）
+	OllamaURL string
+	// Models 启动时下载并预热的模型列表
+	Models []string
+	// Device cpu或gpu
+	Device string
+	// GPULayers gpu模式下卸载到显存的层数
+	GPULayers int
+	// KeepAlive 预热模型在内存中保留的时长
+	KeepAlive time.Duration
+	// CoreURL 核心服务地址，非空时把模型注册进provider管理
+	CoreURL string
+}
+
+// Worker 本地模型推理worker
+//
+// 对上暴露与AI补全服务一致的 /v1/completions 契约，核心服务
+// 把ai_service_url指向worker即可接入"Local"提供方；模型在
+// 启动时下载并加入预热池，按keep_alive周期续热避免首请求
+// 冷加载。
+type Worker struct {
+	cfg    Config
+	ollama *OllamaClient
+	logger *zap.Logger
+
+	mu sync.RWMutex
+	// warm 已预热成功的模型集合
+	warm map[string]bool
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWorker 创建推理worker
+func NewWorker(cfg Config, logger *zap.Logger) (*Worker, error) {
+	if cfg.OllamaURL == "" {
+		return nil, fmt.Errorf("ollama url is required")
+	}
+	if len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("at least one model is required")
+	}
+	if cfg.Device != "cpu" && cfg.Device != "gpu" {
+		return nil, fmt.Errorf("device must be cpu or gpu")
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = 30 * time.Minute
+	}
+	if cfg.Device == "gpu" && cfg.GPULayers == 0 {
+		// -1表示全部层进显存
+		cfg.GPULayers = -1
+	}
+	return &Worker{
+		cfg:    cfg,
+		ollama: NewOllamaClient(cfg.OllamaURL),
+		logger: logger,
+		warm:   make(map[string]bool),
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 下载模型、预热并启动续热循环
+func (w *Worker) Start(ctx context.Context) error {
+	if err := w.ollama.Ping(ctx); err != nil {
+		return err
+	}
+	for _, model := range w.cfg.Models {
+		if err := w.ensureModel(ctx, model); err != nil {
+			return fmt.Errorf("failed to prepare model %s: %w", model, err)
+		}
+	}
+	go w.rewarmLoop()
+	if w.cfg.CoreURL != "" {
+		w.registerProviders(ctx)
+	}
+	return nil
+}
+
+// Stop 停止续热循环
+func (w *Worker) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+// ensureModel 确保模型已下载并预热
+func (w *Worker) ensureModel(ctx context.Context, model string) error {
+	downloaded, err := w.ollama.List(ctx)
+	if err != nil {
+		return err
+	}
+	present := false
+	for _, info := range downloaded {
+		if info.Name == model {
+			present = true
+			break
+		}
+	}
+	if !present {
+		w.logger.Info("pulling model", zap.String("model", model))
+		if err := w.ollama.Pull(ctx, model); err != nil {
+			return err
+		}
+	}
+	if err := w.ollama.Warm(ctx, model, w.generateOptions()); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.warm[model] = true
+	w.mu.Unlock()
+	w.logger.Info("model warmed", zap.String("model", model))
+	return nil
+}
+
+// rewarmLoop 周期性续热，保持模型常驻内存
+func (w *Worker) rewarmLoop() {
+	interval := w.cfg.KeepAlive / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			for _, model := range w.cfg.Models {
+				if err := w.ollama.Warm(ctx, model, w.generateOptions()); err != nil {
+					w.logger.Warn("failed to rewarm model",
+						zap.String("model", model), zap.Error(err))
+					w.mu.Lock()
+					w.warm[model] = false
+					w.mu.Unlock()
+				} else {
+					w.mu.Lock()
+					w.warm[model] = true
+					w.mu.Unlock()
+				}
+			}
+			cancel()
+		}
+	}
+}
+
+// generateOptions 按设备配置构造推理选项
+func (w *Worker) generateOptions() GenerateOptions {
+	numGPU := 0
+	if w.cfg.Device == "gpu" {
+		numGPU = w.cfg.GPULayers
+	}
+	return GenerateOptions{NumGPU: numGPU, KeepAlive: w.cfg.KeepAlive}
+}
+
+// registerProviders 把本地模型注册进核心服务的provider管理
+//
+// 失败只告警：worker可独立运行，注册属于可选集成。
+func (w *Worker) registerProviders(ctx context.Context) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, model := range w.cfg.Models {
+		body, _ := json.Marshal(map[string]int{
+			"maxInputTokens": 4000,
+			"chunkTokens":    1500,
+			"overlapTokens":  100,
+		})
+		url := strings.TrimRight(w.cfg.CoreURL, "/") + "/api/v1/ai/providers/" + model + "/limits"
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			w.logger.Warn("failed to register provider",
+				zap.String("model", model), zap.Error(err))
+			continue
+		}
+		resp.Body.Close()
+		w.logger.Info("provider registered", zap.String("model", model))
+	}
+}
+
+// SetupRoutes 注册worker HTTP路由
+func (w *Worker) SetupRoutes(router gin.IRouter) {
+	router.POST("/v1/completions", w.Completions)
+	router.GET("/healthz", w.Health)
+	router.GET("/models", w.Models)
+	router.POST("/models/pull", w.PullModel)
+}
+
+// Completions 执行补全（与AI补全服务同契约）
+func (w *Worker) Completions(c *gin.Context) {
+	var req struct {
+		Provider string `json:"provider"`
+		Prompt   string `json:"prompt" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	model := req.Provider
+	if model == "" || model == "default" || model == "local" {
+		model = w.cfg.Models[0]
+	}
+	text, err := w.ollama.Generate(c.Request.Context(), model, req.Prompt, w.generateOptions())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"text": text})
+}
+
+// Health 健康检查：运行时可达且全部模型已预热
+func (w *Worker) Health(c *gin.Context) {
+	if err := w.ollama.Ping(c.Request.Context()); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "down", "error": err.Error()})
+		return
+	}
+	w.mu.RLock()
+	warm := make(map[string]bool, len(w.warm))
+	allWarm := true
+	for model, ok := range w.warm {
+		warm[model] = ok
+		if !ok {
+			allWarm = false
+		}
+	}
+	w.mu.RUnlock()
+	status := "ok"
+	code := http.StatusOK
+	if !allWarm {
+		status = "degraded"
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": status, "device": w.cfg.Device, "models": warm})
+}
+
+// Models 列出运行时已下载的模型
+func (w *Worker) Models(c *gin.Context) {
+	models, err := w.ollama.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"models": models})
+}
+
+// PullModel 下载新模型并加入预热池
+func (w *Worker) PullModel(c *gin.Context) {
+	var req struct {
+		Model string `json:"model" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := w.ensureModel(c.Request.Context(), req.Model); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	w.mu.Lock()
+	found := false
+	for _, model := range w.cfg.Models {
+		if model == req.Model {
+			found = true
+			break
+		}
+	}
+	if !found {
+		w.cfg.Models = append(w.cfg.Models, req.Model)
+	}
+	w.mu.Unlock()
+	c.JSON(http.StatusCreated, gin.H{"model": req.Model})
+}
@@ -0,0 +1,227 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Completer 底层补全调用，与ai模块的HTTP客户端签名一致
+type Completer interface {
+	Complete(ctx context.Context, provider, prompt string) (string, error)
+}
+
+// entailPassScore 论断被判定为有支撑的最低得分（0-10）
+const entailPassScore = 6.0
+
+// answerExcerptLimit 送入蕴含判定的证据摘录长度上限
+const answerExcerptLimit = 1500
+
+// SetCompleter 接线补全调用与使用的provider
+func (s *Service) SetCompleter(completer Completer, provider string) {
+	s.completer = completer
+	s.provider = provider
+}
+
+// Citation 回答引用的一篇文档
+type Citation struct {
+	Index      int    `json:"index"`
+	DocumentID string `json:"documentId"`
+	Title      string `json:"title"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ClaimVerification 单条论断的引用核验结果
+type ClaimVerification struct {
+	Statement string `json:"statement"`
+	// CitationIndex 论断标注的引用序号，0表示未标注引用
+	CitationIndex int `json:"citationIndex"`
+	// Score 蕴含得分（0-10），无引用的论断为0
+	Score float64 `json:"score"`
+	// Supported 引用的文档是否支撑该论断
+	Supported bool `json:"supported"`
+}
+
+// Answer 带引用核验的RAG回答
+type Answer struct {
+	Answer    string               `json:"answer"`
+	Citations []*Citation          `json:"citations"`
+	Claims    []*ClaimVerification `json:"claims"`
+	// CitationConfidence 各论断蕴含得分的均值（0-1），UI展示用
+	CitationConfidence float64 `json:"citationConfidence"`
+	// UnsupportedCount 未被引用支撑的论断数
+	UnsupportedCount int `json:"unsupportedCount"`
+}
+
+// AskRequest 提问参数
+type AskRequest struct {
+	Question string   `json:"question" binding:"required"`
+	Scopes   []string `json:"scopes"`
+	// ContextType/ContextID 限定取材范围的聊天室或项目，
+	// 给定后只从该上下文绑定的知识源取材
+	ContextType string `json:"contextType"`
+	ContextID   string `json:"contextId"`
+	// Limit 取材文档数，默认5
+	Limit int `json:"limit"`
+}
+
+// Ask 基于知识库检索生成回答并核验引用
+//
+// 生成后对每条带引用的论断做蕴含判定：被引用的文档片段
+// 是否支撑该论断。不达标的论断在元数据中标记，整体置信度
+// 供UI展示，回答正文不改写。
+func (s *Service) Ask(ctx context.Context, req *AskRequest) (*Answer, error) {
+	if s.completer == nil {
+		return nil, fmt.Errorf("completer not configured")
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > 10 {
+		limit = 5
+	}
+	sourceIDs, err := s.visibleSourceIDs(ctx, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ContextType != "" {
+		// 上下文绑定与可见范围求交，不回退到全局知识
+		bound, err := s.boundSourceIDs(ctx, req.ContextType, req.ContextID)
+		if err != nil {
+			return nil, err
+		}
+		sourceIDs = intersect(sourceIDs, bound)
+		if len(sourceIDs) == 0 {
+			return nil, fmt.Errorf("no knowledge bound to this context")
+		}
+	}
+	results, err := s.searchIn(ctx, req.Question, sourceIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no documents found for question")
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("根据以下资料回答问题。每个论断后用[编号]标注所依据的资料，资料未覆盖的内容不要编造。\n\n")
+	citations := make([]*Citation, 0, len(results))
+	for i, result := range results {
+		doc := result.Document
+		prompt.WriteString(fmt.Sprintf("[%d] %s\n%s\n\n", i+1, doc.Title, excerpt(doc.Content, answerExcerptLimit)))
+		citations = append(citations, &Citation{
+			Index:      i + 1,
+			DocumentID: doc.ID,
+			Title:      doc.Title,
+			URL:        doc.URL,
+		})
+	}
+	prompt.WriteString("问题：" + req.Question + "\n回答：")
+
+	answerText, err := s.completer.Complete(ctx, s.provider, prompt.String())
+	if err != nil {
+		return nil, fmt.Errorf("completion failed: %w", err)
+	}
+
+	answer := &Answer{
+		Answer:    answerText,
+		Citations: citations,
+	}
+	s.verifyClaims(ctx, answer, results)
+	s.recordAudit(ctx, req.ContextType, req.ContextID, req.Question, answer)
+	return answer, nil
+}
+
+// intersect 求两个ID集合的交集
+func intersect(a, b []string) []string {
+	set := make(map[string]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	var out []string
+	for _, id := range b {
+		if set[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// citationMarker 回答中的引用标注
+var citationMarker = regexp.MustCompile(`\[(\d+)\]`)
+
+// claimSplitter 论断切分边界
+var claimSplitter = regexp.MustCompile(`[。！？.!?]\s*|\n+`)
+
+// verifyClaims 对回答中的论断逐条做蕴含判定
+func (s *Service) verifyClaims(ctx context.Context, answer *Answer, results []*SearchResult) {
+	var total float64
+	var scored int
+	for _, statement := range claimSplitter.Split(answer.Answer, -1) {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		claim := &ClaimVerification{Statement: statement}
+		if match := citationMarker.FindStringSubmatch(statement); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			claim.CitationIndex = index
+			if index >= 1 && index <= len(results) {
+				score, err := s.entailScore(ctx, results[index-1].Document, statement)
+				if err != nil {
+					s.logger.Warn("entailment scoring failed")
+				} else {
+					claim.Score = score
+					claim.Supported = score >= entailPassScore
+					total += score
+					scored++
+				}
+			}
+		}
+		if !claim.Supported {
+			answer.UnsupportedCount++
+		}
+		answer.Claims = append(answer.Claims, claim)
+	}
+	if scored > 0 {
+		answer.CitationConfidence = total / float64(scored) / 10
+	}
+}
+
+// entailScoreValue 从判定输出中取第一个数字
+var entailScoreValue = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// entailScore 用模型判定证据对论断的蕴含程度（0-10）
+func (s *Service) entailScore(ctx context.Context, doc *Document, statement string) (float64, error) {
+	prompt := fmt.Sprintf(
+		"判断以下证据是否支撑论断。只输出0到10的数字：0表示完全不支撑或矛盾，10表示完全支撑。\n\n证据：\n%s\n\n论断：%s\n\n得分：",
+		excerpt(doc.Content, answerExcerptLimit), statement)
+	output, err := s.completer.Complete(ctx, s.provider, prompt)
+	if err != nil {
+		return 0, err
+	}
+	match := entailScoreValue.FindString(output)
+	if match == "" {
+		return 0, fmt.Errorf("no score in entailment output")
+	}
+	score, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0, err
+	}
+	if score < 0 {
+		score = 0
+	}
+	if score > 10 {
+		score = 10
+	}
+	return score, nil
+}
+
+// excerpt 截取证据摘录
+func excerpt(content string, max int) string {
+	runes := []rune(content)
+	if len(runes) <= max {
+		return content
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,172 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// 绑定上下文类型
+const (
+	ContextRoom    = "room"
+	ContextProject = "project"
+)
+
+// Binding 知识源与聊天室/项目的绑定
+//
+// 绑定后该上下文内的问答只从绑定的源取材：检索按源ID白名单
+// 过滤，未绑定任何源的上下文直接拒答，杜绝越权取材。
+type Binding struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// ContextType room/project
+	ContextType string    `json:"contextType" gorm:"uniqueIndex:idx_knowledge_binding;type:varchar(16);not null"`
+	ContextID   string    `json:"contextId" gorm:"uniqueIndex:idx_knowledge_binding;type:varchar(36);not null"`
+	SourceID    string    `json:"sourceId" gorm:"uniqueIndex:idx_knowledge_binding;type:varchar(36);not null"`
+	CreatedBy   string    `json:"createdBy" gorm:"type:varchar(36)"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Binding) TableName() string {
+	return "cs_knowledge_bindings"
+}
+
+// AnswerAudit 一次问答的取材审计
+//
+// 记录每次回答实际引用了哪些文档，供溯源与合规检查。
+type AnswerAudit struct {
+	ID          string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ContextType string `json:"contextType,omitempty" gorm:"index:idx_knowledge_audit_ctx;type:varchar(16)"`
+	ContextID   string `json:"contextId,omitempty" gorm:"index:idx_knowledge_audit_ctx;type:varchar(36)"`
+	Question    string `json:"question" gorm:"type:varchar(1024)"`
+	// DocumentIDs 回答取材的文档ID列表
+	DocumentIDs datatypes.JSON `json:"documentIds"`
+	// Confidence 回答的引用置信度（0-1）
+	Confidence float64   `json:"confidence"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AnswerAudit) TableName() string {
+	return "cs_knowledge_answer_audits"
+}
+
+// BindRequest 创建绑定的参数
+type BindRequest struct {
+	ContextType string `json:"contextType" binding:"required"`
+	ContextID   string `json:"contextId" binding:"required"`
+	SourceID    string `json:"sourceId" binding:"required"`
+	CreatedBy   string `json:"createdBy"`
+}
+
+// Bind 把知识源绑定到聊天室或项目
+func (s *Service) Bind(ctx context.Context, req *BindRequest) (*Binding, error) {
+	if req.ContextType != ContextRoom && req.ContextType != ContextProject {
+		return nil, fmt.Errorf("invalid context type: %s", req.ContextType)
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&Source{}).
+		Where("id = ?", req.SourceID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check knowledge source: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("knowledge source not found")
+	}
+	binding := &Binding{
+		ID:          uuid.NewString(),
+		ContextType: req.ContextType,
+		ContextID:   req.ContextID,
+		SourceID:    req.SourceID,
+		CreatedBy:   req.CreatedBy,
+	}
+	if err := s.db.WithContext(ctx).Create(binding).Error; err != nil {
+		return nil, fmt.Errorf("failed to create binding: %w", err)
+	}
+	return binding, nil
+}
+
+// ListBindings 列出某上下文的全部绑定
+func (s *Service) ListBindings(ctx context.Context, contextType, contextID string) ([]*Binding, error) {
+	var bindings []*Binding
+	err := s.db.WithContext(ctx).
+		Where("context_type = ? AND context_id = ?", contextType, contextID).
+		Order("created_at ASC").
+		Find(&bindings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bindings: %w", err)
+	}
+	return bindings, nil
+}
+
+// Unbind 解除绑定
+func (s *Service) Unbind(ctx context.Context, bindingID string) error {
+	result := s.db.WithContext(ctx).Delete(&Binding{}, "id = ?", bindingID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete binding: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("binding not found")
+	}
+	return nil
+}
+
+// boundSourceIDs 求上下文绑定的源ID集合
+func (s *Service) boundSourceIDs(ctx context.Context, contextType, contextID string) ([]string, error) {
+	var bindings []*Binding
+	err := s.db.WithContext(ctx).
+		Select("source_id").
+		Where("context_type = ? AND context_id = ?", contextType, contextID).
+		Find(&bindings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bindings: %w", err)
+	}
+	ids := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		ids = append(ids, binding.SourceID)
+	}
+	return ids, nil
+}
+
+// recordAudit 落一条问答取材审计
+func (s *Service) recordAudit(ctx context.Context, contextType, contextID, question string, answer *Answer) {
+	ids := make([]string, 0, len(answer.Citations))
+	for _, citation := range answer.Citations {
+		ids = append(ids, citation.DocumentID)
+	}
+	raw, err := json.Marshal(ids)
+	if err != nil {
+		s.logger.Error("failed to marshal audit document ids")
+		return
+	}
+	audit := &AnswerAudit{
+		ID:          uuid.NewString(),
+		ContextType: contextType,
+		ContextID:   contextID,
+		Question:    truncate(question, 1024),
+		DocumentIDs: raw,
+		Confidence:  answer.CitationConfidence,
+	}
+	if err := s.db.WithContext(ctx).Create(audit).Error; err != nil {
+		s.logger.Error("failed to record answer audit")
+	}
+}
+
+// ListAudits 列出问答取材审计，可按上下文过滤
+func (s *Service) ListAudits(ctx context.Context, contextType, contextID string, limit int) ([]*AnswerAudit, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if contextType != "" {
+		query = query.Where("context_type = ? AND context_id = ?", contextType, contextID)
+	}
+	var audits []*AnswerAudit
+	if err := query.Find(&audits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list answer audits: %w", err)
+	}
+	return audits, nil
+}
@@ -0,0 +1,364 @@
+package knowledge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxDocsPerSource 单个源单次同步的文档上限
+const maxDocsPerSource = 500
+
+// ConfluenceConnector 通过Confluence REST API拉取空间内页面
+type ConfluenceConnector struct {
+	client *http.Client
+}
+
+// NewConfluenceConnector 创建Confluence连接器
+func NewConfluenceConnector() *ConfluenceConnector {
+	return &ConfluenceConnector{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Type 返回知识源类型
+func (c *ConfluenceConnector) Type() string {
+	return SourceConfluence
+}
+
+// Fetch 分页拉取空间内全部页面
+func (c *ConfluenceConnector) Fetch(ctx context.Context, source *Source) ([]*ExternalDoc, error) {
+	var docs []*ExternalDoc
+	for start := 0; len(docs) < maxDocsPerSource; start += 50 {
+		url := fmt.Sprintf("%s/rest/api/content?spaceKey=%s&type=page&expand=body.storage&limit=50&start=%d",
+			source.BaseURL, source.Space, start)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+source.Token)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("confluence request failed: %w", err)
+		}
+		var parsed struct {
+			Results []struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+				Body  struct {
+					Storage struct {
+						Value string `json:"value"`
+					} `json:"storage"`
+				} `json:"body"`
+				Links struct {
+					WebUI string `json:"webui"`
+				} `json:"_links"`
+			} `json:"results"`
+			Size int `json:"size"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("confluence returned status %d", resp.StatusCode)
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode confluence response: %w", decodeErr)
+		}
+		for _, page := range parsed.Results {
+			docs = append(docs, &ExternalDoc{
+				ExternalID: page.ID,
+				Title:      page.Title,
+				Content:    extractText(page.Body.Storage.Value),
+				URL:        source.BaseURL + page.Links.WebUI,
+			})
+		}
+		if parsed.Size < 50 {
+			break
+		}
+	}
+	return docs, nil
+}
+
+// notionVersion Notion API版本头
+const notionVersion = "2022-06-28"
+
+// NotionConnector 通过Notion API拉取数据库内页面
+type NotionConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewNotionConnector 创建Notion连接器
+func NewNotionConnector() *NotionConnector {
+	return &NotionConnector{
+		baseURL: "https://api.notion.com/v1",
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Type 返回知识源类型
+func (n *NotionConnector) Type() string {
+	return SourceNotion
+}
+
+// Fetch 查询数据库内页面并拉取各页正文
+func (n *NotionConnector) Fetch(ctx context.Context, source *Source) ([]*ExternalDoc, error) {
+	var docs []*ExternalDoc
+	var cursor string
+	for len(docs) < maxDocsPerSource {
+		payload := map[string]interface{}{"page_size": 50}
+		if cursor != "" {
+			payload["start_cursor"] = cursor
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			n.baseURL+"/databases/"+source.Space+"/query", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		n.setHeaders(req, source.Token)
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("notion request failed: %w", err)
+		}
+		var parsed struct {
+			Results []struct {
+				ID         string `json:"id"`
+				URL        string `json:"url"`
+				Properties map[string]struct {
+					Title []struct {
+						PlainText string `json:"plain_text"`
+					} `json:"title"`
+				} `json:"properties"`
+			} `json:"results"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("notion returned status %d", resp.StatusCode)
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode notion response: %w", decodeErr)
+		}
+		for _, page := range parsed.Results {
+			var title string
+			for _, prop := range page.Properties {
+				for _, part := range prop.Title {
+					title += part.PlainText
+				}
+			}
+			content, err := n.fetchBlocks(ctx, source.Token, page.ID)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, &ExternalDoc{
+				ExternalID: page.ID,
+				Title:      title,
+				Content:    content,
+				URL:        page.URL,
+			})
+		}
+		if !parsed.HasMore {
+			break
+		}
+		cursor = parsed.NextCursor
+	}
+	return docs, nil
+}
+
+// fetchBlocks 拉取页面顶层块的纯文本
+func (n *NotionConnector) fetchBlocks(ctx context.Context, token, pageID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		n.baseURL+"/blocks/"+pageID+"/children?page_size=100", nil)
+	if err != nil {
+		return "", err
+	}
+	n.setHeaders(req, token)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("notion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("notion returned status %d", resp.StatusCode)
+	}
+	var parsed struct {
+		Results []map[string]json.RawMessage `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("failed to decode notion blocks: %w", err)
+	}
+	var b strings.Builder
+	for _, block := range parsed.Results {
+		// 各块类型的正文都在同名字段的rich_text里
+		for key, raw := range block {
+			var inner struct {
+				RichText []struct {
+					PlainText string `json:"plain_text"`
+				} `json:"rich_text"`
+			}
+			if key == "id" || key == "type" || json.Unmarshal(raw, &inner) != nil {
+				continue
+			}
+			for _, part := range inner.RichText {
+				b.WriteString(part.PlainText)
+			}
+			if len(inner.RichText) > 0 {
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// setHeaders 设置Notion API公共请求头
+func (n *NotionConnector) setHeaders(req *http.Request, token string) {
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Notion-Version", notionVersion)
+	req.Header.Set("Content-Type", "application/json")
+}
+
+// SitemapConnector 通过站点地图抓取网站页面
+type SitemapConnector struct {
+	client *http.Client
+}
+
+// NewSitemapConnector 创建站点地图连接器
+func NewSitemapConnector() *SitemapConnector {
+	return &SitemapConnector{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Type 返回知识源类型
+func (sc *SitemapConnector) Type() string {
+	return SourceSitemap
+}
+
+// Fetch 解析sitemap.xml并抓取各页面正文
+func (sc *SitemapConnector) Fetch(ctx context.Context, source *Source) ([]*ExternalDoc, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.BaseURL+"/sitemap.xml", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sitemap request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
+	}
+	var sitemap struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&sitemap); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+
+	var docs []*ExternalDoc
+	for _, entry := range sitemap.URLs {
+		if len(docs) >= maxDocsPerSource {
+			break
+		}
+		loc := strings.TrimSpace(entry.Loc)
+		if loc == "" {
+			continue
+		}
+		title, content, err := sc.fetchPage(ctx, loc)
+		if err != nil {
+			// 单页失败不中断整站同步
+			continue
+		}
+		docs = append(docs, &ExternalDoc{
+			ExternalID: loc,
+			Title:      title,
+			Content:    content,
+			URL:        loc,
+		})
+	}
+	return docs, nil
+}
+
+// fetchPage 抓取单个页面并抽取标题与正文
+func (sc *SitemapConnector) fetchPage(ctx context.Context, url string) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "text/html")
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("page returned status %d", resp.StatusCode)
+	}
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	var title string
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "nav", "footer":
+				return
+			case "title":
+				if title == "" && n.FirstChild != nil {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			}
+		}
+		if n.Type == html.TextNode {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				b.WriteString(text)
+				b.WriteString("\n")
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return title, b.String(), nil
+}
+
+// extractText 从HTML片段抽取纯文本
+func extractText(fragment string) string {
+	doc, err := html.Parse(strings.NewReader(fragment))
+	if err != nil {
+		return fragment
+	}
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		if n.Type == html.TextNode {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				b.WriteString(text)
+				b.WriteString("\n")
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return b.String()
+}
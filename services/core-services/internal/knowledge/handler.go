@@ -0,0 +1,173 @@
+package knowledge
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 知识库HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建知识库处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册知识库相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/knowledge/sources", h.CreateSource)
+	router.GET("/knowledge/sources", h.ListSources)
+	router.DELETE("/knowledge/sources/:sourceId", h.DeleteSource)
+	router.POST("/knowledge/sources/:sourceId/sync", h.Sync)
+	router.GET("/knowledge/search", h.Search)
+	router.POST("/knowledge/ask", h.Ask)
+	router.POST("/knowledge/bindings", h.Bind)
+	router.GET("/knowledge/bindings", h.ListBindings)
+	router.DELETE("/knowledge/bindings/:bindingId", h.Unbind)
+	router.GET("/knowledge/audits", h.ListAudits)
+}
+
+// CreateSource 登记知识源
+func (h *Handler) CreateSource(c *gin.Context) {
+	var req CreateSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	source, err := h.service.CreateSource(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, source)
+}
+
+// ListSources 列出知识源及同步状态
+func (h *Handler) ListSources(c *gin.Context) {
+	sources, err := h.service.ListSources(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sources": sources})
+}
+
+// DeleteSource 删除知识源
+func (h *Handler) DeleteSource(c *gin.Context) {
+	if err := h.service.DeleteSource(c.Request.Context(), c.Param("sourceId")); err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "知识源已删除"})
+}
+
+// Sync 手动触发一次同步
+func (h *Handler) Sync(c *gin.Context) {
+	source, err := h.service.Sync(c.Request.Context(), c.Param("sourceId"))
+	if err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, source)
+}
+
+// Search 在可见范围内检索文档
+//
+// scope为逗号分隔的范围标识，来自调用方的身份解析。
+func (h *Handler) Search(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var scopes []string
+	if raw := c.Query("scope"); raw != "" {
+		scopes = strings.Split(raw, ",")
+	}
+	results, err := h.service.Search(c.Request.Context(), c.Query("q"), scopes, limit)
+	if err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Ask 基于知识库的问答，回答附引用核验结果
+func (h *Handler) Ask(c *gin.Context) {
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	answer, err := h.service.Ask(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, answer)
+}
+
+// Bind 把知识源绑定到聊天室或项目
+func (h *Handler) Bind(c *gin.Context) {
+	var req BindRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	binding, err := h.service.Bind(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, binding)
+}
+
+// ListBindings 列出上下文的绑定
+func (h *Handler) ListBindings(c *gin.Context) {
+	bindings, err := h.service.ListBindings(c.Request.Context(),
+		c.Query("contextType"), c.Query("contextId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bindings": bindings})
+}
+
+// Unbind 解除绑定
+func (h *Handler) Unbind(c *gin.Context) {
+	if err := h.service.Unbind(c.Request.Context(), c.Param("bindingId")); err != nil {
+		c.JSON(statusForKnowledgeError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "绑定已解除"})
+}
+
+// ListAudits 列出问答取材审计
+func (h *Handler) ListAudits(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	audits, err := h.service.ListAudits(c.Request.Context(),
+		c.Query("contextType"), c.Query("contextId"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audits": audits})
+}
+
+// statusForKnowledgeError 根据错误内容映射HTTP状态码
+func statusForKnowledgeError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "no knowledge bound"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "unknown source type"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "completion failed"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,471 @@
+package knowledge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 知识源类型
+const (
+	SourceConfluence = "confluence"
+	SourceNotion     = "notion"
+	SourceSitemap    = "sitemap"
+)
+
+// 同步状态
+const (
+	SyncStatusSuccess = "success"
+	SyncStatusFailed  = "failed"
+)
+
+// ScopePublic 所有调用方可见的访问范围
+const ScopePublic = "public"
+
+// defaultSyncInterval 默认同步间隔（分钟）
+const defaultSyncInterval = 60
+
+// Source 外部知识源
+//
+// Scope限定哪些调用方能在检索中看到该源的文档：public对所有人
+// 可见，否则为逗号分隔的范围标识，检索时与请求方的范围求交。
+type Source struct {
+	ID   string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name string `json:"name" gorm:"uniqueIndex;type:varchar(128);not null"`
+	// Type confluence/notion/sitemap
+	Type    string `json:"type" gorm:"type:varchar(32);not null"`
+	BaseURL string `json:"baseUrl" gorm:"type:varchar(512);not null"`
+	Token   string `json:"-" gorm:"type:text"`
+	// Space Confluence空间键或Notion数据库ID，站点地图留空
+	Space string `json:"space,omitempty" gorm:"type:varchar(128)"`
+	Scope string `json:"scope" gorm:"type:varchar(255);not null"`
+	// SyncIntervalMinutes 同步间隔
+	SyncIntervalMinutes int        `json:"syncIntervalMinutes"`
+	NextSyncAt          *time.Time `json:"nextSyncAt,omitempty" gorm:"index"`
+	LastSyncAt          *time.Time `json:"lastSyncAt,omitempty"`
+	// LastSyncStatus success/failed
+	LastSyncStatus string `json:"lastSyncStatus,omitempty" gorm:"type:varchar(16)"`
+	LastSyncError  string `json:"lastSyncError,omitempty" gorm:"type:varchar(512)"`
+	// DocumentCount 当前文档数
+	DocumentCount int `json:"documentCount"`
+	// ChangedCount 最近一次同步新增或更新的文档数
+	ChangedCount int       `json:"changedCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Source) TableName() string {
+	return "cs_knowledge_sources"
+}
+
+// Document 已同步的知识文档
+//
+// ContentHash用于变更检测：内容未变的文档在增量同步中跳过。
+type Document struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	SourceID string `json:"sourceId" gorm:"uniqueIndex:idx_knowledge_doc_source_ext;type:varchar(36);not null"`
+	// ExternalID 来源方的文档标识（页面ID或URL）
+	ExternalID  string    `json:"externalId" gorm:"uniqueIndex:idx_knowledge_doc_source_ext;type:varchar(512);not null"`
+	Title       string    `json:"title" gorm:"type:varchar(512)"`
+	Content     string    `json:"content" gorm:"type:text"`
+	URL         string    `json:"url" gorm:"type:varchar(1024)"`
+	ContentHash string    `json:"-" gorm:"type:char(64);not null"`
+	SyncedAt    time.Time `json:"syncedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Document) TableName() string {
+	return "cs_knowledge_documents"
+}
+
+// ExternalDoc 连接器返回的一篇外部文档
+type ExternalDoc struct {
+	ExternalID string
+	Title      string
+	Content    string
+	URL        string
+}
+
+// Connector 外部知识源连接器
+type Connector interface {
+	// Type 返回知识源类型
+	Type() string
+	// Fetch 拉取源内全部文档
+	Fetch(ctx context.Context, source *Source) ([]*ExternalDoc, error)
+}
+
+// Service 知识库同步与检索服务
+type Service struct {
+	db         *gorm.DB
+	logger     *zap.Logger
+	connectors map[string]Connector
+
+	// completer 可选的补全接线，启用后才提供带引用核验的问答
+	completer Completer
+	provider  string
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建知识库服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Source{}, &Document{}, &Binding{}, &AnswerAudit{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate knowledge tables: %w", err)
+	}
+	return &Service{
+		db:         db,
+		logger:     logger,
+		connectors: make(map[string]Connector),
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+// RegisterConnector 注册知识源连接器
+func (s *Service) RegisterConnector(connector Connector) {
+	s.connectors[connector.Type()] = connector
+}
+
+// CreateSourceRequest 创建知识源的参数
+type CreateSourceRequest struct {
+	Name                string `json:"name" binding:"required"`
+	Type                string `json:"type" binding:"required"`
+	BaseURL             string `json:"baseUrl" binding:"required"`
+	Token               string `json:"token"`
+	Space               string `json:"space"`
+	Scope               string `json:"scope"`
+	SyncIntervalMinutes int    `json:"syncIntervalMinutes"`
+}
+
+// CreateSource 登记一个外部知识源
+func (s *Service) CreateSource(ctx context.Context, req *CreateSourceRequest) (*Source, error) {
+	if _, ok := s.connectors[req.Type]; !ok {
+		return nil, fmt.Errorf("unknown source type: %s", req.Type)
+	}
+	if req.Scope == "" {
+		req.Scope = ScopePublic
+	}
+	if req.SyncIntervalMinutes <= 0 {
+		req.SyncIntervalMinutes = defaultSyncInterval
+	}
+	now := time.Now()
+	source := &Source{
+		ID:                  uuid.NewString(),
+		Name:                req.Name,
+		Type:                req.Type,
+		BaseURL:             strings.TrimRight(req.BaseURL, "/"),
+		Token:               req.Token,
+		Space:               req.Space,
+		Scope:               req.Scope,
+		SyncIntervalMinutes: req.SyncIntervalMinutes,
+		NextSyncAt:          &now,
+	}
+	if err := s.db.WithContext(ctx).Create(source).Error; err != nil {
+		return nil, fmt.Errorf("failed to create knowledge source: %w", err)
+	}
+	return source, nil
+}
+
+// ListSources 列出知识源及同步状态
+func (s *Service) ListSources(ctx context.Context) ([]*Source, error) {
+	var sources []*Source
+	err := s.db.WithContext(ctx).Order("created_at ASC").Find(&sources).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list knowledge sources: %w", err)
+	}
+	return sources, nil
+}
+
+// DeleteSource 删除知识源及其全部文档
+func (s *Service) DeleteSource(ctx context.Context, sourceID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Document{}, "source_id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&Source{}, "id = ?", sourceID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("knowledge source not found")
+		}
+		return nil
+	})
+}
+
+// Sync 对单个知识源执行一次同步
+func (s *Service) Sync(ctx context.Context, sourceID string) (*Source, error) {
+	var source Source
+	err := s.db.WithContext(ctx).First(&source, "id = ?", sourceID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("knowledge source not found")
+		}
+		return nil, fmt.Errorf("failed to load knowledge source: %w", err)
+	}
+	s.syncSource(ctx, &source)
+	return &source, nil
+}
+
+// syncSource 拉取源内文档并做变更检测后落库
+//
+// 内容哈希未变的文档跳过；来源方已删除的文档同步删除。
+func (s *Service) syncSource(ctx context.Context, source *Source) {
+	connector, ok := s.connectors[source.Type]
+	now := time.Now()
+	var changed int
+	var syncErr error
+	if !ok {
+		syncErr = fmt.Errorf("unknown source type: %s", source.Type)
+	} else {
+		var docs []*ExternalDoc
+		docs, syncErr = connector.Fetch(ctx, source)
+		if syncErr == nil {
+			changed, syncErr = s.applyDocs(ctx, source, docs, now)
+		}
+	}
+
+	next := now.Add(time.Duration(source.SyncIntervalMinutes) * time.Minute)
+	updates := map[string]interface{}{
+		"last_sync_at":  now,
+		"next_sync_at":  next,
+		"changed_count": changed,
+	}
+	if syncErr != nil {
+		updates["last_sync_status"] = SyncStatusFailed
+		updates["last_sync_error"] = truncate(syncErr.Error(), 512)
+		s.logger.Error("knowledge sync failed",
+			zap.String("source", source.Name), zap.Error(syncErr))
+	} else {
+		updates["last_sync_status"] = SyncStatusSuccess
+		updates["last_sync_error"] = ""
+		var count int64
+		s.db.WithContext(ctx).Model(&Document{}).
+			Where("source_id = ?", source.ID).Count(&count)
+		updates["document_count"] = int(count)
+	}
+	if err := s.db.WithContext(ctx).Model(&Source{}).
+		Where("id = ?", source.ID).
+		Updates(updates).Error; err != nil {
+		s.logger.Error("failed to update sync status", zap.Error(err))
+		return
+	}
+	s.db.WithContext(ctx).First(source, "id = ?", source.ID)
+}
+
+// applyDocs 将拉取结果合并进文档表，返回新增/更新的数量
+func (s *Service) applyDocs(ctx context.Context, source *Source, docs []*ExternalDoc, now time.Time) (int, error) {
+	var existing []*Document
+	err := s.db.WithContext(ctx).
+		Select("id", "external_id", "content_hash").
+		Where("source_id = ?", source.ID).
+		Find(&existing).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load existing documents: %w", err)
+	}
+	byExternalID := make(map[string]*Document, len(existing))
+	for _, doc := range existing {
+		byExternalID[doc.ExternalID] = doc
+	}
+
+	var changed int
+	seen := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if doc.ExternalID == "" || seen[doc.ExternalID] {
+			continue
+		}
+		seen[doc.ExternalID] = true
+		hash := sha256.Sum256([]byte(doc.Title + "\x00" + doc.Content))
+		contentHash := hex.EncodeToString(hash[:])
+		current, exists := byExternalID[doc.ExternalID]
+		if exists && current.ContentHash == contentHash {
+			continue
+		}
+		if exists {
+			err = s.db.WithContext(ctx).Model(&Document{}).
+				Where("id = ?", current.ID).
+				Updates(map[string]interface{}{
+					"title":        doc.Title,
+					"content":      doc.Content,
+					"url":          doc.URL,
+					"content_hash": contentHash,
+					"synced_at":    now,
+				}).Error
+		} else {
+			err = s.db.WithContext(ctx).Create(&Document{
+				ID:          uuid.NewString(),
+				SourceID:    source.ID,
+				ExternalID:  doc.ExternalID,
+				Title:       doc.Title,
+				Content:     doc.Content,
+				URL:         doc.URL,
+				ContentHash: contentHash,
+				SyncedAt:    now,
+			}).Error
+		}
+		if err != nil {
+			return changed, fmt.Errorf("failed to save document: %w", err)
+		}
+		changed++
+	}
+
+	// 来源方已删除的文档从库中移除
+	for externalID, doc := range byExternalID {
+		if !seen[externalID] {
+			if err := s.db.WithContext(ctx).Delete(&Document{}, "id = ?", doc.ID).Error; err != nil {
+				return changed, fmt.Errorf("failed to remove stale document: %w", err)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// SearchResult 检索结果中的一篇文档
+type SearchResult struct {
+	Document *Document `json:"document"`
+	Score    float64   `json:"score"`
+}
+
+// Search 在请求方可见范围内做关键词检索
+//
+// 供RAG取材：按词项在标题与正文中的出现次数打分，标题命中
+// 加权；请求方范围与源的Scope求交，public源对所有人可见。
+func (s *Service) Search(ctx context.Context, query string, scopes []string, limit int) ([]*SearchResult, error) {
+	sourceIDs, err := s.visibleSourceIDs(ctx, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchIn(ctx, query, sourceIDs, limit)
+}
+
+// searchIn 在给定源ID白名单内做关键词检索
+//
+// 白名单在SQL层过滤，上层无论如何构造查询都取不到名单外
+// 的文档。
+func (s *Service) searchIn(ctx context.Context, query string, sourceIDs []string, limit int) ([]*SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("invalid query: empty")
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	if len(sourceIDs) == 0 {
+		return []*SearchResult{}, nil
+	}
+
+	db := s.db.WithContext(ctx).Where("source_id IN ?", sourceIDs)
+	for _, term := range terms {
+		pattern := "%" + term + "%"
+		db = db.Where("LOWER(title) LIKE ? OR LOWER(content) LIKE ?", pattern, pattern)
+	}
+	var docs []*Document
+	if err := db.Limit(200).Find(&docs).Error; err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+
+	results := make([]*SearchResult, 0, len(docs))
+	for _, doc := range docs {
+		title := strings.ToLower(doc.Title)
+		content := strings.ToLower(doc.Content)
+		var score float64
+		for _, term := range terms {
+			score += 3 * float64(strings.Count(title, term))
+			score += float64(strings.Count(content, term))
+		}
+		results = append(results, &SearchResult{Document: doc, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// visibleSourceIDs 求请求方可见的知识源ID集合
+func (s *Service) visibleSourceIDs(ctx context.Context, scopes []string) ([]string, error) {
+	var sources []*Source
+	err := s.db.WithContext(ctx).Select("id", "scope").Find(&sources).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load knowledge sources: %w", err)
+	}
+	allowed := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		allowed[strings.TrimSpace(scope)] = true
+	}
+	var ids []string
+	for _, source := range sources {
+		if sourceVisible(source.Scope, allowed) {
+			ids = append(ids, source.ID)
+		}
+	}
+	return ids, nil
+}
+
+// sourceVisible 判断源对给定范围集合是否可见
+func sourceVisible(sourceScope string, allowed map[string]bool) bool {
+	for _, scope := range strings.Split(sourceScope, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == ScopePublic || allowed[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// Start 启动定时同步循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.syncDue(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止定时同步循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// syncDue 同步所有到期的知识源
+func (s *Service) syncDue(ctx context.Context) {
+	var due []*Source
+	err := s.db.WithContext(ctx).
+		Where("next_sync_at <= ?", time.Now()).
+		Limit(20).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due knowledge sources", zap.Error(err))
+		return
+	}
+	for _, source := range due {
+		s.syncSource(ctx, source)
+	}
+}
+
+// truncate 截断过长文本
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	return text[:max]
+}
@@ -0,0 +1,164 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 批量上报限制
+const (
+	// batchMaxPoints 单批点数上限
+	batchMaxPoints = 1000
+	// clockSkewTolerance 客户端时钟偏差超过该值时整批校正
+	clockSkewTolerance = 30 * time.Second
+	// dedupWindow 判重时间窗：同一用户同坐标在窗内视为重复
+	dedupWindow = time.Second
+)
+
+// BatchPoint 批量上报中的单个点
+type BatchPoint struct {
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	AccuracyM  float64   `json:"accuracyM"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// BatchRequest 批量上报请求
+//
+// deviceTime是客户端发送时刻的本地时间，用于推算时钟偏差并
+// 校正所有点的时间戳。
+type BatchRequest struct {
+	DeviceTime time.Time    `json:"deviceTime"`
+	Points     []BatchPoint `json:"points" binding:"required"`
+}
+
+// PointResult 单点的接受/拒绝结论
+type PointResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	// Reason 拒绝原因，接受时为空
+	Reason string `json:"reason,omitempty"`
+	// ID 接受后的位置记录ID
+	ID string `json:"id,omitempty"`
+}
+
+// BatchResult 批量上报结果
+type BatchResult struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	// ClockSkewMs 应用的时钟校正量（毫秒），未校正时为0
+	ClockSkewMs int64          `json:"clockSkewMs"`
+	Results     []*PointResult `json:"results"`
+}
+
+// IngestBatch 批量写入离线缓冲的位置点
+//
+// 逐点校验并去重，时间戳按deviceTime推算的偏差整批校正；
+// 地理围栏只按时间序逐点评估已接受的点。
+func (s *Service) IngestBatch(ctx context.Context, userID string, req *BatchRequest) (*BatchResult, error) {
+	if len(req.Points) == 0 {
+		return nil, fmt.Errorf("invalid batch: points must not be empty")
+	}
+	if len(req.Points) > batchMaxPoints {
+		return nil, fmt.Errorf("invalid batch: maximum %d points", batchMaxPoints)
+	}
+
+	// 客户端时钟偏差：serverNow - deviceTime，超容差才校正
+	now := time.Now()
+	var skew time.Duration
+	if !req.DeviceTime.IsZero() {
+		if diff := now.Sub(req.DeviceTime); diff > clockSkewTolerance || diff < -clockSkewTolerance {
+			skew = diff
+		}
+	}
+
+	result := &BatchResult{ClockSkewMs: skew.Milliseconds()}
+	var accepted []*Position
+	for i, point := range req.Points {
+		entry := &PointResult{Index: i, Status: "accepted"}
+		result.Results = append(result.Results, entry)
+
+		if err := validatePoint(point.Lat, point.Lng); err != nil {
+			entry.Status, entry.Reason = "rejected", err.Error()
+			continue
+		}
+		if point.RecordedAt.IsZero() {
+			entry.Status, entry.Reason = "rejected", "missing recordedAt"
+			continue
+		}
+		recordedAt := point.RecordedAt.Add(skew)
+		if recordedAt.After(now.Add(time.Minute)) {
+			entry.Status, entry.Reason = "rejected", "timestamp in the future"
+			continue
+		}
+		if s.isDuplicate(ctx, userID, point.Lat, point.Lng, recordedAt, accepted) {
+			entry.Status, entry.Reason = "rejected", "duplicate point"
+			continue
+		}
+
+		position := &Position{
+			ID:         uuid.NewString(),
+			UserID:     userID,
+			Lat:        point.Lat,
+			Lng:        point.Lng,
+			AccuracyM:  point.AccuracyM,
+			RecordedAt: recordedAt,
+		}
+		if err := s.db.WithContext(ctx).Create(position).Error; err != nil {
+			entry.Status, entry.Reason = "rejected", "failed to store point"
+			continue
+		}
+		entry.ID = position.ID
+		accepted = append(accepted, position)
+	}
+
+	// 按时间序评估围栏，保证entry/exit顺序正确
+	for _, position := range sortByRecordedAt(accepted) {
+		s.evaluateGeofences(ctx, position)
+	}
+
+	for _, entry := range result.Results {
+		if entry.Status == "accepted" {
+			result.Accepted++
+		} else {
+			result.Rejected++
+		}
+	}
+	return result, nil
+}
+
+// isDuplicate 判断点是否与库中或本批已接受的点重复
+func (s *Service) isDuplicate(ctx context.Context, userID string, lat, lng float64, recordedAt time.Time, accepted []*Position) bool {
+	for _, p := range accepted {
+		if p.Lat == lat && p.Lng == lng && absDuration(p.RecordedAt.Sub(recordedAt)) < dedupWindow {
+			return true
+		}
+	}
+	var count int64
+	err := s.db.WithContext(ctx).Model(&Position{}).
+		Where("user_id = ? AND lat = ? AND lng = ? AND recorded_at BETWEEN ? AND ?",
+			userID, lat, lng, recordedAt.Add(-dedupWindow), recordedAt.Add(dedupWindow)).
+		Count(&count).Error
+	return err == nil && count > 0
+}
+
+// sortByRecordedAt 按记录时间升序（插入排序，批量点基本有序）
+func sortByRecordedAt(points []*Position) []*Position {
+	for i := 1; i < len(points); i++ {
+		for j := i; j > 0 && points[j].RecordedAt.Before(points[j-1].RecordedAt); j-- {
+			points[j], points[j-1] = points[j-1], points[j]
+		}
+	}
+	return points
+}
+
+// absDuration 时长绝对值
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,315 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 围栏形状
+const (
+	FenceCircle  = "circle"
+	FencePolygon = "polygon"
+)
+
+// 围栏事件类型
+const (
+	EventEntry = "entry"
+	EventExit  = "exit"
+)
+
+// maxFencesPerUser 单用户的围栏数量上限，限制逐点评估开销
+const maxFencesPerUser = 50
+
+// maxPolygonVertices 多边形顶点上限
+const maxPolygonVertices = 100
+
+// Vertex 多边形顶点
+type Vertex struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Geofence 一个地理围栏
+//
+// circle用中心点+半径描述，polygon用顶点列表描述；围栏归属
+// 用户自己，只对其本人的位置上报生效。
+type Geofence struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	Name   string `json:"name" gorm:"type:varchar(128);not null"`
+	// Type circle/polygon
+	Type      string  `json:"type" gorm:"type:varchar(16);not null"`
+	CenterLat float64 `json:"centerLat,omitempty"`
+	CenterLng float64 `json:"centerLng,omitempty"`
+	RadiusM   float64 `json:"radiusM,omitempty"`
+	// Vertices polygon的顶点列表（JSON）
+	Vertices  datatypes.JSON `json:"vertices,omitempty" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Geofence) TableName() string {
+	return "cs_geofences"
+}
+
+// GeofenceEvent 一次围栏进出事件
+type GeofenceEvent struct {
+	ID         string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID     string `json:"userId" gorm:"index:idx_geofence_event_user;type:varchar(36);not null"`
+	GeofenceID string `json:"geofenceId" gorm:"index;type:varchar(36);not null"`
+	// Type entry/exit
+	Type       string    `json:"type" gorm:"type:varchar(16);not null"`
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"index:idx_geofence_event_user"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (GeofenceEvent) TableName() string {
+	return "cs_geofence_events"
+}
+
+// GeofenceRequest 创建围栏的参数
+type GeofenceRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Type      string   `json:"type" binding:"required"`
+	CenterLat float64  `json:"centerLat"`
+	CenterLng float64  `json:"centerLng"`
+	RadiusM   float64  `json:"radiusM"`
+	Vertices  []Vertex `json:"vertices"`
+}
+
+// CreateGeofence 创建地理围栏
+func (s *Service) CreateGeofence(ctx context.Context, userID string, req *GeofenceRequest) (*Geofence, error) {
+	fence := &Geofence{
+		ID:     uuid.NewString(),
+		UserID: userID,
+		Name:   req.Name,
+		Type:   req.Type,
+	}
+	switch req.Type {
+	case FenceCircle:
+		if err := validatePoint(req.CenterLat, req.CenterLng); err != nil {
+			return nil, err
+		}
+		if req.RadiusM <= 0 {
+			return nil, fmt.Errorf("invalid geofence: radiusM must be positive")
+		}
+		fence.CenterLat = req.CenterLat
+		fence.CenterLng = req.CenterLng
+		fence.RadiusM = req.RadiusM
+	case FencePolygon:
+		if len(req.Vertices) < 3 {
+			return nil, fmt.Errorf("invalid geofence: polygon requires at least 3 vertices")
+		}
+		if len(req.Vertices) > maxPolygonVertices {
+			return nil, fmt.Errorf("invalid geofence: maximum %d vertices", maxPolygonVertices)
+		}
+		for _, vertex := range req.Vertices {
+			if err := validatePoint(vertex.Lat, vertex.Lng); err != nil {
+				return nil, err
+			}
+		}
+		raw, err := json.Marshal(req.Vertices)
+		if err != nil {
+			return nil, err
+		}
+		fence.Vertices = raw
+	default:
+		return nil, fmt.Errorf("invalid geofence type: %s", req.Type)
+	}
+
+	var count int64
+	err := s.db.WithContext(ctx).Model(&Geofence{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count geofences: %w", err)
+	}
+	if count >= maxFencesPerUser {
+		return nil, fmt.Errorf("invalid geofence: maximum %d geofences per user", maxFencesPerUser)
+	}
+	if err := s.db.WithContext(ctx).Create(fence).Error; err != nil {
+		return nil, fmt.Errorf("failed to create geofence: %w", err)
+	}
+	return fence, nil
+}
+
+// ListGeofences 列出用户的围栏
+func (s *Service) ListGeofences(ctx context.Context, userID string) ([]*Geofence, error) {
+	var fences []*Geofence
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&fences).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list geofences: %w", err)
+	}
+	return fences, nil
+}
+
+// DeleteGeofence 删除围栏及其事件历史
+func (s *Service) DeleteGeofence(ctx context.Context, userID, fenceID string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&Geofence{}, "id = ? AND user_id = ?", fenceID, userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("geofence not found")
+		}
+		return tx.Delete(&GeofenceEvent{}, "geofence_id = ?", fenceID).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.fenceMu.Lock()
+	delete(s.fenceState, userID+"|"+fenceID)
+	s.fenceMu.Unlock()
+	return nil
+}
+
+// ListGeofenceEvents 用户的围栏事件历史
+func (s *Service) ListGeofenceEvents(ctx context.Context, userID, fenceID string, limit int) ([]*GeofenceEvent, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if fenceID != "" {
+		db = db.Where("geofence_id = ?", fenceID)
+	}
+	var events []*GeofenceEvent
+	if err := db.Order("occurred_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list geofence events: %w", err)
+	}
+	return events, nil
+}
+
+// evaluateGeofences 对一次上报评估用户的全部围栏
+//
+// 与上次状态比较产生entry/exit事件；评估失败只记日志，不影响
+// 位置写入。
+func (s *Service) evaluateGeofences(ctx context.Context, position *Position) {
+	fences, err := s.ListGeofences(ctx, position.UserID)
+	if err != nil {
+		s.logger.Warn("failed to load geofences for evaluation", zap.Error(err))
+		return
+	}
+	for _, fence := range fences {
+		inside := s.contains(fence, position.Lat, position.Lng)
+		was, known := s.lastState(ctx, position.UserID, fence.ID)
+		if known && was == inside {
+			continue
+		}
+		if !known && !inside {
+			// 首次评估且在围栏外：记录状态即可，不发exit
+			s.storeState(position.UserID, fence.ID, inside)
+			continue
+		}
+		eventType := EventExit
+		if inside {
+			eventType = EventEntry
+		}
+		s.storeState(position.UserID, fence.ID, inside)
+		s.emitGeofenceEvent(ctx, position, fence, eventType)
+	}
+}
+
+// lastState 查上次的围栏内外状态，冷启动时从最近事件恢复
+func (s *Service) lastState(ctx context.Context, userID, fenceID string) (bool, bool) {
+	key := userID + "|" + fenceID
+	s.fenceMu.Lock()
+	state, ok := s.fenceState[key]
+	s.fenceMu.Unlock()
+	if ok {
+		return state, true
+	}
+	var last GeofenceEvent
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND geofence_id = ?", userID, fenceID).
+		Order("occurred_at DESC").
+		First(&last).Error
+	if err != nil {
+		return false, false
+	}
+	inside := last.Type == EventEntry
+	s.storeState(userID, fenceID, inside)
+	return inside, true
+}
+
+// storeState 记录围栏内外状态
+func (s *Service) storeState(userID, fenceID string, inside bool) {
+	s.fenceMu.Lock()
+	s.fenceState[userID+"|"+fenceID] = inside
+	s.fenceMu.Unlock()
+}
+
+// emitGeofenceEvent 落库并发布围栏事件
+func (s *Service) emitGeofenceEvent(ctx context.Context, position *Position, fence *Geofence, eventType string) {
+	event := &GeofenceEvent{
+		ID:         uuid.NewString(),
+		UserID:     position.UserID,
+		GeofenceID: fence.ID,
+		Type:       eventType,
+		Lat:        position.Lat,
+		Lng:        position.Lng,
+		OccurredAt: position.RecordedAt,
+	}
+	if err := s.db.WithContext(ctx).Create(event).Error; err != nil {
+		s.logger.Warn("failed to record geofence event", zap.Error(err))
+		return
+	}
+	if s.publish != nil {
+		name := "location.geofence.exited"
+		if eventType == EventEntry {
+			name = "location.geofence.entered"
+		}
+		s.publish(name, map[string]interface{}{
+			"userId":       position.UserID,
+			"geofenceId":   fence.ID,
+			"geofenceName": fence.Name,
+			"lat":          position.Lat,
+			"lng":          position.Lng,
+			"occurredAt":   event.OccurredAt,
+		})
+	}
+}
+
+// contains 判断点是否在围栏内
+func (s *Service) contains(fence *Geofence, lat, lng float64) bool {
+	switch fence.Type {
+	case FenceCircle:
+		return haversineM(fence.CenterLat, fence.CenterLng, lat, lng) <= fence.RadiusM
+	case FencePolygon:
+		var vertices []Vertex
+		if err := json.Unmarshal(fence.Vertices, &vertices); err != nil {
+			return false
+		}
+		return pointInPolygon(lat, lng, vertices)
+	default:
+		return false
+	}
+}
+
+// pointInPolygon 射线法点在多边形内判定
+func pointInPolygon(lat, lng float64, vertices []Vertex) bool {
+	inside := false
+	n := len(vertices)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		vi, vj := vertices[i], vertices[j]
+		if (vi.Lat > lat) != (vj.Lat > lat) &&
+			lng < (vj.Lng-vi.Lng)*(lat-vi.Lat)/(vj.Lat-vi.Lat)+vi.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
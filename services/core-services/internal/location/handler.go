@@ -0,0 +1,344 @@
+package location
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 位置追踪HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建位置追踪处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册位置追踪路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/location")
+	{
+		group.POST("/positions", h.RecordPosition)
+		group.POST("/positions/batch", h.IngestBatch)
+		group.GET("/positions", h.ListPositions)
+		group.POST("/geofences", h.CreateGeofence)
+		group.GET("/geofences", h.ListGeofences)
+		group.DELETE("/geofences/:geofenceId", h.DeleteGeofence)
+		group.GET("/geofence-events", h.ListGeofenceEvents)
+		group.GET("/trips", h.ListTrips)
+		group.GET("/retention", h.GetRetention)
+		group.PUT("/retention", h.SetRetention)
+		group.GET("/export", h.ExportData)
+		group.DELETE("/data", h.DeleteData)
+	}
+}
+
+// requireUser 从请求头解析用户ID
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return "", false
+	}
+	return userID, true
+}
+
+// statusForLocationError 根据错误内容映射HTTP状态码
+func statusForLocationError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RecordPosition 上报一次位置
+func (h *Handler) RecordPosition(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Lat        float64   `json:"lat" binding:"required"`
+		Lng        float64   `json:"lng" binding:"required"`
+		AccuracyM  float64   `json:"accuracyM"`
+		RecordedAt time.Time `json:"recordedAt"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	position, err := h.service.RecordPosition(c.Request.Context(), userID, req.Lat, req.Lng, req.AccuracyM, req.RecordedAt)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, position)
+}
+
+// batchBodyLimit 批量上报请求体上限（解压后）
+const batchBodyLimit = 8 << 20
+
+// IngestBatch 批量上报离线缓冲的位置点
+//
+// 移动端可用Content-Encoding: gzip压缩请求体；逐点返回
+// 接受/拒绝结论。
+func (h *Handler) IngestBatch(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	body := io.Reader(c.Request.Body)
+	if strings.EqualFold(c.GetHeader("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+	var req BatchRequest
+	if err := json.NewDecoder(io.LimitReader(body, batchBodyLimit)).Decode(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch payload: " + err.Error()})
+		return
+	}
+	result, err := h.service.IngestBatch(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListPositions 查询位置历史
+func (h *Handler) ListPositions(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var since, until time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, expected RFC3339"})
+			return
+		}
+		since = parsed
+	}
+	if raw := c.Query("until"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid until, expected RFC3339"})
+			return
+		}
+		until = parsed
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	positions, err := h.service.ListPositions(c.Request.Context(), userID, since, until, limit)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"positions": positions, "total": len(positions)})
+}
+
+// CreateGeofence 创建地理围栏
+func (h *Handler) CreateGeofence(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fence, err := h.service.CreateGeofence(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, fence)
+}
+
+// ListGeofences 列出地理围栏
+func (h *Handler) ListGeofences(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	fences, err := h.service.ListGeofences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"geofences": fences, "total": len(fences)})
+}
+
+// DeleteGeofence 删除地理围栏
+func (h *Handler) DeleteGeofence(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteGeofence(c.Request.Context(), userID, c.Param("geofenceId")); err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "geofence deleted"})
+}
+
+// ListTrips 查询重建出的行程历史
+func (h *Handler) ListTrips(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var since, until time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, expected RFC3339"})
+			return
+		}
+		since = parsed
+	}
+	if raw := c.Query("until"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid until, expected RFC3339"})
+			return
+		}
+		until = parsed
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	trips, err := h.service.ListTrips(c.Request.Context(), userID, since, until, limit)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"trips": trips, "total": len(trips)})
+}
+
+// GetRetention 读取数据保留策略
+func (h *Handler) GetRetention(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	setting, err := h.service.GetRetention(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// SetRetention 保存数据保留策略
+func (h *Handler) SetRetention(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		RetainDays int    `json:"retainDays"`
+		Mode       string `json:"mode" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setting, err := h.service.SetRetention(c.Request.Context(), userID, req.RetainDays, req.Mode)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// ExportData 导出位置历史（format=geojson/gpx，默认geojson）
+func (h *Handler) ExportData(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var since, until time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, expected RFC3339"})
+			return
+		}
+		since = parsed
+	}
+	if raw := c.Query("until"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid until, expected RFC3339"})
+			return
+		}
+		until = parsed
+	}
+
+	switch c.DefaultQuery("format", "geojson") {
+	case "geojson":
+		doc, err := h.service.ExportGeoJSON(c.Request.Context(), userID, since, until)
+		if err != nil {
+			c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="locations.geojson"`)
+		c.JSON(http.StatusOK, doc)
+	case "gpx":
+		body, err := h.service.ExportGPX(c.Request.Context(), userID, since, until)
+		if err != nil {
+			c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="locations.gpx"`)
+		c.Data(http.StatusOK, "application/gpx+xml", body)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, expected geojson or gpx"})
+	}
+}
+
+// DeleteData 删除本人的全部位置数据（含派生行程）
+func (h *Handler) DeleteData(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteAllData(c.Request.Context(), userID); err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "location data deleted"})
+}
+
+// ListGeofenceEvents 查询围栏进出事件历史
+func (h *Handler) ListGeofenceEvents(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	events, err := h.service.ListGeofenceEvents(c.Request.Context(), userID, c.Query("geofenceId"), limit)
+	if err != nil {
+		c.JSON(statusForLocationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events, "total": len(events)})
+}
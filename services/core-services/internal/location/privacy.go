@@ -0,0 +1,255 @@
+package location
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"go.uber.org/zap"
+)
+
+// 保留策略到期后的处理方式
+const (
+	RetentionPurge     = "purge"
+	RetentionAnonymize = "anonymize"
+)
+
+// retentionInterval 保留策略的执行间隔
+const retentionInterval = time.Hour
+
+// anonymizeGridDeg 匿名化时坐标取整的粒度（度），约1公里
+const anonymizeGridDeg = 0.01
+
+// RetentionSetting 每用户的位置数据保留策略
+//
+// RetainDays为0表示不限期保留；到期的原始点按Mode清除或
+// 匿名化，行程等派生数据不受影响。
+type RetentionSetting struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	// RetainDays 原始点保留天数，0为不限
+	RetainDays int `json:"retainDays"`
+	// Mode purge/anonymize
+	Mode      string    `json:"mode" gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (RetentionSetting) TableName() string {
+	return "cs_location_retention"
+}
+
+// SetRetention 保存保留策略
+func (s *Service) SetRetention(ctx context.Context, userID string, retainDays int, mode string) (*RetentionSetting, error) {
+	if retainDays < 0 {
+		return nil, fmt.Errorf("invalid retention: retainDays must not be negative")
+	}
+	if mode != RetentionPurge && mode != RetentionAnonymize {
+		return nil, fmt.Errorf("invalid retention mode: %s", mode)
+	}
+	var setting RetentionSetting
+	err := s.db.WithContext(ctx).First(&setting, "user_id = ?", userID).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load retention setting: %w", err)
+		}
+		setting = RetentionSetting{ID: uuid.NewString(), UserID: userID}
+	}
+	setting.RetainDays = retainDays
+	setting.Mode = mode
+	if err := s.db.WithContext(ctx).Save(&setting).Error; err != nil {
+		return nil, fmt.Errorf("failed to save retention setting: %w", err)
+	}
+	return &setting, nil
+}
+
+// GetRetention 读取保留策略，未设置时返回默认值
+func (s *Service) GetRetention(ctx context.Context, userID string) (*RetentionSetting, error) {
+	var setting RetentionSetting
+	err := s.db.WithContext(ctx).First(&setting, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &RetentionSetting{UserID: userID, Mode: RetentionPurge}, nil
+		}
+		return nil, fmt.Errorf("failed to load retention setting: %w", err)
+	}
+	return &setting, nil
+}
+
+// enforceRetention 执行所有到期的保留策略
+func (s *Service) enforceRetention(ctx context.Context) {
+	var settings []*RetentionSetting
+	err := s.db.WithContext(ctx).
+		Where("retain_days > 0").
+		Limit(1000).
+		Find(&settings).Error
+	if err != nil {
+		s.logger.Error("failed to load retention settings", zap.Error(err))
+		return
+	}
+	for _, setting := range settings {
+		cutoff := time.Now().AddDate(0, 0, -setting.RetainDays)
+		var result *gorm.DB
+		switch setting.Mode {
+		case RetentionAnonymize:
+			// 坐标对齐到粗网格并抹掉精度，保留计数与时间分布
+			result = s.db.WithContext(ctx).Model(&Position{}).
+				Where("user_id = ? AND recorded_at < ? AND anonymized = ?", setting.UserID, cutoff, false).
+				Updates(map[string]interface{}{
+					"lat":        gorm.Expr("ROUND(lat / ?) * ?", anonymizeGridDeg, anonymizeGridDeg),
+					"lng":        gorm.Expr("ROUND(lng / ?) * ?", anonymizeGridDeg, anonymizeGridDeg),
+					"accuracy_m": 0,
+					"anonymized": true,
+				})
+		default:
+			result = s.db.WithContext(ctx).
+				Where("user_id = ? AND recorded_at < ?", setting.UserID, cutoff).
+				Delete(&Position{})
+		}
+		if result.Error != nil {
+			s.logger.Warn("failed to enforce location retention",
+				zap.String("userId", setting.UserID), zap.Error(result.Error))
+		} else if result.RowsAffected > 0 {
+			s.logger.Info("enforced location retention",
+				zap.String("userId", setting.UserID),
+				zap.String("mode", setting.Mode),
+				zap.Int64("rows", result.RowsAffected))
+		}
+	}
+}
+
+// DeleteAllData 删除用户的全部位置数据
+//
+// 原始点、行程与围栏事件一并删除，围栏定义保留；这是GDPR
+// 删除请求的入口，不可恢复。
+func (s *Service) DeleteAllData(ctx context.Context, userID string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Position{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&Trip{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&GeofenceEvent{}, "user_id = ?", userID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete location data: %w", err)
+	}
+	s.fenceMu.Lock()
+	for key := range s.fenceState {
+		if strings.HasPrefix(key, userID+"|") {
+			delete(s.fenceState, key)
+		}
+	}
+	s.fenceMu.Unlock()
+	return nil
+}
+
+// exportMaxPoints 单次导出的点数上限
+const exportMaxPoints = 50000
+
+// exportPoints 取导出用的全量点（时间升序）
+func (s *Service) exportPoints(ctx context.Context, userID string, since, until time.Time) ([]*Position, error) {
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !since.IsZero() {
+		db = db.Where("recorded_at >= ?", since)
+	}
+	if !until.IsZero() {
+		db = db.Where("recorded_at <= ?", until)
+	}
+	var points []*Position
+	if err := db.Order("recorded_at ASC").Limit(exportMaxPoints).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("failed to load positions for export: %w", err)
+	}
+	return points, nil
+}
+
+// ExportGeoJSON 导出GeoJSON FeatureCollection
+//
+// 轨迹作为单个LineString，点级时间戳放在properties.timestamps，
+// 与坐标一一对应。
+func (s *Service) ExportGeoJSON(ctx context.Context, userID string, since, until time.Time) (map[string]interface{}, error) {
+	points, err := s.exportPoints(ctx, userID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	coordinates := make([][]float64, 0, len(points))
+	timestamps := make([]string, 0, len(points))
+	for _, p := range points {
+		coordinates = append(coordinates, []float64{p.Lng, p.Lat})
+		timestamps = append(timestamps, p.RecordedAt.Format(time.RFC3339))
+	}
+	return map[string]interface{}{
+		"type": "FeatureCollection",
+		"features": []map[string]interface{}{
+			{
+				"type": "Feature",
+				"geometry": map[string]interface{}{
+					"type":        "LineString",
+					"coordinates": coordinates,
+				},
+				"properties": map[string]interface{}{
+					"userId":     userID,
+					"pointCount": len(points),
+					"timestamps": timestamps,
+				},
+			},
+		},
+	}, nil
+}
+
+// gpx GPX 1.1文档结构
+type gpx struct {
+	XMLName xml.Name `xml:"gpx"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"creator,attr"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Trk     gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name    string     `xml:"name"`
+	Segment gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// ExportGPX 导出GPX 1.1轨迹
+func (s *Service) ExportGPX(ctx context.Context, userID string, since, until time.Time) ([]byte, error) {
+	points, err := s.exportPoints(ctx, userID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	doc := gpx{
+		Version: "1.1",
+		Creator: "taishanglaojun",
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Trk:     gpxTrack{Name: "location history"},
+	}
+	for _, p := range points {
+		doc.Trk.Segment.Points = append(doc.Trk.Segment.Points, gpxPoint{
+			Lat:  p.Lat,
+			Lon:  p.Lng,
+			Time: p.RecordedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gpx: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
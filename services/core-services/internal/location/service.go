@@ -0,0 +1,160 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Position 一次位置上报
+type Position struct {
+	ID     string  `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string  `json:"userId" gorm:"index:idx_position_user_time;type:varchar(36);not null"`
+	Lat    float64 `json:"lat"`
+	Lng    float64 `json:"lng"`
+	// AccuracyM 定位精度（米），0表示未知
+	AccuracyM float64 `json:"accuracyM,omitempty"`
+	// Anonymized 已按保留策略匿名化，坐标只到粗网格精度
+	Anonymized bool      `json:"anonymized,omitempty"`
+	RecordedAt time.Time `json:"recordedAt" gorm:"index:idx_position_user_time"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Position) TableName() string {
+	return "cs_location_positions"
+}
+
+// Service 位置追踪服务
+//
+// 位置上报即时评估地理围栏，进出状态保存在进程内并在冷启动
+// 时从最近一次事件惰性恢复。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	// publish 事件发布器，围栏进出走事件流
+	publish func(event string, payload interface{})
+
+	// fenceMu 保护fenceState与tripSince
+	fenceMu sync.Mutex
+	// fenceState user|fence → 是否在围栏内
+	fenceState map[string]bool
+	// tripSince 行程重建的扫描水位线
+	tripSince time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建位置追踪服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Position{}, &Geofence{}, &GeofenceEvent{}, &Trip{}, &RetentionSetting{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate location tables: %w", err)
+	}
+	return &Service{
+		db:         db,
+		logger:     logger,
+		fenceState: make(map[string]bool),
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+// Start 启动行程重建与保留策略循环
+func (s *Service) Start() {
+	go func() {
+		tripTicker := time.NewTicker(tripInterval)
+		defer tripTicker.Stop()
+		retentionTicker := time.NewTicker(retentionInterval)
+		defer retentionTicker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-tripTicker.C:
+				s.reconstructTrips(context.Background())
+			case <-retentionTicker.C:
+				s.enforceRetention(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止后台循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// SetEventPublisher 接入事件发布（location.geofence.entered/exited）
+func (s *Service) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// validatePoint 经纬度合法性校验
+func validatePoint(lat, lng float64) error {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return fmt.Errorf("invalid coordinates: lat %f lng %f", lat, lng)
+	}
+	return nil
+}
+
+// RecordPosition 记录一次位置并评估地理围栏
+func (s *Service) RecordPosition(ctx context.Context, userID string, lat, lng, accuracyM float64, recordedAt time.Time) (*Position, error) {
+	if err := validatePoint(lat, lng); err != nil {
+		return nil, err
+	}
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+	position := &Position{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		Lat:        lat,
+		Lng:        lng,
+		AccuracyM:  accuracyM,
+		RecordedAt: recordedAt,
+	}
+	if err := s.db.WithContext(ctx).Create(position).Error; err != nil {
+		return nil, fmt.Errorf("failed to record position: %w", err)
+	}
+	s.evaluateGeofences(ctx, position)
+	return position, nil
+}
+
+// ListPositions 列出用户的位置历史
+func (s *Service) ListPositions(ctx context.Context, userID string, since, until time.Time, limit int) ([]*Position, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 200
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !since.IsZero() {
+		db = db.Where("recorded_at >= ?", since)
+	}
+	if !until.IsZero() {
+		db = db.Where("recorded_at <= ?", until)
+	}
+	var positions []*Position
+	if err := db.Order("recorded_at DESC").Limit(limit).Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	return positions, nil
+}
+
+// earthRadiusM 地球半径（米）
+const earthRadiusM = 6371000
+
+// haversineM 两点间大圆距离（米）
+func haversineM(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(a))
+}
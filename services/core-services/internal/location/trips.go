@@ -0,0 +1,265 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 行程切分参数
+const (
+	// tripInterval 行程重建的扫描间隔
+	tripInterval = 5 * time.Minute
+	// tripGap 相邻点间隔超过该值视为行程结束
+	tripGap = 10 * time.Minute
+	// tripMinPoints 一段行程至少包含的点数
+	tripMinPoints = 5
+	// tripMinDistanceM 一段行程的最小总里程（米），过滤原地漂移
+	tripMinDistanceM = 200
+	// tripLookback 扫描窗口，重启后最多回溯这么久的原始点
+	tripLookback = 24 * time.Hour
+	// simplifyToleranceM 折线抽稀容差（米）
+	simplifyToleranceM = 25
+)
+
+// Trip 一段重建出的行程
+//
+// 没有路网数据，polyline是对原始点做Douglas-Peucker抽稀后的
+// 编码折线（Google polyline算法），不是真正的地图匹配结果。
+type Trip struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID    string    `json:"userId" gorm:"index:idx_trip_user_time;type:varchar(36);not null"`
+	StartAt   time.Time `json:"startAt" gorm:"index:idx_trip_user_time"`
+	EndAt     time.Time `json:"endAt"`
+	StartLat  float64   `json:"startLat"`
+	StartLng  float64   `json:"startLng"`
+	EndLat    float64   `json:"endLat"`
+	EndLng    float64   `json:"endLng"`
+	DistanceM float64   `json:"distanceM"`
+	// DurationS 行程时长（秒）
+	DurationS int64 `json:"durationS"`
+	// AvgSpeedKMH 平均速度（公里/小时）
+	AvgSpeedKMH float64   `json:"avgSpeedKmh"`
+	PointCount  int       `json:"pointCount"`
+	Polyline    string    `json:"polyline" gorm:"type:text"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Trip) TableName() string {
+	return "cs_location_trips"
+}
+
+// ListTrips 查询用户的行程历史
+func (s *Service) ListTrips(ctx context.Context, userID string, since, until time.Time, limit int) ([]*Trip, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !since.IsZero() {
+		db = db.Where("start_at >= ?", since)
+	}
+	if !until.IsZero() {
+		db = db.Where("start_at <= ?", until)
+	}
+	var trips []*Trip
+	if err := db.Order("start_at DESC").Limit(limit).Find(&trips).Error; err != nil {
+		return nil, fmt.Errorf("failed to list trips: %w", err)
+	}
+	return trips, nil
+}
+
+// reconstructTrips 把原始位置点切分成行程
+//
+// 只处理已经"收尾"的片段：最后一个点距今超过tripGap才落库，
+// 进行中的行程留到下一轮。水位线避免重复切分同一批点。
+func (s *Service) reconstructTrips(ctx context.Context) {
+	since := s.tripWatermark()
+	var userIDs []string
+	err := s.db.WithContext(ctx).Model(&Position{}).
+		Distinct("user_id").
+		Where("recorded_at > ?", since).
+		Limit(500).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		s.logger.Error("failed to list users for trip reconstruction", zap.Error(err))
+		return
+	}
+	for _, userID := range userIDs {
+		if err := s.reconstructUserTrips(ctx, userID, since); err != nil {
+			s.logger.Warn("failed to reconstruct trips",
+				zap.String("userId", userID), zap.Error(err))
+		}
+	}
+}
+
+// tripWatermark 行程重建的扫描起点
+func (s *Service) tripWatermark() time.Time {
+	s.fenceMu.Lock()
+	defer s.fenceMu.Unlock()
+	if s.tripSince.IsZero() {
+		s.tripSince = time.Now().Add(-tripLookback)
+	}
+	return s.tripSince
+}
+
+// reconstructUserTrips 切分单个用户的新增点
+func (s *Service) reconstructUserTrips(ctx context.Context, userID string, since time.Time) error {
+	// 从最近一段行程结束处继续，避免把同一批点重复切成行程
+	var last Trip
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("end_at DESC").
+		First(&last).Error
+	if err == nil && last.EndAt.After(since) {
+		since = last.EndAt
+	}
+
+	var points []*Position
+	err = s.db.WithContext(ctx).
+		Where("user_id = ? AND recorded_at > ?", userID, since).
+		Order("recorded_at ASC").
+		Limit(5000).
+		Find(&points).Error
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	segment := []*Position{points[0]}
+	for _, point := range points[1:] {
+		if point.RecordedAt.Sub(segment[len(segment)-1].RecordedAt) > tripGap {
+			s.saveTrip(ctx, userID, segment)
+			segment = nil
+		}
+		segment = append(segment, point)
+	}
+	// 尾段只有确认收尾（超过tripGap无新点）才落库
+	if len(segment) > 0 && now.Sub(segment[len(segment)-1].RecordedAt) > tripGap {
+		s.saveTrip(ctx, userID, segment)
+		s.advanceTripWatermark(segment[len(segment)-1].RecordedAt)
+	}
+	return nil
+}
+
+// advanceTripWatermark 推进扫描水位线
+func (s *Service) advanceTripWatermark(t time.Time) {
+	s.fenceMu.Lock()
+	if t.After(s.tripSince) {
+		s.tripSince = t
+	}
+	s.fenceMu.Unlock()
+}
+
+// saveTrip 把一段点序列汇总成行程落库，过短的片段丢弃
+func (s *Service) saveTrip(ctx context.Context, userID string, points []*Position) {
+	if len(points) < tripMinPoints {
+		return
+	}
+	var distance float64
+	for i := 1; i < len(points); i++ {
+		distance += haversineM(points[i-1].Lat, points[i-1].Lng, points[i].Lat, points[i].Lng)
+	}
+	if distance < tripMinDistanceM {
+		return
+	}
+	first, last := points[0], points[len(points)-1]
+	duration := last.RecordedAt.Sub(first.RecordedAt)
+	if duration <= 0 {
+		return
+	}
+	trip := &Trip{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		StartAt:     first.RecordedAt,
+		EndAt:       last.RecordedAt,
+		StartLat:    first.Lat,
+		StartLng:    first.Lng,
+		EndLat:      last.Lat,
+		EndLng:      last.Lng,
+		DistanceM:   distance,
+		DurationS:   int64(duration.Seconds()),
+		AvgSpeedKMH: distance / 1000 / duration.Hours(),
+		PointCount:  len(points),
+		Polyline:    encodePolyline(simplifyPoints(points, simplifyToleranceM)),
+	}
+	if err := s.db.WithContext(ctx).Create(trip).Error; err != nil {
+		s.logger.Warn("failed to save trip",
+			zap.String("userId", userID), zap.Error(err))
+	}
+}
+
+// simplifyPoints Douglas-Peucker抽稀
+func simplifyPoints(points []*Position, toleranceM float64) []*Position {
+	if len(points) <= 2 {
+		return points
+	}
+	maxDist, maxIdx := 0.0, 0
+	first, last := points[0], points[len(points)-1]
+	for i := 1; i < len(points)-1; i++ {
+		d := perpendicularDistanceM(points[i], first, last)
+		if d > maxDist {
+			maxDist, maxIdx = d, i
+		}
+	}
+	if maxDist <= toleranceM {
+		return []*Position{first, last}
+	}
+	left := simplifyPoints(points[:maxIdx+1], toleranceM)
+	right := simplifyPoints(points[maxIdx:], toleranceM)
+	return append(left[:len(left)-1], right...)
+}
+
+// perpendicularDistanceM 点到线段的近似垂距（米）
+//
+// 小范围内按平面几何近似，经度按纬度余弦缩放。
+func perpendicularDistanceM(p, a, b *Position) float64 {
+	scale := math.Cos(a.Lat * math.Pi / 180)
+	ax, ay := a.Lng*scale, a.Lat
+	bx, by := b.Lng*scale, b.Lat
+	px, py := p.Lng*scale, p.Lat
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return haversineM(a.Lat, a.Lng, p.Lat, p.Lng)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	nx, ny := ax+t*dx, ay+t*dy
+	return haversineM(ny, nx/scale, py, px/scale)
+}
+
+// encodePolyline Google polyline编码
+func encodePolyline(points []*Position) string {
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * 1e5))
+		lng := int64(math.Round(p.Lng * 1e5))
+		encodeSigned(&b, lat-prevLat)
+		encodeSigned(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+// encodeSigned polyline编码的单个有符号增量
+func encodeSigned(b *strings.Builder, value int64) {
+	v := value << 1
+	if value < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		b.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	b.WriteByte(byte(v + 63))
+}
@@ -0,0 +1,111 @@
+package lunar
+
+import (
+	"fmt"
+	"time"
+)
+
+// termNames 二十四节气，从小寒起
+var termNames = []string{
+	"小寒", "大寒", "立春", "雨水", "惊蛰", "春分", "清明", "谷雨",
+	"立夏", "小满", "芒种", "夏至", "小暑", "大暑", "立秋", "处暑",
+	"白露", "秋分", "寒露", "霜降", "立冬", "小雪", "大雪", "冬至",
+}
+
+// termInfo 各节气相对当年小寒基准点的分钟偏移
+var termInfo = []int64{
+	0, 21208, 42467, 63836, 85337, 107014, 128867, 150921,
+	173149, 195551, 218072, 240693, 263343, 285989, 308563, 331033,
+	353350, 375494, 397447, 419210, 440795, 462224, 483532, 504758,
+}
+
+// termBase 1900年小寒的基准时刻（东八区1900-01-06 02:05）
+var termBase = time.Date(1900, 1, 6, 2, 5, 0, 0, time.FixedZone("CST", 8*3600))
+
+// tropicalYearMs 平均回归年毫秒数
+const tropicalYearMs = 31556925974
+
+// termDate 某年第n个节气（0起）的公历日期
+func termDate(year, n int) time.Time {
+	ms := int64(year-1900)*tropicalYearMs + termInfo[n]*60*1000
+	return termBase.Add(time.Duration(ms) * time.Millisecond)
+}
+
+// SolarTermOn 当天的节气名，非节气日返回空串
+func SolarTermOn(t time.Time) string {
+	if t.Year() < MinYear || t.Year() > MaxYear {
+		return ""
+	}
+	for n := range termNames {
+		d := termDate(t.Year(), n)
+		if d.Year() == t.Year() && d.Month() == t.Month() && d.Day() == t.Day() {
+			return termNames[n]
+		}
+	}
+	return ""
+}
+
+// lunarFestivals 农历节日：月*100+日 → 名称
+var lunarFestivals = map[int]string{
+	101:  "春节",
+	115:  "元宵节",
+	202:  "龙抬头",
+	505:  "端午节",
+	707:  "七夕节",
+	715:  "中元节",
+	815:  "中秋节",
+	909:  "重阳节",
+	1208: "腊八节",
+	1223: "小年",
+}
+
+// FestivalsOn 当天的传统节日（含节气类节日与除夕）
+func FestivalsOn(t time.Time) []string {
+	var festivals []string
+	lunarDate, err := FromSolar(t)
+	if err != nil {
+		return nil
+	}
+	if !lunarDate.Leap {
+		if name, ok := lunarFestivals[lunarDate.Month*100+lunarDate.Day]; ok {
+			festivals = append(festivals, name)
+		}
+		// 除夕：腊月最后一天
+		if lunarDate.Month == 12 && lunarDate.Day == monthDays(lunarDate.Year, 12) && leapMonth(lunarDate.Year) != 12 {
+			festivals = append(festivals, "除夕")
+		}
+	}
+	// 清明既是节气也是节日
+	if term := SolarTermOn(t); term == "清明" || term == "冬至" {
+		festivals = append(festivals, term)
+	}
+	return festivals
+}
+
+// FestivalDate 一个节日在某公历年内的日期
+type FestivalDate struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+	// Lunar 农历表示，节气类节日为空
+	Lunar string `json:"lunar,omitempty"`
+}
+
+// FestivalsIn 某公历年内的全部传统节日
+func FestivalsIn(year int) ([]*FestivalDate, error) {
+	if year < MinYear || year > MaxYear {
+		return nil, fmt.Errorf("year out of supported range %d-%d", MinYear, MaxYear)
+	}
+	var results []*FestivalDate
+	day := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day.Year() == year {
+		for _, name := range FestivalsOn(day) {
+			entry := &FestivalDate{Name: name, Date: day.Format("2006-01-02")}
+			if lunarDate, err := FromSolar(day); err == nil {
+				entry.Lunar = lunarDate.String()
+			}
+			results = append(results, entry)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return results, nil
+}
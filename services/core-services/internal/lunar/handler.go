@@ -0,0 +1,87 @@
+package lunar
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 农历HTTP处理器
+//
+// 纯计算服务，无存储。
+type Handler struct{}
+
+// NewHandler 创建农历处理器
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// SetupRoutes 注册农历相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/lunar")
+	{
+		group.GET("/convert", h.Convert)
+		group.GET("/solar", h.ToSolarDate)
+		group.GET("/festivals", h.Festivals)
+	}
+}
+
+// Convert 公历转农历（date=YYYY-MM-DD，默认今天）
+func (h *Handler) Convert(c *gin.Context) {
+	day := time.Now()
+	if raw := c.Query("date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+			return
+		}
+		day = parsed
+	}
+	lunarDate, err := FromSolar(day)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"date":      day.Format("2006-01-02"),
+		"lunar":     lunarDate,
+		"display":   lunarDate.String(),
+		"solarTerm": SolarTermOn(day),
+		"festivals": FestivalsOn(day),
+	})
+}
+
+// ToSolarDate 农历转公历（year/month/day/leap）
+func (h *Handler) ToSolarDate(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	day, _ := strconv.Atoi(c.Query("day"))
+	leap := c.Query("leap") == "true"
+	solar, err := ToSolar(year, month, day, leap)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"date": solar.Format("2006-01-02")})
+}
+
+// Festivals 某公历年的传统节日列表（year默认今年）
+func (h *Handler) Festivals(c *gin.Context) {
+	year := time.Now().Year()
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		year = parsed
+	}
+	festivals, err := FestivalsIn(year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"year": year, "festivals": festivals})
+}
@@ -0,0 +1,228 @@
+package lunar
+
+import (
+	"fmt"
+	"time"
+)
+
+// 农历工具
+//
+// 公历↔农历转换基于1900-2100年的压缩月份表（每年一个17位
+// 编码：13位大小月 + 闰月序号 + 闰月大小），节气用1900年起点
+// 的平均回归年近似公式，精度对节日与排期场景足够。
+
+// 支持的年份范围
+const (
+	MinYear = 1900
+	MaxYear = 2100
+)
+
+// baseDate 农历1900年正月初一对应的公历日期
+var baseDate = time.Date(1900, 1, 31, 0, 0, 0, 0, time.UTC)
+
+// lunarInfo 1900-2100年的农历月份编码表
+var lunarInfo = [201]int{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2,
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977,
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970,
+	0x06566, 0x0d4a0, 0x0ea50, 0x06e95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950,
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557,
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5b0, 0x14573, 0x052b0, 0x0a9a8, 0x0e950, 0x06aa0,
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0,
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b6a0, 0x195a6,
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570,
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0,
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5,
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930,
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530,
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45,
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0,
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06b20, 0x1a6c4, 0x0aae0,
+	0x0a2e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4,
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0,
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160,
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252,
+	0x0d520,
+}
+
+// 干支与属相
+var (
+	heavenlyStems   = []string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+	earthlyBranches = []string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+	zodiacAnimals   = []string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+	monthNames      = []string{"正月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "冬月", "腊月"}
+	dayNames        = []string{
+		"初一", "初二", "初三", "初四", "初五", "初六", "初七", "初八", "初九", "初十",
+		"十一", "十二", "十三", "十四", "十五", "十六", "十七", "十八", "十九", "二十",
+		"廿一", "廿二", "廿三", "廿四", "廿五", "廿六", "廿七", "廿八", "廿九", "三十",
+	}
+)
+
+// Date 一个农历日期
+type Date struct {
+	Year  int  `json:"year"`
+	Month int  `json:"month"`
+	Day   int  `json:"day"`
+	Leap  bool `json:"leap"`
+	// MonthName 正月/二月/…/腊月，闰月带"闰"前缀
+	MonthName string `json:"monthName"`
+	DayName   string `json:"dayName"`
+	// GanZhi 年干支，如 甲辰
+	GanZhi string `json:"ganZhi"`
+	Zodiac string `json:"zodiac"`
+}
+
+// String 如 甲辰年正月初一
+func (d *Date) String() string {
+	return d.GanZhi + "年" + d.MonthName + d.DayName
+}
+
+// leapMonth 某年的闰月序号，无闰月返回0
+func leapMonth(year int) int {
+	return lunarInfo[year-MinYear] & 0xf
+}
+
+// leapDays 某年闰月的天数，无闰月返回0
+func leapDays(year int) int {
+	if leapMonth(year) == 0 {
+		return 0
+	}
+	if lunarInfo[year-MinYear]&0x10000 != 0 {
+		return 30
+	}
+	return 29
+}
+
+// monthDays 某年某个正常月的天数
+func monthDays(year, month int) int {
+	if lunarInfo[year-MinYear]&(0x10000>>uint(month)) != 0 {
+		return 30
+	}
+	return 29
+}
+
+// yearDays 某农历年的总天数
+func yearDays(year int) int {
+	days := 0
+	for month := 1; month <= 12; month++ {
+		days += monthDays(year, month)
+	}
+	return days + leapDays(year)
+}
+
+// FromSolar 公历转农历
+func FromSolar(t time.Time) (*Date, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := int(day.Sub(baseDate).Hours() / 24)
+	if offset < 0 || t.Year() > MaxYear {
+		return nil, fmt.Errorf("date out of supported range %d-%d", MinYear, MaxYear)
+	}
+
+	year := MinYear
+	for year <= MaxYear {
+		days := yearDays(year)
+		if offset < days {
+			break
+		}
+		offset -= days
+		year++
+	}
+	if year > MaxYear {
+		return nil, fmt.Errorf("date out of supported range %d-%d", MinYear, MaxYear)
+	}
+
+	leap := leapMonth(year)
+	month, isLeap := 1, false
+	for {
+		days := monthDays(year, month)
+		if isLeap {
+			days = leapDays(year)
+		}
+		if offset < days {
+			break
+		}
+		offset -= days
+		if !isLeap && month == leap {
+			// 正常月之后插入同号闰月
+			isLeap = true
+		} else {
+			isLeap = false
+			month++
+		}
+	}
+
+	result := &Date{
+		Year:      year,
+		Month:     month,
+		Day:       offset + 1,
+		Leap:      isLeap,
+		MonthName: monthNames[month-1],
+		DayName:   dayNames[offset],
+		GanZhi:    heavenlyStems[(year-4)%10] + earthlyBranches[(year-4)%12],
+		Zodiac:    zodiacAnimals[(year-4)%12],
+	}
+	if isLeap {
+		result.MonthName = "闰" + result.MonthName
+	}
+	return result, nil
+}
+
+// ToSolar 农历转公历
+func ToSolar(year, month, day int, leap bool) (time.Time, error) {
+	if year < MinYear || year > MaxYear {
+		return time.Time{}, fmt.Errorf("year out of supported range %d-%d", MinYear, MaxYear)
+	}
+	if month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("invalid lunar month: %d", month)
+	}
+	if leap && leapMonth(year) != month {
+		return time.Time{}, fmt.Errorf("lunar year %d has no leap month %d", year, month)
+	}
+	maxDay := monthDays(year, month)
+	if leap {
+		maxDay = leapDays(year)
+	}
+	if day < 1 || day > maxDay {
+		return time.Time{}, fmt.Errorf("invalid lunar day %d for month %d of %d", day, month, year)
+	}
+
+	offset := 0
+	for y := MinYear; y < year; y++ {
+		offset += yearDays(y)
+	}
+	lm := leapMonth(year)
+	for m := 1; m < month; m++ {
+		offset += monthDays(year, m)
+		if m == lm {
+			offset += leapDays(year)
+		}
+	}
+	if leap {
+		offset += monthDays(year, month)
+	}
+	return baseDate.AddDate(0, 0, offset+day-1), nil
+}
+
+// NextOccurrence 某个农历月日在after之后的下一次公历日期
+//
+// 跳过闰月（节日按正常月计），月末不存在的日子（如某年腊月
+// 无三十）顺延到下一年。
+func NextOccurrence(month, day int, after time.Time) (time.Time, error) {
+	lunarNow, err := FromSolar(after)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for year := lunarNow.Year; year <= MaxYear; year++ {
+		if day > monthDays(year, month) {
+			continue
+		}
+		solar, err := ToSolar(year, month, day, false)
+		if err != nil {
+			continue
+		}
+		if solar.After(time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)) {
+			return solar, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no occurrence of lunar %d-%d before %d", month, day, MaxYear)
+}
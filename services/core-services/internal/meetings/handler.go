@@ -0,0 +1,249 @@
+package meetings
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 会议排期HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建会议排期处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册会议排期相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	meetings := router.Group("/meetings")
+	{
+		meetings.PUT("/users/:userId/working-hours", h.SetWorkingHours)
+		meetings.POST("/proposals", h.Propose)
+		meetings.GET("/proposals/:proposalId", h.GetProposal)
+		meetings.POST("/proposals/:proposalId/availability", h.Availability)
+		meetings.POST("/proposals/:proposalId/finalize", h.Finalize)
+		meetings.POST("/slots/:slotId/vote", h.VoteSlot)
+		meetings.GET("/rooms/:roomId/events", h.ListRoomEvents)
+	}
+	availability := router.Group("/availability/users/:userId")
+	{
+		availability.POST("/ooo", h.AddOOO)
+		availability.GET("/ooo", h.ListOOO)
+		availability.DELETE("/ooo/:periodId", h.RemoveOOO)
+		availability.GET("/status", h.Status)
+	}
+}
+
+// AddOOO 登记OOO时段（可附私信自动回复）
+func (h *Handler) AddOOO(c *gin.Context) {
+	var req struct {
+		StartAt      time.Time `json:"startAt" binding:"required"`
+		EndAt        time.Time `json:"endAt" binding:"required"`
+		AutoResponse string    `json:"autoResponse"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.service.AddOOO(c.Request.Context(), c.Param("userId"), req.StartAt, req.EndAt, req.AutoResponse)
+	if err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, period)
+}
+
+// ListOOO 列出OOO时段
+func (h *Handler) ListOOO(c *gin.Context) {
+	periods, err := h.service.ListOOO(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"periods": periods, "count": len(periods)})
+}
+
+// RemoveOOO 删除OOO时段
+func (h *Handler) RemoveOOO(c *gin.Context) {
+	if err := h.service.RemoveOOO(c.Request.Context(), c.Param("userId"), c.Param("periodId")); err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "ooo period removed"})
+}
+
+// Status 查询用户此刻的可用状态
+//
+// 返回是否OOO、自动回复内容，以及非紧急推送是否应延迟，
+// 供私信与通知管线查询。
+func (h *Handler) Status(c *gin.Context) {
+	now := time.Now()
+	ooo, autoResponse, err := h.service.IsOOO(c.Request.Context(), c.Param("userId"), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	shouldDefer, err := h.service.ShouldDeferNotification(c.Request.Context(), c.Param("userId"), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ooo":                ooo,
+		"autoResponse":       autoResponse,
+		"deferNotifications": shouldDefer,
+	})
+}
+
+// SetWorkingHours 申报工作时间
+func (h *Handler) SetWorkingHours(c *gin.Context) {
+	var req struct {
+		Timezone  string `json:"timezone" binding:"required"`
+		StartHour int    `json:"startHour"`
+		EndHour   int    `json:"endHour" binding:"required"`
+		Workdays  string `json:"workdays"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hours := &WorkingHours{
+		UserID:    c.Param("userId"),
+		Timezone:  req.Timezone,
+		StartHour: req.StartHour,
+		EndHour:   req.EndHour,
+		Workdays:  req.Workdays,
+	}
+	if hours.Workdays == "" {
+		hours.Workdays = "1,2,3,4,5"
+	}
+	if err := h.service.SetWorkingHours(c.Request.Context(), hours); err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, hours)
+}
+
+// Propose 发起会议提案
+func (h *Handler) Propose(c *gin.Context) {
+	var req struct {
+		RoomID    string `json:"roomId" binding:"required"`
+		Title     string `json:"title" binding:"required"`
+		CreatedBy string `json:"createdBy" binding:"required"`
+		Slots     []struct {
+			StartAt time.Time `json:"startAt" binding:"required"`
+			EndAt   time.Time `json:"endAt" binding:"required"`
+		} `json:"slots" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	windows := make([][2]time.Time, 0, len(req.Slots))
+	for _, slot := range req.Slots {
+		windows = append(windows, [2]time.Time{slot.StartAt, slot.EndAt})
+	}
+	proposal, err := h.service.Propose(c.Request.Context(), req.RoomID, req.Title, req.CreatedBy, windows)
+	if err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// GetProposal 获取提案
+func (h *Handler) GetProposal(c *gin.Context) {
+	proposal, err := h.service.GetProposal(c.Request.Context(), c.Param("proposalId"))
+	if err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, proposal)
+}
+
+// VoteSlot 为时段投票
+func (h *Handler) VoteSlot(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.VoteSlot(c.Request.Context(), c.Param("slotId"), req.UserID); err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "vote recorded"})
+}
+
+// Availability 计算成员对各时段的可用性
+func (h *Handler) Availability(c *gin.Context) {
+	var req struct {
+		UserIDs []string `json:"userIds" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.service.Availability(c.Request.Context(), c.Param("proposalId"), req.UserIDs)
+	if err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"slots": results})
+}
+
+// Finalize 敲定提案，slotId可选，缺省取票数最多的时段
+func (h *Handler) Finalize(c *gin.Context) {
+	var req struct {
+		SlotID string `json:"slotId"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := h.service.Finalize(c.Request.Context(), c.Param("proposalId"), req.SlotID)
+	if err != nil {
+		c.JSON(statusForMeetingError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, event)
+}
+
+// ListRoomEvents 列出房间的日历事件
+func (h *Handler) ListRoomEvents(c *gin.Context) {
+	events, err := h.service.ListRoomEvents(c.Request.Context(), c.Param("roomId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events, "count": len(events)})
+}
+
+// statusForMeetingError 把服务层错误映射为HTTP状态码
+func statusForMeetingError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "voting closed"), strings.Contains(message, "cannot finalize"):
+		return http.StatusConflict
+	case strings.Contains(message, "invalid"), strings.Contains(message, "must be"),
+		strings.Contains(message, "required"), strings.Contains(message, "does not belong"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,118 @@
+package meetings
+
+import (
+	"time"
+)
+
+// 提案状态
+const (
+	ProposalOpen      = "open"
+	ProposalFinalized = "finalized"
+	ProposalCancelled = "cancelled"
+)
+
+// WorkingHours 用户申报的工作时间
+//
+// 时间按用户自己的时区表达，可用性计算时换算到会议时段所在时刻。
+type WorkingHours struct {
+	UserID string `json:"userId" gorm:"primaryKey;type:varchar(36)"`
+	// Timezone IANA时区名，如 Asia/Shanghai
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null"`
+	// StartHour/EndHour 每个工作日的起止小时（0-24）
+	StartHour int `json:"startHour" gorm:"not null"`
+	EndHour   int `json:"endHour" gorm:"not null"`
+	// Workdays 工作日列表，0=周日...6=周六，逗号分隔，如 "1,2,3,4,5"
+	Workdays  string    `json:"workdays" gorm:"type:varchar(16);default:'1,2,3,4,5'"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (WorkingHours) TableName() string {
+	return "cs_working_hours"
+}
+
+// Proposal 会议时间提案
+type Proposal struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RoomID    string    `json:"roomId" gorm:"index;type:varchar(36);not null"`
+	Title     string    `json:"title" gorm:"not null"`
+	CreatedBy string    `json:"createdBy" gorm:"type:varchar(36)"`
+	Status    string    `json:"status" gorm:"type:varchar(16);default:'open'"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Slots []Slot `json:"slots,omitempty" gorm:"foreignKey:ProposalID"`
+}
+
+// TableName 指定表名
+func (Proposal) TableName() string {
+	return "cs_meeting_proposals"
+}
+
+// Slot 候选时段（UTC存储）
+type Slot struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ProposalID string    `json:"proposalId" gorm:"index;type:varchar(36);not null"`
+	StartAt    time.Time `json:"startAt" gorm:"not null"`
+	EndAt      time.Time `json:"endAt" gorm:"not null"`
+
+	Votes []Vote `json:"votes,omitempty" gorm:"foreignKey:SlotID"`
+}
+
+// TableName 指定表名
+func (Slot) TableName() string {
+	return "cs_meeting_slots"
+}
+
+// Vote 时段投票
+type Vote struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	SlotID    string    `json:"slotId" gorm:"uniqueIndex:idx_meeting_vote;type:varchar(36);not null"`
+	UserID    string    `json:"userId" gorm:"uniqueIndex:idx_meeting_vote;type:varchar(36);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Vote) TableName() string {
+	return "cs_meeting_votes"
+}
+
+// CalendarEvent 敲定会议后生成的日历事件
+type CalendarEvent struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RoomID     string    `json:"roomId" gorm:"index;type:varchar(36);not null"`
+	ProposalID string    `json:"proposalId" gorm:"type:varchar(36)"`
+	Title      string    `json:"title" gorm:"not null"`
+	StartAt    time.Time `json:"startAt" gorm:"index;not null"`
+	EndAt      time.Time `json:"endAt" gorm:"not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (CalendarEvent) TableName() string {
+	return "cs_calendar_events"
+}
+
+// Reminder 会议提醒
+type Reminder struct {
+	ID       uint      `json:"-" gorm:"primaryKey"`
+	EventID  string    `json:"eventId" gorm:"index;type:varchar(36);not null"`
+	RemindAt time.Time `json:"remindAt" gorm:"index;not null"`
+	Sent     bool      `json:"sent" gorm:"default:false"`
+}
+
+// TableName 指定表名
+func (Reminder) TableName() string {
+	return "cs_meeting_reminders"
+}
+
+// SlotAvailability 单个时段的可用性结果
+type SlotAvailability struct {
+	SlotID      string   `json:"slotId"`
+	StartAt     string   `json:"startAt"`
+	EndAt       string   `json:"endAt"`
+	Available   []string `json:"available"`
+	Unavailable []string `json:"unavailable"`
+	// Undeclared 未申报工作时间、无法判断的成员
+	Undeclared []string `json:"undeclared"`
+}
@@ -0,0 +1,111 @@
+package meetings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OOOPeriod 请假/不在办公室时段
+type OOOPeriod struct {
+	ID      string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID  string    `json:"userId" gorm:"index;type:varchar(36);not null"`
+	StartAt time.Time `json:"startAt" gorm:"not null"`
+	EndAt   time.Time `json:"endAt" gorm:"not null"`
+	// AutoResponse OOO期间私信的自动回复内容，可为空
+	AutoResponse string    `json:"autoResponse,omitempty" gorm:"type:text"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (OOOPeriod) TableName() string {
+	return "cs_ooo_periods"
+}
+
+// AddOOO 登记OOO时段
+func (s *Service) AddOOO(ctx context.Context, userID string, startAt, endAt time.Time, autoResponse string) (*OOOPeriod, error) {
+	if !startAt.Before(endAt) {
+		return nil, fmt.Errorf("ooo start must be before end")
+	}
+
+	period := &OOOPeriod{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		StartAt:      startAt.UTC(),
+		EndAt:        endAt.UTC(),
+		AutoResponse: autoResponse,
+	}
+	if err := s.db.WithContext(ctx).Create(period).Error; err != nil {
+		return nil, fmt.Errorf("failed to create ooo period: %w", err)
+	}
+	return period, nil
+}
+
+// RemoveOOO 删除OOO时段
+func (s *Service) RemoveOOO(ctx context.Context, userID, periodID string) error {
+	result := s.db.WithContext(ctx).Delete(&OOOPeriod{}, "id = ? AND user_id = ?", periodID, userID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove ooo period: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("ooo period not found: %s", periodID)
+	}
+	return nil
+}
+
+// ListOOO 列出用户未结束的OOO时段
+func (s *Service) ListOOO(ctx context.Context, userID string) ([]*OOOPeriod, error) {
+	var periods []*OOOPeriod
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND end_at >= ?", userID, time.Now()).
+		Order("start_at ASC").
+		Find(&periods).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ooo periods: %w", err)
+	}
+	return periods, nil
+}
+
+// IsOOO 判断用户此刻是否OOO，是则返回自动回复内容
+func (s *Service) IsOOO(ctx context.Context, userID string, at time.Time) (bool, string, error) {
+	var period OOOPeriod
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND start_at <= ? AND end_at > ?", userID, at, at).
+		Order("start_at DESC").
+		First(&period).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("failed to check ooo: %w", err)
+	}
+	return true, period.AutoResponse, nil
+}
+
+// ShouldDeferNotification 判断非紧急推送是否应延迟
+//
+// 用户OOO或当前不在其申报的工作时间内时返回true；
+// 未申报工作时间的用户不延迟。
+func (s *Service) ShouldDeferNotification(ctx context.Context, userID string, at time.Time) (bool, error) {
+	ooo, _, err := s.IsOOO(ctx, userID, at)
+	if err != nil {
+		return false, err
+	}
+	if ooo {
+		return true, nil
+	}
+
+	var hours WorkingHours
+	err = s.db.WithContext(ctx).First(&hours, "user_id = ?", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load working hours: %w", err)
+	}
+	// 用单点时刻构造零长度时段复用可用性判断
+	return !isAvailable(&hours, at, at), nil
+}
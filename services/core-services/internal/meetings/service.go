@@ -0,0 +1,338 @@
+package meetings
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// reminderLead 会议开始前多久发送提醒
+const reminderLead = 15 * time.Minute
+
+// Service 会议排期服务
+type Service struct {
+	db       *gorm.DB
+	hub      *realtime.Hub // 可为nil，提醒仅落库不广播
+	logger   *zap.Logger
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建会议排期服务
+func NewService(db *gorm.DB, hub *realtime.Hub, logger *zap.Logger) (*Service, error) {
+	err := db.AutoMigrate(&WorkingHours{}, &Proposal{}, &Slot{}, &Vote{}, &CalendarEvent{}, &Reminder{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate meeting tables: %w", err)
+	}
+	return &Service{
+		db:     db,
+		hub:    hub,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 启动提醒循环
+func (s *Service) Start() {
+	go s.reminderLoop()
+}
+
+// Stop 停止提醒循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// SetWorkingHours 申报或更新工作时间
+func (s *Service) SetWorkingHours(ctx context.Context, hours *WorkingHours) error {
+	if _, err := time.LoadLocation(hours.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", hours.Timezone, err)
+	}
+	if hours.StartHour < 0 || hours.EndHour > 24 || hours.StartHour >= hours.EndHour {
+		return fmt.Errorf("invalid working hours: %d-%d", hours.StartHour, hours.EndHour)
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(hours).Error
+	if err != nil {
+		return fmt.Errorf("failed to save working hours: %w", err)
+	}
+	return nil
+}
+
+// Propose 在房间内发起会议提案
+func (s *Service) Propose(ctx context.Context, roomID, title, createdBy string, slots [][2]time.Time) (*Proposal, error) {
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("at least one slot is required")
+	}
+
+	proposal := &Proposal{
+		ID:        uuid.NewString(),
+		RoomID:    roomID,
+		Title:     title,
+		CreatedBy: createdBy,
+		Status:    ProposalOpen,
+	}
+	for _, window := range slots {
+		if !window[0].Before(window[1]) {
+			return nil, fmt.Errorf("slot start must be before end")
+		}
+		proposal.Slots = append(proposal.Slots, Slot{
+			ID:         uuid.NewString(),
+			ProposalID: proposal.ID,
+			StartAt:    window[0].UTC(),
+			EndAt:      window[1].UTC(),
+		})
+	}
+	if err := s.db.WithContext(ctx).Create(proposal).Error; err != nil {
+		return nil, fmt.Errorf("failed to create proposal: %w", err)
+	}
+	return proposal, nil
+}
+
+// GetProposal 获取提案（含时段与投票）
+func (s *Service) GetProposal(ctx context.Context, id string) (*Proposal, error) {
+	var proposal Proposal
+	err := s.db.WithContext(ctx).
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("start_at ASC") }).
+		Preload("Slots.Votes").
+		First(&proposal, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("proposal not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get proposal: %w", err)
+	}
+	return &proposal, nil
+}
+
+// VoteSlot 为候选时段投票
+func (s *Service) VoteSlot(ctx context.Context, slotID, userID string) error {
+	var slot Slot
+	if err := s.db.WithContext(ctx).First(&slot, "id = ?", slotID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("slot not found: %s", slotID)
+		}
+		return fmt.Errorf("failed to get slot: %w", err)
+	}
+
+	var proposal Proposal
+	if err := s.db.WithContext(ctx).First(&proposal, "id = ?", slot.ProposalID).Error; err != nil {
+		return fmt.Errorf("failed to get proposal: %w", err)
+	}
+	if proposal.Status != ProposalOpen {
+		return fmt.Errorf("proposal is %s, voting closed", proposal.Status)
+	}
+
+	vote := &Vote{SlotID: slotID, UserID: userID}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(vote).Error
+	if err != nil {
+		return fmt.Errorf("failed to record vote: %w", err)
+	}
+	return nil
+}
+
+// Finalize 敲定提案
+//
+// slotID为空时选票数最多的时段；生成日历事件与会前提醒，
+// 并把提案标记为finalized。
+func (s *Service) Finalize(ctx context.Context, proposalID, slotID string) (*CalendarEvent, error) {
+	proposal, err := s.GetProposal(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != ProposalOpen {
+		return nil, fmt.Errorf("proposal is %s, cannot finalize", proposal.Status)
+	}
+
+	var chosen *Slot
+	if slotID != "" {
+		for i := range proposal.Slots {
+			if proposal.Slots[i].ID == slotID {
+				chosen = &proposal.Slots[i]
+				break
+			}
+		}
+		if chosen == nil {
+			return nil, fmt.Errorf("slot %s does not belong to proposal %s", slotID, proposalID)
+		}
+	} else {
+		for i := range proposal.Slots {
+			if chosen == nil || len(proposal.Slots[i].Votes) > len(chosen.Votes) {
+				chosen = &proposal.Slots[i]
+			}
+		}
+	}
+
+	event := &CalendarEvent{
+		ID:         uuid.NewString(),
+		RoomID:     proposal.RoomID,
+		ProposalID: proposal.ID,
+		Title:      proposal.Title,
+		StartAt:    chosen.StartAt,
+		EndAt:      chosen.EndAt,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to create calendar event: %w", err)
+		}
+		reminder := &Reminder{EventID: event.ID, RemindAt: chosen.StartAt.Add(-reminderLead)}
+		if err := tx.Create(reminder).Error; err != nil {
+			return fmt.Errorf("failed to create reminder: %w", err)
+		}
+		return tx.Model(&Proposal{}).Where("id = ?", proposal.ID).
+			Update("status", ProposalFinalized).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Availability 计算成员对提案各时段的可用性
+//
+// 按成员申报的工作时间与时区判断：时段在其当地时间的
+// 工作日工作时段内视为可用。
+func (s *Service) Availability(ctx context.Context, proposalID string, userIDs []string) ([]*SlotAvailability, error) {
+	proposal, err := s.GetProposal(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var declared []*WorkingHours
+	err = s.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&declared).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load working hours: %w", err)
+	}
+	hoursByUser := make(map[string]*WorkingHours, len(declared))
+	for _, hours := range declared {
+		hoursByUser[hours.UserID] = hours
+	}
+
+	results := make([]*SlotAvailability, 0, len(proposal.Slots))
+	for _, slot := range proposal.Slots {
+		result := &SlotAvailability{
+			SlotID:  slot.ID,
+			StartAt: slot.StartAt.Format(time.RFC3339),
+			EndAt:   slot.EndAt.Format(time.RFC3339),
+		}
+		for _, userID := range userIDs {
+			hours, ok := hoursByUser[userID]
+			if !ok {
+				result.Undeclared = append(result.Undeclared, userID)
+				continue
+			}
+			if isAvailable(hours, slot.StartAt, slot.EndAt) {
+				result.Available = append(result.Available, userID)
+			} else {
+				result.Unavailable = append(result.Unavailable, userID)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ListRoomEvents 列出房间即将开始的日历事件
+func (s *Service) ListRoomEvents(ctx context.Context, roomID string) ([]*CalendarEvent, error) {
+	var events []*CalendarEvent
+	err := s.db.WithContext(ctx).
+		Where("room_id = ? AND end_at >= ?", roomID, time.Now()).
+		Order("start_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+// isAvailable 判断时段是否完全落在用户当地的工作时间内
+func isAvailable(hours *WorkingHours, start, end time.Time) bool {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return false
+	}
+	localStart := start.In(location)
+	localEnd := end.In(location)
+
+	// 跨天的时段不视为可用
+	if localStart.YearDay() != localEnd.YearDay() || localStart.Year() != localEnd.Year() {
+		return false
+	}
+	if !workdayIncluded(hours.Workdays, int(localStart.Weekday())) {
+		return false
+	}
+
+	startMinutes := localStart.Hour()*60 + localStart.Minute()
+	endMinutes := localEnd.Hour()*60 + localEnd.Minute()
+	return startMinutes >= hours.StartHour*60 && endMinutes <= hours.EndHour*60
+}
+
+// workdayIncluded 判断星期几是否在工作日列表内
+func workdayIncluded(workdays string, weekday int) bool {
+	for _, part := range strings.Split(workdays, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// reminderLoop 每分钟发送到期的会议提醒
+func (s *Service) reminderLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.sendDueReminders(context.Background())
+		}
+	}
+}
+
+// sendDueReminders 广播到期提醒并标记已发送
+func (s *Service) sendDueReminders(ctx context.Context) {
+	var due []*Reminder
+	err := s.db.WithContext(ctx).
+		Where("sent = ? AND remind_at <= ?", false, time.Now()).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due reminders", zap.Error(err))
+		return
+	}
+
+	for _, reminder := range due {
+		var event CalendarEvent
+		if err := s.db.WithContext(ctx).First(&event, "id = ?", reminder.EventID).Error; err != nil {
+			continue
+		}
+		if s.hub != nil {
+			s.hub.Broadcast(event.RoomID, &realtime.Message{
+				Type: "meeting.reminder",
+				Payload: []byte(fmt.Sprintf(`{"eventId":%q,"title":%q,"startAt":%q}`,
+					event.ID, event.Title, event.StartAt.Format(time.RFC3339))),
+			})
+		}
+		err := s.db.WithContext(ctx).Model(&Reminder{}).
+			Where("id = ?", reminder.ID).Update("sent", true).Error
+		if err != nil {
+			s.logger.Warn("failed to mark reminder sent", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 全局标签取值保持各模块一致：route使用gin路由模板而非实际路径，
+// provider为AI服务商标识（如moderation），kind为prompt/completion。
+
+// Registry 核心服务的Prometheus注册表
+type Registry struct {
+	registry *prometheus.Registry
+
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	aiDuration      *prometheus.HistogramVec
+	aiTokensTotal   *prometheus.CounterVec
+
+	// 供实时推送使用的轻量计数
+	requestCount int64 // atomic
+	aiInFlight   int64 // atomic
+
+	rateMu        sync.Mutex
+	lastRateCount int64
+	lastRateTime  time.Time
+}
+
+// NewRegistry 创建注册表并注册进程/Go运行时采集器
+func NewRegistry() *Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	r := &Registry{
+		registry: registry,
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "core_http_requests_total",
+			Help: "Total HTTP requests by route, method and status",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "core_http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		aiDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "core_ai_request_duration_seconds",
+			Help:    "AI provider request latency",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"provider", "status"}),
+		aiTokensTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "core_ai_tokens_total",
+			Help: "AI tokens consumed by provider and kind",
+		}, []string{"provider", "kind"}),
+	}
+	registry.MustRegister(r.requestsTotal, r.requestDuration, r.aiDuration, r.aiTokensTotal)
+	return r
+}
+
+// RegisterDBStats 注册数据库连接池指标
+func (r *Registry) RegisterDBStats(db *sql.DB) {
+	r.registry.MustRegister(collectors.NewDBStatsCollector(db, "core"))
+}
+
+// Middleware 返回记录RED指标的gin中间件
+func (r *Registry) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		r.requestsTotal.WithLabelValues(route, method, strconv.Itoa(c.Writer.Status())).Inc()
+		r.requestDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+		atomic.AddInt64(&r.requestCount, 1)
+	}
+}
+
+// Handler 返回/metrics端点处理器
+func (r *Registry) Handler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+	return func(c *gin.Context) {
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// AIRequestStarted 标记一次AI服务商调用开始
+func (r *Registry) AIRequestStarted(provider string) {
+	atomic.AddInt64(&r.aiInFlight, 1)
+}
+
+// ObserveAIRequest 记录一次AI服务商调用结束
+func (r *Registry) ObserveAIRequest(provider string, duration time.Duration, err error) {
+	atomic.AddInt64(&r.aiInFlight, -1)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	r.aiDuration.WithLabelValues(provider, status).Observe(duration.Seconds())
+}
+
+// AIInFlight 当前进行中的AI调用数
+func (r *Registry) AIInFlight() float64 {
+	return float64(atomic.LoadInt64(&r.aiInFlight))
+}
+
+// RequestRate 自上次调用以来的每秒请求数
+func (r *Registry) RequestRate() float64 {
+	r.rateMu.Lock()
+	defer r.rateMu.Unlock()
+
+	now := time.Now()
+	count := atomic.LoadInt64(&r.requestCount)
+	if r.lastRateTime.IsZero() {
+		r.lastRateCount = count
+		r.lastRateTime = now
+		return 0
+	}
+
+	elapsed := now.Sub(r.lastRateTime).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	rate := float64(count-r.lastRateCount) / elapsed
+	r.lastRateCount = count
+	r.lastRateTime = now
+	return rate
+}
+
+// AddAITokens 累加AI token消耗，kind为prompt或completion
+func (r *Registry) AddAITokens(provider, kind string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	r.aiTokensTotal.WithLabelValues(provider, kind).Add(float64(tokens))
+}
@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// streamInterval 采样与推送间隔，作为服务端限流避免压垮客户端
+const streamInterval = 2 * time.Second
+
+// backfillPoints 新连接回填的历史点数
+const backfillPoints = 60
+
+// SampleFunc 返回某个实时指标的当前值
+type SampleFunc func() float64
+
+// Point 单个采样点
+type Point struct {
+	Series    string  `json:"series"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"` // Unix秒
+}
+
+// Stream 面向管理后台的实时指标推送
+//
+// 每个指标系列对应hub中的一个房间（metrics:<series>），客户端按需
+// 订阅系列即实现过滤；连接建立时回填最近backfillPoints个点。
+type Stream struct {
+	hub *realtime.Hub
+
+	mu      sync.RWMutex
+	sources map[string]SampleFunc
+	history map[string][]Point // 每系列的环形历史
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewStream 创建实时指标推送器
+func NewStream(hub *realtime.Hub) *Stream {
+	return &Stream{
+		hub:     hub,
+		sources: make(map[string]SampleFunc),
+		history: make(map[string][]Point),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// RegisterSource 注册一个指标系列
+func (s *Stream) RegisterSource(name string, fn SampleFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources[name] = fn
+}
+
+// Series 列出可订阅的系列名
+func (s *Stream) Series() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start 启动采样循环
+func (s *Stream) Start() {
+	go s.sampleLoop()
+}
+
+// Stop 停止采样循环
+func (s *Stream) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// sampleLoop 定期采样、入历史并广播
+func (s *Stream) sampleLoop() {
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.sampleOnce()
+		}
+	}
+}
+
+// sampleOnce 对全部系列采样一次
+func (s *Stream) sampleOnce() {
+	s.mu.Lock()
+	now := time.Now().Unix()
+	points := make([]Point, 0, len(s.sources))
+	for name, fn := range s.sources {
+		point := Point{Series: name, Value: fn(), Timestamp: now}
+		history := append(s.history[name], point)
+		if len(history) > backfillPoints {
+			history = history[len(history)-backfillPoints:]
+		}
+		s.history[name] = history
+		points = append(points, point)
+	}
+	s.mu.Unlock()
+
+	for _, point := range points {
+		payload, err := json.Marshal(point)
+		if err != nil {
+			continue
+		}
+		s.hub.Broadcast("metrics:"+point.Series, &realtime.Message{
+			Type:    "metrics.sample",
+			Room:    "metrics:" + point.Series,
+			Payload: payload,
+		})
+	}
+}
+
+// SetupRoutes 注册实时指标相关路由
+func (s *Stream) SetupRoutes(router gin.IRouter) {
+	router.GET("/metrics/series", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"series": s.Series()})
+	})
+	router.GET("/metrics/stream/:series", s.serveStream)
+}
+
+// serveStream 升级WebSocket并回填历史点
+func (s *Stream) serveStream(c *gin.Context) {
+	series := c.Param("series")
+	s.mu.RLock()
+	_, known := s.sources[series]
+	backfill := append([]Point(nil), s.history[series]...)
+	s.mu.RUnlock()
+	if !known {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown series: " + series})
+		return
+	}
+
+	userID := c.Query("user_id")
+	room := "metrics:" + series
+	s.hub.ServeWS(c, room, userID)
+
+	// 回填最近的历史点，让面板无需等待首个采样周期
+	for _, point := range backfill {
+		payload, err := json.Marshal(point)
+		if err != nil {
+			continue
+		}
+		s.hub.SendToUser(room, userID, &realtime.Message{
+			Type:    "metrics.backfill",
+			Room:    room,
+			Payload: payload,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package negotiate
+
+import (
+	"bytes"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// 协商支持的二进制媒体类型
+const (
+	TypeMsgpack  = "application/x-msgpack"
+	TypeProtobuf = "application/x-protobuf"
+)
+
+// Encoder 把JSON值域的通用结构编码为目标格式
+//
+// 共享的序列化基座：各端点照常产出JSON，中间件按Accept头
+// 解码后走这里重编码，端点代码无须感知格式。
+type Encoder interface {
+	ContentType() string
+	Encode(value interface{}) ([]byte, error)
+}
+
+// msgpackEncoder MessagePack编码器
+type msgpackEncoder struct{}
+
+// ContentType 媒体类型
+func (msgpackEncoder) ContentType() string {
+	return TypeMsgpack
+}
+
+// Encode 编码为MessagePack
+func (msgpackEncoder) Encode(value interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := appendMsgpack(&b, value); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// protobufEncoder google.protobuf.Struct编码器
+//
+// 响应无静态schema，编码为Struct/Value通用消息；客户端用
+// 任意protobuf运行时的well-known types解码。
+type protobufEncoder struct{}
+
+// ContentType 媒体类型
+func (protobufEncoder) ContentType() string {
+	return TypeProtobuf
+}
+
+// Encode 编码为protobuf Struct
+func (protobufEncoder) Encode(value interface{}) ([]byte, error) {
+	converted, err := structpb.NewValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	return proto.Marshal(converted)
+}
+
+// encoders Accept媒体类型 → 编码器
+var encoders = map[string]Encoder{
+	TypeMsgpack:  msgpackEncoder{},
+	TypeProtobuf: protobufEncoder{},
+}
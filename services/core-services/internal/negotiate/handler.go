@@ -0,0 +1,42 @@
+package negotiate
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 内容协商统计HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建内容协商处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册内容协商路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/negotiate/stats", h.Stats)
+}
+
+// Stats 逐格式的响应数与字节节省统计
+func (h *Handler) Stats(c *gin.Context) {
+	stats := h.service.Stats()
+	type savings struct {
+		*formatStats
+		SavedBytes int64   `json:"savedBytes"`
+		SavedRatio float64 `json:"savedRatio"`
+	}
+	result := make(map[string]*savings, len(stats))
+	for format, stat := range stats {
+		entry := &savings{formatStats: stat}
+		entry.SavedBytes = stat.JSONBytes - stat.EncodedBytes
+		if stat.JSONBytes > 0 {
+			entry.SavedRatio = float64(entry.SavedBytes) / float64(stat.JSONBytes)
+		}
+		result[format] = entry
+	}
+	c.JSON(http.StatusOK, gin.H{"formats": result})
+}
@@ -0,0 +1,121 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// appendMsgpack 编码json.Unmarshal产出的通用值
+//
+// 只需覆盖JSON值域（nil/bool/float64/string/数组/对象），
+// 无须引入完整的msgpack依赖。
+func appendMsgpack(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if v {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case float64:
+		// 整数值走定长整型编码，移动端解码更省
+		if v == math.Trunc(v) && v >= math.MinInt64 && v <= math.MaxInt64 {
+			appendMsgpackInt(b, int64(v))
+			return nil
+		}
+		b.WriteByte(0xcb)
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+		b.Write(buf[:])
+	case string:
+		appendMsgpackString(b, v)
+	case []interface{}:
+		appendMsgpackLen(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := appendMsgpack(b, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		appendMsgpackLen(b, len(v), 0x80, 0xde, 0xdf)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			appendMsgpackString(b, key)
+			if err := appendMsgpack(b, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", value)
+	}
+	return nil
+}
+
+// appendMsgpackInt 定长整型编码
+func appendMsgpackInt(b *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0 && v <= 127:
+		b.WriteByte(byte(v))
+	case v < 0 && v >= -32:
+		b.WriteByte(byte(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		b.WriteByte(0xd2)
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(int32(v)))
+		b.Write(buf[:])
+	default:
+		b.WriteByte(0xd3)
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		b.Write(buf[:])
+	}
+}
+
+// appendMsgpackString 字符串编码
+func appendMsgpackString(b *bytes.Buffer, v string) {
+	switch {
+	case len(v) < 32:
+		b.WriteByte(0xa0 | byte(len(v)))
+	case len(v) <= math.MaxUint8:
+		b.WriteByte(0xd9)
+		b.WriteByte(byte(len(v)))
+	case len(v) <= math.MaxUint16:
+		b.WriteByte(0xda)
+		var buf [2]byte
+		binary.BigEndian.PutUint16(buf[:], uint16(len(v)))
+		b.Write(buf[:])
+	default:
+		b.WriteByte(0xdb)
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(len(v)))
+		b.Write(buf[:])
+	}
+	b.WriteString(v)
+}
+
+// appendMsgpackLen 数组/映射的长度前缀
+func appendMsgpackLen(b *bytes.Buffer, length int, fixPrefix byte, tag16, tag32 byte) {
+	switch {
+	case length < 16:
+		b.WriteByte(fixPrefix | byte(length))
+	case length <= math.MaxUint16:
+		b.WriteByte(tag16)
+		var buf [2]byte
+		binary.BigEndian.PutUint16(buf[:], uint16(length))
+		b.Write(buf[:])
+	default:
+		b.WriteByte(tag32)
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(length))
+		b.Write(buf[:])
+	}
+}
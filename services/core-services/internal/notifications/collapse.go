@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// collapseWindows 各类型的折叠窗口，窗口内同折叠键的通知原地聚合
+//
+// 不在表里的类型不折叠。窗口从聚合通知最近一次更新算起，
+// 持续的高频事件会不断滑动窗口。
+var collapseWindows = map[string]time.Duration{
+	TypeLike:         10 * time.Minute,
+	TypeCommentReply: 5 * time.Minute,
+}
+
+// collapseMaxActors 聚合通知保留的最近行为者数量
+const collapseMaxActors = 5
+
+// aggregateTitle 聚合通知的标题格式
+func aggregateTitle(notifType string, count int) string {
+	switch notifType {
+	case TypeLike:
+		return fmt.Sprintf("%d人赞了你的帖子", count)
+	case TypeCommentReply:
+		return fmt.Sprintf("你的讨论串有%d条新回复", count)
+	default:
+		return fmt.Sprintf("%d条新通知", count)
+	}
+}
+
+// storeOrCollapse 落库站内通知，可折叠类型在窗口内原地聚合
+//
+// 返回是否命中了已有聚合通知：命中时email/push不再重复投递，
+// 客户端靠站内通知的原地更新感知进展。
+func (s *Service) storeOrCollapse(ctx context.Context, userID string, msg *Message) (bool, error) {
+	window, collapsible := collapseWindows[msg.Type]
+	if !collapsible || msg.CollapseKey == "" {
+		return false, s.createNotification(ctx, userID, msg, 1, actorList(nil, msg.Actor))
+	}
+
+	var existing Notification
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND collapse_key = ? AND updated_at >= ?",
+			userID, msg.CollapseKey, time.Now().Add(-window)).
+		Order("updated_at DESC").
+		First(&existing).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return false, err
+		}
+		return false, s.createNotification(ctx, userID, msg, 1, actorList(nil, msg.Actor))
+	}
+
+	var actors []string
+	if len(existing.Actors) > 0 {
+		_ = json.Unmarshal(existing.Actors, &actors)
+	}
+	actors = actorList(actors, msg.Actor)
+	raw, err := json.Marshal(actors)
+	if err != nil {
+		return false, err
+	}
+	count := existing.Count + 1
+	err = s.db.WithContext(ctx).Model(&Notification{}).
+		Where("id = ?", existing.ID).
+		Updates(map[string]interface{}{
+			"count":  count,
+			"actors": raw,
+			"title":  aggregateTitle(msg.Type, count),
+			// 聚合后的新动态重新置为未读
+			"read":       false,
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createNotification 新建一条站内通知
+func (s *Service) createNotification(ctx context.Context, userID string, msg *Message, count int, actors []string) error {
+	notification := &Notification{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		Type:        msg.Type,
+		Scope:       msg.Scope,
+		Title:       msg.Title,
+		Body:        msg.Body,
+		CollapseKey: msg.CollapseKey,
+		Count:       count,
+	}
+	if len(actors) > 0 {
+		raw, err := json.Marshal(actors)
+		if err != nil {
+			return err
+		}
+		notification.Actors = raw
+	}
+	return s.db.WithContext(ctx).Create(notification).Error
+}
+
+// actorList 把行为者加入列表，去重并只保留最近的几个
+func actorList(actors []string, actor string) []string {
+	if actor == "" {
+		return actors
+	}
+	filtered := make([]string, 0, len(actors)+1)
+	for _, existing := range actors {
+		if existing != actor {
+			filtered = append(filtered, existing)
+		}
+	}
+	filtered = append(filtered, actor)
+	if len(filtered) > collapseMaxActors {
+		filtered = filtered[len(filtered)-collapseMaxActors:]
+	}
+	return filtered
+}
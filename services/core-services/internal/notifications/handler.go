@@ -0,0 +1,234 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 通知HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建通知处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册通知相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/notifications", h.List)
+	router.POST("/notifications/:notificationId/read", h.MarkRead)
+	router.GET("/notifications/preferences", h.ListPreferences)
+	router.PUT("/notifications/preferences", h.SetPreference)
+	router.GET("/notifications/preferences/resolve", h.Resolve)
+	router.GET("/notifications/settings", h.GetSetting)
+	router.PUT("/notifications/settings", h.UpdateSetting)
+	router.GET("/notifications/mutes", h.ListMutes)
+	router.POST("/notifications/mutes", h.AddMute)
+	router.DELETE("/notifications/mutes", h.RemoveMute)
+}
+
+// requireUser 从请求头取当前用户
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// List 站内通知列表
+func (h *Handler) List(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	unreadOnly := c.Query("unread") == "true"
+	items, err := h.service.ListNotifications(c.Request.Context(), userID, unreadOnly, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": items})
+}
+
+// MarkRead 标记通知已读
+func (h *Handler) MarkRead(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.MarkRead(c.Request.Context(), userID, c.Param("notificationId")); err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked read"})
+}
+
+// ListPreferences 完整偏好矩阵
+func (h *Handler) ListPreferences(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	prefs, err := h.service.ListPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+// SetPreference 设置单个类型×渠道开关
+func (h *Handler) SetPreference(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Type    string `json:"type" binding:"required"`
+		Channel string `json:"channel" binding:"required"`
+		Enabled *bool  `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pref, err := h.service.SetPreference(c.Request.Context(), userID, req.Type, req.Channel, *req.Enabled)
+	if err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// Resolve 调试接口：返回某类型某来源的最终生效矩阵
+func (h *Handler) Resolve(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	notifType := c.Query("type")
+	if notifType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type is required"})
+		return
+	}
+	resolution, err := h.service.Resolve(c.Request.Context(), userID, notifType, c.Query("scope"))
+	if err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resolution)
+}
+
+// GetSetting 读取免打扰设置
+func (h *Handler) GetSetting(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	setting, err := h.service.GetSetting(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// UpdateSetting 更新免打扰时段与收件地址
+func (h *Handler) UpdateSetting(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Email          string `json:"email"`
+		QuietEnabled   *bool  `json:"quietEnabled" binding:"required"`
+		QuietStartHour int    `json:"quietStartHour"`
+		QuietEndHour   int    `json:"quietEndHour"`
+		Timezone       string `json:"timezone"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setting, err := h.service.UpdateSetting(c.Request.Context(), userID,
+		req.Email, *req.QuietEnabled, req.QuietStartHour, req.QuietEndHour, req.Timezone)
+	if err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// ListMutes 列出静音覆盖
+func (h *Handler) ListMutes(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	mutes, err := h.service.ListMutes(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"mutes": mutes})
+}
+
+// AddMute 静音房间/项目
+func (h *Handler) AddMute(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Scope string `json:"scope" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mute, err := h.service.AddMute(c.Request.Context(), userID, req.Scope)
+	if err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, mute)
+}
+
+// RemoveMute 取消静音
+func (h *Handler) RemoveMute(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	scope := c.Query("scope")
+	if scope == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope is required"})
+		return
+	}
+	if err := h.service.RemoveMute(c.Request.Context(), userID, scope); err != nil {
+		c.JSON(statusForNotificationError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "mute removed"})
+}
+
+// statusForNotificationError 根据错误内容映射HTTP状态码
+func statusForNotificationError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,537 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 通知渠道
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+)
+
+// 通知类型
+const (
+	TypeTaskAssigned = "task_assigned"
+	TypeCommentReply = "comment_reply"
+	TypeLike         = "like"
+	TypeHealthAlert  = "health_alert"
+	TypeDailyWisdom  = "daily_wisdom"
+)
+
+// validChannels 渠道白名单
+var validChannels = map[string]bool{
+	ChannelInApp: true,
+	ChannelEmail: true,
+	ChannelPush:  true,
+}
+
+// validTypes 类型白名单
+var validTypes = map[string]bool{
+	TypeTaskAssigned: true,
+	TypeCommentReply: true,
+	TypeLike:         true,
+	TypeHealthAlert:  true,
+	TypeDailyWisdom:  true,
+}
+
+// Preference 类型×渠道粒度的开关，无记录时默认开启
+type Preference struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex:idx_notification_pref;type:varchar(36);not null"`
+	// Type task_assigned/comment_reply/like/health_alert/daily_wisdom
+	Type string `json:"type" gorm:"uniqueIndex:idx_notification_pref;type:varchar(32);not null"`
+	// Channel in_app/email/push
+	Channel   string    `json:"channel" gorm:"uniqueIndex:idx_notification_pref;type:varchar(16);not null"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Preference) TableName() string {
+	return "cs_notification_prefs"
+}
+
+// Setting 每用户的免打扰设置
+type Setting struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	// Email email渠道的收件地址，为空时该渠道静默跳过
+	Email string `json:"email,omitempty" gorm:"type:varchar(255)"`
+	// QuietEnabled 免打扰时段内email/push被抑制，站内通知照常落库
+	QuietEnabled bool `json:"quietEnabled"`
+	// QuietStartHour/QuietEndHour 用户本地时间，支持跨午夜（如22到7）
+	QuietStartHour int `json:"quietStartHour"`
+	QuietEndHour   int `json:"quietEndHour"`
+	// Timezone IANA时区名，免打扰时段按此换算
+	Timezone  string    `json:"timezone" gorm:"type:varchar(64)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Setting) TableName() string {
+	return "cs_notification_settings"
+}
+
+// Mute 房间/项目级静音覆盖，命中时该来源的所有渠道全部关闭
+type Mute struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex:idx_notification_mute;type:varchar(36);not null"`
+	// Scope 静音的来源标识，如房间或项目ID
+	Scope     string    `json:"scope" gorm:"uniqueIndex:idx_notification_mute;type:varchar(128);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Mute) TableName() string {
+	return "cs_notification_mutes"
+}
+
+// Notification 一条站内通知
+//
+// 同一折叠键在折叠窗口内的重复通知原地聚合：Count递增、
+// Actors追加、标题改写为聚合格式，客户端按Count>1渲染聚合样式。
+type Notification struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	Type   string `json:"type" gorm:"type:varchar(32);not null"`
+	// Scope 来源标识，用于静音匹配与跳转
+	Scope string `json:"scope,omitempty" gorm:"type:varchar(128)"`
+	Title string `json:"title" gorm:"type:varchar(255)"`
+	Body  string `json:"body,omitempty" gorm:"type:varchar(1024)"`
+	// CollapseKey 折叠键，如 like|<postID>；空表示不参与折叠
+	CollapseKey string `json:"collapseKey,omitempty" gorm:"index;type:varchar(160)"`
+	// Count 聚合的事件数，1表示未聚合
+	Count int `json:"count"`
+	// Actors 参与聚合的最近行为者ID列表
+	Actors    datatypes.JSON `json:"actors,omitempty" gorm:"type:jsonb"`
+	Read      bool           `json:"read" gorm:"index"`
+	CreatedAt time.Time      `json:"createdAt" gorm:"index"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "cs_notifications"
+}
+
+// ChannelDecision 单个渠道的生效结果与原因
+type ChannelDecision struct {
+	Enabled bool `json:"enabled"`
+	// Reason default/preference/muted/quiet_hours
+	Reason string `json:"reason"`
+}
+
+// Resolution 某用户某类型某来源的最终生效矩阵，调试接口直接返回
+type Resolution struct {
+	UserID   string                      `json:"userId"`
+	Type     string                      `json:"type"`
+	Scope    string                      `json:"scope,omitempty"`
+	Channels map[string]*ChannelDecision `json:"channels"`
+}
+
+// Service 通知分发服务
+//
+// 所有出站通知都经过Dispatch做偏好裁决：静音覆盖优先于类型
+// ×渠道开关，免打扰只抑制打扰型渠道。email与push的实际发送
+// 通过注入的回调完成，本包不依赖具体投递实现。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	// sendEmail 邮件投递回调，未注入时email渠道静默跳过
+	sendEmail func(ctx context.Context, to, title, body string) error
+	// publish 事件发布器，push渠道经事件流交给下游投递
+	publish func(event string, payload interface{})
+}
+
+// NewService 创建通知服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Preference{}, &Setting{}, &Mute{}, &Notification{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate notification tables: %w", err)
+	}
+	return &Service{db: db, logger: logger}, nil
+}
+
+// SetEmailSender 注入邮件投递回调
+func (s *Service) SetEmailSender(send func(ctx context.Context, to, title, body string) error) {
+	s.sendEmail = send
+}
+
+// SetEventPublisher 接入事件发布（notification.push）
+func (s *Service) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// Resolve 计算某用户对某类型某来源的生效渠道矩阵
+func (s *Service) Resolve(ctx context.Context, userID, notifType, scope string) (*Resolution, error) {
+	if !validTypes[notifType] {
+		return nil, fmt.Errorf("invalid notification type: %s", notifType)
+	}
+	resolution := &Resolution{
+		UserID:   userID,
+		Type:     notifType,
+		Scope:    scope,
+		Channels: make(map[string]*ChannelDecision, len(validChannels)),
+	}
+	for channel := range validChannels {
+		resolution.Channels[channel] = &ChannelDecision{Enabled: true, Reason: "default"}
+	}
+
+	// 静音覆盖：命中来源时全渠道关闭，优先级最高
+	if scope != "" {
+		var count int64
+		err := s.db.WithContext(ctx).Model(&Mute{}).
+			Where("user_id = ? AND scope = ?", userID, scope).
+			Count(&count).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to check mutes: %w", err)
+		}
+		if count > 0 {
+			for _, decision := range resolution.Channels {
+				decision.Enabled = false
+				decision.Reason = "muted"
+			}
+			return resolution, nil
+		}
+	}
+
+	// 类型×渠道开关
+	var prefs []*Preference
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, notifType).
+		Find(&prefs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load preferences: %w", err)
+	}
+	for _, pref := range prefs {
+		if decision, ok := resolution.Channels[pref.Channel]; ok && !pref.Enabled {
+			decision.Enabled = false
+			decision.Reason = "preference"
+		}
+	}
+
+	// 免打扰：只抑制email/push，站内通知照常
+	quiet, err := s.inQuietHours(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if quiet {
+		for _, channel := range []string{ChannelEmail, ChannelPush} {
+			if decision := resolution.Channels[channel]; decision.Enabled {
+				decision.Enabled = false
+				decision.Reason = "quiet_hours"
+			}
+		}
+	}
+	return resolution, nil
+}
+
+// inQuietHours 判断用户当前是否处于免打扰时段
+func (s *Service) inQuietHours(ctx context.Context, userID string) (bool, error) {
+	setting, err := s.GetSetting(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !setting.QuietEnabled {
+		return false, nil
+	}
+	loc := time.UTC
+	if setting.Timezone != "" {
+		if parsed, err := time.LoadLocation(setting.Timezone); err == nil {
+			loc = parsed
+		}
+	}
+	hour := time.Now().In(loc).Hour()
+	start, end := setting.QuietStartHour, setting.QuietEndHour
+	if start == end {
+		return false, nil
+	}
+	if start < end {
+		return hour >= start && hour < end, nil
+	}
+	// 跨午夜时段，如22-7
+	return hour >= start || hour < end, nil
+}
+
+// Message 一条待分发的通知
+type Message struct {
+	Type  string
+	Scope string
+	Title string
+	Body  string
+	// CollapseKey 折叠键，同键通知在窗口内原地聚合；空表示不折叠
+	CollapseKey string
+	// Actor 触发本条通知的行为者，聚合时追加进Actors
+	Actor string
+}
+
+// Dispatch 按偏好裁决后分发一条通知
+//
+// 返回本次实际生效的渠道矩阵；任何单渠道的投递失败只记日志，
+// 不影响其余渠道。命中折叠窗口的通知只原地更新站内聚合，不再
+// 重复触发email/push，避免高活跃内容造成通知风暴。
+func (s *Service) Dispatch(ctx context.Context, userID string, msg *Message) (*Resolution, error) {
+	resolution, err := s.Resolve(ctx, userID, msg.Type, msg.Scope)
+	if err != nil {
+		return nil, err
+	}
+	collapsed := false
+	if resolution.Channels[ChannelInApp].Enabled {
+		collapsed, err = s.storeOrCollapse(ctx, userID, msg)
+		if err != nil {
+			s.logger.Warn("failed to store in-app notification",
+				zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+	if collapsed {
+		return resolution, nil
+	}
+	if resolution.Channels[ChannelEmail].Enabled && s.sendEmail != nil {
+		setting, err := s.GetSetting(ctx, userID)
+		if err == nil && setting.Email != "" {
+			if err := s.sendEmail(ctx, setting.Email, msg.Title, msg.Body); err != nil {
+				s.logger.Warn("failed to send notification email",
+					zap.String("user_id", userID), zap.Error(err))
+			}
+		}
+	}
+	if resolution.Channels[ChannelPush].Enabled && s.publish != nil {
+		s.publish("notification.push", map[string]interface{}{
+			"userId": userID,
+			"type":   msg.Type,
+			"title":  msg.Title,
+			"body":   msg.Body,
+		})
+	}
+	return resolution, nil
+}
+
+// HandleEvent 事件回调：把领域事件转成通知并分发
+//
+// 与搜索索引、活动时间线共用同一事件流，在main中挂到事件
+// 发布器上。
+func (s *Service) HandleEvent(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	var common struct {
+		ID         string `json:"id"`
+		Title      string `json:"title"`
+		UserID     string `json:"userId"`
+		AuthorID   string `json:"authorId"`
+		AssigneeID string `json:"assigneeId"`
+		ThreadID   string `json:"threadId"`
+		BoardID    string `json:"boardId"`
+	}
+	if err := json.Unmarshal(data, &common); err != nil {
+		return
+	}
+
+	var userID string
+	msg := &Message{}
+	switch event {
+	case "task.created":
+		userID = common.AssigneeID
+		msg.Type, msg.Scope = TypeTaskAssigned, common.BoardID
+		msg.Title = fmt.Sprintf("你被指派了任务「%s」", common.Title)
+	case "post.liked":
+		// 点赞风暴按帖子折叠成一条聚合通知
+		userID = common.AuthorID
+		msg.Type, msg.Scope = TypeLike, common.ID
+		msg.CollapseKey = TypeLike + "|" + common.ID
+		msg.Actor = common.UserID
+		msg.Title = "有人赞了你的帖子"
+	case "health.alert.triggered":
+		userID = common.UserID
+		msg.Type = TypeHealthAlert
+		msg.Title = "你有一条新的健康预警"
+	case "wisdom.daily.push":
+		userID = common.UserID
+		msg.Type = TypeDailyWisdom
+		msg.Title = fmt.Sprintf("今日智慧：%s", common.Title)
+	default:
+		return
+	}
+	if userID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := s.Dispatch(ctx, userID, msg); err != nil {
+		s.logger.Warn("failed to dispatch notification",
+			zap.String("event", event), zap.Error(err))
+	}
+}
+
+// SetPreference 设置单个类型×渠道开关
+func (s *Service) SetPreference(ctx context.Context, userID, notifType, channel string, enabled bool) (*Preference, error) {
+	if !validTypes[notifType] {
+		return nil, fmt.Errorf("invalid notification type: %s", notifType)
+	}
+	if !validChannels[channel] {
+		return nil, fmt.Errorf("invalid channel: %s", channel)
+	}
+	var pref Preference
+	err := s.db.WithContext(ctx).
+		First(&pref, "user_id = ? AND type = ? AND channel = ?", userID, notifType, channel).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load preference: %w", err)
+		}
+		pref = Preference{ID: uuid.NewString(), UserID: userID, Type: notifType, Channel: channel}
+	}
+	pref.Enabled = enabled
+	if err := s.db.WithContext(ctx).Save(&pref).Error; err != nil {
+		return nil, fmt.Errorf("failed to save preference: %w", err)
+	}
+	return &pref, nil
+}
+
+// ListPreferences 返回完整矩阵，未显式设置的组合按默认开启补齐
+func (s *Service) ListPreferences(ctx context.Context, userID string) ([]*Preference, error) {
+	var prefs []*Preference
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Find(&prefs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preferences: %w", err)
+	}
+	existing := make(map[string]bool, len(prefs))
+	for _, pref := range prefs {
+		existing[pref.Type+"|"+pref.Channel] = true
+	}
+	for notifType := range validTypes {
+		for channel := range validChannels {
+			if !existing[notifType+"|"+channel] {
+				prefs = append(prefs, &Preference{
+					UserID: userID, Type: notifType, Channel: channel, Enabled: true,
+				})
+			}
+		}
+	}
+	return prefs, nil
+}
+
+// GetSetting 读取免打扰设置
+func (s *Service) GetSetting(ctx context.Context, userID string) (*Setting, error) {
+	var setting Setting
+	err := s.db.WithContext(ctx).First(&setting, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &Setting{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to load notification setting: %w", err)
+	}
+	return &setting, nil
+}
+
+// UpdateSetting 更新免打扰时段与email渠道收件地址
+func (s *Service) UpdateSetting(ctx context.Context, userID string, email string, enabled bool, startHour, endHour int, timezone string) (*Setting, error) {
+	if startHour < 0 || startHour > 23 || endHour < 0 || endHour > 23 {
+		return nil, fmt.Errorf("invalid quiet hours: hours must be between 0 and 23")
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", timezone)
+		}
+	}
+	setting, err := s.GetSetting(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting.ID == "" {
+		setting.ID = uuid.NewString()
+	}
+	setting.Email = email
+	setting.QuietEnabled = enabled
+	setting.QuietStartHour = startHour
+	setting.QuietEndHour = endHour
+	setting.Timezone = timezone
+	if err := s.db.WithContext(ctx).Save(setting).Error; err != nil {
+		return nil, fmt.Errorf("failed to save notification setting: %w", err)
+	}
+	return setting, nil
+}
+
+// AddMute 静音一个房间/项目
+func (s *Service) AddMute(ctx context.Context, userID, scope string) (*Mute, error) {
+	if scope == "" {
+		return nil, fmt.Errorf("invalid mute: scope is required")
+	}
+	var existing Mute
+	err := s.db.WithContext(ctx).First(&existing, "user_id = ? AND scope = ?", userID, scope).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check mute: %w", err)
+	}
+	mute := &Mute{ID: uuid.NewString(), UserID: userID, Scope: scope}
+	if err := s.db.WithContext(ctx).Create(mute).Error; err != nil {
+		return nil, fmt.Errorf("failed to create mute: %w", err)
+	}
+	return mute, nil
+}
+
+// ListMutes 列出静音覆盖
+func (s *Service) ListMutes(ctx context.Context, userID string) ([]*Mute, error) {
+	var mutes []*Mute
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Find(&mutes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mutes: %w", err)
+	}
+	return mutes, nil
+}
+
+// RemoveMute 取消静音
+func (s *Service) RemoveMute(ctx context.Context, userID, scope string) error {
+	result := s.db.WithContext(ctx).Delete(&Mute{}, "user_id = ? AND scope = ?", userID, scope)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove mute: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("mute not found")
+	}
+	return nil
+}
+
+// ListNotifications 列出站内通知
+func (s *Service) ListNotifications(ctx context.Context, userID string, unreadOnly bool, limit int) ([]*Notification, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	db := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		db = db.Where("read = ?", false)
+	}
+	var items []*Notification
+	if err := db.Order("created_at DESC").Limit(limit).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return items, nil
+}
+
+// MarkRead 标记通知已读
+func (s *Service) MarkRead(ctx context.Context, userID, notificationID string) error {
+	result := s.db.WithContext(ctx).Model(&Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read", true)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark notification read: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package oauth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler OAuth2授权与开发者门户HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建OAuth2处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册OAuth2与开发者门户路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	oauth := router.Group("/oauth")
+	{
+		oauth.GET("/authorize", h.DescribeClient)
+		oauth.POST("/authorize", h.Authorize)
+		oauth.POST("/token", h.Token)
+		oauth.POST("/introspect", h.Introspect)
+		oauth.POST("/revoke", h.Revoke)
+	}
+	portal := router.Group("/developer")
+	{
+		portal.POST("/clients", h.CreateClient)
+		portal.GET("/clients", h.ListClients)
+		portal.DELETE("/clients/:clientId", h.DeleteClient)
+		portal.GET("/clients/:clientId/usage", h.GetClientUsage)
+	}
+}
+
+// requireUser 从请求头解析用户ID
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return "", false
+	}
+	return userID, true
+}
+
+// statusForOAuthError 根据错误内容映射HTTP状态码
+func statusForOAuthError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid client"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateClient 注册第三方应用
+func (h *Handler) CreateClient(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req CreateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	created, err := h.service.CreateClient(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListClients 列出本人的客户端
+func (h *Handler) ListClients(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	clients, err := h.service.ListClients(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clients": clients, "total": len(clients)})
+}
+
+// DeleteClient 删除客户端
+func (h *Handler) DeleteClient(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteClient(c.Request.Context(), userID, c.Param("clientId")); err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "client deleted"})
+}
+
+// GetClientUsage 客户端用量
+func (h *Handler) GetClientUsage(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	usage, err := h.service.GetClientUsage(c.Request.Context(), userID, c.Param("clientId"))
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// DescribeClient 授权确认页查询客户端信息（client_id查询参数）
+func (h *Handler) DescribeClient(c *gin.Context) {
+	clientID := c.Query("client_id")
+	if clientID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: client_id is required"})
+		return
+	}
+	info, err := h.service.DescribeClient(c.Request.Context(), clientID)
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// Authorize 用户批准授权，返回授权码与回跳地址
+func (h *Handler) Authorize(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req AuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Authorize(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Token 令牌端点（application/x-www-form-urlencoded）
+func (h *Handler) Token(c *gin.Context) {
+	req := &TokenRequest{
+		GrantType:    c.PostForm("grant_type"),
+		Code:         c.PostForm("code"),
+		RedirectURI:  c.PostForm("redirect_uri"),
+		ClientID:     c.PostForm("client_id"),
+		ClientSecret: c.PostForm("client_secret"),
+		CodeVerifier: c.PostForm("code_verifier"),
+		RefreshToken: c.PostForm("refresh_token"),
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = id, secret
+	}
+	response, err := h.service.ExchangeToken(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Introspect 令牌自省端点
+func (h *Handler) Introspect(c *gin.Context) {
+	clientID, clientSecret := clientCredentials(c)
+	result, err := h.service.Introspect(c.Request.Context(), clientID, clientSecret, c.PostForm("token"))
+	if err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Revoke 令牌吊销端点
+func (h *Handler) Revoke(c *gin.Context) {
+	clientID, clientSecret := clientCredentials(c)
+	if err := h.service.Revoke(c.Request.Context(), clientID, clientSecret, c.PostForm("token")); err != nil {
+		c.JSON(statusForOAuthError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "token revoked"})
+}
+
+// clientCredentials 从Basic认证或表单取客户端凭证
+func clientCredentials(c *gin.Context) (string, string) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		return id, secret
+	}
+	return c.PostForm("client_id"), c.PostForm("client_secret")
+}
@@ -0,0 +1,299 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 客户端限制
+const (
+	// maxClientsPerOwner 单个开发者可注册的客户端数
+	maxClientsPerOwner = 20
+	// maxRedirectURIs 单客户端回调地址数上限
+	maxRedirectURIs = 10
+)
+
+// knownScopes 第三方应用可申请的权限范围
+var knownScopes = map[string]bool{
+	"profile":     true,
+	"wisdom:read": true,
+	"tasks:read":  true,
+	"tasks:write": true,
+	"health:read": true,
+}
+
+// Client 第三方应用
+//
+// 机密客户端持有密钥（仅存哈希），公开客户端（移动端/SPA）
+// 无密钥、必须走PKCE。
+type Client struct {
+	ID      string `json:"clientId" gorm:"primaryKey;type:varchar(36)"`
+	OwnerID string `json:"ownerId" gorm:"index;type:varchar(36);not null"`
+	Name    string `json:"name" gorm:"type:varchar(128);not null"`
+	// SecretHash 机密客户端的密钥哈希，公开客户端为空
+	SecretHash string `json:"-" gorm:"type:varchar(64)"`
+	// Public 公开客户端（无密钥，强制PKCE）
+	Public bool `json:"public"`
+	// RedirectURIs 允许的回调地址，逗号分隔
+	RedirectURIs string `json:"redirectUris" gorm:"type:text;not null"`
+	// Scopes 允许申请的范围，空格分隔
+	Scopes    string    `json:"scopes" gorm:"type:varchar(512);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Client) TableName() string {
+	return "cs_oauth_clients"
+}
+
+// authCode 授权码，一次性、短时效
+type authCode struct {
+	Code            string    `gorm:"primaryKey;type:varchar(64)"`
+	ClientID        string    `gorm:"index;type:varchar(36);not null"`
+	UserID          string    `gorm:"type:varchar(36);not null"`
+	RedirectURI     string    `gorm:"type:varchar(512);not null"`
+	Scopes          string    `gorm:"type:varchar(512)"`
+	CodeChallenge   string    `gorm:"type:varchar(128)"`
+	ChallengeMethod string    `gorm:"type:varchar(8)"`
+	Used            bool      `gorm:"default:false"`
+	ExpiresAt       time.Time `gorm:"index"`
+	CreatedAt       time.Time
+}
+
+// TableName 指定表名
+func (authCode) TableName() string {
+	return "cs_oauth_codes"
+}
+
+// Token 已签发的访问令牌（仅存哈希）
+type Token struct {
+	ID               string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ClientID         string `json:"clientId" gorm:"index;type:varchar(36);not null"`
+	UserID           string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	AccessTokenHash  string `json:"-" gorm:"type:varchar(64);index;not null"`
+	RefreshTokenHash string `json:"-" gorm:"type:varchar(64);index"`
+	Scopes           string `json:"scopes" gorm:"type:varchar(512)"`
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	LastUsedAt       *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Token) TableName() string {
+	return "cs_oauth_tokens"
+}
+
+// Service OAuth2授权服务
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewService 创建OAuth2授权服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Client{}, &authCode{}, &Token{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate oauth tables: %w", err)
+	}
+	return &Service{db: db, logger: logger}, nil
+}
+
+// CreateClientRequest 注册客户端请求
+type CreateClientRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectURIs []string `json:"redirectUris" binding:"required"`
+	Scopes       []string `json:"scopes" binding:"required"`
+	Public       bool     `json:"public"`
+}
+
+// CreatedClient 注册结果，机密客户端的Secret仅此一次下发
+type CreatedClient struct {
+	Client *Client `json:"client"`
+	Secret string  `json:"secret,omitempty"`
+}
+
+// CreateClient 注册第三方应用
+func (s *Service) CreateClient(ctx context.Context, ownerID string, req *CreateClientRequest) (*CreatedClient, error) {
+	if len(req.RedirectURIs) == 0 || len(req.RedirectURIs) > maxRedirectURIs {
+		return nil, fmt.Errorf("invalid redirect uris: between 1 and %d required", maxRedirectURIs)
+	}
+	for _, raw := range req.RedirectURIs {
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Scheme == "" || parsed.Fragment != "" {
+			return nil, fmt.Errorf("invalid redirect uri: %s", raw)
+		}
+	}
+	for _, scope := range req.Scopes {
+		if !knownScopes[scope] {
+			return nil, fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&Client{}).
+		Where("owner_id = ?", ownerID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count clients: %w", err)
+	}
+	if count >= maxClientsPerOwner {
+		return nil, fmt.Errorf("invalid request: maximum %d clients per developer", maxClientsPerOwner)
+	}
+
+	client := &Client{
+		ID:           uuid.NewString(),
+		OwnerID:      ownerID,
+		Name:         req.Name,
+		Public:       req.Public,
+		RedirectURIs: strings.Join(req.RedirectURIs, ","),
+		Scopes:       strings.Join(req.Scopes, " "),
+	}
+	var secret string
+	if !req.Public {
+		raw, err := randomToken("cso_")
+		if err != nil {
+			return nil, err
+		}
+		secret = raw
+		client.SecretHash = hashToken(secret)
+	}
+	if err := s.db.WithContext(ctx).Create(client).Error; err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return &CreatedClient{Client: client, Secret: secret}, nil
+}
+
+// ListClients 列出开发者名下的客户端
+func (s *Service) ListClients(ctx context.Context, ownerID string) ([]*Client, error) {
+	var clients []*Client
+	err := s.db.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Find(&clients).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	return clients, nil
+}
+
+// DeleteClient 删除客户端并吊销其全部令牌
+func (s *Service) DeleteClient(ctx context.Context, ownerID, clientID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&Client{}, "id = ? AND owner_id = ?", clientID, ownerID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete client: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("client not found: %s", clientID)
+		}
+		if err := tx.Delete(&authCode{}, "client_id = ?", clientID).Error; err != nil {
+			return fmt.Errorf("failed to delete codes: %w", err)
+		}
+		now := time.Now()
+		if err := tx.Model(&Token{}).
+			Where("client_id = ? AND revoked_at IS NULL", clientID).
+			Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+// ClientUsage 客户端用量概览
+type ClientUsage struct {
+	TokensIssued int64      `json:"tokensIssued"`
+	ActiveTokens int64      `json:"activeTokens"`
+	UniqueUsers  int64      `json:"uniqueUsers"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// GetClientUsage 开发者门户的用量视图
+func (s *Service) GetClientUsage(ctx context.Context, ownerID, clientID string) (*ClientUsage, error) {
+	var client Client
+	err := s.db.WithContext(ctx).First(&client, "id = ? AND owner_id = ?", clientID, ownerID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("client not found: %s", clientID)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	usage := &ClientUsage{}
+	base := s.db.WithContext(ctx).Model(&Token{}).Where("client_id = ?", clientID)
+	if err := base.Session(&gorm.Session{}).Count(&usage.TokensIssued).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tokens: %w", err)
+	}
+	err = base.Session(&gorm.Session{}).
+		Where("revoked_at IS NULL AND expires_at > ?", time.Now()).
+		Count(&usage.ActiveTokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active tokens: %w", err)
+	}
+	err = base.Session(&gorm.Session{}).
+		Distinct("user_id").Count(&usage.UniqueUsers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	var latest Token
+	err = s.db.WithContext(ctx).
+		Where("client_id = ? AND last_used_at IS NOT NULL", clientID).
+		Order("last_used_at DESC").
+		First(&latest).Error
+	if err == nil {
+		usage.LastUsedAt = latest.LastUsedAt
+	}
+	return usage, nil
+}
+
+// allowsRedirect 回调地址须与注册值精确匹配
+func (c *Client) allowsRedirect(uri string) bool {
+	for _, registered := range strings.Split(c.RedirectURIs, ",") {
+		if registered == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsScopes 申请范围须是注册范围的子集
+func (c *Client) allowsScopes(requested string) bool {
+	allowed := make(map[string]bool)
+	for _, scope := range strings.Fields(c.Scopes) {
+		allowed[scope] = true
+	}
+	for _, scope := range strings.Fields(requested) {
+		if !allowed[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// randomToken 生成带前缀的随机令牌明文
+func randomToken(prefix string) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// hashToken 计算令牌明文的存储哈希
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// constantTimeEqual 常数时间比较哈希
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
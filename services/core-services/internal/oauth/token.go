@@ -0,0 +1,333 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 令牌时效
+const (
+	// codeTTL 授权码有效期
+	codeTTL = 5 * time.Minute
+	// accessTokenTTL 访问令牌有效期
+	accessTokenTTL = time.Hour
+	// refreshTokenTTL 刷新令牌有效期
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// AuthorizeRequest 用户批准授权的参数
+type AuthorizeRequest struct {
+	ClientID            string `json:"clientId" binding:"required"`
+	RedirectURI         string `json:"redirectUri" binding:"required"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"codeChallenge"`
+	CodeChallengeMethod string `json:"codeChallengeMethod"`
+}
+
+// AuthorizeResult 授权码与拼好的回跳地址
+type AuthorizeResult struct {
+	Code        string `json:"code"`
+	State       string `json:"state,omitempty"`
+	RedirectURI string `json:"redirectUri"`
+}
+
+// Authorize 用户批准授权，签发授权码
+//
+// 消费方是自家的授权确认页：展示ClientInfo后把用户的同意
+// POST到这里，再携code回跳到第三方。
+func (s *Service) Authorize(ctx context.Context, userID string, req *AuthorizeRequest) (*AuthorizeResult, error) {
+	client, err := s.loadClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.allowsRedirect(req.RedirectURI) {
+		return nil, fmt.Errorf("invalid redirect uri: not registered for client")
+	}
+	scope := strings.TrimSpace(req.Scope)
+	if scope == "" {
+		scope = client.Scopes
+	}
+	if !client.allowsScopes(scope) {
+		return nil, fmt.Errorf("invalid scope: exceeds client registration")
+	}
+	switch req.CodeChallengeMethod {
+	case "", "plain", "S256":
+	default:
+		return nil, fmt.Errorf("invalid code challenge method: %s", req.CodeChallengeMethod)
+	}
+	if client.Public && req.CodeChallenge == "" {
+		return nil, fmt.Errorf("invalid request: public clients must use PKCE")
+	}
+
+	raw, err := randomToken("")
+	if err != nil {
+		return nil, err
+	}
+	code := &authCode{
+		Code:            raw,
+		ClientID:        client.ID,
+		UserID:          userID,
+		RedirectURI:     req.RedirectURI,
+		Scopes:          scope,
+		CodeChallenge:   req.CodeChallenge,
+		ChallengeMethod: req.CodeChallengeMethod,
+		ExpiresAt:       time.Now().Add(codeTTL),
+	}
+	if err := s.db.WithContext(ctx).Create(code).Error; err != nil {
+		return nil, fmt.Errorf("failed to create authorization code: %w", err)
+	}
+
+	redirect := req.RedirectURI
+	separator := "?"
+	if strings.Contains(redirect, "?") {
+		separator = "&"
+	}
+	redirect += separator + "code=" + raw
+	if req.State != "" {
+		redirect += "&state=" + req.State
+	}
+	return &AuthorizeResult{Code: raw, State: req.State, RedirectURI: redirect}, nil
+}
+
+// ClientInfo 授权确认页展示的客户端信息
+type ClientInfo struct {
+	ClientID string   `json:"clientId"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+// DescribeClient 供授权确认页查询客户端
+func (s *Service) DescribeClient(ctx context.Context, clientID string) (*ClientInfo, error) {
+	client, err := s.loadClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientInfo{
+		ClientID: client.ID,
+		Name:     client.Name,
+		Scopes:   strings.Fields(client.Scopes),
+	}, nil
+}
+
+// TokenRequest 令牌端点参数（表单编码）
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+	RefreshToken string
+}
+
+// TokenResponse RFC 6749令牌响应
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// ExchangeToken 令牌端点：授权码换令牌或刷新令牌轮换
+func (s *Service) ExchangeToken(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
+	switch req.GrantType {
+	case "authorization_code":
+		return s.exchangeCode(ctx, req)
+	case "refresh_token":
+		return s.refreshToken(ctx, req)
+	default:
+		return nil, fmt.Errorf("invalid grant type: %s", req.GrantType)
+	}
+}
+
+// exchangeCode 授权码换令牌，校验客户端凭证或PKCE
+func (s *Service) exchangeCode(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	var code authCode
+	err = s.db.WithContext(ctx).First(&code, "code = ?", req.Code).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invalid grant: unknown code")
+		}
+		return nil, fmt.Errorf("failed to load code: %w", err)
+	}
+	if code.Used || time.Now().After(code.ExpiresAt) || code.ClientID != client.ID {
+		return nil, fmt.Errorf("invalid grant: code expired or already used")
+	}
+	if code.RedirectURI != req.RedirectURI {
+		return nil, fmt.Errorf("invalid grant: redirect uri mismatch")
+	}
+	if code.CodeChallenge != "" && !verifyPKCE(code.CodeChallenge, code.ChallengeMethod, req.CodeVerifier) {
+		return nil, fmt.Errorf("invalid grant: PKCE verification failed")
+	}
+	if err := s.db.WithContext(ctx).Model(&authCode{}).
+		Where("code = ?", code.Code).Update("used", true).Error; err != nil {
+		return nil, fmt.Errorf("failed to consume code: %w", err)
+	}
+	return s.issueToken(ctx, client.ID, code.UserID, code.Scopes)
+}
+
+// refreshToken 刷新令牌轮换：旧令牌吊销、签发新对
+func (s *Service) refreshToken(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	var token Token
+	err = s.db.WithContext(ctx).
+		First(&token, "refresh_token_hash = ? AND client_id = ?", hashToken(req.RefreshToken), client.ID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invalid grant: unknown refresh token")
+		}
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	if token.RevokedAt != nil || time.Now().After(token.CreatedAt.Add(refreshTokenTTL)) {
+		return nil, fmt.Errorf("invalid grant: refresh token revoked or expired")
+	}
+	now := time.Now()
+	if err := s.db.WithContext(ctx).Model(&Token{}).
+		Where("id = ?", token.ID).Update("revoked_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to rotate token: %w", err)
+	}
+	return s.issueToken(ctx, client.ID, token.UserID, token.Scopes)
+}
+
+// issueToken 签发访问令牌与刷新令牌
+func (s *Service) issueToken(ctx context.Context, clientID, userID, scopes string) (*TokenResponse, error) {
+	access, err := randomToken("csa_")
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := randomToken("csr_")
+	if err != nil {
+		return nil, err
+	}
+	token := &Token{
+		ID:               uuid.NewString(),
+		ClientID:         clientID,
+		UserID:           userID,
+		AccessTokenHash:  hashToken(access),
+		RefreshTokenHash: hashToken(refresh),
+		Scopes:           scopes,
+		ExpiresAt:        time.Now().Add(accessTokenTTL),
+	}
+	if err := s.db.WithContext(ctx).Create(token).Error; err != nil {
+		return nil, fmt.Errorf("failed to store token: %w", err)
+	}
+	return &TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+		RefreshToken: refresh,
+		Scope:        scopes,
+	}, nil
+}
+
+// Introspection RFC 7662自省响应
+type Introspection struct {
+	Active   bool   `json:"active"`
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+	Sub      string `json:"sub,omitempty"`
+	Exp      int64  `json:"exp,omitempty"`
+}
+
+// Introspect 令牌自省，调用方须以客户端凭证认证
+func (s *Service) Introspect(ctx context.Context, clientID, clientSecret, accessToken string) (*Introspection, error) {
+	if _, err := s.authenticateClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+	var token Token
+	err := s.db.WithContext(ctx).
+		First(&token, "access_token_hash = ?", hashToken(accessToken)).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &Introspection{Active: false}, nil
+		}
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+	if token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return &Introspection{Active: false}, nil
+	}
+	now := time.Now()
+	s.db.WithContext(ctx).Model(&Token{}).
+		Where("id = ?", token.ID).Update("last_used_at", now)
+	return &Introspection{
+		Active:   true,
+		Scope:    token.Scopes,
+		ClientID: token.ClientID,
+		Sub:      token.UserID,
+		Exp:      token.ExpiresAt.Unix(),
+	}, nil
+}
+
+// Revoke RFC 7009令牌吊销（access或refresh令牌均可）
+func (s *Service) Revoke(ctx context.Context, clientID, clientSecret, tokenValue string) error {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	hash := hashToken(tokenValue)
+	now := time.Now()
+	// 未命中的吊销按规范静默成功
+	s.db.WithContext(ctx).Model(&Token{}).
+		Where("client_id = ? AND revoked_at IS NULL AND (access_token_hash = ? OR refresh_token_hash = ?)",
+			client.ID, hash, hash).
+		Update("revoked_at", now)
+	return nil
+}
+
+// authenticateClient 校验客户端身份；公开客户端无密钥
+func (s *Service) authenticateClient(ctx context.Context, clientID, clientSecret string) (*Client, error) {
+	client, err := s.loadClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return client, nil
+	}
+	if clientSecret == "" || !constantTimeEqual(client.SecretHash, hashToken(clientSecret)) {
+		return nil, fmt.Errorf("invalid client: authentication failed")
+	}
+	return client, nil
+}
+
+// loadClient 按ID取客户端
+func (s *Service) loadClient(ctx context.Context, clientID string) (*Client, error) {
+	var client Client
+	err := s.db.WithContext(ctx).First(&client, "id = ?", clientID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("client not found: %s", clientID)
+		}
+		return nil, fmt.Errorf("failed to load client: %w", err)
+	}
+	return &client, nil
+}
+
+// verifyPKCE 校验code_verifier与挑战值
+func verifyPKCE(challenge, method, verifier string) bool {
+	if verifier == "" {
+		return false
+	}
+	switch method {
+	case "S256":
+		sum := sha256.Sum256([]byte(verifier))
+		return constantTimeEqual(challenge, base64.RawURLEncoding.EncodeToString(sum[:]))
+	default:
+		return constantTimeEqual(challenge, verifier)
+	}
+}
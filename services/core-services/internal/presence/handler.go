@@ -0,0 +1,193 @@
+package presence
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// Handler 在线状态HTTP处理器
+type Handler struct {
+	service *Service
+	hub     *realtime.Hub
+}
+
+// NewHandler 创建在线状态处理器
+func NewHandler(service *Service, hub *realtime.Hub) *Handler {
+	return &Handler{service: service, hub: hub}
+}
+
+// SetupRoutes 注册在线状态相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/presence/ws", h.ServeWS)
+	router.POST("/presence/heartbeat", h.Heartbeat)
+	router.GET("/presence", h.Query)
+	router.GET("/presence/settings", h.GetSetting)
+	router.PUT("/presence/settings", h.SetInvisible)
+	router.POST("/presence/indicators", h.Indicate)
+	router.GET("/presence/indicators", h.ListIndicators)
+	router.GET("/presence/rooms/:room/settings", h.GetRoomSetting)
+	router.PUT("/presence/rooms/:room/settings", h.SetRoomIndicators)
+}
+
+// ServeWS 加入在线状态订阅房间
+func (h *Handler) ServeWS(c *gin.Context) {
+	userID := c.Query("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	h.hub.ServeWS(c, presenceRoom, userID)
+}
+
+// Heartbeat HTTP心跳，无WebSocket连接的客户端使用
+func (h *Handler) Heartbeat(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	var req struct {
+		Status   string `json:"status"`
+		Activity string `json:"activity"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	presence, err := h.service.Heartbeat(c.Request.Context(), userID, req.Status, req.Activity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, presence)
+}
+
+// Query 批量查询用户状态
+func (h *Handler) Query(c *gin.Context) {
+	raw := c.Query("userIds")
+	if raw == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userIds is required"})
+		return
+	}
+	presences, err := h.service.Query(c.Request.Context(), strings.Split(raw, ","))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"presences": presences})
+}
+
+// Indicate 发送输入/查看指示器（HTTP回退通道）
+func (h *Handler) Indicate(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	var req struct {
+		Room   string `json:"room" binding:"required"`
+		Kind   string `json:"kind" binding:"required"`
+		Target string `json:"target"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	indicator, err := h.service.Indicate(c.Request.Context(), userID, req.Room, req.Kind, req.Target)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "disabled") {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if indicator == nil {
+		// 命中节流窗口，信号被丢弃
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "indicator throttled"})
+		return
+	}
+	c.JSON(http.StatusOK, indicator)
+}
+
+// ListIndicators 列出房间内活跃指示器
+func (h *Handler) ListIndicators(c *gin.Context) {
+	room := c.Query("room")
+	if room == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "room is required"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"indicators": h.service.ActiveIndicators(room)})
+}
+
+// GetRoomSetting 读取房间指示器设置
+func (h *Handler) GetRoomSetting(c *gin.Context) {
+	setting, err := h.service.GetRoomSetting(c.Request.Context(), c.Param("room"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// SetRoomIndicators 开关房间指示器
+func (h *Handler) SetRoomIndicators(c *gin.Context) {
+	var req struct {
+		IndicatorsDisabled *bool `json:"indicatorsDisabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setting, err := h.service.SetRoomIndicators(c.Request.Context(), c.Param("room"), *req.IndicatorsDisabled)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// GetSetting 读取隐私设置
+func (h *Handler) GetSetting(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	setting, err := h.service.GetSetting(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// SetInvisible 开关隐身模式
+func (h *Handler) SetInvisible(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	var req struct {
+		Invisible *bool `json:"invisible" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setting, err := h.service.SetInvisible(c.Request.Context(), userID, *req.Invisible)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
@@ -0,0 +1,206 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// 指示器类型
+const (
+	IndicatorTyping  = "typing"
+	IndicatorViewing = "viewing"
+)
+
+// indicatorTTL 指示器有效期，未续发自动消失
+const indicatorTTL = 6 * time.Second
+
+// indicatorThrottle 同一用户同一房间同类指示器的最小发送间隔
+const indicatorThrottle = 2 * time.Second
+
+// RoomSetting 房间级指示器开关
+//
+// 大房间里指示器广播量与成员数成平方关系，允许按房间关闭。
+type RoomSetting struct {
+	ID   string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Room string `json:"room" gorm:"uniqueIndex;type:varchar(128);not null"`
+	// IndicatorsDisabled 关闭后该房间的指示器被静默丢弃
+	IndicatorsDisabled bool      `json:"indicatorsDisabled"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (RoomSetting) TableName() string {
+	return "cs_presence_room_settings"
+}
+
+// Indicator 一个活跃的指示器
+type Indicator struct {
+	UserID string `json:"userId"`
+	Room   string `json:"room"`
+	// Kind typing/viewing
+	Kind string `json:"kind"`
+	// Target viewing指示器关联的资源，如任务ID
+	Target    string    `json:"target,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// indicatorPayload WebSocket指示器消息体
+type indicatorPayload struct {
+	Room   string `json:"room"`
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
+
+// attachIndicatorHandler 在hub上挂接指示器消息处理
+func (s *Service) attachIndicatorHandler(hub *realtime.Hub) {
+	hub.Handle("presence.indicator", func(client *realtime.Client, message *realtime.Message) (*realtime.Message, error) {
+		var payload indicatorPayload
+		if err := json.Unmarshal(message.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("invalid indicator payload: %w", err)
+		}
+		indicator, err := s.Indicate(client.Context(), client.UserID, payload.Room, payload.Kind, payload.Target)
+		if err != nil || indicator == nil {
+			// 节流丢弃的信号不回错误，避免客户端重试风暴
+			return nil, err
+		}
+		return nil, nil
+	})
+}
+
+// Indicate 记录并广播一个指示器
+//
+// 同类信号按indicatorThrottle节流，命中节流窗口时静默丢弃
+// （返回nil指示器）；关闭指示器的房间直接拒绝。
+func (s *Service) Indicate(ctx context.Context, userID, room, kind, target string) (*Indicator, error) {
+	if room == "" {
+		return nil, fmt.Errorf("invalid indicator: room is required")
+	}
+	if kind != IndicatorTyping && kind != IndicatorViewing {
+		return nil, fmt.Errorf("invalid indicator kind: %s", kind)
+	}
+	disabled, err := s.indicatorsDisabled(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		return nil, fmt.Errorf("indicators disabled for this room")
+	}
+
+	now := time.Now()
+	throttleKey := userID + "|" + room + "|" + kind
+	s.mu.Lock()
+	if last, ok := s.indicatorLast[throttleKey]; ok && now.Sub(last) < indicatorThrottle {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	s.indicatorLast[throttleKey] = now
+	indicator := &Indicator{
+		UserID:    userID,
+		Room:      room,
+		Kind:      kind,
+		Target:    target,
+		ExpiresAt: now.Add(indicatorTTL),
+	}
+	if s.indicators[room] == nil {
+		s.indicators[room] = make(map[string]*Indicator)
+	}
+	s.indicators[room][userID+"|"+kind] = indicator
+	hub := s.hub
+	s.mu.Unlock()
+
+	if hub != nil {
+		raw, err := json.Marshal(indicator)
+		if err == nil {
+			hub.Broadcast(room, &realtime.Message{
+				Type:    "presence.indicator",
+				Room:    room,
+				From:    userID,
+				Payload: raw,
+			})
+		}
+	}
+	return indicator, nil
+}
+
+// ActiveIndicators 列出房间内未过期的指示器
+func (s *Service) ActiveIndicators(room string) []*Indicator {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var active []*Indicator
+	for _, indicator := range s.indicators[room] {
+		if now.Before(indicator.ExpiresAt) {
+			active = append(active, indicator)
+		}
+	}
+	return active
+}
+
+// indicatorsDisabled 查房间是否关闭了指示器
+func (s *Service) indicatorsDisabled(ctx context.Context, room string) (bool, error) {
+	var setting RoomSetting
+	err := s.db.WithContext(ctx).First(&setting, "room = ?", room).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load room setting: %w", err)
+	}
+	return setting.IndicatorsDisabled, nil
+}
+
+// GetRoomSetting 读取房间设置
+func (s *Service) GetRoomSetting(ctx context.Context, room string) (*RoomSetting, error) {
+	var setting RoomSetting
+	err := s.db.WithContext(ctx).First(&setting, "room = ?", room).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &RoomSetting{Room: room}, nil
+		}
+		return nil, fmt.Errorf("failed to load room setting: %w", err)
+	}
+	return &setting, nil
+}
+
+// SetRoomIndicators 开关房间的指示器
+func (s *Service) SetRoomIndicators(ctx context.Context, room string, disabled bool) (*RoomSetting, error) {
+	setting, err := s.GetRoomSetting(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if setting.ID == "" {
+		setting.ID = uuid.NewString()
+	}
+	setting.IndicatorsDisabled = disabled
+	if err := s.db.WithContext(ctx).Save(setting).Error; err != nil {
+		return nil, fmt.Errorf("failed to save room setting: %w", err)
+	}
+	return setting, nil
+}
+
+// sweepIndicators 移除过期指示器与陈旧的节流记录
+func (s *Service) sweepIndicators(now time.Time) {
+	for room, byKey := range s.indicators {
+		for key, indicator := range byKey {
+			if now.After(indicator.ExpiresAt) {
+				delete(byKey, key)
+			}
+		}
+		if len(byKey) == 0 {
+			delete(s.indicators, room)
+		}
+	}
+	for key, last := range s.indicatorLast {
+		if now.Sub(last) > time.Minute {
+			delete(s.indicatorLast, key)
+		}
+	}
+}
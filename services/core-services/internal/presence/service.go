@@ -0,0 +1,279 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// 在线状态
+const (
+	StatusOnline  = "online"
+	StatusAway    = "away"
+	StatusDND     = "do_not_disturb"
+	StatusOffline = "offline"
+)
+
+// presenceRoom 所有在线状态订阅方共用的hub房间
+const presenceRoom = "presence"
+
+// heartbeatTTL 心跳过期时间，超时未续的用户视为离线
+const heartbeatTTL = 60 * time.Second
+
+// sweepInterval 过期状态清理间隔
+const sweepInterval = 30 * time.Second
+
+// Setting 每用户的在线状态隐私设置
+//
+// 隐身模式下对外永远报告离线，心跳照常接收以便用户
+// 自己的多端保持同步。
+type Setting struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID    string    `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	Invisible bool      `json:"invisible"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Setting) TableName() string {
+	return "cs_presence_settings"
+}
+
+// state 单个用户的内存态
+type state struct {
+	status    string
+	activity  string
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+// UserPresence 查询返回的单个用户状态
+type UserPresence struct {
+	UserID string `json:"userId"`
+	// Status online/away/do_not_disturb/offline
+	Status string `json:"status"`
+	// Activity 当前活动描述，如“正在编辑文档X”
+	Activity  string     `json:"activity,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Service 统一在线状态服务
+//
+// 状态保存在进程内并按TTL过期（与其他队列/缓存一致，本仓库
+// 不引入Redis），隐私设置落库持久化。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	states map[string]*state
+	// indicators 房间→（用户|类型）→活跃指示器
+	indicators map[string]map[string]*Indicator
+	// indicatorLast 指示器节流：键→最近一次发送时间
+	indicatorLast map[string]time.Time
+	hub           *realtime.Hub
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建在线状态服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Setting{}, &RoomSetting{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate presence tables: %w", err)
+	}
+	return &Service{
+		db:            db,
+		logger:        logger,
+		states:        make(map[string]*state),
+		indicators:    make(map[string]map[string]*Indicator),
+		indicatorLast: make(map[string]time.Time),
+		stopCh:        make(chan struct{}),
+	}, nil
+}
+
+// heartbeatPayload WebSocket心跳消息体
+type heartbeatPayload struct {
+	Status   string `json:"status"`
+	Activity string `json:"activity"`
+}
+
+// AttachHub 在WebSocket hub上挂接心跳处理
+//
+// 客户端加入presence房间后周期发送presence.heartbeat，状态
+// 变化广播给房间内其他订阅方（隐身用户不广播）。
+func (s *Service) AttachHub(hub *realtime.Hub) {
+	s.hub = hub
+	s.attachIndicatorHandler(hub)
+	hub.Handle("presence.heartbeat", func(client *realtime.Client, message *realtime.Message) (*realtime.Message, error) {
+		var payload heartbeatPayload
+		if len(message.Payload) > 0 {
+			if err := json.Unmarshal(message.Payload, &payload); err != nil {
+				return nil, fmt.Errorf("invalid heartbeat payload: %w", err)
+			}
+		}
+		presence, err := s.Heartbeat(client.Context(), client.UserID, payload.Status, payload.Activity)
+		if err != nil {
+			return nil, err
+		}
+		if presence.Status == StatusOffline {
+			// 隐身用户的心跳不对外广播
+			return nil, nil
+		}
+		raw, err := json.Marshal(presence)
+		if err != nil {
+			return nil, err
+		}
+		return &realtime.Message{Type: "presence.update", Payload: raw}, nil
+	})
+}
+
+// Heartbeat 记录一次心跳，返回对外可见的状态
+func (s *Service) Heartbeat(ctx context.Context, userID, status, activity string) (*UserPresence, error) {
+	if status == "" {
+		status = StatusOnline
+	}
+	switch status {
+	case StatusOnline, StatusAway, StatusDND:
+	default:
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+	now := time.Now()
+	s.mu.Lock()
+	s.states[userID] = &state{
+		status:    status,
+		activity:  activity,
+		updatedAt: now,
+		expiresAt: now.Add(heartbeatTTL),
+	}
+	s.mu.Unlock()
+	return s.visiblePresence(ctx, userID), nil
+}
+
+// Query 批量查询用户状态
+//
+// 隐身与过期用户统一报告offline，不暴露任何活动信息。
+func (s *Service) Query(ctx context.Context, userIDs []string) ([]*UserPresence, error) {
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("invalid query: userIds is empty")
+	}
+	if len(userIDs) > 200 {
+		return nil, fmt.Errorf("invalid query: maximum 200 userIds per request")
+	}
+	invisible, err := s.invisibleSet(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	result := make([]*UserPresence, 0, len(userIDs))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, userID := range userIDs {
+		presence := &UserPresence{UserID: userID, Status: StatusOffline}
+		if current, ok := s.states[userID]; ok && now.Before(current.expiresAt) && !invisible[userID] {
+			updatedAt := current.updatedAt
+			presence.Status = current.status
+			presence.Activity = current.activity
+			presence.UpdatedAt = &updatedAt
+		}
+		result = append(result, presence)
+	}
+	return result, nil
+}
+
+// visiblePresence 求单个用户对外可见的状态
+func (s *Service) visiblePresence(ctx context.Context, userID string) *UserPresence {
+	presences, err := s.Query(ctx, []string{userID})
+	if err != nil || len(presences) == 0 {
+		return &UserPresence{UserID: userID, Status: StatusOffline}
+	}
+	return presences[0]
+}
+
+// invisibleSet 查询给定用户中开启隐身的集合
+func (s *Service) invisibleSet(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	var settings []*Setting
+	err := s.db.WithContext(ctx).
+		Where("user_id IN ? AND invisible = ?", userIDs, true).
+		Find(&settings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load presence settings: %w", err)
+	}
+	invisible := make(map[string]bool, len(settings))
+	for _, setting := range settings {
+		invisible[setting.UserID] = true
+	}
+	return invisible, nil
+}
+
+// GetSetting 读取用户的隐私设置
+func (s *Service) GetSetting(ctx context.Context, userID string) (*Setting, error) {
+	var setting Setting
+	err := s.db.WithContext(ctx).First(&setting, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &Setting{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to load presence setting: %w", err)
+	}
+	return &setting, nil
+}
+
+// SetInvisible 开关隐身模式
+func (s *Service) SetInvisible(ctx context.Context, userID string, invisible bool) (*Setting, error) {
+	setting, err := s.GetSetting(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting.ID == "" {
+		setting.ID = uuid.NewString()
+	}
+	setting.Invisible = invisible
+	if err := s.db.WithContext(ctx).Save(setting).Error; err != nil {
+		return nil, fmt.Errorf("failed to save presence setting: %w", err)
+	}
+	return setting, nil
+}
+
+// Start 启动过期状态清理循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.sweep()
+			}
+		}
+	}()
+}
+
+// Stop 停止清理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// sweep 移除过期状态
+func (s *Service) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for userID, current := range s.states {
+		if now.After(current.expiresAt) {
+			delete(s.states, userID)
+		}
+	}
+	s.sweepIndicators(now)
+}
@@ -0,0 +1,121 @@
+package printing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler PDF导出HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建PDF导出处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册PDF导出路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/print")
+	{
+		group.POST("/jobs", h.CreateJob)
+		group.GET("/jobs", h.ListJobs)
+		group.GET("/jobs/:jobId", h.GetJob)
+		group.GET("/jobs/:jobId/download", h.Download)
+	}
+}
+
+// requireUser 从请求头解析用户ID
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return "", false
+	}
+	return userID, true
+}
+
+// statusForPrintError 根据错误内容映射HTTP状态码
+func statusForPrintError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "not ready"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateJob 提交导出作业
+func (h *Handler) CreateJob(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Kind      string `json:"kind" binding:"required"`
+		TargetID  string `json:"targetId"`
+		Watermark string `json:"watermark"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job, err := h.service.CreateJob(c.Request.Context(), userID, req.Kind, req.TargetID, req.Watermark)
+	if err != nil {
+		c.JSON(statusForPrintError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListJobs 列出本人的导出作业
+func (h *Handler) ListJobs(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	jobs, err := h.service.ListJobs(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(statusForPrintError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs, "total": len(jobs)})
+}
+
+// GetJob 查询作业状态
+func (h *Handler) GetJob(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	job, err := h.service.GetJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		c.JSON(statusForPrintError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Download 下载渲染结果
+func (h *Handler) Download(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	path, fileName, err := h.service.FilePath(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		c.JSON(statusForPrintError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.FileAttachment(path, fileName)
+}
@@ -0,0 +1,147 @@
+package printing
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+	"unicode/utf16"
+)
+
+// A4页面尺寸与边距（单位pt）
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 54.0
+)
+
+// pdfWriter 多页PDF组装器
+//
+// 文本统一走UniGB-UCS2-H编码的STSong CID字体，由查看器自带的
+// Adobe-GB1字库渲染，中日韩字符无须内嵌字体文件。
+type pdfWriter struct {
+	pages []*bytes.Buffer
+}
+
+// newPDFWriter 创建空文档（不含页面，首页需显式addPage）
+func newPDFWriter() *pdfWriter {
+	return &pdfWriter{}
+}
+
+// addPage 追加一页并作为当前页
+func (w *pdfWriter) addPage() {
+	w.pages = append(w.pages, &bytes.Buffer{})
+}
+
+// current 当前页的内容流
+func (w *pdfWriter) current() *bytes.Buffer {
+	return w.pages[len(w.pages)-1]
+}
+
+// text 在当前页输出一段文本
+func (w *pdfWriter) text(x, y float64, size int, text string) {
+	b := w.current()
+	fmt.Fprintf(b, "BT /F1 %d Tf %.1f %.1f Td <", size, x, y)
+	writeUCS2(b, text)
+	b.WriteString("> Tj ET\n")
+}
+
+// watermark 在当前页居中绘制45度灰色水印
+func (w *pdfWriter) watermark(text string) {
+	if text == "" {
+		return
+	}
+	b := w.current()
+	// 文本矩阵旋转45度（cos/sin≈0.707），浅灰填充不遮正文
+	fmt.Fprintf(b, "q 0.85 g BT /F1 42 Tf 0.707 0.707 -0.707 0.707 %.1f %.1f Tm <",
+		pageWidth/2-float64(len([]rune(text)))*15, pageHeight/3)
+	writeUCS2(b, text)
+	b.WriteString("> Tj ET Q\n")
+}
+
+// writeUCS2 文本按UTF-16BE写为十六进制串
+func writeUCS2(b *bytes.Buffer, text string) {
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(b, "%04X", unit)
+	}
+}
+
+// textWidth 估算文本排版宽度：CJK按全宽、其余按0.55em近似
+func textWidth(text string, size int) float64 {
+	var width float64
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || r >= 0x3000 && r <= 0x9FFF || r >= 0xFF00 && r <= 0xFFEF {
+			width += float64(size)
+		} else {
+			width += float64(size) * 0.55
+		}
+	}
+	return width
+}
+
+// wrapText 按可用宽度把文本折成多行
+func wrapText(text string, size int, maxWidth float64) []string {
+	runes := []rune(text)
+	var lines []string
+	var line []rune
+	var width float64
+	for _, r := range runes {
+		w := textWidth(string(r), size)
+		if width+w > maxWidth && len(line) > 0 {
+			lines = append(lines, string(line))
+			line, width = nil, 0
+		}
+		line = append(line, r)
+		width += w
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// bytes 组装为完整PDF文档
+func (w *pdfWriter) bytes() []byte {
+	if len(w.pages) == 0 {
+		w.addPage()
+	}
+	// 对象编号：1目录 2页面树 3-5字体 之后每页两个对象（页面+内容流）
+	const firstPageObj = 6
+	var kids bytes.Buffer
+	for i := range w.pages {
+		if i > 0 {
+			kids.WriteString(" ")
+		}
+		fmt.Fprintf(&kids, "%d 0 R", firstPageObj+2*i)
+	}
+
+	objects := [][]byte{
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(w.pages))),
+		[]byte("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>"),
+		[]byte("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 5 >> /FontDescriptor 5 0 R /DW 1000 >>"),
+		[]byte("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 4 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>"),
+	}
+	for i, page := range w.pages {
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPageObj+2*i+1)))
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.Bytes())))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects)+1)
+	for i, object := range objects {
+		offsets[i+1] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for i := 1; i <= len(objects); i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, xref)
+	return b.Bytes()
+}
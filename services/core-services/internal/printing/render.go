@@ -0,0 +1,87 @@
+package printing
+
+import "strings"
+
+// 排版字号
+const (
+	titleSize   = 18
+	metaSize    = 9
+	headingSize = 13
+	bodySize    = 10
+)
+
+// Document 待排版的结构化文档
+//
+// 各业务模块把自己的实体转成标题+元信息+分节的通用形态，
+// 排版与分页由本包统一处理。
+type Document struct {
+	Title string
+	// Meta 标题下方的元信息行（作者、时间等）
+	Meta     []string
+	Sections []Section
+}
+
+// Section 文档中的一节
+type Section struct {
+	// Heading 节标题，可为空
+	Heading string
+	Lines   []string
+}
+
+// SplitLines 把多行文本拆成Section行（保留空行作段落间隔）
+func SplitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// render 把文档排版为PDF，watermark非空时每页打水印
+func render(doc *Document, watermark string) []byte {
+	w := newPDFWriter()
+	contentWidth := pageWidth - 2*pageMargin
+	y := 0.0
+
+	newPage := func() {
+		w.addPage()
+		w.watermark(watermark)
+		y = pageHeight - pageMargin
+	}
+	// ensure 光标下移前确保剩余空间，不足则换页
+	ensure := func(need float64) {
+		if y-need < pageMargin {
+			newPage()
+		}
+	}
+	newPage()
+
+	for _, line := range wrapText(doc.Title, titleSize, contentWidth) {
+		ensure(24)
+		w.text(pageMargin, y-float64(titleSize), titleSize, line)
+		y -= 24
+	}
+	for _, meta := range doc.Meta {
+		ensure(13)
+		w.text(pageMargin, y-float64(metaSize), metaSize, meta)
+		y -= 13
+	}
+	y -= 10
+
+	for _, section := range doc.Sections {
+		if section.Heading != "" {
+			ensure(22)
+			y -= 8
+			w.text(pageMargin, y-float64(headingSize), headingSize, section.Heading)
+			y -= 20
+		}
+		for _, line := range section.Lines {
+			if line == "" {
+				y -= 7
+				continue
+			}
+			for _, wrapped := range wrapText(line, bodySize, contentWidth) {
+				ensure(14)
+				w.text(pageMargin, y-float64(bodySize), bodySize, wrapped)
+				y -= 14
+			}
+		}
+	}
+	return w.bytes()
+}
@@ -0,0 +1,268 @@
+package printing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 打印作业状态
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// 作业处理参数
+const (
+	// workInterval 待处理作业的轮询间隔
+	workInterval = 5 * time.Second
+	// workBatchSize 单轮处理的作业数上限
+	workBatchSize = 10
+	// watermarkMaxLen 水印文本长度上限
+	watermarkMaxLen = 64
+)
+
+// Job 一次PDF导出作业
+//
+// 渲染异步执行，客户端轮询状态并在success后走下载链接取文件。
+type Job struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// Kind 导出类型，如 wisdom_entry/task_board/health_report
+	Kind     string `json:"kind" gorm:"type:varchar(32);not null"`
+	TargetID string `json:"targetId" gorm:"type:varchar(36)"`
+	// Watermark 每页水印文本，为空时不加水印
+	Watermark string `json:"watermark,omitempty" gorm:"type:varchar(64)"`
+	Status    string `json:"status" gorm:"index;type:varchar(16);not null"`
+	Error     string `json:"error,omitempty" gorm:"type:varchar(512)"`
+	FileName  string `json:"fileName,omitempty" gorm:"type:varchar(128)"`
+	SizeBytes int64  `json:"sizeBytes,omitempty"`
+	// DownloadURL success后的下载链接，响应时计算不落库
+	DownloadURL string     `json:"downloadUrl,omitempty" gorm:"-"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (Job) TableName() string {
+	return "cs_print_jobs"
+}
+
+// SourceFunc 把业务实体取出并转成待排版文档
+type SourceFunc func(ctx context.Context, userID, targetID string) (*Document, error)
+
+// RenderFunc 自带排版的模块直接产出PDF字节（水印选项不生效）
+type RenderFunc func(ctx context.Context, userID, targetID string) ([]byte, error)
+
+// Service PDF导出服务
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	// dir 渲染结果的存放目录
+	dir       string
+	sources   map[string]SourceFunc
+	renderers map[string]RenderFunc
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建PDF导出服务
+func NewService(db *gorm.DB, dir string, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Job{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate printing tables: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create print directory: %w", err)
+	}
+	return &Service{
+		db:        db,
+		logger:    logger,
+		dir:       dir,
+		sources:   make(map[string]SourceFunc),
+		renderers: make(map[string]RenderFunc),
+		stopCh:    make(chan struct{}),
+	}, nil
+}
+
+// RegisterSource 注册一种结构化文档来源（启动期调用，不加锁）
+func (s *Service) RegisterSource(kind string, fn SourceFunc) {
+	s.sources[kind] = fn
+}
+
+// RegisterRenderer 注册一种自渲染来源
+func (s *Service) RegisterRenderer(kind string, fn RenderFunc) {
+	s.renderers[kind] = fn
+}
+
+// Start 启动作业处理循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(workInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.processPending(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止作业处理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// CreateJob 提交一次导出作业
+func (s *Service) CreateJob(ctx context.Context, userID, kind, targetID, watermark string) (*Job, error) {
+	_, hasSource := s.sources[kind]
+	_, hasRenderer := s.renderers[kind]
+	if !hasSource && !hasRenderer {
+		return nil, fmt.Errorf("invalid kind: %s", kind)
+	}
+	if len([]rune(watermark)) > watermarkMaxLen {
+		return nil, fmt.Errorf("invalid watermark: maximum %d characters", watermarkMaxLen)
+	}
+	job := &Job{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Kind:      kind,
+		TargetID:  targetID,
+		Watermark: watermark,
+		Status:    StatusPending,
+	}
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create print job: %w", err)
+	}
+	return job, nil
+}
+
+// GetJob 查询本人的作业
+func (s *Service) GetJob(ctx context.Context, userID, jobID string) (*Job, error) {
+	var job Job
+	err := s.db.WithContext(ctx).
+		First(&job, "id = ? AND user_id = ?", jobID, userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("print job not found: %s", jobID)
+		}
+		return nil, fmt.Errorf("failed to get print job: %w", err)
+	}
+	fillDownloadURL(&job)
+	return &job, nil
+}
+
+// ListJobs 列出本人的作业（新到旧）
+func (s *Service) ListJobs(ctx context.Context, userID string, limit int) ([]*Job, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	var jobs []*Job
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list print jobs: %w", err)
+	}
+	for _, job := range jobs {
+		fillDownloadURL(job)
+	}
+	return jobs, nil
+}
+
+// FilePath 取成功作业的文件路径与下载文件名
+func (s *Service) FilePath(ctx context.Context, userID, jobID string) (string, string, error) {
+	job, err := s.GetJob(ctx, userID, jobID)
+	if err != nil {
+		return "", "", err
+	}
+	if job.Status != StatusSuccess {
+		return "", "", fmt.Errorf("print job not ready: status is %s", job.Status)
+	}
+	return filepath.Join(s.dir, job.ID+".pdf"), job.FileName, nil
+}
+
+// fillDownloadURL 为成功作业补下载链接
+func fillDownloadURL(job *Job) {
+	if job.Status == StatusSuccess {
+		job.DownloadURL = "/api/v1/print/jobs/" + job.ID + "/download"
+	}
+}
+
+// processPending 取一批待处理作业并渲染
+func (s *Service) processPending(ctx context.Context) {
+	var jobs []*Job
+	err := s.db.WithContext(ctx).
+		Where("status = ?", StatusPending).
+		Order("created_at ASC").
+		Limit(workBatchSize).
+		Find(&jobs).Error
+	if err != nil {
+		s.logger.Error("failed to load pending print jobs", zap.Error(err))
+		return
+	}
+	for _, job := range jobs {
+		s.runJob(ctx, job)
+	}
+}
+
+// runJob 执行单个作业：渲染、落盘并更新状态
+func (s *Service) runJob(ctx context.Context, job *Job) {
+	if err := s.db.WithContext(ctx).Model(job).Update("status", StatusRunning).Error; err != nil {
+		s.logger.Warn("failed to claim print job", zap.String("id", job.ID), zap.Error(err))
+		return
+	}
+
+	body, fileName, err := s.renderJob(ctx, job)
+	now := time.Now()
+	if err != nil {
+		s.logger.Warn("print job failed",
+			zap.String("id", job.ID), zap.String("kind", job.Kind), zap.Error(err))
+		s.db.WithContext(ctx).Model(job).Updates(map[string]interface{}{
+			"status": StatusFailed, "error": err.Error(), "finished_at": now,
+		})
+		return
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, job.ID+".pdf"), body, 0o644); err != nil {
+		s.db.WithContext(ctx).Model(job).Updates(map[string]interface{}{
+			"status": StatusFailed, "error": "failed to store file", "finished_at": now,
+		})
+		return
+	}
+	s.db.WithContext(ctx).Model(job).Updates(map[string]interface{}{
+		"status": StatusSuccess, "file_name": fileName,
+		"size_bytes": int64(len(body)), "finished_at": now,
+	})
+}
+
+// renderJob 按作业类型产出PDF与下载文件名
+func (s *Service) renderJob(ctx context.Context, job *Job) ([]byte, string, error) {
+	if fn, ok := s.renderers[job.Kind]; ok {
+		body, err := fn(ctx, job.UserID, job.TargetID)
+		return body, job.Kind + ".pdf", err
+	}
+	fn, ok := s.sources[job.Kind]
+	if !ok {
+		return nil, "", fmt.Errorf("invalid kind: %s", job.Kind)
+	}
+	doc, err := fn(ctx, job.UserID, job.TargetID)
+	if err != nil {
+		return nil, "", err
+	}
+	return render(doc, job.Watermark), job.Kind + ".pdf", nil
+}
@@ -0,0 +1,92 @@
+package publicapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/cdn"
+)
+
+// cacheControl 公共响应的CDN友好缓存头
+const cacheControl = "public, max-age=60, s-maxage=300"
+
+// Handler 公共只读层HTTP处理器
+type Handler struct {
+	service *Service
+	limiter *limiter
+}
+
+// NewHandler 创建公共只读层处理器
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{service: service, limiter: newLimiter(logger)}
+}
+
+// SetRateLimit 调整单IP每分钟上限（运行时配置热更新用）
+func (h *Handler) SetRateLimit(perMinute int) {
+	h.limiter.setLimit(perMinute)
+}
+
+// SetupRoutes 注册公共路由（挂在免认证的分组下，自带IP限流）
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("", h.limiter.middleware())
+	{
+		group.GET("/trending", h.Trending)
+		group.GET("/wisdom", h.ListWisdom)
+		group.GET("/wisdom/daily", h.DailyWisdom)
+	}
+}
+
+// statusForPublicError 根据错误内容映射HTTP状态码
+func statusForPublicError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Trending 热门讨论
+func (h *Handler) Trending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	threads, err := h.service.TrendingThreads(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(statusForPublicError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Surrogate-Key", cdn.TagTrending)
+	c.JSON(http.StatusOK, gin.H{"threads": threads, "total": len(threads)})
+}
+
+// ListWisdom 已发布的智慧词条
+func (h *Handler) ListWisdom(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	entries, err := h.service.ListWisdom(c.Request.Context(), c.Query("category"), limit)
+	if err != nil {
+		c.JSON(statusForPublicError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Surrogate-Key", cdn.TagWisdom)
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "total": len(entries)})
+}
+
+// DailyWisdom 今天的每日一语
+func (h *Handler) DailyWisdom(c *gin.Context) {
+	daily, err := h.service.DailyWisdom(c.Request.Context())
+	if err != nil {
+		c.JSON(statusForPublicError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Surrogate-Key", cdn.TagDaily)
+	c.JSON(http.StatusOK, daily)
+}
@@ -0,0 +1,124 @@
+package publicapi
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// 限流与滥用判定参数
+const (
+	// rateLimitPerMinute 单IP每分钟请求数上限默认值，可运行时调整
+	rateLimitPerMinute = 60
+	// abuseStrikeThreshold 连续超限窗口数达到该值视为滥用
+	abuseStrikeThreshold = 5
+	// abuseBanDuration 滥用IP的封禁时长
+	abuseBanDuration = time.Hour
+	// limiterSweepAge 超过该时长未活跃的IP状态被清理
+	limiterSweepAge = 10 * time.Minute
+)
+
+// ipState 单IP的固定窗口计数与滥用记分
+type ipState struct {
+	windowStart time.Time
+	count       int
+	// strikes 连续超限的窗口数，正常窗口清零
+	strikes     int
+	bannedUntil time.Time
+	lastSeen    time.Time
+}
+
+// limiter 公共层的IP限流与滥用封禁
+//
+// 固定分钟窗口计数；连续多个窗口打满视为滥用，封禁一小时
+// 并记日志供上游防护（CDN/WAF）跟进。
+type limiter struct {
+	mu     sync.Mutex
+	ips    map[string]*ipState
+	limit  int
+	logger *zap.Logger
+}
+
+// newLimiter 创建限流器
+func newLimiter(logger *zap.Logger) *limiter {
+	return &limiter{ips: make(map[string]*ipState), limit: rateLimitPerMinute, logger: logger}
+}
+
+// setLimit 调整每分钟上限（运行时配置热更新用）
+func (l *limiter) setLimit(perMinute int) {
+	if perMinute < 1 {
+		return
+	}
+	l.mu.Lock()
+	l.limit = perMinute
+	l.mu.Unlock()
+}
+
+// allow 判定一次请求，返回是否放行、剩余额度与当前上限
+func (l *limiter) allow(ip string) (bool, int, int) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.ips) > 0 && now.Second() == 0 {
+		l.sweep(now)
+	}
+	state, ok := l.ips[ip]
+	if !ok {
+		state = &ipState{windowStart: now}
+		l.ips[ip] = state
+	}
+	state.lastSeen = now
+
+	if now.Before(state.bannedUntil) {
+		return false, 0, l.limit
+	}
+	if now.Sub(state.windowStart) >= time.Minute {
+		if state.count <= l.limit {
+			state.strikes = 0
+		}
+		state.windowStart = now
+		state.count = 0
+	}
+	state.count++
+	if state.count > l.limit {
+		if state.count == l.limit+1 {
+			state.strikes++
+			if state.strikes >= abuseStrikeThreshold {
+				state.bannedUntil = now.Add(abuseBanDuration)
+				l.logger.Warn("public api abuse detected, ip banned",
+					zap.String("ip", ip), zap.Time("until", state.bannedUntil))
+			}
+		}
+		return false, 0, l.limit
+	}
+	return true, l.limit - state.count, l.limit
+}
+
+// sweep 清理不活跃IP（须持锁调用）
+func (l *limiter) sweep(now time.Time) {
+	for ip, state := range l.ips {
+		if now.Sub(state.lastSeen) > limiterSweepAge && now.After(state.bannedUntil) {
+			delete(l.ips, ip)
+		}
+	}
+}
+
+// middleware 限流中间件，超限返回429
+func (l *limiter) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, remaining, limit := l.allow(c.ClientIP())
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !ok {
+			c.Header("Retry-After", "60")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
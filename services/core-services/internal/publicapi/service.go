@@ -0,0 +1,121 @@
+package publicapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 公共层查询限制
+const (
+	// trendingWindow 热门讨论的统计时间窗
+	trendingWindow = 7 * 24 * time.Hour
+	// maxListLimit 公共列表单页上限
+	maxListLimit = 50
+	// defaultListLimit 未指定时的页大小
+	defaultListLimit = 20
+)
+
+// PublicThread 热门讨论的公开视图（不含作者等内部字段）
+type PublicThread struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	PostCount int64     `json:"postCount"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PublicEntry 智慧词条的公开视图
+type PublicEntry struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Source      string     `json:"source,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+}
+
+// DailyContent 每日一语的公开视图
+type DailyContent struct {
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Source  string `json:"source,omitempty"`
+}
+
+// Service 面向匿名调用方的公共只读层
+//
+// 只暴露已发布内容的白名单字段，响应结构与内部API解耦；
+// 营销站与外部聚合方按此契约消费。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	// daily 每日一语来源，由main注入以复用内部选取逻辑
+	daily func(ctx context.Context) (*DailyContent, error)
+}
+
+// NewService 创建公共只读层
+func NewService(db *gorm.DB, logger *zap.Logger) *Service {
+	return &Service{db: db, logger: logger}
+}
+
+// SetDailyProvider 注入每日一语来源
+func (s *Service) SetDailyProvider(daily func(ctx context.Context) (*DailyContent, error)) {
+	s.daily = daily
+}
+
+// TrendingThreads 近期回复最多的讨论串
+func (s *Service) TrendingThreads(ctx context.Context, limit int) ([]*PublicThread, error) {
+	limit = clampLimit(limit)
+	var threads []*PublicThread
+	err := s.db.WithContext(ctx).
+		Table("cs_threads").
+		Select("id, title, post_count, updated_at").
+		Where("updated_at > ? AND is_locked = ?", time.Now().Add(-trendingWindow), false).
+		Order("post_count DESC, updated_at DESC").
+		Limit(limit).
+		Scan(&threads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trending threads: %w", err)
+	}
+	return threads, nil
+}
+
+// ListWisdom 已发布的智慧词条
+func (s *Service) ListWisdom(ctx context.Context, category string, limit int) ([]*PublicEntry, error) {
+	limit = clampLimit(limit)
+	query := s.db.WithContext(ctx).
+		Table("cs_wisdom_entries").
+		Select("id, title, content, source, category, published_at").
+		Where("status = ?", "published")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	var entries []*PublicEntry
+	err := query.Order("published_at DESC").Limit(limit).Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wisdom entries: %w", err)
+	}
+	return entries, nil
+}
+
+// DailyWisdom 今天的每日一语
+func (s *Service) DailyWisdom(ctx context.Context) (*DailyContent, error) {
+	if s.daily == nil {
+		return nil, fmt.Errorf("daily wisdom is not configured")
+	}
+	return s.daily(ctx)
+}
+
+// clampLimit 页大小收敛到公共层上限
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
@@ -0,0 +1,278 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Message WebSocket消息信封
+type Message struct {
+	Type    string          `json:"type"`
+	Room    string          `json:"room,omitempty"`
+	From    string          `json:"from,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// MessageHandler 房间内收到客户端消息时的回调
+//
+// 返回的消息（非nil时）会广播给房间内其他客户端，
+// 各业务模块（白板、信令等）通过注册回调挂接到同一个hub。
+type MessageHandler func(client *Client, message *Message) (*Message, error)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 512 * 1024
+	sendBuffer     = 64
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// 跨域控制由网关层统一处理
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Client hub中的一个WebSocket连接
+type Client struct {
+	UserID string
+	Room   string
+	hub    *Hub
+	conn   *websocket.Conn
+	send   chan []byte
+}
+
+// Context 返回处理该连接消息时使用的上下文
+func (c *Client) Context() context.Context {
+	return context.Background()
+}
+
+// Hub 按房间分组的WebSocket广播中心
+type Hub struct {
+	mu       sync.RWMutex
+	rooms    map[string]map[*Client]bool
+	handlers map[string]MessageHandler // 按消息类型分发
+	logger   *zap.Logger
+}
+
+// NewHub 创建WebSocket广播中心
+func NewHub(logger *zap.Logger) *Hub {
+	return &Hub{
+		rooms:    make(map[string]map[*Client]bool),
+		handlers: make(map[string]MessageHandler),
+		logger:   logger,
+	}
+}
+
+// Handle 注册某类型消息的处理回调
+func (h *Hub) Handle(messageType string, handler MessageHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[messageType] = handler
+}
+
+// ServeWS 把HTTP请求升级为WebSocket并加入房间
+func (h *Hub) ServeWS(c *gin.Context, room, userID string) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.Warn("websocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	client := &Client{
+		UserID: userID,
+		Room:   room,
+		hub:    h,
+		conn:   conn,
+		send:   make(chan []byte, sendBuffer),
+	}
+	h.register(client)
+
+	go client.writePump()
+	go client.readPump()
+}
+
+// Broadcast 向房间内所有客户端广播消息
+func (h *Hub) Broadcast(room string, message *Message) {
+	h.broadcast(room, message, nil)
+}
+
+// RoomMembers 列出房间内在线用户ID（去重）
+func (h *Hub) RoomMembers(room string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var members []string
+	for client := range h.rooms[room] {
+		if !seen[client.UserID] {
+			seen[client.UserID] = true
+			members = append(members, client.UserID)
+		}
+	}
+	return members
+}
+
+// ConnectionCount 当前在线连接总数
+func (h *Hub) ConnectionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	total := 0
+	for _, clients := range h.rooms {
+		total += len(clients)
+	}
+	return total
+}
+
+// SendToUser 向房间内指定用户的所有连接发送消息
+func (h *Hub) SendToUser(room, userID string, message *Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("failed to marshal message", zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.rooms[room] {
+		if client.UserID != userID {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			// 发送缓冲满时丢弃，避免拖垮整个房间
+		}
+	}
+}
+
+// register 客户端加入房间
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[client.Room] == nil {
+		h.rooms[client.Room] = make(map[*Client]bool)
+	}
+	h.rooms[client.Room][client] = true
+}
+
+// unregister 客户端离开房间
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if clients, ok := h.rooms[client.Room]; ok {
+		if clients[client] {
+			delete(clients, client)
+			close(client.send)
+		}
+		if len(clients) == 0 {
+			delete(h.rooms, client.Room)
+		}
+	}
+}
+
+// broadcast 向房间广播，except不为nil时跳过该客户端
+func (h *Hub) broadcast(room string, message *Message, except *Client) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("failed to marshal message", zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.rooms[room] {
+		if client == except {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+		}
+	}
+}
+
+// dispatch 按消息类型调用已注册的处理回调
+func (h *Hub) dispatch(client *Client, message *Message) {
+	h.mu.RLock()
+	handler, ok := h.handlers[message.Type]
+	h.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	reply, err := handler(client, message)
+	if err != nil {
+		h.logger.Warn("message handler failed",
+			zap.String("type", message.Type), zap.Error(err))
+		return
+	}
+	if reply != nil {
+		h.broadcast(client.Room, reply, client)
+	}
+}
+
+// readPump 读取客户端消息并分发
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			continue
+		}
+		message.Room = c.Room
+		message.From = c.UserID
+		c.hub.dispatch(c, &message)
+	}
+}
+
+// writePump 把待发送消息写入连接并维持心跳
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 服务注册中心HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建服务注册中心处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册服务注册中心路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/registry")
+	{
+		group.POST("/instances", h.Register)
+		group.PUT("/instances/:instanceId/heartbeat", h.Heartbeat)
+		group.DELETE("/instances/:instanceId", h.Deregister)
+		group.GET("/services", h.ListServices)
+		group.GET("/resolve", h.Resolve)
+	}
+}
+
+// statusForRegistryError 根据错误内容映射HTTP状态码
+func statusForRegistryError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "no healthy instance"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Register 注册服务实例
+func (h *Handler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	instance, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForRegistryError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, instance)
+}
+
+// Heartbeat 实例心跳续约
+func (h *Handler) Heartbeat(c *gin.Context) {
+	if err := h.service.Heartbeat(c.Request.Context(), c.Param("instanceId")); err != nil {
+		c.JSON(statusForRegistryError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "heartbeat recorded"})
+}
+
+// Deregister 注销服务实例
+func (h *Handler) Deregister(c *gin.Context) {
+	if err := h.service.Deregister(c.Request.Context(), c.Param("instanceId")); err != nil {
+		c.JSON(statusForRegistryError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "instance deregistered"})
+}
+
+// ListServices 列出全部服务与实例
+func (h *Handler) ListServices(c *gin.Context) {
+	services, err := h.service.ListServices(c.Request.Context())
+	if err != nil {
+		c.JSON(statusForRegistryError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"services": services})
+}
+
+// Resolve 按加权轮询解析一个健康实例（service=名称）
+func (h *Handler) Resolve(c *gin.Context) {
+	name := c.Query("service")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: service is required"})
+		return
+	}
+	instance, err := h.service.Resolve(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(statusForRegistryError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
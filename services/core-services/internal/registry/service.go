@@ -0,0 +1,298 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 探活与过期参数
+const (
+	// probeInterval 主动健康探测间隔
+	probeInterval = 30 * time.Second
+	// probeTimeout 单次探测超时
+	probeTimeout = 5 * time.Second
+	// unhealthyThreshold 连续失败该次数后摘除实例
+	unhealthyThreshold = 3
+	// heartbeatTTL 心跳超时：超过该时长未续约的实例视为下线
+	heartbeatTTL = 90 * time.Second
+	// defaultWeight 未指定时的实例权重
+	defaultWeight = 1
+)
+
+// Instance 一个注册的服务实例
+//
+// 实例通过注册接口上线并周期性心跳续约，网关按服务名解析到
+// 健康实例；摘除只影响路由，记录保留到心跳过期。
+type Instance struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// Service 服务名，如 core-services、ai-service
+	Service string `json:"service" gorm:"index;type:varchar(64);not null"`
+	// Address 实例基址，如 http://10.0.0.3:8080
+	Address string `json:"address" gorm:"type:varchar(255);not null"`
+	// HealthPath 健康检查路径，默认/health
+	HealthPath string `json:"healthPath" gorm:"type:varchar(128)"`
+	// Weight 加权轮询权重，>=1
+	Weight  int  `json:"weight"`
+	Healthy bool `json:"healthy"`
+	// FailCount 连续探测失败次数
+	FailCount    int       `json:"-"`
+	LastSeenAt   time.Time `json:"lastSeenAt" gorm:"index"`
+	RegisteredAt time.Time `json:"registeredAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Instance) TableName() string {
+	return "cs_service_instances"
+}
+
+// Service 服务注册中心
+//
+// 不依赖Consul/etcd，注册表落库、轮询状态存进程内；网关通过
+// resolve接口按加权轮询取健康实例。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	probe  *http.Client
+
+	// rrMu 保护currentWeight：实例ID → 平滑加权轮询的当前权重
+	rrMu          sync.Mutex
+	currentWeight map[string]int
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建服务注册中心
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Instance{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate registry tables: %w", err)
+	}
+	return &Service{
+		db:            db,
+		logger:        logger,
+		probe:         &http.Client{Timeout: probeTimeout},
+		currentWeight: make(map[string]int),
+		stopCh:        make(chan struct{}),
+	}, nil
+}
+
+// Start 启动健康探测与过期清理循环
+func (s *Service) Start() {
+	go func() {
+		ticker := time.NewTicker(probeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.probeAll(context.Background())
+				s.expireStale(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止后台循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// RegisterRequest 注册实例的参数
+type RegisterRequest struct {
+	Service    string `json:"service" binding:"required"`
+	Address    string `json:"address" binding:"required"`
+	HealthPath string `json:"healthPath"`
+	Weight     int    `json:"weight"`
+}
+
+// Register 注册或更新一个服务实例
+//
+// 同服务同地址重复注册视为同一实例的续约更新。
+func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*Instance, error) {
+	parsed, err := url.Parse(req.Address)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid address: must be an absolute URL")
+	}
+	weight := req.Weight
+	if weight <= 0 {
+		weight = defaultWeight
+	}
+	healthPath := req.HealthPath
+	if healthPath == "" {
+		healthPath = "/health"
+	}
+	if !strings.HasPrefix(healthPath, "/") {
+		return nil, fmt.Errorf("invalid health path: must start with /")
+	}
+
+	now := time.Now()
+	var instance Instance
+	err = s.db.WithContext(ctx).
+		First(&instance, "service = ? AND address = ?", req.Service, strings.TrimRight(req.Address, "/")).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load instance: %w", err)
+		}
+		instance = Instance{
+			ID:           uuid.NewString(),
+			Service:      req.Service,
+			Address:      strings.TrimRight(req.Address, "/"),
+			RegisteredAt: now,
+		}
+	}
+	instance.HealthPath = healthPath
+	instance.Weight = weight
+	instance.Healthy = true
+	instance.FailCount = 0
+	instance.LastSeenAt = now
+	if err := s.db.WithContext(ctx).Save(&instance).Error; err != nil {
+		return nil, fmt.Errorf("failed to register instance: %w", err)
+	}
+	return &instance, nil
+}
+
+// Heartbeat 实例心跳续约
+func (s *Service) Heartbeat(ctx context.Context, instanceID string) error {
+	result := s.db.WithContext(ctx).Model(&Instance{}).
+		Where("id = ?", instanceID).
+		Update("last_seen_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+	return nil
+}
+
+// Deregister 注销实例
+func (s *Service) Deregister(ctx context.Context, instanceID string) error {
+	result := s.db.WithContext(ctx).Delete(&Instance{}, "id = ?", instanceID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to deregister instance: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+	s.rrMu.Lock()
+	delete(s.currentWeight, instanceID)
+	s.rrMu.Unlock()
+	return nil
+}
+
+// ListServices 服务名 → 实例列表
+func (s *Service) ListServices(ctx context.Context) (map[string][]*Instance, error) {
+	var instances []*Instance
+	err := s.db.WithContext(ctx).Order("service ASC, registered_at ASC").Find(&instances).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+	services := make(map[string][]*Instance)
+	for _, instance := range instances {
+		services[instance.Service] = append(services[instance.Service], instance)
+	}
+	return services, nil
+}
+
+// Resolve 按平滑加权轮询取一个健康实例
+func (s *Service) Resolve(ctx context.Context, service string) (*Instance, error) {
+	var healthy []*Instance
+	err := s.db.WithContext(ctx).
+		Where("service = ? AND healthy = ?", service, true).
+		Find(&healthy).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instances: %w", err)
+	}
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("no healthy instance for service: %s", service)
+	}
+
+	// 平滑加权轮询：每次给各实例加自身权重，取当前值最大者
+	// 并减去总权重，权重高的实例被选中更频繁且分布均匀
+	s.rrMu.Lock()
+	defer s.rrMu.Unlock()
+	total := 0
+	var chosen *Instance
+	for _, instance := range healthy {
+		total += instance.Weight
+		s.currentWeight[instance.ID] += instance.Weight
+		if chosen == nil || s.currentWeight[instance.ID] > s.currentWeight[chosen.ID] {
+			chosen = instance
+		}
+	}
+	s.currentWeight[chosen.ID] -= total
+	return chosen, nil
+}
+
+// probeAll 探测全部实例并更新健康状态
+func (s *Service) probeAll(ctx context.Context) {
+	var instances []*Instance
+	if err := s.db.WithContext(ctx).Find(&instances).Error; err != nil {
+		s.logger.Error("failed to load instances for probing", zap.Error(err))
+		return
+	}
+	for _, instance := range instances {
+		ok := s.probeOne(ctx, instance)
+		updates := map[string]interface{}{}
+		if ok {
+			if !instance.Healthy {
+				s.logger.Info("service instance recovered",
+					zap.String("service", instance.Service), zap.String("address", instance.Address))
+			}
+			updates["healthy"] = true
+			updates["fail_count"] = 0
+		} else {
+			failCount := instance.FailCount + 1
+			updates["fail_count"] = failCount
+			if failCount >= unhealthyThreshold && instance.Healthy {
+				updates["healthy"] = false
+				s.logger.Warn("service instance removed from rotation",
+					zap.String("service", instance.Service), zap.String("address", instance.Address))
+			}
+		}
+		err := s.db.WithContext(ctx).Model(&Instance{}).
+			Where("id = ?", instance.ID).
+			Updates(updates).Error
+		if err != nil {
+			s.logger.Warn("failed to update instance health", zap.String("id", instance.ID), zap.Error(err))
+		}
+	}
+}
+
+// probeOne 对单个实例做一次HTTP健康检查
+func (s *Service) probeOne(ctx context.Context, instance *Instance) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, instance.Address+instance.HealthPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := s.probe.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// expireStale 删除心跳过期的实例
+func (s *Service) expireStale(ctx context.Context) {
+	cutoff := time.Now().Add(-heartbeatTTL)
+	result := s.db.WithContext(ctx).
+		Where("last_seen_at < ?", cutoff).
+		Delete(&Instance{})
+	if result.Error != nil {
+		s.logger.Warn("failed to expire stale instances", zap.Error(result.Error))
+	} else if result.RowsAffected > 0 {
+		s.logger.Info("expired stale service instances", zap.Int64("count", result.RowsAffected))
+	}
+}
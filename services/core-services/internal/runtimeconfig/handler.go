@@ -0,0 +1,61 @@
+package runtimeconfig
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 运行时配置HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建运行时配置处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册运行时配置路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/runtime-config", h.Show)
+	router.POST("/runtime-config/reload", h.Reload)
+}
+
+// Show 已订阅键的当前生效值
+func (h *Handler) Show(c *gin.Context) {
+	values, source, loadedAt := h.service.Snapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"values":   values,
+		"source":   source,
+		"loadedAt": loadedAt,
+	})
+}
+
+// Reload 强制重载配置来源
+func (h *Handler) Reload(c *gin.Context) {
+	if err := h.service.Reload(true); err != nil {
+		c.JSON(statusForRuntimeConfigError(err), gin.H{"error": err.Error()})
+		return
+	}
+	values, source, loadedAt := h.service.Snapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"values":   values,
+		"source":   source,
+		"loadedAt": loadedAt,
+	})
+}
+
+// statusForRuntimeConfigError 运行时配置错误到HTTP状态码
+func statusForRuntimeConfigError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not configured"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,265 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// reloadInterval 配置来源变更检查间隔
+const reloadInterval = 10 * time.Second
+
+// Source 运行时配置来源
+//
+// 默认实现监视本地YAML文件；etcd/Consul等远端来源实现
+// 同一接口即可接入订阅链路，服务侧无须改动。
+type Source interface {
+	// Name 来源描述（日志与stats端点用）
+	Name() string
+	// Changed 自上次加载后是否有变更
+	Changed() (bool, error)
+	// Load 读取全量键值
+	Load() (map[string]interface{}, error)
+}
+
+// fileSource 本地YAML文件来源，mtime变更触发重载
+type fileSource struct {
+	path    string
+	modTime time.Time
+}
+
+// Name 来源描述
+func (f *fileSource) Name() string {
+	return "file:" + f.path
+}
+
+// Changed 比较文件mtime
+func (f *fileSource) Changed() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return false, nil
+	}
+	f.modTime = info.ModTime()
+	return true, nil
+}
+
+// Load 读取并解析文件
+func (f *fileSource) Load() (map[string]interface{}, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid runtime config: %w", err)
+	}
+	return values, nil
+}
+
+// subscriber 单订阅的校验与应用回调
+type subscriber struct {
+	validate func(value interface{}) error
+	apply    func(value interface{})
+}
+
+// Service 运行时配置热更新
+//
+// 周期性检查配置来源，已订阅键的变更先经校验再通知订阅方，
+// 日志级别、限流阈值等可在不重启进程的前提下调整。校验
+// 失败的变更保留旧值并记日志；未订阅的键不参与比较。
+type Service struct {
+	source   Source
+	logger   *zap.Logger
+	mu       sync.Mutex
+	values   map[string]interface{}
+	subs     map[string][]subscriber
+	loadedAt time.Time
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建运行时配置服务；path为空时服务不监视任何来源
+func NewService(path string, logger *zap.Logger) (*Service, error) {
+	s := &Service{
+		logger: logger,
+		values: make(map[string]interface{}),
+		subs:   make(map[string][]subscriber),
+		stopCh: make(chan struct{}),
+	}
+	if path == "" {
+		return s, nil
+	}
+	source := &fileSource{path: path}
+	if _, err := source.Changed(); err != nil {
+		return nil, fmt.Errorf("stat runtime config: %w", err)
+	}
+	values, err := source.Load()
+	if err != nil {
+		return nil, err
+	}
+	s.source = source
+	s.values = values
+	s.loadedAt = time.Now()
+	return s, nil
+}
+
+// Subscribe 订阅某键的变更；validate可为nil，apply在变更通过校验后调用
+func (s *Service) Subscribe(key string, validate func(value interface{}) error, apply func(value interface{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs[key] = append(s.subs[key], subscriber{validate: validate, apply: apply})
+}
+
+// Start 启动变更检查循环
+func (s *Service) Start() {
+	if s.source == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Reload(false); err != nil {
+					s.logger.Warn("runtime config reload failed", zap.Error(err))
+				}
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止变更检查循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Reload 检查来源并应用变更；force为true时跳过变更检测
+func (s *Service) Reload(force bool) error {
+	if s.source == nil {
+		return fmt.Errorf("runtime config source not configured")
+	}
+	changed, err := s.source.Changed()
+	if err != nil {
+		return err
+	}
+	if !changed && !force {
+		return nil
+	}
+	values, err := s.source.Load()
+	if err != nil {
+		return err
+	}
+	s.applyChanges(values)
+	return nil
+}
+
+// applyChanges 比较已订阅键并通知订阅方
+func (s *Service) applyChanges(values map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, subs := range s.subs {
+		value, ok := values[key]
+		if !ok || reflect.DeepEqual(s.values[key], value) {
+			continue
+		}
+		rejected := false
+		for _, sub := range subs {
+			if sub.validate == nil {
+				continue
+			}
+			if err := sub.validate(value); err != nil {
+				s.logger.Warn("runtime config change rejected",
+					zap.String("key", key), zap.Error(err))
+				rejected = true
+				break
+			}
+		}
+		if rejected {
+			continue
+		}
+		s.values[key] = value
+		for _, sub := range subs {
+			sub.apply(value)
+		}
+		s.logger.Info("runtime config applied",
+			zap.String("key", key), zap.Any("value", value))
+	}
+	s.loadedAt = time.Now()
+}
+
+// Snapshot 已订阅键的当前生效值与来源信息
+func (s *Service) Snapshot() (map[string]interface{}, string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watched := make(map[string]interface{}, len(s.subs))
+	for key := range s.subs {
+		if value, ok := s.values[key]; ok {
+			watched[key] = value
+		}
+	}
+	name := ""
+	if s.source != nil {
+		name = s.source.Name()
+	}
+	return watched, name, s.loadedAt
+}
+
+// ValidateEnum 枚举校验器
+func ValidateEnum(allowed ...string) func(value interface{}) error {
+	return func(value interface{}) error {
+		v, ok := StringValue(value)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+		for _, candidate := range allowed {
+			if v == candidate {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %q not in %v", v, allowed)
+	}
+}
+
+// ValidatePositiveInt 正整数校验器
+func ValidatePositiveInt(value interface{}) error {
+	v, ok := IntValue(value)
+	if !ok {
+		return fmt.Errorf("expected integer, got %T", value)
+	}
+	if v <= 0 {
+		return fmt.Errorf("value %d must be positive", v)
+	}
+	return nil
+}
+
+// StringValue 取字符串值
+func StringValue(value interface{}) (string, bool) {
+	v, ok := value.(string)
+	return v, ok
+}
+
+// IntValue 取整数值
+func IntValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,174 @@
+package sandbox
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecordSize 单条沙箱数据的最大字节数
+const maxRecordSize = 64 * 1024
+
+// Handler 沙箱HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建沙箱处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册沙箱相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	admin := router.Group("/sandboxes")
+	{
+		admin.POST("", h.Create)
+		admin.GET("", h.List)
+		admin.DELETE("/:sandboxId", h.Delete)
+	}
+
+	// 数据接口以X-Sandbox-Token认证，与生产数据完全隔离
+	data := router.Group("/sandbox/data", h.authMiddleware())
+	{
+		data.GET("/:collection", h.ListRecords)
+		data.POST("/:collection", h.CreateRecord)
+		data.PUT("/:collection/:recordId", h.UpdateRecord)
+		data.DELETE("/:collection/:recordId", h.DeleteRecord)
+	}
+}
+
+// authMiddleware 校验X-Sandbox-Token并把沙箱放入上下文
+func (h *Handler) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimSpace(c.GetHeader("X-Sandbox-Token"))
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing X-Sandbox-Token header"})
+			return
+		}
+
+		sandbox, err := h.service.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set("sandbox", sandbox)
+		c.Next()
+	}
+}
+
+// contextSandbox 取出中间件放入的沙箱
+func contextSandbox(c *gin.Context) *Sandbox {
+	value, _ := c.Get("sandbox")
+	sandbox, _ := value.(*Sandbox)
+	return sandbox
+}
+
+// Create 创建沙箱
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateSandboxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "ttl exceeds") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// List 列出沙箱
+func (h *Handler) List(c *gin.Context) {
+	sandboxes, err := h.service.List(c.Request.Context(), c.Query("owner_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sandboxes": sandboxes})
+}
+
+// Delete 立即清除沙箱
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("sandboxId")); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "purged"})
+}
+
+// ListRecords 列出集合数据
+func (h *Handler) ListRecords(c *gin.Context) {
+	records, err := h.service.ListRecords(c.Request.Context(),
+		contextSandbox(c).ID, c.Param("collection"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+// CreateRecord 写入数据
+func (h *Handler) CreateRecord(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRecordSize))
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request body must be a JSON document"})
+		return
+	}
+
+	record, err := h.service.CreateRecord(c.Request.Context(),
+		contextSandbox(c).ID, c.Param("collection"), data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, record)
+}
+
+// UpdateRecord 更新数据
+func (h *Handler) UpdateRecord(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRecordSize))
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request body must be a JSON document"})
+		return
+	}
+
+	record, err := h.service.UpdateRecord(c.Request.Context(),
+		contextSandbox(c).ID, c.Param("recordId"), data)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// DeleteRecord 删除数据
+func (h *Handler) DeleteRecord(c *gin.Context) {
+	err := h.service.DeleteRecord(c.Request.Context(),
+		contextSandbox(c).ID, c.Param("recordId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
@@ -0,0 +1,325 @@
+package sandbox
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 沙箱状态
+const (
+	StatusActive = "active"
+	StatusPurged = "purged"
+)
+
+// defaultTTL 未指定时沙箱的存活时长
+const defaultTTL = 4 * time.Hour
+
+// maxTTL 沙箱允许的最长存活时长
+const maxTTL = 72 * time.Hour
+
+// Sandbox 第三方开发者的隔离测试环境
+//
+// 令牌只保存哈希；沙箱内的全部写入都落在cs_sandbox_records，
+// 过期后由清理循环整体删除，不会触碰生产表。
+type Sandbox struct {
+	ID        string    `json:"id" gorm:"type:varchar(36);primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(128);not null"`
+	OwnerID   string    `json:"ownerId" gorm:"type:varchar(36);index;not null"`
+	TokenHash string    `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Status    string    `json:"status" gorm:"type:varchar(16);default:'active';index"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"index"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Sandbox) TableName() string {
+	return "cs_sandboxes"
+}
+
+// Record 沙箱内的一条数据，按collection分组模拟业务资源
+type Record struct {
+	ID         string         `json:"id" gorm:"type:varchar(36);primaryKey"`
+	SandboxID  string         `json:"sandboxId" gorm:"type:varchar(36);index;not null"`
+	Collection string         `json:"collection" gorm:"type:varchar(64);index;not null"`
+	Data       datatypes.JSON `json:"data"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Record) TableName() string {
+	return "cs_sandbox_records"
+}
+
+// CreateSandboxRequest 创建沙箱请求
+type CreateSandboxRequest struct {
+	Name       string `json:"name" binding:"required"`
+	OwnerID    string `json:"ownerId" binding:"required"`
+	TTLMinutes int    `json:"ttlMinutes"`
+}
+
+// CreatedSandbox 创建结果，Token仅此一次下发
+type CreatedSandbox struct {
+	Sandbox *Sandbox `json:"sandbox"`
+	Token   string   `json:"token"`
+}
+
+// Service 沙箱服务
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建沙箱服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Sandbox{}, &Record{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate sandbox tables: %w", err)
+	}
+	return &Service{
+		db:     db,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 启动过期清理循环
+func (s *Service) Start() {
+	go s.purgeLoop()
+}
+
+// Stop 停止清理循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// purgeLoop 定期删除过期沙箱及其数据
+func (s *Service) purgeLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			if err := s.purgeExpired(context.Background()); err != nil {
+				s.logger.Error("sandbox purge failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// purgeExpired 清除全部过期沙箱
+func (s *Service) purgeExpired(ctx context.Context) error {
+	var expired []*Sandbox
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", StatusActive, time.Now()).
+		Find(&expired).Error; err != nil {
+		return fmt.Errorf("failed to list expired sandboxes: %w", err)
+	}
+
+	for _, sandbox := range expired {
+		if err := s.purge(ctx, sandbox); err != nil {
+			return err
+		}
+		s.logger.Info("sandbox purged",
+			zap.String("sandbox", sandbox.ID), zap.String("owner", sandbox.OwnerID))
+	}
+	return nil
+}
+
+// purge 删除沙箱数据并标记为已清除
+func (s *Service) purge(ctx context.Context, sandbox *Sandbox) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sandbox_id = ?", sandbox.ID).Delete(&Record{}).Error; err != nil {
+			return fmt.Errorf("failed to delete sandbox records: %w", err)
+		}
+		if err := tx.Model(sandbox).Update("status", StatusPurged).Error; err != nil {
+			return fmt.Errorf("failed to mark sandbox purged: %w", err)
+		}
+		return nil
+	})
+}
+
+// Create 创建沙箱并播种示例数据，令牌明文仅返回一次
+func (s *Service) Create(ctx context.Context, req *CreateSandboxRequest) (*CreatedSandbox, error) {
+	ttl := defaultTTL
+	if req.TTLMinutes > 0 {
+		ttl = time.Duration(req.TTLMinutes) * time.Minute
+	}
+	if ttl > maxTTL {
+		return nil, fmt.Errorf("ttl exceeds maximum of %s", maxTTL)
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate sandbox token: %w", err)
+	}
+	token := "sbx_" + hex.EncodeToString(buf)
+
+	sandbox := &Sandbox{
+		ID:        uuid.NewString(),
+		Name:      req.Name,
+		OwnerID:   req.OwnerID,
+		TokenHash: hashToken(token),
+		Status:    StatusActive,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(sandbox).Error; err != nil {
+			return fmt.Errorf("failed to create sandbox: %w", err)
+		}
+		return seedRecords(tx, sandbox.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreatedSandbox{Sandbox: sandbox, Token: token}, nil
+}
+
+// List 列出某开发者的沙箱
+func (s *Service) List(ctx context.Context, ownerID string) ([]*Sandbox, error) {
+	var sandboxes []*Sandbox
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	if ownerID != "" {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	if err := query.Find(&sandboxes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sandboxes: %w", err)
+	}
+	return sandboxes, nil
+}
+
+// Delete 立即清除沙箱
+func (s *Service) Delete(ctx context.Context, id string) error {
+	var sandbox Sandbox
+	if err := s.db.WithContext(ctx).First(&sandbox, "id = ? AND status = ?", id, StatusActive).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("sandbox not found: %s", id)
+		}
+		return fmt.Errorf("failed to load sandbox: %w", err)
+	}
+	return s.purge(ctx, &sandbox)
+}
+
+// Authenticate 校验沙箱令牌并返回对应沙箱
+func (s *Service) Authenticate(ctx context.Context, token string) (*Sandbox, error) {
+	var sandbox Sandbox
+	err := s.db.WithContext(ctx).
+		First(&sandbox, "token_hash = ? AND status = ?", hashToken(token), StatusActive).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invalid sandbox token")
+		}
+		return nil, fmt.Errorf("failed to look up sandbox: %w", err)
+	}
+	if time.Now().After(sandbox.ExpiresAt) {
+		return nil, fmt.Errorf("sandbox expired")
+	}
+	return &sandbox, nil
+}
+
+// ListRecords 列出沙箱内某集合的数据
+func (s *Service) ListRecords(ctx context.Context, sandboxID, collection string) ([]*Record, error) {
+	var records []*Record
+	if err := s.db.WithContext(ctx).
+		Where("sandbox_id = ? AND collection = ?", sandboxID, collection).
+		Order("created_at").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sandbox records: %w", err)
+	}
+	return records, nil
+}
+
+// CreateRecord 在沙箱内写入一条数据
+func (s *Service) CreateRecord(ctx context.Context, sandboxID, collection string, data []byte) (*Record, error) {
+	record := &Record{
+		ID:         uuid.NewString(),
+		SandboxID:  sandboxID,
+		Collection: collection,
+		Data:       datatypes.JSON(data),
+	}
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create sandbox record: %w", err)
+	}
+	return record, nil
+}
+
+// UpdateRecord 更新沙箱内的一条数据
+func (s *Service) UpdateRecord(ctx context.Context, sandboxID, recordID string, data []byte) (*Record, error) {
+	var record Record
+	err := s.db.WithContext(ctx).
+		First(&record, "id = ? AND sandbox_id = ?", recordID, sandboxID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("record not found: %s", recordID)
+		}
+		return nil, fmt.Errorf("failed to load sandbox record: %w", err)
+	}
+
+	record.Data = datatypes.JSON(data)
+	if err := s.db.WithContext(ctx).Save(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to update sandbox record: %w", err)
+	}
+	return &record, nil
+}
+
+// DeleteRecord 删除沙箱内的一条数据
+func (s *Service) DeleteRecord(ctx context.Context, sandboxID, recordID string) error {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND sandbox_id = ?", recordID, sandboxID).Delete(&Record{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete sandbox record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("record not found: %s", recordID)
+	}
+	return nil
+}
+
+// seedRecords 为新沙箱播种示例数据，让开发者开箱即有可读内容
+func seedRecords(tx *gorm.DB, sandboxID string) error {
+	seeds := map[string][]string{
+		"tasks": {
+			`{"title":"体验示例任务","status":"todo","priority":"medium"}`,
+			`{"title":"集成回调联调","status":"in_progress","priority":"high"}`,
+		},
+		"threads": {
+			`{"title":"欢迎使用沙箱","content":"这里的全部数据与生产环境完全隔离。"}`,
+		},
+	}
+	for collection, rows := range seeds {
+		for _, row := range rows {
+			record := &Record{
+				ID:         uuid.NewString(),
+				SandboxID:  sandboxID,
+				Collection: collection,
+				Data:       datatypes.JSON(row),
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return fmt.Errorf("failed to seed sandbox data: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// hashToken 计算令牌的存储哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
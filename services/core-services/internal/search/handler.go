@@ -0,0 +1,76 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 搜索HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建搜索处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册搜索相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.GET("/search", h.Search)
+	router.PUT("/search/documents", h.IndexDocument)
+}
+
+// Search 统一全文检索入口
+//
+// 参数：q关键词（必填）、types类型过滤（逗号分隔）、scope范围过滤、
+// from/size分页。
+func (h *Handler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			types = append(types, strings.TrimSpace(t))
+		}
+	}
+	from, _ := strconv.Atoi(c.DefaultQuery("from", "0"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	result, err := h.service.Search(c.Request.Context(), query, types, c.Query("scope"), from, size)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not configured") {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// IndexDocument 手动写入文档（批量回填、智慧内容接入用）
+func (h *Handler) IndexDocument(c *gin.Context) {
+	var doc Document
+	if err := c.ShouldBindJSON(&doc); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if doc.ID == "" || doc.Type == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id and type are required"})
+		return
+	}
+
+	if err := h.service.IndexDocument(c.Request.Context(), &doc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "document indexed"})
+}
@@ -0,0 +1,176 @@
+package search
+
+import "strings"
+
+// 中文检索归一化
+//
+// 不引入完整的拼音/繁简词典依赖，内置高频字表：索引与查询两侧
+// 都先做繁→简归一，再为标题与正文生成拼音字段（逐字音节加上
+// 整串连写，"laozi"与"lao zi"都能命中「老子」）。表外的字保持
+// 原样，不影响普通中文检索。
+
+// t2sTable 常用繁体→简体映射
+var t2sTable = map[rune]rune{
+	'萬': '万', '與': '与', '專': '专', '業': '业', '東': '东', '絲': '丝',
+	'兩': '两', '嚴': '严', '喪': '丧', '個': '个', '豐': '丰', '臨': '临',
+	'為': '为', '麗': '丽', '舉': '举', '義': '义', '樂': '乐', '習': '习',
+	'鄉': '乡', '書': '书', '買': '买', '亂': '乱', '爭': '争', '於': '于',
+	'雲': '云', '亞': '亚', '產': '产', '親': '亲', '億': '亿', '僅': '仅',
+	'從': '从', '倉': '仓', '儀': '仪', '們': '们', '價': '价', '眾': '众',
+	'優': '优', '會': '会', '傳': '传', '傷': '伤', '倫': '伦', '體': '体',
+	'餘': '余', '佛': '佛', '作': '作', '侶': '侣', '側': '侧', '偉': '伟',
+	'備': '备', '傢': '家', '儉': '俭', '修': '修', '倆': '俩', '關': '关',
+	'興': '兴', '養': '养', '獸': '兽', '內': '内', '岡': '冈', '冊': '册',
+	'軍': '军', '農': '农', '馮': '冯', '決': '决', '況': '况', '凍': '冻',
+	'淨': '净', '準': '准', '幾': '几', '鳳': '凤', '憑': '凭', '擊': '击',
+	'劃': '划', '則': '则', '剛': '刚', '創': '创', '務': '务', '動': '动',
+	'勵': '励', '勸': '劝', '辦': '办', '勢': '势', '勤': '勤', '區': '区',
+	'醫': '医', '華': '华', '協': '协', '單': '单', '賣': '卖', '盧': '卢',
+	'衛': '卫', '廠': '厂', '歷': '历', '壓': '压', '廳': '厅', '縣': '县',
+	'參': '参', '雙': '双', '發': '发', '變': '变', '敘': '叙', '臺': '台',
+	'號': '号', '嘆': '叹', '後': '后', '嚇': '吓', '聽': '听', '員': '员',
+	'問': '问', '啟': '启', '嗚': '呜', '響': '响', '國': '国', '圖': '图',
+	'園': '园', '圓': '圆', '場': '场', '壞': '坏', '塊': '块', '堅': '坚',
+	'壇': '坛', '報': '报', '壽': '寿', '夢': '梦', '頭': '头', '誇': '夸',
+	'奪': '夺', '奮': '奋', '婦': '妇', '媽': '妈', '婁': '娄', '嬰': '婴',
+	'學': '学', '寧': '宁', '實': '实', '審': '审', '寫': '写', '寬': '宽',
+	'對': '对', '導': '导', '將': '将', '爾': '尔', '塵': '尘', '嘗': '尝',
+	'層': '层', '屬': '属', '歲': '岁', '師': '师', '帶': '带', '幫': '帮',
+	'廣': '广', '慶': '庆', '應': '应', '廢': '废', '開': '开', '異': '异',
+	'棄': '弃', '張': '张', '強': '强', '歸': '归', '當': '当', '錄': '录',
+	'彥': '彦', '徹': '彻', '徑': '径', '憶': '忆', '態': '态', '懷': '怀',
+	'總': '总', '惡': '恶', '愛': '爱', '憲': '宪', '慮': '虑', '戰': '战',
+	'戲': '戏', '戶': '户', '擔': '担', '據': '据', '擇': '择', '揮': '挥',
+	'損': '损', '換': '换', '擁': '拥', '攝': '摄', '敗': '败', '敵': '敌',
+	'數': '数', '斷': '断', '時': '时', '曉': '晓', '晝': '昼', '顯': '显',
+	'術': '术', '機': '机', '權': '权', '條': '条', '來': '来', '極': '极',
+	'構': '构', '標': '标', '樣': '样', '橋': '桥', '檢': '检', '榮': '荣',
+	'樹': '树', '歐': '欧', '歡': '欢', '氣': '气', '漢': '汉', '沒': '没',
+	'澤': '泽', '濟': '济', '測': '测', '渾': '浑', '灣': '湾', '滿': '满',
+	'濕': '湿', '燈': '灯', '點': '点', '煉': '炼', '熱': '热', '爺': '爷',
+	'牆': '墙', '獨': '独', '獲': '获', '現': '现', '環': '环', '瑪': '玛',
+	'電': '电', '畫': '画', '疑': '疑', '療': '疗', '盡': '尽', '監': '监',
+	'蓋': '盖', '盤': '盘', '確': '确', '礎': '础', '禮': '礼', '禪': '禅',
+	'離': '离', '種': '种', '積': '积', '穩': '稳', '窮': '穷', '競': '竞',
+	'筆': '笔', '節': '节', '範': '范', '簡': '简', '類': '类', '經': '经',
+	'結': '结', '給': '给', '絕': '绝', '統': '统', '繼': '继', '續': '续',
+	'網': '网', '緣': '缘', '編': '编', '聖': '圣', '聯': '联', '聲': '声',
+	'職': '职', '臟': '脏', '舊': '旧', '藝': '艺', '藥': '药', '蘇': '苏',
+	'蘭': '兰', '處': '处', '衝': '冲', '補': '补', '裝': '装', '見': '见',
+	'觀': '观', '規': '规', '視': '视', '覺': '觉', '計': '计', '訂': '订',
+	'認': '认', '討': '讨', '訓': '训', '記': '记', '講': '讲', '許': '许',
+	'論': '论', '設': '设', '訪': '访', '評': '评', '識': '识', '詩': '诗',
+	'話': '话', '詳': '详', '語': '语', '誠': '诚', '誤': '误', '說': '说',
+	'請': '请', '諸': '诸', '謂': '谓', '謝': '谢', '譯': '译', '議': '议',
+	'護': '护', '讀': '读', '貝': '贝', '負': '负', '貢': '贡', '財': '财',
+	'責': '责', '質': '质', '購': '购', '貴': '贵', '費': '费', '資': '资',
+	'賽': '赛', '趙': '赵', '車': '车', '轉': '转', '輕': '轻', '較': '较',
+	'輸': '输', '辭': '辞', '達': '达', '過': '过', '運': '运', '還': '还',
+	'這': '这', '進': '进', '遠': '远', '違': '违', '連': '连', '週': '周',
+	'遺': '遗', '選': '选', '邊': '边', '鄧': '邓', '鄭': '郑', '釋': '释',
+	'裡': '里', '重': '重', '針': '针', '錢': '钱', '鋼': '钢', '銀': '银',
+	'錯': '错', '鍵': '键', '鎮': '镇', '長': '长', '門': '门', '閉': '闭',
+	'間': '间', '閱': '阅', '陳': '陈', '陽': '阳', '階': '阶', '際': '际',
+	'隨': '随', '隱': '隐', '雖': '虽', '雞': '鸡', '難': '难', '靈': '灵',
+	'靜': '静', '靠': '靠', '頁': '页', '頂': '顶', '項': '项', '順': '顺',
+	'預': '预', '領': '领', '頻': '频', '題': '题', '額': '额', '風': '风',
+	'飛': '飞', '館': '馆', '馬': '马', '駕': '驾', '驗': '验', '驚': '惊',
+	'高': '高', '魚': '鱼', '鳥': '鸟', '麥': '麦', '黃': '黄', '黨': '党',
+	'齊': '齐', '龍': '龙', '龜': '龟',
+}
+
+// pinyinTable 高频汉字→拼音音节（无声调）
+var pinyinTable = map[rune]string{
+	'的': "de", '一': "yi", '是': "shi", '了': "le", '我': "wo", '不': "bu",
+	'人': "ren", '在': "zai", '他': "ta", '有': "you", '这': "zhe", '个': "ge",
+	'上': "shang", '们': "men", '来': "lai", '到': "dao", '时': "shi", '大': "da",
+	'地': "di", '为': "wei", '子': "zi", '中': "zhong", '你': "ni", '说': "shuo",
+	'生': "sheng", '国': "guo", '年': "nian", '着': "zhe", '就': "jiu", '那': "na",
+	'和': "he", '要': "yao", '她': "ta", '出': "chu", '也': "ye", '得': "de",
+	'里': "li", '后': "hou", '自': "zi", '以': "yi", '会': "hui", '家': "jia",
+	'可': "ke", '下': "xia", '而': "er", '过': "guo", '天': "tian", '去': "qu",
+	'能': "neng", '对': "dui", '小': "xiao", '多': "duo", '然': "ran", '于': "yu",
+	'心': "xin", '学': "xue", '么': "me", '之': "zhi", '都': "dou", '好': "hao",
+	'看': "kan", '起': "qi", '发': "fa", '当': "dang", '没': "mei", '成': "cheng",
+	'只': "zhi", '如': "ru", '事': "shi", '把': "ba", '还': "hai", '用': "yong",
+	'第': "di", '样': "yang", '道': "dao", '想': "xiang", '作': "zuo", '种': "zhong",
+	'开': "kai", '美': "mei", '总': "zong", '从': "cong", '无': "wu", '情': "qing",
+	'己': "ji", '面': "mian", '最': "zui", '女': "nv", '但': "dan", '现': "xian",
+	'前': "qian", '些': "xie", '所': "suo", '同': "tong", '日': "ri", '手': "shou",
+	'又': "you", '行': "xing", '意': "yi", '动': "dong", '方': "fang", '期': "qi",
+	'它': "ta", '头': "tou", '经': "jing", '长': "chang", '儿': "er", '回': "hui",
+	'位': "wei", '分': "fen", '爱': "ai", '老': "lao", '因': "yin", '很': "hen",
+	'给': "gei", '名': "ming", '法': "fa", '间': "jian", '斯': "si", '知': "zhi",
+	'世': "shi", '什': "shen", '两': "liang", '次': "ci", '使': "shi", '身': "shen",
+	'者': "zhe", '被': "bei", '高': "gao", '已': "yi", '亲': "qin", '其': "qi",
+	'进': "jin", '此': "ci", '话': "hua", '常': "chang", '与': "yu", '活': "huo",
+	'正': "zheng", '感': "gan", '见': "jian", '明': "ming", '问': "wen", '力': "li",
+	'理': "li", '尔': "er", '点': "dian", '文': "wen", '几': "ji", '定': "ding",
+	'本': "ben", '公': "gong", '特': "te", '做': "zuo", '外': "wai", '孩': "hai",
+	'相': "xiang", '西': "xi", '果': "guo", '走': "zou", '将': "jiang", '月': "yue",
+	'十': "shi", '实': "shi", '向': "xiang", '声': "sheng", '车': "che", '全': "quan",
+	'信': "xin", '重': "zhong", '三': "san", '机': "ji", '工': "gong", '物': "wu",
+	'气': "qi", '每': "mei", '并': "bing", '别': "bie", '真': "zhen", '打': "da",
+	'太': "tai", '新': "xin", '比': "bi", '才': "cai", '便': "bian", '夫': "fu",
+	'再': "zai", '书': "shu", '部': "bu", '水': "shui", '像': "xiang", '眼': "yan",
+	'等': "deng", '体': "ti", '却': "que", '加': "jia", '电': "dian", '主': "zhu",
+	'界': "jie", '门': "men", '利': "li", '海': "hai", '受': "shou", '听': "ting",
+	'表': "biao", '德': "de", '少': "shao", '克': "ke", '代': "dai", '员': "yuan",
+	'许': "xu", '稜': "leng", '先': "xian", '口': "kou", '由': "you", '死': "si",
+	'安': "an", '写': "xie", '性': "xing", '马': "ma", '光': "guang", '白': "bai",
+	'或': "huo", '住': "zhu", '难': "nan", '望': "wang", '教': "jiao", '命': "ming",
+	'花': "hua", '结': "jie", '乐': "le", '色': "se", '更': "geng", '拉': "la",
+	'东': "dong", '神': "shen", '记': "ji", '处': "chu", '让': "rang", '母': "mu",
+	'父': "fu", '应': "ying", '直': "zhi", '字': "zi", '场': "chang", '平': "ping",
+	'报': "bao", '友': "you", '关': "guan", '放': "fang", '至': "zhi", '张': "zhang",
+	'认': "ren", '接': "jie", '告': "gao", '入': "ru", '笑': "xiao", '内': "nei",
+	'英': "ying", '军': "jun", '候': "hou", '民': "min", '岁': "sui", '往': "wang",
+	'何': "he", '度': "du", '山': "shan", '觉': "jue", '路': "lu", '带': "dai",
+	'万': "wan", '男': "nan", '边': "bian", '风': "feng", '解': "jie", '叫': "jiao",
+	'任': "ren", '金': "jin", '快': "kuai", '原': "yuan", '吃': "chi", '章': "zhang",
+	'且': "qie", '习': "xi", '师': "shi", '观': "guan", '王': "wang", '流': "liu",
+	'式': "shi", '音': "yin", '云': "yun", '产': "chan", '品': "pin", '算': "suan",
+	'善': "shan", '静': "jing", '修': "xiu", '禅': "chan", '佛': "fo", '儒': "ru",
+	'庄': "zhuang", '孔': "kong", '孟': "meng", '易': "yi", '智': "zhi", '慧': "hui",
+	'灵': "ling", '玄': "xuan", '虚': "xu", '阴': "yin", '阳': "yang", '五': "wu",
+	'八': "ba", '卦': "gua", '极': "ji", '养': "yang", '炼': "lian", '丹': "dan",
+	'仙': "xian", '圣': "sheng", '贤': "xian", '古': "gu", '今': "jin", '诗': "shi",
+	'词': "ci", '歌': "ge", '赋': "fu", '论': "lun", '语': "yu", '春': "chun",
+	'秋': "qiu", '史': "shi", '传': "chuan", '注': "zhu", '释': "shi", '译': "yi",
+	'读': "du", '讲': "jiang",
+}
+
+// toSimplified 繁→简归一化
+func toSimplified(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if simplified, ok := t2sTable[r]; ok {
+			b.WriteRune(simplified)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// toPinyin 生成拼音索引串：空格分隔的音节加整串连写
+//
+// 输入先做繁简归一；表外的字跳过。超过一个音节时附加连写形式，
+// 让"laozi"这类不带空格的查询也能命中。
+func toPinyin(s string) string {
+	var syllables []string
+	for _, r := range toSimplified(s) {
+		if syllable, ok := pinyinTable[r]; ok {
+			syllables = append(syllables, syllable)
+		}
+	}
+	if len(syllables) == 0 {
+		return ""
+	}
+	joined := strings.Join(syllables, " ")
+	if len(syllables) > 1 {
+		joined += " " + strings.Join(syllables, "")
+	}
+	return joined
+}
@@ -0,0 +1,291 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 可检索的文档类型
+const (
+	TypePost    = "post"
+	TypeComment = "comment"
+	TypeWisdom  = "wisdom"
+)
+
+// Document 写入搜索索引的统一文档结构
+type Document struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content"`
+	// Scope 来源范围（板块、分类等），用于过滤
+	Scope     string    `json:"scope,omitempty"`
+	AuthorID  string    `json:"authorId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Hit 单条搜索结果
+type Hit struct {
+	Document
+	Score float64 `json:"score"`
+	// Highlights 命中片段，字段名到高亮片段列表
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// Result 搜索结果页
+type Result struct {
+	Total int64  `json:"total"`
+	Hits  []*Hit `json:"hits"`
+}
+
+// Service Elasticsearch搜索服务
+//
+// 通过ES的HTTP接口读写，避免引入厚重的客户端依赖；
+// 帖子、评论与文化智慧写入同一索引，按type字段区分。
+type Service struct {
+	baseURL string
+	index   string
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+// NewService 创建搜索服务
+func NewService(baseURL, index string, logger *zap.Logger) *Service {
+	if index == "" {
+		index = "taishang"
+	}
+	return &Service{
+		baseURL: baseURL,
+		index:   index,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  logger,
+	}
+}
+
+// Enabled 是否配置了ES地址
+func (s *Service) Enabled() bool {
+	return s.baseURL != ""
+}
+
+// indexedDocument 索引时附加的归一化字段
+//
+// 简体副本与拼音串只存在于索引里，搜索结果解码回Document时
+// 自动忽略，不会泄露到API响应。
+type indexedDocument struct {
+	*Document
+	TitleNorm     string `json:"titleNorm,omitempty"`
+	ContentNorm   string `json:"contentNorm,omitempty"`
+	TitlePinyin   string `json:"titlePinyin,omitempty"`
+	ContentPinyin string `json:"contentPinyin,omitempty"`
+}
+
+// IndexDocument 写入或更新文档
+func (s *Service) IndexDocument(ctx context.Context, doc *Document) error {
+	if !s.Enabled() {
+		return nil
+	}
+	enriched := &indexedDocument{
+		Document:      doc,
+		TitlePinyin:   toPinyin(doc.Title),
+		ContentPinyin: toPinyin(doc.Content),
+	}
+	if norm := toSimplified(doc.Title); norm != doc.Title {
+		enriched.TitleNorm = norm
+	}
+	if norm := toSimplified(doc.Content); norm != doc.Content {
+		enriched.ContentNorm = norm
+	}
+	body, err := json.Marshal(enriched)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/_doc/%s-%s", s.baseURL, s.index, doc.Type, doc.ID)
+	return s.do(ctx, http.MethodPut, endpoint, body, nil)
+}
+
+// DeleteDocument 删除文档
+func (s *Service) DeleteDocument(ctx context.Context, docType, id string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	endpoint := fmt.Sprintf("%s/%s/_doc/%s-%s", s.baseURL, s.index, docType, id)
+	return s.do(ctx, http.MethodDelete, endpoint, nil, nil)
+}
+
+// Search 全文检索
+//
+// 对title与content做multi_match（title权重更高），支持按
+// 类型与范围过滤，返回相关度排序的结果与高亮片段。查询词先做
+// 繁→简归一，并同时检索拼音字段，"laozi"能命中「老子」。
+func (s *Service) Search(ctx context.Context, query string, types []string, scope string, from, size int) (*Result, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("search backend is not configured")
+	}
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+
+	normalized := toSimplified(query)
+	must := []map[string]interface{}{
+		{
+			"multi_match": map[string]interface{}{
+				"query": normalized,
+				"fields": []string{
+					"title^3", "titleNorm^3", "titlePinyin^2",
+					"content", "contentNorm", "contentPinyin",
+				},
+			},
+		},
+	}
+	var filter []map[string]interface{}
+	if len(types) > 0 {
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{"type": types},
+		})
+	}
+	if scope != "" {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"scope": scope},
+		})
+	}
+
+	request := map[string]interface{}{
+		"from": from,
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   must,
+				"filter": filter,
+			},
+		},
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{
+				"title":   map[string]interface{}{},
+				"content": map[string]interface{}{"fragment_size": 120, "number_of_fragments": 3},
+			},
+		},
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search request: %w", err)
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Score     float64             `json:"_score"`
+				Source    Document            `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	endpoint := fmt.Sprintf("%s/%s/_search", s.baseURL, url.PathEscape(s.index))
+	if err := s.do(ctx, http.MethodPost, endpoint, body, &response); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: response.Hits.Total.Value, Hits: make([]*Hit, 0, len(response.Hits.Hits))}
+	for _, hit := range response.Hits.Hits {
+		result.Hits = append(result.Hits, &Hit{
+			Document:   hit.Source,
+			Score:      hit.Score,
+			Highlights: hit.Highlight,
+		})
+	}
+	return result, nil
+}
+
+// HandleEvent 事件回调：把社区内容变更同步进索引
+//
+// 与webhook共用同一事件流（post.created、comment.created等），
+// 在main中与其它订阅者一起挂到事件发布器上。
+func (s *Service) HandleEvent(event string, payload interface{}) {
+	if !s.Enabled() {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	var common struct {
+		ID        string    `json:"id"`
+		Scope     string    `json:"scope"`
+		Title     string    `json:"title"`
+		Content   string    `json:"content"`
+		AuthorID  string    `json:"authorId"`
+		ThreadID  string    `json:"threadId"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	if err := json.Unmarshal(data, &common); err != nil {
+		return
+	}
+
+	var doc *Document
+	switch event {
+	case "post.created":
+		doc = &Document{
+			ID: common.ID, Type: TypePost, Title: common.Title,
+			Content: common.Title, Scope: common.Scope,
+			AuthorID: common.AuthorID, CreatedAt: common.CreatedAt,
+		}
+	case "comment.created":
+		doc = &Document{
+			ID: common.ID, Type: TypeComment, Content: common.Content,
+			Scope: common.ThreadID, AuthorID: common.AuthorID, CreatedAt: common.CreatedAt,
+		}
+	default:
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.IndexDocument(ctx, doc); err != nil {
+		s.logger.Warn("failed to index document",
+			zap.String("event", event), zap.String("id", common.ID), zap.Error(err))
+	}
+}
+
+// do 执行ES请求并解析响应
+func (s *Service) do(ctx context.Context, method, endpoint string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build search request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("search backend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("search backend returned status %d: %s", resp.StatusCode, string(message))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode search response: %w", err)
+		}
+	}
+	return nil
+}
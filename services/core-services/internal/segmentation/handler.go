@@ -0,0 +1,138 @@
+package segmentation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 分群HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建分群处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册分群相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	segments := router.Group("/segments")
+	{
+		segments.POST("", h.CreateSegment)
+		segments.GET("", h.ListSegments)
+		segments.GET("/:id", h.GetSegment)
+		segments.PUT("/:id", h.UpdateSegment)
+		segments.DELETE("/:id", h.DeleteSegment)
+		segments.POST("/:id/refresh", h.RefreshSegment)
+		segments.GET("/:id/members", h.GetMembers)
+		segments.GET("/:id/members/:userId", h.CheckMember)
+	}
+	router.GET("/users/:userId/segments", h.SegmentsForUser)
+}
+
+// CreateSegment 创建分群
+func (h *Handler) CreateSegment(c *gin.Context) {
+	var req CreateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	segment, err := h.service.CreateSegment(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, segment)
+}
+
+// ListSegments 列出所有分群
+func (h *Handler) ListSegments(c *gin.Context) {
+	segments, err := h.service.ListSegments(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"segments": segments, "count": len(segments)})
+}
+
+// GetSegment 获取分群详情
+func (h *Handler) GetSegment(c *gin.Context) {
+	segment, err := h.service.GetSegment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, segment)
+}
+
+// UpdateSegment 更新分群
+func (h *Handler) UpdateSegment(c *gin.Context) {
+	var req UpdateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	segment, err := h.service.UpdateSegment(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, segment)
+}
+
+// DeleteSegment 删除分群
+func (h *Handler) DeleteSegment(c *gin.Context) {
+	if err := h.service.DeleteSegment(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "segment deleted"})
+}
+
+// RefreshSegment 手动触发分群刷新
+func (h *Handler) RefreshSegment(c *gin.Context) {
+	count, err := h.service.RefreshSegment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"memberCount": count})
+}
+
+// GetMembers 获取分群成员
+func (h *Handler) GetMembers(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	members, err := h.service.GetMembers(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"members": members, "count": len(members)})
+}
+
+// CheckMember 判断用户是否属于分群
+func (h *Handler) CheckMember(c *gin.Context) {
+	isMember, err := h.service.IsMember(c.Request.Context(), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"isMember": isMember})
+}
+
+// SegmentsForUser 获取用户所属的分群
+func (h *Handler) SegmentsForUser(c *gin.Context) {
+	segmentIDs, err := h.service.SegmentsForUser(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"segments": segmentIDs, "count": len(segmentIDs)})
+}
@@ -0,0 +1,93 @@
+package segmentation
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Segment 用户分群定义
+//
+// 规则由多个条件组合而成，条件可以基于用户画像属性、
+// 活跃度指标或事件谓词（例如"最近7天活跃 且 使用过AI对话"）。
+type Segment struct {
+	ID          string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name        string         `json:"name" gorm:"uniqueIndex;not null"`
+	Description string         `json:"description"`
+	Rules       datatypes.JSON `json:"rules" gorm:"not null"`
+	// RefreshIntervalMinutes 物化成员列表的刷新周期（分钟），0表示仅手动刷新
+	RefreshIntervalMinutes int       `json:"refreshIntervalMinutes" gorm:"default:60"`
+	IsActive               bool      `json:"isActive" gorm:"default:true"`
+	MemberCount            int64     `json:"memberCount" gorm:"default:0"`
+	LastRefreshedAt        time.Time `json:"lastRefreshedAt"`
+	CreatedAt              time.Time `json:"createdAt"`
+	UpdatedAt              time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Segment) TableName() string {
+	return "cs_segments"
+}
+
+// SegmentMember 分群成员（物化结果）
+type SegmentMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	SegmentID string    `json:"segmentId" gorm:"type:varchar(36);uniqueIndex:idx_segment_user;not null"`
+	UserID    string    `json:"userId" gorm:"type:varchar(36);uniqueIndex:idx_segment_user;index;not null"`
+	AddedAt   time.Time `json:"addedAt"`
+}
+
+// TableName 指定表名
+func (SegmentMember) TableName() string {
+	return "cs_segment_members"
+}
+
+// 条件类型
+const (
+	ConditionTypeProfile = "profile" // 用户画像属性，如 region = "cn"
+	ConditionTypeMetric  = "metric"  // 活跃度指标，如 active_days >= 3
+	ConditionTypeEvent   = "event"   // 事件谓词，如最近7天发生过 ai_chat
+)
+
+// 条件运算符
+const (
+	OperatorEquals    = "eq"
+	OperatorNotEquals = "ne"
+	OperatorGT        = "gt"
+	OperatorGTE       = "gte"
+	OperatorLT        = "lt"
+	OperatorLTE       = "lte"
+	OperatorIn        = "in"
+	OperatorExists    = "exists"
+)
+
+// Condition 单个分群条件
+type Condition struct {
+	Type       string      `json:"type" binding:"required,oneof=profile metric event"`
+	Field      string      `json:"field" binding:"required"`
+	Operator   string      `json:"operator,omitempty"`
+	Value      interface{} `json:"value,omitempty"`
+	WindowDays int         `json:"windowDays,omitempty"`
+}
+
+// SegmentRules 分群规则：所有条件取交集（AND）
+type SegmentRules struct {
+	All []Condition `json:"all" binding:"required,min=1"`
+}
+
+// CreateSegmentRequest 创建分群请求
+type CreateSegmentRequest struct {
+	Name                   string       `json:"name" binding:"required"`
+	Description            string       `json:"description"`
+	Rules                  SegmentRules `json:"rules" binding:"required"`
+	RefreshIntervalMinutes int          `json:"refreshIntervalMinutes"`
+}
+
+// UpdateSegmentRequest 更新分群请求
+type UpdateSegmentRequest struct {
+	Name                   *string       `json:"name"`
+	Description            *string       `json:"description"`
+	Rules                  *SegmentRules `json:"rules"`
+	RefreshIntervalMinutes *int          `json:"refreshIntervalMinutes"`
+	IsActive               *bool         `json:"isActive"`
+}
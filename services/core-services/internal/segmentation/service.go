@@ -0,0 +1,416 @@
+package segmentation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AttributeSource 提供分群条件求值所需的用户数据
+//
+// 画像、指标和事件数据分散在各业务模块中，分群引擎通过该接口读取，
+// 避免与具体业务表耦合。
+type AttributeSource interface {
+	// ListUserIDs 列出参与分群计算的全部用户ID
+	ListUserIDs(ctx context.Context) ([]string, error)
+	// GetProfile 获取用户画像属性
+	GetProfile(ctx context.Context, userID string) (map[string]interface{}, error)
+	// GetMetric 获取窗口期内的活跃度指标值
+	GetMetric(ctx context.Context, userID, name string, window time.Duration) (float64, error)
+	// HasEvent 判断窗口期内用户是否发生过指定事件
+	HasEvent(ctx context.Context, userID, event string, window time.Duration) (bool, error)
+}
+
+// Service 用户分群服务
+type Service struct {
+	db     *gorm.DB
+	source AttributeSource
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建分群服务
+func NewService(db *gorm.DB, source AttributeSource, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Segment{}, &SegmentMember{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate segmentation tables: %w", err)
+	}
+
+	return &Service{
+		db:     db,
+		source: source,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// CreateSegment 创建分群
+func (s *Service) CreateSegment(ctx context.Context, req *CreateSegmentRequest) (*Segment, error) {
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	segment := &Segment{
+		ID:                     uuid.NewString(),
+		Name:                   req.Name,
+		Description:            req.Description,
+		Rules:                  rules,
+		RefreshIntervalMinutes: req.RefreshIntervalMinutes,
+		IsActive:               true,
+	}
+	if segment.RefreshIntervalMinutes == 0 {
+		segment.RefreshIntervalMinutes = 60
+	}
+
+	if err := s.db.WithContext(ctx).Create(segment).Error; err != nil {
+		return nil, fmt.Errorf("failed to create segment: %w", err)
+	}
+	return segment, nil
+}
+
+// GetSegment 获取分群
+func (s *Service) GetSegment(ctx context.Context, id string) (*Segment, error) {
+	var segment Segment
+	if err := s.db.WithContext(ctx).First(&segment, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("segment not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get segment: %w", err)
+	}
+	return &segment, nil
+}
+
+// ListSegments 列出所有分群
+func (s *Service) ListSegments(ctx context.Context) ([]*Segment, error) {
+	var segments []*Segment
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&segments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	return segments, nil
+}
+
+// UpdateSegment 更新分群
+func (s *Service) UpdateSegment(ctx context.Context, id string, req *UpdateSegmentRequest) (*Segment, error) {
+	segment, err := s.GetSegment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Rules != nil {
+		rules, err := json.Marshal(req.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rules: %w", err)
+		}
+		updates["rules"] = rules
+	}
+	if req.RefreshIntervalMinutes != nil {
+		updates["refresh_interval_minutes"] = *req.RefreshIntervalMinutes
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.WithContext(ctx).Model(segment).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update segment: %w", err)
+		}
+	}
+	return s.GetSegment(ctx, id)
+}
+
+// DeleteSegment 删除分群及其成员
+func (s *Service) DeleteSegment(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&SegmentMember{}, "segment_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete segment members: %w", err)
+		}
+		if err := tx.Delete(&Segment{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete segment: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetMembers 获取分群成员ID列表
+func (s *Service) GetMembers(ctx context.Context, segmentID string, limit, offset int) ([]string, error) {
+	query := s.db.WithContext(ctx).Model(&SegmentMember{}).Where("segment_id = ?", segmentID)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var userIDs []string
+	if err := query.Order("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get segment members: %w", err)
+	}
+	return userIDs, nil
+}
+
+// IsMember 判断用户是否属于分群
+//
+// 供通知、功能开关和实验模块做定向投放时调用。
+func (s *Service) IsMember(ctx context.Context, segmentID, userID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&SegmentMember{}).
+		Where("segment_id = ? AND user_id = ?", segmentID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check segment membership: %w", err)
+	}
+	return count > 0, nil
+}
+
+// SegmentsForUser 获取用户所属的全部分群ID
+func (s *Service) SegmentsForUser(ctx context.Context, userID string) ([]string, error) {
+	var segmentIDs []string
+	err := s.db.WithContext(ctx).Model(&SegmentMember{}).
+		Where("user_id = ?", userID).
+		Pluck("segment_id", &segmentIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segments for user: %w", err)
+	}
+	return segmentIDs, nil
+}
+
+// RefreshSegment 重新计算并物化分群成员
+func (s *Service) RefreshSegment(ctx context.Context, id string) (int64, error) {
+	segment, err := s.GetSegment(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	var rules SegmentRules
+	if err := json.Unmarshal(segment.Rules, &rules); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+
+	userIDs, err := s.source.ListUserIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	members := make([]SegmentMember, 0)
+	now := time.Now()
+	for _, userID := range userIDs {
+		match, err := s.evaluate(ctx, userID, &rules)
+		if err != nil {
+			s.logger.Warn("failed to evaluate segment rules",
+				zap.String("segment_id", id),
+				zap.String("user_id", userID),
+				zap.Error(err))
+			continue
+		}
+		if match {
+			members = append(members, SegmentMember{SegmentID: id, UserID: userID, AddedAt: now})
+		}
+	}
+
+	// 在事务中整体替换成员列表，保证读取方看到一致的快照
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&SegmentMember{}, "segment_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to clear segment members: %w", err)
+		}
+		if len(members) > 0 {
+			if err := tx.CreateInBatches(members, 500).Error; err != nil {
+				return fmt.Errorf("failed to insert segment members: %w", err)
+			}
+		}
+		return tx.Model(&Segment{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"member_count":      len(members),
+			"last_refreshed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(members)), nil
+}
+
+// evaluate 对单个用户求值全部条件（AND）
+func (s *Service) evaluate(ctx context.Context, userID string, rules *SegmentRules) (bool, error) {
+	for _, cond := range rules.All {
+		match, err := s.evaluateCondition(ctx, userID, &cond)
+		if err != nil {
+			return false, err
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// evaluateCondition 求值单个条件
+func (s *Service) evaluateCondition(ctx context.Context, userID string, cond *Condition) (bool, error) {
+	window := time.Duration(cond.WindowDays) * 24 * time.Hour
+	if window == 0 {
+		window = 7 * 24 * time.Hour
+	}
+
+	switch cond.Type {
+	case ConditionTypeProfile:
+		profile, err := s.source.GetProfile(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		value, exists := profile[cond.Field]
+		if cond.Operator == OperatorExists {
+			return exists, nil
+		}
+		if !exists {
+			return false, nil
+		}
+		return compareValues(value, cond.Operator, cond.Value)
+
+	case ConditionTypeMetric:
+		value, err := s.source.GetMetric(ctx, userID, cond.Field, window)
+		if err != nil {
+			return false, err
+		}
+		return compareValues(value, cond.Operator, cond.Value)
+
+	case ConditionTypeEvent:
+		return s.source.HasEvent(ctx, userID, cond.Field, window)
+
+	default:
+		return false, fmt.Errorf("unknown condition type: %s", cond.Type)
+	}
+}
+
+// compareValues 按运算符比较实际值与期望值
+func compareValues(actual interface{}, operator string, expected interface{}) (bool, error) {
+	if operator == "" {
+		operator = OperatorEquals
+	}
+
+	// 数值比较
+	actualNum, actualOK := toFloat(actual)
+	expectedNum, expectedOK := toFloat(expected)
+	if actualOK && expectedOK {
+		switch operator {
+		case OperatorEquals:
+			return actualNum == expectedNum, nil
+		case OperatorNotEquals:
+			return actualNum != expectedNum, nil
+		case OperatorGT:
+			return actualNum > expectedNum, nil
+		case OperatorGTE:
+			return actualNum >= expectedNum, nil
+		case OperatorLT:
+			return actualNum < expectedNum, nil
+		case OperatorLTE:
+			return actualNum <= expectedNum, nil
+		}
+	}
+
+	switch operator {
+	case OperatorEquals:
+		return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected), nil
+	case OperatorNotEquals:
+		return fmt.Sprintf("%v", actual) != fmt.Sprintf("%v", expected), nil
+	case OperatorIn:
+		list, ok := expected.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %q requires a list value", OperatorIn)
+		}
+		actualStr := fmt.Sprintf("%v", actual)
+		for _, item := range list {
+			if fmt.Sprintf("%v", item) == actualStr {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", operator)
+	}
+}
+
+// toFloat 尝试将任意值转换为float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Start 启动定时刷新循环
+func (s *Service) Start() {
+	go s.refreshLoop()
+}
+
+// Stop 停止定时刷新循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// refreshLoop 按各分群配置的周期刷新物化成员
+func (s *Service) refreshLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.refreshDue(context.Background())
+		}
+	}
+}
+
+// refreshDue 刷新所有到期的活跃分群
+func (s *Service) refreshDue(ctx context.Context) {
+	segments, err := s.ListSegments(ctx)
+	if err != nil {
+		s.logger.Error("failed to list segments for refresh", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, segment := range segments {
+		if !segment.IsActive || segment.RefreshIntervalMinutes <= 0 {
+			continue
+		}
+		due := segment.LastRefreshedAt.Add(time.Duration(segment.RefreshIntervalMinutes) * time.Minute)
+		if now.Before(due) {
+			continue
+		}
+		if _, err := s.RefreshSegment(ctx, segment.ID); err != nil {
+			s.logger.Error("failed to refresh segment",
+				zap.String("segment_id", segment.ID),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package segmentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// UserProfile 用户画像（供分群条件求值）
+type UserProfile struct {
+	UserID     string         `json:"userId" gorm:"primaryKey;type:varchar(36)"`
+	Attributes datatypes.JSON `json:"attributes"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (UserProfile) TableName() string {
+	return "cs_user_profiles"
+}
+
+// UserEvent 用户行为事件（供分群条件求值）
+type UserEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     string    `json:"userId" gorm:"type:varchar(36);index:idx_user_event"`
+	Event      string    `json:"event" gorm:"index:idx_user_event"`
+	Value      float64   `json:"value"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (UserEvent) TableName() string {
+	return "cs_user_events"
+}
+
+// ErrConsentDenied 用户未同意行为分析采集
+var ErrConsentDenied = errors.New("user has not consented to analytics tracking")
+
+// ConsentChecker 判断用户是否允许行为分析采集
+//
+// 由consent模块实现，这里只依赖接口以避免反向耦合。
+type ConsentChecker interface {
+	Allows(ctx context.Context, userID, purpose string) (bool, error)
+}
+
+// DatabaseAttributeSource 基于数据库的默认属性数据源
+type DatabaseAttributeSource struct {
+	db      *gorm.DB
+	consent ConsentChecker
+}
+
+// SetConsentChecker 设置同意检查器，启用采集前的合规校验
+func (s *DatabaseAttributeSource) SetConsentChecker(checker ConsentChecker) {
+	s.consent = checker
+}
+
+// NewDatabaseAttributeSource 创建数据库属性数据源
+func NewDatabaseAttributeSource(db *gorm.DB) (*DatabaseAttributeSource, error) {
+	if err := db.AutoMigrate(&UserProfile{}, &UserEvent{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate attribute source tables: %w", err)
+	}
+	return &DatabaseAttributeSource{db: db}, nil
+}
+
+// ListUserIDs 列出有画像记录的全部用户ID
+func (s *DatabaseAttributeSource) ListUserIDs(ctx context.Context) ([]string, error) {
+	var userIDs []string
+	if err := s.db.WithContext(ctx).Model(&UserProfile{}).Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user ids: %w", err)
+	}
+	return userIDs, nil
+}
+
+// GetProfile 获取用户画像属性
+func (s *DatabaseAttributeSource) GetProfile(ctx context.Context, userID string) (map[string]interface{}, error) {
+	var profile UserProfile
+	if err := s.db.WithContext(ctx).First(&profile, "user_id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+
+	attrs := map[string]interface{}{}
+	if len(profile.Attributes) > 0 {
+		if err := json.Unmarshal(profile.Attributes, &attrs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal profile attributes: %w", err)
+		}
+	}
+	return attrs, nil
+}
+
+// GetMetric 聚合窗口期内的事件值作为指标
+func (s *DatabaseAttributeSource) GetMetric(ctx context.Context, userID, name string, window time.Duration) (float64, error) {
+	var total float64
+	err := s.db.WithContext(ctx).Model(&UserEvent{}).
+		Where("user_id = ? AND event = ? AND occurred_at >= ?", userID, name, time.Now().Add(-window)).
+		Select("COALESCE(SUM(value), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to aggregate metric %s: %w", name, err)
+	}
+	return total, nil
+}
+
+// HasEvent 判断窗口期内是否发生过指定事件
+func (s *DatabaseAttributeSource) HasEvent(ctx context.Context, userID, event string, window time.Duration) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&UserEvent{}).
+		Where("user_id = ? AND event = ? AND occurred_at >= ?", userID, event, time.Now().Add(-window)).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check event %s: %w", event, err)
+	}
+	return count > 0, nil
+}
+
+// RecordEvent 记录一条用户事件，供其他模块上报行为数据
+//
+// 配置了同意检查器时，未授权analytics用途的用户事件会被拒绝。
+func (s *DatabaseAttributeSource) RecordEvent(ctx context.Context, userID, event string, value float64) error {
+	if s.consent != nil {
+		allowed, err := s.consent.Allows(ctx, userID, "analytics")
+		if err != nil {
+			return fmt.Errorf("failed to check analytics consent: %w", err)
+		}
+		if !allowed {
+			return ErrConsentDenied
+		}
+	}
+
+	record := &UserEvent{
+		UserID:     userID,
+		Event:      event,
+		Value:      value,
+		OccurredAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
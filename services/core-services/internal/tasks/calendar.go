@@ -0,0 +1,215 @@
+package tasks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// calendarTokenPrefix 日历订阅令牌前缀
+const calendarTokenPrefix = "cal_"
+
+// CalendarToken 每用户的ICS订阅令牌
+//
+// 日历客户端只能带URL订阅，无法携带JWT，因此为feed单独
+// 签发长期令牌；与其他令牌一致只存哈希，泄露后可重新生成。
+type CalendarToken struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID    string    `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	TokenHash string    `json:"-" gorm:"type:char(64);uniqueIndex;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (CalendarToken) TableName() string {
+	return "cs_calendar_tokens"
+}
+
+// IssueFeedToken 为用户签发（或重新生成）ICS订阅令牌
+//
+// 明文只在响应中出现一次；重复调用会使旧令牌失效。
+func (s *Service) IssueFeedToken(ctx context.Context, userID string) (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := calendarTokenPrefix + hex.EncodeToString(raw)
+	hash := sha256.Sum256([]byte(token))
+
+	record := &CalendarToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		TokenHash: hex.EncodeToString(hash[:]),
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&CalendarToken{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to issue feed token: %w", err)
+	}
+	return token, nil
+}
+
+// resolveFeedToken 校验订阅令牌并返回用户ID
+func (s *Service) resolveFeedToken(ctx context.Context, token string) (string, error) {
+	hash := sha256.Sum256([]byte(token))
+	var record CalendarToken
+	err := s.db.WithContext(ctx).
+		First(&record, "token_hash = ?", hex.EncodeToString(hash[:])).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("feed token not found")
+		}
+		return "", fmt.Errorf("failed to resolve feed token: %w", err)
+	}
+	return record.UserID, nil
+}
+
+// CalendarDay 日历视图中的一格
+type CalendarDay struct {
+	Date  string  `json:"date"` // 日视图YYYY-MM-DD，周视图为该周周一
+	Tasks []*Task `json:"tasks"`
+}
+
+// CalendarView 按天或周分组返回区间内有截止时间的任务
+func (s *Service) CalendarView(ctx context.Context, assigneeID string, from, to time.Time, groupByWeek bool) ([]*CalendarDay, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: to is before from")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return nil, fmt.Errorf("range too large: maximum one year")
+	}
+
+	query := s.db.WithContext(ctx).
+		Where("due_at IS NOT NULL AND due_at >= ? AND due_at < ?", from, to).
+		Order("due_at ASC")
+	if assigneeID != "" {
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+	var dueTasks []*Task
+	if err := query.Find(&dueTasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load calendar tasks: %w", err)
+	}
+
+	grouped := make(map[string]*CalendarDay)
+	var order []string
+	for _, task := range dueTasks {
+		key := bucketKey(*task.DueAt, groupByWeek)
+		day, ok := grouped[key]
+		if !ok {
+			day = &CalendarDay{Date: key}
+			grouped[key] = day
+			order = append(order, key)
+		}
+		day.Tasks = append(day.Tasks, task)
+	}
+
+	days := make([]*CalendarDay, 0, len(order))
+	for _, key := range order {
+		days = append(days, grouped[key])
+	}
+	return days, nil
+}
+
+// bucketKey 计算任务落入的日/周分组键
+func bucketKey(t time.Time, byWeek bool) string {
+	t = t.UTC()
+	if byWeek {
+		// 回退到该周周一
+		offset := (int(t.Weekday()) + 6) % 7
+		t = t.AddDate(0, 0, -offset)
+	}
+	return t.Format("2006-01-02")
+}
+
+// AssigneeDigest 汇总用户的逾期与即将到期任务为文本条目
+//
+// 供摘要邮件等场景使用：逾期任务在前，随后是7天内到期的任务。
+func (s *Service) AssigneeDigest(ctx context.Context, assigneeID string, now time.Time) ([]string, error) {
+	var dueTasks []*Task
+	err := s.db.WithContext(ctx).
+		Where("assignee_id = ? AND due_at IS NOT NULL AND completed_at IS NULL AND due_at < ?",
+			assigneeID, now.AddDate(0, 0, 7)).
+		Order("due_at ASC").
+		Limit(50).
+		Find(&dueTasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest tasks: %w", err)
+	}
+
+	var items []string
+	for _, task := range dueTasks {
+		if task.DueAt.Before(now) {
+			items = append(items, fmt.Sprintf("任务「%s」已逾期（截止 %s）",
+				task.Title, task.DueAt.Format("2006-01-02")))
+		} else {
+			items = append(items, fmt.Sprintf("任务「%s」将于 %s 到期",
+				task.Title, task.DueAt.Format("2006-01-02")))
+		}
+	}
+	return items, nil
+}
+
+// ICSFeed 生成用户的iCalendar订阅内容
+//
+// 每个有截止时间且分派给该用户的任务输出为一个VEVENT，
+// UID使用任务ID保证客户端增量刷新时事件稳定。
+func (s *Service) ICSFeed(ctx context.Context, token string) (string, error) {
+	userID, err := s.resolveFeedToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	var dueTasks []*Task
+	err = s.db.WithContext(ctx).
+		Where("assignee_id = ? AND due_at IS NOT NULL AND completed_at IS NULL", userID).
+		Order("due_at ASC").
+		Limit(500).
+		Find(&dueTasks).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to load feed tasks: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//taishanglaojun//core-services//CN\r\n")
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+	now := time.Now().UTC().Format("20060102T150405Z")
+	for _, task := range dueTasks {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		b.WriteString("UID:task-" + task.ID + "@taishanglaojun\r\n")
+		b.WriteString("DTSTAMP:" + now + "\r\n")
+		b.WriteString("DTSTART:" + task.DueAt.UTC().Format("20060102T150405Z") + "\r\n")
+		b.WriteString("SUMMARY:" + escapeICS(task.Title) + "\r\n")
+		if task.Description != "" {
+			b.WriteString("DESCRIPTION:" + escapeICS(task.Description) + "\r\n")
+		}
+		b.WriteString("END:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String(), nil
+}
+
+// escapeICS 转义iCalendar文本值
+func escapeICS(text string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\n", "\\n",
+		"\r", "",
+	)
+	return replacer.Replace(text)
+}
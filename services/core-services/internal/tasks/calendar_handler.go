@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler 任务日历HTTP处理器
+type CalendarHandler struct {
+	service *Service
+}
+
+// NewCalendarHandler 创建日历处理器
+func NewCalendarHandler(service *Service) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// SetupRoutes 注册日历相关路由
+func (h *CalendarHandler) SetupRoutes(router gin.IRouter) {
+	router.GET("/tasks/calendar", h.Calendar)
+	router.POST("/tasks/calendar/feed-token", h.IssueFeedToken)
+	// 日历客户端通过该URL订阅，令牌即身份
+	router.GET("/tasks/feed/:token", h.Feed)
+}
+
+// Calendar 按天/周分组返回日历视图
+func (h *CalendarHandler) Calendar(c *gin.Context) {
+	now := time.Now()
+	from, err := parseDateQuery(c.DefaultQuery("from", now.Format("2006-01-02")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+		return
+	}
+	to, err := parseDateQuery(c.DefaultQuery("to", now.AddDate(0, 1, 0).Format("2006-01-02")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+		return
+	}
+
+	days, err := h.service.CalendarView(c.Request.Context(),
+		c.Query("assigneeId"), from, to, c.Query("group") == "week")
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"days": days})
+}
+
+// IssueFeedToken 签发/重置当前用户的ICS订阅令牌
+func (h *CalendarHandler) IssueFeedToken(c *gin.Context) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+	token, err := h.service.IssueFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"token": token,
+		"url":   "/api/v1/tasks/feed/" + token + ".ics",
+	})
+}
+
+// Feed 输出ICS订阅内容
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	feed, err := h.service.ICSFeed(c.Request.Context(), token)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="tasks.ics"`)
+	c.String(http.StatusOK, feed)
+}
+
+// parseDateQuery 解析YYYY-MM-DD参数
+func parseDateQuery(raw string) (time.Time, error) {
+	return time.Parse("2006-01-02", raw)
+}
@@ -0,0 +1,145 @@
+package tasks
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 任务看板HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建任务看板处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册任务看板相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	boards := router.Group("/tasks/boards")
+	{
+		boards.POST("", h.CreateBoard)
+		boards.GET("/:boardId", h.GetBoard)
+		boards.POST("/:boardId/columns", h.AddColumn)
+		boards.POST("/:boardId/tasks", h.CreateTask)
+	}
+	router.PUT("/tasks/columns/:columnId/wip-limit", h.SetWIPLimit)
+	router.PATCH("/tasks/:taskId/move", h.MoveTask)
+}
+
+// CreateBoard 创建看板
+func (h *Handler) CreateBoard(c *gin.Context) {
+	var req CreateBoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := h.service.CreateBoard(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, board)
+}
+
+// GetBoard 获取看板（含列与任务，服务端稳定排序）
+func (h *Handler) GetBoard(c *gin.Context) {
+	board, err := h.service.GetBoard(c.Request.Context(), c.Param("boardId"))
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// AddColumn 新增列
+func (h *Handler) AddColumn(c *gin.Context) {
+	var req struct {
+		Name     string `json:"name" binding:"required"`
+		WIPLimit int    `json:"wipLimit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	column, err := h.service.AddColumn(c.Request.Context(), c.Param("boardId"), req.Name, req.WIPLimit)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, column)
+}
+
+// SetWIPLimit 设置列WIP上限
+func (h *Handler) SetWIPLimit(c *gin.Context) {
+	var req struct {
+		Limit int `json:"limit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetWIPLimit(c.Request.Context(), c.Param("columnId"), req.Limit); err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "wip limit updated"})
+}
+
+// CreateTask 创建任务
+func (h *Handler) CreateTask(c *gin.Context) {
+	var req CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.service.CreateTask(c.Request.Context(), c.Param("boardId"), &req)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// 指派OOO用户时附带提醒，但不阻止创建
+	if warning := h.service.AssignmentWarning(c.Request.Context(), task.AssigneeID); warning != "" {
+		c.JSON(http.StatusCreated, gin.H{"task": task, "warning": warning})
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+// MoveTask 拖拽移动任务到目标列与位置
+func (h *Handler) MoveTask(c *gin.Context) {
+	var req MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.service.MoveTask(c.Request.Context(), c.Param("taskId"), &req)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// statusForTaskError 把服务层错误映射为HTTP状态码
+func statusForTaskError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "wip limit reached"):
+		return http.StatusConflict
+	case strings.Contains(message, "must be"), strings.Contains(message, "another board"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"time"
+)
+
+// Board 看板
+type Board struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ProjectID string    `json:"projectId" gorm:"index;type:varchar(36)"`
+	Name      string    `json:"name" gorm:"not null"`
+	OwnerID   string    `json:"ownerId" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Columns []BoardColumn `json:"columns,omitempty" gorm:"foreignKey:BoardID"`
+}
+
+// TableName 指定表名
+func (Board) TableName() string {
+	return "cs_boards"
+}
+
+// BoardColumn 看板列，按Position排序
+type BoardColumn struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	BoardID string `json:"boardId" gorm:"index;type:varchar(36);not null"`
+	Name    string `json:"name" gorm:"not null"`
+	// Position 列在看板中的顺序，从0开始
+	Position int `json:"position" gorm:"not null"`
+	// WIPLimit 列内最大任务数，0表示不限制
+	WIPLimit  int       `json:"wipLimit" gorm:"default:0"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Tasks []Task `json:"tasks,omitempty" gorm:"foreignKey:ColumnID"`
+}
+
+// TableName 指定表名
+func (BoardColumn) TableName() string {
+	return "cs_board_columns"
+}
+
+// Task 任务
+type Task struct {
+	ID          string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	BoardID     string `json:"boardId" gorm:"index;type:varchar(36);not null"`
+	ColumnID    string `json:"columnId" gorm:"index;type:varchar(36);not null"`
+	Title       string `json:"title" gorm:"not null"`
+	Description string `json:"description" gorm:"type:text"`
+	AssigneeID  string `json:"assigneeId" gorm:"type:varchar(36)"`
+	// Position 任务在列内的顺序，从0开始，服务端维护连续序号
+	// 保证多客户端看到一致的看板状态
+	Position int `json:"position" gorm:"not null"`
+	// SeriesID 所属循环系列，空表示一次性任务
+	SeriesID string `json:"seriesId,omitempty" gorm:"index;type:varchar(36)"`
+	// DueAt 截止时间，驱动日历视图与ICS订阅
+	DueAt       *time.Time `json:"dueAt,omitempty" gorm:"index"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Task) TableName() string {
+	return "cs_tasks"
+}
+
+// CreateBoardRequest 创建看板请求
+type CreateBoardRequest struct {
+	ProjectID string   `json:"projectId"`
+	Name      string   `json:"name" binding:"required"`
+	OwnerID   string   `json:"ownerId" binding:"required"`
+	Columns   []string `json:"columns"` // 初始列名，为空时使用默认列
+}
+
+// CreateTaskRequest 创建任务请求
+type CreateTaskRequest struct {
+	ColumnID    string     `json:"columnId" binding:"required"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	AssigneeID  string     `json:"assigneeId"`
+	DueAt       *time.Time `json:"dueAt"`
+}
+
+// MoveTaskRequest 移动任务请求
+type MoveTaskRequest struct {
+	ColumnID string `json:"columnId" binding:"required"`
+	// Position 目标列内的位置，超出范围时追加到末尾
+	Position int `json:"position"`
+}
@@ -0,0 +1,271 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/lunar"
+)
+
+// RecurringSeries 循环任务系列
+//
+// 系列本身是任务模板，调度器按cron表达式或农历月日实例化
+// 具体任务；暂停的系列不再产生新任务，恢复后从下一个时间点
+// 继续。
+type RecurringSeries struct {
+	ID          string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	BoardID     string `json:"boardId" gorm:"index;type:varchar(36);not null"`
+	ColumnID    string `json:"columnId" gorm:"type:varchar(36);not null"`
+	Title       string `json:"title" gorm:"not null"`
+	Description string `json:"description" gorm:"type:text"`
+	AssigneeID  string `json:"assigneeId" gorm:"type:varchar(36)"`
+	// CronExpr 标准五段cron表达式，如 "0 9 * * 1"（每周一9点）；
+	// 与农历月日二选一
+	CronExpr string `json:"cronExpr,omitempty" gorm:"type:varchar(64)"`
+	// LunarMonth/LunarDay 按农历每年循环，如八月十五为8/15
+	LunarMonth int  `json:"lunarMonth,omitempty"`
+	LunarDay   int  `json:"lunarDay,omitempty"`
+	Paused     bool `json:"paused" gorm:"default:false"`
+	// NextRunAt 下次实例化时间，由调度器维护
+	NextRunAt time.Time `json:"nextRunAt" gorm:"index"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (RecurringSeries) TableName() string {
+	return "cs_task_recurrences"
+}
+
+// CreateSeriesRequest 创建循环系列请求
+type CreateSeriesRequest struct {
+	BoardID     string `json:"boardId" binding:"required"`
+	ColumnID    string `json:"columnId" binding:"required"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	AssigneeID  string `json:"assigneeId"`
+	CronExpr    string `json:"cronExpr"`
+	LunarMonth  int    `json:"lunarMonth"`
+	LunarDay    int    `json:"lunarDay"`
+}
+
+// RecurrenceService 循环任务调度服务
+type RecurrenceService struct {
+	db       *gorm.DB
+	tasks    *Service
+	logger   *zap.Logger
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRecurrenceService 创建循环任务调度服务
+func NewRecurrenceService(db *gorm.DB, tasks *Service, logger *zap.Logger) (*RecurrenceService, error) {
+	if err := db.AutoMigrate(&RecurringSeries{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate recurrence table: %w", err)
+	}
+	return &RecurrenceService{
+		db:     db,
+		tasks:  tasks,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 启动调度循环
+func (s *RecurrenceService) Start() {
+	go s.scheduleLoop()
+}
+
+// Stop 停止调度循环
+func (s *RecurrenceService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// CreateSeries 创建循环系列并计算首次运行时间
+func (s *RecurrenceService) CreateSeries(ctx context.Context, req *CreateSeriesRequest) (*RecurringSeries, error) {
+	hasCron := req.CronExpr != ""
+	hasLunar := req.LunarMonth != 0 || req.LunarDay != 0
+	if hasCron == hasLunar {
+		return nil, fmt.Errorf("exactly one of cronExpr or lunarMonth/lunarDay is required")
+	}
+	if hasLunar && (req.LunarMonth < 1 || req.LunarMonth > 12 || req.LunarDay < 1 || req.LunarDay > 30) {
+		return nil, fmt.Errorf("invalid lunar date: month %d day %d", req.LunarMonth, req.LunarDay)
+	}
+
+	series := &RecurringSeries{
+		ID:          uuid.NewString(),
+		BoardID:     req.BoardID,
+		ColumnID:    req.ColumnID,
+		Title:       req.Title,
+		Description: req.Description,
+		AssigneeID:  req.AssigneeID,
+		CronExpr:    req.CronExpr,
+		LunarMonth:  req.LunarMonth,
+		LunarDay:    req.LunarDay,
+	}
+	next, err := s.nextRun(series, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	series.NextRunAt = next
+	if err := s.db.WithContext(ctx).Create(series).Error; err != nil {
+		return nil, fmt.Errorf("failed to create recurring series: %w", err)
+	}
+	return series, nil
+}
+
+// nextRun 系列在after之后的下一个实例化时间
+//
+// 农历系列按农历月日逐年循环（实例化在当天9点），某年不存在
+// 的日子（如腊月三十）自动落到存在该日的下一年。
+func (s *RecurrenceService) nextRun(series *RecurringSeries, after time.Time) (time.Time, error) {
+	if series.LunarMonth != 0 {
+		day, err := lunar.NextOccurrence(series.LunarMonth, series.LunarDay, after)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid lunar recurrence: %w", err)
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local), nil
+	}
+	schedule, err := cron.ParseStandard(series.CronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", series.CronExpr, err)
+	}
+	return schedule.Next(after), nil
+}
+
+// GetSeries 获取循环系列
+func (s *RecurrenceService) GetSeries(ctx context.Context, id string) (*RecurringSeries, error) {
+	var series RecurringSeries
+	if err := s.db.WithContext(ctx).First(&series, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("series not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+	return &series, nil
+}
+
+// SetPaused 暂停或恢复系列，恢复时从下一个cron时间点继续
+func (s *RecurrenceService) SetPaused(ctx context.Context, id string, paused bool) (*RecurringSeries, error) {
+	series, err := s.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"paused": paused}
+	if !paused {
+		next, err := s.nextRun(series, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		series.NextRunAt = next
+		updates["next_run_at"] = series.NextRunAt
+	}
+	if err := s.db.WithContext(ctx).Model(series).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update series: %w", err)
+	}
+	series.Paused = paused
+	return series, nil
+}
+
+// CompleteTask 完成任务
+//
+// 属于循环系列的任务完成后，若系列的下次运行时间已过期
+// （例如调度器停机期间错过），立即实例化下一个任务。
+func (s *RecurrenceService) CompleteTask(ctx context.Context, taskID string) (*Task, error) {
+	var task Task
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("task not found: %s", taskID)
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.CompletedAt != nil {
+		return &task, nil
+	}
+
+	now := time.Now()
+	task.CompletedAt = &now
+	if err := s.db.WithContext(ctx).Model(&task).Update("completed_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to complete task: %w", err)
+	}
+
+	if task.SeriesID != "" {
+		series, err := s.GetSeries(ctx, task.SeriesID)
+		if err == nil && !series.Paused && !series.NextRunAt.After(now) {
+			if err := s.instantiate(ctx, series); err != nil {
+				s.logger.Warn("failed to instantiate next occurrence",
+					zap.String("series_id", series.ID), zap.Error(err))
+			}
+		}
+	}
+	return &task, nil
+}
+
+// scheduleLoop 每分钟实例化到期的系列
+func (s *RecurrenceService) scheduleLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.runDue(context.Background())
+		}
+	}
+}
+
+// runDue 实例化所有到期的系列
+func (s *RecurrenceService) runDue(ctx context.Context) {
+	var due []*RecurringSeries
+	err := s.db.WithContext(ctx).
+		Where("paused = ? AND next_run_at <= ?", false, time.Now()).
+		Find(&due).Error
+	if err != nil {
+		s.logger.Error("failed to query due series", zap.Error(err))
+		return
+	}
+
+	for _, series := range due {
+		if err := s.instantiate(ctx, series); err != nil {
+			s.logger.Warn("failed to instantiate occurrence",
+				zap.String("series_id", series.ID), zap.Error(err))
+		}
+	}
+}
+
+// instantiate 实例化一个任务并推进下次运行时间
+func (s *RecurrenceService) instantiate(ctx context.Context, series *RecurringSeries) error {
+	next, err := s.nextRun(series, time.Now())
+	if err != nil {
+		return err
+	}
+
+	task, err := s.tasks.CreateTask(ctx, series.BoardID, &CreateTaskRequest{
+		ColumnID:    series.ColumnID,
+		Title:       series.Title,
+		Description: series.Description,
+		AssigneeID:  series.AssigneeID,
+	})
+	if err != nil {
+		return err
+	}
+	err = s.db.WithContext(ctx).Model(&Task{}).Where("id = ?", task.ID).
+		Update("series_id", series.ID).Error
+	if err != nil {
+		return fmt.Errorf("failed to link task to series: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Model(series).Update("next_run_at", next).Error
+}
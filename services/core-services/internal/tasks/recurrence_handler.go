@@ -0,0 +1,85 @@
+package tasks
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecurrenceHandler 循环任务HTTP处理器
+type RecurrenceHandler struct {
+	service *RecurrenceService
+}
+
+// NewRecurrenceHandler 创建循环任务处理器
+func NewRecurrenceHandler(service *RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{service: service}
+}
+
+// SetupRoutes 注册循环任务相关路由
+func (h *RecurrenceHandler) SetupRoutes(router gin.IRouter) {
+	series := router.Group("/tasks/series")
+	{
+		series.POST("", h.CreateSeries)
+		series.GET("/:seriesId", h.GetSeries)
+		series.POST("/:seriesId/pause", h.Pause)
+		series.POST("/:seriesId/resume", h.Resume)
+	}
+	router.POST("/tasks/:taskId/complete", h.CompleteTask)
+}
+
+// CreateSeries 创建循环系列
+func (h *RecurrenceHandler) CreateSeries(c *gin.Context) {
+	var req CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.service.CreateSeries(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, series)
+}
+
+// GetSeries 获取循环系列
+func (h *RecurrenceHandler) GetSeries(c *gin.Context) {
+	series, err := h.service.GetSeries(c.Request.Context(), c.Param("seriesId"))
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Pause 暂停循环系列
+func (h *RecurrenceHandler) Pause(c *gin.Context) {
+	series, err := h.service.SetPaused(c.Request.Context(), c.Param("seriesId"), true)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Resume 恢复循环系列
+func (h *RecurrenceHandler) Resume(c *gin.Context) {
+	series, err := h.service.SetPaused(c.Request.Context(), c.Param("seriesId"), false)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// CompleteTask 完成任务（循环任务完成后按需实例化下一次）
+func (h *RecurrenceHandler) CompleteTask(c *gin.Context) {
+	task, err := h.service.CompleteTask(c.Request.Context(), c.Param("taskId"))
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
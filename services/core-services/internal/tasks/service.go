@@ -0,0 +1,281 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AvailabilityChecker 负责人可用性检查接口（由排期模块实现）
+type AvailabilityChecker interface {
+	IsOOO(ctx context.Context, userID string, at time.Time) (bool, string, error)
+}
+
+// defaultColumns 未指定初始列时创建的默认列
+var defaultColumns = []string{"待办", "进行中", "已完成"}
+
+// Service 任务看板服务
+type Service struct {
+	db *gorm.DB
+	// publish 任务事件发布回调（webhook等），可为nil
+	publish func(event string, payload interface{})
+	// availability 负责人可用性检查，可为nil
+	availability AvailabilityChecker
+}
+
+// SetAvailabilityChecker 接入负责人可用性检查，指派OOO用户时返回提醒
+func (s *Service) SetAvailabilityChecker(checker AvailabilityChecker) {
+	s.availability = checker
+}
+
+// AssignmentWarning 检查指派对象是否OOO，返回提醒文案（无提醒时为空）
+func (s *Service) AssignmentWarning(ctx context.Context, assigneeID string) string {
+	if s.availability == nil || assigneeID == "" {
+		return ""
+	}
+	ooo, _, err := s.availability.IsOOO(ctx, assigneeID, time.Now())
+	if err != nil || !ooo {
+		return ""
+	}
+	return fmt.Sprintf("assignee %s is currently out of office", assigneeID)
+}
+
+// SetEventPublisher 接入任务事件发布（task.created、task.status_changed）
+func (s *Service) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// emit 发布事件，未接入时为空操作
+func (s *Service) emit(event string, payload interface{}) {
+	if s.publish != nil {
+		s.publish(event, payload)
+	}
+}
+
+// NewService 创建任务看板服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Board{}, &BoardColumn{}, &Task{}, &CalendarToken{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate task board tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// CreateBoard 创建看板及初始列
+func (s *Service) CreateBoard(ctx context.Context, req *CreateBoardRequest) (*Board, error) {
+	columnNames := req.Columns
+	if len(columnNames) == 0 {
+		columnNames = defaultColumns
+	}
+
+	board := &Board{
+		ID:        uuid.NewString(),
+		ProjectID: req.ProjectID,
+		Name:      req.Name,
+		OwnerID:   req.OwnerID,
+	}
+	for i, name := range columnNames {
+		board.Columns = append(board.Columns, BoardColumn{
+			ID:       uuid.NewString(),
+			BoardID:  board.ID,
+			Name:     name,
+			Position: i,
+		})
+	}
+	if err := s.db.WithContext(ctx).Create(board).Error; err != nil {
+		return nil, fmt.Errorf("failed to create board: %w", err)
+	}
+	return board, nil
+}
+
+// GetBoard 获取看板，列与任务均按Position排序
+func (s *Service) GetBoard(ctx context.Context, boardID string) (*Board, error) {
+	var board Board
+	err := s.db.WithContext(ctx).
+		Preload("Columns", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Columns.Tasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&board, "id = ?", boardID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("board not found: %s", boardID)
+		}
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+	return &board, nil
+}
+
+// AddColumn 在看板末尾新增一列
+func (s *Service) AddColumn(ctx context.Context, boardID, name string, wipLimit int) (*BoardColumn, error) {
+	if wipLimit < 0 {
+		return nil, fmt.Errorf("wip limit must be non-negative")
+	}
+
+	column := &BoardColumn{
+		ID:       uuid.NewString(),
+		BoardID:  boardID,
+		Name:     name,
+		WIPLimit: wipLimit,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&BoardColumn{}).Where("board_id = ?", boardID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count columns: %w", err)
+		}
+		column.Position = int(count)
+		return tx.Create(column).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add column: %w", err)
+	}
+	return column, nil
+}
+
+// SetWIPLimit 设置列的在制品上限，0表示不限制
+func (s *Service) SetWIPLimit(ctx context.Context, columnID string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("wip limit must be non-negative")
+	}
+	result := s.db.WithContext(ctx).Model(&BoardColumn{}).
+		Where("id = ?", columnID).
+		Update("wip_limit", limit)
+	if result.Error != nil {
+		return fmt.Errorf("failed to set wip limit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("column not found: %s", columnID)
+	}
+	return nil
+}
+
+// CreateTask 在列末尾创建任务
+func (s *Service) CreateTask(ctx context.Context, boardID string, req *CreateTaskRequest) (*Task, error) {
+	task := &Task{
+		ID:          uuid.NewString(),
+		BoardID:     boardID,
+		ColumnID:    req.ColumnID,
+		Title:       req.Title,
+		Description: req.Description,
+		AssigneeID:  req.AssigneeID,
+		DueAt:       req.DueAt,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		column, err := lockColumn(tx, req.ColumnID)
+		if err != nil {
+			return err
+		}
+		count, err := countColumnTasks(tx, column.ID)
+		if err != nil {
+			return err
+		}
+		if column.WIPLimit > 0 && count >= int64(column.WIPLimit) {
+			return fmt.Errorf("wip limit reached for column %s (%d)", column.Name, column.WIPLimit)
+		}
+		task.Position = int(count)
+		return tx.Create(task).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emit("task.created", task)
+	return task, nil
+}
+
+// MoveTask 把任务移动到目标列的指定位置
+//
+// 在事务中重排源列与目标列的序号，保持各列内Position连续，
+// 使并发拖拽后所有客户端读到的顺序一致。
+func (s *Service) MoveTask(ctx context.Context, taskID string, req *MoveTaskRequest) (*Task, error) {
+	var task Task
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, "id = ?", taskID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("task not found: %s", taskID)
+			}
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+
+		target, err := lockColumn(tx, req.ColumnID)
+		if err != nil {
+			return err
+		}
+		if target.BoardID != task.BoardID {
+			return fmt.Errorf("column %s belongs to another board", target.ID)
+		}
+
+		targetCount, err := countColumnTasks(tx, target.ID)
+		if err != nil {
+			return err
+		}
+		crossColumn := task.ColumnID != target.ID
+		if crossColumn && target.WIPLimit > 0 && targetCount >= int64(target.WIPLimit) {
+			return fmt.Errorf("wip limit reached for column %s (%d)", target.Name, target.WIPLimit)
+		}
+
+		// 限定目标位置到合法区间
+		position := req.Position
+		maxPosition := int(targetCount)
+		if !crossColumn {
+			maxPosition--
+		}
+		if position < 0 {
+			position = 0
+		}
+		if position > maxPosition {
+			position = maxPosition
+		}
+
+		// 源列收缩：原位置之后的任务前移
+		err = tx.Model(&Task{}).
+			Where("column_id = ? AND position > ?", task.ColumnID, task.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return fmt.Errorf("failed to compact source column: %w", err)
+		}
+
+		// 目标列扩张：目标位置及之后的任务后移
+		err = tx.Model(&Task{}).
+			Where("column_id = ? AND position >= ? AND id <> ?", target.ID, position, task.ID).
+			UpdateColumn("position", gorm.Expr("position + 1")).Error
+		if err != nil {
+			return fmt.Errorf("failed to make room in target column: %w", err)
+		}
+
+		task.ColumnID = target.ID
+		task.Position = position
+		return tx.Model(&Task{}).Where("id = ?", task.ID).
+			Updates(map[string]interface{}{"column_id": target.ID, "position": position}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emit("task.status_changed", &task)
+	return &task, nil
+}
+
+// lockColumn 行锁获取列，序号重排期间阻塞并发移动
+func lockColumn(tx *gorm.DB, columnID string) (*BoardColumn, error) {
+	var column BoardColumn
+	err := tx.Set("gorm:query_option", "FOR UPDATE").First(&column, "id = ?", columnID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("column not found: %s", columnID)
+		}
+		return nil, fmt.Errorf("failed to lock column: %w", err)
+	}
+	return &column, nil
+}
+
+// countColumnTasks 统计列内任务数
+func countColumnTasks(tx *gorm.DB, columnID string) (int64, error) {
+	var count int64
+	if err := tx.Model(&Task{}).Where("column_id = ?", columnID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return count, nil
+}
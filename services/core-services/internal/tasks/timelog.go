@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeLog 任务计时记录
+type TimeLog struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TaskID string `json:"taskId" gorm:"index;type:varchar(36);not null"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// Note 计时说明，开始时可选填写
+	Note      string     `json:"note,omitempty"`
+	StartedAt time.Time  `json:"startedAt" gorm:"not null"`
+	StoppedAt *time.Time `json:"stoppedAt,omitempty"`
+	// DurationSeconds 停止时计算的时长，计时中为0
+	DurationSeconds int64 `json:"durationSeconds" gorm:"default:0"`
+}
+
+// TableName 指定表名
+func (TimeLog) TableName() string {
+	return "cs_task_time_logs"
+}
+
+// TimeTotal 按任务或用户聚合的计时总量
+type TimeTotal struct {
+	Key          string `json:"key"` // 任务ID或用户ID
+	TotalSeconds int64  `json:"totalSeconds"`
+	Entries      int64  `json:"entries"`
+}
+
+// TimesheetEntry 周报中的一行：某天在某任务上的投入
+type TimesheetEntry struct {
+	Date         string `json:"date"` // YYYY-MM-DD
+	TaskID       string `json:"taskId"`
+	TotalSeconds int64  `json:"totalSeconds"`
+}
+
+// TimeLogService 任务计时服务
+type TimeLogService struct {
+	db *gorm.DB
+}
+
+// NewTimeLogService 创建任务计时服务
+func NewTimeLogService(db *gorm.DB) (*TimeLogService, error) {
+	if err := db.AutoMigrate(&TimeLog{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate time log table: %w", err)
+	}
+	return &TimeLogService{db: db}, nil
+}
+
+// Start 开始计时
+//
+// 每个用户同一时刻只允许一个运行中的计时器；
+// 已有运行中的计时器时返回错误，由客户端先停止再开始。
+func (s *TimeLogService) Start(ctx context.Context, taskID, userID, note string) (*TimeLog, error) {
+	var task Task
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("task not found: %s", taskID)
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	log := &TimeLog{
+		ID:        uuid.NewString(),
+		TaskID:    taskID,
+		UserID:    userID,
+		Note:      note,
+		StartedAt: time.Now(),
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var running TimeLog
+		err := tx.Set("gorm:query_option", "FOR UPDATE").
+			First(&running, "user_id = ? AND stopped_at IS NULL", userID).Error
+		if err == nil {
+			return fmt.Errorf("a timer is already running on task %s", running.TaskID)
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to check running timer: %w", err)
+		}
+		return tx.Create(log).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// Stop 停止当前运行中的计时器并计算时长
+func (s *TimeLogService) Stop(ctx context.Context, taskID, userID string) (*TimeLog, error) {
+	var log TimeLog
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&log, "task_id = ? AND user_id = ? AND stopped_at IS NULL", taskID, userID).Error
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("no running timer for user %s on task %s", userID, taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get running timer: %w", err)
+		}
+
+		now := time.Now()
+		log.StoppedAt = &now
+		log.DurationSeconds = int64(now.Sub(log.StartedAt).Seconds())
+		return tx.Model(&TimeLog{}).Where("id = ?", log.ID).Updates(map[string]interface{}{
+			"stopped_at":       now,
+			"duration_seconds": log.DurationSeconds,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// Running 查询用户当前运行中的计时器，无则返回nil
+func (s *TimeLogService) Running(ctx context.Context, userID string) (*TimeLog, error) {
+	var log TimeLog
+	err := s.db.WithContext(ctx).First(&log, "user_id = ? AND stopped_at IS NULL", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+	return &log, nil
+}
+
+// TaskTotal 任务的计时总量（所有用户）
+func (s *TimeLogService) TaskTotal(ctx context.Context, taskID string) (*TimeTotal, error) {
+	return s.total(ctx, "task_id", taskID)
+}
+
+// UserTotal 用户的计时总量（所有任务）
+func (s *TimeLogService) UserTotal(ctx context.Context, userID string) (*TimeTotal, error) {
+	return s.total(ctx, "user_id", userID)
+}
+
+// Timesheet 用户的周报：一周内每天每任务的投入
+//
+// weekStart为周起始日（按日期截断），统计其后7天内已停止的计时。
+func (s *TimeLogService) Timesheet(ctx context.Context, userID string, weekStart time.Time) ([]*TimesheetEntry, error) {
+	start := weekStart.Truncate(24 * time.Hour)
+	end := start.AddDate(0, 0, 7)
+
+	var entries []*TimesheetEntry
+	err := s.db.WithContext(ctx).Model(&TimeLog{}).
+		Select("DATE(started_at) AS date, task_id, SUM(duration_seconds) AS total_seconds").
+		Where("user_id = ? AND stopped_at IS NOT NULL AND started_at >= ? AND started_at < ?", userID, start, end).
+		Group("DATE(started_at), task_id").
+		Order("date ASC").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to build timesheet: %w", err)
+	}
+	return entries, nil
+}
+
+// total 按列聚合计时总量
+func (s *TimeLogService) total(ctx context.Context, column, value string) (*TimeTotal, error) {
+	var result struct {
+		TotalSeconds int64
+		Entries      int64
+	}
+	err := s.db.WithContext(ctx).Model(&TimeLog{}).
+		Select("COALESCE(SUM(duration_seconds), 0) AS total_seconds, COUNT(*) AS entries").
+		Where(fmt.Sprintf("%s = ? AND stopped_at IS NOT NULL", column), value).
+		Scan(&result).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate time logs: %w", err)
+	}
+	return &TimeTotal{Key: value, TotalSeconds: result.TotalSeconds, Entries: result.Entries}, nil
+}
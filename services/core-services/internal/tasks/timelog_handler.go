@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeLogHandler 任务计时HTTP处理器
+type TimeLogHandler struct {
+	service *TimeLogService
+}
+
+// NewTimeLogHandler 创建任务计时处理器
+func NewTimeLogHandler(service *TimeLogService) *TimeLogHandler {
+	return &TimeLogHandler{service: service}
+}
+
+// SetupRoutes 注册任务计时相关路由
+func (h *TimeLogHandler) SetupRoutes(router gin.IRouter) {
+	router.POST("/tasks/:taskId/time-logs/start", h.Start)
+	router.POST("/tasks/:taskId/time-logs/stop", h.Stop)
+	router.GET("/tasks/:taskId/time-logs/total", h.TaskTotal)
+	timeLogs := router.Group("/time-logs/users/:userId")
+	{
+		timeLogs.GET("/running", h.Running)
+		timeLogs.GET("/total", h.UserTotal)
+		timeLogs.GET("/timesheet", h.Timesheet)
+	}
+}
+
+// Start 开始计时
+func (h *TimeLogHandler) Start(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log, err := h.service.Start(c.Request.Context(), c.Param("taskId"), req.UserID, req.Note)
+	if err != nil {
+		c.JSON(statusForTimeLogError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, log)
+}
+
+// Stop 停止计时
+func (h *TimeLogHandler) Stop(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log, err := h.service.Stop(c.Request.Context(), c.Param("taskId"), req.UserID)
+	if err != nil {
+		c.JSON(statusForTimeLogError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, log)
+}
+
+// Running 查询运行中的计时器
+func (h *TimeLogHandler) Running(c *gin.Context) {
+	log, err := h.service.Running(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusOK, gin.H{"running": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"running": true, "timeLog": log})
+}
+
+// TaskTotal 任务计时总量
+func (h *TimeLogHandler) TaskTotal(c *gin.Context) {
+	total, err := h.service.TaskTotal(c.Request.Context(), c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, total)
+}
+
+// UserTotal 用户计时总量
+func (h *TimeLogHandler) UserTotal(c *gin.Context) {
+	total, err := h.service.UserTotal(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, total)
+}
+
+// Timesheet 周报，weekStart参数格式YYYY-MM-DD，缺省为本周一
+func (h *TimeLogHandler) Timesheet(c *gin.Context) {
+	weekStart := mondayOf(time.Now())
+	if raw := c.Query("weekStart"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid weekStart, expected YYYY-MM-DD"})
+			return
+		}
+		weekStart = parsed
+	}
+
+	entries, err := h.service.Timesheet(c.Request.Context(), c.Param("userId"), weekStart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"weekStart": weekStart.Format("2006-01-02"),
+		"entries":   entries,
+	})
+}
+
+// mondayOf 返回所在周的周一零点
+func mondayOf(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return t.AddDate(0, 0, 1-weekday)
+}
+
+// statusForTimeLogError 把服务层错误映射为HTTP状态码
+func statusForTimeLogError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"), strings.Contains(message, "no running timer"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already running"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,155 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// importMaxRows 单次导入的行数上限
+const importMaxRows = 5000
+
+// ImportRow 一行待导入数据，键为目标字段名
+//
+// 调用方（HTTP层）负责按字段映射把CSV列名/JSON键翻译成
+// title、description、assigneeId、column这四个目标字段。
+type ImportRow map[string]string
+
+// RowError 单行校验错误
+type RowError struct {
+	Row     int    `json:"row"` // 从1开始的数据行号
+	Message string `json:"message"`
+}
+
+// ImportReport 导入校验与执行报告
+type ImportReport struct {
+	Total   int        `json:"total"`
+	Valid   int        `json:"valid"`
+	Created int        `json:"created"`
+	DryRun  bool       `json:"dryRun"`
+	Errors  []RowError `json:"errors,omitempty"`
+}
+
+// ImportTasks 批量导入任务到看板
+//
+// 行内的column按列名解析，空值落入看板第一列；全部行先校验，
+// dryRun时只返回报告不写库。WIP限制在批量迁移场景下不强制，
+// 与逐个创建不同——导入的是既有项目的存量数据。
+func (s *Service) ImportTasks(ctx context.Context, boardID string, rows []ImportRow, dryRun bool) (*ImportReport, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no rows to import")
+	}
+	if len(rows) > importMaxRows {
+		return nil, fmt.Errorf("too many rows: %d (limit %d)", len(rows), importMaxRows)
+	}
+
+	board, err := s.GetBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(board.Columns) == 0 {
+		return nil, fmt.Errorf("board has no columns: %s", boardID)
+	}
+
+	columnByName := make(map[string]string, len(board.Columns))
+	for _, column := range board.Columns {
+		columnByName[strings.ToLower(column.Name)] = column.ID
+	}
+	defaultColumn := board.Columns[0].ID
+
+	report := &ImportReport{Total: len(rows), DryRun: dryRun}
+	tasks := make([]*Task, 0, len(rows))
+	for i, row := range rows {
+		title := strings.TrimSpace(row["title"])
+		if title == "" {
+			report.Errors = append(report.Errors, RowError{Row: i + 1, Message: "title is required"})
+			continue
+		}
+		columnID := defaultColumn
+		if name := strings.TrimSpace(row["column"]); name != "" {
+			id, ok := columnByName[strings.ToLower(name)]
+			if !ok {
+				report.Errors = append(report.Errors, RowError{
+					Row: i + 1, Message: fmt.Sprintf("unknown column: %s", name),
+				})
+				continue
+			}
+			columnID = id
+		}
+		report.Valid++
+		tasks = append(tasks, &Task{
+			ID:          uuid.NewString(),
+			BoardID:     boardID,
+			ColumnID:    columnID,
+			Title:       title,
+			Description: row["description"],
+			AssigneeID:  strings.TrimSpace(row["assigneeId"]),
+		})
+	}
+
+	if dryRun || len(tasks) == 0 {
+		return report, nil
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 每列从当前末尾开始追加，保持Position连续
+		nextPosition := make(map[string]int)
+		for _, task := range tasks {
+			if _, ok := nextPosition[task.ColumnID]; !ok {
+				count, err := countColumnTasks(tx, task.ColumnID)
+				if err != nil {
+					return err
+				}
+				nextPosition[task.ColumnID] = int(count)
+			}
+			task.Position = nextPosition[task.ColumnID]
+			nextPosition[task.ColumnID]++
+			if err := tx.Create(task).Error; err != nil {
+				return fmt.Errorf("failed to import task (row title %q): %w", task.Title, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Created = len(tasks)
+	return report, nil
+}
+
+// ExportFilter 导出过滤条件
+type ExportFilter struct {
+	BoardID    string
+	ColumnID   string
+	AssigneeID string
+}
+
+// ExportTasks 分批流式导出任务
+//
+// 每批调用一次yield，避免大项目导出时把全部任务载入内存；
+// yield返回错误时中止。
+func (s *Service) ExportTasks(ctx context.Context, filter ExportFilter, yield func(batch []Task) error) error {
+	if filter.BoardID == "" {
+		return fmt.Errorf("boardId is required")
+	}
+	query := s.db.WithContext(ctx).Where("board_id = ?", filter.BoardID)
+	if filter.ColumnID != "" {
+		query = query.Where("column_id = ?", filter.ColumnID)
+	}
+	if filter.AssigneeID != "" {
+		query = query.Where("assignee_id = ?", filter.AssigneeID)
+	}
+
+	var batch []Task
+	result := query.Order("column_id ASC, position ASC").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			return yield(batch)
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to export tasks: %w", result.Error)
+	}
+	return nil
+}
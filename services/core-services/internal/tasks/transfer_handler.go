@@ -0,0 +1,256 @@
+package tasks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importMaxBody 导入请求体大小上限
+const importMaxBody = 8 << 20 // 8MB
+
+// TransferHandler 任务批量导入/导出HTTP处理器
+type TransferHandler struct {
+	service *Service
+}
+
+// NewTransferHandler 创建导入/导出处理器
+func NewTransferHandler(service *Service) *TransferHandler {
+	return &TransferHandler{service: service}
+}
+
+// SetupRoutes 注册导入/导出路由
+func (h *TransferHandler) SetupRoutes(router gin.IRouter) {
+	router.POST("/tasks/import", h.Import)
+	router.GET("/tasks/export", h.Export)
+}
+
+// Import 批量导入任务
+//
+// 请求体为CSV或JSON数组（按Content-Type区分）；mapping参数把
+// 源列名映射到目标字段，如 mapping=Summary:title,Owner:assigneeId；
+// dryRun=true时只做校验并返回报告。
+func (h *TransferHandler) Import(c *gin.Context) {
+	boardID := c.Query("boardId")
+	if boardID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "boardId is required"})
+		return
+	}
+	mapping, err := parseMapping(c.Query("mapping"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	body := io.LimitReader(c.Request.Body, importMaxBody)
+	var rows []ImportRow
+	contentType := c.ContentType()
+	switch {
+	case strings.Contains(contentType, "csv"):
+		rows, err = parseCSV(body, mapping)
+	case strings.Contains(contentType, "json"):
+		rows, err = parseJSON(body, mapping)
+	default:
+		err = fmt.Errorf("unsupported content type: %s (use text/csv or application/json)", contentType)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.ImportTasks(c.Request.Context(), boardID, rows, dryRun)
+	if err != nil {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Export 流式导出任务
+//
+// format=csv|json（默认json），可按columnId、assigneeId过滤；
+// 输出边查边写，大项目不会占用与任务数成正比的内存。
+func (h *TransferHandler) Export(c *gin.Context) {
+	filter := ExportFilter{
+		BoardID:    c.Query("boardId"),
+		ColumnID:   c.Query("columnId"),
+		AssigneeID: c.Query("assigneeId"),
+	}
+
+	var err error
+	if c.DefaultQuery("format", "json") == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="tasks.csv"`)
+		err = h.exportCSV(c, filter)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = h.exportJSON(c, filter)
+	}
+	if err != nil && !c.Writer.Written() {
+		c.JSON(statusForTaskError(err), gin.H{"error": err.Error()})
+	}
+}
+
+// exportCSV 按CSV流式写出
+func (h *TransferHandler) exportCSV(c *gin.Context, filter ExportFilter) error {
+	writer := csv.NewWriter(c.Writer)
+	header := []string{"id", "columnId", "title", "description", "assigneeId", "position", "createdAt"}
+	headerWritten := false
+
+	err := h.service.ExportTasks(c.Request.Context(), filter, func(batch []Task) error {
+		if !headerWritten {
+			if err := writer.Write(header); err != nil {
+				return err
+			}
+			headerWritten = true
+		}
+		for _, task := range batch {
+			record := []string{
+				task.ID, task.ColumnID, task.Title, task.Description,
+				task.AssigneeID, fmt.Sprintf("%d", task.Position),
+				task.CreatedAt.Format(time.RFC3339),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	if !headerWritten {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportJSON 按JSON数组流式写出
+func (h *TransferHandler) exportJSON(c *gin.Context, filter ExportFilter) error {
+	encoder := json.NewEncoder(c.Writer)
+	first := true
+	if _, err := c.Writer.WriteString("["); err != nil {
+		return err
+	}
+	err := h.service.ExportTasks(c.Request.Context(), filter, func(batch []Task) error {
+		for _, task := range batch {
+			if !first {
+				if _, err := c.Writer.WriteString(","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := encoder.Encode(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.Writer.WriteString("]")
+	return err
+}
+
+// parseMapping 解析 源列名:目标字段 形式的映射参数
+func parseMapping(raw string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if raw == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid mapping entry: %s", pair)
+		}
+		switch parts[1] {
+		case "title", "description", "assigneeId", "column":
+			mapping[strings.ToLower(strings.TrimSpace(parts[0]))] = parts[1]
+		default:
+			return nil, fmt.Errorf("unknown target field: %s", parts[1])
+		}
+	}
+	return mapping, nil
+}
+
+// mapField 源字段名经映射表翻译成目标字段，未映射时按同名匹配
+func mapField(name string, mapping map[string]string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if target, ok := mapping[key]; ok {
+		return target
+	}
+	switch key {
+	case "title", "description", "column":
+		return key
+	case "assigneeid", "assignee":
+		return "assigneeId"
+	}
+	return ""
+}
+
+// parseCSV 解析CSV，首行为表头
+func parseCSV(r io.Reader, mapping map[string]string) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	targets := make([]string, len(header))
+	for i, name := range header {
+		targets[i] = mapField(name, mapping)
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv row %d: %w", len(rows)+1, err)
+		}
+		row := make(ImportRow)
+		for i, value := range record {
+			if i < len(targets) && targets[i] != "" {
+				row[targets[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseJSON 解析JSON对象数组
+func parseJSON(r io.Reader, mapping map[string]string) ([]ImportRow, error) {
+	var raw []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+	rows := make([]ImportRow, 0, len(raw))
+	for _, item := range raw {
+		row := make(ImportRow)
+		for name, value := range item {
+			target := mapField(name, mapping)
+			if target == "" {
+				continue
+			}
+			if text, ok := value.(string); ok {
+				row[target] = text
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
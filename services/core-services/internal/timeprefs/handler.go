@@ -0,0 +1,96 @@
+package timeprefs
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 时间偏好HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建时间偏好处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册时间偏好路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/time")
+	{
+		group.GET("/preferences", h.GetPreference)
+		group.PUT("/preferences", h.SetPreference)
+		group.GET("/settings", h.ResolveSettings)
+	}
+}
+
+// requireUser 从请求头解析用户ID
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return "", false
+	}
+	return userID, true
+}
+
+// statusForTimePrefError 根据错误内容映射HTTP状态码
+func statusForTimePrefError(err error) int {
+	if strings.Contains(err.Error(), "invalid") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetPreference 读取时区与语言偏好
+func (h *Handler) GetPreference(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	pref, err := h.service.Get(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForTimePrefError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// SetPreference 保存时区与语言偏好
+func (h *Handler) SetPreference(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Timezone string `json:"timezone" binding:"required"`
+		Locale   string `json:"locale" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pref, err := h.service.Set(c.Request.Context(), userID, req.Timezone, req.Locale)
+	if err != nil {
+		c.JSON(statusForTimePrefError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// ResolveSettings 返回解析后的用户时间设置
+func (h *Handler) ResolveSettings(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	resolved, err := h.service.Resolve(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(statusForTimePrefError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resolved)
+}
@@ -0,0 +1,144 @@
+package timeprefs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/i18n"
+)
+
+// DefaultTimezone 未设置偏好时的兜底时区
+const DefaultTimezone = "Asia/Shanghai"
+
+// Preference 每用户的时区与语言偏好
+//
+// 各调度器（摘要、每日一语等）通过LocationFor取用户本地时区，
+// 避免在各自的表里重复存时区字段。
+type Preference struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	// Timezone IANA时区名，如 Asia/Shanghai
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null"`
+	// Locale 界面语言，规范化为zh-Hans/zh-Hant/en
+	Locale    string    `json:"locale" gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Preference) TableName() string {
+	return "cs_time_preferences"
+}
+
+// Resolved 解析后的用户时间设置
+//
+// localTime带显式UTC偏移，客户端据此做所有时间显示换算。
+type Resolved struct {
+	Timezone  string `json:"timezone"`
+	Locale    string `json:"locale"`
+	UTCOffset string `json:"utcOffset"`
+	LocalTime string `json:"localTime"`
+	// Source preference/default
+	Source string `json:"source"`
+}
+
+// Service 时间偏好服务
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService 创建时间偏好服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Preference{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate time preference tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// Set 保存用户的时区与语言偏好
+func (s *Service) Set(ctx context.Context, userID, timezone, locale string) (*Preference, error) {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", timezone)
+	}
+	normalized := i18n.Normalize(locale)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid locale: %s", locale)
+	}
+
+	var pref Preference
+	err := s.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load time preference: %w", err)
+		}
+		pref = Preference{ID: uuid.NewString(), UserID: userID}
+	}
+	pref.Timezone = timezone
+	pref.Locale = normalized
+	if err := s.db.WithContext(ctx).Save(&pref).Error; err != nil {
+		return nil, fmt.Errorf("failed to save time preference: %w", err)
+	}
+	return &pref, nil
+}
+
+// Get 读取用户偏好，未设置时返回默认值
+func (s *Service) Get(ctx context.Context, userID string) (*Preference, error) {
+	var pref Preference
+	err := s.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &Preference{
+				UserID:   userID,
+				Timezone: DefaultTimezone,
+				Locale:   i18n.DefaultLanguage,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to load time preference: %w", err)
+	}
+	return &pref, nil
+}
+
+// Resolve 解析用户当前的时间设置
+func (s *Service) Resolve(ctx context.Context, userID string) (*Resolved, error) {
+	pref, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	source := "preference"
+	if pref.ID == "" {
+		source = "default"
+	}
+	loc, err := time.LoadLocation(pref.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := time.Now().In(loc)
+	return &Resolved{
+		Timezone:  pref.Timezone,
+		Locale:    pref.Locale,
+		UTCOffset: local.Format("-07:00"),
+		LocalTime: local.Format(time.RFC3339),
+		Source:    source,
+	}, nil
+}
+
+// LocationFor 取用户的本地时区，供各调度器换算本地时间
+//
+// 偏好缺失或时区无效时退回默认时区，保证调度器总能拿到
+// 可用的Location。
+func (s *Service) LocationFor(ctx context.Context, userID string) *time.Location {
+	pref, err := s.Get(ctx, userID)
+	if err == nil {
+		if loc, err := time.LoadLocation(pref.Timezone); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// tracerName 本服务所有span使用的tracer标识
+const tracerName = "core-services"
+
+// Init 按配置初始化OTLP导出与全局propagator
+//
+// endpoint为OTLP HTTP收集器地址（如 localhost:4318），为空时不注册
+// 导出器，span成为空操作。返回的关闭函数应在进程退出前调用。
+func Init(endpoint, environment string) (func(context.Context) error, error) {
+	// traceparent在网关反向代理处原样透传，这里统一注册W3C propagator
+	// 即可接续上游trace
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(tracerName),
+			semconv.DeploymentEnvironment(environment),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Middleware 返回为每个HTTP请求开启span的gin中间件
+func Middleware() gin.HandlerFunc {
+	tracer := otel.Tracer(tracerName)
+	propagator := otel.GetTextMapPropagator()
+
+	return func(c *gin.Context) {
+		ctx := propagator.Extract(c.Request.Context(),
+			propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(c.Request.Method),
+				semconv.HTTPRoute(route),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		span.SetAttributes(semconv.HTTPStatusCode(c.Writer.Status()))
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+	}
+}
+
+// gormPlugin 为GORM操作创建span的插件
+type gormPlugin struct{}
+
+// NewGormPlugin 创建GORM追踪插件
+func NewGormPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+// Name 插件名
+func (gormPlugin) Name() string {
+	return "otel-tracing"
+}
+
+// Initialize 注册各操作的前后回调
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("otel:before_create", beforeCallback("create")); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("otel:after_create", afterCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("otel:before_query", beforeCallback("query")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("otel:after_query", afterCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("otel:before_update", beforeCallback("update")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("otel:after_update", afterCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("otel:before_delete", beforeCallback("delete")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("otel:after_delete", afterCallback); err != nil {
+		return err
+	}
+	return nil
+}
+
+// beforeCallback 在操作前开启span
+func beforeCallback(operation string) func(*gorm.DB) {
+	tracer := otel.Tracer(tracerName)
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.operation", operation)))
+		db.Statement.Context = ctx
+		_ = span
+	}
+}
+
+// afterCallback 在操作后结束span并记录表名与错误
+func afterCallback(db *gorm.DB) {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return
+	}
+	span := trace.SpanFromContext(db.Statement.Context)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.String("db.table", db.Statement.Table))
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+	}
+	span.End()
+}
+
+// StartClientSpan 为出站调用（AI服务商等）开启span并返回注入头的函数
+func StartClientSpan(ctx context.Context, name string) (context.Context, trace.Span, func(carrier propagation.TextMapCarrier)) {
+	tracer := otel.Tracer(tracerName)
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("peer.service", name)))
+
+	inject := func(carrier propagation.TextMapCarrier) {
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+	}
+	return ctx, span, inject
+}
+
+// RecordDuration 把调用耗时记录到span属性
+func RecordDuration(span trace.Span, duration time.Duration) {
+	span.SetAttributes(attribute.Float64("duration_seconds", duration.Seconds()))
+}
@@ -0,0 +1,51 @@
+package transform
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 转换规则管理HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建转换规则处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册转换规则路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	group := router.Group("/transform")
+	{
+		group.GET("/rules", h.ListRules)
+		group.POST("/reload", h.Reload)
+	}
+}
+
+// ListRules 现行规则与加载时间
+func (h *Handler) ListRules(c *gin.Context) {
+	rules, loadedAt := h.service.Rules()
+	c.JSON(http.StatusOK, gin.H{
+		"rules":    rules,
+		"total":    len(rules),
+		"loadedAt": loadedAt,
+	})
+}
+
+// Reload 立即重新加载规则文件
+func (h *Handler) Reload(c *gin.Context) {
+	if err := h.service.Reload(); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	rules, loadedAt := h.service.Rules()
+	c.JSON(http.StatusOK, gin.H{"total": len(rules), "loadedAt": loadedAt})
+}
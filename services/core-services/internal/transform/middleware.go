@@ -0,0 +1,172 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyLimit 参与字段转换的请求/响应体大小上限
+const bodyLimit = 8 << 20
+
+// ctxRewritten 标记请求已被改写重派发，防止循环
+const ctxRewritten = "transform.rewritten"
+
+// bufferedWriter 缓冲响应以便做体级转换，最后一次性下发
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+// Write 写入缓冲
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString 写入缓冲
+func (w *bufferedWriter) WriteString(data string) (int, error) {
+	return w.body.WriteString(data)
+}
+
+// WriteHeader 暂存状态码，延迟到转换完成后下发
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// Middleware 声明式转换中间件
+//
+// 请求侧先改头、改体，再做路径改写并重派发（改写后的请求
+// 走新路径命中的规则做响应转换）；响应侧在缓冲里改头、剥离
+// 内部字段后一次性写出。
+func (s *Service) Middleware(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules := s.matchedRules(c.Request.Method, c.Request.URL.Path)
+		if len(rules) == 0 {
+			c.Next()
+			return
+		}
+
+		for _, rule := range rules {
+			applyRequestHeaders(c, rule)
+			applyRequestFields(c, rule)
+		}
+
+		if _, rewritten := c.Get(ctxRewritten); !rewritten {
+			path := c.Request.URL.Path
+			for _, rule := range rules {
+				path = rule.rewrittenPath(path)
+			}
+			if path != c.Request.URL.Path {
+				c.Set(ctxRewritten, true)
+				c.Request.URL.Path = path
+				engine.HandleContext(c)
+				c.Abort()
+				return
+			}
+		}
+
+		var strip []string
+		var respRules []*Rule
+		for _, rule := range rules {
+			if len(rule.ResponseHeaders.Set) > 0 || len(rule.ResponseHeaders.Remove) > 0 ||
+				len(rule.StripResponseFields) > 0 {
+				respRules = append(respRules, rule)
+				strip = append(strip, rule.StripResponseFields...)
+			}
+		}
+		if len(respRules) == 0 {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		for _, rule := range respRules {
+			for name, value := range rule.ResponseHeaders.Set {
+				c.Writer.Header().Set(name, value)
+			}
+			for _, name := range rule.ResponseHeaders.Remove {
+				c.Writer.Header().Del(name)
+			}
+		}
+		body := writer.body.Bytes()
+		if len(strip) > 0 {
+			body = stripFields(body, c.Writer.Header().Get("Content-Type"), strip)
+		}
+		c.Writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		c.Writer.WriteHeader(writer.status)
+		c.Writer.Write(body)
+	}
+}
+
+// applyRequestHeaders 应用请求头增删
+func applyRequestHeaders(c *gin.Context, rule *Rule) {
+	for name, value := range rule.RequestHeaders.Set {
+		c.Request.Header.Set(name, value)
+	}
+	for _, name := range rule.RequestHeaders.Remove {
+		c.Request.Header.Del(name)
+	}
+}
+
+// applyRequestFields 请求体顶层字段改名（老字段名映射到新名）
+func applyRequestFields(c *gin.Context, rule *Rule) {
+	if len(rule.MapRequestFields) == 0 || c.Request.Body == nil {
+		return
+	}
+	if !strings.Contains(c.Request.Header.Get("Content-Type"), "application/json") {
+		return
+	}
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, bodyLimit))
+	if err != nil {
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+		return
+	}
+	for old, renamed := range rule.MapRequestFields {
+		if value, ok := fields[old]; ok {
+			if _, exists := fields[renamed]; !exists {
+				fields[renamed] = value
+			}
+			delete(fields, old)
+		}
+	}
+	mapped, err := json.Marshal(fields)
+	if err != nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(mapped))
+	c.Request.ContentLength = int64(len(mapped))
+}
+
+// stripFields 从JSON响应体剥离顶层字段，非JSON原样返回
+func stripFields(body []byte, contentType string, fields []string) []byte {
+	if !strings.Contains(contentType, "application/json") || len(body) > bodyLimit {
+		return body
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return body
+	}
+	for _, field := range fields {
+		delete(decoded, field)
+	}
+	stripped, err := json.Marshal(decoded)
+	if err != nil {
+		return body
+	}
+	return stripped
+}
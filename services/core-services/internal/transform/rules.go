@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule 一条声明式转换规则
+//
+// 按match命中后依次应用：路径改写、请求/响应头增删、请求体
+// 字段改名（承接老客户端）与响应体内部字段剥离。
+type Rule struct {
+	Name  string `yaml:"name" json:"name"`
+	Match struct {
+		// Path 路径匹配，支持前缀通配（以/*结尾）
+		Path   string `yaml:"path" json:"path"`
+		Method string `yaml:"method,omitempty" json:"method,omitempty"`
+	} `yaml:"match" json:"match"`
+	// RewritePath 改写后的路径；前缀匹配时替换命中的前缀
+	RewritePath    string `yaml:"rewritePath,omitempty" json:"rewritePath,omitempty"`
+	RequestHeaders struct {
+		Set    map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
+		Remove []string          `yaml:"remove,omitempty" json:"remove,omitempty"`
+	} `yaml:"requestHeaders,omitempty" json:"requestHeaders,omitempty"`
+	ResponseHeaders struct {
+		Set    map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
+		Remove []string          `yaml:"remove,omitempty" json:"remove,omitempty"`
+	} `yaml:"responseHeaders,omitempty" json:"responseHeaders,omitempty"`
+	// MapRequestFields 请求体顶层字段改名：旧名 → 新名
+	MapRequestFields map[string]string `yaml:"mapRequestFields,omitempty" json:"mapRequestFields,omitempty"`
+	// StripResponseFields 从响应体剥离的顶层字段
+	StripResponseFields []string `yaml:"stripResponseFields,omitempty" json:"stripResponseFields,omitempty"`
+}
+
+// ruleFile YAML规则文件的根结构
+type ruleFile struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// loadRules 解析并校验规则文件
+func loadRules(path string) ([]*Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	var file ruleFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	for i, rule := range file.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("invalid rule %d: name is required", i)
+		}
+		if rule.Match.Path == "" {
+			return nil, fmt.Errorf("invalid rule %s: match.path is required", rule.Name)
+		}
+		if !strings.HasPrefix(rule.Match.Path, "/") {
+			return nil, fmt.Errorf("invalid rule %s: match.path must start with /", rule.Name)
+		}
+	}
+	return file.Rules, nil
+}
+
+// matches 判定规则是否命中请求
+func (r *Rule) matches(method, path string) bool {
+	if r.Match.Method != "" && !strings.EqualFold(r.Match.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Match.Path, "/*"); ok {
+		return strings.HasPrefix(path, prefix+"/")
+	}
+	return path == r.Match.Path
+}
+
+// rewrittenPath 求改写后的路径，未配置改写时返回原值
+func (r *Rule) rewrittenPath(path string) string {
+	if r.RewritePath == "" {
+		return path
+	}
+	if prefix, ok := strings.CutSuffix(r.Match.Path, "/*"); ok {
+		return r.RewritePath + strings.TrimPrefix(path, prefix)
+	}
+	return r.RewritePath
+}
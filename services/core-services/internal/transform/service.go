@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reloadInterval 规则文件变更检测间隔
+const reloadInterval = 10 * time.Second
+
+// Service 请求/响应转换引擎
+//
+// 规则来自YAML文件，按修改时间轮询热加载；加载失败时保留
+// 现行规则，不因一次坏配置中断流量。
+type Service struct {
+	path   string
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	rules    []*Rule
+	loadedAt time.Time
+	modTime  time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建转换引擎，path为空时引擎空转
+func NewService(path string, logger *zap.Logger) (*Service, error) {
+	s := &Service{
+		path:   path,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}
+	if path != "" {
+		if err := s.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Start 启动热加载循环
+func (s *Service) Start() {
+	if s.path == "" {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.reloadIfChanged()
+			}
+		}
+	}()
+}
+
+// Stop 停止热加载循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Reload 立即重新加载规则文件
+func (s *Service) Reload() error {
+	if s.path == "" {
+		return fmt.Errorf("invalid request: no rules file configured")
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat rules file: %w", err)
+	}
+	rules, err := loadRules(s.path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.rules = rules
+	s.loadedAt = time.Now()
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	s.logger.Info("transformation rules loaded",
+		zap.String("path", s.path), zap.Int("rules", len(rules)))
+	return nil
+}
+
+// reloadIfChanged 文件修改时间变化时热加载，失败保留现行规则
+func (s *Service) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return
+	}
+	if err := s.Reload(); err != nil {
+		s.logger.Warn("failed to reload transformation rules, keeping previous",
+			zap.Error(err))
+		s.mu.Lock()
+		s.modTime = info.ModTime()
+		s.mu.Unlock()
+	}
+}
+
+// Rules 现行规则与加载时间
+func (s *Service) Rules() ([]*Rule, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules, s.loadedAt
+}
+
+// matchedRules 命中请求的规则（按文件顺序）
+func (s *Service) matchedRules(method, path string) []*Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []*Rule
+	for _, rule := range s.rules {
+		if rule.matches(method, path) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
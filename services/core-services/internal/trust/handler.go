@@ -0,0 +1,172 @@
+package trust
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 账号风控HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建账号风控处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册账号风控相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	trust := router.Group("/trust")
+	{
+		trust.POST("/signals", h.RecordSignal)
+		trust.POST("/users/:userId/ban", h.BanUser)
+		trust.GET("/users/:userId/ban", h.GetBan)
+		trust.POST("/users/:userId/cluster", h.BuildCluster)
+		trust.GET("/clusters", h.ListClusters)
+		trust.GET("/clusters/:clusterId", h.GetCluster)
+		trust.POST("/clusters/:clusterId/ban", h.BanCluster)
+		trust.GET("/audit", h.AuditTrail)
+	}
+}
+
+// RecordSignal 上报账号信号
+func (h *Handler) RecordSignal(c *gin.Context) {
+	var req struct {
+		UserID            string `json:"userId" binding:"required"`
+		DeviceFingerprint string `json:"deviceFingerprint"`
+		IPAddress         string `json:"ipAddress"`
+		Email             string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.RecordSignal(c.Request.Context(), req.UserID, req.DeviceFingerprint, req.IPAddress, req.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "signal recorded"})
+}
+
+// BanUser 封禁单个账号
+func (h *Handler) BanUser(c *gin.Context) {
+	var req struct {
+		Reason   string `json:"reason" binding:"required"`
+		BannedBy string `json:"bannedBy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ban, err := h.service.BanUser(c.Request.Context(), c.Param("userId"), req.Reason, req.BannedBy, "")
+	if err != nil {
+		c.JSON(statusForTrustError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ban)
+}
+
+// GetBan 查询封禁状态
+func (h *Handler) GetBan(c *gin.Context) {
+	banned, err := h.service.IsBanned(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"banned": banned})
+}
+
+// BuildCluster 检测疑似小号并建立集群
+func (h *Handler) BuildCluster(c *gin.Context) {
+	var req struct {
+		ActorID string `json:"actorId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cluster, err := h.service.BuildCluster(c.Request.Context(), c.Param("userId"), req.ActorID)
+	if err != nil {
+		c.JSON(statusForTrustError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cluster)
+}
+
+// ListClusters 列出集群
+func (h *Handler) ListClusters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	clusters, err := h.service.ListClusters(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clusters": clusters, "count": len(clusters)})
+}
+
+// GetCluster 获取集群详情
+func (h *Handler) GetCluster(c *gin.Context) {
+	cluster, err := h.service.GetCluster(c.Request.Context(), c.Param("clusterId"))
+	if err != nil {
+		c.JSON(statusForTrustError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cluster)
+}
+
+// BanCluster 一键封禁集群
+func (h *Handler) BanCluster(c *gin.Context) {
+	var req struct {
+		ActorID string `json:"actorId" binding:"required"`
+		Reason  string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	banned, err := h.service.BanCluster(c.Request.Context(), c.Param("clusterId"), req.ActorID, req.Reason)
+	if err != nil {
+		c.JSON(statusForTrustError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"banned": banned, "count": len(banned)})
+}
+
+// AuditTrail 查询风控操作留痕
+func (h *Handler) AuditTrail(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, err := h.service.AuditTrail(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audit": entries, "count": len(entries)})
+}
+
+// statusForTrustError 把服务层错误映射为HTTP状态码
+func statusForTrustError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already banned"):
+		return http.StatusConflict
+	case strings.Contains(message, "not banned"), strings.Contains(message, "no suspected"),
+		strings.Contains(message, "no eligible"):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
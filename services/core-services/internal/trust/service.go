@@ -0,0 +1,497 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 信号权重：多个信号命中时按独立概率叠加置信度
+const (
+	weightDeviceFingerprint = 0.90
+	weightEmailPattern      = 0.60
+	weightIPRange           = 0.30
+	weightBehavior          = 0.20
+
+	// signalWindow 信号比对的时间窗口
+	signalWindow = 90 * 24 * time.Hour
+	// minClusterConfidence 低于该置信度的账号不进入集群
+	minClusterConfidence = 0.30
+	// minBanConfidence 一键封禁集群时要求的最低置信度
+	minBanConfidence = 0.60
+)
+
+// AccountSignal 账号行为信号（登录/会话时上报）
+type AccountSignal struct {
+	ID     uint   `json:"-" gorm:"primaryKey"`
+	UserID string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	// DeviceFingerprint 客户端设备指纹
+	DeviceFingerprint string `json:"deviceFingerprint" gorm:"index;type:varchar(64)"`
+	IPAddress         string `json:"ipAddress" gorm:"type:varchar(45)"`
+	// IPPrefix IPv4取/24、IPv6取/48，用于网段比对
+	IPPrefix string `json:"-" gorm:"index;type:varchar(64)"`
+	// EmailNormalized 规范化邮箱（去掉+后缀与gmail点号）
+	EmailNormalized string    `json:"-" gorm:"index;type:varchar(255)"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AccountSignal) TableName() string {
+	return "cs_account_signals"
+}
+
+// AccountBan 封禁记录
+type AccountBan struct {
+	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID   string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	Reason   string `json:"reason"`
+	BannedBy string `json:"bannedBy" gorm:"type:varchar(36)"`
+	// ClusterID 由集群封禁产生时记录来源集群
+	ClusterID string    `json:"clusterId,omitempty" gorm:"index;type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (AccountBan) TableName() string {
+	return "cs_account_bans"
+}
+
+// AltCluster 疑似小号集群
+type AltCluster struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// SeedUserID 被封禁的种子账号
+	SeedUserID string    `json:"seedUserId" gorm:"index;type:varchar(36);not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	Members []AltClusterMember `json:"members,omitempty" gorm:"foreignKey:ClusterID"`
+}
+
+// TableName 指定表名
+func (AltCluster) TableName() string {
+	return "cs_alt_clusters"
+}
+
+// AltClusterMember 集群成员及其置信度
+type AltClusterMember struct {
+	ID         uint    `json:"-" gorm:"primaryKey"`
+	ClusterID  string  `json:"clusterId" gorm:"uniqueIndex:idx_cluster_member;type:varchar(36);not null"`
+	UserID     string  `json:"userId" gorm:"uniqueIndex:idx_cluster_member;type:varchar(36);not null"`
+	Confidence float64 `json:"confidence"`
+	// Signals 命中的信号说明，逗号分隔
+	Signals string `json:"signals"`
+}
+
+// TableName 指定表名
+func (AltClusterMember) TableName() string {
+	return "cs_alt_cluster_members"
+}
+
+// TrustAudit 风控操作留痕
+type TrustAudit struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	Action    string    `json:"action" gorm:"type:varchar(32);not null"` // ban / cluster_ban / cluster_built
+	TargetID  string    `json:"targetId" gorm:"index;type:varchar(36)"`
+	ActorID   string    `json:"actorId" gorm:"type:varchar(36)"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TrustAudit) TableName() string {
+	return "cs_trust_audit"
+}
+
+// Service 账号风控服务
+//
+// 基于设备指纹、IP网段、邮箱模式与行为相似度识别封禁用户的
+// 疑似小号，聚合为带置信度的集群供管理员处置。
+type Service struct {
+	db *gorm.DB
+	// auditSink 可选的全局审计日志（audit模块实现）
+	auditSink AuditSink
+}
+
+// AuditSink 安全敏感操作的全局审计接口
+type AuditSink interface {
+	LogAction(ctx context.Context, actorID, action, resource, resourceID, ip string, before, after interface{})
+}
+
+// NewService 创建账号风控服务
+func NewService(db *gorm.DB) (*Service, error) {
+	err := db.AutoMigrate(&AccountSignal{}, &AccountBan{}, &AltCluster{}, &AltClusterMember{}, &TrustAudit{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate trust tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// SetAuditSink 设置可选的全局审计日志
+func (s *Service) SetAuditSink(sink AuditSink) {
+	s.auditSink = sink
+}
+
+// RecordSignal 记录账号信号
+func (s *Service) RecordSignal(ctx context.Context, userID, deviceFingerprint, ipAddress, email string) error {
+	signal := &AccountSignal{
+		UserID:            userID,
+		DeviceFingerprint: deviceFingerprint,
+		IPAddress:         ipAddress,
+		IPPrefix:          ipPrefix(ipAddress),
+		EmailNormalized:   normalizeEmail(email),
+	}
+	if err := s.db.WithContext(ctx).Create(signal).Error; err != nil {
+		return fmt.Errorf("failed to record signal: %w", err)
+	}
+	return nil
+}
+
+// BanUser 封禁单个账号
+func (s *Service) BanUser(ctx context.Context, userID, reason, bannedBy, clusterID string) (*AccountBan, error) {
+	ban := &AccountBan{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Reason:    reason,
+		BannedBy:  bannedBy,
+		ClusterID: clusterID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing AccountBan
+		if err := tx.First(&existing, "user_id = ?", userID).Error; err == nil {
+			return fmt.Errorf("user already banned: %s", userID)
+		}
+		if err := tx.Create(ban).Error; err != nil {
+			return fmt.Errorf("failed to create ban: %w", err)
+		}
+		audit := &TrustAudit{Action: "ban", TargetID: userID, ActorID: bannedBy, Detail: reason}
+		return tx.Create(audit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.auditSink != nil {
+		s.auditSink.LogAction(ctx, bannedBy, "user.ban", "user", userID, "", nil, ban)
+	}
+	return ban, nil
+}
+
+// IsBanned 查询账号是否被封禁
+func (s *Service) IsBanned(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&AccountBan{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check ban: %w", err)
+	}
+	return count > 0, nil
+}
+
+// BuildCluster 以被封禁账号为种子检测疑似小号并建立集群
+func (s *Service) BuildCluster(ctx context.Context, seedUserID, actorID string) (*AltCluster, error) {
+	banned, err := s.IsBanned(ctx, seedUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !banned {
+		return nil, fmt.Errorf("seed user is not banned: %s", seedUserID)
+	}
+
+	candidates, err := s.detectAlts(ctx, seedUserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no suspected alt accounts found for %s", seedUserID)
+	}
+
+	cluster := &AltCluster{ID: uuid.NewString(), SeedUserID: seedUserID}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(cluster).Error; err != nil {
+			return fmt.Errorf("failed to create cluster: %w", err)
+		}
+		for _, member := range candidates {
+			member.ClusterID = cluster.ID
+			if err := tx.Create(member).Error; err != nil {
+				return fmt.Errorf("failed to add cluster member: %w", err)
+			}
+		}
+		audit := &TrustAudit{
+			Action:   "cluster_built",
+			TargetID: cluster.ID,
+			ActorID:  actorID,
+			Detail:   fmt.Sprintf("seed %s, %d members", seedUserID, len(candidates)),
+		}
+		return tx.Create(audit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	cluster.Members = make([]AltClusterMember, 0, len(candidates))
+	for _, member := range candidates {
+		cluster.Members = append(cluster.Members, *member)
+	}
+	return cluster, nil
+}
+
+// GetCluster 获取集群详情
+func (s *Service) GetCluster(ctx context.Context, clusterID string) (*AltCluster, error) {
+	var cluster AltCluster
+	err := s.db.WithContext(ctx).Preload("Members").First(&cluster, "id = ?", clusterID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("cluster not found: %s", clusterID)
+		}
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+	return &cluster, nil
+}
+
+// ListClusters 列出集群（管理视图）
+func (s *Service) ListClusters(ctx context.Context, limit, offset int) ([]*AltCluster, error) {
+	query := s.db.WithContext(ctx).Preload("Members").Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var clusters []*AltCluster
+	if err := query.Find(&clusters).Error; err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	return clusters, nil
+}
+
+// BanCluster 一键封禁集群中置信度达标且未封禁的成员
+func (s *Service) BanCluster(ctx context.Context, clusterID, actorID, reason string) ([]string, error) {
+	cluster, err := s.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var banned []string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, member := range cluster.Members {
+			if member.Confidence < minBanConfidence {
+				continue
+			}
+			var existing AccountBan
+			if err := tx.First(&existing, "user_id = ?", member.UserID).Error; err == nil {
+				continue
+			}
+			ban := &AccountBan{
+				ID:        uuid.NewString(),
+				UserID:    member.UserID,
+				Reason:    reason,
+				BannedBy:  actorID,
+				ClusterID: clusterID,
+			}
+			if err := tx.Create(ban).Error; err != nil {
+				return fmt.Errorf("failed to ban member %s: %w", member.UserID, err)
+			}
+			banned = append(banned, member.UserID)
+		}
+		if len(banned) == 0 {
+			return fmt.Errorf("no eligible members to ban in cluster %s", clusterID)
+		}
+		audit := &TrustAudit{
+			Action:   "cluster_ban",
+			TargetID: clusterID,
+			ActorID:  actorID,
+			Detail:   fmt.Sprintf("banned %d members: %s", len(banned), strings.Join(banned, ",")),
+		}
+		return tx.Create(audit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.auditSink != nil {
+		s.auditSink.LogAction(ctx, actorID, "cluster.ban", "cluster", clusterID, "",
+			nil, map[string]interface{}{"banned": banned, "reason": reason})
+	}
+	return banned, nil
+}
+
+// AuditTrail 查询风控操作留痕
+func (s *Service) AuditTrail(ctx context.Context, limit, offset int) ([]*TrustAudit, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var entries []*TrustAudit
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load audit trail: %w", err)
+	}
+	return entries, nil
+}
+
+// detectAlts 找出与种子账号共享信号的账号并计算置信度
+func (s *Service) detectAlts(ctx context.Context, seedUserID string) ([]*AltClusterMember, error) {
+	since := time.Now().Add(-signalWindow)
+
+	var seedSignals []*AccountSignal
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ?", seedUserID, since).
+		Find(&seedSignals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load seed signals: %w", err)
+	}
+	if len(seedSignals) == 0 {
+		return nil, nil
+	}
+
+	fingerprints := map[string]bool{}
+	prefixes := map[string]bool{}
+	emails := map[string]bool{}
+	for _, signal := range seedSignals {
+		if signal.DeviceFingerprint != "" {
+			fingerprints[signal.DeviceFingerprint] = true
+		}
+		if signal.IPPrefix != "" {
+			prefixes[signal.IPPrefix] = true
+		}
+		if signal.EmailNormalized != "" {
+			emails[signal.EmailNormalized] = true
+		}
+	}
+	seedHours := activityHistogram(seedSignals)
+
+	// 候选账号：与种子共享任一信号的账号
+	var candidates []string
+	err = s.db.WithContext(ctx).Model(&AccountSignal{}).
+		Distinct("user_id").
+		Where("user_id <> ? AND created_at >= ?", seedUserID, since).
+		Where(
+			s.db.Where("device_fingerprint IN ?", keys(fingerprints)).
+				Or("ip_prefix IN ?", keys(prefixes)).
+				Or("email_normalized IN ?", keys(emails)),
+		).
+		Pluck("user_id", &candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidates: %w", err)
+	}
+
+	var members []*AltClusterMember
+	for _, candidate := range candidates {
+		var signals []*AccountSignal
+		err := s.db.WithContext(ctx).
+			Where("user_id = ? AND created_at >= ?", candidate, since).
+			Find(&signals).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candidate signals: %w", err)
+		}
+
+		var matched []string
+		confidence := 1.0
+		if sharesAny(signals, func(sig *AccountSignal) string { return sig.DeviceFingerprint }, fingerprints) {
+			confidence *= 1 - weightDeviceFingerprint
+			matched = append(matched, "device_fingerprint")
+		}
+		if sharesAny(signals, func(sig *AccountSignal) string { return sig.EmailNormalized }, emails) {
+			confidence *= 1 - weightEmailPattern
+			matched = append(matched, "email_pattern")
+		}
+		if sharesAny(signals, func(sig *AccountSignal) string { return sig.IPPrefix }, prefixes) {
+			confidence *= 1 - weightIPRange
+			matched = append(matched, "ip_range")
+		}
+		if similarity := cosineSimilarity(seedHours, activityHistogram(signals)); similarity >= 0.8 {
+			confidence *= 1 - weightBehavior*similarity
+			matched = append(matched, "behavioral_similarity")
+		}
+		confidence = 1 - confidence
+
+		if confidence >= minClusterConfidence {
+			members = append(members, &AltClusterMember{
+				UserID:     candidate,
+				Confidence: math.Round(confidence*100) / 100,
+				Signals:    strings.Join(matched, ","),
+			})
+		}
+	}
+	return members, nil
+}
+
+// sharesAny 候选信号值是否命中种子集合
+func sharesAny(signals []*AccountSignal, value func(*AccountSignal) string, seed map[string]bool) bool {
+	for _, signal := range signals {
+		if v := value(signal); v != "" && seed[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// activityHistogram 按小时统计活跃分布
+func activityHistogram(signals []*AccountSignal) [24]float64 {
+	var hours [24]float64
+	for _, signal := range signals {
+		hours[signal.CreatedAt.Hour()]++
+	}
+	return hours
+}
+
+// cosineSimilarity 两个活跃分布的余弦相似度
+func cosineSimilarity(a, b [24]float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < 24; i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// ipPrefix IPv4取/24、IPv6取/48网段
+func ipPrefix(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+// normalizeEmail 规范化邮箱：小写、去+后缀，gmail再去掉点号
+func normalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return ""
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// keys map键列表（空集合返回非nil切片，避免IN空列表）
+func keys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	if len(out) == 0 {
+		out = append(out, "")
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package unfurl
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 链接预览HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建链接预览处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册链接预览相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/unfurl", h.Unfurl)
+	router.POST("/unfurl/resolve", h.Resolve)
+}
+
+// Unfurl 获取单个链接的预览卡片
+func (h *Handler) Unfurl(c *gin.Context) {
+	var req struct {
+		URL string `json:"url" binding:"required,url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := h.service.Unfurl(c.Request.Context(), req.URL)
+	if err != nil {
+		c.JSON(statusForUnfurlError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Resolve 解析文本中的链接并返回预览卡片
+func (h *Handler) Resolve(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	previews := h.service.Resolve(c.Request.Context(), req.Text)
+	c.JSON(http.StatusOK, gin.H{"previews": previews, "count": len(previews)})
+}
+
+// statusForUnfurlError 把服务层错误映射为HTTP状态码
+func statusForUnfurlError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "invalid url"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "not allowed"), strings.Contains(message, "disallowed"):
+		return http.StatusForbidden
+	case strings.Contains(message, "failed to fetch"), strings.Contains(message, "unexpected status"),
+		strings.Contains(message, "unsupported content type"):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
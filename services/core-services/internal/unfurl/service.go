@@ -0,0 +1,360 @@
+package unfurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// previewTTL 预览缓存有效期，过期后重新抓取
+	previewTTL = 24 * time.Hour
+	// maxBodyBytes 抓取页面的读取上限
+	maxBodyBytes = 512 * 1024
+	// fetchTimeout 单次抓取超时
+	fetchTimeout = 5 * time.Second
+	// maxTitleRunes / maxDescriptionRunes 卡片字段截断长度
+	maxTitleRunes       = 120
+	maxDescriptionRunes = 300
+	userAgent           = "taishanglaojun-unfurl/1.0"
+)
+
+// urlPattern 消息文本中的链接
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// Preview 链接预览卡片（OpenGraph元数据，已净化）
+type Preview struct {
+	ID string `json:"-" gorm:"primaryKey;type:varchar(64)"` // URL的sha256
+	// URL 规范化后的原始链接
+	URL         string    `json:"url" gorm:"type:text;not null"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"imageUrl,omitempty"`
+	SiteName    string    `json:"siteName,omitempty"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+	ExpiresAt   time.Time `json:"-" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Preview) TableName() string {
+	return "cs_link_previews"
+}
+
+// Service 链接预览服务
+//
+// 抓取目标页面的OpenGraph元数据生成预览卡片，带TTL缓存；
+// 拒绝命中拒绝名单的域名与内网地址，并遵循目标站robots.txt。
+type Service struct {
+	db        *gorm.DB
+	client    *http.Client
+	denyHosts []string
+	logger    *zap.Logger
+}
+
+// NewService 创建链接预览服务
+func NewService(db *gorm.DB, denyHosts []string, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Preview{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate link preview tables: %w", err)
+	}
+	return &Service{
+		db:        db,
+		client:    &http.Client{Timeout: fetchTimeout},
+		denyHosts: denyHosts,
+		logger:    logger,
+	}, nil
+}
+
+// Unfurl 获取单个链接的预览卡片，优先命中缓存
+func (s *Service) Unfurl(ctx context.Context, rawURL string) (*Preview, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid url: %s", rawURL)
+	}
+	if s.hostDenied(parsed.Hostname()) {
+		return nil, fmt.Errorf("url host is not allowed: %s", parsed.Hostname())
+	}
+
+	key := hashURL(parsed.String())
+	var cached Preview
+	err = s.db.WithContext(ctx).First(&cached, "id = ?", key).Error
+	if err == nil && time.Now().Before(cached.ExpiresAt) {
+		return &cached, nil
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to read preview cache: %w", err)
+	}
+
+	if !s.robotsAllowed(ctx, parsed) {
+		return nil, fmt.Errorf("url is disallowed by robots.txt: %s", parsed.Hostname())
+	}
+
+	preview, err := s.fetch(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+	preview.ID = key
+
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		UpdateAll: true,
+	}).Create(preview).Error
+	if err != nil {
+		// 缓存写入失败不影响返回结果
+		s.logger.Warn("failed to cache link preview", zap.String("url", preview.URL), zap.Error(err))
+	}
+	return preview, nil
+}
+
+// Resolve 解析文本中的链接并返回预览卡片
+//
+// 单条链接失败（拒绝名单、抓取超时等）不影响其它链接。
+func (s *Service) Resolve(ctx context.Context, text string) []*Preview {
+	urls := urlPattern.FindAllString(text, -1)
+	seen := make(map[string]bool, len(urls))
+	previews := make([]*Preview, 0, len(urls))
+	for _, raw := range urls {
+		raw = strings.TrimRight(raw, ".,;:!?)")
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+
+		preview, err := s.Unfurl(ctx, raw)
+		if err != nil {
+			s.logger.Debug("skip unfurl", zap.String("url", raw), zap.Error(err))
+			continue
+		}
+		previews = append(previews, preview)
+	}
+	return previews
+}
+
+// fetch 抓取页面并解析OpenGraph元数据
+func (s *Service) fetch(ctx context.Context, target *url.URL) (*Preview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch url: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, target.Host)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "text/html") {
+		return nil, fmt.Errorf("unsupported content type: %s", ct)
+	}
+
+	meta, err := parseMetadata(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	now := time.Now()
+	return &Preview{
+		URL:         target.String(),
+		Title:       sanitize(meta.title, maxTitleRunes),
+		Description: sanitize(meta.description, maxDescriptionRunes),
+		ImageURL:    sanitizeImageURL(meta.image, target),
+		SiteName:    sanitize(meta.siteName, maxTitleRunes),
+		FetchedAt:   now,
+		ExpiresAt:   now.Add(previewTTL),
+	}, nil
+}
+
+// hostDenied 检查拒绝名单与内网地址（SSRF防护）
+func (s *Service) hostDenied(host string) bool {
+	lower := strings.ToLower(host)
+	for _, denied := range s.denyHosts {
+		denied = strings.ToLower(strings.TrimSpace(denied))
+		if denied == "" {
+			continue
+		}
+		if lower == denied || strings.HasSuffix(lower, "."+denied) {
+			return true
+		}
+	}
+	if lower == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+	}
+	return false
+}
+
+// robotsAllowed 检查目标站robots.txt是否允许抓取该路径
+//
+// robots.txt不可达时视为允许；仅解析通配User-agent的Disallow规则。
+func (s *Service) robotsAllowed(ctx context.Context, target *url.URL) bool {
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", target.Scheme, target.Host)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return true
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return true
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return true
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	applies := false
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		lower := strings.ToLower(line)
+		switch {
+		case strings.HasPrefix(lower, "user-agent:"):
+			agent := strings.TrimSpace(line[len("user-agent:"):])
+			applies = agent == "*"
+		case applies && strings.HasPrefix(lower, "disallow:"):
+			rule := strings.TrimSpace(line[len("disallow:"):])
+			if rule != "" && strings.HasPrefix(path, rule) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// metadata 页面元数据的中间结构
+type metadata struct {
+	title       string
+	description string
+	image       string
+	siteName    string
+}
+
+// parseMetadata 解析OpenGraph meta标签，缺失时回退到<title>
+func parseMetadata(r io.Reader) (*metadata, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &metadata{}
+	var pageTitle string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if n.FirstChild != nil && pageTitle == "" {
+					pageTitle = n.FirstChild.Data
+				}
+			case "meta":
+				var property, name, content string
+				for _, attr := range n.Attr {
+					switch attr.Key {
+					case "property":
+						property = attr.Val
+					case "name":
+						name = attr.Val
+					case "content":
+						content = attr.Val
+					}
+				}
+				switch property {
+				case "og:title":
+					meta.title = content
+				case "og:description":
+					meta.description = content
+				case "og:image":
+					meta.image = content
+				case "og:site_name":
+					meta.siteName = content
+				}
+				if name == "description" && meta.description == "" {
+					meta.description = content
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	if meta.title == "" {
+		meta.title = pageTitle
+	}
+	return meta, nil
+}
+
+// sanitize 去除控制字符并按长度截断
+func sanitize(value string, maxRunes int) string {
+	value = strings.TrimSpace(value)
+	var builder strings.Builder
+	for _, r := range value {
+		if r == '\n' || r == '\t' {
+			builder.WriteRune(' ')
+			continue
+		}
+		if r < 0x20 || r == 0x7f {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	runes := []rune(builder.String())
+	if len(runes) > maxRunes {
+		return string(runes[:maxRunes-1]) + "…"
+	}
+	return string(runes)
+}
+
+// sanitizeImageURL 只保留http(s)图片地址，相对地址基于页面解析
+func sanitizeImageURL(image string, base *url.URL) string {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return ""
+	}
+	parsed, err := url.Parse(image)
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+// hashURL 预览缓存主键
+func hashURL(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
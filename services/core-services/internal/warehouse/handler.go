@@ -0,0 +1,108 @@
+package warehouse
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 仓库导出HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建仓库导出处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册仓库导出相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	jobs := router.Group("/warehouse/jobs")
+	{
+		jobs.POST("", h.CreateJob)
+		jobs.GET("", h.ListJobs)
+		jobs.POST("/:jobId/run", h.RunJob)
+		jobs.PUT("/:jobId/enabled", h.SetEnabled)
+		jobs.GET("/:jobId/runs", h.ListRuns)
+	}
+	router.GET("/warehouse/runs", h.ListRuns)
+}
+
+// CreateJob 创建导出作业
+func (h *Handler) CreateJob(c *gin.Context) {
+	var req CreateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.service.CreateJob(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not exportable") || strings.Contains(err.Error(), "invalid cron") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, job)
+}
+
+// ListJobs 列出导出作业
+func (h *Handler) ListJobs(c *gin.Context) {
+	jobs, err := h.service.ListJobs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// RunJob 立即执行一次导出
+func (h *Handler) RunJob(c *gin.Context) {
+	run, err := h.service.RunJob(c.Request.Context(), c.Param("jobId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "run": run})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// SetEnabled 启用/停用作业
+func (h *Handler) SetEnabled(c *gin.Context) {
+	var req struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetEnabled(c.Request.Context(), c.Param("jobId"), *req.Enabled); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": *req.Enabled})
+}
+
+// ListRuns 列出运行记录
+func (h *Handler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	runs, err := h.service.ListRuns(c.Request.Context(), c.Param("jobId"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
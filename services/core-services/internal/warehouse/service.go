@@ -0,0 +1,418 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 导出运行状态
+const (
+	RunStatusRunning = "running"
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+)
+
+// exportBatchSize 单次导出运行读取的最大行数
+const exportBatchSize = 5000
+
+// exportableTables 允许导出的源表白名单，防止任意表被拉取
+var exportableTables = map[string]bool{
+	"cs_threads":           true,
+	"cs_posts":             true,
+	"cs_tasks":             true,
+	"cs_consent_history":   true,
+	"cs_emoji_usage":       true,
+	"cs_trust_audit":       true,
+	"cs_terms_acceptances": true,
+}
+
+// ExportJob 仓库导出作业
+//
+// 每个作业以cursor_column为增量水位，按cron计划把新增/变更行推送到
+// 外部仓库（ClickHouse HTTP接口）。
+type ExportJob struct {
+	ID            string     `json:"id" gorm:"type:varchar(36);primaryKey"`
+	Name          string     `json:"name" gorm:"type:varchar(128);not null"`
+	SourceTable   string     `json:"sourceTable" gorm:"type:varchar(64);not null"`
+	TargetTable   string     `json:"targetTable" gorm:"type:varchar(128);not null"`
+	CursorColumn  string     `json:"cursorColumn" gorm:"type:varchar(64);default:'updated_at'"`
+	CronExpr      string     `json:"cronExpr" gorm:"type:varchar(64);not null"`
+	Enabled       bool       `json:"enabled" gorm:"default:true"`
+	Watermark     *time.Time `json:"watermark,omitempty"`
+	SchemaColumns string     `json:"schemaColumns" gorm:"type:text"` // 已同步到目标表的列，逗号分隔
+	NextRunAt     time.Time  `json:"nextRunAt" gorm:"index"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "cs_warehouse_jobs"
+}
+
+// ExportRun 单次导出运行记录，供监控与排障
+type ExportRun struct {
+	ID         string     `json:"id" gorm:"type:varchar(36);primaryKey"`
+	JobID      string     `json:"jobId" gorm:"type:varchar(36);index;not null"`
+	Status     string     `json:"status" gorm:"type:varchar(16);not null"`
+	RowsCount  int        `json:"rowsCount"`
+	Watermark  *time.Time `json:"watermark,omitempty"`
+	Error      string     `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (ExportRun) TableName() string {
+	return "cs_warehouse_runs"
+}
+
+// CreateJobRequest 创建导出作业请求
+type CreateJobRequest struct {
+	Name         string `json:"name" binding:"required"`
+	SourceTable  string `json:"sourceTable" binding:"required"`
+	TargetTable  string `json:"targetTable" binding:"required"`
+	CursorColumn string `json:"cursorColumn"`
+	CronExpr     string `json:"cronExpr" binding:"required"`
+}
+
+// Service 仓库导出服务
+type Service struct {
+	db      *gorm.DB
+	sinkURL string // ClickHouse HTTP地址，为空时作业只记录不推送
+	client  *http.Client
+	logger  *zap.Logger
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建仓库导出服务
+func NewService(db *gorm.DB, sinkURL string, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&ExportJob{}, &ExportRun{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate warehouse tables: %w", err)
+	}
+	return &Service{
+		db:      db,
+		sinkURL: strings.TrimRight(sinkURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+		logger:  logger,
+		stopCh:  make(chan struct{}),
+	}, nil
+}
+
+// Start 启动调度循环
+func (s *Service) Start() {
+	go s.scheduleLoop()
+}
+
+// Stop 停止调度循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// scheduleLoop 每分钟检查到期作业并执行
+func (s *Service) scheduleLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.runDueJobs(context.Background())
+		}
+	}
+}
+
+// runDueJobs 执行全部到期作业
+func (s *Service) runDueJobs(ctx context.Context) {
+	var jobs []*ExportJob
+	if err := s.db.WithContext(ctx).
+		Where("enabled = ? AND next_run_at <= ?", true, time.Now()).
+		Find(&jobs).Error; err != nil {
+		s.logger.Error("failed to load due export jobs", zap.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		if _, err := s.RunJob(ctx, job.ID); err != nil {
+			s.logger.Error("export job failed",
+				zap.String("job", job.Name), zap.Error(err))
+		}
+	}
+}
+
+// CreateJob 创建导出作业
+func (s *Service) CreateJob(ctx context.Context, req *CreateJobRequest) (*ExportJob, error) {
+	if !exportableTables[req.SourceTable] {
+		return nil, fmt.Errorf("table not exportable: %s", req.SourceTable)
+	}
+	schedule, err := cron.ParseStandard(req.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", req.CronExpr, err)
+	}
+
+	job := &ExportJob{
+		ID:           uuid.NewString(),
+		Name:         req.Name,
+		SourceTable:  req.SourceTable,
+		TargetTable:  req.TargetTable,
+		CursorColumn: req.CursorColumn,
+		CronExpr:     req.CronExpr,
+		Enabled:      true,
+		NextRunAt:    schedule.Next(time.Now()),
+	}
+	if job.CursorColumn == "" {
+		job.CursorColumn = "updated_at"
+	}
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+	return job, nil
+}
+
+// ListJobs 列出导出作业
+func (s *Service) ListJobs(ctx context.Context) ([]*ExportJob, error) {
+	var jobs []*ExportJob
+	if err := s.db.WithContext(ctx).Order("created_at").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// SetEnabled 启用/停用作业
+func (s *Service) SetEnabled(ctx context.Context, jobID string, enabled bool) error {
+	result := s.db.WithContext(ctx).Model(&ExportJob{}).
+		Where("id = ?", jobID).Update("enabled", enabled)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update export job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("export job not found: %s", jobID)
+	}
+	return nil
+}
+
+// ListRuns 列出作业的最近运行记录
+func (s *Service) ListRuns(ctx context.Context, jobID string, limit int) ([]*ExportRun, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	var runs []*ExportRun
+	query := s.db.WithContext(ctx).Order("started_at DESC").Limit(limit)
+	if jobID != "" {
+		query = query.Where("job_id = ?", jobID)
+	}
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list export runs: %w", err)
+	}
+	return runs, nil
+}
+
+// RunJob 立即执行一次增量导出
+func (s *Service) RunJob(ctx context.Context, jobID string) (*ExportRun, error) {
+	var job ExportJob
+	if err := s.db.WithContext(ctx).First(&job, "id = ?", jobID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("export job not found: %s", jobID)
+		}
+		return nil, fmt.Errorf("failed to load export job: %w", err)
+	}
+
+	run := &ExportRun{
+		ID:        uuid.NewString(),
+		JobID:     job.ID,
+		Status:    RunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to create export run: %w", err)
+	}
+
+	rows, watermark, err := s.export(ctx, &job)
+	now := time.Now()
+	run.FinishedAt = &now
+	run.RowsCount = rows
+	if err != nil {
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = RunStatusSuccess
+		run.Watermark = watermark
+	}
+	if saveErr := s.db.WithContext(ctx).Save(run).Error; saveErr != nil {
+		s.logger.Error("failed to save export run", zap.Error(saveErr))
+	}
+
+	// 成功后推进水位并计算下次运行时间
+	updates := map[string]interface{}{}
+	if schedule, parseErr := cron.ParseStandard(job.CronExpr); parseErr == nil {
+		updates["next_run_at"] = schedule.Next(time.Now())
+	}
+	if err == nil && watermark != nil {
+		updates["watermark"] = watermark
+	}
+	if len(updates) > 0 {
+		s.db.WithContext(ctx).Model(&job).Updates(updates)
+	}
+
+	if err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// export 读取水位之后的行并推送到仓库，返回行数与新水位
+func (s *Service) export(ctx context.Context, job *ExportJob) (int, *time.Time, error) {
+	query := s.db.WithContext(ctx).Table(job.SourceTable).
+		Order(job.CursorColumn).Limit(exportBatchSize)
+	if job.Watermark != nil {
+		query = query.Where(job.CursorColumn+" > ?", *job.Watermark)
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		return 0, nil, fmt.Errorf("failed to read source rows: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, job.Watermark, nil
+	}
+
+	if err := s.ensureSchema(ctx, job, rows); err != nil {
+		return 0, nil, err
+	}
+	if err := s.push(ctx, job.TargetTable, rows); err != nil {
+		return 0, nil, err
+	}
+
+	watermark := latestCursor(rows, job.CursorColumn)
+	if watermark == nil {
+		watermark = job.Watermark
+	}
+	return len(rows), watermark, nil
+}
+
+// ensureSchema 处理模式演进：源表出现新列时在目标表补齐为String列
+func (s *Service) ensureSchema(ctx context.Context, job *ExportJob, rows []map[string]interface{}) error {
+	known := map[string]bool{}
+	for _, column := range strings.Split(job.SchemaColumns, ",") {
+		if column != "" {
+			known[column] = true
+		}
+	}
+
+	var added []string
+	for column := range rows[0] {
+		if !known[column] {
+			added = append(added, column)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	sort.Strings(added)
+
+	// 首次导出时目标表结构由仓库侧预建，只记录列集合
+	if len(known) > 0 && s.sinkURL != "" {
+		for _, column := range added {
+			statement := fmt.Sprintf(
+				"ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s String", job.TargetTable, column)
+			if err := s.execute(ctx, statement, nil); err != nil {
+				return fmt.Errorf("failed to evolve target schema: %w", err)
+			}
+		}
+	}
+
+	for column := range known {
+		added = append(added, column)
+	}
+	sort.Strings(added)
+	return s.db.WithContext(ctx).Model(job).
+		Update("schema_columns", strings.Join(added, ",")).Error
+}
+
+// push 以JSONEachRow格式批量写入目标表
+func (s *Service) push(ctx context.Context, targetTable string, rows []map[string]interface{}) error {
+	if s.sinkURL == "" {
+		return fmt.Errorf("warehouse sink is not configured")
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode row: %w", err)
+		}
+	}
+	statement := fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", targetTable)
+	return s.execute(ctx, statement, &body)
+}
+
+// execute 通过ClickHouse HTTP接口执行语句
+func (s *Service) execute(ctx context.Context, statement string, body io.Reader) error {
+	if body == nil {
+		body = strings.NewReader("")
+	}
+	endpoint := s.sinkURL + "/?query=" + url.QueryEscape(statement)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to build warehouse request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("warehouse request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("warehouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// latestCursor 从导出的行中取最大游标值作为新水位
+func latestCursor(rows []map[string]interface{}, cursorColumn string) *time.Time {
+	var latest *time.Time
+	for _, row := range rows {
+		value, ok := row[cursorColumn]
+		if !ok {
+			continue
+		}
+		var cursor time.Time
+		switch typed := value.(type) {
+		case time.Time:
+			cursor = typed
+		case string:
+			parsed, err := time.Parse(time.RFC3339, typed)
+			if err != nil {
+				continue
+			}
+			cursor = parsed
+		default:
+			continue
+		}
+		if latest == nil || cursor.After(*latest) {
+			current := cursor
+			latest = &current
+		}
+	}
+	return latest
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler webhook管理HTTP处理器
+type Handler struct {
+	manager *Manager
+}
+
+// NewHandler 创建webhook处理器
+func NewHandler(manager *Manager) *Handler {
+	return &Handler{manager: manager}
+}
+
+// SetupRoutes 注册webhook管理相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("", h.Register)
+		webhooks.GET("", h.List)
+		webhooks.DELETE("/:webhookId", h.Remove)
+		webhooks.PUT("/:webhookId/active", h.SetActive)
+		webhooks.GET("/:webhookId/deliveries", h.Deliveries)
+	}
+}
+
+// Register 注册端点
+func (h *Handler) Register(c *gin.Context) {
+	var req struct {
+		URL       string `json:"url" binding:"required,url"`
+		Secret    string `json:"secret" binding:"required"`
+		Events    string `json:"events" binding:"required"`
+		CreatedBy string `json:"createdBy"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := h.manager.Register(c.Request.Context(), req.URL, req.Secret, req.Events, req.CreatedBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// List 列出端点
+func (h *Handler) List(c *gin.Context) {
+	endpoints, err := h.manager.ListEndpoints(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": endpoints, "count": len(endpoints)})
+}
+
+// Remove 删除端点
+func (h *Handler) Remove(c *gin.Context) {
+	if err := h.manager.Remove(c.Request.Context(), c.Param("webhookId")); err != nil {
+		c.JSON(statusForWebhookError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "webhook removed"})
+}
+
+// SetActive 启用/停用端点
+func (h *Handler) SetActive(c *gin.Context) {
+	var req struct {
+		Active bool `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.manager.SetActive(c.Request.Context(), c.Param("webhookId"), req.Active); err != nil {
+		c.JSON(statusForWebhookError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "webhook updated"})
+}
+
+// Deliveries 查询最近投递记录
+func (h *Handler) Deliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	deliveries, err := h.manager.Deliveries(c.Request.Context(), c.Param("webhookId"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "count": len(deliveries)})
+}
+
+// statusForWebhookError 把服务层错误映射为HTTP状态码
+func statusForWebhookError(err error) int {
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,349 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 投递状态
+const (
+	DeliveryPending = "pending"
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
+// maxAttempts 单条投递的最大尝试次数
+const maxAttempts = 5
+
+// retryBackoff 第n次失败后的重试间隔（指数退避）
+var retryBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// Endpoint 外发webhook端点
+type Endpoint struct {
+	ID  string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	URL string `json:"url" gorm:"not null"`
+	// Secret HMAC签名密钥，响应中不回显
+	Secret string `json:"-" gorm:"not null"`
+	// Events 订阅的事件列表，逗号分隔，如 "task.created,post.created"
+	Events    string    `json:"events" gorm:"type:text;not null"`
+	Active    bool      `json:"active" gorm:"default:true"`
+	CreatedBy string    `json:"createdBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Endpoint) TableName() string {
+	return "cs_webhook_endpoints"
+}
+
+// Delivery 单次投递记录
+type Delivery struct {
+	ID         string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	EndpointID string         `json:"endpointId" gorm:"index;type:varchar(36);not null"`
+	Event      string         `json:"event" gorm:"type:varchar(64);not null"`
+	Payload    datatypes.JSON `json:"payload" gorm:"type:jsonb"`
+	Status     string         `json:"status" gorm:"type:varchar(16);index;default:'pending'"`
+	Attempts   int            `json:"attempts" gorm:"default:0"`
+	// NextRetryAt 下次尝试时间，成功或放弃后清空
+	NextRetryAt  *time.Time `json:"nextRetryAt,omitempty" gorm:"index"`
+	ResponseCode int        `json:"responseCode,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Delivery) TableName() string {
+	return "cs_webhook_deliveries"
+}
+
+// Manager 外发webhook管理器
+//
+// 事件发布后为每个订阅端点生成投递记录，后台循环按
+// 指数退避重试，payload用端点密钥做HMAC-SHA256签名。
+type Manager struct {
+	db       *gorm.DB
+	logger   *zap.Logger
+	client   *http.Client
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager 创建webhook管理器
+func NewManager(db *gorm.DB, logger *zap.Logger) (*Manager, error) {
+	if err := db.AutoMigrate(&Endpoint{}, &Delivery{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate webhook tables: %w", err)
+	}
+	return &Manager{
+		db:     db,
+		logger: logger,
+		client: &http.Client{Timeout: 15 * time.Second},
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start 启动投递循环
+func (m *Manager) Start() {
+	go m.deliveryLoop()
+}
+
+// Stop 停止投递循环
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+// Register 注册webhook端点
+func (m *Manager) Register(ctx context.Context, url, secret, events, createdBy string) (*Endpoint, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("secret is required for payload signing")
+	}
+	endpoint := &Endpoint{
+		ID:        uuid.NewString(),
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatedBy: createdBy,
+	}
+	if err := m.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		return nil, fmt.Errorf("failed to register webhook: %w", err)
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints 列出已注册端点
+func (m *Manager) ListEndpoints(ctx context.Context) ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	if err := m.db.WithContext(ctx).Order("created_at DESC").Find(&endpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return endpoints, nil
+}
+
+// Remove 删除端点
+func (m *Manager) Remove(ctx context.Context, id string) error {
+	result := m.db.WithContext(ctx).Delete(&Endpoint{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("webhook not found: %s", id)
+	}
+	return nil
+}
+
+// SetActive 启用/停用端点
+func (m *Manager) SetActive(ctx context.Context, id string, active bool) error {
+	result := m.db.WithContext(ctx).Model(&Endpoint{}).Where("id = ?", id).Update("active", active)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("webhook not found: %s", id)
+	}
+	return nil
+}
+
+// Publish 发布事件，为所有订阅端点生成待投递记录
+func (m *Manager) Publish(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		m.logger.Error("failed to marshal webhook payload", zap.Error(err))
+		return
+	}
+
+	ctx := context.Background()
+	var endpoints []*Endpoint
+	if err := m.db.WithContext(ctx).Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		m.logger.Error("failed to load webhook endpoints", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		if !subscribes(endpoint.Events, event) {
+			continue
+		}
+		delivery := &Delivery{
+			ID:          uuid.NewString(),
+			EndpointID:  endpoint.ID,
+			Event:       event,
+			Payload:     datatypes.JSON(data),
+			Status:      DeliveryPending,
+			NextRetryAt: &now,
+		}
+		if err := m.db.WithContext(ctx).Create(delivery).Error; err != nil {
+			m.logger.Error("failed to enqueue webhook delivery", zap.Error(err))
+		}
+	}
+}
+
+// Deliveries 查询端点最近的投递记录
+func (m *Manager) Deliveries(ctx context.Context, endpointID string, limit int) ([]*Delivery, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	var deliveries []*Delivery
+	err := m.db.WithContext(ctx).
+		Where("endpoint_id = ?", endpointID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// subscribes 判断端点是否订阅了事件
+func subscribes(events, event string) bool {
+	for _, subscribed := range strings.Split(events, ",") {
+		subscribed = strings.TrimSpace(subscribed)
+		if subscribed == event || subscribed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// deliveryLoop 周期性投递到期的记录
+func (m *Manager) deliveryLoop() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.deliverDue(context.Background())
+		}
+	}
+}
+
+// deliverDue 尝试投递所有到期记录
+func (m *Manager) deliverDue(ctx context.Context) {
+	var due []*Delivery
+	err := m.db.WithContext(ctx).
+		Where("status = ? AND next_retry_at <= ?", DeliveryPending, time.Now()).
+		Limit(100).
+		Find(&due).Error
+	if err != nil {
+		m.logger.Error("failed to query due deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range due {
+		m.attempt(ctx, delivery)
+	}
+}
+
+// attempt 执行一次投递并更新状态
+func (m *Manager) attempt(ctx context.Context, delivery *Delivery) {
+	var endpoint Endpoint
+	if err := m.db.WithContext(ctx).First(&endpoint, "id = ?", delivery.EndpointID).Error; err != nil {
+		// 端点已删除，放弃投递
+		m.finish(ctx, delivery, DeliveryFailed, 0, "endpoint removed")
+		return
+	}
+
+	code, err := m.send(ctx, &endpoint, delivery)
+	if err == nil && code >= 200 && code < 300 {
+		m.finish(ctx, delivery, DeliverySuccess, code, "")
+		return
+	}
+
+	lastError := fmt.Sprintf("status %d", code)
+	if err != nil {
+		lastError = err.Error()
+	}
+	delivery.Attempts++
+	if delivery.Attempts >= maxAttempts {
+		m.finish(ctx, delivery, DeliveryFailed, code, lastError)
+		return
+	}
+
+	backoff := retryBackoff[len(retryBackoff)-1]
+	if delivery.Attempts-1 < len(retryBackoff) {
+		backoff = retryBackoff[delivery.Attempts-1]
+	}
+	next := time.Now().Add(backoff)
+	err = m.db.WithContext(ctx).Model(&Delivery{}).Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"attempts":      delivery.Attempts,
+			"next_retry_at": next,
+			"response_code": code,
+			"last_error":    lastError,
+		}).Error
+	if err != nil {
+		m.logger.Error("failed to update delivery", zap.Error(err))
+	}
+}
+
+// send 发送签名后的payload
+func (m *Manager) send(ctx context.Context, endpoint *Endpoint, delivery *Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Signature", "sha256="+sign(endpoint.Secret, body))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// finish 写入最终状态
+func (m *Manager) finish(ctx context.Context, delivery *Delivery, status string, code int, lastError string) {
+	attempts := delivery.Attempts
+	if status == DeliverySuccess {
+		attempts++
+	}
+	err := m.db.WithContext(ctx).Model(&Delivery{}).Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"attempts":      attempts,
+			"next_retry_at": nil,
+			"response_code": code,
+			"last_error":    lastError,
+		}).Error
+	if err != nil {
+		m.logger.Error("failed to finalize delivery", zap.Error(err))
+	}
+}
+
+// sign HMAC-SHA256十六进制签名
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
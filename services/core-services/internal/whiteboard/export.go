@@ -0,0 +1,227 @@
+package whiteboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// 默认导出画布尺寸
+const (
+	defaultCanvasWidth  = 1600
+	defaultCanvasHeight = 900
+)
+
+// drawing 事件数据中的绘制属性
+type drawing struct {
+	Shape  string       `json:"shape,omitempty"` // rect / ellipse / line
+	Points [][2]float64 `json:"points,omitempty"`
+	X      float64      `json:"x,omitempty"`
+	Y      float64      `json:"y,omitempty"`
+	W      float64      `json:"w,omitempty"`
+	H      float64      `json:"h,omitempty"`
+	Color  string       `json:"color,omitempty"`
+	Width  float64      `json:"width,omitempty"`
+	// Target 擦除操作指向的事件序号
+	Target int64 `json:"target,omitempty"`
+}
+
+// ExportSVG 把白板当前内容渲染为SVG
+func (s *Service) ExportSVG(ctx context.Context, boardID string, width, height int) ([]byte, error) {
+	drawings, err := s.visibleDrawings(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 {
+		width = defaultCanvasWidth
+	}
+	if height <= 0 {
+		height = defaultCanvasHeight
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height, width, height)
+	buf.WriteString(`<rect width="100%" height="100%" fill="white"/>`)
+
+	for _, d := range drawings {
+		stroke := d.Color
+		if stroke == "" {
+			stroke = "#000000"
+		}
+		strokeWidth := d.Width
+		if strokeWidth <= 0 {
+			strokeWidth = 2
+		}
+
+		switch {
+		case len(d.Points) > 0:
+			points := make([]string, 0, len(d.Points))
+			for _, p := range d.Points {
+				points = append(points, fmt.Sprintf("%g,%g", p[0], p[1]))
+			}
+			fmt.Fprintf(&buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linecap="round"/>`,
+				strings.Join(points, " "), stroke, strokeWidth)
+		case d.Shape == "rect":
+			fmt.Fprintf(&buf, `<rect x="%g" y="%g" width="%g" height="%g" fill="none" stroke="%s" stroke-width="%g"/>`,
+				d.X, d.Y, d.W, d.H, stroke, strokeWidth)
+		case d.Shape == "ellipse":
+			fmt.Fprintf(&buf, `<ellipse cx="%g" cy="%g" rx="%g" ry="%g" fill="none" stroke="%s" stroke-width="%g"/>`,
+				d.X+d.W/2, d.Y+d.H/2, d.W/2, d.H/2, stroke, strokeWidth)
+		case d.Shape == "line":
+			fmt.Fprintf(&buf, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-width="%g"/>`,
+				d.X, d.Y, d.X+d.W, d.Y+d.H, stroke, strokeWidth)
+		}
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// ExportPNG 把白板当前内容栅格化为PNG
+func (s *Service) ExportPNG(ctx context.Context, boardID string, width, height int) ([]byte, error) {
+	drawings, err := s.visibleDrawings(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 {
+		width = defaultCanvasWidth
+	}
+	if height <= 0 {
+		height = defaultCanvasHeight
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	for _, d := range drawings {
+		c := parseColor(d.Color)
+		switch {
+		case len(d.Points) > 1:
+			for i := 1; i < len(d.Points); i++ {
+				drawLine(img, d.Points[i-1], d.Points[i], c)
+			}
+		case d.Shape == "rect":
+			corners := [][2]float64{
+				{d.X, d.Y}, {d.X + d.W, d.Y}, {d.X + d.W, d.Y + d.H}, {d.X, d.Y + d.H},
+			}
+			for i := range corners {
+				drawLine(img, corners[i], corners[(i+1)%4], c)
+			}
+		case d.Shape == "line":
+			drawLine(img, [2]float64{d.X, d.Y}, [2]float64{d.X + d.W, d.Y + d.H}, c)
+		case d.Shape == "ellipse":
+			drawEllipse(img, d.X+d.W/2, d.Y+d.H/2, d.W/2, d.H/2, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// visibleDrawings 重放事件得到当前可见的绘制集合（应用擦除）
+func (s *Service) visibleDrawings(ctx context.Context, boardID string) ([]*drawing, error) {
+	if _, err := s.GetBoard(ctx, boardID); err != nil {
+		return nil, err
+	}
+	events, err := s.EventsSince(ctx, boardID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[int64]*drawing, len(events))
+	order := make([]int64, 0, len(events))
+	for _, event := range events {
+		var d drawing
+		if err := json.Unmarshal(event.Data, &d); err != nil {
+			continue
+		}
+		if event.Kind == "erase" {
+			delete(visible, d.Target)
+			continue
+		}
+		visible[event.Seq] = &d
+		order = append(order, event.Seq)
+	}
+
+	drawings := make([]*drawing, 0, len(visible))
+	for _, seq := range order {
+		if d, ok := visible[seq]; ok {
+			drawings = append(drawings, d)
+		}
+	}
+	return drawings, nil
+}
+
+// parseColor 解析 #RRGGBB 颜色，失败时返回黑色
+func parseColor(hex string) color.RGBA {
+	c := color.RGBA{A: 255}
+	if len(hex) == 7 && hex[0] == '#' {
+		fmt.Sscanf(hex[1:], "%02x%02x%02x", &c.R, &c.G, &c.B)
+	}
+	return c
+}
+
+// drawLine Bresenham画线
+func drawLine(img *image.RGBA, from, to [2]float64, c color.RGBA) {
+	x0, y0 := int(from[0]), int(from[1])
+	x1, y1 := int(to[0]), int(to[1])
+
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawEllipse 按参数方程近似画椭圆
+func drawEllipse(img *image.RGBA, cx, cy, rx, ry float64, c color.RGBA) {
+	const segments = 180
+	prev := [2]float64{cx + rx, cy}
+	for i := 1; i <= segments; i++ {
+		angle := float64(i) / segments * 2 * math.Pi
+		point := [2]float64{cx + rx*math.Cos(angle), cy + ry*math.Sin(angle)}
+		drawLine(img, prev, point, c)
+		prev = point
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,184 @@
+package whiteboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+)
+
+// opMessageType hub中白板操作消息的类型标识
+const opMessageType = "whiteboard.op"
+
+// Handler 白板HTTP/WebSocket处理器
+type Handler struct {
+	service *Service
+	hub     *realtime.Hub
+}
+
+// NewHandler 创建白板处理器并在hub上注册操作消息回调
+func NewHandler(service *Service, hub *realtime.Hub) *Handler {
+	h := &Handler{service: service, hub: hub}
+	hub.Handle(opMessageType, h.handleOp)
+	return h
+}
+
+// SetupRoutes 注册白板相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	boards := router.Group("/whiteboards")
+	{
+		boards.POST("", h.CreateBoard)
+		boards.GET("/:boardId", h.GetBoard)
+		boards.GET("/:boardId/events", h.Events)
+		boards.GET("/:boardId/snapshot", h.LatestSnapshot)
+		boards.GET("/:boardId/export.svg", h.ExportSVG)
+		boards.GET("/:boardId/export.png", h.ExportPNG)
+		boards.GET("/:boardId/ws", h.ServeWS)
+	}
+}
+
+// handleOp hub回调：持久化操作、分配序号并广播给房间内其他客户端
+func (h *Handler) handleOp(client *realtime.Client, message *realtime.Message) (*realtime.Message, error) {
+	boardID := strings.TrimPrefix(client.Room, "whiteboard:")
+
+	var op struct {
+		Kind string          `json:"kind"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message.Payload, &op); err != nil {
+		return nil, err
+	}
+
+	event, err := h.service.AppendEvent(client.Context(), boardID, client.UserID, op.Kind, op.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &realtime.Message{Type: opMessageType, From: client.UserID, Payload: payload}, nil
+}
+
+// ServeWS 加入白板的实时协作房间
+func (h *Handler) ServeWS(c *gin.Context) {
+	userID := c.Query("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+
+	board, err := h.service.GetBoard(c.Request.Context(), c.Param("boardId"))
+	if err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.CheckAccess(c.Request.Context(), board, userID); err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.hub.ServeWS(c, "whiteboard:"+board.ID, userID)
+}
+
+// CreateBoard 创建白板
+func (h *Handler) CreateBoard(c *gin.Context) {
+	var req struct {
+		OwnerType string `json:"ownerType" binding:"required,oneof=room project"`
+		OwnerID   string `json:"ownerId" binding:"required"`
+		Title     string `json:"title"`
+		CreatedBy string `json:"createdBy" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := h.service.CreateBoard(c.Request.Context(), req.OwnerType, req.OwnerID, req.Title, req.CreatedBy)
+	if err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, board)
+}
+
+// GetBoard 获取白板
+func (h *Handler) GetBoard(c *gin.Context) {
+	board, err := h.service.GetBoard(c.Request.Context(), c.Param("boardId"))
+	if err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// Events 增量拉取操作事件（断线重连用）
+func (h *Handler) Events(c *gin.Context) {
+	afterSeq, _ := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+
+	events, err := h.service.EventsSince(c.Request.Context(), c.Param("boardId"), afterSeq)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events, "count": len(events)})
+}
+
+// LatestSnapshot 获取最近快照
+func (h *Handler) LatestSnapshot(c *gin.Context) {
+	snapshot, err := h.service.LatestSnapshot(c.Request.Context(), c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if snapshot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no snapshot yet"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// ExportSVG 导出SVG
+func (h *Handler) ExportSVG(c *gin.Context) {
+	width, _ := strconv.Atoi(c.Query("width"))
+	height, _ := strconv.Atoi(c.Query("height"))
+
+	data, err := h.service.ExportSVG(c.Request.Context(), c.Param("boardId"), width, height)
+	if err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", data)
+}
+
+// ExportPNG 导出PNG
+func (h *Handler) ExportPNG(c *gin.Context) {
+	width, _ := strconv.Atoi(c.Query("width"))
+	height, _ := strconv.Atoi(c.Query("height"))
+
+	data, err := h.service.ExportPNG(c.Request.Context(), c.Param("boardId"), width, height)
+	if err != nil {
+		c.JSON(statusForWhiteboardError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+// statusForWhiteboardError 把服务层错误映射为HTTP状态码
+func statusForWhiteboardError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "access denied"):
+		return http.StatusForbidden
+	case strings.Contains(message, "unsupported"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,281 @@
+package whiteboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// snapshotInterval 快照持久化周期
+const snapshotInterval = 2 * time.Minute
+
+// Board 白板
+//
+// 白板归属于聊天房间或项目，访问权限从归属方继承。
+type Board struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	OwnerType string `json:"ownerType" gorm:"type:varchar(16);not null"` // room / project
+	OwnerID   string `json:"ownerId" gorm:"index;type:varchar(36);not null"`
+	Title     string `json:"title"`
+	CreatedBy string `json:"createdBy" gorm:"type:varchar(36)"`
+	// Seq 最近一次操作的序号，服务端单调递增，保证操作全序
+	Seq       int64     `json:"seq" gorm:"default:0"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Board) TableName() string {
+	return "cs_whiteboards"
+}
+
+// Event 白板操作事件（笔画、图形、擦除）
+type Event struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	BoardID string `json:"boardId" gorm:"uniqueIndex:idx_wb_event_seq;type:varchar(36);not null"`
+	// Seq 服务端分配的操作序号，客户端按序重放即可收敛到一致画面
+	Seq       int64          `json:"seq" gorm:"uniqueIndex:idx_wb_event_seq;not null"`
+	UserID    string         `json:"userId" gorm:"type:varchar(36)"`
+	Kind      string         `json:"kind" gorm:"type:varchar(16)"` // stroke / shape / erase
+	Data      datatypes.JSON `json:"data" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Event) TableName() string {
+	return "cs_whiteboard_events"
+}
+
+// Snapshot 周期性落盘的白板快照
+type Snapshot struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	BoardID string `json:"boardId" gorm:"index;type:varchar(36);not null"`
+	// UpToSeq 快照包含的最后一个操作序号
+	UpToSeq   int64          `json:"upToSeq" gorm:"not null"`
+	Data      datatypes.JSON `json:"data" gorm:"type:jsonb"` // 截至UpToSeq的全部事件
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Snapshot) TableName() string {
+	return "cs_whiteboard_snapshots"
+}
+
+// AccessChecker 白板访问权限检查接口（由房间或项目模块实现）
+type AccessChecker interface {
+	CanAccess(ctx context.Context, userID, ownerType, ownerID string) (bool, error)
+}
+
+// Service 白板服务
+type Service struct {
+	db       *gorm.DB
+	logger   *zap.Logger
+	access   AccessChecker // 可为nil，表示不做权限检查
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService 创建白板服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Board{}, &Event{}, &Snapshot{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate whiteboard tables: %w", err)
+	}
+	return &Service{
+		db:     db,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// SetAccessChecker 接入归属方的访问控制
+func (s *Service) SetAccessChecker(access AccessChecker) {
+	s.access = access
+}
+
+// Start 启动快照循环
+func (s *Service) Start() {
+	go s.snapshotLoop()
+}
+
+// Stop 停止快照循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// CreateBoard 创建白板
+func (s *Service) CreateBoard(ctx context.Context, ownerType, ownerID, title, createdBy string) (*Board, error) {
+	if ownerType != "room" && ownerType != "project" {
+		return nil, fmt.Errorf("unsupported owner type: %s", ownerType)
+	}
+
+	board := &Board{
+		ID:        uuid.NewString(),
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		Title:     title,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(board).Error; err != nil {
+		return nil, fmt.Errorf("failed to create whiteboard: %w", err)
+	}
+	return board, nil
+}
+
+// GetBoard 获取白板
+func (s *Service) GetBoard(ctx context.Context, id string) (*Board, error) {
+	var board Board
+	if err := s.db.WithContext(ctx).First(&board, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("whiteboard not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get whiteboard: %w", err)
+	}
+	return &board, nil
+}
+
+// CheckAccess 校验用户对白板的访问权限（继承自归属房间/项目）
+func (s *Service) CheckAccess(ctx context.Context, board *Board, userID string) error {
+	if s.access == nil {
+		return nil
+	}
+	allowed, err := s.access.CanAccess(ctx, userID, board.OwnerType, board.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to check whiteboard access: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("access denied to whiteboard %s", board.ID)
+	}
+	return nil
+}
+
+// AppendEvent 追加一个操作事件并分配全局序号
+//
+// 序号在行锁保护下递增，并发操作获得确定的全序，
+// 客户端按序号重放即可收敛到一致画面。
+func (s *Service) AppendEvent(ctx context.Context, boardID, userID, kind string, data json.RawMessage) (*Event, error) {
+	if kind != "stroke" && kind != "shape" && kind != "erase" {
+		return nil, fmt.Errorf("unsupported event kind: %s", kind)
+	}
+
+	event := &Event{
+		BoardID: boardID,
+		UserID:  userID,
+		Kind:    kind,
+		Data:    datatypes.JSON(data),
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var board Board
+		err := tx.Set("gorm:query_option", "FOR UPDATE").First(&board, "id = ?", boardID).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("whiteboard not found: %s", boardID)
+			}
+			return fmt.Errorf("failed to lock whiteboard: %w", err)
+		}
+
+		event.Seq = board.Seq + 1
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+		return tx.Model(&Board{}).Where("id = ?", boardID).
+			UpdateColumn("seq", event.Seq).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// EventsSince 列出指定序号之后的事件，用于断线重连增量同步
+func (s *Service) EventsSince(ctx context.Context, boardID string, afterSeq int64) ([]*Event, error) {
+	var events []*Event
+	err := s.db.WithContext(ctx).
+		Where("board_id = ? AND seq > ?", boardID, afterSeq).
+		Order("seq ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+// LatestSnapshot 获取最近一次快照，无快照时返回nil
+func (s *Service) LatestSnapshot(ctx context.Context, boardID string) (*Snapshot, error) {
+	var snapshot Snapshot
+	err := s.db.WithContext(ctx).
+		Where("board_id = ?", boardID).
+		Order("up_to_seq DESC").
+		First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// snapshotLoop 周期性为有新事件的白板落盘快照
+func (s *Service) snapshotLoop() {
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.snapshotDirtyBoards(context.Background())
+		}
+	}
+}
+
+// snapshotDirtyBoards 为快照落后于当前序号的白板生成新快照
+func (s *Service) snapshotDirtyBoards(ctx context.Context) {
+	var boards []*Board
+	err := s.db.WithContext(ctx).
+		Where("seq > COALESCE((SELECT MAX(up_to_seq) FROM cs_whiteboard_snapshots WHERE board_id = cs_whiteboards.id), 0)").
+		Find(&boards).Error
+	if err != nil {
+		s.logger.Error("failed to query dirty whiteboards", zap.Error(err))
+		return
+	}
+
+	for _, board := range boards {
+		if err := s.persistSnapshot(ctx, board); err != nil {
+			s.logger.Warn("failed to snapshot whiteboard",
+				zap.String("board_id", board.ID), zap.Error(err))
+		}
+	}
+}
+
+// persistSnapshot 把截至当前序号的全部事件打包为快照
+func (s *Service) persistSnapshot(ctx context.Context, board *Board) error {
+	events, err := s.EventsSince(ctx, board.ID, 0)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	snapshot := &Snapshot{
+		BoardID: board.ID,
+		UpToSeq: events[len(events)-1].Seq,
+		Data:    data,
+	}
+	return s.db.WithContext(ctx).Create(snapshot).Error
+}
@@ -0,0 +1,183 @@
+package wiki
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 知识库HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建知识库处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册知识库相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	wiki := router.Group("/wiki")
+	{
+		wiki.POST("/pages", h.CreatePage)
+		wiki.GET("/pages/:pageId", h.GetPage)
+		wiki.PUT("/pages/:pageId", h.UpdatePage)
+		wiki.GET("/pages/:pageId/history", h.History)
+		wiki.GET("/pages/:pageId/diff", h.Diff)
+		wiki.POST("/pages/:pageId/lock", h.AcquireLock)
+		wiki.DELETE("/pages/:pageId/lock", h.ReleaseLock)
+		wiki.GET("/orgs/:orgId/tree", h.Tree)
+		// 永久链接解析，projectId为空时用"-"占位
+		wiki.GET("/orgs/:orgId/projects/:projectId/pages/:slug", h.Resolve)
+	}
+}
+
+// CreatePage 创建页面
+func (h *Handler) CreatePage(c *gin.Context) {
+	var req CreatePageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.CreatePage(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"page": page, "permalink": h.service.Permalink(page)})
+}
+
+// GetPage 获取页面
+func (h *Handler) GetPage(c *gin.Context) {
+	page, err := h.service.GetPage(c.Request.Context(), c.Param("pageId"))
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"page": page, "permalink": h.service.Permalink(page)})
+}
+
+// Resolve 按永久链接定位页面
+func (h *Handler) Resolve(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "-" {
+		projectID = ""
+	}
+	page, err := h.service.Resolve(c.Request.Context(), c.Param("orgId"), projectID, c.Param("slug"))
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"page": page, "permalink": h.service.Permalink(page)})
+}
+
+// UpdatePage 保存页面新版本
+func (h *Handler) UpdatePage(c *gin.Context) {
+	var req UpdatePageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.UpdatePage(c.Request.Context(), c.Param("pageId"), &req)
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// Tree 列出组织/项目下的页面树
+func (h *Handler) Tree(c *gin.Context) {
+	pages, err := h.service.Tree(c.Request.Context(), c.Param("orgId"), c.Query("projectId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"pages": pages, "count": len(pages)})
+}
+
+// History 列出页面历史版本
+func (h *Handler) History(c *gin.Context) {
+	revisions, err := h.service.History(c.Request.Context(), c.Param("pageId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions, "count": len(revisions)})
+}
+
+// Diff 对比两个版本
+func (h *Handler) Diff(c *gin.Context) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from version"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to version"})
+		return
+	}
+
+	diff, err := h.service.Diff(c.Request.Context(), c.Param("pageId"), from, to)
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"diff": diff})
+}
+
+// AcquireLock 获取编辑锁
+func (h *Handler) AcquireLock(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lock, err := h.service.AcquireLock(c.Request.Context(), c.Param("pageId"), req.UserID)
+	if err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lock)
+}
+
+// ReleaseLock 释放编辑锁
+func (h *Handler) ReleaseLock(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ReleaseLock(c.Request.Context(), c.Param("pageId"), req.UserID); err != nil {
+		c.JSON(statusForWikiError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "lock released"})
+}
+
+// statusForWikiError 把服务层错误映射为HTTP状态码
+func statusForWikiError(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"), strings.Contains(message, "no lock held"):
+		return http.StatusNotFound
+	case strings.Contains(message, "locked by another"), strings.Contains(message, "version conflict"),
+		strings.Contains(message, "lock not held"), strings.Contains(message, "acquire the edit lock"):
+		return http.StatusConflict
+	case strings.Contains(message, "invalid slug"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
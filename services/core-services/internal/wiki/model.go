@@ -0,0 +1,87 @@
+package wiki
+
+import (
+	"time"
+)
+
+// Page 知识库页面（markdown内容）
+//
+// 页面按组织/项目归属，通过ParentID组成层级树；
+// Slug在同一组织+项目内唯一，构成稳定的永久链接。
+type Page struct {
+	ID        string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	OrgID     string `json:"orgId" gorm:"uniqueIndex:idx_wiki_permalink;type:varchar(36);not null"`
+	ProjectID string `json:"projectId" gorm:"uniqueIndex:idx_wiki_permalink;type:varchar(36)"`
+	Slug      string `json:"slug" gorm:"uniqueIndex:idx_wiki_permalink;type:varchar(128);not null"`
+	ParentID  string `json:"parentId,omitempty" gorm:"index;type:varchar(36)"`
+	Title     string `json:"title" gorm:"not null"`
+	Content   string `json:"content" gorm:"type:text"`
+	// Version 每次保存递增，更新时作乐观并发检查
+	Version   int       `json:"version" gorm:"default:1"`
+	UpdatedBy string    `json:"updatedBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Page) TableName() string {
+	return "cs_wiki_pages"
+}
+
+// Revision 页面历史版本
+type Revision struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PageID    string    `json:"pageId" gorm:"uniqueIndex:idx_wiki_revision;type:varchar(36);not null"`
+	Version   int       `json:"version" gorm:"uniqueIndex:idx_wiki_revision;not null"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content" gorm:"type:text"`
+	EditedBy  string    `json:"editedBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Revision) TableName() string {
+	return "cs_wiki_revisions"
+}
+
+// EditLock 编辑锁
+//
+// 同一时刻只允许一个用户编辑页面，锁到期自动失效；
+// 持锁用户可刷新续期。
+type EditLock struct {
+	PageID    string    `json:"pageId" gorm:"primaryKey;type:varchar(36)"`
+	UserID    string    `json:"userId" gorm:"type:varchar(36);not null"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"not null"`
+}
+
+// TableName 指定表名
+func (EditLock) TableName() string {
+	return "cs_wiki_locks"
+}
+
+// DiffLine 版本对比中的一行
+type DiffLine struct {
+	// Op 操作类型：" "未变、"-"删除、"+"新增
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// CreatePageRequest 创建页面请求
+type CreatePageRequest struct {
+	OrgID     string `json:"orgId" binding:"required"`
+	ProjectID string `json:"projectId"`
+	Slug      string `json:"slug" binding:"required"`
+	ParentID  string `json:"parentId"`
+	Title     string `json:"title" binding:"required"`
+	Content   string `json:"content"`
+	AuthorID  string `json:"authorId" binding:"required"`
+}
+
+// UpdatePageRequest 更新页面请求
+type UpdatePageRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content"`
+	// BaseVersion 客户端编辑所基于的版本，不匹配时拒绝保存
+	BaseVersion int    `json:"baseVersion" binding:"required"`
+	EditorID    string `json:"editorId" binding:"required"`
+}
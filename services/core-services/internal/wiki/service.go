@@ -0,0 +1,309 @@
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockTTL 编辑锁有效期，到期未续期自动释放
+const lockTTL = 5 * time.Minute
+
+// Service 知识库服务
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService 创建知识库服务
+func NewService(db *gorm.DB) (*Service, error) {
+	if err := db.AutoMigrate(&Page{}, &Revision{}, &EditLock{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate wiki tables: %w", err)
+	}
+	return &Service{db: db}, nil
+}
+
+// CreatePage 创建页面
+func (s *Service) CreatePage(ctx context.Context, req *CreatePageRequest) (*Page, error) {
+	slug := normalizeSlug(req.Slug)
+	if slug == "" {
+		return nil, fmt.Errorf("invalid slug: %s", req.Slug)
+	}
+	if req.ParentID != "" {
+		if _, err := s.GetPage(ctx, req.ParentID); err != nil {
+			return nil, fmt.Errorf("parent page: %w", err)
+		}
+	}
+
+	page := &Page{
+		ID:        uuid.NewString(),
+		OrgID:     req.OrgID,
+		ProjectID: req.ProjectID,
+		Slug:      slug,
+		ParentID:  req.ParentID,
+		Title:     req.Title,
+		Content:   req.Content,
+		Version:   1,
+		UpdatedBy: req.AuthorID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(page).Error; err != nil {
+			return fmt.Errorf("failed to create page: %w", err)
+		}
+		return tx.Create(revisionOf(page)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetPage 按ID获取页面
+func (s *Service) GetPage(ctx context.Context, id string) (*Page, error) {
+	var page Page
+	if err := s.db.WithContext(ctx).First(&page, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("page not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+	return &page, nil
+}
+
+// Resolve 按永久链接定位页面
+//
+// 永久链接格式 /wiki/{orgId}/{projectId}/{slug}，projectId可为空；
+// 任务与帖子中引用该链接不受页面改名影响。
+func (s *Service) Resolve(ctx context.Context, orgID, projectID, slug string) (*Page, error) {
+	var page Page
+	err := s.db.WithContext(ctx).
+		First(&page, "org_id = ? AND project_id = ? AND slug = ?", orgID, projectID, normalizeSlug(slug)).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("page not found: %s", slug)
+		}
+		return nil, fmt.Errorf("failed to resolve page: %w", err)
+	}
+	return &page, nil
+}
+
+// Permalink 返回页面的永久链接
+func (s *Service) Permalink(page *Page) string {
+	return fmt.Sprintf("/wiki/%s/%s/%s", page.OrgID, page.ProjectID, page.Slug)
+}
+
+// UpdatePage 保存页面新版本
+//
+// 要求编辑者持有有效编辑锁，且基于最新版本编辑（乐观并发检查），
+// 旧内容写入历史版本表。
+func (s *Service) UpdatePage(ctx context.Context, pageID string, req *UpdatePageRequest) (*Page, error) {
+	var page Page
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&page, "id = ?", pageID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("page not found: %s", pageID)
+			}
+			return fmt.Errorf("failed to get page: %w", err)
+		}
+
+		if err := s.checkLockHolder(tx, pageID, req.EditorID); err != nil {
+			return err
+		}
+		if page.Version != req.BaseVersion {
+			return fmt.Errorf("version conflict: page is at version %d, edit based on %d", page.Version, req.BaseVersion)
+		}
+
+		page.Title = req.Title
+		page.Content = req.Content
+		page.Version++
+		page.UpdatedBy = req.EditorID
+		if err := tx.Save(&page).Error; err != nil {
+			return fmt.Errorf("failed to save page: %w", err)
+		}
+		return tx.Create(revisionOf(&page)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Tree 列出组织/项目下的页面树（平铺返回，客户端按ParentID组装）
+func (s *Service) Tree(ctx context.Context, orgID, projectID string) ([]*Page, error) {
+	var pages []*Page
+	err := s.db.WithContext(ctx).
+		Select("id", "org_id", "project_id", "slug", "parent_id", "title", "version", "updated_by", "created_at", "updated_at").
+		Where("org_id = ? AND project_id = ?", orgID, projectID).
+		Order("slug ASC").
+		Find(&pages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	return pages, nil
+}
+
+// History 列出页面历史版本（不含正文）
+func (s *Service) History(ctx context.Context, pageID string) ([]*Revision, error) {
+	var revisions []*Revision
+	err := s.db.WithContext(ctx).
+		Select("id", "page_id", "version", "title", "edited_by", "created_at").
+		Where("page_id = ?", pageID).
+		Order("version DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// Diff 对比页面两个版本的内容
+func (s *Service) Diff(ctx context.Context, pageID string, fromVersion, toVersion int) ([]DiffLine, error) {
+	from, err := s.getRevision(ctx, pageID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getRevision(ctx, pageID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return diffLines(from.Content, to.Content), nil
+}
+
+// AcquireLock 获取编辑锁
+//
+// 无锁或锁已过期时获取成功；持锁用户重复获取视为续期。
+func (s *Service) AcquireLock(ctx context.Context, pageID, userID string) (*EditLock, error) {
+	if _, err := s.GetPage(ctx, pageID); err != nil {
+		return nil, err
+	}
+
+	lock := &EditLock{PageID: pageID, UserID: userID, ExpiresAt: time.Now().Add(lockTTL)}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing EditLock
+		err := tx.First(&existing, "page_id = ?", pageID).Error
+		if err == nil && existing.UserID != userID && existing.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("page is locked by another editor until %s", existing.ExpiresAt.Format(time.RFC3339))
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to check lock: %w", err)
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "page_id"}},
+			UpdateAll: true,
+		}).Create(lock).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// ReleaseLock 释放编辑锁（仅持锁用户）
+func (s *Service) ReleaseLock(ctx context.Context, pageID, userID string) error {
+	result := s.db.WithContext(ctx).Delete(&EditLock{}, "page_id = ? AND user_id = ?", pageID, userID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to release lock: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no lock held by user %s", userID)
+	}
+	return nil
+}
+
+// checkLockHolder 校验编辑者持有有效锁
+func (s *Service) checkLockHolder(tx *gorm.DB, pageID, userID string) error {
+	var lock EditLock
+	err := tx.First(&lock, "page_id = ?", pageID).Error
+	if err == gorm.ErrRecordNotFound {
+		return fmt.Errorf("acquire the edit lock before saving")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check lock: %w", err)
+	}
+	if lock.UserID != userID || lock.ExpiresAt.Before(time.Now()) {
+		return fmt.Errorf("edit lock not held by user %s", userID)
+	}
+	return nil
+}
+
+// getRevision 获取指定版本
+func (s *Service) getRevision(ctx context.Context, pageID string, version int) (*Revision, error) {
+	var revision Revision
+	err := s.db.WithContext(ctx).First(&revision, "page_id = ? AND version = ?", pageID, version).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("revision %d not found for page %s", version, pageID)
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// revisionOf 由页面当前状态生成历史版本记录
+func revisionOf(page *Page) *Revision {
+	return &Revision{
+		PageID:   page.ID,
+		Version:  page.Version,
+		Title:    page.Title,
+		Content:  page.Content,
+		EditedBy: page.UpdatedBy,
+	}
+}
+
+// normalizeSlug 规范化slug：小写、空格转连字符
+func normalizeSlug(slug string) string {
+	slug = strings.TrimSpace(strings.ToLower(slug))
+	slug = strings.ReplaceAll(slug, " ", "-")
+	return strings.Trim(slug, "-/")
+}
+
+// diffLines 基于最长公共子序列的按行对比
+func diffLines(from, to string) []DiffLine {
+	fromLines := strings.Split(from, "\n")
+	toLines := strings.Split(to, "\n")
+
+	// LCS动态规划表
+	lcs := make([][]int, len(fromLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(toLines)+1)
+	}
+	for i := len(fromLines) - 1; i >= 0; i-- {
+		for j := len(toLines) - 1; j >= 0; j-- {
+			if fromLines[i] == toLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(fromLines) && j < len(toLines) {
+		switch {
+		case fromLines[i] == toLines[j]:
+			diff = append(diff, DiffLine{Op: " ", Text: fromLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: "-", Text: fromLines[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: "+", Text: toLines[j]})
+			j++
+		}
+	}
+	for ; i < len(fromLines); i++ {
+		diff = append(diff, DiffLine{Op: "-", Text: fromLines[i]})
+	}
+	for ; j < len(toLines); j++ {
+		diff = append(diff, DiffLine{Op: "+", Text: toLines[j]})
+	}
+	return diff
+}
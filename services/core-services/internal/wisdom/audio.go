@@ -0,0 +1,269 @@
+package wisdom
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 音频状态
+const (
+	AudioReady  = "ready"
+	AudioFailed = "failed"
+)
+
+// audioURLTTL 朗读音频下载链接有效期
+const audioURLTTL = time.Hour
+
+// defaultVoice 默认音色
+const defaultVoice = "default"
+
+// audioMaxChars 单次合成的正文长度上限
+const audioMaxChars = 8000
+
+// Synthesizer 语音合成提供方
+//
+// 消费端接口：具体实现（HTTP服务、云厂商SDK）在main中注入，
+// 本包只关心文本进、音频出。
+type Synthesizer interface {
+	// Synthesize 合成音频，返回音频内容与MIME类型
+	Synthesize(ctx context.Context, text, voice string) ([]byte, string, error)
+}
+
+// AudioStorage 音频对象存储
+//
+// 与附件模块的Backend结构一致，main中直接复用同一后端实例。
+type AudioStorage interface {
+	PresignUpload(objectKey, contentType string, expires time.Duration) (string, error)
+	PresignDownload(objectKey string, expires time.Duration) (string, error)
+	Delete(ctx context.Context, objectKey string) error
+}
+
+// AudioTrack 词条的一条朗读音频
+//
+// ContentHash记录合成时的内容指纹，词条修改后指纹不再匹配，
+// 旧音频视为失效并在下次访问时清理。
+type AudioTrack struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	EntryID string `json:"entryId" gorm:"uniqueIndex:idx_wisdom_audio;type:varchar(36);not null"`
+	Voice   string `json:"voice" gorm:"uniqueIndex:idx_wisdom_audio;type:varchar(32);not null"`
+	// ContentHash 合成时的标题+正文指纹
+	ContentHash string `json:"-" gorm:"type:char(64);not null"`
+	ObjectKey   string `json:"-" gorm:"type:varchar(255);not null"`
+	// ContentType 音频MIME类型，如 audio/mpeg
+	ContentType string `json:"contentType" gorm:"type:varchar(64)"`
+	// Status ready/failed
+	Status    string    `json:"status" gorm:"type:varchar(16);not null"`
+	Error     string    `json:"error,omitempty" gorm:"type:varchar(512)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (AudioTrack) TableName() string {
+	return "cs_wisdom_audio"
+}
+
+// SetAudioPipeline 注入语音合成与对象存储
+func (s *Service) SetAudioPipeline(synth Synthesizer, storage AudioStorage) {
+	s.synth = synth
+	s.audioStore = storage
+}
+
+// entryContentHash 词条内容指纹
+func entryContentHash(entry *Entry) string {
+	sum := sha256.Sum256([]byte(entry.Title + "\x00" + entry.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAudio 为词条合成朗读音频（编辑）
+//
+// 同词条同音色只保留一条：内容未变时直接复用，已失效或失败
+// 的旧音频先清理再重新合成。
+func (s *Service) GenerateAudio(ctx context.Context, userID, entryID, voice string) (*AudioTrack, error) {
+	if s.synth == nil || s.audioStore == nil {
+		return nil, fmt.Errorf("audio pipeline not configured")
+	}
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	if voice == "" {
+		voice = defaultVoice
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	hash := entryContentHash(entry)
+
+	var existing AudioTrack
+	err = s.db.WithContext(ctx).
+		First(&existing, "entry_id = ? AND voice = ?", entryID, voice).Error
+	if err == nil {
+		if existing.Status == AudioReady && existing.ContentHash == hash {
+			return &existing, nil
+		}
+		if err := s.removeTrack(ctx, &existing); err != nil {
+			return nil, err
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load audio track: %w", err)
+	}
+
+	text := entry.Title + "。" + entry.Content
+	if len(text) > audioMaxChars {
+		text = text[:audioMaxChars]
+	}
+	track := &AudioTrack{
+		ID:          uuid.NewString(),
+		EntryID:     entryID,
+		Voice:       voice,
+		ContentHash: hash,
+		ObjectKey:   fmt.Sprintf("wisdom-audio/%s/%s-%s", entryID, voice, hash[:12]),
+	}
+	audio, contentType, err := s.synth.Synthesize(ctx, text, voice)
+	if err != nil {
+		track.Status = AudioFailed
+		track.Error = err.Error()
+		if saveErr := s.db.WithContext(ctx).Create(track).Error; saveErr != nil {
+			return nil, fmt.Errorf("failed to save audio track: %w", saveErr)
+		}
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	if err := s.uploadAudio(ctx, track.ObjectKey, contentType, audio); err != nil {
+		return nil, err
+	}
+	track.ContentType = contentType
+	track.Status = AudioReady
+	if err := s.db.WithContext(ctx).Create(track).Error; err != nil {
+		return nil, fmt.Errorf("failed to save audio track: %w", err)
+	}
+	return track, nil
+}
+
+// uploadAudio 通过预签名URL把音频写入对象存储
+func (s *Service) uploadAudio(ctx context.Context, objectKey, contentType string, audio []byte) error {
+	signed, err := s.audioStore.PresignUpload(objectKey, contentType, 5*time.Minute)
+	if err != nil {
+		return fmt.Errorf("failed to presign audio upload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signed, bytes.NewReader(audio))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload audio: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audio upload returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// AudioURLs 返回词条当前有效的朗读音频链接
+//
+// 内容已变更的旧音频在这里惰性清理，不会把过期朗读暴露给
+// 客户端。
+func (s *Service) AudioURLs(ctx context.Context, entry *Entry) (map[string]string, error) {
+	if s.audioStore == nil {
+		return nil, nil
+	}
+	var tracks []*AudioTrack
+	err := s.db.WithContext(ctx).
+		Where("entry_id = ? AND status = ?", entry.ID, AudioReady).
+		Find(&tracks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audio tracks: %w", err)
+	}
+	hash := entryContentHash(entry)
+	urls := make(map[string]string)
+	for _, track := range tracks {
+		if track.ContentHash != hash {
+			if err := s.removeTrack(ctx, track); err != nil {
+				s.logger.Warn("failed to remove stale audio",
+					zap.String("entry_id", entry.ID), zap.Error(err))
+			}
+			continue
+		}
+		signed, err := s.audioStore.PresignDownload(track.ObjectKey, audioURLTTL)
+		if err != nil {
+			continue
+		}
+		urls[track.Voice] = signed
+	}
+	return urls, nil
+}
+
+// removeTrack 删除音频对象与记录
+func (s *Service) removeTrack(ctx context.Context, track *AudioTrack) error {
+	if track.Status == AudioReady {
+		if err := s.audioStore.Delete(ctx, track.ObjectKey); err != nil {
+			return fmt.Errorf("failed to delete audio object: %w", err)
+		}
+	}
+	if err := s.db.WithContext(ctx).Delete(&AudioTrack{}, "id = ?", track.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete audio track: %w", err)
+	}
+	return nil
+}
+
+// HTTPSynthesizer HTTP语音合成提供方
+//
+// 对接任何实现 POST /synthesize {text, voice} → 音频字节流
+// 的合成服务。
+type HTTPSynthesizer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSynthesizer 创建HTTP合成提供方
+func NewHTTPSynthesizer(baseURL string) *HTTPSynthesizer {
+	return &HTTPSynthesizer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// Synthesize 调用合成服务
+func (p *HTTPSynthesizer) Synthesize(ctx context.Context, text, voice string) ([]byte, string, error) {
+	body, err := json.Marshal(map[string]string{"text": text, "voice": voice})
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/synthesize", bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("tts request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("tts service returned %d", resp.StatusCode)
+	}
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read tts response: %w", err)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "audio/mpeg"
+	}
+	return audio, contentType, nil
+}
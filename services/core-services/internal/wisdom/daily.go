@@ -0,0 +1,355 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/lunar"
+)
+
+// dailyDateLayout 每日一语的日期键格式
+const dailyDateLayout = "2006-01-02"
+
+// dailyNoRepeatDays 算法选取时避开最近N天已选过的词条
+const dailyNoRepeatDays = 30
+
+// dailyDeliverInterval 推送投递检查间隔
+const dailyDeliverInterval = 5 * time.Minute
+
+// DailyPick 某一天的每日一语
+//
+// 日期键唯一，天然充当缓存：同一天的重复请求直接命中已落库
+// 的选取结果。审校可提前为指定日期人工指定词条（curated）。
+type DailyPick struct {
+	ID string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	// Date 本地无关的日期键，按订阅者各自时区解析
+	Date    string `json:"date" gorm:"uniqueIndex;type:varchar(10);not null"`
+	EntryID string `json:"entryId" gorm:"type:varchar(36);not null"`
+	// Curated true表示人工指定，false表示算法选取
+	Curated   bool      `json:"curated"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (DailyPick) TableName() string {
+	return "cs_wisdom_daily_picks"
+}
+
+// DailySubscription 每日一语的推送订阅
+type DailySubscription struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	// Timezone IANA时区名，如 Asia/Shanghai
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null"`
+	// Hour 用户本地的推送小时，0-23
+	Hour    int  `json:"hour"`
+	Enabled bool `json:"enabled"`
+	// LastSentDate 最近一次已推送的本地日期，防止重复投递
+	LastSentDate string    `json:"lastSentDate,omitempty" gorm:"type:varchar(10)"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (DailySubscription) TableName() string {
+	return "cs_wisdom_daily_subscriptions"
+}
+
+// DailyResult 每日一语的返回结构
+type DailyResult struct {
+	Date    string `json:"date"`
+	Entry   *Entry `json:"entry"`
+	Curated bool   `json:"curated"`
+}
+
+// Daily 返回给定时区下今天的每日一语
+//
+// 时区为空时按UTC解析“今天”；首个请求触发选取并落库，之后
+// 同一天的请求都命中同一结果。
+func (s *Service) Daily(ctx context.Context, timezone string) (*DailyResult, error) {
+	loc := time.UTC
+	if timezone != "" {
+		parsed, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", timezone)
+		}
+		loc = parsed
+	}
+	date := time.Now().In(loc).Format(dailyDateLayout)
+	return s.dailyFor(ctx, date)
+}
+
+// dailyFor 取或生成某一天的每日一语
+func (s *Service) dailyFor(ctx context.Context, date string) (*DailyResult, error) {
+	var pick DailyPick
+	err := s.db.WithContext(ctx).First(&pick, "date = ?", date).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load daily pick: %w", err)
+		}
+		picked, err := s.selectDaily(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		pick = *picked
+	}
+	entry, err := s.loadEntry(ctx, pick.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	return &DailyResult{Date: pick.Date, Entry: entry, Curated: pick.Curated}, nil
+}
+
+// selectDaily 算法选取某一天的词条并落库
+//
+// 在避开最近选过的前提下按日期哈希确定性取一条，多实例并发
+// 选取时由日期唯一索引保证只落一条。
+func (s *Service) selectDaily(ctx context.Context, date string) (*DailyPick, error) {
+	var recentIDs []string
+	since := time.Now().AddDate(0, 0, -dailyNoRepeatDays).Format(dailyDateLayout)
+	err := s.db.WithContext(ctx).Model(&DailyPick{}).
+		Where("date >= ?", since).
+		Pluck("entry_id", &recentIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent picks: %w", err)
+	}
+	query := s.db.WithContext(ctx).Where("status = ?", StatusPublished)
+	if len(recentIDs) > 0 {
+		query = query.Where("id NOT IN ?", recentIDs)
+	}
+	var candidates []*Entry
+	if err := query.Order("id ASC").Limit(500).Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to load candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		// 全部近期选过时放开避重约束兜底
+		err := s.db.WithContext(ctx).
+			Where("status = ?", StatusPublished).
+			Order("id ASC").Limit(500).
+			Find(&candidates).Error
+		if err != nil || len(candidates) == 0 {
+			return nil, fmt.Errorf("no published entries available for daily pick")
+		}
+	}
+	// 传统节日优先选应景词条（标题、正文或分类提到节日名）
+	if themed := festivalCandidates(date, candidates); len(themed) > 0 {
+		candidates = themed
+	}
+	hasher := fnv.New32a()
+	hasher.Write([]byte(date))
+	chosen := candidates[int(hasher.Sum32())%len(candidates)]
+
+	pick := &DailyPick{ID: uuid.NewString(), Date: date, EntryID: chosen.ID}
+	err = s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(pick).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save daily pick: %w", err)
+	}
+	// 并发落库时以先写入的为准
+	var saved DailyPick
+	if err := s.db.WithContext(ctx).First(&saved, "date = ?", date).Error; err != nil {
+		return nil, fmt.Errorf("failed to load daily pick: %w", err)
+	}
+	return &saved, nil
+}
+
+// festivalCandidates 筛出与当天传统节日应景的候选词条
+func festivalCandidates(date string, candidates []*Entry) []*Entry {
+	day, err := time.Parse(dailyDateLayout, date)
+	if err != nil {
+		return nil
+	}
+	festivals := lunar.FestivalsOn(day)
+	if len(festivals) == 0 {
+		return nil
+	}
+	var themed []*Entry
+	for _, entry := range candidates {
+		for _, festival := range festivals {
+			// 节日名去掉"节"后缀也算命中，如"中秋"命中"中秋节"
+			short := strings.TrimSuffix(festival, "节")
+			if strings.Contains(entry.Title, short) ||
+				strings.Contains(entry.Category, short) ||
+				strings.Contains(entry.Content, festival) {
+				themed = append(themed, entry)
+				break
+			}
+		}
+	}
+	return themed
+}
+
+// CurateDaily 人工指定某天的每日一语（审校）
+func (s *Service) CurateDaily(ctx context.Context, userID, date, entryID string) (*DailyPick, error) {
+	if err := s.requireRole(ctx, userID, RoleReviewer); err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse(dailyDateLayout, date); err != nil {
+		return nil, fmt.Errorf("invalid date: must be %s", dailyDateLayout)
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != StatusPublished {
+		return nil, fmt.Errorf("invalid entry: only published entries can be curated")
+	}
+	pick := &DailyPick{ID: uuid.NewString(), Date: date, EntryID: entryID, Curated: true}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&DailyPick{}, "date = ?", date).Error; err != nil {
+			return err
+		}
+		return tx.Create(pick).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to curate daily pick: %w", err)
+	}
+	return pick, nil
+}
+
+// DailyHistory 历史每日一语
+func (s *Service) DailyHistory(ctx context.Context, limit int) ([]*DailyResult, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 30
+	}
+	var picks []*DailyPick
+	err := s.db.WithContext(ctx).
+		Order("date DESC").
+		Limit(limit).
+		Find(&picks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily history: %w", err)
+	}
+	entryIDs := make([]string, 0, len(picks))
+	for _, pick := range picks {
+		entryIDs = append(entryIDs, pick.EntryID)
+	}
+	entries, err := s.loadEntryMap(ctx, entryIDs)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*DailyResult, 0, len(picks))
+	for _, pick := range picks {
+		results = append(results, &DailyResult{
+			Date:    pick.Date,
+			Entry:   entries[pick.EntryID],
+			Curated: pick.Curated,
+		})
+	}
+	return results, nil
+}
+
+// SubscribeDaily 订阅或更新每日一语推送
+//
+// timezone留空时按用户的时间偏好投递。
+func (s *Service) SubscribeDaily(ctx context.Context, userID, timezone string, hour int, enabled bool) (*DailySubscription, error) {
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", timezone)
+		}
+	}
+	if hour < 0 || hour > 23 {
+		return nil, fmt.Errorf("invalid hour: must be between 0 and 23")
+	}
+	var sub DailySubscription
+	err := s.db.WithContext(ctx).First(&sub, "user_id = ?", userID).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load subscription: %w", err)
+		}
+		sub = DailySubscription{ID: uuid.NewString(), UserID: userID}
+	}
+	sub.Timezone = timezone
+	sub.Hour = hour
+	sub.Enabled = enabled
+	if err := s.db.WithContext(ctx).Save(&sub).Error; err != nil {
+		return nil, fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// GetDailySubscription 读取推送订阅
+func (s *Service) GetDailySubscription(ctx context.Context, userID string) (*DailySubscription, error) {
+	var sub DailySubscription
+	err := s.db.WithContext(ctx).First(&sub, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &DailySubscription{UserID: userID, Timezone: "UTC"}, nil
+		}
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// deliverDaily 把每日一语投递给到点的订阅者
+//
+// 每个订阅按自己的时区换算本地时间，命中订阅小时且当天未发
+// 过才投递；投递走事件流，由webhook等下游转成实际通知。
+func (s *Service) deliverDaily(ctx context.Context) {
+	var subs []*DailySubscription
+	err := s.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Limit(1000).
+		Find(&subs).Error
+	if err != nil {
+		s.logger.Error("failed to load daily subscriptions", zap.Error(err))
+		return
+	}
+	now := time.Now()
+	for _, sub := range subs {
+		loc := s.subscriptionLocation(ctx, sub)
+		if loc == nil {
+			continue
+		}
+		local := now.In(loc)
+		localDate := local.Format(dailyDateLayout)
+		if local.Hour() != sub.Hour || sub.LastSentDate == localDate {
+			continue
+		}
+		result, err := s.dailyFor(ctx, localDate)
+		if err != nil {
+			s.logger.Warn("failed to resolve daily pick for delivery",
+				zap.String("date", localDate), zap.Error(err))
+			continue
+		}
+		if s.publish != nil {
+			s.publish("wisdom.daily.push", map[string]interface{}{
+				"userId":  sub.UserID,
+				"date":    result.Date,
+				"entryId": result.Entry.ID,
+				"title":   result.Entry.Title,
+			})
+		}
+		sub.LastSentDate = localDate
+		if err := s.db.WithContext(ctx).Save(sub).Error; err != nil {
+			s.logger.Warn("failed to mark daily push sent",
+				zap.String("user_id", sub.UserID), zap.Error(err))
+		}
+	}
+}
+
+// subscriptionLocation 取订阅的投递时区
+//
+// 订阅自带时区优先，留空时落到用户的时间偏好；都拿不到时
+// 返回nil跳过该订阅。
+func (s *Service) subscriptionLocation(ctx context.Context, sub *DailySubscription) *time.Location {
+	if sub.Timezone != "" {
+		if loc, err := time.LoadLocation(sub.Timezone); err == nil {
+			return loc
+		}
+		return nil
+	}
+	if s.locationFor != nil {
+		return s.locationFor(ctx, sub.UserID)
+	}
+	return nil
+}
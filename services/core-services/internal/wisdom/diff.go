@@ -0,0 +1,100 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DiffLine 修订对比中的一行
+type DiffLine struct {
+	// Op +新增 -删除 =未变
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff 两个修订间的逐行对比
+type RevisionDiff struct {
+	EntryID     string      `json:"entryId"`
+	FromVersion int         `json:"fromVersion"`
+	ToVersion   int         `json:"toVersion"`
+	Lines       []*DiffLine `json:"lines"`
+}
+
+// Diff 对比词条的两个修订
+func (s *Service) Diff(ctx context.Context, entryID string, fromVersion, toVersion int) (*RevisionDiff, error) {
+	from, err := s.loadRevision(ctx, entryID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.loadRevision(ctx, entryID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{
+		EntryID:     entryID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Lines:       diffLines(splitLines(from.Content), splitLines(to.Content)),
+	}, nil
+}
+
+// loadRevision 读取指定修订
+func (s *Service) loadRevision(ctx context.Context, entryID string, version int) (*Revision, error) {
+	var revision Revision
+	err := s.db.WithContext(ctx).
+		First(&revision, "entry_id = ? AND version = ?", entryID, version).Error
+	if err != nil {
+		return nil, fmt.Errorf("revision %d not found", version)
+	}
+	return &revision, nil
+}
+
+// splitLines 按行切分正文
+func splitLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
+// diffLines 基于最长公共子序列的逐行对比
+func diffLines(from, to []string) []*DiffLine {
+	// lcs[i][j] = from[i:]与to[j:]的最长公共子序列长度
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []*DiffLine
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, &DiffLine{Op: "=", Text: from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, &DiffLine{Op: "-", Text: from[i]})
+			i++
+		default:
+			lines = append(lines, &DiffLine{Op: "+", Text: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		lines = append(lines, &DiffLine{Op: "-", Text: from[i]})
+	}
+	for ; j < len(to); j++ {
+		lines = append(lines, &DiffLine{Op: "+", Text: to[j]})
+	}
+	return lines
+}
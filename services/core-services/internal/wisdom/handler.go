@@ -0,0 +1,519 @@
+package wisdom
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/i18n"
+)
+
+// Handler 文化智慧HTTP处理器
+type Handler struct {
+	service *Service
+}
+
+// NewHandler 创建文化智慧处理器
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SetupRoutes 注册文化智慧相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	router.POST("/wisdom/roles", h.AssignRole)
+	router.POST("/wisdom/entries", h.CreateEntry)
+	router.GET("/wisdom/entries", h.ListEntries)
+	router.GET("/wisdom/entries/:entryId", h.GetEntry)
+	router.PUT("/wisdom/entries/:entryId", h.UpdateEntry)
+	router.POST("/wisdom/entries/:entryId/submit", h.Submit)
+	router.POST("/wisdom/entries/:entryId/approve", h.Approve)
+	router.POST("/wisdom/entries/:entryId/reject", h.Reject)
+	router.GET("/wisdom/entries/:entryId/revisions", h.ListRevisions)
+	router.GET("/wisdom/entries/:entryId/diff", h.Diff)
+	router.POST("/wisdom/entries/:entryId/interactions", h.RecordInteraction)
+	router.GET("/wisdom/recommendations", h.Recommendations)
+	router.POST("/wisdom/entries/:entryId/audio", h.GenerateAudio)
+	router.GET("/wisdom/entries/:entryId/variants", h.ListVariants)
+	router.PUT("/wisdom/entries/:entryId/variants/:lang", h.UpsertVariant)
+	router.POST("/wisdom/entries/:entryId/variants/:lang/approve", h.ApproveVariant)
+	router.POST("/wisdom/entries/:entryId/variants/:lang/draft", h.DraftVariant)
+	router.PUT("/wisdom/entries/:entryId/progress", h.SaveProgress)
+	router.GET("/wisdom/entries/:entryId/progress", h.GetProgress)
+	router.GET("/wisdom/continue-reading", h.ContinueReading)
+	router.GET("/wisdom/daily", h.Daily)
+	router.GET("/wisdom/daily/history", h.DailyHistory)
+	router.POST("/wisdom/daily/curate", h.CurateDaily)
+	router.GET("/wisdom/daily/subscription", h.GetDailySubscription)
+	router.PUT("/wisdom/daily/subscription", h.SubscribeDaily)
+}
+
+// requireUser 从请求头取用户标识
+func requireUser(c *gin.Context) (string, bool) {
+	userID := strings.TrimSpace(c.GetHeader("X-User-ID"))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// AssignRole 指派创作角色
+func (h *Handler) AssignRole(c *gin.Context) {
+	var req struct {
+		UserID string `json:"userId" binding:"required"`
+		Role   string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	assignment, err := h.service.AssignRole(c.Request.Context(), req.UserID, req.Role)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// CreateEntry 创建草稿词条
+func (h *Handler) CreateEntry(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req EntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.CreateEntry(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ListEntries 列出词条
+func (h *Handler) ListEntries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	entries, err := h.service.ListEntries(c.Request.Context(),
+		c.Query("status"), c.Query("category"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// GetEntry 读取词条，按Accept-Language返回最佳语言变体，
+// 附带有效的朗读音频链接
+func (h *Handler) GetEntry(c *gin.Context) {
+	entry, err := h.service.GetEntry(c.Request.Context(), c.Param("entryId"))
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	lang := c.Query("lang")
+	if lang == "" {
+		lang = i18n.Negotiate(c.GetHeader("Accept-Language"))
+	}
+	served := h.service.Localize(c.Request.Context(), entry, lang)
+	// 音频链接失败不影响词条本身的返回
+	audioURLs, _ := h.service.AudioURLs(c.Request.Context(), entry)
+	c.JSON(http.StatusOK, struct {
+		*Entry
+		Language  string            `json:"language"`
+		AudioURLs map[string]string `json:"audioUrls,omitempty"`
+	}{Entry: entry, Language: served, AudioURLs: audioURLs})
+}
+
+// UpdateEntry 更新词条并生成修订
+func (h *Handler) UpdateEntry(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req EntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.UpdateEntry(c.Request.Context(), userID, c.Param("entryId"), &req)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Submit 提交审校
+func (h *Handler) Submit(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	entry, err := h.service.Submit(c.Request.Context(), userID, c.Param("entryId"))
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Approve 审校通过，可带定时发布时间
+func (h *Handler) Approve(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		PublishAt *time.Time `json:"publishAt"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.Approve(c.Request.Context(), userID, c.Param("entryId"), req.PublishAt)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Reject 驳回词条
+func (h *Handler) Reject(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.service.Reject(c.Request.Context(), userID, c.Param("entryId"), req.Note)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// ListRevisions 列出修订历史
+func (h *Handler) ListRevisions(c *gin.Context) {
+	revisions, err := h.service.ListRevisions(c.Request.Context(), c.Param("entryId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// Diff 对比两个修订
+func (h *Handler) Diff(c *gin.Context) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from version is required"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to version is required"})
+		return
+	}
+	diff, err := h.service.Diff(c.Request.Context(), c.Param("entryId"), from, to)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// RecordInteraction 记录阅读行为
+func (h *Handler) RecordInteraction(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Kind string `json:"kind" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	interaction, err := h.service.RecordInteraction(c.Request.Context(), userID, c.Param("entryId"), req.Kind)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, interaction)
+}
+
+// Recommendations 个性化推荐列表
+func (h *Handler) Recommendations(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	recommendations, err := h.service.Recommendations(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recommendations": recommendations})
+}
+
+// GenerateAudio 为词条合成朗读音频
+func (h *Handler) GenerateAudio(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Voice string `json:"voice"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	track, err := h.service.GenerateAudio(c.Request.Context(), userID, c.Param("entryId"), req.Voice)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, track)
+}
+
+// ListVariants 列出语言变体
+func (h *Handler) ListVariants(c *gin.Context) {
+	variants, err := h.service.ListVariants(c.Request.Context(), c.Param("entryId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"variants": variants})
+}
+
+// UpsertVariant 写入或更新语言变体
+func (h *Handler) UpsertVariant(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Title   string `json:"title" binding:"required"`
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	variant, err := h.service.UpsertVariant(c.Request.Context(), userID,
+		c.Param("entryId"), c.Param("lang"), req.Title, req.Content)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, variant)
+}
+
+// ApproveVariant 审校通过语言变体
+func (h *Handler) ApproveVariant(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	variant, err := h.service.ApproveVariant(c.Request.Context(), userID,
+		c.Param("entryId"), c.Param("lang"))
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, variant)
+}
+
+// DraftVariant AI起草语言变体
+func (h *Handler) DraftVariant(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	variant, err := h.service.DraftVariant(c.Request.Context(), userID,
+		c.Param("entryId"), c.Param("lang"))
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, variant)
+}
+
+// SaveProgress 记录阅读进度
+func (h *Handler) SaveProgress(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Percent  *int   `json:"percent" binding:"required"`
+		Position string `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	progress, err := h.service.SaveProgress(c.Request.Context(), userID, c.Param("entryId"), *req.Percent, req.Position)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetProgress 读取阅读进度
+func (h *Handler) GetProgress(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	progress, err := h.service.GetProgress(c.Request.Context(), userID, c.Param("entryId"))
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// ContinueReading 继续阅读列表
+func (h *Handler) ContinueReading(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	items, err := h.service.ContinueReading(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Daily 今日每日一语
+//
+// 时区优先取tz参数，带用户头时回退到其订阅时区。
+func (h *Handler) Daily(c *gin.Context) {
+	timezone := c.Query("tz")
+	if timezone == "" {
+		if userID := strings.TrimSpace(c.GetHeader("X-User-ID")); userID != "" {
+			if sub, err := h.service.GetDailySubscription(c.Request.Context(), userID); err == nil {
+				timezone = sub.Timezone
+			}
+		}
+	}
+	result, err := h.service.Daily(c.Request.Context(), timezone)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DailyHistory 历史每日一语
+func (h *Handler) DailyHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+	results, err := h.service.DailyHistory(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": results})
+}
+
+// CurateDaily 人工指定某天的每日一语
+func (h *Handler) CurateDaily(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Date    string `json:"date" binding:"required"`
+		EntryID string `json:"entryId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pick, err := h.service.CurateDaily(c.Request.Context(), userID, req.Date, req.EntryID)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pick)
+}
+
+// GetDailySubscription 读取推送订阅
+func (h *Handler) GetDailySubscription(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	sub, err := h.service.GetDailySubscription(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// SubscribeDaily 订阅或更新每日一语推送
+func (h *Handler) SubscribeDaily(c *gin.Context) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Timezone string `json:"timezone" binding:"required"`
+		Hour     *int   `json:"hour" binding:"required"`
+		Enabled  *bool  `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := h.service.SubscribeDaily(c.Request.Context(), userID, req.Timezone, *req.Hour, *req.Enabled)
+	if err != nil {
+		c.JSON(statusForWisdomError(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// statusForWisdomError 根据错误内容映射HTTP状态码
+func statusForWisdomError(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "permission denied"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "invalid transition"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	case strings.Contains(msg, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.Contains(msg, "synthesis failed"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
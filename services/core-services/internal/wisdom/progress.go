@@ -0,0 +1,123 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Progress 用户对单个词条的阅读进度
+//
+// 收藏本身走统一收藏服务（resourceType=wisdom，支持收藏夹），
+// 这里只负责进度与“继续阅读”。
+type Progress struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID  string `json:"userId" gorm:"uniqueIndex:idx_wisdom_progress;type:varchar(36);not null"`
+	EntryID string `json:"entryId" gorm:"uniqueIndex:idx_wisdom_progress;type:varchar(36);not null"`
+	// Percent 阅读进度百分比，0-100
+	Percent int `json:"percent"`
+	// Position 客户端自定义的位置标记，如段落锚点
+	Position  string    `json:"position,omitempty" gorm:"type:varchar(128)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Progress) TableName() string {
+	return "cs_wisdom_progress"
+}
+
+// ContinueItem “继续阅读”列表的一项
+type ContinueItem struct {
+	Entry    *Entry `json:"entry"`
+	Percent  int    `json:"percent"`
+	Position string `json:"position,omitempty"`
+	// UpdatedAt 最后一次阅读时间
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SaveProgress 记录阅读进度
+//
+// 进度只进不退：客户端乱序上报时保留较大的百分比，位置标记
+// 始终取最新。
+func (s *Service) SaveProgress(ctx context.Context, userID, entryID string, percent int, position string) (*Progress, error) {
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid percent: must be between 0 and 100")
+	}
+	if _, err := s.loadEntry(ctx, entryID); err != nil {
+		return nil, err
+	}
+	var progress Progress
+	err := s.db.WithContext(ctx).
+		First(&progress, "user_id = ? AND entry_id = ?", userID, entryID).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load progress: %w", err)
+		}
+		progress = Progress{ID: uuid.NewString(), UserID: userID, EntryID: entryID}
+	}
+	if percent > progress.Percent {
+		progress.Percent = percent
+	}
+	progress.Position = position
+	if err := s.db.WithContext(ctx).Save(&progress).Error; err != nil {
+		return nil, fmt.Errorf("failed to save progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// GetProgress 读取单个词条的阅读进度
+func (s *Service) GetProgress(ctx context.Context, userID, entryID string) (*Progress, error) {
+	var progress Progress
+	err := s.db.WithContext(ctx).
+		First(&progress, "user_id = ? AND entry_id = ?", userID, entryID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &Progress{UserID: userID, EntryID: entryID}, nil
+		}
+		return nil, fmt.Errorf("failed to load progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// ContinueReading 列出未读完的词条，按最近阅读排序
+func (s *Service) ContinueReading(ctx context.Context, userID string, limit int) ([]*ContinueItem, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	var records []*Progress
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND percent < 100", userID).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list progress: %w", err)
+	}
+	entryIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		entryIDs = append(entryIDs, record.EntryID)
+	}
+	entries, err := s.loadEntryMap(ctx, entryIDs)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*ContinueItem, 0, len(records))
+	for _, record := range records {
+		entry, ok := entries[record.EntryID]
+		// 已下线或回到草稿的词条不再出现在继续阅读里
+		if !ok || entry.Status != StatusPublished {
+			continue
+		}
+		items = append(items, &ContinueItem{
+			Entry:     entry,
+			Percent:   record.Percent,
+			Position:  record.Position,
+			UpdatedAt: record.UpdatedAt,
+		})
+	}
+	return items, nil
+}
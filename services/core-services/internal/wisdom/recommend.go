@@ -0,0 +1,341 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 阅读行为类型
+const (
+	InteractionView     = "view"
+	InteractionLike     = "like"
+	InteractionBookmark = "bookmark"
+)
+
+// interactionWeights 行为对兴趣画像的权重
+var interactionWeights = map[string]float64{
+	InteractionView:     1,
+	InteractionLike:     3,
+	InteractionBookmark: 5,
+}
+
+// recommendInterval 推荐结果离线重算间隔
+const recommendInterval = 30 * time.Minute
+
+// recommendCacheTTL 推荐缓存有效期（进程内缓存，本仓库不引入Redis）
+const recommendCacheTTL = 30 * time.Minute
+
+// recommendMaxInteractions 计算时加载的最近行为上限
+const recommendMaxInteractions = 5000
+
+// recommendUserHistory 单用户参与画像的最近行为上限
+const recommendUserHistory = 200
+
+// Interaction 一次阅读行为
+type Interaction struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID  string `json:"userId" gorm:"index;type:varchar(36);not null"`
+	EntryID string `json:"entryId" gorm:"index;type:varchar(36);not null"`
+	// Kind view/like/bookmark
+	Kind      string    `json:"kind" gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Interaction) TableName() string {
+	return "cs_wisdom_interactions"
+}
+
+// Recommendation 一条个性化推荐
+type Recommendation struct {
+	Entry *Entry  `json:"entry"`
+	Score float64 `json:"score"`
+	// Explanation 推荐理由，如“与你收藏的《X》同属'修身'分类”
+	Explanation string `json:"explanation"`
+}
+
+// recommendCacheEntry 单用户的缓存结果
+type recommendCacheEntry struct {
+	items     []*Recommendation
+	expiresAt time.Time
+}
+
+// RecordInteraction 记录一次阅读行为
+func (s *Service) RecordInteraction(ctx context.Context, userID, entryID, kind string) (*Interaction, error) {
+	if _, ok := interactionWeights[kind]; !ok {
+		return nil, fmt.Errorf("invalid interaction kind: %s", kind)
+	}
+	if _, err := s.loadEntry(ctx, entryID); err != nil {
+		return nil, err
+	}
+	interaction := &Interaction{
+		ID:      uuid.NewString(),
+		UserID:  userID,
+		EntryID: entryID,
+		Kind:    kind,
+	}
+	if err := s.db.WithContext(ctx).Create(interaction).Error; err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+	return interaction, nil
+}
+
+// Recommendations 返回用户的个性化推荐
+//
+// 优先读缓存；缓存过期时同步重算一次。无任何行为的用户退化
+// 为最近发布的词条（理由标注为热门回退）。
+func (s *Service) Recommendations(ctx context.Context, userID string, limit int) ([]*Recommendation, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	s.recMu.RLock()
+	cached, ok := s.recCache[userID]
+	s.recMu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return truncateRecommendations(cached.items, limit), nil
+	}
+	items, err := s.computeRecommendations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.recMu.Lock()
+	s.recCache[userID] = &recommendCacheEntry{items: items, expiresAt: time.Now().Add(recommendCacheTTL)}
+	s.recMu.Unlock()
+	return truncateRecommendations(items, limit), nil
+}
+
+// truncateRecommendations 截取前limit条
+func truncateRecommendations(items []*Recommendation, limit int) []*Recommendation {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// computeRecommendations 为单个用户计算推荐
+//
+// 内容侧：按用户交互过的分类加权匹配候选词条；协同侧：与
+// 用户读过相同词条的邻居还读了什么，按共现次数加分。
+func (s *Service) computeRecommendations(ctx context.Context, userID string) ([]*Recommendation, error) {
+	var mine []*Interaction
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(recommendUserHistory).
+		Find(&mine).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load interactions: %w", err)
+	}
+	if len(mine) == 0 {
+		return s.fallbackRecommendations(ctx)
+	}
+
+	// 用户兴趣画像：分类权重 + 已读集合 + 权重最高的代表词条
+	seen := make(map[string]bool)
+	categoryWeight := make(map[string]float64)
+	entryWeight := make(map[string]float64)
+	for _, interaction := range mine {
+		seen[interaction.EntryID] = true
+		entryWeight[interaction.EntryID] += interactionWeights[interaction.Kind]
+	}
+	myEntries, err := s.loadEntryMap(ctx, keys(entryWeight))
+	if err != nil {
+		return nil, err
+	}
+	categoryAnchor := make(map[string]*Entry)
+	for entryID, weight := range entryWeight {
+		entry, ok := myEntries[entryID]
+		if !ok || entry.Category == "" {
+			continue
+		}
+		categoryWeight[entry.Category] += weight
+		anchor := categoryAnchor[entry.Category]
+		if anchor == nil || entryWeight[anchor.ID] < weight {
+			categoryAnchor[entry.Category] = entry
+		}
+	}
+
+	// 协同过滤：读过相同词条的邻居的其他阅读
+	neighborCounts, neighborAnchor, err := s.coOccurrence(ctx, userID, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	// 候选：已发布且未读过的词条
+	var candidates []*Entry
+	err = s.db.WithContext(ctx).
+		Where("status = ?", StatusPublished).
+		Order("published_at DESC").
+		Limit(500).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candidates: %w", err)
+	}
+
+	var result []*Recommendation
+	for _, candidate := range candidates {
+		if seen[candidate.ID] {
+			continue
+		}
+		contentScore := categoryWeight[candidate.Category]
+		collabScore := float64(neighborCounts[candidate.ID]) * 2
+		score := contentScore + collabScore
+		if score <= 0 {
+			continue
+		}
+		explanation := ""
+		if collabScore > contentScore {
+			explanation = fmt.Sprintf("读过《%s》的用户也在读这篇", neighborAnchor[candidate.ID])
+		} else if anchor := categoryAnchor[candidate.Category]; anchor != nil {
+			explanation = fmt.Sprintf("与你读过的《%s》同属「%s」分类", anchor.Title, candidate.Category)
+		}
+		result = append(result, &Recommendation{Entry: candidate, Score: score, Explanation: explanation})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	if len(result) > 50 {
+		result = result[:50]
+	}
+	if len(result) == 0 {
+		return s.fallbackRecommendations(ctx)
+	}
+	return result, nil
+}
+
+// coOccurrence 统计邻居共现：候选词条ID→共现次数与锚点标题
+func (s *Service) coOccurrence(ctx context.Context, userID string, seen map[string]bool) (map[string]int, map[string]string, error) {
+	var neighborIDs []string
+	err := s.db.WithContext(ctx).Model(&Interaction{}).
+		Distinct("user_id").
+		Where("entry_id IN ? AND user_id <> ?", keysBool(seen), userID).
+		Limit(200).
+		Pluck("user_id", &neighborIDs).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load neighbors: %w", err)
+	}
+	counts := make(map[string]int)
+	anchors := make(map[string]string)
+	if len(neighborIDs) == 0 {
+		return counts, anchors, nil
+	}
+	var theirs []*Interaction
+	err = s.db.WithContext(ctx).
+		Where("user_id IN ?", neighborIDs).
+		Order("created_at DESC").
+		Limit(recommendMaxInteractions).
+		Find(&theirs).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load neighbor interactions: %w", err)
+	}
+	// 每个邻居读过的我也读过的词条里，取一个作为推荐理由锚点
+	neighborShared := make(map[string]string)
+	for _, interaction := range theirs {
+		if seen[interaction.EntryID] {
+			neighborShared[interaction.UserID] = interaction.EntryID
+		}
+	}
+	sharedEntries, err := s.loadEntryMap(ctx, values(neighborShared))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, interaction := range theirs {
+		if seen[interaction.EntryID] {
+			continue
+		}
+		counts[interaction.EntryID]++
+		if _, ok := anchors[interaction.EntryID]; !ok {
+			if shared, ok := sharedEntries[neighborShared[interaction.UserID]]; ok {
+				anchors[interaction.EntryID] = shared.Title
+			}
+		}
+	}
+	return counts, anchors, nil
+}
+
+// fallbackRecommendations 无画像用户回退到最近发布
+func (s *Service) fallbackRecommendations(ctx context.Context) ([]*Recommendation, error) {
+	var entries []*Entry
+	err := s.db.WithContext(ctx).
+		Where("status = ?", StatusPublished).
+		Order("published_at DESC").
+		Limit(20).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fallback recommendations: %w", err)
+	}
+	result := make([]*Recommendation, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &Recommendation{Entry: entry, Score: 0, Explanation: "最新发布"})
+	}
+	return result, nil
+}
+
+// loadEntryMap 批量读取词条
+func (s *Service) loadEntryMap(ctx context.Context, entryIDs []string) (map[string]*Entry, error) {
+	entries := make(map[string]*Entry, len(entryIDs))
+	if len(entryIDs) == 0 {
+		return entries, nil
+	}
+	var rows []*Entry
+	if err := s.db.WithContext(ctx).Where("id IN ?", entryIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load entries: %w", err)
+	}
+	for _, entry := range rows {
+		entries[entry.ID] = entry
+	}
+	return entries, nil
+}
+
+// refreshRecommendations 离线重算所有已有缓存用户的推荐
+//
+// 只刷新近期请求过推荐的用户，避免为全量用户做无人消费的计算。
+func (s *Service) refreshRecommendations(ctx context.Context) {
+	s.recMu.RLock()
+	userIDs := make([]string, 0, len(s.recCache))
+	for userID := range s.recCache {
+		userIDs = append(userIDs, userID)
+	}
+	s.recMu.RUnlock()
+	for _, userID := range userIDs {
+		items, err := s.computeRecommendations(ctx, userID)
+		if err != nil {
+			s.logger.Warn("failed to refresh recommendations",
+				zap.String("user_id", userID), zap.Error(err))
+			continue
+		}
+		s.recMu.Lock()
+		s.recCache[userID] = &recommendCacheEntry{items: items, expiresAt: time.Now().Add(recommendCacheTTL)}
+		s.recMu.Unlock()
+	}
+}
+
+// keys map的键集合
+func keys(m map[string]float64) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// keysBool map的键集合
+func keysBool(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// values map的值集合（去重）
+func values(m map[string]string) []string {
+	set := make(map[string]bool, len(m))
+	for _, v := range m {
+		set[v] = true
+	}
+	return keysBool(set)
+}
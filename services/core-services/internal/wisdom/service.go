@@ -0,0 +1,436 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 词条生命周期状态
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+)
+
+// 创作角色
+const (
+	RoleEditor   = "editor"
+	RoleReviewer = "reviewer"
+	RoleAdmin    = "admin"
+)
+
+// publishInterval 定时发布检查间隔
+const publishInterval = time.Minute
+
+// Entry 一条文化智慧词条
+type Entry struct {
+	ID    string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Title string `json:"title" gorm:"type:varchar(255);not null"`
+	// Content 正文，修订历史见Revision
+	Content string `json:"content" gorm:"type:text"`
+	// Source 典籍出处
+	Source   string `json:"source,omitempty" gorm:"type:varchar(255)"`
+	Category string `json:"category,omitempty" gorm:"index;type:varchar(64)"`
+	// Status draft/in_review/scheduled/published
+	Status string `json:"status" gorm:"index;type:varchar(16);not null"`
+	// Version 当前修订号
+	Version   int    `json:"version"`
+	CreatedBy string `json:"createdBy" gorm:"type:varchar(36);not null"`
+	// ReviewNote 驳回时的审校意见
+	ReviewNote string `json:"reviewNote,omitempty" gorm:"type:varchar(512)"`
+	// PublishAt 定时发布时间，scheduled状态到点自动发布
+	PublishAt   *time.Time `json:"publishAt,omitempty" gorm:"index"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Entry) TableName() string {
+	return "cs_wisdom_entries"
+}
+
+// Revision 词条的一次修订
+type Revision struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	EntryID string `json:"entryId" gorm:"uniqueIndex:idx_wisdom_revision;type:varchar(36);not null"`
+	Version int    `json:"version" gorm:"uniqueIndex:idx_wisdom_revision;not null"`
+	Title   string `json:"title" gorm:"type:varchar(255)"`
+	Content string `json:"content" gorm:"type:text"`
+	// EditedBy 本次修订的作者
+	EditedBy  string    `json:"editedBy" gorm:"type:varchar(36)"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Revision) TableName() string {
+	return "cs_wisdom_revisions"
+}
+
+// RoleAssignment 用户在创作流程中的角色
+type RoleAssignment struct {
+	ID     string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID string `json:"userId" gorm:"uniqueIndex;type:varchar(36);not null"`
+	// Role editor/reviewer/admin
+	Role      string    `json:"role" gorm:"type:varchar(16);not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (RoleAssignment) TableName() string {
+	return "cs_wisdom_roles"
+}
+
+// Service 文化智慧创作服务
+//
+// 词条走草稿→审校→发布的工作流：编辑创建与修改草稿并提交
+// 审校，审校通过后立即或按排期发布；每次内容变更都产生修订。
+type Service struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	// recCache 用户→推荐结果的进程内缓存
+	recMu    sync.RWMutex
+	recCache map[string]*recommendCacheEntry
+
+	// publish 事件发布器，每日一语推送走事件流
+	publish func(event string, payload interface{})
+
+	// synth/audioStore 朗读音频管线，未注入时音频功能不可用
+	synth      Synthesizer
+	audioStore AudioStorage
+
+	// completer AI翻译起草，未注入时AI起草端点不可用
+	completer Completer
+	provider  string
+
+	// locationFor 取用户本地时区（接线到时间偏好模块），
+	// 订阅未指定时区时按用户偏好投递
+	locationFor func(ctx context.Context, userID string) *time.Location
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// SetLocationResolver 接线用户时区解析
+func (s *Service) SetLocationResolver(locationFor func(ctx context.Context, userID string) *time.Location) {
+	s.locationFor = locationFor
+}
+
+// SetEventPublisher 接入事件发布（wisdom.daily.push）
+func (s *Service) SetEventPublisher(publish func(event string, payload interface{})) {
+	s.publish = publish
+}
+
+// NewService 创建文化智慧服务
+func NewService(db *gorm.DB, logger *zap.Logger) (*Service, error) {
+	if err := db.AutoMigrate(&Entry{}, &Revision{}, &RoleAssignment{}, &Interaction{}, &Progress{},
+		&DailyPick{}, &DailySubscription{}, &AudioTrack{}, &Variant{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate wisdom tables: %w", err)
+	}
+	return &Service{
+		db:       db,
+		logger:   logger,
+		recCache: make(map[string]*recommendCacheEntry),
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// AssignRole 指派用户的创作角色
+func (s *Service) AssignRole(ctx context.Context, userID, role string) (*RoleAssignment, error) {
+	switch role {
+	case RoleEditor, RoleReviewer, RoleAdmin:
+	default:
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+	assignment := &RoleAssignment{ID: uuid.NewString(), UserID: userID, Role: role}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&RoleAssignment{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		return tx.Create(assignment).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+	return assignment, nil
+}
+
+// roleOf 查用户角色，未指派时返回空串
+func (s *Service) roleOf(ctx context.Context, userID string) (string, error) {
+	var assignment RoleAssignment
+	err := s.db.WithContext(ctx).First(&assignment, "user_id = ?", userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to load role: %w", err)
+	}
+	return assignment.Role, nil
+}
+
+// requireRole 校验用户具备给定角色之一（admin视为全部）
+func (s *Service) requireRole(ctx context.Context, userID string, roles ...string) error {
+	role, err := s.roleOf(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if role == RoleAdmin {
+		return nil
+	}
+	for _, allowed := range roles {
+		if role == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("permission denied: requires %v role", roles)
+}
+
+// EntryRequest 创建/更新词条的参数
+type EntryRequest struct {
+	Title    string `json:"title" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	Source   string `json:"source"`
+	Category string `json:"category"`
+}
+
+// CreateEntry 创建草稿词条（编辑）
+func (s *Service) CreateEntry(ctx context.Context, userID string, req *EntryRequest) (*Entry, error) {
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	entry := &Entry{
+		ID:        uuid.NewString(),
+		Title:     req.Title,
+		Content:   req.Content,
+		Source:    req.Source,
+		Category:  req.Category,
+		Status:    StatusDraft,
+		Version:   1,
+		CreatedBy: userID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return tx.Create(s.revisionOf(entry, userID)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wisdom entry: %w", err)
+	}
+	return entry, nil
+}
+
+// UpdateEntry 更新词条内容并生成新修订（编辑）
+func (s *Service) UpdateEntry(ctx context.Context, userID, entryID string, req *EntryRequest) (*Entry, error) {
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == StatusInReview {
+		return nil, fmt.Errorf("invalid transition: entry is in review, withdraw it first")
+	}
+	entry.Title = req.Title
+	entry.Content = req.Content
+	entry.Source = req.Source
+	entry.Category = req.Category
+	entry.Version++
+	// 已发布词条的修改回到草稿重新走审校
+	if entry.Status == StatusPublished || entry.Status == StatusScheduled {
+		entry.Status = StatusDraft
+		entry.PublishAt = nil
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		return tx.Create(s.revisionOf(entry, userID)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wisdom entry: %w", err)
+	}
+	return entry, nil
+}
+
+// revisionOf 基于词条当前内容构造修订记录
+func (s *Service) revisionOf(entry *Entry, userID string) *Revision {
+	return &Revision{
+		ID:       uuid.NewString(),
+		EntryID:  entry.ID,
+		Version:  entry.Version,
+		Title:    entry.Title,
+		Content:  entry.Content,
+		EditedBy: userID,
+	}
+}
+
+// Submit 提交词条进入审校（编辑）
+func (s *Service) Submit(ctx context.Context, userID, entryID string) (*Entry, error) {
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != StatusDraft {
+		return nil, fmt.Errorf("invalid transition: only drafts can be submitted")
+	}
+	entry.Status = StatusInReview
+	entry.ReviewNote = ""
+	if err := s.db.WithContext(ctx).Save(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to submit wisdom entry: %w", err)
+	}
+	return entry, nil
+}
+
+// Approve 审校通过，立即或按排期发布（审校）
+func (s *Service) Approve(ctx context.Context, userID, entryID string, publishAt *time.Time) (*Entry, error) {
+	if err := s.requireRole(ctx, userID, RoleReviewer); err != nil {
+		return nil, err
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != StatusInReview {
+		return nil, fmt.Errorf("invalid transition: only entries in review can be approved")
+	}
+	now := time.Now()
+	if publishAt != nil && publishAt.After(now) {
+		entry.Status = StatusScheduled
+		entry.PublishAt = publishAt
+	} else {
+		entry.Status = StatusPublished
+		entry.PublishedAt = &now
+	}
+	if err := s.db.WithContext(ctx).Save(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to approve wisdom entry: %w", err)
+	}
+	return entry, nil
+}
+
+// Reject 驳回词条回到草稿（审校）
+func (s *Service) Reject(ctx context.Context, userID, entryID, note string) (*Entry, error) {
+	if err := s.requireRole(ctx, userID, RoleReviewer); err != nil {
+		return nil, err
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != StatusInReview {
+		return nil, fmt.Errorf("invalid transition: only entries in review can be rejected")
+	}
+	entry.Status = StatusDraft
+	entry.ReviewNote = note
+	if err := s.db.WithContext(ctx).Save(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to reject wisdom entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListEntries 列出词条，status为空时只返回已发布的
+func (s *Service) ListEntries(ctx context.Context, status, category string, limit int) ([]*Entry, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if status == "" {
+		status = StatusPublished
+	}
+	query := s.db.WithContext(ctx).Where("status = ?", status)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	var entries []*Entry
+	if err := query.Order("updated_at DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list wisdom entries: %w", err)
+	}
+	return entries, nil
+}
+
+// GetEntry 读取单条词条
+func (s *Service) GetEntry(ctx context.Context, entryID string) (*Entry, error) {
+	return s.loadEntry(ctx, entryID)
+}
+
+// ListRevisions 列出词条的修订历史
+func (s *Service) ListRevisions(ctx context.Context, entryID string) ([]*Revision, error) {
+	var revisions []*Revision
+	err := s.db.WithContext(ctx).
+		Where("entry_id = ?", entryID).
+		Order("version ASC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// loadEntry 读取词条
+func (s *Service) loadEntry(ctx context.Context, entryID string) (*Entry, error) {
+	var entry Entry
+	err := s.db.WithContext(ctx).First(&entry, "id = ?", entryID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("wisdom entry not found")
+		}
+		return nil, fmt.Errorf("failed to load wisdom entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// Start 启动定时发布与推荐重算循环
+func (s *Service) Start() {
+	go func() {
+		publishTicker := time.NewTicker(publishInterval)
+		defer publishTicker.Stop()
+		recommendTicker := time.NewTicker(recommendInterval)
+		defer recommendTicker.Stop()
+		dailyTicker := time.NewTicker(dailyDeliverInterval)
+		defer dailyTicker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-publishTicker.C:
+				s.publishDue(context.Background())
+			case <-recommendTicker.C:
+				s.refreshRecommendations(context.Background())
+			case <-dailyTicker.C:
+				s.deliverDaily(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop 停止后台循环
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// publishDue 发布所有到期的排期词条
+func (s *Service) publishDue(ctx context.Context) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&Entry{}).
+		Where("status = ? AND publish_at <= ?", StatusScheduled, now).
+		Updates(map[string]interface{}{"status": StatusPublished, "published_at": now})
+	if result.Error != nil {
+		s.logger.Error("failed to publish scheduled entries", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		s.logger.Info("published scheduled wisdom entries", zap.Int64("count", result.RowsAffected))
+	}
+}
@@ -0,0 +1,223 @@
+package wisdom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/i18n"
+)
+
+// 语言变体状态
+const (
+	VariantDraft    = "draft"
+	VariantApproved = "approved"
+)
+
+// Completer AI翻译起草的底层补全调用（与ai.Completer同形，
+// 消费端自定义接口避免跨包依赖）
+type Completer interface {
+	Complete(ctx context.Context, provider, prompt string) (string, error)
+}
+
+// Variant 词条的一个语言变体
+//
+// 原文语言（zh-Hans）始终存在Entry本体上，变体只覆盖其他
+// 语言；draft状态的变体不会出现在按语言协商的读取路径里。
+type Variant struct {
+	ID      string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	EntryID string `json:"entryId" gorm:"uniqueIndex:idx_wisdom_variant;type:varchar(36);not null"`
+	// Language 规范语言标签：zh-Hant/en
+	Language string `json:"language" gorm:"uniqueIndex:idx_wisdom_variant;type:varchar(16);not null"`
+	Title    string `json:"title" gorm:"type:varchar(255)"`
+	Content  string `json:"content" gorm:"type:text"`
+	// Status draft/approved
+	Status string `json:"status" gorm:"type:varchar(16);not null"`
+	// Machine true表示AI起草，审校通过后清除
+	Machine      bool      `json:"machine"`
+	TranslatedBy string    `json:"translatedBy,omitempty" gorm:"type:varchar(36)"`
+	ReviewedBy   string    `json:"reviewedBy,omitempty" gorm:"type:varchar(36)"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Variant) TableName() string {
+	return "cs_wisdom_variants"
+}
+
+// SetCompleter 注入AI补全，启用AI辅助翻译起草
+func (s *Service) SetCompleter(completer Completer, provider string) {
+	s.completer = completer
+	s.provider = provider
+}
+
+// validVariantLanguage 变体语言校验：必须在支持集内且不是原文语言
+func validVariantLanguage(lang string) error {
+	if !i18n.Supported(lang) {
+		return fmt.Errorf("invalid language: %s", lang)
+	}
+	if lang == i18n.DefaultLanguage {
+		return fmt.Errorf("invalid language: %s is the source language", lang)
+	}
+	return nil
+}
+
+// UpsertVariant 写入或更新语言变体（编辑），总是回到draft待审
+func (s *Service) UpsertVariant(ctx context.Context, userID, entryID, lang, title, content string) (*Variant, error) {
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := validVariantLanguage(lang); err != nil {
+		return nil, err
+	}
+	if _, err := s.loadEntry(ctx, entryID); err != nil {
+		return nil, err
+	}
+	var variant Variant
+	err := s.db.WithContext(ctx).
+		First(&variant, "entry_id = ? AND language = ?", entryID, lang).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load variant: %w", err)
+		}
+		variant = Variant{ID: uuid.NewString(), EntryID: entryID, Language: lang}
+	}
+	variant.Title = title
+	variant.Content = content
+	variant.Status = VariantDraft
+	variant.Machine = false
+	variant.TranslatedBy = userID
+	variant.ReviewedBy = ""
+	if err := s.db.WithContext(ctx).Save(&variant).Error; err != nil {
+		return nil, fmt.Errorf("failed to save variant: %w", err)
+	}
+	return &variant, nil
+}
+
+// ApproveVariant 审校通过语言变体（审校）
+func (s *Service) ApproveVariant(ctx context.Context, userID, entryID, lang string) (*Variant, error) {
+	if err := s.requireRole(ctx, userID, RoleReviewer); err != nil {
+		return nil, err
+	}
+	var variant Variant
+	err := s.db.WithContext(ctx).
+		First(&variant, "entry_id = ? AND language = ?", entryID, lang).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("variant not found")
+		}
+		return nil, fmt.Errorf("failed to load variant: %w", err)
+	}
+	if variant.Status != VariantDraft {
+		return nil, fmt.Errorf("invalid transition: only draft variants can be approved")
+	}
+	variant.Status = VariantApproved
+	variant.Machine = false
+	variant.ReviewedBy = userID
+	if err := s.db.WithContext(ctx).Save(&variant).Error; err != nil {
+		return nil, fmt.Errorf("failed to approve variant: %w", err)
+	}
+	return &variant, nil
+}
+
+// ListVariants 列出词条的全部语言变体
+func (s *Service) ListVariants(ctx context.Context, entryID string) ([]*Variant, error) {
+	var variants []*Variant
+	err := s.db.WithContext(ctx).
+		Where("entry_id = ?", entryID).
+		Order("language ASC").
+		Find(&variants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variants: %w", err)
+	}
+	return variants, nil
+}
+
+// DraftVariant AI起草语言变体（编辑）
+//
+// 结果以draft+machine落库等待审校，不覆盖已有的人工翻译。
+func (s *Service) DraftVariant(ctx context.Context, userID, entryID, lang string) (*Variant, error) {
+	if s.completer == nil {
+		return nil, fmt.Errorf("ai translation not configured")
+	}
+	if err := s.requireRole(ctx, userID, RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := validVariantLanguage(lang); err != nil {
+		return nil, err
+	}
+	entry, err := s.loadEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	var existing Variant
+	err = s.db.WithContext(ctx).
+		First(&existing, "entry_id = ? AND language = ?", entryID, lang).Error
+	if err == nil && !existing.Machine {
+		return nil, fmt.Errorf("invalid draft: human translation already exists")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load variant: %w", err)
+	}
+
+	languageNames := map[string]string{
+		i18n.LangZhHant: "繁体中文",
+		i18n.LangEn:     "英语",
+	}
+	prompt := fmt.Sprintf(
+		"把下面的文化典籍词条翻译成%s。第一行输出标题译文，其余行输出正文译文，保持原文语气，不要添加解释。\n\n标题：%s\n\n正文：\n%s",
+		languageNames[lang], entry.Title, entry.Content)
+	translated, err := s.completer.Complete(ctx, s.provider, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("translation failed: %w", err)
+	}
+	title, content := splitDraft(translated)
+
+	if existing.ID == "" {
+		existing = Variant{ID: uuid.NewString(), EntryID: entryID, Language: lang}
+	}
+	existing.Title = title
+	existing.Content = content
+	existing.Status = VariantDraft
+	existing.Machine = true
+	existing.TranslatedBy = userID
+	existing.ReviewedBy = ""
+	if err := s.db.WithContext(ctx).Save(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to save variant: %w", err)
+	}
+	return &existing, nil
+}
+
+// splitDraft 把AI译文拆成标题与正文
+func splitDraft(translated string) (string, string) {
+	translated = strings.TrimSpace(translated)
+	if idx := strings.Index(translated, "\n"); idx > 0 {
+		return strings.TrimSpace(translated[:idx]), strings.TrimSpace(translated[idx+1:])
+	}
+	return translated, translated
+}
+
+// Localize 按协商语言替换词条的标题与正文
+//
+// 只有approved变体参与替换；目标语言无可用变体时回退原文。
+// 返回实际使用的语言标签。
+func (s *Service) Localize(ctx context.Context, entry *Entry, lang string) string {
+	if lang == "" || lang == i18n.DefaultLanguage {
+		return i18n.DefaultLanguage
+	}
+	var variant Variant
+	err := s.db.WithContext(ctx).
+		First(&variant, "entry_id = ? AND language = ? AND status = ?",
+			entry.ID, lang, VariantApproved).Error
+	if err != nil {
+		return i18n.DefaultLanguage
+	}
+	entry.Title = variant.Title
+	entry.Content = variant.Content
+	return lang
+}
@@ -0,0 +1,863 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/codetaoist/taishanglaojun/core-services/internal/activity"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/ai"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/apikey"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/apiv2"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/attachments"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/audit"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/bookmarks"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/breaker"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/caching"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/calls"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/cdn"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/community"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/compression"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/config"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/consent"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/database"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/deprecation"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/digest"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/email"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/emoji"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/events"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/featureflags"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/graphqlapi"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/grpcserver"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/health"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/i18n"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/knowledge"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/location"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/lunar"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/meetings"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/metrics"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/negotiate"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/notifications"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/oauth"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/presence"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/printing"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/publicapi"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/realtime"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/registry"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/runtimeconfig"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/sandbox"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/search"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/segmentation"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tasks"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/timeprefs"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/tracing"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/transform"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/trust"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/unfurl"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/warehouse"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/webhooks"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/whiteboard"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/wiki"
+	"github.com/codetaoist/taishanglaojun/core-services/internal/wisdom"
+)
+
+func main() {
+	// 加载配置
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// 设置Gin模式
+	if cfg.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// 初始化日志（保留级别句柄供运行时配置热更新）
+	zapConfig := zap.NewProductionConfig()
+	if level, err := zapcore.ParseLevel(cfg.LogLevel); err == nil {
+		zapConfig.Level.SetLevel(level)
+	}
+	logger, err := zapConfig.Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	// 初始化数据库
+	db, err := database.Init(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// 初始化分布式追踪
+	shutdownTracing, err := tracing.Init(cfg.OTLPEndpoint, cfg.Environment)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		log.Fatalf("Failed to install gorm tracing plugin: %v", err)
+	}
+
+	// 初始化指标注册表
+	metricsRegistry := metrics.NewRegistry()
+	if sqlDB, err := db.DB(); err == nil {
+		metricsRegistry.RegisterDBStats(sqlDB)
+	}
+
+	// 初始化同意管理模块
+	consentService, err := consent.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize consent service: %v", err)
+	}
+
+	// 初始化社区治理模块
+	governanceService, err := community.NewGovernanceService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize governance service: %v", err)
+	}
+
+	// 初始化webhook模块
+	webhookManager, err := webhooks.NewManager(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize webhook manager: %v", err)
+	}
+	webhookManager.Start()
+	defer webhookManager.Stop()
+
+	// 初始化搜索模块
+	searchService := search.NewService(cfg.ElasticsearchURL, cfg.SearchIndex, logger)
+
+	// 初始化领域事件日志（重放与审计的事实来源）
+	eventService, err := events.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize event service: %v", err)
+	}
+	eventService.RegisterConsumer("search", events.ConsumerFunc(searchService.HandleEvent))
+	eventService.RegisterConsumer("webhooks", events.ConsumerFunc(webhookManager.Publish))
+
+	// 初始化用户活动时间线（领域事件的按用户投影）
+	activityService, err := activity.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize activity service: %v", err)
+	}
+	eventService.RegisterConsumer("activity", events.ConsumerFunc(activityService.HandleEvent))
+
+	// 初始化通知分发模块（所有出站通知在这里按偏好矩阵裁决）
+	notificationService, err := notifications.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize notification service: %v", err)
+	}
+	eventService.RegisterConsumer("notifications", events.ConsumerFunc(notificationService.HandleEvent))
+
+	// 初始化CDN边缘缓存清除（内容变更时按代理键保鲜公共页面）
+	cdnService, err := cdn.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize cdn service: %v", err)
+	}
+	switch cfg.CDNProvider {
+	case "cloudflare":
+		cdnService.SetPurger(cdn.NewCloudflarePurger(cfg.CloudflareZoneID, cfg.CloudflareAPIToken), cfg.CDNBaseURL)
+	case "aliyun":
+		cdnService.SetPurger(cdn.NewAliyunPurger(cfg.AliyunAccessKeyID, cfg.AliyunAccessKeySecret), cfg.CDNBaseURL)
+	}
+	cdnService.RegisterPath(cdn.TagTrending, "/api/public/trending")
+	cdnService.RegisterPath(cdn.TagWisdom, "/api/public/wisdom")
+	cdnService.RegisterPath(cdn.TagDaily, "/api/public/wisdom/daily")
+	cdnService.Start()
+	defer cdnService.Stop()
+
+	// 业务事件落事件日志后分发给webhook、搜索索引、活动时间线与通知
+	publishEvent := func(event string, payload interface{}) {
+		eventService.Record(event, payload)
+		webhookManager.Publish(event, payload)
+		searchService.HandleEvent(event, payload)
+		activityService.HandleEvent(event, payload)
+		notificationService.HandleEvent(event, payload)
+		cdnService.HandleEvent(event, payload)
+	}
+	notificationService.SetEventPublisher(publishEvent)
+
+	// 初始化讨论串模块
+	threadService, err := community.NewThreadService(db, community.DefaultHeatPolicy())
+	if err != nil {
+		log.Fatalf("Failed to initialize thread service: %v", err)
+	}
+	if cfg.ModerationURL != "" {
+		scorer := community.NewHTTPToxicityScorer(cfg.ModerationURL, cfg.ModerationTenant)
+		scorer.SetObserver(metricsRegistry)
+		threadService.SetToxicityScorer(scorer, community.DefaultToxicityPolicy())
+	}
+	threadService.SetEventPublisher(publishEvent)
+
+	// 初始化审核管线模块
+	reviewService, err := community.NewReviewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize review service: %v", err)
+	}
+	if cfg.ModerationURL != "" {
+		reviewService.SetClassifier(community.NewHTTPToxicityScorer(cfg.ModerationURL, cfg.ModerationTenant))
+	}
+	threadService.SetScreener(reviewService)
+
+	// 初始化垃圾检测模块
+	spamService, err := community.NewSpamService(db, reviewService)
+	if err != nil {
+		log.Fatalf("Failed to initialize spam service: %v", err)
+	}
+	if cfg.ModerationURL != "" {
+		spamService.SetClassifier(community.NewHTTPToxicityScorer(cfg.ModerationURL, cfg.ModerationTenant))
+	}
+	threadService.SetSpamDetector(spamService)
+	reviewService.SetFeedbackSink(spamService)
+
+	// 初始化跨发布模块
+	crossPostService, err := community.NewCrossPostService(db, threadService)
+	if err != nil {
+		log.Fatalf("Failed to initialize cross post service: %v", err)
+	}
+
+	// 初始化任务看板模块
+	taskService, err := tasks.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize task service: %v", err)
+	}
+	taskService.SetEventPublisher(publishEvent)
+
+	// 初始化循环任务模块
+	recurrenceService, err := tasks.NewRecurrenceService(db, taskService, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize recurrence service: %v", err)
+	}
+	recurrenceService.Start()
+	defer recurrenceService.Stop()
+
+	// 初始化任务计时模块
+	timeLogService, err := tasks.NewTimeLogService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize time log service: %v", err)
+	}
+
+	// 初始化知识库模块
+	wikiService, err := wiki.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize wiki service: %v", err)
+	}
+
+	// 初始化实时通信hub与白板模块
+	hub := realtime.NewHub(logger)
+	whiteboardService, err := whiteboard.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize whiteboard service: %v", err)
+	}
+	whiteboardService.Start()
+	defer whiteboardService.Stop()
+
+	// 初始化时间偏好模块（各调度器按用户本地时区换算）
+	timeprefsService, err := timeprefs.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize time preference service: %v", err)
+	}
+
+	// 初始化文化智慧创作模块
+	wisdomService, err := wisdom.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize wisdom service: %v", err)
+	}
+	wisdomService.SetEventPublisher(publishEvent)
+	wisdomService.SetLocationResolver(timeprefsService.LocationFor)
+	wisdomService.Start()
+	defer wisdomService.Stop()
+
+	// 初始化统一在线状态模块
+	presenceService, err := presence.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize presence service: %v", err)
+	}
+	presenceService.AttachHub(hub)
+	presenceService.Start()
+	defer presenceService.Stop()
+
+	// 初始化统一收藏模块
+	bookmarkService, err := bookmarks.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize bookmark service: %v", err)
+	}
+
+	// 初始化通话信令模块
+	callService, err := calls.NewService(db, cfg.TURNSecret, cfg.TURNURIs)
+	if err != nil {
+		log.Fatalf("Failed to initialize call service: %v", err)
+	}
+
+	// 初始化会议排期模块
+	meetingService, err := meetings.NewService(db, hub, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize meeting service: %v", err)
+	}
+	meetingService.Start()
+	defer meetingService.Stop()
+	taskService.SetAvailabilityChecker(meetingService)
+
+	// 初始化表情包模块
+	emojiService, err := emoji.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize emoji service: %v", err)
+	}
+
+	// 初始化链接预览模块
+	unfurlService, err := unfurl.NewService(db, cfg.UnfurlDenyHosts, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize unfurl service: %v", err)
+	}
+
+	// 初始化账号风控模块
+	trustService, err := trust.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize trust service: %v", err)
+	}
+
+	// 初始化实时指标推送
+	metricsStream := metrics.NewStream(hub)
+	metricsStream.RegisterSource("online_users", func() float64 {
+		return float64(hub.ConnectionCount())
+	})
+	metricsStream.RegisterSource("requests_per_sec", metricsRegistry.RequestRate)
+	metricsStream.RegisterSource("active_ai_requests", metricsRegistry.AIInFlight)
+	metricsStream.RegisterSource("review_queue_depth", func() float64 {
+		count, err := reviewService.PendingCount(context.Background())
+		if err != nil {
+			return 0
+		}
+		return float64(count)
+	})
+	metricsStream.Start()
+	defer metricsStream.Stop()
+
+	// 初始化弃用注册表
+	deprecationService, err := deprecation.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize deprecation service: %v", err)
+	}
+	// 平铺的运行记录端点由按作业嵌套的端点取代
+	deprecationService.Register(deprecation.Info{
+		Method:      "GET",
+		Route:       "/api/v1/warehouse/runs",
+		Replacement: "/api/v1/warehouse/jobs/:jobId/runs",
+	})
+
+	// 初始化审计日志模块
+	auditService, err := audit.NewService(db, cfg.AuditRetentionDays, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize audit service: %v", err)
+	}
+	auditService.Start()
+	defer auditService.Stop()
+	trustService.SetAuditSink(auditService)
+
+	// 初始化开发者沙箱模块
+	sandboxService, err := sandbox.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize sandbox service: %v", err)
+	}
+	sandboxService.Start()
+	defer sandboxService.Stop()
+
+	// 初始化仓库导出模块
+	warehouseService, err := warehouse.NewService(db, cfg.WarehouseURL, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize warehouse service: %v", err)
+	}
+	warehouseService.Start()
+	defer warehouseService.Stop()
+
+	// 初始化条款版本模块
+	termsService, err := consent.NewTermsService(db, time.Duration(cfg.TermsGraceDays)*24*time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to initialize terms service: %v", err)
+	}
+
+	// 初始化API密钥模块
+	apikeyService, err := apikey.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize apikey service: %v", err)
+	}
+
+	// 出站熔断：各上游共用一个管理器，按服务名分熔断器
+	breakerManager := breaker.NewManager()
+	var aiCompleter *ai.HTTPCompleter
+	if cfg.AIServiceURL != "" {
+		aiCompleter = ai.NewHTTPCompleter(cfg.AIServiceURL)
+		aiCompleter.SetHTTPClient(breakerManager.Client("ai-service", 60*time.Second))
+	}
+
+	// 初始化AI分块处理模块、护栏与评测
+	var aiService *ai.Service
+	var aiGuard *ai.Guard
+	var aiEvalService *ai.EvalService
+	var aiAgentService *ai.AgentService
+	if cfg.AIServiceURL != "" {
+		completer := aiCompleter
+		aiService = ai.NewService(completer)
+		aiService.SetObserver(metricsRegistry)
+		aiGuard, err = ai.NewGuard(db)
+		if err != nil {
+			log.Fatalf("Failed to initialize ai guard: %v", err)
+		}
+		aiService.SetGuard(aiGuard)
+		aiEvalService, err = ai.NewEvalService(db, completer)
+		if err != nil {
+			log.Fatalf("Failed to initialize ai eval service: %v", err)
+		}
+
+		// 初始化定时Agent工作流引擎
+		aiAgentService, err = ai.NewAgentService(db, completer, logger)
+		if err != nil {
+			log.Fatalf("Failed to initialize ai agent service: %v", err)
+		}
+		aiAgentService.RegisterTool("community.recent_activity", func(ctx context.Context) (string, error) {
+			return threadService.RecentActivityDigest(ctx, time.Now().Add(-24*time.Hour), 10)
+		})
+		aiAgentService.SetPostSink(func(ctx context.Context, threadID, content string) error {
+			_, err := threadService.AddPost(ctx, threadID, "agent", content)
+			return err
+		})
+		aiAgentService.SetEventPublisher(publishEvent)
+		aiAgentService.Start()
+		defer aiAgentService.Stop()
+	}
+
+	// 初始化AI计费与预算模块
+	aiCostService, err := ai.NewCostService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize ai cost service: %v", err)
+	}
+	aiCostService.SetEventPublisher(publishEvent)
+
+	// 初始化微调数据整备模块
+	aiFinetuneService, err := ai.NewFinetuneService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize ai finetune service: %v", err)
+	}
+	aiFinetuneService.SetConsentChecker(consentService)
+
+	// 初始化附件模块：配置了对象存储则直传S3/MinIO/OSS，否则本地磁盘
+	var attachmentBackend attachments.Backend
+	var localBackend *attachments.LocalBackend
+	if cfg.StorageEndpoint != "" {
+		attachmentBackend = attachments.NewS3Backend(
+			cfg.StorageEndpoint, cfg.StorageRegion, cfg.StorageBucket,
+			cfg.StorageAccessKey, cfg.StorageSecretKey)
+	} else {
+		localBackend, err = attachments.NewLocalBackend(
+			cfg.AttachmentDir, cfg.JWTSecret, "/api/v1/attachments/content")
+		if err != nil {
+			log.Fatalf("Failed to initialize local attachment backend: %v", err)
+		}
+		attachmentBackend = localBackend
+	}
+	attachmentService, err := attachments.NewService(db, attachmentBackend, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment service: %v", err)
+	}
+	if cfg.ClamAVAddr != "" {
+		attachmentService.SetScanner(attachments.NewClamAVScanner(cfg.ClamAVAddr), cfg.ScanFailOpen)
+	}
+	attachmentService.SetAltTextRequired(cfg.RequireAltText)
+	if cfg.AIServiceURL != "" {
+		// 图片替代文本的AI建议
+		attachmentService.SetCaptioner(attachments.NewHTTPCaptioner(cfg.AIServiceURL))
+	}
+	attachmentService.Start()
+	defer attachmentService.Stop()
+
+	// 朗读音频复用附件的对象存储后端
+	if cfg.TTSServiceURL != "" {
+		wisdomService.SetAudioPipeline(wisdom.NewHTTPSynthesizer(cfg.TTSServiceURL), attachmentBackend)
+	}
+
+	// 初始化邮件模块：按配置选择驱动，未配置时不启用
+	var emailService *email.Service
+	if cfg.EmailDriver != "" {
+		var emailDriver email.Driver
+		switch cfg.EmailDriver {
+		case "smtp":
+			emailDriver = email.NewSMTPDriver(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword)
+		case "sendgrid":
+			emailDriver = email.NewSendGridDriver(cfg.SendGridAPIKey)
+		case "ses":
+			emailDriver = email.NewSESDriver(cfg.SESRegion, cfg.SESAccessKey, cfg.SESSecretKey)
+		default:
+			log.Fatalf("Unknown email driver: %s", cfg.EmailDriver)
+		}
+		emailService, err = email.NewService(db, emailDriver, cfg.EmailFrom, logger)
+		if err != nil {
+			log.Fatalf("Failed to initialize email service: %v", err)
+		}
+		emailService.Start()
+		defer emailService.Stop()
+		notificationService.SetEmailSender(func(ctx context.Context, to, title, body string) error {
+			_, err := emailService.Enqueue(ctx, to, email.TemplateNotification, gin.H{
+				"Title": title,
+				"Body":  body,
+			})
+			return err
+		})
+	}
+
+	// 初始化摘要模块：依赖邮件发送，未启用邮件时不启用
+	var digestService *digest.Service
+	if emailService != nil {
+		digestService, err = digest.NewService(db, logger)
+		if err != nil {
+			log.Fatalf("Failed to initialize digest service: %v", err)
+		}
+		digestService.SetTaskSource(func(ctx context.Context, userID string) ([]string, error) {
+			return taskService.AssigneeDigest(ctx, userID, time.Now())
+		})
+		digestService.SetCommunitySource(func(ctx context.Context) (string, error) {
+			return threadService.RecentActivityDigest(ctx, time.Now().Add(-24*time.Hour), 5)
+		})
+		digestService.SetSender(func(ctx context.Context, to, title string, items []string) error {
+			_, err := emailService.Enqueue(ctx, to, email.TemplateDigest, gin.H{
+				"Title": title,
+				"Items": items,
+			})
+			return err
+		})
+		digestService.SetLocationResolver(timeprefsService.LocationFor)
+		digestService.Start()
+		defer digestService.Stop()
+	}
+
+	// 初始化健康数据模块
+	healthService, err := health.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize health service: %v", err)
+	}
+	healthService.RegisterConnector(health.NewGoogleFitConnector())
+	healthService.RegisterConnector(health.NewHuaweiConnector())
+	healthService.SetEventPublisher(publishEvent)
+	healthService.SetReportSecret(cfg.JWTSecret)
+	healthService.Start()
+	defer healthService.Stop()
+
+	// 初始化知识库同步模块
+	knowledgeService, err := knowledge.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize knowledge service: %v", err)
+	}
+	knowledgeService.RegisterConnector(knowledge.NewConfluenceConnector())
+	knowledgeService.RegisterConnector(knowledge.NewNotionConnector())
+	knowledgeService.RegisterConnector(knowledge.NewSitemapConnector())
+	if cfg.AIServiceURL != "" {
+		// 启用带引用核验的知识库问答
+		knowledgeService.SetCompleter(aiCompleter, "default")
+	}
+	knowledgeService.Start()
+	defer knowledgeService.Stop()
+
+	// 初始化翻译管理模块
+	i18nService, err := i18n.NewService(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize i18n service: %v", err)
+	}
+	if cfg.AIServiceURL != "" {
+		i18nService.SetCompleter(aiCompleter, "default")
+		// 文化智慧词条的AI翻译起草
+		wisdomService.SetCompleter(aiCompleter, "default")
+	}
+
+	// 初始化位置追踪模块
+	locationService, err := location.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize location service: %v", err)
+	}
+	locationService.SetEventPublisher(publishEvent)
+	locationService.Start()
+	defer locationService.Stop()
+
+	// 初始化服务注册中心（供网关动态发现实例）
+	registryService, err := registry.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize registry service: %v", err)
+	}
+	registryService.Start()
+	defer registryService.Stop()
+
+	// 初始化OAuth2授权服务（第三方开放平台）
+	oauthService, err := oauth.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize oauth service: %v", err)
+	}
+
+	// 初始化功能开关：灰度放量与快速回滚
+	flagService, err := featureflags.NewService(db, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize feature flags service: %v", err)
+	}
+
+	// 初始化响应缓存
+	cachingService := caching.NewService()
+	cachingService.Start()
+	defer cachingService.Stop()
+
+	// 初始化PDF导出模块
+	printingService, err := printing.NewService(db, filepath.Join(cfg.AttachmentDir, "prints"), logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize printing service: %v", err)
+	}
+	printingService.RegisterSource("wisdom_entry", func(ctx context.Context, userID, targetID string) (*printing.Document, error) {
+		entry, err := wisdomService.GetEntry(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		doc := &printing.Document{Title: entry.Title}
+		if entry.Source != "" {
+			doc.Meta = append(doc.Meta, "出处："+entry.Source)
+		}
+		if entry.Category != "" {
+			doc.Meta = append(doc.Meta, "分类："+entry.Category)
+		}
+		doc.Sections = append(doc.Sections, printing.Section{Lines: printing.SplitLines(entry.Content)})
+		return doc, nil
+	})
+	printingService.RegisterSource("task_board", func(ctx context.Context, userID, targetID string) (*printing.Document, error) {
+		board, err := taskService.GetBoard(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		doc := &printing.Document{
+			Title: board.Name,
+			Meta:  []string{"看板快照 " + time.Now().Format("2006-01-02 15:04")},
+		}
+		for _, column := range board.Columns {
+			section := printing.Section{Heading: column.Name}
+			for _, task := range column.Tasks {
+				line := "· " + task.Title
+				if task.DueAt != nil {
+					line += "（截止 " + task.DueAt.Format("2006-01-02") + "）"
+				}
+				section.Lines = append(section.Lines, line)
+			}
+			doc.Sections = append(doc.Sections, section)
+		}
+		return doc, nil
+	})
+	printingService.RegisterRenderer("health_report", func(ctx context.Context, userID, targetID string) ([]byte, error) {
+		return healthService.RenderReportPDF(ctx, userID, "zh")
+	})
+	printingService.Start()
+	defer printingService.Stop()
+
+	// 初始化分群模块
+	attributeSource, err := segmentation.NewDatabaseAttributeSource(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize attribute source: %v", err)
+	}
+	attributeSource.SetConsentChecker(consentService)
+	segmentationService, err := segmentation.NewService(db, attributeSource, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize segmentation service: %v", err)
+	}
+	segmentationService.Start()
+	defer segmentationService.Stop()
+
+	// 初始化请求/响应转换引擎
+	transformService, err := transform.NewService(cfg.TransformRulesPath, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize transform service: %v", err)
+	}
+	transformService.Start()
+	defer transformService.Stop()
+
+	// 初始化运行时配置热更新：监视配置文件，变更经校验后通知订阅方
+	runtimeService, err := runtimeconfig.NewService(config.FileUsed(), logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize runtime config service: %v", err)
+	}
+	runtimeService.Subscribe("log_level",
+		runtimeconfig.ValidateEnum("debug", "info", "warn", "error"),
+		func(value interface{}) {
+			if name, ok := runtimeconfig.StringValue(value); ok {
+				if level, err := zapcore.ParseLevel(name); err == nil {
+					zapConfig.Level.SetLevel(level)
+				}
+			}
+		})
+	runtimeService.Start()
+	defer runtimeService.Stop()
+
+	// 创建路由
+	r := gin.New()
+	r.Use(gin.Logger())
+	r.Use(gin.Recovery())
+	// 压缩最外层：先解开请求体，响应在全部内层写完后收尾
+	compressionService := compression.NewService(cfg.CompressMinBytes, logger)
+	r.Use(compressionService.Middleware())
+	// 声明式转换在请求体解压后尽早执行，路径改写后重派发
+	r.Use(transformService.Middleware(r))
+	// Accept内容协商：移动端可请求msgpack/protobuf编码
+	negotiateService := negotiate.NewService()
+	r.Use(negotiateService.Middleware())
+	r.Use(tracing.Middleware())
+	r.Use(metricsRegistry.Middleware())
+
+	// Prometheus指标
+	r.GET("/metrics", metricsRegistry.Handler())
+
+	// 健康检查
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":  "ok",
+			"service": "core-services",
+		})
+	})
+
+	// API v1 路由
+	v1 := r.Group("/api/v1")
+	apikeyHandler := apikey.NewHandler(apikeyService)
+	// 机器调用方可携带X-API-Key替代JWT，无该请求头的请求不受影响
+	v1.Use(apikeyHandler.Middleware(""))
+	deprecationHandler := deprecation.NewHandler(deprecationService)
+	v1.Use(deprecationHandler.Middleware())
+	deprecationHandler.SetupRoutes(v1)
+	apikeyHandler.SetupRoutes(v1)
+	termsHandler := consent.NewTermsHandler(termsService)
+	// 未在宽限期内接受最新条款的用户会被提示或拦截
+	v1.Use(termsHandler.Middleware())
+	termsHandler.SetupRoutes(v1)
+	// GET响应缓存（仅对声明了Cache-Control max-age的响应生效）
+	v1.Use(cachingService.Middleware())
+	caching.NewHandler(cachingService).SetupRoutes(v1)
+	segmentation.NewHandler(segmentationService).SetupRoutes(v1)
+	consent.NewHandler(consentService).SetupRoutes(v1)
+	community.NewGovernanceHandler(governanceService).SetupRoutes(v1)
+	community.NewThreadHandler(threadService).SetupRoutes(v1)
+	community.NewCrossPostHandler(crossPostService).SetupRoutes(v1)
+	tasks.NewHandler(taskService).SetupRoutes(v1)
+	tasks.NewTransferHandler(taskService).SetupRoutes(v1)
+	tasks.NewCalendarHandler(taskService).SetupRoutes(v1)
+	wiki.NewHandler(wikiService).SetupRoutes(v1)
+	tasks.NewRecurrenceHandler(recurrenceService).SetupRoutes(v1)
+	whiteboard.NewHandler(whiteboardService, hub).SetupRoutes(v1)
+	calls.NewHandler(callService, hub).SetupRoutes(v1)
+	tasks.NewTimeLogHandler(timeLogService).SetupRoutes(v1)
+	meetings.NewHandler(meetingService).SetupRoutes(v1)
+	webhooks.NewHandler(webhookManager).SetupRoutes(v1)
+	search.NewHandler(searchService).SetupRoutes(v1)
+	community.NewReviewHandler(reviewService).SetupRoutes(v1)
+	emoji.NewHandler(emojiService).SetupRoutes(v1)
+	unfurl.NewHandler(unfurlService).SetupRoutes(v1)
+	community.NewSpamHandler(spamService).SetupRoutes(v1)
+	trust.NewHandler(trustService).SetupRoutes(v1)
+	warehouse.NewHandler(warehouseService).SetupRoutes(v1)
+	metricsStream.SetupRoutes(v1)
+	sandbox.NewHandler(sandboxService).SetupRoutes(v1)
+	audit.NewHandler(auditService).SetupRoutes(v1)
+	events.NewHandler(eventService).SetupRoutes(v1)
+	if aiService != nil {
+		ai.NewHandler(aiService, aiGuard).SetupRoutes(v1)
+		ai.NewEvalHandler(aiEvalService).SetupRoutes(v1)
+		ai.NewAgentHandler(aiAgentService).SetupRoutes(v1)
+	}
+	ai.NewCostHandler(aiCostService).SetupRoutes(v1)
+	ai.NewFinetuneHandler(aiFinetuneService).SetupRoutes(v1)
+	attachments.NewHandler(attachmentService, localBackend).SetupRoutes(v1)
+	if emailService != nil {
+		email.NewHandler(emailService).SetupRoutes(v1)
+	}
+	if digestService != nil {
+		digest.NewHandler(digestService).SetupRoutes(v1)
+	}
+	health.NewHandler(healthService).SetupRoutes(v1)
+	knowledge.NewHandler(knowledgeService).SetupRoutes(v1)
+	bookmarks.NewHandler(bookmarkService).SetupRoutes(v1)
+	activity.NewHandler(activityService).SetupRoutes(v1)
+	notifications.NewHandler(notificationService).SetupRoutes(v1)
+	i18n.NewHandler(i18nService).SetupRoutes(v1)
+	location.NewHandler(locationService).SetupRoutes(v1)
+	timeprefs.NewHandler(timeprefsService).SetupRoutes(v1)
+	lunar.NewHandler().SetupRoutes(v1)
+	registry.NewHandler(registryService).SetupRoutes(v1)
+	printing.NewHandler(printingService).SetupRoutes(v1)
+	oauth.NewHandler(oauthService).SetupRoutes(v1)
+	breaker.NewHandler(breakerManager).SetupRoutes(v1)
+	cdn.NewHandler(cdnService).SetupRoutes(v1)
+	transform.NewHandler(transformService).SetupRoutes(v1)
+	negotiate.NewHandler(negotiateService).SetupRoutes(v1)
+	runtimeconfig.NewHandler(runtimeService).SetupRoutes(v1)
+	featureflags.NewHandler(flagService).SetupRoutes(v1)
+	presence.NewHandler(presenceService, hub).SetupRoutes(v1)
+	wisdom.NewHandler(wisdomService).SetupRoutes(v1)
+
+	// 公共只读层：免认证，自带IP限流与CDN缓存头
+	publicService := publicapi.NewService(db, logger)
+	publicService.SetDailyProvider(func(ctx context.Context) (*publicapi.DailyContent, error) {
+		result, err := wisdomService.Daily(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		return &publicapi.DailyContent{
+			Date:    result.Date,
+			Title:   result.Entry.Title,
+			Content: result.Entry.Content,
+			Source:  result.Entry.Source,
+		}, nil
+	})
+	public := r.Group("/api/public")
+	publicHandler := publicapi.NewHandler(publicService, logger)
+	publicHandler.SetupRoutes(public)
+	runtimeService.Subscribe("public_rate_limit_per_minute",
+		runtimeconfig.ValidatePositiveInt,
+		func(value interface{}) {
+			if perMinute, ok := runtimeconfig.IntValue(value); ok {
+				publicHandler.SetRateLimit(perMinute)
+			}
+		})
+
+	// API v2 路由：共享服务层，契约见 openapi/core.v2.yaml；
+	// v1的响应形状保持冻结，由 tests/contracts 保障
+	v2 := r.Group("/api/v2")
+	v2.Use(apikeyHandler.Middleware(""))
+	apiv2.NewHandler(taskService, threadService).SetupRoutes(v2)
+
+	// GraphQL聚合端点：跨模块数据一次请求取回
+	graphqlHandler, err := graphqlapi.NewHandler(taskService, threadService)
+	if err != nil {
+		log.Fatalf("Failed to initialize graphql handler: %v", err)
+	}
+	api := r.Group("/api")
+	api.Use(apikeyHandler.Middleware(""))
+	graphqlHandler.SetupRoutes(api)
+
+	// 启动gRPC服务器（与REST并行，供内部服务调用）
+	if cfg.GRPCPort != "" {
+		grpcServer := grpcserver.New(taskService, threadService)
+		go func() {
+			log.Printf("Core services gRPC listening on :%s", cfg.GRPCPort)
+			if err := grpcServer.Serve(cfg.GRPCPort); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+		defer grpcServer.Stop()
+	}
+
+	// 启动服务器
+	port := cfg.Port
+	if port == "" {
+		port = "8082"
+	}
+	addr := fmt.Sprintf(":%s", port)
+	log.Printf("Core services starting on %s", addr)
+	if err := r.Run(addr); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
@@ -39,8 +39,24 @@ type RedisConfig struct {
 
 // AIConfig represents configuration for AI services
 type AIConfig struct {
-	VectorService ServiceConfig `mapstructure:"vector_service"`
-	ModelService  ServiceConfig `mapstructure:"model_service"`
+	VectorService ServiceConfig   `mapstructure:"vector_service"`
+	ModelService  ServiceConfig   `mapstructure:"model_service"`
+	Routing       AIRoutingConfig `mapstructure:"routing"`
+}
+
+// AIRoutingConfig represents configuration for routing across model providers
+type AIRoutingConfig struct {
+	Enabled             bool                    `mapstructure:"enabled"`
+	HealthCheckInterval time.Duration           `mapstructure:"health_check_interval"`
+	Targets             []AIRoutingTargetConfig `mapstructure:"targets"`
+}
+
+// AIRoutingTargetConfig represents a single routing target
+type AIRoutingTargetConfig struct {
+	Service           string `mapstructure:"service"`
+	Weight            int    `mapstructure:"weight"`
+	MaxConcurrency    int    `mapstructure:"max_concurrency"`
+	RequestsPerMinute int    `mapstructure:"requests_per_minute"`
 }
 
 // PluginConfig represents configuration for the plugin system
@@ -258,6 +274,10 @@ func setDefaults() {
 	viper.SetDefault("ai.model_service.protocol", "grpc")
 	viper.SetDefault("ai.model_service.tags", []string{"ai", "model", "inference"})
 
+	// AI routing defaults
+	viper.SetDefault("ai.routing.enabled", false)
+	viper.SetDefault("ai.routing.health_check_interval", "30s")
+
 	// Plugin system defaults
 	viper.SetDefault("plugin.directory", "./plugins")
 	viper.SetDefault("plugin.repository.url", "https://github.com/codetaoist/plugins")
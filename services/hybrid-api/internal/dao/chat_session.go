@@ -0,0 +1,256 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/codetaoist/taishanglaojun/hybrid-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// Summarizer 生成滚动摘要的接口，由模型服务实现
+type Summarizer interface {
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// ChatSessionRepository 聊天会话持久化仓库
+//
+// 会话与消息落盘PostgreSQL，读路径使用Redis做cache-aside缓存；
+// 长对话通过上下文窗口裁剪与滚动摘要控制token消耗。
+type ChatSessionRepository struct {
+	db         *gorm.DB
+	cache      *redis.Client // 可为nil，此时降级为直查数据库
+	cacheTTL   time.Duration
+	summarizer Summarizer // 可为nil，此时不生成摘要
+
+	// summaryThreshold 消息数超过该值时触发滚动摘要
+	summaryThreshold int
+}
+
+// NewChatSessionRepository 创建聊天会话仓库
+func NewChatSessionRepository(db *gorm.DB, cache *redis.Client, summarizer Summarizer) *ChatSessionRepository {
+	return &ChatSessionRepository{
+		db:               db,
+		cache:            cache,
+		cacheTTL:         10 * time.Minute,
+		summarizer:       summarizer,
+		summaryThreshold: 50,
+	}
+}
+
+// sessionCacheKey 会话缓存键
+func sessionCacheKey(conversationID string) string {
+	return "chat:session:" + conversationID
+}
+
+// CreateSession 创建会话
+func (r *ChatSessionRepository) CreateSession(ctx context.Context, conversation *models.Conversation) error {
+	if err := r.db.WithContext(ctx).Create(conversation).Error; err != nil {
+		return fmt.Errorf("failed to create chat session: %w", err)
+	}
+	return nil
+}
+
+// GetSession 获取会话及其全部消息（优先读缓存）
+func (r *ChatSessionRepository) GetSession(ctx context.Context, conversationID string) (*models.Conversation, error) {
+	if r.cache != nil {
+		data, err := r.cache.Get(ctx, sessionCacheKey(conversationID)).Bytes()
+		if err == nil {
+			var conversation models.Conversation
+			if err := json.Unmarshal(data, &conversation); err == nil {
+				return &conversation, nil
+			}
+		}
+	}
+
+	conversation, err := r.loadSession(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.fillCache(ctx, conversation)
+	return conversation, nil
+}
+
+// loadSession 从数据库加载会话及消息
+func (r *ChatSessionRepository) loadSession(ctx context.Context, conversationID string) (*models.Conversation, error) {
+	var conversation models.Conversation
+	if err := r.db.WithContext(ctx).First(&conversation, "id = ?", conversationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("chat session not found: %s", conversationID)
+		}
+		return nil, fmt.Errorf("failed to get chat session: %w", err)
+	}
+
+	var messages []models.Message
+	if err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at ASC").
+		Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
+	}
+	conversation.Messages = messages
+
+	return &conversation, nil
+}
+
+// fillCache 写入会话缓存（失败时静默降级）
+func (r *ChatSessionRepository) fillCache(ctx context.Context, conversation *models.Conversation) {
+	if r.cache == nil {
+		return
+	}
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return
+	}
+	r.cache.Set(ctx, sessionCacheKey(conversation.ID), data, r.cacheTTL)
+}
+
+// invalidate 失效会话缓存
+func (r *ChatSessionRepository) invalidate(ctx context.Context, conversationID string) {
+	if r.cache == nil {
+		return
+	}
+	r.cache.Del(ctx, sessionCacheKey(conversationID))
+}
+
+// AppendMessage 追加一条消息并维护滚动摘要
+func (r *ChatSessionRepository) AppendMessage(ctx context.Context, message *models.Message) error {
+	if err := r.db.WithContext(ctx).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to append chat message: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Conversation{}).
+		Where("id = ?", message.ConversationID).
+		Update("updated_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to touch chat session: %w", err)
+	}
+
+	r.invalidate(ctx, message.ConversationID)
+
+	// 消息数超过阈值时异步维护滚动摘要
+	if r.summarizer != nil {
+		if err := r.maybeSummarize(ctx, message.ConversationID); err != nil {
+			// 摘要失败不影响消息写入
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteSession 删除会话及其消息
+func (r *ChatSessionRepository) DeleteSession(ctx context.Context, conversationID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Message{}, "conversation_id = ?", conversationID).Error; err != nil {
+			return fmt.Errorf("failed to delete chat messages: %w", err)
+		}
+		if err := tx.Delete(&models.Conversation{}, "id = ?", conversationID).Error; err != nil {
+			return fmt.Errorf("failed to delete chat session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, conversationID)
+	return nil
+}
+
+// estimateTokens 粗略估算文本token数（约4字符/token）
+func estimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// GetContextWindow 获取不超过token预算的上下文消息
+//
+// 从最新消息向前累计，超出预算的旧消息被裁剪；
+// 存在滚动摘要时，将其作为system消息置于窗口首位。
+func (r *ChatSessionRepository) GetContextWindow(ctx context.Context, conversationID string, maxTokens int) ([]*models.Message, error) {
+	conversation, err := r.GetSession(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := maxTokens
+	if conversation.Summary != "" {
+		budget -= estimateTokens(conversation.Summary)
+	}
+
+	// 从最新往前取
+	kept := make([]*models.Message, 0, len(conversation.Messages))
+	used := 0
+	for i := len(conversation.Messages) - 1; i >= 0; i-- {
+		msg := conversation.Messages[i]
+		cost := estimateTokens(msg.Content)
+		if used+cost > budget && len(kept) > 0 {
+			break
+		}
+		used += cost
+		kept = append(kept, &conversation.Messages[i])
+	}
+
+	// 反转回时间顺序
+	window := make([]*models.Message, 0, len(kept)+1)
+	if conversation.Summary != "" {
+		window = append(window, &models.Message{
+			ConversationID: conversationID,
+			Role:           "system",
+			Content:        "Summary of earlier conversation: " + conversation.Summary,
+		})
+	}
+	for i := len(kept) - 1; i >= 0; i-- {
+		window = append(window, kept[i])
+	}
+	return window, nil
+}
+
+// maybeSummarize 消息数超过阈值时，把最旧的一半消息并入滚动摘要
+func (r *ChatSessionRepository) maybeSummarize(ctx context.Context, conversationID string) error {
+	conversation, err := r.loadSession(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	pending := len(conversation.Messages) - conversation.SummaryMessageCount
+	if pending <= r.summaryThreshold {
+		return nil
+	}
+
+	// 摘要最旧的一半未摘要消息
+	count := pending / 2
+	start := conversation.SummaryMessageCount
+	var sb strings.Builder
+	if conversation.Summary != "" {
+		sb.WriteString("Previous summary: ")
+		sb.WriteString(conversation.Summary)
+		sb.WriteString("\n")
+	}
+	for _, msg := range conversation.Messages[start : start+count] {
+		sb.WriteString(msg.Role)
+		sb.WriteString(": ")
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
+	}
+
+	summary, err := r.summarizer.Summarize(ctx, sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to summarize chat session: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.Conversation{}).
+		Where("id = ?", conversationID).
+		Updates(map[string]interface{}{
+			"summary":               summary,
+			"summary_message_count": start + count,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to store rolling summary: %w", err)
+	}
+
+	r.invalidate(ctx, conversationID)
+	return nil
+}
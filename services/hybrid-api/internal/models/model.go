@@ -70,9 +70,25 @@ type TextGenerationRequest struct {
 	Temperature float64                `json:"temperature,omitempty"`
 	TopP        float64                `json:"topP,omitempty"`
 	Stream      bool                   `json:"stream,omitempty"`
+	Tools       []ToolDefinition       `json:"tools,omitempty"`      // 可供模型调用的工具
+	ToolChoice  string                 `json:"toolChoice,omitempty"` // auto / none / 指定工具名
 	Options     map[string]interface{} `json:"options,omitempty"`
 }
 
+// ToolDefinition 暴露给模型的工具定义
+type ToolDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  datatypes.JSON `json:"parameters,omitempty"` // JSON Schema格式的参数定义
+}
+
+// ToolCall 模型发起的工具调用
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON编码的调用参数
+}
+
 // TextGenerationResponse 文本生成响应
 type TextGenerationResponse struct {
 	ID      string    `json:"id"`
@@ -114,14 +130,16 @@ type EmbeddingResponse struct {
 
 // Message 对话消息
 type Message struct {
-	ID             string          `json:"id" gorm:"primaryKey"`
-	ConversationID string          `json:"conversationId"`
-	UserID         string          `json:"userId"`
-	Role           string          `json:"role"` // system, user, assistant
-	Content        string          `json:"content"`
-	Metadata       datatypes.JSON  `json:"metadata,omitempty" gorm:"type:jsonb"`
-	CreatedAt      time.Time       `json:"createdAt"`
-	UpdatedAt      time.Time       `json:"updatedAt"`
+	ID             string         `json:"id" gorm:"primaryKey"`
+	ConversationID string         `json:"conversationId"`
+	UserID         string         `json:"userId"`
+	Role           string         `json:"role"` // system, user, assistant, tool
+	Content        string         `json:"content"`
+	ToolCalls      []ToolCall     `json:"toolCalls,omitempty" gorm:"serializer:json"` // assistant消息中的工具调用
+	ToolCallID     string         `json:"toolCallId,omitempty"`                       // tool消息对应的调用ID
+	Metadata       datatypes.JSON `json:"metadata,omitempty" gorm:"type:jsonb"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
 }
 
 // TableName returns the table name for Message
@@ -158,8 +176,12 @@ type Conversation struct {
 	UserID      string                 `json:"userId"`
 	Title       string                 `json:"title"`
 	ModelConfig datatypes.JSON         `json:"modelConfig" gorm:"type:jsonb"` // JSON格式的模型配置
-	CreatedAt   time.Time              `json:"createdAt"`
-	UpdatedAt   time.Time              `json:"updatedAt"`
+	// Summary 滚动摘要：被裁剪出上下文窗口的历史消息的概括
+	Summary string `json:"summary,omitempty"`
+	// SummaryMessageCount 已纳入摘要的消息数量
+	SummaryMessageCount int       `json:"summaryMessageCount,omitempty"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
 	
 	// 关联字段，不存储在数据库中
 	Messages []Message `json:"messages,omitempty" gorm:"-"`
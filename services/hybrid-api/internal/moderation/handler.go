@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 内容审核HTTP处理器
+type Handler struct {
+	words    *SensitiveWordManager
+	media    *MediaModerator // 可为nil，表示未配置视觉模型
+	toxicity *ToxicityScorer // 可为nil，表示未配置毒性分类模型
+}
+
+// NewHandler 创建内容审核处理器
+func NewHandler(words *SensitiveWordManager) *Handler {
+	return &Handler{words: words}
+}
+
+// WithMediaModerator 启用媒体审核端点
+func (h *Handler) WithMediaModerator(media *MediaModerator) *Handler {
+	h.media = media
+	return h
+}
+
+// WithToxicityScorer 启用毒性评分端点
+func (h *Handler) WithToxicityScorer(toxicity *ToxicityScorer) *Handler {
+	h.toxicity = toxicity
+	return h
+}
+
+// SetupRoutes 注册内容审核相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	moderation := router.Group("/moderation")
+	{
+		words := moderation.Group("/tenants/:tenantId/sensitive-words")
+		{
+			words.GET("", h.ListWords)
+			words.POST("", h.AddWords)
+			words.DELETE("/:word", h.RemoveWord)
+			words.POST("/reload", h.Reload)
+		}
+		moderation.POST("/tenants/:tenantId/check", h.CheckText)
+
+		if h.media != nil {
+			moderation.POST("/tenants/:tenantId/media", h.ModerateMedia)
+			moderation.GET("/tenants/:tenantId/media/history", h.MediaHistory)
+		}
+
+		if h.toxicity != nil {
+			moderation.POST("/tenants/:tenantId/toxicity", h.ScoreToxicity)
+		}
+	}
+}
+
+// ModerateMedia 审核图片或视频
+func (h *Handler) ModerateMedia(c *gin.Context) {
+	var req struct {
+		MediaType string `json:"mediaType" binding:"required,oneof=image video"`
+		MediaURL  string `json:"mediaUrl" binding:"required,url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.media.Moderate(c.Request.Context(), c.Param("tenantId"), req.MediaType, req.MediaURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// MediaHistory 查询媒体审核历史
+func (h *Handler) MediaHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	results, err := h.media.History(c.Request.Context(), c.Param("tenantId"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results, "count": len(results)})
+}
+
+// ListWords 列出租户敏感词
+func (h *Handler) ListWords(c *gin.Context) {
+	words, err := h.words.ListWords(c.Request.Context(), c.Param("tenantId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"words": words, "count": len(words)})
+}
+
+// AddWords 批量添加敏感词
+func (h *Handler) AddWords(c *gin.Context) {
+	var req struct {
+		Words []SensitiveWord `json:"words" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.words.AddWords(c.Request.Context(), c.Param("tenantId"), req.Words); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"added": len(req.Words)})
+}
+
+// RemoveWord 删除敏感词
+func (h *Handler) RemoveWord(c *gin.Context) {
+	if err := h.words.RemoveWord(c.Request.Context(), c.Param("tenantId"), c.Param("word")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "word removed"})
+}
+
+// Reload 手动热加载租户词表
+func (h *Handler) Reload(c *gin.Context) {
+	if err := h.words.Reload(c.Request.Context(), c.Param("tenantId")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "word list reloaded"})
+}
+
+// CheckText 检查文本命中的敏感词
+func (h *Handler) CheckText(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	matches, err := h.words.Match(c.Request.Context(), c.Param("tenantId"), req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"matches": matches, "blocked": hasAction(matches, ActionBlock)})
+}
+
+// ScoreToxicity 对文本做毒性评分
+//
+// 评论与聊天管线在内容入库前调用该端点，按返回的score决定
+// 是否放行、送审或拒绝。
+func (h *Handler) ScoreToxicity(c *gin.Context) {
+	var req struct {
+		Source string `json:"source" binding:"required,oneof=comment chat"`
+		RefID  string `json:"refId"`
+		Text   string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	score, err := h.toxicity.Score(c.Request.Context(), c.Param("tenantId"), req.Source, req.RefID, req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, score)
+}
+
+// hasAction 判断匹配结果中是否包含指定动作
+func hasAction(matches []MatchResult, action string) bool {
+	for _, match := range matches {
+		if match.Action == action {
+			return true
+		}
+	}
+	return false
+}
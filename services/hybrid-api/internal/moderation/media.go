@@ -0,0 +1,159 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// toJSON 序列化为JSONB字段
+func toJSON(v interface{}) (datatypes.JSON, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json: %w", err)
+	}
+	return datatypes.JSON(data), nil
+}
+
+// 审核结论
+const (
+	VerdictApproved = "approved" // 自动通过
+	VerdictRejected = "rejected" // 自动拒绝
+	VerdictReview   = "review"   // 转人工审核
+)
+
+// 媒体类型
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
+// VisionLabel 视觉模型返回的单个风险标签
+type VisionLabel struct {
+	Category   string  `json:"category"` // 如 porn / violence / politics
+	Confidence float64 `json:"confidence"`
+}
+
+// VisionModel 视觉审核模型接口
+//
+// 图片直接分析；视频由实现方抽帧后对帧逐一分析并汇总。
+type VisionModel interface {
+	AnalyzeImage(ctx context.Context, imageURL string) ([]VisionLabel, error)
+	AnalyzeVideo(ctx context.Context, videoURL string) ([]VisionLabel, error)
+}
+
+// MediaModerationResult 媒体审核结果记录
+type MediaModerationResult struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	TenantID  string         `json:"tenantId" gorm:"index"`
+	MediaType string         `json:"mediaType"`
+	MediaURL  string         `json:"mediaUrl" gorm:"not null"`
+	Verdict   string         `json:"verdict"`
+	Labels    datatypes.JSON `json:"labels" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (MediaModerationResult) TableName() string {
+	return "tai_media_moderation"
+}
+
+// MediaModerator 图片/视频内容审核器
+//
+// 调用视觉模型获取风险标签，按类别阈值给出结论并落库，
+// 置信度处于灰色区间的内容转入人工审核。
+type MediaModerator struct {
+	db    *gorm.DB
+	model VisionModel
+
+	// rejectThreshold 达到该置信度直接拒绝
+	rejectThreshold float64
+	// reviewThreshold 达到该置信度（但低于拒绝阈值）转人工审核
+	reviewThreshold float64
+}
+
+// NewMediaModerator 创建媒体审核器
+func NewMediaModerator(db *gorm.DB, model VisionModel) (*MediaModerator, error) {
+	if err := db.AutoMigrate(&MediaModerationResult{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate media moderation table: %w", err)
+	}
+
+	return &MediaModerator{
+		db:              db,
+		model:           model,
+		rejectThreshold: 0.85,
+		reviewThreshold: 0.5,
+	}, nil
+}
+
+// Moderate 审核一个媒体资源并返回结论
+func (m *MediaModerator) Moderate(ctx context.Context, tenantID, mediaType, mediaURL string) (*MediaModerationResult, error) {
+	var labels []VisionLabel
+	var err error
+
+	switch strings.ToLower(mediaType) {
+	case MediaTypeImage:
+		labels, err = m.model.AnalyzeImage(ctx, mediaURL)
+	case MediaTypeVideo:
+		labels, err = m.model.AnalyzeVideo(ctx, mediaURL)
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", mediaType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("vision model analysis failed: %w", err)
+	}
+
+	verdict := m.verdict(labels)
+
+	labelsJSON, err := toJSON(labels)
+	if err != nil {
+		return nil, err
+	}
+	result := &MediaModerationResult{
+		TenantID:  tenantID,
+		MediaType: strings.ToLower(mediaType),
+		MediaURL:  mediaURL,
+		Verdict:   verdict,
+		Labels:    labelsJSON,
+	}
+	if err := m.db.WithContext(ctx).Create(result).Error; err != nil {
+		return nil, fmt.Errorf("failed to store moderation result: %w", err)
+	}
+	return result, nil
+}
+
+// verdict 按标签置信度给出审核结论
+func (m *MediaModerator) verdict(labels []VisionLabel) string {
+	verdict := VerdictApproved
+	for _, label := range labels {
+		if label.Confidence >= m.rejectThreshold {
+			return VerdictRejected
+		}
+		if label.Confidence >= m.reviewThreshold {
+			verdict = VerdictReview
+		}
+	}
+	return verdict
+}
+
+// History 查询租户的审核历史
+func (m *MediaModerator) History(ctx context.Context, tenantID string, limit, offset int) ([]*MediaModerationResult, error) {
+	query := m.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var results []*MediaModerationResult
+	if err := query.Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to list moderation history: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,239 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 命中敏感词后的处置动作
+const (
+	ActionBlock   = "block"   // 拒绝内容
+	ActionReplace = "replace" // 替换为掩码
+	ActionReview  = "review"  // 进入人工审核
+)
+
+// SensitiveWord 租户敏感词条目
+type SensitiveWord struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TenantID  string    `json:"tenantId" gorm:"uniqueIndex:idx_tenant_word;not null"`
+	Word      string    `json:"word" gorm:"uniqueIndex:idx_tenant_word;not null"`
+	Category  string    `json:"category"`
+	Action    string    `json:"action" gorm:"default:block"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (SensitiveWord) TableName() string {
+	return "tai_sensitive_words"
+}
+
+// MatchResult 敏感词匹配结果
+type MatchResult struct {
+	Word     string `json:"word"`
+	Category string `json:"category"`
+	Action   string `json:"action"`
+}
+
+// tenantWordList 单个租户的内存词表
+type tenantWordList struct {
+	words    []SensitiveWord
+	loadedAt time.Time
+}
+
+// SensitiveWordManager 租户敏感词管理器
+//
+// 词表持久化在数据库中，按租户加载到内存用于匹配；
+// 支持修改后立即热加载，也会按周期自动刷新兜底。
+type SensitiveWordManager struct {
+	db *gorm.DB
+
+	mu      sync.RWMutex
+	tenants map[string]*tenantWordList
+
+	refreshInterval time.Duration
+	stopCh          chan struct{}
+	stopOnce        sync.Once
+}
+
+// NewSensitiveWordManager 创建敏感词管理器
+func NewSensitiveWordManager(db *gorm.DB) (*SensitiveWordManager, error) {
+	if err := db.AutoMigrate(&SensitiveWord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate sensitive word table: %w", err)
+	}
+
+	return &SensitiveWordManager{
+		db:              db,
+		tenants:         make(map[string]*tenantWordList),
+		refreshInterval: time.Minute,
+		stopCh:          make(chan struct{}),
+	}, nil
+}
+
+// Start 启动周期刷新循环
+func (m *SensitiveWordManager) Start() {
+	go m.refreshLoop()
+}
+
+// Stop 停止周期刷新循环
+func (m *SensitiveWordManager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+// refreshLoop 周期性重载已缓存租户的词表
+func (m *SensitiveWordManager) refreshLoop() {
+	ticker := time.NewTicker(m.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.mu.RLock()
+			tenantIDs := make([]string, 0, len(m.tenants))
+			for tenantID := range m.tenants {
+				tenantIDs = append(tenantIDs, tenantID)
+			}
+			m.mu.RUnlock()
+
+			for _, tenantID := range tenantIDs {
+				// 刷新失败时保留旧词表继续服务
+				_ = m.Reload(context.Background(), tenantID)
+			}
+		}
+	}
+}
+
+// Reload 立即从数据库重载指定租户的词表
+func (m *SensitiveWordManager) Reload(ctx context.Context, tenantID string) error {
+	var words []SensitiveWord
+	if err := m.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Find(&words).Error; err != nil {
+		return fmt.Errorf("failed to load sensitive words for tenant %s: %w", tenantID, err)
+	}
+
+	m.mu.Lock()
+	m.tenants[tenantID] = &tenantWordList{words: words, loadedAt: time.Now()}
+	m.mu.Unlock()
+	return nil
+}
+
+// getWords 获取租户词表（未缓存时先加载）
+func (m *SensitiveWordManager) getWords(ctx context.Context, tenantID string) ([]SensitiveWord, error) {
+	m.mu.RLock()
+	list, exists := m.tenants[tenantID]
+	m.mu.RUnlock()
+	if exists {
+		return list.words, nil
+	}
+
+	if err := m.Reload(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tenants[tenantID].words, nil
+}
+
+// Match 在文本中查找租户敏感词
+func (m *SensitiveWordManager) Match(ctx context.Context, tenantID, text string) ([]MatchResult, error) {
+	words, err := m.getWords(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	lower := strings.ToLower(text)
+	var results []MatchResult
+	for _, word := range words {
+		if strings.Contains(lower, strings.ToLower(word.Word)) {
+			results = append(results, MatchResult{
+				Word:     word.Word,
+				Category: word.Category,
+				Action:   word.Action,
+			})
+		}
+	}
+	return results, nil
+}
+
+// Filter 过滤文本：命中replace动作的词被掩码，命中block的词返回错误
+func (m *SensitiveWordManager) Filter(ctx context.Context, tenantID, text string) (string, error) {
+	matches, err := m.Match(ctx, tenantID, text)
+	if err != nil {
+		return "", err
+	}
+
+	filtered := text
+	for _, match := range matches {
+		switch match.Action {
+		case ActionBlock:
+			return "", fmt.Errorf("content contains blocked word: %s", match.Word)
+		case ActionReplace:
+			mask := strings.Repeat("*", len([]rune(match.Word)))
+			filtered = replaceAllFold(filtered, match.Word, mask)
+		}
+	}
+	return filtered, nil
+}
+
+// replaceAllFold 大小写不敏感的全量替换
+func replaceAllFold(text, old, new string) string {
+	lowerText := strings.ToLower(text)
+	lowerOld := strings.ToLower(old)
+
+	var sb strings.Builder
+	for {
+		idx := strings.Index(lowerText, lowerOld)
+		if idx < 0 {
+			sb.WriteString(text)
+			return sb.String()
+		}
+		sb.WriteString(text[:idx])
+		sb.WriteString(new)
+		text = text[idx+len(old):]
+		lowerText = lowerText[idx+len(lowerOld):]
+	}
+}
+
+// AddWords 批量添加敏感词并热加载
+func (m *SensitiveWordManager) AddWords(ctx context.Context, tenantID string, words []SensitiveWord) error {
+	for i := range words {
+		words[i].TenantID = tenantID
+		if words[i].Action == "" {
+			words[i].Action = ActionBlock
+		}
+	}
+
+	if err := m.db.WithContext(ctx).Create(&words).Error; err != nil {
+		return fmt.Errorf("failed to add sensitive words: %w", err)
+	}
+	return m.Reload(ctx, tenantID)
+}
+
+// RemoveWord 删除敏感词并热加载
+func (m *SensitiveWordManager) RemoveWord(ctx context.Context, tenantID, word string) error {
+	result := m.db.WithContext(ctx).
+		Where("tenant_id = ? AND word = ?", tenantID, word).
+		Delete(&SensitiveWord{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove sensitive word: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("sensitive word not found: %s", word)
+	}
+	return m.Reload(ctx, tenantID)
+}
+
+// ListWords 列出租户的全部敏感词
+func (m *SensitiveWordManager) ListWords(ctx context.Context, tenantID string) ([]SensitiveWord, error) {
+	return m.getWords(ctx, tenantID)
+}
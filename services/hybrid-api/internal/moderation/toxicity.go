@@ -0,0 +1,80 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToxicityScore 毒性评分结果
+type ToxicityScore struct {
+	// Score 总体毒性，0（无害）到1（严重）
+	Score float64 `json:"score"`
+	// Categories 各细分类别的置信度，如 insult / threat / hate
+	Categories map[string]float64 `json:"categories,omitempty"`
+}
+
+// ToxicityClassifier 毒性分类模型接口（由文本模型实现）
+type ToxicityClassifier interface {
+	ClassifyToxicity(ctx context.Context, text string) (*ToxicityScore, error)
+}
+
+// ToxicityRecord 毒性评分记录
+type ToxicityRecord struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	TenantID   string         `json:"tenantId" gorm:"index"`
+	Source     string         `json:"source"` // comment / chat
+	RefID      string         `json:"refId"`  // 评论或消息ID
+	Score      float64        `json:"score"`
+	Categories datatypes.JSON `json:"categories" gorm:"type:jsonb"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ToxicityRecord) TableName() string {
+	return "tai_toxicity_records"
+}
+
+// ToxicityScorer 毒性评分服务
+//
+// 供评论与聊天管线在内容入库前调用；评分结果落库以便追踪
+// 模型表现与复核争议内容。
+type ToxicityScorer struct {
+	db         *gorm.DB
+	classifier ToxicityClassifier
+}
+
+// NewToxicityScorer 创建毒性评分服务
+func NewToxicityScorer(db *gorm.DB, classifier ToxicityClassifier) (*ToxicityScorer, error) {
+	if err := db.AutoMigrate(&ToxicityRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate toxicity table: %w", err)
+	}
+	return &ToxicityScorer{db: db, classifier: classifier}, nil
+}
+
+// Score 评分并记录
+func (s *ToxicityScorer) Score(ctx context.Context, tenantID, source, refID, text string) (*ToxicityScore, error) {
+	score, err := s.classifier.ClassifyToxicity(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("toxicity classification failed: %w", err)
+	}
+
+	categoriesJSON, err := toJSON(score.Categories)
+	if err != nil {
+		return nil, err
+	}
+	record := &ToxicityRecord{
+		TenantID:   tenantID,
+		Source:     source,
+		RefID:      refID,
+		Score:      score.Score,
+		Categories: categoriesJSON,
+	}
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to store toxicity record: %w", err)
+	}
+	return score, nil
+}
@@ -375,10 +375,10 @@ func generateText(modelManager *service.ModelManager) gin.HandlerFunc {
 		var service service.ModelService
 		var err error
 		
-		// 获取服务
+		// 获取服务：按请求中的模型名称解析（含动态发现），失败时回退默认服务
 		if request.Model != "" {
 			// 使用模型名称获取服务
-			service, err = modelManager.GetServiceByModel(request.Model)
+			service, err = modelManager.ResolveServiceForModel(c.Request.Context(), request.Model)
 			if err != nil {
 				// 如果找不到特定模型的服务，尝试使用默认服务
 				service, err = modelManager.GetDefaultService()
@@ -395,7 +395,7 @@ func generateText(modelManager *service.ModelManager) gin.HandlerFunc {
 				return
 			}
 		}
-		
+
 		// 生成文本
 		resp, err := service.GenerateText(c.Request.Context(), &request)
 		if err != nil {
@@ -419,13 +419,16 @@ func generateTextStream(modelManager *service.ModelManager) gin.HandlerFunc {
 		var service service.ModelService
 		var err error
 		
-		// 获取服务
+		// 获取服务：按请求中的模型名称解析（含动态发现），失败时回退默认服务
 		if request.Model != "" {
 			// 使用模型名称获取服务
-			service, err = modelManager.GetDefaultService()
+			service, err = modelManager.ResolveServiceForModel(c.Request.Context(), request.Model)
 			if err != nil {
-				response.NotFound(c, "No default service available")
-				return
+				service, err = modelManager.GetDefaultService()
+				if err != nil {
+					response.NotFound(c, "No service available for model and no default service")
+					return
+				}
 			}
 		} else {
 			// 使用默认服务
@@ -435,7 +438,7 @@ func generateTextStream(modelManager *service.ModelManager) gin.HandlerFunc {
 				return
 			}
 		}
-		
+
 		// 生成流式文本
 		chunkChan, err := service.GenerateTextStream(c.Request.Context(), &request)
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/codetaoist/taishanglaojun/hybrid-api/internal/models"
 )
@@ -14,14 +15,21 @@ type ModelManager struct {
 	factory  ModelServiceFactory
 	configs  map[string]*models.ModelConfig
 	mu       sync.RWMutex
+
+	// 模型发现缓存：模型ID -> 服务名称
+	discovered   map[string]string
+	discoveredAt time.Time
+	discoveryTTL time.Duration
 }
 
 // NewModelManager 创建模型服务管理器
 func NewModelManager() *ModelManager {
 	return &ModelManager{
-		services: make(map[string]ModelService),
-		factory:  NewModelServiceFactory(),
-		configs:  make(map[string]*models.ModelConfig),
+		services:     make(map[string]ModelService),
+		factory:      NewModelServiceFactory(),
+		configs:      make(map[string]*models.ModelConfig),
+		discovered:   make(map[string]string),
+		discoveryTTL: defaultDiscoveryTTL,
 	}
 }
 
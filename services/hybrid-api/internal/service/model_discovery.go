@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/codetaoist/taishanglaojun/hybrid-api/internal/models"
+)
+
+// defaultDiscoveryTTL 模型发现缓存的默认有效期
+const defaultDiscoveryTTL = 5 * time.Minute
+
+// RefreshModels 刷新所有已注册服务的模型列表并重建发现缓存
+//
+// 对于Ollama等本地部署服务，已安装的模型会随时变化，
+// 因此需要定期从服务端动态拉取，而不是只依赖静态配置。
+func (m *ModelManager) RefreshModels(ctx context.Context) error {
+	m.mu.RLock()
+	services := make(map[string]ModelService, len(m.services))
+	for name, service := range m.services {
+		services[name] = service
+	}
+	m.mu.RUnlock()
+
+	discovered := make(map[string]string)
+	var lastErr error
+	for name, service := range services {
+		modelInfos, err := service.ListModels(ctx)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to list models for service %s: %w", name, err)
+			continue
+		}
+		for _, info := range modelInfos {
+			// 先注册的服务优先，避免同名模型互相覆盖
+			if _, exists := discovered[info.ID]; !exists {
+				discovered[info.ID] = name
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.discovered = discovered
+	m.discoveredAt = time.Now()
+	m.mu.Unlock()
+
+	return lastErr
+}
+
+// DiscoverModels 返回所有已注册服务当前可用的模型信息
+func (m *ModelManager) DiscoverModels(ctx context.Context) ([]*models.ModelInfo, error) {
+	m.mu.RLock()
+	services := make(map[string]ModelService, len(m.services))
+	for name, service := range m.services {
+		services[name] = service
+	}
+	m.mu.RUnlock()
+
+	var modelInfos []*models.ModelInfo
+	var lastErr error
+	for name, service := range services {
+		infos, err := service.ListModels(ctx)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to list models for service %s: %w", name, err)
+			continue
+		}
+		modelInfos = append(modelInfos, infos...)
+	}
+
+	if len(modelInfos) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return modelInfos, nil
+}
+
+// ResolveServiceForModel 根据请求中的模型名称选择服务
+//
+// 解析顺序：
+//  1. 静态配置中的ModelID精确匹配
+//  2. 模型发现缓存（未过期时）
+//  3. 刷新发现缓存后重试
+func (m *ModelManager) ResolveServiceForModel(ctx context.Context, modelID string) (ModelService, error) {
+	if modelID == "" {
+		return m.GetDefaultService()
+	}
+
+	// 静态配置优先
+	if service, err := m.GetServiceByModel(modelID); err == nil {
+		return service, nil
+	}
+
+	// 查发现缓存
+	if service, ok := m.lookupDiscovered(modelID); ok {
+		return service, nil
+	}
+
+	// 缓存过期或未命中时刷新后重试
+	refreshErr := m.RefreshModels(ctx)
+	if service, ok := m.lookupDiscovered(modelID); ok {
+		return service, nil
+	}
+	if refreshErr != nil {
+		return nil, fmt.Errorf("failed to discover models: %w", refreshErr)
+	}
+
+	return nil, fmt.Errorf("no service provides model: %s", modelID)
+}
+
+// lookupDiscovered 在未过期的发现缓存中查找模型对应的服务
+func (m *ModelManager) lookupDiscovered(modelID string) (ModelService, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if time.Since(m.discoveredAt) > m.discoveryTTL {
+		return nil, false
+	}
+	name, exists := m.discovered[modelID]
+	if !exists {
+		return nil, false
+	}
+	service, exists := m.services[name]
+	return service, exists
+}
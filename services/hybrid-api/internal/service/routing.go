@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RoutingTarget 路由目标配置（对应config.yaml中ai.routing.targets的一项）
+type RoutingTarget struct {
+	Service           string // 已注册的模型服务名称
+	Weight            int    // 加权轮询权重，默认1
+	MaxConcurrency    int    // 最大并发请求数，0表示不限制
+	RequestsPerMinute int    // 每分钟请求数上限，0表示不限制
+}
+
+// RoutingConfig 路由层配置（对应config.yaml中的ai.routing）
+type RoutingConfig struct {
+	Enabled             bool
+	HealthCheckInterval time.Duration
+	Targets             []RoutingTarget
+}
+
+// routingState 单个路由目标的运行时状态
+type routingState struct {
+	target        RoutingTarget
+	healthy       bool
+	currentWeight int // 平滑加权轮询的当前权重
+	inFlight      int
+	windowStart   time.Time
+	windowCount   int
+}
+
+// ServiceRouter 模型服务路由层
+//
+// 在多个已注册的模型服务之间做平滑加权轮询，结合健康检查自动摘除
+// 故障节点，并对每个目标实施并发与速率限制。
+type ServiceRouter struct {
+	manager *ModelManager
+	config  RoutingConfig
+
+	mu     sync.Mutex
+	states []*routingState
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServiceRouter 创建模型服务路由层
+func NewServiceRouter(manager *ModelManager, config RoutingConfig) *ServiceRouter {
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = 30 * time.Second
+	}
+
+	states := make([]*routingState, 0, len(config.Targets))
+	for _, target := range config.Targets {
+		if target.Weight <= 0 {
+			target.Weight = 1
+		}
+		states = append(states, &routingState{
+			target:  target,
+			healthy: true,
+		})
+	}
+
+	return &ServiceRouter{
+		manager: manager,
+		config:  config,
+		states:  states,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start 启动健康检查循环
+func (r *ServiceRouter) Start() {
+	go r.healthCheckLoop()
+}
+
+// Stop 停止健康检查循环
+func (r *ServiceRouter) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+// healthCheckLoop 周期性探测所有路由目标
+func (r *ServiceRouter) healthCheckLoop() {
+	ticker := time.NewTicker(r.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.checkHealth()
+		}
+	}
+}
+
+// checkHealth 探测所有目标并更新健康状态
+func (r *ServiceRouter) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, state := range r.states {
+		service, err := r.manager.GetService(state.target.Service)
+		healthy := err == nil && service.Health(ctx) == nil
+
+		r.mu.Lock()
+		state.healthy = healthy
+		r.mu.Unlock()
+	}
+}
+
+// Acquire 按平滑加权轮询选择一个可用的模型服务
+//
+// 返回的release函数必须在请求结束后调用以释放并发配额。
+// 不健康、并发已满或速率超限的目标会被跳过。
+func (r *ServiceRouter) Acquire(ctx context.Context) (ModelService, func(), error) {
+	r.mu.Lock()
+	state := r.pickLocked()
+	if state == nil {
+		r.mu.Unlock()
+		return nil, nil, fmt.Errorf("no healthy routing target available")
+	}
+	state.inFlight++
+	state.windowCount++
+	name := state.target.Service
+	r.mu.Unlock()
+
+	service, err := r.manager.GetService(name)
+	if err != nil {
+		r.release(state)
+		return nil, nil, fmt.Errorf("routing target %s not registered: %w", name, err)
+	}
+
+	return service, func() { r.release(state) }, nil
+}
+
+// ExecuteWithFailover 按轮询顺序执行请求，失败时自动切换到下一个目标
+func (r *ServiceRouter) ExecuteWithFailover(ctx context.Context, fn func(ModelService) error) error {
+	attempts := len(r.states)
+	if attempts == 0 {
+		return fmt.Errorf("no routing targets configured")
+	}
+
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		service, release, err := r.Acquire(ctx)
+		if err != nil {
+			if lastErr == nil {
+				lastErr = err
+			}
+			break
+		}
+
+		err = fn(service)
+		release()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("all routing targets failed: %w", lastErr)
+}
+
+// pickLocked 平滑加权轮询选择（调用方需持有锁）
+func (r *ServiceRouter) pickLocked() *routingState {
+	now := time.Now()
+	var best *routingState
+	totalWeight := 0
+
+	for _, state := range r.states {
+		if !state.healthy {
+			continue
+		}
+		if state.target.MaxConcurrency > 0 && state.inFlight >= state.target.MaxConcurrency {
+			continue
+		}
+		// 按分钟滑动窗口限速
+		if state.target.RequestsPerMinute > 0 {
+			if now.Sub(state.windowStart) >= time.Minute {
+				state.windowStart = now
+				state.windowCount = 0
+			}
+			if state.windowCount >= state.target.RequestsPerMinute {
+				continue
+			}
+		}
+
+		state.currentWeight += state.target.Weight
+		totalWeight += state.target.Weight
+		if best == nil || state.currentWeight > best.currentWeight {
+			best = state
+		}
+	}
+
+	if best != nil {
+		best.currentWeight -= totalWeight
+	}
+	return best
+}
+
+// release 释放并发配额
+func (r *ServiceRouter) release(state *routingState) {
+	r.mu.Lock()
+	if state.inFlight > 0 {
+		state.inFlight--
+	}
+	r.mu.Unlock()
+}
+
+// Healthy 返回当前健康的目标名称列表
+func (r *ServiceRouter) Healthy() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.states))
+	for _, state := range r.states {
+		if state.healthy {
+			names = append(names, state.target.Service)
+		}
+	}
+	return names
+}
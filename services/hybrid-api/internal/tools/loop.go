@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codetaoist/taishanglaojun/hybrid-api/internal/models"
+)
+
+// Generator 文本生成接口（由模型服务实现）
+type Generator interface {
+	GenerateText(ctx context.Context, request *models.TextGenerationRequest) (*models.TextGenerationResponse, error)
+}
+
+// maxToolRounds 单次对话中工具调用的最大轮数，防止模型陷入循环
+const maxToolRounds = 8
+
+// RunWithTools 带工具调用的生成循环
+//
+// 把注册表中的工具定义附加到请求上；模型返回工具调用时执行工具，
+// 将结果作为tool消息追加后再次请求，直到模型产出最终回答。
+func RunWithTools(ctx context.Context, generator Generator, registry *Registry, request *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+	if request.ToolChoice == "none" {
+		return generator.GenerateText(ctx, request)
+	}
+
+	if len(request.Tools) == 0 {
+		request.Tools = registry.Definitions()
+	}
+	if len(request.Tools) == 0 {
+		return generator.GenerateText(ctx, request)
+	}
+
+	for round := 0; round < maxToolRounds; round++ {
+		response, err := generator.GenerateText(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		calls := extractToolCalls(response)
+		if len(calls) == 0 {
+			return response, nil
+		}
+
+		// 把assistant的工具调用与各工具结果追加到消息序列
+		if len(response.Choices) > 0 {
+			request.Messages = append(request.Messages, response.Choices[0].Message)
+		}
+		for _, call := range calls {
+			result, err := registry.Execute(ctx, &call)
+			if err != nil {
+				// 执行失败时把错误信息回传给模型，让其决定如何继续
+				result = fmt.Sprintf(`{"error": %q}`, err.Error())
+			}
+			request.Messages = append(request.Messages, models.Message{
+				Role:       "tool",
+				Content:    result,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("tool call loop exceeded %d rounds", maxToolRounds)
+}
+
+// extractToolCalls 从响应中提取工具调用
+func extractToolCalls(response *models.TextGenerationResponse) []models.ToolCall {
+	if response == nil || len(response.Choices) == 0 {
+		return nil
+	}
+	return response.Choices[0].Message.ToolCalls
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"gorm.io/datatypes"
+
+	"github.com/codetaoist/taishanglaojun/hybrid-api/internal/models"
+)
+
+// ToolFunc 工具的执行函数，参数为JSON解码后的调用入参
+type ToolFunc func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// Tool 已注册的工具
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters JSON Schema格式的参数定义，原样下发给模型
+	Parameters datatypes.JSON
+	Execute    ToolFunc
+}
+
+// Registry 工具注册表
+//
+// AI对话请求可携带tools字段声明可用工具；模型返回工具调用时，
+// 由注册表分发执行并把结果作为tool消息回填到对话中。
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]*Tool
+}
+
+// NewRegistry 创建工具注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		tools: make(map[string]*Tool),
+	}
+}
+
+// Register 注册工具
+func (r *Registry) Register(tool *Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if tool.Execute == nil {
+		return fmt.Errorf("tool %s has no execute function", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[tool.Name]; exists {
+		return fmt.Errorf("tool already registered: %s", tool.Name)
+	}
+	r.tools[tool.Name] = tool
+	return nil
+}
+
+// Unregister 注销工具
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[name]; !exists {
+		return fmt.Errorf("tool not found: %s", name)
+	}
+	delete(r.tools, name)
+	return nil
+}
+
+// Definitions 返回全部工具定义（用于下发给模型）
+func (r *Registry) Definitions() []models.ToolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]models.ToolDefinition, 0, len(r.tools))
+	for _, tool := range r.tools {
+		definitions = append(definitions, models.ToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  tool.Parameters,
+		})
+	}
+	return definitions
+}
+
+// Execute 执行一次模型发起的工具调用
+func (r *Registry) Execute(ctx context.Context, call *models.ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, exists := r.tools[call.Name]
+	r.mu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("tool not found: %s", call.Name)
+	}
+
+	args := map[string]interface{}{}
+	if call.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid tool arguments: %w", err)
+		}
+	}
+
+	result, err := tool.Execute(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("tool %s execution failed: %w", call.Name, err)
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return string(encoded), nil
+}
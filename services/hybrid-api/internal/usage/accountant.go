@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrQuotaExceeded 用户token配额已用尽
+var ErrQuotaExceeded = errors.New("token quota exceeded")
+
+// UsageRecord 单次AI调用的token消耗记录
+type UsageRecord struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           string    `json:"userId" gorm:"index;not null"`
+	TenantID         string    `json:"tenantId" gorm:"index"`
+	Model            string    `json:"model"`
+	Provider         string    `json:"provider"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+	TotalTokens      int       `json:"totalTokens"`
+	CreatedAt        time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName 指定表名
+func (UsageRecord) TableName() string {
+	return "tai_usage_records"
+}
+
+// UserQuota 用户token配额，0表示不限制
+type UserQuota struct {
+	UserID            string    `json:"userId" gorm:"primaryKey;type:varchar(36)"`
+	DailyTokenLimit   int64     `json:"dailyTokenLimit"`
+	MonthlyTokenLimit int64     `json:"monthlyTokenLimit"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (UserQuota) TableName() string {
+	return "tai_user_quotas"
+}
+
+// UsageSummary token消耗汇总
+type UsageSummary struct {
+	UserID            string `json:"userId"`
+	DailyTokens       int64  `json:"dailyTokens"`
+	MonthlyTokens     int64  `json:"monthlyTokens"`
+	DailyTokenLimit   int64  `json:"dailyTokenLimit"`
+	MonthlyTokenLimit int64  `json:"monthlyTokenLimit"`
+}
+
+// Accountant token用量记账与配额执行
+//
+// 每次AI调用后记录消耗，调用前检查用户的日/月配额，
+// 超限的请求在进入模型服务之前被拒绝。
+type Accountant struct {
+	db *gorm.DB
+}
+
+// NewAccountant 创建用量记账器
+func NewAccountant(db *gorm.DB) (*Accountant, error) {
+	if err := db.AutoMigrate(&UsageRecord{}, &UserQuota{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate usage tables: %w", err)
+	}
+	return &Accountant{db: db}, nil
+}
+
+// Record 记录一次调用的token消耗
+func (a *Accountant) Record(ctx context.Context, record *UsageRecord) error {
+	if record.TotalTokens == 0 {
+		record.TotalTokens = record.PromptTokens + record.CompletionTokens
+	}
+	if err := a.db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return nil
+}
+
+// CheckQuota 检查用户配额，超限时返回ErrQuotaExceeded
+func (a *Accountant) CheckQuota(ctx context.Context, userID string) error {
+	summary, err := a.Summary(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if summary.DailyTokenLimit > 0 && summary.DailyTokens >= summary.DailyTokenLimit {
+		return fmt.Errorf("%w: daily limit %d reached", ErrQuotaExceeded, summary.DailyTokenLimit)
+	}
+	if summary.MonthlyTokenLimit > 0 && summary.MonthlyTokens >= summary.MonthlyTokenLimit {
+		return fmt.Errorf("%w: monthly limit %d reached", ErrQuotaExceeded, summary.MonthlyTokenLimit)
+	}
+	return nil
+}
+
+// Summary 获取用户当日与当月的token消耗及配额
+func (a *Accountant) Summary(ctx context.Context, userID string) (*UsageSummary, error) {
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	daily, err := a.sumTokens(ctx, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := a.sumTokens(ctx, userID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &UsageSummary{
+		UserID:        userID,
+		DailyTokens:   daily,
+		MonthlyTokens: monthly,
+	}
+
+	var quota UserQuota
+	err = a.db.WithContext(ctx).First(&quota, "user_id = ?", userID).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get user quota: %w", err)
+	}
+	summary.DailyTokenLimit = quota.DailyTokenLimit
+	summary.MonthlyTokenLimit = quota.MonthlyTokenLimit
+
+	return summary, nil
+}
+
+// sumTokens 统计指定时间之后的token总量
+func (a *Accountant) sumTokens(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var total int64
+	err := a.db.WithContext(ctx).Model(&UsageRecord{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum token usage: %w", err)
+	}
+	return total, nil
+}
+
+// SetQuota 设置用户配额
+func (a *Accountant) SetQuota(ctx context.Context, quota *UserQuota) error {
+	if err := a.db.WithContext(ctx).Save(quota).Error; err != nil {
+		return fmt.Errorf("failed to set user quota: %w", err)
+	}
+	return nil
+}
+
+// ListRecords 查询用户的消耗明细
+func (a *Accountant) ListRecords(ctx context.Context, userID string, since time.Time, limit, offset int) ([]*UsageRecord, error) {
+	query := a.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var records []*UsageRecord
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list usage records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaMiddleware AI路由的配额执行中间件
+//
+// 从X-User-ID头识别用户，配额用尽时返回429，阻止请求进入模型服务。
+func QuotaMiddleware(accountant *Accountant) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetHeader("X-User-ID")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		if err := accountant.CheckQuota(c.Request.Context(), userID); err != nil {
+			if errors.Is(err, ErrQuotaExceeded) {
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Handler 用量记账HTTP处理器
+type Handler struct {
+	accountant *Accountant
+}
+
+// NewHandler 创建用量记账处理器
+func NewHandler(accountant *Accountant) *Handler {
+	return &Handler{accountant: accountant}
+}
+
+// SetupRoutes 注册用量相关路由
+func (h *Handler) SetupRoutes(router gin.IRouter) {
+	usage := router.Group("/usage")
+	{
+		usage.GET("/users/:userId", h.Summary)
+		usage.GET("/users/:userId/records", h.Records)
+		usage.GET("/users/:userId/quota", h.GetQuota)
+		usage.PUT("/users/:userId/quota", h.SetQuota)
+	}
+}
+
+// Summary 获取用户消耗汇总
+func (h *Handler) Summary(c *gin.Context) {
+	summary, err := h.accountant.Summary(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// Records 查询消耗明细
+func (h *Handler) Records(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	since := time.Now().AddDate(0, 0, -days)
+	records, err := h.accountant.ListRecords(c.Request.Context(), c.Param("userId"), since, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"records": records, "count": len(records)})
+}
+
+// GetQuota 获取用户配额
+func (h *Handler) GetQuota(c *gin.Context) {
+	summary, err := h.accountant.Summary(c.Request.Context(), c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"userId":            summary.UserID,
+		"dailyTokenLimit":   summary.DailyTokenLimit,
+		"monthlyTokenLimit": summary.MonthlyTokenLimit,
+	})
+}
+
+// SetQuota 设置用户配额
+func (h *Handler) SetQuota(c *gin.Context) {
+	var req struct {
+		DailyTokenLimit   int64 `json:"dailyTokenLimit"`
+		MonthlyTokenLimit int64 `json:"monthlyTokenLimit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quota := &UserQuota{
+		UserID:            c.Param("userId"),
+		DailyTokenLimit:   req.DailyTokenLimit,
+		MonthlyTokenLimit: req.MonthlyTokenLimit,
+	}
+	if err := h.accountant.SetQuota(c.Request.Context(), quota); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
@@ -0,0 +1,86 @@
+import json
+import os
+
+import pytest
+import requests
+
+# 核心服务（非网关）地址
+CORE_BASE_URL = os.getenv("CORE_BASE_URL", "http://localhost:8082")
+
+try:
+    requests.get(CORE_BASE_URL + "/health", timeout=2)
+    SERVER_UP = True
+except requests.exceptions.RequestException:
+    SERVER_UP = False
+
+ERROR_CODES = {
+    "OK",
+    "INVALID_ARGUMENT",
+    "UNAUTHENTICATED",
+    "PERMISSION_DENIED",
+    "NOT_FOUND",
+    "CONFLICT",
+    "FAILED_PRECONDITION",
+    "INTERNAL",
+    "UNAVAILABLE",
+}
+
+skip_reason = "核心服务未运行，跳过版本契约测试（设置 CORE_BASE_URL 或启动服务）"
+
+
+def request_json(method: str, path: str, json_body=None, timeout=5):
+    url = CORE_BASE_URL.rstrip("/") + path
+    resp = requests.request(method, url, json=json_body, timeout=timeout)
+    try:
+        data = resp.json()
+    except json.JSONDecodeError:
+        pytest.fail(f"Non-JSON response for {method} {path}: status={resp.status_code}")
+    return resp.status_code, data
+
+
+@pytest.mark.skipif(not SERVER_UP, reason=skip_reason)
+class TestV1Frozen:
+    """v1契约冻结：错误始终是 {"error": "..."}，不得出现v2包装字段"""
+
+    def test_board_not_found_legacy_shape(self):
+        status, body = request_json("GET", "/api/v1/tasks/boards/b-unknown")
+        assert status == 404
+        assert "error" in body
+        assert "code" not in body, "v1 error shape must stay frozen (no wrapper code)"
+
+    def test_thread_not_found_legacy_shape(self):
+        status, body = request_json("GET", "/api/v1/community/threads/t-unknown")
+        assert status == 404
+        assert "error" in body
+        assert "code" not in body, "v1 error shape must stay frozen (no wrapper code)"
+
+
+@pytest.mark.skipif(not SERVER_UP, reason=skip_reason)
+class TestV2Wrapper:
+    """v2契约：所有响应使用 code/data/message 标准包装"""
+
+    def assert_wrapper(self, payload):
+        assert isinstance(payload, dict)
+        assert payload.get("code") in ERROR_CODES
+        if payload["code"] == "OK":
+            assert "data" in payload
+        else:
+            assert "message" in payload
+
+    def test_board_not_found_wrapper(self):
+        status, body = request_json("GET", "/api/v2/boards/b-unknown")
+        assert status == 404
+        self.assert_wrapper(body)
+        assert body["code"] == "NOT_FOUND"
+
+    def test_thread_not_found_wrapper(self):
+        status, body = request_json("GET", "/api/v2/threads/t-unknown")
+        assert status == 404
+        self.assert_wrapper(body)
+        assert body["code"] == "NOT_FOUND"
+
+    def test_create_task_invalid_wrapper(self):
+        status, body = request_json("POST", "/api/v2/boards/b-unknown/tasks", json_body={})
+        assert status == 400
+        self.assert_wrapper(body)
+        assert body["code"] == "INVALID_ARGUMENT"